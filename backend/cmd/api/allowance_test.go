@@ -1,6 +1,11 @@
 package main
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/service"
+)
 
 // strconv.ParseFloat accepts more than this config can use: "NaN", "Inf",
 // "+Inf" and "-Inf" all parse without error. A NaN allowance would be written
@@ -38,3 +43,29 @@ func TestParseMonthlyAllowance(t *testing.T) {
 		})
 	}
 }
+
+// The rate is read as an exact percentage: "1.25" is 125 basis points, with
+// no float64 rounding. A value that cannot be used is dropped on its own, so
+// a typo in the cap does not also turn interest off.
+func TestParseInterestPolicy(t *testing.T) {
+	tests := []struct {
+		name                 string
+		rate, minBalance, cp string
+		want                 service.InterestPolicy
+	}{
+		{"absent is off", "", "", "", service.InterestPolicy{}},
+		{"whole percent", "1", "", "", service.InterestPolicy{RateBasisPoints: 100}},
+		{"fractional percent", "1.25", "20", "5", service.InterestPolicy{RateBasisPoints: 125, MinBalance: model.Dollars(20), Cap: model.Dollars(5)}},
+		{"garbage rate is off", "abc", "20", "", service.InterestPolicy{MinBalance: model.Dollars(20)}},
+		{"negative rate is off", "-1", "", "", service.InterestPolicy{}},
+		{"over 100 percent is off", "150", "", "", service.InterestPolicy{}},
+		{"bad cap is dropped alone", "2", "", "-5", service.InterestPolicy{RateBasisPoints: 200}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := parseInterestPolicy(tc.rate, tc.minBalance, tc.cp); got != tc.want {
+				t.Errorf("parseInterestPolicy(%q, %q, %q) = %+v, want %+v", tc.rate, tc.minBalance, tc.cp, got, tc.want)
+			}
+		})
+	}
+}
@@ -12,19 +12,24 @@ import (
 	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/vppillai/passbook/backend/internal/blobstore"
 	"github.com/vppillai/passbook/backend/internal/handler"
+	"github.com/vppillai/passbook/backend/internal/model"
 	"github.com/vppillai/passbook/backend/internal/repository"
 	"github.com/vppillai/passbook/backend/internal/service"
 )
 
-// maxBodyBytes caps the DECODED request body. Every endpoint here takes a
-// small JSON object, so this is generous.
+// maxBodyBytes caps the DECODED request body. Every endpoint but the
+// attachment upload takes a small JSON object, so this is generous; see
+// bodyLimit for the upload.
 const maxBodyBytes = 32 * 1024
 
 // errBadBase64Body marks a body that API Gateway flagged as base64-encoded but
@@ -32,13 +37,28 @@ const maxBodyBytes = 32 * 1024
 // to 400 rather than 500.
 var errBadBase64Body = errors.New("request body is not valid base64")
 
-// decodedTooLarge reports whether a decoded body exceeds the cap.
-func decodedTooLarge(n int64) bool { return n > maxBodyBytes }
+// decodedTooLarge reports whether a decoded body exceeds limit.
+func decodedTooLarge(n, limit int64) bool { return n > limit }
+
+// bodyLimit is the decoded body cap for a request. An attachment upload
+// (POST /api/expense/{month}/{id}/attachments) carries a file, so that one
+// route alone gets service.MaxAttachmentBytes. It is matched on the raw
+// path: the suffix has nothing to percent-encode, and a client that encodes
+// it anyway only gets the stricter cap.
+func bodyLimit(method, rawPath string) int64 {
+	if method == http.MethodPost && strings.HasPrefix(rawPath, "/api/expense/") && strings.HasSuffix(rawPath, "/attachments") {
+		return service.MaxAttachmentBytes
+	}
+	return maxBodyBytes
+}
 
 var (
-	router    *handler.Router
-	setupOnce sync.Once
-	setupErr  error
+	router *handler.Router
+	// expenseService is the same instance the router serves, kept for the
+	// scheduled jobs (scheduler.go), which run without a request.
+	expenseService *service.ExpenseService
+	setupOnce      sync.Once
+	setupErr       error
 )
 
 // defaultMonthlyAllowance is used when MONTHLY_ALLOWANCE is absent or unusable.
@@ -80,6 +100,43 @@ func parseMonthlyAllowance(val string, fallback float64) float64 {
 	return parsed
 }
 
+// parseInterestPolicy reads INTEREST_RATE (a monthly percentage, to two
+// decimals), INTEREST_MIN_BALANCE and INTEREST_CAP (dollars). Like the
+// allowance, a value it cannot use is logged and ignored rather than failing
+// the cold start: a bad rate turns interest off, a bad minimum or cap leaves
+// that limit unset. A rate above 100% is refused as certainly a typo.
+func parseInterestPolicy(rate, minBalance, maxInterest string) service.InterestPolicy {
+	var p service.InterestPolicy
+	if rate != "" {
+		// Percent to two decimals is basis points the way dollars to two
+		// decimals are cents, so ParseMoney reads it exactly.
+		bp, err := model.ParseMoney(rate)
+		if err != nil || bp < 0 || bp > 10000 {
+			log.Printf("warn: INTEREST_RATE=%q is not a percentage from 0 to 100, paying no interest", rate)
+		} else {
+			p.RateBasisPoints = int64(bp)
+		}
+	}
+	for _, v := range []struct {
+		name, val string
+		dst       *model.Money
+	}{
+		{"INTEREST_MIN_BALANCE", minBalance, &p.MinBalance},
+		{"INTEREST_CAP", maxInterest, &p.Cap},
+	} {
+		if v.val == "" {
+			continue
+		}
+		amount, err := model.ParseMoney(v.val)
+		if err != nil || amount < 0 {
+			log.Printf("warn: %s=%q is not a non-negative amount, ignoring it", v.name, v.val)
+			continue
+		}
+		*v.dst = amount
+	}
+	return p
+}
+
 // setupRouter constructs the router on first call. Previously this lived
 // in init(), which called log.Fatal on missing env vars or AWS config
 // failure — that's a process-killing crash on cold start AND makes the
@@ -117,7 +174,7 @@ func setupRouter() error {
 	dynamoClient := dynamodb.NewFromConfig(cfg)
 	repo := repository.NewRepository(dynamoClient, tableName)
 	authService := service.NewAuthService(repo)
-	expenseService := service.NewExpenseService(repo, monthlyAllowance, allowOverspending, carryOverBalance)
+	expenseService = service.NewExpenseService(repo, monthlyAllowance, allowOverspending, carryOverBalance)
 
 	// WebAuthn (biometric unlock): RP ID is derived from ALLOWED_ORIGIN's
 	// host, RP origin is ALLOWED_ORIGIN, display name from
@@ -131,6 +188,36 @@ func setupRouter() error {
 		webauthnService = nil
 	}
 
+	// Receipt attachments. ATTACHMENT_BUCKET selects S3, as deployed;
+	// ATTACHMENT_DIR a local directory, for running outside AWS. With
+	// neither, the attachment routes answer 503 and everything else works.
+	if bucket := os.Getenv("ATTACHMENT_BUCKET"); bucket != "" {
+		expenseService.SetBlobStore(blobstore.NewS3Store(s3.NewFromConfig(cfg), bucket))
+	} else if dir := os.Getenv("ATTACHMENT_DIR"); dir != "" {
+		store, err := blobstore.NewLocalStore(dir)
+		if err != nil {
+			log.Printf("warn: attachments disabled: %v", err)
+		} else {
+			expenseService.SetBlobStore(store)
+		}
+	}
+
+	// Deleted expenses stay restorable for TRASH_RETENTION_DAYS (the
+	// service default when unset). A bad value falls back like a bad
+	// allowance does, with a warning.
+	if val := os.Getenv("TRASH_RETENTION_DAYS"); val != "" {
+		days, err := strconv.Atoi(val)
+		if err != nil || days < 1 {
+			log.Printf("warn: TRASH_RETENTION_DAYS=%q is not a positive whole number, keeping the default", val)
+		} else {
+			expenseService.SetTrashRetention(days)
+		}
+	}
+
+	// Monthly interest on carried balances, off unless INTEREST_RATE is set.
+	expenseService.SetInterestPolicy(parseInterestPolicy(
+		os.Getenv("INTEREST_RATE"), os.Getenv("INTEREST_MIN_BALANCE"), os.Getenv("INTEREST_CAP")))
+
 	router = handler.NewRouter(authService, expenseService, webauthnService, allowedOrigin)
 	return nil
 }
@@ -143,10 +230,12 @@ func handleRequest(ctx context.Context, event events.APIGatewayV2HTTPRequest) (e
 	// exercised at all without a full environment, which is why its status
 	// mapping had no coverage.
 
+	limit := bodyLimit(event.RequestContext.HTTP.Method, event.RawPath)
+
 	// Cheap pre-filter on the wire size, before spending anything on decoding.
 	// base64 inflates by about a third, so this bound is deliberately loose;
 	// the real limit is enforced on the DECODED body below.
-	if len(event.Body) > 2*maxBodyBytes {
+	if int64(len(event.Body)) > 2*limit {
 		return events.APIGatewayV2HTTPResponse{
 			StatusCode: http.StatusRequestEntityTooLarge,
 			Body:       `{"error":"Request body too large"}`,
@@ -175,7 +264,7 @@ func handleRequest(ctx context.Context, event events.APIGatewayV2HTTPRequest) (e
 	}
 
 	// Enforce the real limit on the decoded body.
-	if decodedTooLarge(req.ContentLength) {
+	if decodedTooLarge(req.ContentLength, limit) {
 		return events.APIGatewayV2HTTPResponse{
 			StatusCode: http.StatusRequestEntityTooLarge,
 			Body:       `{"error":"Request body too large"}`,
@@ -207,12 +296,7 @@ func handleRequest(ctx context.Context, event events.APIGatewayV2HTTPRequest) (e
 	// Handle request
 	router.ServeHTTP(rw, req)
 
-	// Convert response
-	return events.APIGatewayV2HTTPResponse{
-		StatusCode: rw.statusCode,
-		Body:       rw.body,
-		Headers:    flattenHeaders(rw.headers),
-	}, nil
+	return rw.response(), nil
 }
 
 func convertToHTTPRequest(ctx context.Context, event events.APIGatewayV2HTTPRequest) (*http.Request, error) {
@@ -318,6 +402,22 @@ func (rw *responseWriter) WriteHeader(statusCode int) {
 	rw.statusCode = statusCode
 }
 
+// response converts what the handler wrote into the API Gateway response.
+// JSON goes out as text; any other content type is an attachment download,
+// whose bytes API Gateway only passes through base64-encoded.
+func (rw *responseWriter) response() events.APIGatewayV2HTTPResponse {
+	resp := events.APIGatewayV2HTTPResponse{
+		StatusCode: rw.statusCode,
+		Body:       rw.body,
+		Headers:    flattenHeaders(rw.headers),
+	}
+	if ct := rw.headers.Get("Content-Type"); ct != "" && !strings.HasPrefix(ct, "application/json") {
+		resp.Body = base64.StdEncoding.EncodeToString([]byte(rw.body))
+		resp.IsBase64Encoded = true
+	}
+	return resp
+}
+
 func flattenHeaders(h http.Header) map[string]string {
 	flat := make(map[string]string)
 	for k, v := range h {
@@ -329,5 +429,5 @@ func flattenHeaders(h http.Header) map[string]string {
 }
 
 func main() {
-	lambda.Start(handleRequest)
+	lambda.Start(dispatch)
 }
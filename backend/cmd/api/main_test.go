@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
+	"github.com/vppillai/passbook/backend/internal/service"
 )
 
 // TestBodyReader_EOF asserts the io.Reader contract: once all data has
@@ -203,6 +204,27 @@ func TestResponseWriter(t *testing.T) {
 	})
 }
 
+// JSON responses go out as text; an attachment download is binary and has to
+// reach API Gateway base64-encoded.
+func TestResponseWriter_BinaryBodyIsBase64(t *testing.T) {
+	rw := &responseWriter{headers: make(http.Header)}
+	rw.headers.Set("Content-Type", "application/json")
+	rw.Write([]byte(`{"ok":true}`))
+	if resp := rw.response(); resp.IsBase64Encoded || resp.Body != `{"ok":true}` {
+		t.Errorf("json response = %+v, want plain text", resp)
+	}
+
+	png := "\x89PNG\r\n\x1a\n\x00\xff"
+	rw = &responseWriter{headers: make(http.Header)}
+	rw.headers.Set("Content-Type", "image/png")
+	rw.Write([]byte(png))
+	resp := rw.response()
+	decoded, err := base64.StdEncoding.DecodeString(resp.Body)
+	if !resp.IsBase64Encoded || err != nil || string(decoded) != png {
+		t.Errorf("binary response = %+v (decode err %v), want the bytes base64-encoded", resp, err)
+	}
+}
+
 // TestFlattenHeaders pins first-value-wins flattening and empty-slice
 // skipping for the APIGW response header map.
 func TestFlattenHeaders(t *testing.T) {
@@ -408,3 +430,42 @@ func TestHandleRequest_RejectsOversizedWireBody(t *testing.T) {
 		t.Errorf("status = %d, want 413", resp.StatusCode)
 	}
 }
+
+// Only the attachment upload route gets the larger cap: the same body posted
+// anywhere else is still 413.
+func TestHandleRequest_AttachmentUploadCap(t *testing.T) {
+	event := func(method, path string, size int) events.APIGatewayV2HTTPRequest {
+		return events.APIGatewayV2HTTPRequest{
+			RawPath: path,
+			Body:    strings.Repeat("p", size),
+			RequestContext: events.APIGatewayV2HTTPRequestContext{
+				HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+					Method: method, SourceIP: "203.0.113.1",
+				},
+			},
+		}
+	}
+	upload := "/api/expense/2026-02/EXP%231%23abc/attachments"
+
+	cases := []struct {
+		name     string
+		event    events.APIGatewayV2HTTPRequest
+		tooLarge bool
+	}{
+		{"photo-sized upload", event(http.MethodPost, upload, 1024*1024), false},
+		{"upload over the attachment cap", event(http.MethodPost, upload, service.MaxAttachmentBytes+1), true},
+		{"same body on a JSON route", event(http.MethodPost, "/api/expense", 1024*1024), true},
+		{"same body on a non-upload method", event(http.MethodDelete, upload, 1024*1024), true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := handleRequest(context.Background(), tc.event)
+			if err != nil {
+				t.Fatalf("handleRequest: %v", err)
+			}
+			if got := resp.StatusCode == http.StatusRequestEntityTooLarge; got != tc.tooLarge {
+				t.Errorf("status = %d, want 413 = %v", resp.StatusCode, tc.tooLarge)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+	"github.com/vppillai/passbook/backend/internal/service"
+)
+
+// scheduledEventSource is the "source" EventBridge stamps on a scheduled
+// rule's event. Nothing else that reaches this function carries it: API
+// Gateway's v2 payload has no top-level "source" field at all.
+const scheduledEventSource = "aws.events"
+
+// scheduledRunResult is what a scheduled invocation returns. Lambda writes
+// it nowhere useful for an async EventBridge invoke, so it is also logged.
+type scheduledRunResult struct {
+	MonthsActivated []string                    `json:"months_activated"`
+	Recurring       *model.RecurringRunResponse `json:"recurring"`
+	TrashPurged     int                         `json:"trash_purged"`
+}
+
+// runScheduledJobs is the unattended half of the app: it activates the
+// current month (and any months nobody opened the app for), books the
+// recurring expenses that have fallen due, then purges expired trash
+// entries whose attachments need deleting. Every step is idempotent, so a
+// retried or duplicated invocation is harmless. Months come first so a
+// recurring expense lands in a month that already has its allowance.
+func runScheduledJobs(ctx context.Context, svc *service.ExpenseService, now time.Time) (*scheduledRunResult, error) {
+	months, err := svc.RolloverMonths(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	recurring, err := svc.RunRecurring(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	purged, err := svc.PurgeTrash(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	return &scheduledRunResult{MonthsActivated: months, Recurring: recurring, TrashPurged: purged}, nil
+}
+
+// runScheduledJobsForAccounts runs the jobs for every account, keyed by
+// account id. It first sweeps the table for float-dollar money rows, the
+// one job that is table-wide rather than per account; the sweep is a
+// single point read once it has completed. One job failing does not hold
+// up the others; the first error is returned once they have all run, so
+// the retry it causes repeats only what is still undone.
+func runScheduledJobsForAccounts(ctx context.Context, svc *service.ExpenseService, now time.Time) (map[string]*scheduledRunResult, error) {
+	accounts, err := svc.ListAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	results := make(map[string]*scheduledRunResult, len(accounts.Accounts))
+	var firstErr error
+	if err := svc.MigrateMoney(ctx); err != nil {
+		log.Printf("scheduled.run: money migration: %v", err)
+		firstErr = err
+	}
+	for _, account := range accounts.Accounts {
+		result, err := runScheduledJobs(repository.WithAccount(ctx, account.ID), svc, now)
+		if err != nil {
+			log.Printf("scheduled.run: account %s: %v", account.ID, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		log.Printf("scheduled.run: account %s: activated %v, booked %d, skipped %d, purged %d from trash",
+			account.ID, result.MonthsActivated, len(result.Recurring.Booked), len(result.Recurring.Skipped), result.TrashPurged)
+		results[account.ID] = result
+	}
+	return results, firstErr
+}
+
+// handleScheduledEvent runs the scheduled jobs for an EventBridge event. The
+// event's own time is used rather than the clock, so a delayed or retried
+// delivery still rolls over the month the schedule fired in.
+func handleScheduledEvent(ctx context.Context, event events.CloudWatchEvent) (map[string]*scheduledRunResult, error) {
+	setupOnce.Do(func() { setupErr = setupRouter() })
+	if setupErr != nil {
+		log.Printf("error: router initialization failed: %v", setupErr)
+		return nil, setupErr
+	}
+
+	now := event.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+	results, err := runScheduledJobsForAccounts(ctx, expenseService, now)
+	if err != nil {
+		// Returning the error makes Lambda retry the async invoke, which is
+		// safe: every step re-checks what is already done.
+		return nil, err
+	}
+	return results, nil
+}
+
+// dispatch is the Lambda entrypoint. One function serves both the HTTP API
+// and the schedule, so the payload is inspected for EventBridge's source
+// field and routed accordingly; everything else is an API Gateway request.
+func dispatch(ctx context.Context, payload json.RawMessage) (interface{}, error) {
+	var probe struct {
+		Source string `json:"source"`
+	}
+	if err := json.Unmarshal(payload, &probe); err != nil {
+		return nil, fmt.Errorf("unrecognized event: %w", err)
+	}
+	if probe.Source == scheduledEventSource {
+		var event events.CloudWatchEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, fmt.Errorf("invalid scheduled event: %w", err)
+		}
+		return handleScheduledEvent(ctx, event)
+	}
+
+	var request events.APIGatewayV2HTTPRequest
+	if err := json.Unmarshal(payload, &request); err != nil {
+		return nil, fmt.Errorf("invalid API Gateway event: %w", err)
+	}
+	return handleRequest(ctx, request)
+}
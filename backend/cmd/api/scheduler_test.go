@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/service"
+	"github.com/vppillai/passbook/backend/internal/testutil"
+)
+
+var scheduledNow = time.Date(2025, time.April, 3, 0, 5, 0, 0, time.UTC)
+
+// A fresh instance gets its current month on the first run, and a second
+// run the same day finds nothing to do.
+func TestRunScheduledJobs_ActivatesCurrentMonthOnce(t *testing.T) {
+	repo := testutil.NewFakeRepo()
+	svc := service.NewExpenseService(repo, 100, false, true)
+
+	result, err := runScheduledJobs(context.Background(), svc, scheduledNow)
+	if err != nil {
+		t.Fatalf("runScheduledJobs: %v", err)
+	}
+	if len(result.MonthsActivated) != 1 || result.MonthsActivated[0] != "2025-04" {
+		t.Fatalf("activated = %v, want [2025-04]", result.MonthsActivated)
+	}
+	if got := repo.Months["2025-04"].AllowanceAdded; got != model.Dollars(100) {
+		t.Errorf("allowance = %v, want 100", got)
+	}
+
+	result, err = runScheduledJobs(context.Background(), svc, scheduledNow)
+	if err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if len(result.MonthsActivated) != 0 {
+		t.Errorf("second run activated %v, want nothing", result.MonthsActivated)
+	}
+	if repo.Balance.TotalBalance != model.Dollars(100) {
+		t.Errorf("balance = %v, the allowance must be granted once", repo.Balance.TotalBalance)
+	}
+}
+
+// Months nobody opened the app for are filled in order, each carrying from
+// the one before.
+func TestRunScheduledJobs_FillsGapMonths(t *testing.T) {
+	repo := testutil.NewFakeRepo()
+	testutil.SeedMonth(repo, "2025-01", 0, 100, 40, 60)
+	repo.Balance.TotalBalance = model.Dollars(60)
+	svc := service.NewExpenseService(repo, 100, false, true)
+
+	result, err := runScheduledJobs(context.Background(), svc, scheduledNow)
+	if err != nil {
+		t.Fatalf("runScheduledJobs: %v", err)
+	}
+	want := []string{"2025-02", "2025-03", "2025-04"}
+	if strings.Join(result.MonthsActivated, ",") != strings.Join(want, ",") {
+		t.Fatalf("activated = %v, want %v", result.MonthsActivated, want)
+	}
+	if got := repo.Months["2025-04"]; got.StartingBalance != model.Dollars(260) || got.EndingBalance != model.Dollars(360) {
+		t.Errorf("2025-04 = start %v end %v, want 260 / 360", got.StartingBalance, got.EndingBalance)
+	}
+	if repo.Balance.TotalBalance != model.Dollars(360) {
+		t.Errorf("balance = %v, want 360", repo.Balance.TotalBalance)
+	}
+}
+
+// A month auto-created at $0 by an early expense still gets its allowance.
+func TestRunScheduledJobs_TopsUpAutoCreatedMonth(t *testing.T) {
+	repo := testutil.NewFakeRepo()
+	testutil.SeedMonth(repo, "2025-04", 0, 0, 0, 0)
+	svc := service.NewExpenseService(repo, 100, true, true)
+
+	if _, err := runScheduledJobs(context.Background(), svc, scheduledNow); err != nil {
+		t.Fatalf("runScheduledJobs: %v", err)
+	}
+	if got := repo.Months["2025-04"].AllowanceAdded; got != model.Dollars(100) {
+		t.Errorf("allowance = %v, want 100", got)
+	}
+}
+
+// Recurring expenses run after the rollover, so on a hard-stop instance the
+// new month's allowance is already there to pay for them.
+func TestRunScheduledJobs_BooksRecurringIntoTheNewMonth(t *testing.T) {
+	repo := testutil.NewFakeRepo()
+	svc := service.NewExpenseService(repo, 100, false, true)
+	if _, err := svc.CreateRecurring(context.Background(), &model.CreateRecurringRequest{
+		Amount: model.Dollars(30), Description: "Phone", DayOfMonth: 1, StartMonth: "2025-04",
+	}); err != nil {
+		t.Fatalf("CreateRecurring: %v", err)
+	}
+
+	result, err := runScheduledJobs(context.Background(), svc, scheduledNow)
+	if err != nil {
+		t.Fatalf("runScheduledJobs: %v", err)
+	}
+	if len(result.Recurring.Booked) != 1 || len(result.Recurring.Skipped) != 0 {
+		t.Fatalf("recurring = %+v, want one booked", result.Recurring)
+	}
+	if repo.Months["2025-04"].EndingBalance != model.Dollars(70) {
+		t.Errorf("ending balance = %v, want 70", repo.Months["2025-04"].EndingBalance)
+	}
+}
+
+// Expired trash entries with attachments are purged by the daily run; the
+// rest are left to the table's TTL.
+func TestRunScheduledJobs_PurgesExpiredTrash(t *testing.T) {
+	repo := testutil.NewFakeRepo()
+	svc := service.NewExpenseService(repo, 100, false, true)
+	expired := scheduledNow.Add(-time.Hour)
+	repo.Trash["EXP#1#a"] = &model.TrashedExpense{
+		ID: "EXP#1#a", ExpiresAt: expired, Attachments: []model.Attachment{{ID: "att1"}},
+	}
+	repo.Trash["EXP#2#b"] = &model.TrashedExpense{ID: "EXP#2#b", ExpiresAt: expired}
+	repo.Trash["EXP#3#c"] = &model.TrashedExpense{
+		ID: "EXP#3#c", ExpiresAt: scheduledNow.Add(time.Hour), Attachments: []model.Attachment{{ID: "att3"}},
+	}
+
+	result, err := runScheduledJobs(context.Background(), svc, scheduledNow)
+	if err != nil {
+		t.Fatalf("runScheduledJobs: %v", err)
+	}
+	if result.TrashPurged != 1 || repo.Trash["EXP#1#a"] != nil || len(repo.Trash) != 2 {
+		t.Errorf("purged %d, trash left %d entries, want only the expired one with attachments gone", result.TrashPurged, len(repo.Trash))
+	}
+}
+
+// Every account gets its own months: the daily run goes through the
+// default account and each registered one. The money sweep is table-wide,
+// so it runs once per run, not once per account.
+func TestRunScheduledJobsForAccounts_RunsEveryAccount(t *testing.T) {
+	repo := testutil.NewFakeRepo()
+	svc := service.NewExpenseService(repo, 100, false, true)
+	account, err := svc.CreateAccount(context.Background(), "Sam")
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+
+	results, err := runScheduledJobsForAccounts(context.Background(), svc, scheduledNow)
+	if err != nil {
+		t.Fatalf("runScheduledJobsForAccounts: %v", err)
+	}
+	if len(results) != 2 || len(results[model.DefaultAccountID].MonthsActivated) != 1 || len(results[account.ID].MonthsActivated) != 1 {
+		t.Fatalf("results = %v, want the month activated in both accounts", results)
+	}
+	if got := repo.Ledger(account.ID).Balance.TotalBalance; got != model.Dollars(100) || repo.Balance.TotalBalance != model.Dollars(100) {
+		t.Errorf("balances = %v and %v, want 100 each", repo.Balance.TotalBalance, got)
+	}
+	if repo.MoneyMigrations != 1 {
+		t.Errorf("MoneyMigrations = %d, want 1", repo.MoneyMigrations)
+	}
+}
+
+// API Gateway payloads still reach handleRequest through the dispatcher. An
+// oversized body is refused before any AWS setup, so this needs no
+// environment.
+func TestDispatch_RoutesAPIGatewayRequests(t *testing.T) {
+	payload, _ := json.Marshal(events.APIGatewayV2HTTPRequest{
+		RawPath: "/api/expense",
+		Body:    strings.Repeat("x", 2*maxBodyBytes+1),
+	})
+	out, err := dispatch(context.Background(), payload)
+	if err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	resp, ok := out.(events.APIGatewayV2HTTPResponse)
+	if !ok {
+		t.Fatalf("dispatch returned %T, want an API Gateway response", out)
+	}
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413", resp.StatusCode)
+	}
+}
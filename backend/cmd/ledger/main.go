@@ -0,0 +1,116 @@
+// Command ledger checks an instance's ledger for drift and, with -repair,
+// fixes it: the Go counterpart of `scripts/add-data.sh audit` and `repair`,
+// deriving the expected values with the same service code the API runs
+// rather than a shell re-implementation of the carry chain.
+//
+//	TABLE_NAME=passbook-kids-prod go run ./cmd/ledger            # check; exits 1 on drift
+//	TABLE_NAME=passbook-kids-prod go run ./cmd/ledger -repair    # check and fix
+//	TABLE_NAME=passbook-kids-prod go run ./cmd/ledger -account a1b2c3d4
+//
+// It checks one account's ledger, the default one unless -account names
+// another (GET /api/accounts lists their ids).
+//
+// CARRY_OVER_BALANCE must match the instance's deployment setting, exactly as
+// the Lambda reads it: anything but "false" means carry-over is on. A
+// carry-over mode set through PUT /api/settings is read from the table and
+// takes precedence, as it does in the Lambda.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+	"github.com/vppillai/passbook/backend/internal/service"
+)
+
+// errDrift is returned by run when the check found issues and was not asked
+// to repair them. main turns it into exit status 1, like `audit` does.
+var errDrift = errors.New("ledger has drifted")
+
+func main() {
+	repair := flag.Bool("repair", false, "fix every discrepancy found")
+	table := flag.String("table", os.Getenv("TABLE_NAME"), "DynamoDB table (default $TABLE_NAME)")
+	account := flag.String("account", model.DefaultAccountID, "account whose ledger to check")
+	flag.Parse()
+	if *table == "" {
+		log.Fatal("ledger: -table or TABLE_NAME is required")
+	}
+	carryOverBalance := os.Getenv("CARRY_OVER_BALANCE") != "false"
+
+	ctx := repository.WithAccount(context.Background(), *account)
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		log.Fatalf("ledger: failed to load AWS config: %v", err)
+	}
+	repo := repository.NewRepository(dynamodb.NewFromConfig(cfg), *table)
+	// The allowance and overspending settings play no part in the check.
+	svc := service.NewExpenseService(repo, 0, true, carryOverBalance)
+	if err := svc.CheckAccount(ctx, *account); err != nil {
+		log.Fatalf("ledger: account %s: %v", *account, err)
+	}
+
+	if err := run(ctx, svc, *repair, os.Stdout); err != nil {
+		if errors.Is(err, errDrift) {
+			os.Exit(1)
+		}
+		log.Fatalf("ledger: %v", err)
+	}
+}
+
+// run checks the ledger, or repairs it, and prints the report to out.
+func run(ctx context.Context, svc *service.ExpenseService, repair bool, out io.Writer) error {
+	check := svc.VerifyLedger
+	if repair {
+		check = svc.RepairLedger
+	}
+	report, err := check(ctx)
+	if errors.Is(err, service.ErrLedgerModified) {
+		return fmt.Errorf("%w; run it again", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Checked %d months.\n", report.Months)
+	manual := 0
+	for _, issue := range report.Issues {
+		month := issue.Month
+		if month == "" {
+			month = "-"
+		}
+		mark := ""
+		if issue.Manual {
+			mark = "  (manual)"
+			manual++
+		}
+		fmt.Fprintf(out, "  %-8s %-18s stored %10s  expected %10s%s\n", month, issue.Field, issue.Stored, issue.Expected, mark)
+	}
+	switch {
+	case len(report.Issues) == 0:
+		fmt.Fprintln(out, "Ledger is consistent.")
+		return nil
+	case report.Repaired:
+		if fixed := len(report.Issues) - manual; fixed > 0 {
+			fmt.Fprintf(out, "Repaired %d issues. Run again to confirm.\n", fixed)
+		}
+	default:
+		fmt.Fprintf(out, "%d issues found. Back up, then run with -repair.\n", len(report.Issues))
+		return errDrift
+	}
+	if manual > 0 {
+		// Allowance and funds entries are records, not derived values:
+		// -repair leaves them for a person to reconcile.
+		fmt.Fprintf(out, "%d issues need a manual fix; -repair does not touch allowances or funds entries.\n", manual)
+		return errDrift
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/service"
+	"github.com/vppillai/passbook/backend/internal/testutil"
+)
+
+// TestRun_ReportsThenRepairs checks the command's contract: a check that
+// finds drift prints it and returns errDrift (exit 1); -repair fixes it and
+// the next check passes.
+func TestRun_ReportsThenRepairs(t *testing.T) {
+	repo := testutil.NewFakeRepo()
+	testutil.SeedMonth(repo, "2025-01", 0, 100, 0, 100)
+	repo.Balance.TotalBalance = model.Dollars(90)
+	svc := service.NewExpenseService(repo, 0, true, true)
+	ctx := context.Background()
+
+	var out bytes.Buffer
+	if err := run(ctx, svc, false, &out); !errors.Is(err, errDrift) {
+		t.Fatalf("check err = %v, want errDrift", err)
+	}
+	if !strings.Contains(out.String(), "balance") || !strings.Contains(out.String(), "-repair") {
+		t.Errorf("check output = %q, want the balance issue and the repair hint", out.String())
+	}
+
+	out.Reset()
+	if err := run(ctx, svc, true, &out); err != nil {
+		t.Fatalf("repair: %v", err)
+	}
+	if !strings.Contains(out.String(), "Repaired 1 issues") {
+		t.Errorf("repair output = %q", out.String())
+	}
+
+	out.Reset()
+	if err := run(ctx, svc, false, &out); err != nil {
+		t.Fatalf("re-check: %v", err)
+	}
+	if !strings.Contains(out.String(), "Ledger is consistent.") {
+		t.Errorf("re-check output = %q", out.String())
+	}
+}
+
+// TestRun_ManualIssuesStillFail checks that -repair does not report success
+// over the issues it leaves for a person: it marks them and exits 1.
+func TestRun_ManualIssuesStillFail(t *testing.T) {
+	repo := testutil.NewFakeRepo()
+	testutil.SeedMonth(repo, "2025-01", 0, 100, 0, 100)
+	base := model.Dollars(100)
+	repo.Months["2025-01"].BaseAllowance = &base
+	repo.Funds[testutil.ExpenseKey("2025-01", "FUND#1#a")] = &model.FundEntry{SK: "FUND#1#a", Amount: model.Dollars(10)}
+	repo.Balance.TotalBalance = model.Dollars(100)
+	svc := service.NewExpenseService(repo, 0, true, true)
+
+	var out bytes.Buffer
+	if err := run(context.Background(), svc, true, &out); !errors.Is(err, errDrift) {
+		t.Fatalf("repair err = %v, want errDrift", err)
+	}
+	if !strings.Contains(out.String(), "(manual)") || strings.Contains(out.String(), "Repaired") {
+		t.Errorf("repair output = %q, want the manual issue and no repair claim", out.String())
+	}
+}
@@ -0,0 +1,27 @@
+// Package blobstore holds the bytes of expense attachments (receipt photos).
+//
+// The expense row in DynamoDB carries only an attachment's metadata; the file
+// itself goes to a BlobStore. A deployment uses S3Store. LocalStore keeps the
+// files in a directory, for running the backend outside AWS.
+package blobstore
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned by Get when no blob is stored under the key.
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore stores opaque blobs by key. Keys are chosen by the caller and
+// never come from a request, so implementations do not sanitize them beyond
+// refusing anything that would escape their namespace.
+type BlobStore interface {
+	// Put stores data under key, replacing any existing blob.
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	// Get returns the blob stored under key, or ErrNotFound.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete removes the blob under key. Deleting a missing key is not an
+	// error.
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,103 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestLocalStore_RoundTrip(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "attachments/a1", "image/png", []byte("one")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := store.Put(ctx, "attachments/a1", "image/png", []byte("two")); err != nil {
+		t.Fatalf("Put over existing: %v", err)
+	}
+	got, err := store.Get(ctx, "attachments/a1")
+	if err != nil || string(got) != "two" {
+		t.Fatalf("Get = %q, %v; want the replacement", got, err)
+	}
+
+	if err := store.Delete(ctx, "attachments/a1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, "attachments/a1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after delete err = %v, want ErrNotFound", err)
+	}
+	if err := store.Delete(ctx, "attachments/a1"); err != nil {
+		t.Errorf("deleting a missing key: %v", err)
+	}
+}
+
+func TestLocalStore_RefusesKeysOutsideRoot(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	for _, key := range []string{"", "/etc/passwd", "../escape", "a/../../escape"} {
+		if err := store.Put(context.Background(), key, "", []byte("x")); err == nil {
+			t.Errorf("Put(%q) succeeded, want an error", key)
+		}
+	}
+}
+
+// fakeS3 keeps objects in a map and answers a missing key the way S3 does.
+type fakeS3 struct {
+	objects map[string][]byte
+}
+
+func (f *fakeS3) PutObject(_ context.Context, in *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	data, err := io.ReadAll(in.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.objects[*in.Bucket+"/"+*in.Key] = data
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (f *fakeS3) GetObject(_ context.Context, in *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	data, ok := f.objects[*in.Bucket+"/"+*in.Key]
+	if !ok {
+		return nil, &types.NoSuchKey{}
+	}
+	return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(data))}, nil
+}
+
+func (f *fakeS3) DeleteObject(_ context.Context, in *s3.DeleteObjectInput, _ ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	delete(f.objects, *in.Bucket+"/"+*in.Key)
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func TestS3Store_RoundTripAndMissingKey(t *testing.T) {
+	client := &fakeS3{objects: map[string][]byte{}}
+	store := NewS3Store(client, "receipts")
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "attachments/a1", "image/jpeg", []byte("jpeg")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, ok := client.objects["receipts/attachments/a1"]; !ok {
+		t.Fatalf("object not written to the bucket: %v", client.objects)
+	}
+	got, err := store.Get(ctx, "attachments/a1")
+	if err != nil || string(got) != "jpeg" {
+		t.Fatalf("Get = %q, %v", got, err)
+	}
+	if err := store.Delete(ctx, "attachments/a1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, "attachments/a1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after delete err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/vppillai/passbook/backend/internal/httperr"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/service"
+)
+
+// AccountHeader selects the ledger a request reads and writes. Without it a
+// request works on the default account, as every request did before an
+// instance could hold several.
+const AccountHeader = "X-Account-Id"
+
+// accountIDFromPath extracts {id} from /api/accounts/{id}, with the same
+// rules as goalIDFromPath.
+func accountIDFromPath(path string) (string, bool) {
+	id := strings.TrimPrefix(path, "/api/accounts/")
+	if id == "" || strings.ContainsAny(id, "/#") {
+		return "", false
+	}
+	return id, true
+}
+
+// writeAccountNameError maps the name errors shared by create and rename;
+// it reports false for anything else.
+func writeAccountNameError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrAccountNameRequired):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Account name is required")
+	case errors.Is(err, service.ErrDescriptionTooLong):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Account name too long (max 100 characters)")
+	default:
+		return false
+	}
+	return true
+}
+
+func (rt *Router) handleListAccounts(w http.ResponseWriter, r *http.Request) {
+	response, err := rt.expenseService.ListAccounts(r.Context())
+	if err != nil {
+		log.Printf("accounts.list: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to list accounts")
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (rt *Router) handleCreateAccount(w http.ResponseWriter, r *http.Request) {
+	var req model.AccountRequest
+	if err := decodeStrict(&req, r); err != nil {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	account, err := rt.expenseService.CreateAccount(r.Context(), req.Name)
+	if err != nil {
+		if writeAccountNameError(w, err) {
+			return
+		}
+		log.Printf("accounts.create: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to create account")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(account)
+}
+
+func (rt *Router) handleRenameAccount(w http.ResponseWriter, r *http.Request) {
+	id, ok := accountIDFromPath(r.URL.Path)
+	if !ok {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid account ID")
+		return
+	}
+	var req model.AccountRequest
+	if err := decodeStrict(&req, r); err != nil {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	account, err := rt.expenseService.RenameAccount(r.Context(), id, req.Name)
+	if err != nil {
+		if writeAccountNameError(w, err) {
+			return
+		}
+		if errors.Is(err, service.ErrAccountNotFound) {
+			httperr.WriteJSON(w, http.StatusNotFound, "Account not found")
+			return
+		}
+		log.Printf("accounts.rename: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to rename account")
+		return
+	}
+	json.NewEncoder(w).Encode(account)
+}
+
+func (rt *Router) handleDeleteAccount(w http.ResponseWriter, r *http.Request) {
+	id, ok := accountIDFromPath(r.URL.Path)
+	if !ok {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid account ID")
+		return
+	}
+
+	if err := rt.expenseService.DeleteAccount(r.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, service.ErrAccountNotFound):
+			httperr.WriteJSON(w, http.StatusNotFound, "Account not found")
+		case errors.Is(err, service.ErrDefaultAccountDelete):
+			httperr.WriteJSON(w, http.StatusBadRequest, "The default account cannot be deleted")
+		case errors.Is(err, service.ErrAccountNotEmpty):
+			httperr.WriteJSON(w, http.StatusConflict, "Account still has months, recurring expenses, goals or instalment plans")
+		default:
+			log.Printf("accounts.delete: %v", err)
+			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to delete account")
+		}
+		return
+	}
+	json.NewEncoder(w).Encode(model.SuccessResponse{Success: true, Message: "Account deleted"})
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/vppillai/passbook/backend/internal/httperr"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/service"
+)
+
+func (rt *Router) handleGetAllowance(w http.ResponseWriter, r *http.Request) {
+	response, err := rt.expenseService.GetAllowanceSchedule(r.Context())
+	if err != nil {
+		log.Printf("allowance.get: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to get allowance schedule")
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (rt *Router) handleSetAllowance(w http.ResponseWriter, r *http.Request) {
+	var req model.AllowanceSchedule
+	if err := decodeStrict(&req, r); err != nil {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := rt.expenseService.SetAllowanceSchedule(r.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidAllowance):
+			httperr.WriteJSON(w, http.StatusBadRequest, "Allowance must be between $0 and $99,999.99")
+		case errors.Is(err, service.ErrInvalidAllowanceSchedule):
+			httperr.WriteJSON(w, http.StatusBadRequest, "Each step needs a distinct YYYY-MM month (max 120 steps)")
+		default:
+			log.Printf("allowance.set: %v", err)
+			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to save allowance schedule")
+		}
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
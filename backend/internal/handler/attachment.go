@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/vppillai/passbook/backend/internal/httperr"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/service"
+)
+
+// attachmentPath is a parsed /api/expense/{month}/{id}/attachments[/{attID}]
+// path. attachmentID is empty for the collection.
+type attachmentPath struct {
+	month        string
+	expenseID    string
+	attachmentID string
+}
+
+// isAttachmentPath reports whether path addresses an expense's attachments,
+// so the router sends it here rather than to the expense routes.
+func isAttachmentPath(path string) bool {
+	segments := strings.Split(strings.TrimPrefix(path, "/api/expense/"), "/")
+	return strings.HasPrefix(path, "/api/expense/") && len(segments) >= 3 && segments[2] == "attachments"
+}
+
+// parseAttachmentPath validates the month, expense id and (when present)
+// attachment id in an attachment path.
+func parseAttachmentPath(path string) (attachmentPath, bool) {
+	segments := strings.Split(strings.TrimPrefix(path, "/api/expense/"), "/")
+	if len(segments) < 3 || len(segments) > 4 || segments[2] != "attachments" {
+		return attachmentPath{}, false
+	}
+	p := attachmentPath{month: segments[0], expenseID: segments[1]}
+	if validateMonthKey(p.month) != nil || !validateExpenseID(p.expenseID) {
+		return attachmentPath{}, false
+	}
+	if len(segments) == 4 {
+		if segments[3] == "" || strings.Contains(segments[3], "#") {
+			return attachmentPath{}, false
+		}
+		p.attachmentID = segments[3]
+	}
+	return p, true
+}
+
+// writeAttachmentError maps the errors every attachment route shares and
+// reports false for anything else.
+func writeAttachmentError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrAttachmentsDisabled):
+		httperr.WriteJSON(w, http.StatusServiceUnavailable, "Attachments are not available")
+	case errors.Is(err, service.ErrExpenseNotFound):
+		httperr.WriteJSON(w, http.StatusNotFound, "Expense not found")
+	case errors.Is(err, service.ErrAttachmentNotFound):
+		httperr.WriteJSON(w, http.StatusNotFound, "Attachment not found")
+	case errors.Is(err, service.ErrExpenseModified):
+		httperr.WriteJSON(w, http.StatusConflict, "Expense was modified, please refresh and try again")
+	default:
+		return false
+	}
+	return true
+}
+
+// handleUploadAttachment takes the file as the raw request body — not JSON —
+// so a photo needs no client-side encoding beyond what API Gateway does.
+// cmd/api lets this route's body through at service.MaxAttachmentBytes
+// instead of the usual JSON cap.
+func (rt *Router) handleUploadAttachment(w http.ResponseWriter, r *http.Request) {
+	p, ok := parseAttachmentPath(r.URL.Path)
+	if !ok || p.attachmentID != "" {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid attachment path")
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(r.Body, service.MaxAttachmentBytes+1))
+	if err != nil {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	attachment, err := rt.expenseService.AddAttachment(r.Context(), p.month, p.expenseID, data)
+	if err != nil {
+		if writeAttachmentError(w, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrAttachmentTooLarge):
+			httperr.WriteJSON(w, http.StatusRequestEntityTooLarge, "Attachment must be between 1 byte and 4 MB")
+		case errors.Is(err, service.ErrUnsupportedAttachment):
+			httperr.WriteJSON(w, http.StatusUnsupportedMediaType, "Attachment must be a JPEG, PNG or WebP image, or a PDF")
+		case errors.Is(err, service.ErrTooManyAttachments):
+			httperr.WriteJSON(w, http.StatusConflict, "This expense already has the maximum number of attachments")
+		default:
+			log.Printf("attachment.upload: %v", err)
+			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to upload attachment")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(attachment)
+}
+
+// handleGetAttachments lists an expense's attachments, or with an
+// attachment id returns that file's bytes under its stored content type.
+func (rt *Router) handleGetAttachments(w http.ResponseWriter, r *http.Request) {
+	p, ok := parseAttachmentPath(r.URL.Path)
+	if !ok {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid attachment path")
+		return
+	}
+
+	if p.attachmentID == "" {
+		response, err := rt.expenseService.ListAttachments(r.Context(), p.month, p.expenseID)
+		if err != nil {
+			if writeAttachmentError(w, err) {
+				return
+			}
+			log.Printf("attachment.list: %v", err)
+			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to list attachments")
+			return
+		}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	attachment, data, err := rt.expenseService.GetAttachment(r.Context(), p.month, p.expenseID, p.attachmentID)
+	if err != nil {
+		if writeAttachmentError(w, err) {
+			return
+		}
+		log.Printf("attachment.get: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to get attachment")
+		return
+	}
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Header().Set("Content-Disposition", "inline")
+	w.Write(data)
+}
+
+func (rt *Router) handleDeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	p, ok := parseAttachmentPath(r.URL.Path)
+	if !ok || p.attachmentID == "" {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid attachment path")
+		return
+	}
+
+	if err := rt.expenseService.DeleteAttachment(r.Context(), p.month, p.expenseID, p.attachmentID); err != nil {
+		if writeAttachmentError(w, err) {
+			return
+		}
+		log.Printf("attachment.delete: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to delete attachment")
+		return
+	}
+	json.NewEncoder(w).Encode(model.SuccessResponse{Success: true, Message: "Attachment deleted"})
+}
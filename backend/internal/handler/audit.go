@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/vppillai/passbook/backend/internal/httperr"
+	"github.com/vppillai/passbook/backend/internal/service"
+)
+
+// handleListAudit serves GET /api/audit?limit=&cursor=, the audit journal
+// newest first.
+func (rt *Router) handleListAudit(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := int32(50)
+	if l := query.Get("limit"); l != "" {
+		if parsed, err := strconv.ParseInt(l, 10, 32); err == nil && parsed > 0 && parsed <= 100 {
+			limit = int32(parsed)
+		}
+	}
+
+	response, err := rt.expenseService.ListAudit(r.Context(), limit, query.Get("cursor"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCursor) {
+			httperr.WriteJSON(w, http.StatusBadRequest, "Invalid pagination cursor")
+			return
+		}
+		log.Printf("audit.list: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to list audit entries")
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
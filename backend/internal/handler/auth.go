@@ -136,6 +136,8 @@ func (rt *Router) handleChangePIN(w http.ResponseWriter, r *http.Request) {
 			httperr.WriteJSON(w, http.StatusBadRequest, "New PIN must be 4-6 digits")
 		case errors.Is(err, service.ErrPINNotNumeric):
 			httperr.WriteJSON(w, http.StatusBadRequest, "New PIN must contain only digits")
+		case errors.Is(err, service.ErrPINsMustDiffer):
+			httperr.WriteJSON(w, http.StatusBadRequest, "New PIN must differ from the member PIN")
 		default:
 			log.Printf("auth.change: %v", err)
 			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to change PIN")
@@ -160,3 +162,53 @@ func (rt *Router) handleLogout(w http.ResponseWriter, r *http.Request) {
 	}
 	json.NewEncoder(w).Encode(model.SuccessResponse{Success: true})
 }
+
+func (rt *Router) handleGetMemberPIN(w http.ResponseWriter, r *http.Request) {
+	isSet, err := rt.authService.HasMemberPIN(r.Context())
+	if err != nil {
+		log.Printf("auth.member: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to check member PIN")
+		return
+	}
+	json.NewEncoder(w).Encode(model.MemberPinStatusResponse{IsSet: isSet})
+}
+
+func (rt *Router) handleSetMemberPIN(w http.ResponseWriter, r *http.Request) {
+	var req model.MemberPinRequest
+	if err := decodeStrict(&req, r); err != nil {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := rt.authService.SetMemberPIN(r.Context(), req.AdminPin, req.Pin,
+		r.Header.Get(SourceIPHeader)); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidPIN):
+			httperr.WriteJSON(w, http.StatusUnauthorized, "Admin PIN is incorrect")
+		case errors.Is(err, service.ErrRateLimited):
+			httperr.WriteJSON(w, http.StatusTooManyRequests,
+				"Too many incorrect attempts. Please wait and try again.")
+		case errors.Is(err, service.ErrPINTooShort):
+			httperr.WriteJSON(w, http.StatusBadRequest, "Member PIN must be 4-6 digits")
+		case errors.Is(err, service.ErrPINNotNumeric):
+			httperr.WriteJSON(w, http.StatusBadRequest, "Member PIN must contain only digits")
+		case errors.Is(err, service.ErrPINsMustDiffer):
+			httperr.WriteJSON(w, http.StatusBadRequest, "Member PIN must differ from the admin PIN")
+		default:
+			log.Printf("auth.member: %v", err)
+			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to set member PIN")
+		}
+		return
+	}
+
+	json.NewEncoder(w).Encode(model.SuccessResponse{Success: true, Message: "Member PIN set"})
+}
+
+func (rt *Router) handleRemoveMemberPIN(w http.ResponseWriter, r *http.Request) {
+	if err := rt.authService.RemoveMemberPIN(r.Context()); err != nil {
+		log.Printf("auth.member: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to remove member PIN")
+		return
+	}
+	json.NewEncoder(w).Encode(model.SuccessResponse{Success: true, Message: "Member PIN removed"})
+}
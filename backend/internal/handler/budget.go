@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/vppillai/passbook/backend/internal/httperr"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/service"
+)
+
+func (rt *Router) handleGetBudgets(w http.ResponseWriter, r *http.Request) {
+	response, err := rt.expenseService.GetCategoryBudgets(r.Context())
+	if err != nil {
+		log.Printf("budgets.get: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to get budgets")
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (rt *Router) handleSetBudgets(w http.ResponseWriter, r *http.Request) {
+	var req model.CategoryBudgets
+	if err := decodeStrict(&req, r); err != nil {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := rt.expenseService.SetCategoryBudgets(r.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidBudget):
+			httperr.WriteJSON(w, http.StatusBadRequest, "Budget must be between $0.01 and $99,999.99")
+		case errors.Is(err, service.ErrInvalidBudgetCategory):
+			httperr.WriteJSON(w, http.StatusBadRequest, "Each budget needs a distinct category of at most 30 characters")
+		case errors.Is(err, service.ErrTooManyBudgets):
+			httperr.WriteJSON(w, http.StatusBadRequest, "Too many category budgets (max 50)")
+		default:
+			log.Printf("budgets.set: %v", err)
+			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to save budgets")
+		}
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeCategoryBudgetExceeded returns a 400 naming the category and what is
+// left of its budget, the category counterpart of writeInsufficientFunds.
+func writeCategoryBudgetExceeded(w http.ResponseWriter, err error) {
+	var exceeded *service.CategoryBudgetError
+	if errors.As(err, &exceeded) {
+		body := struct {
+			Error     string      `json:"error"`
+			Category  string      `json:"category"`
+			Remaining model.Money `json:"remaining"`
+		}{
+			Error:     "Category budget exceeded",
+			Category:  exceeded.Category,
+			Remaining: exceeded.Remaining,
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(body)
+		return
+	}
+	httperr.WriteJSON(w, http.StatusBadRequest, "Category budget exceeded")
+}
@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vppillai/passbook/backend/internal/httperr"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/service"
+)
+
+// choreIDFromPath extracts {id} from /api/chores/{id}, with the same rules
+// as goalIDFromPath.
+func choreIDFromPath(path string) (string, bool) {
+	id := strings.TrimPrefix(path, "/api/chores/")
+	if id == "" || strings.ContainsAny(id, "/#") {
+		return "", false
+	}
+	return id, true
+}
+
+// choreToComplete returns the chore id of /api/chores/{id}/complete, or ""
+// when path is not one.
+func choreToComplete(path string) string {
+	rest, ok := strings.CutPrefix(path, "/api/chores/")
+	if !ok {
+		return ""
+	}
+	id, ok := strings.CutSuffix(rest, "/complete")
+	if !ok || id == "" || strings.ContainsAny(id, "/#") {
+		return ""
+	}
+	return id
+}
+
+// choreCompletionIDFromPath returns the completion id of
+// /api/chores/completions/{id}/{action}, or "" when path is not one.
+func choreCompletionIDFromPath(path, action string) string {
+	rest, ok := strings.CutPrefix(path, "/api/chores/completions/")
+	if !ok {
+		return ""
+	}
+	id, ok := strings.CutSuffix(rest, "/"+action)
+	if !ok || id == "" || strings.ContainsAny(id, "/#") {
+		return ""
+	}
+	return id
+}
+
+// writeChoreValidationError maps the chore input errors shared by create
+// and update; it reports false for anything else.
+func writeChoreValidationError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrInvalidAmount):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Reward must be between $0.01 and $99,999.99")
+	case errors.Is(err, service.ErrChoreNameRequired):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Chore name is required")
+	case errors.Is(err, service.ErrDescriptionTooLong):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Chore name too long (max 100 characters)")
+	default:
+		return false
+	}
+	return true
+}
+
+func (rt *Router) handleListChores(w http.ResponseWriter, r *http.Request) {
+	response, err := rt.expenseService.ListChores(r.Context())
+	if err != nil {
+		log.Printf("chores.list: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to list chores")
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (rt *Router) handleCreateChore(w http.ResponseWriter, r *http.Request) {
+	var req model.CreateChoreRequest
+	if err := decodeStrict(&req, r); err != nil {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := rt.expenseService.CreateChore(r.Context(), &req)
+	if err != nil {
+		if writeChoreValidationError(w, err) {
+			return
+		}
+		log.Printf("chores.create: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to create chore")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (rt *Router) handleUpdateChore(w http.ResponseWriter, r *http.Request) {
+	id, ok := choreIDFromPath(r.URL.Path)
+	if !ok {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid chore ID")
+		return
+	}
+	var req model.UpdateChoreRequest
+	if err := decodeStrict(&req, r); err != nil {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := rt.expenseService.UpdateChore(r.Context(), id, &req)
+	if err != nil {
+		if writeChoreValidationError(w, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrNoChanges):
+			httperr.WriteJSON(w, http.StatusBadRequest, "No changes provided")
+		case errors.Is(err, service.ErrChoreNotFound):
+			httperr.WriteJSON(w, http.StatusNotFound, "Chore not found")
+		default:
+			log.Printf("chores.update: %v", err)
+			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to update chore")
+		}
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (rt *Router) handleDeleteChore(w http.ResponseWriter, r *http.Request) {
+	id, ok := choreIDFromPath(r.URL.Path)
+	if !ok {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid chore ID")
+		return
+	}
+
+	if err := rt.expenseService.DeleteChore(r.Context(), id); err != nil {
+		if errors.Is(err, service.ErrChoreNotFound) {
+			httperr.WriteJSON(w, http.StatusNotFound, "Chore not found")
+			return
+		}
+		log.Printf("chores.delete: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to delete chore")
+		return
+	}
+	json.NewEncoder(w).Encode(model.SuccessResponse{Success: true, Message: "Chore deleted"})
+}
+
+// handleCompleteChore serves POST /api/chores/{id}/complete. The
+// completion waits for approval, so the answer is 202, as for a member's
+// expense.
+func (rt *Router) handleCompleteChore(w http.ResponseWriter, r *http.Request) {
+	id := choreToComplete(r.URL.Path)
+
+	response, err := rt.expenseService.CompleteChore(r.Context(), id, time.Now())
+	if err != nil {
+		if errors.Is(err, service.ErrChoreNotFound) {
+			httperr.WriteJSON(w, http.StatusNotFound, "Chore not found")
+			return
+		}
+		log.Printf("chores.complete: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to submit chore")
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (rt *Router) handleListChoreCompletions(w http.ResponseWriter, r *http.Request) {
+	response, err := rt.expenseService.ListChoreCompletions(r.Context())
+	if err != nil {
+		log.Printf("chores.completions: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to list chore completions")
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleApproveChoreCompletion serves
+// POST /api/chores/completions/{id}/approve, crediting the reward.
+func (rt *Router) handleApproveChoreCompletion(w http.ResponseWriter, r *http.Request) {
+	id := choreCompletionIDFromPath(r.URL.Path, "approve")
+
+	response, err := rt.expenseService.ApproveChoreCompletion(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrChoreCompletionNotFound):
+			httperr.WriteJSON(w, http.StatusNotFound, "Chore completion not found")
+		case errors.Is(err, service.ErrMonthNotFound):
+			httperr.WriteJSON(w, http.StatusConflict, "The month was deleted; try again")
+		default:
+			log.Printf("chores.approve: %v", err)
+			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to approve chore")
+		}
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (rt *Router) handleRejectChoreCompletion(w http.ResponseWriter, r *http.Request) {
+	id := choreCompletionIDFromPath(r.URL.Path, "reject")
+
+	if err := rt.expenseService.RejectChoreCompletion(r.Context(), id); err != nil {
+		if errors.Is(err, service.ErrChoreCompletionNotFound) {
+			httperr.WriteJSON(w, http.StatusNotFound, "Chore completion not found")
+			return
+		}
+		log.Printf("chores.reject: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to reject chore")
+		return
+	}
+	json.NewEncoder(w).Encode(model.SuccessResponse{Success: true})
+}
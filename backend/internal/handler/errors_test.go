@@ -95,7 +95,7 @@ func TestAddFunds_OverCapIsBadRequest(t *testing.T) {
 	rt, repo := newTestRouter(t)
 	token := seedSession(repo)
 	repo.Months["2026-02"] = &model.MonthSummary{
-		Month: "2026-02", AllowanceAdded: 100, EndingBalance: 100,
+		Month: "2026-02", AllowanceAdded: model.Dollars(100), EndingBalance: model.Dollars(100),
 	}
 	repo.MonthList["2026-02"] = repo.Months["2026-02"]
 
@@ -105,7 +105,7 @@ func TestAddFunds_OverCapIsBadRequest(t *testing.T) {
 	if rec.Code != http.StatusBadRequest {
 		t.Errorf("status = %d, want 400 (body %q)", rec.Code, rec.Body.String())
 	}
-	if got := repo.Months["2026-02"].AllowanceAdded; got != 100 {
+	if got := repo.Months["2026-02"].AllowanceAdded; got != model.Dollars(100) {
 		t.Errorf("AllowanceAdded = %v, want 100 (nothing should have been credited)", got)
 	}
 }
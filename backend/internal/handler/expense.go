@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/vppillai/passbook/backend/internal/httperr"
+	"github.com/vppillai/passbook/backend/internal/middleware"
 	"github.com/vppillai/passbook/backend/internal/model"
 	"github.com/vppillai/passbook/backend/internal/repository"
 	"github.com/vppillai/passbook/backend/internal/service"
@@ -113,6 +114,36 @@ func (rt *Router) handleGetMonth(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// handleListExpenses serves GET /api/expenses?from=&to=&limit=&cursor=,
+// the expenses dated within [from, to] across months, paginated like the
+// month view.
+func (rt *Router) handleListExpenses(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := int32(50)
+	if l := query.Get("limit"); l != "" {
+		if parsed, err := strconv.ParseInt(l, 10, 32); err == nil && parsed > 0 && parsed <= 100 {
+			limit = int32(parsed)
+		}
+	}
+
+	response, err := rt.expenseService.GetExpensesInRange(r.Context(), query.Get("from"), query.Get("to"), limit, query.Get("cursor"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidDate):
+			httperr.WriteJSON(w, http.StatusBadRequest, "Invalid date format. Use YYYY-MM-DD")
+		case errors.Is(err, service.ErrInvalidDateRange):
+			httperr.WriteJSON(w, http.StatusBadRequest, "The from date must not be after the to date, and the range must be at most 366 days")
+		case errors.Is(err, service.ErrInvalidCursor):
+			httperr.WriteJSON(w, http.StatusBadRequest, "Invalid pagination cursor")
+		default:
+			log.Printf("expenses.list: %v", err)
+			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to list expenses")
+		}
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
 func (rt *Router) handleAddExpense(w http.ResponseWriter, r *http.Request) {
 	var req model.AddExpenseRequest
 	if err := decodeStrict(&req, r); err != nil {
@@ -120,27 +151,22 @@ func (rt *Router) handleAddExpense(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A member's expense waits for an admin's approval instead of being
+	// booked.
+	if middleware.GetSessionRole(r.Context()) == model.RoleMember {
+		pending, err := rt.expenseService.SubmitExpense(r.Context(), &req)
+		if err != nil {
+			writeAddExpenseError(w, "expense.submit", err)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(model.SubmitExpenseResponse{Success: true, Pending: pending})
+		return
+	}
+
 	response, err := rt.expenseService.AddExpense(r.Context(), &req)
 	if err != nil {
-		switch {
-		case errors.Is(err, service.ErrInvalidAmount):
-			httperr.WriteJSON(w, http.StatusBadRequest, amountRangeMessage)
-		case errors.Is(err, service.ErrInvalidMonth):
-			httperr.WriteJSON(w, http.StatusBadRequest, "Invalid month format. Use YYYY-MM")
-		case errors.Is(err, service.ErrInvalidDate):
-			httperr.WriteJSON(w, http.StatusBadRequest, "Invalid date format. Use YYYY-MM-DD")
-		case errors.Is(err, service.ErrFutureDate):
-			httperr.WriteJSON(w, http.StatusBadRequest, "Date cannot be in the future")
-		case errors.Is(err, service.ErrDateMonthMismatch):
-			httperr.WriteJSON(w, http.StatusBadRequest, "Date does not match the provided month")
-		case errors.Is(err, service.ErrDescriptionTooLong):
-			httperr.WriteJSON(w, http.StatusBadRequest, "Description too long (max 100 characters)")
-		case errors.Is(err, service.ErrInsufficientFunds):
-			writeInsufficientFunds(w, err)
-		default:
-			log.Printf("expense.add: %v", err)
-			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to add expense")
-		}
+		writeAddExpenseError(w, "expense.add", err)
 		return
 	}
 
@@ -148,6 +174,36 @@ func (rt *Router) handleAddExpense(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// writeAddExpenseError maps an AddExpense or SubmitExpense error to its
+// response; op labels the log line of a 500.
+func writeAddExpenseError(w http.ResponseWriter, op string, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidAmount):
+		httperr.WriteJSON(w, http.StatusBadRequest, amountRangeMessage)
+	case errors.Is(err, service.ErrInvalidMonth):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid month format. Use YYYY-MM")
+	case errors.Is(err, service.ErrInvalidDate):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid date format. Use YYYY-MM-DD")
+	case errors.Is(err, service.ErrFutureDate):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Date cannot be in the future")
+	case errors.Is(err, service.ErrDateMonthMismatch):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Date does not match the provided month")
+	case errors.Is(err, service.ErrDescriptionTooLong):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Description too long (max 100 characters)")
+	case errors.Is(err, service.ErrCategoryTooLong):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Category too long (max 30 characters)")
+	case errors.Is(err, service.ErrInsufficientFunds):
+		writeInsufficientFunds(w, err)
+	case errors.Is(err, service.ErrCategoryBudgetExceeded):
+		writeCategoryBudgetExceeded(w, err)
+	case errors.Is(err, service.ErrSpendingLimitExceeded):
+		writeSpendingLimitExceeded(w, err)
+	default:
+		log.Printf("%s: %v", op, err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to add expense")
+	}
+}
+
 // writeInsufficientFunds returns a 400 whose message includes the available
 // balance when the service supplied it (U4), falling back to the bare
 // message otherwise.
@@ -155,8 +211,8 @@ func writeInsufficientFunds(w http.ResponseWriter, err error) {
 	var insufficient *service.InsufficientFundsError
 	if errors.As(err, &insufficient) {
 		body := struct {
-			Error     string  `json:"error"`
-			Available float64 `json:"available"`
+			Error     string      `json:"error"`
+			Available model.Money `json:"available"`
 		}{
 			Error:     "Insufficient funds",
 			Available: insufficient.Available,
@@ -206,16 +262,24 @@ func (rt *Router) handleUpdateExpense(w http.ResponseWriter, r *http.Request) {
 			httperr.WriteJSON(w, http.StatusBadRequest, "Date cannot be in the future")
 		case errors.Is(err, service.ErrDescriptionTooLong):
 			httperr.WriteJSON(w, http.StatusBadRequest, "Description too long (max 100 characters)")
+		case errors.Is(err, service.ErrCategoryTooLong):
+			httperr.WriteJSON(w, http.StatusBadRequest, "Category too long (max 30 characters)")
 		case errors.Is(err, service.ErrNoChanges):
 			httperr.WriteJSON(w, http.StatusBadRequest, "No changes provided")
 		case errors.Is(err, service.ErrInsufficientFunds):
 			writeInsufficientFunds(w, err)
+		case errors.Is(err, service.ErrCategoryBudgetExceeded):
+			writeCategoryBudgetExceeded(w, err)
+		case errors.Is(err, service.ErrSpendingLimitExceeded):
+			writeSpendingLimitExceeded(w, err)
 		case errors.Is(err, service.ErrExpenseModified):
 			// Concurrent edit landed between read and write — tell the
 			// client to refresh, with 409 not a misleading 404 (U4).
 			httperr.WriteJSON(w, http.StatusConflict, "Expense was modified, please refresh and try again")
 		case errors.Is(err, service.ErrExpenseNotFound):
 			httperr.WriteJSON(w, http.StatusNotFound, "Expense not found")
+		case errors.Is(err, service.ErrInstalmentExpense):
+			httperr.WriteJSON(w, http.StatusConflict, "This expense is an instalment; edit or delete its plan instead")
 		default:
 			log.Printf("expense.update: %v", err)
 			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to update expense")
@@ -256,6 +320,8 @@ func (rt *Router) handleDeleteExpense(w http.ResponseWriter, r *http.Request) {
 			httperr.WriteJSON(w, http.StatusConflict, "Expense was modified, please refresh and try again")
 		case errors.Is(err, service.ErrExpenseNotFound):
 			httperr.WriteJSON(w, http.StatusNotFound, "Expense not found")
+		case errors.Is(err, service.ErrInstalmentExpense):
+			httperr.WriteJSON(w, http.StatusConflict, "This expense is an instalment; edit or delete its plan instead")
 		default:
 			log.Printf("expense.delete: %v", err)
 			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to delete expense")
@@ -308,7 +374,7 @@ func (rt *Router) handleAddFunds(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response, err := rt.expenseService.AddFunds(r.Context(), month, req.Amount)
+	response, err := rt.expenseService.AddFunds(r.Context(), month, req.Amount, req.Description)
 	if err != nil {
 		switch {
 		// Both ends of the amount rule are user error, not server error:
@@ -318,6 +384,8 @@ func (rt *Router) handleAddFunds(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, service.ErrFundsNotPositive),
 			errors.Is(err, service.ErrInvalidAmount):
 			httperr.WriteJSON(w, http.StatusBadRequest, amountRangeMessage)
+		case errors.Is(err, service.ErrDescriptionTooLong):
+			httperr.WriteJSON(w, http.StatusBadRequest, "Description too long (max 100 characters)")
 		case errors.Is(err, service.ErrMonthNotFound):
 			httperr.WriteJSON(w, http.StatusNotFound, "Month not found")
 		default:
@@ -330,6 +398,45 @@ func (rt *Router) handleAddFunds(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// handleWithdrawFunds serves POST /api/month/{month}/withdraw, taking part of
+// the month's allowance back. Same response as handleAddFunds.
+func (rt *Router) handleWithdrawFunds(w http.ResponseWriter, r *http.Request) {
+	month := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/month/"), "/withdraw")
+	if err := validateMonthKey(month); err != nil {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid month format. Use YYYY-MM")
+		return
+	}
+
+	var req model.WithdrawFundsRequest
+	if err := decodeStrict(&req, r); err != nil {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := rt.expenseService.WithdrawFunds(r.Context(), month, req.Amount)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrFundsNotPositive),
+			errors.Is(err, service.ErrInvalidAmount):
+			httperr.WriteJSON(w, http.StatusBadRequest, amountRangeMessage)
+		case errors.Is(err, service.ErrWithdrawalExceedsAllowance):
+			httperr.WriteJSON(w, http.StatusBadRequest, "Cannot take back more than the month's allowance, less what chores earned")
+		case errors.Is(err, service.ErrInsufficientFunds):
+			writeInsufficientFunds(w, err)
+		case errors.Is(err, service.ErrMonthNotFound):
+			httperr.WriteJSON(w, http.StatusNotFound, "Month not found")
+		case errors.Is(err, service.ErrMonthModified):
+			httperr.WriteJSON(w, http.StatusConflict, "This month changed just now. Refresh and try again.")
+		default:
+			log.Printf("funds.withdraw: %v", err)
+			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to withdraw funds")
+		}
+		return
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
 func (rt *Router) handleDeleteMonth(w http.ResponseWriter, r *http.Request) {
 	// Extract month from path: /api/month/{yyyy-mm}
 	path := r.URL.Path
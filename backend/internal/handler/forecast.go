@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/vppillai/passbook/backend/internal/httperr"
+	"github.com/vppillai/passbook/backend/internal/service"
+)
+
+// handleGetForecast serves GET /api/forecast?month=, the projected ending
+// balance of month (default: this month).
+func (rt *Router) handleGetForecast(w http.ResponseWriter, r *http.Request) {
+	response, err := rt.expenseService.GetForecast(r.Context(), r.URL.Query().Get("month"), time.Now())
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidMonth):
+			httperr.WriteJSON(w, http.StatusBadRequest, "Invalid month format. Use YYYY-MM")
+		case errors.Is(err, service.ErrForecastFutureMonth):
+			httperr.WriteJSON(w, http.StatusBadRequest, "Cannot forecast a month that has not begun")
+		case errors.Is(err, service.ErrMonthNotFound):
+			httperr.WriteJSON(w, http.StatusNotFound, "Month not found")
+		default:
+			log.Printf("forecast.get: %v", err)
+			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to compute forecast")
+		}
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
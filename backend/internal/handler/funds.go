@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/vppillai/passbook/backend/internal/httperr"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/service"
+)
+
+// fundEntryPath splits /api/month/{month}/funds/{id}. ok is false (and a 400
+// written) when either part is malformed.
+func fundEntryPath(w http.ResponseWriter, r *http.Request) (month, id string, ok bool) {
+	month, id, found := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/month/"), "/funds/")
+	if !found || id == "" {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid funds entry path")
+		return "", "", false
+	}
+	if err := validateMonthKey(month); err != nil {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid month format. Use YYYY-MM")
+		return "", "", false
+	}
+	return month, id, true
+}
+
+// writeFundEntryError maps the errors the funds-credit edit and delete share.
+// It returns false for an error it does not know.
+func writeFundEntryError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrFundEntryNotFound):
+		httperr.WriteJSON(w, http.StatusNotFound, "Funds entry not found")
+	case errors.Is(err, service.ErrFundEntryModified):
+		httperr.WriteJSON(w, http.StatusConflict, "Funds entry was modified, please refresh and try again")
+	case errors.Is(err, service.ErrWithdrawalAmountFixed):
+		httperr.WriteJSON(w, http.StatusBadRequest, "A withdrawal's amount cannot be changed; delete it and withdraw again")
+	case errors.Is(err, service.ErrWithdrawalExceedsAllowance):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Cannot take back more than the month's allowance")
+	case errors.Is(err, service.ErrInsufficientFunds):
+		writeInsufficientFunds(w, err)
+	case errors.Is(err, service.ErrMonthNotFound):
+		httperr.WriteJSON(w, http.StatusNotFound, "Month not found")
+	default:
+		return false
+	}
+	return true
+}
+
+// handleUpdateFundEntry serves PUT /api/month/{month}/funds/{id}.
+func (rt *Router) handleUpdateFundEntry(w http.ResponseWriter, r *http.Request) {
+	month, id, ok := fundEntryPath(w, r)
+	if !ok {
+		return
+	}
+	var req model.UpdateFundEntryRequest
+	if err := decodeStrict(&req, r); err != nil {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := rt.expenseService.UpdateFundEntry(r.Context(), month, id, &req)
+	if err != nil {
+		if writeFundEntryError(w, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrNoChanges):
+			httperr.WriteJSON(w, http.StatusBadRequest, "No changes provided")
+		case errors.Is(err, service.ErrFundsNotPositive),
+			errors.Is(err, service.ErrInvalidAmount):
+			httperr.WriteJSON(w, http.StatusBadRequest, amountRangeMessage)
+		case errors.Is(err, service.ErrDescriptionTooLong):
+			httperr.WriteJSON(w, http.StatusBadRequest, "Description too long (max 100 characters)")
+		default:
+			log.Printf("funds.update: %v", err)
+			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to update funds entry")
+		}
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleDeleteFundEntry serves DELETE /api/month/{month}/funds/{id}.
+func (rt *Router) handleDeleteFundEntry(w http.ResponseWriter, r *http.Request) {
+	month, id, ok := fundEntryPath(w, r)
+	if !ok {
+		return
+	}
+	if err := rt.expenseService.DeleteFundEntry(r.Context(), month, id); err != nil {
+		if !writeFundEntryError(w, err) {
+			log.Printf("funds.delete: %v", err)
+			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to delete funds entry")
+		}
+		return
+	}
+	json.NewEncoder(w).Encode(model.SuccessResponse{Success: true, Message: "Funds entry deleted"})
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vppillai/passbook/backend/internal/httperr"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/service"
+)
+
+// goalIDFromPath extracts {id} from /api/goals/{id}, with the same rules
+// as recurringIDFromPath.
+func goalIDFromPath(path string) (string, bool) {
+	id := strings.TrimPrefix(path, "/api/goals/")
+	if id == "" || strings.ContainsAny(id, "/#") {
+		return "", false
+	}
+	return id, true
+}
+
+// writeGoalValidationError maps the goal input errors shared by create and
+// update; it reports false for anything else.
+func writeGoalValidationError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrInvalidAmount):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Target must be between $0.01 and $99,999.99")
+	case errors.Is(err, service.ErrGoalNameRequired):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Goal name is required")
+	case errors.Is(err, service.ErrDescriptionTooLong):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Goal name too long (max 100 characters)")
+	case errors.Is(err, service.ErrInvalidMonth):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid deadline format. Use YYYY-MM")
+	case errors.Is(err, service.ErrInvalidGoalPriority):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Priority must be between 0 and 99")
+	default:
+		return false
+	}
+	return true
+}
+
+func (rt *Router) handleListGoals(w http.ResponseWriter, r *http.Request) {
+	response, err := rt.expenseService.ListGoals(r.Context(), time.Now())
+	if err != nil {
+		log.Printf("goals.list: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to list goals")
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (rt *Router) handleCreateGoal(w http.ResponseWriter, r *http.Request) {
+	var req model.CreateGoalRequest
+	if err := decodeStrict(&req, r); err != nil {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := rt.expenseService.CreateGoal(r.Context(), &req)
+	if err != nil {
+		if writeGoalValidationError(w, err) {
+			return
+		}
+		log.Printf("goals.create: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to create goal")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (rt *Router) handleUpdateGoal(w http.ResponseWriter, r *http.Request) {
+	id, ok := goalIDFromPath(r.URL.Path)
+	if !ok {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid goal ID")
+		return
+	}
+	var req model.UpdateGoalRequest
+	if err := decodeStrict(&req, r); err != nil {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := rt.expenseService.UpdateGoal(r.Context(), id, &req)
+	if err != nil {
+		if writeGoalValidationError(w, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrNoChanges):
+			httperr.WriteJSON(w, http.StatusBadRequest, "No changes provided")
+		case errors.Is(err, service.ErrGoalNotFound):
+			httperr.WriteJSON(w, http.StatusNotFound, "Goal not found")
+		default:
+			log.Printf("goals.update: %v", err)
+			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to update goal")
+		}
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (rt *Router) handleDeleteGoal(w http.ResponseWriter, r *http.Request) {
+	id, ok := goalIDFromPath(r.URL.Path)
+	if !ok {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid goal ID")
+		return
+	}
+
+	if err := rt.expenseService.DeleteGoal(r.Context(), id); err != nil {
+		if errors.Is(err, service.ErrGoalNotFound) {
+			httperr.WriteJSON(w, http.StatusNotFound, "Goal not found")
+			return
+		}
+		log.Printf("goals.delete: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to delete goal")
+		return
+	}
+	json.NewEncoder(w).Encode(model.SuccessResponse{Success: true, Message: "Goal deleted"})
+}
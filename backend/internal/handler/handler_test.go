@@ -2,12 +2,15 @@ package handler
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/vppillai/passbook/backend/internal/blobstore"
 	"github.com/vppillai/passbook/backend/internal/model"
 	"github.com/vppillai/passbook/backend/internal/service"
 	"github.com/vppillai/passbook/backend/internal/testutil"
@@ -40,10 +43,12 @@ func seedSession(repo *testutil.FakeRepo) string {
 }
 
 type reqOpts struct {
-	origin  string // "" omits the Origin header
-	referer string
-	token   string
-	body    string
+	origin   string // "" omits the Origin header
+	referer  string
+	token    string
+	body     string
+	sourceIP string // what the Lambda entrypoint would set
+	account  string // "" omits the account header
 }
 
 func do(t *testing.T, rt *Router, method, path string, o reqOpts) *httptest.ResponseRecorder {
@@ -64,6 +69,12 @@ func do(t *testing.T, rt *Router, method, path string, o reqOpts) *httptest.Resp
 	if o.token != "" {
 		req.Header.Set("X-Session-Token", o.token)
 	}
+	if o.sourceIP != "" {
+		req.Header.Set(SourceIPHeader, o.sourceIP)
+	}
+	if o.account != "" {
+		req.Header.Set(AccountHeader, o.account)
+	}
 	rec := httptest.NewRecorder()
 	rt.ServeHTTP(rec, req)
 	return rec
@@ -155,6 +166,180 @@ func TestProtectedRoutes(t *testing.T) {
 	})
 }
 
+// A member session may read and submit expenses; anything else is 403
+// before its handler runs.
+func TestMemberPermissions(t *testing.T) {
+	rt, repo := newTestRouter(t)
+	month := time.Now().UTC().Format("2006-01")
+	testutil.SeedMonth(repo, month, 0, 100, 0, 100)
+	const tok = "member-session-token"
+	repo.Sessions[tok] = &model.Session{Token: tok, Role: model.RoleMember}
+	member := func(body string) reqOpts { return reqOpts{origin: testOrigin, token: tok, body: body} }
+
+	for _, c := range []struct {
+		method, path, body string
+		want               int
+	}{
+		{http.MethodGet, "/api/balance", "", http.StatusOK},
+		{http.MethodGet, "/api/month/" + month, "", http.StatusOK},
+		{http.MethodPost, "/api/expense", `{"amount":5,"description":"Snack"}`, http.StatusAccepted},
+	} {
+		if rec := do(t, rt, c.method, c.path, member(c.body)); rec.Code != c.want {
+			t.Errorf("member %s %s = %d, want %d: %s", c.method, c.path, rec.Code, c.want, rec.Body)
+		}
+	}
+	for _, c := range []struct{ method, path, body string }{
+		{http.MethodPost, "/api/month/" + month + "/funds", `{"amount":5}`},
+		{http.MethodDelete, "/api/month/" + month, ""},
+		{http.MethodPut, "/api/budgets", `{"budgets":{}}`},
+		{http.MethodPost, "/api/auth/webauthn/register/options", ""},
+		{http.MethodPut, "/api/auth/member-pin", `{"admin_pin":"1234","pin":"5678"}`},
+		{http.MethodPost, "/api/accounts", `{"name":"X"}`},
+		{http.MethodPost, "/api/pending/" + url.PathEscape("EXP#1#a") + "/approve", ""},
+	} {
+		if rec := do(t, rt, c.method, c.path, member(c.body)); rec.Code != http.StatusForbidden {
+			t.Errorf("member %s %s = %d, want 403", c.method, c.path, rec.Code)
+		}
+	}
+	if got := repo.Months[month].AllowanceAdded; got != model.Dollars(100) {
+		t.Errorf("allowance = %v, want the refused funds left out", got)
+	}
+}
+
+// A member's expense waits in /api/pending until an admin approves it,
+// which books it, or rejects it with a reason.
+func TestPendingEndpoints(t *testing.T) {
+	rt, repo := newTestRouter(t)
+	month := time.Now().UTC().Format("2006-01")
+	testutil.SeedMonth(repo, month, 0, 100, 0, 100)
+	repo.Balance.TotalBalance = model.Dollars(100)
+	const tok = "member-session-token"
+	repo.Sessions[tok] = &model.Session{Token: tok, Role: model.RoleMember}
+	member := func(body string) reqOpts { return reqOpts{origin: testOrigin, token: tok, body: body} }
+
+	submit := func(description string) model.PendingExpense {
+		t.Helper()
+		rec := do(t, rt, http.MethodPost, "/api/expense", member(`{"amount":30,"description":"`+description+`"}`))
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("submit = %d, want 202: %s", rec.Code, rec.Body)
+		}
+		var resp model.SubmitExpenseResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp.Pending == nil {
+			t.Fatalf("decode submit: %v %s", err, rec.Body)
+		}
+		return *resp.Pending
+	}
+	lego, book := submit("Lego"), submit("Book")
+	if got := repo.Months[month].TotalExpenses; got != 0 {
+		t.Fatalf("month total = %v, want nothing booked before approval", got)
+	}
+
+	rec := do(t, rt, http.MethodGet, "/api/pending", member(""))
+	var list model.PendingResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil || len(list.Expenses) != 2 {
+		t.Fatalf("list = %d %s, want both submissions", rec.Code, rec.Body)
+	}
+
+	rec = do(t, rt, http.MethodPost, "/api/pending/"+url.PathEscape(lego.ID)+"/approve", authed(repo, ""))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("approve = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if got := repo.Months[month].TotalExpenses; got != model.Dollars(30) {
+		t.Errorf("month total = %v, want the approved 30 booked", got)
+	}
+	rec = do(t, rt, http.MethodPost, "/api/pending/"+url.PathEscape(lego.ID)+"/approve", authed(repo, ""))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("second approve = %d, want 404", rec.Code)
+	}
+
+	path := "/api/pending/" + url.PathEscape(book.ID) + "/reject"
+	if rec := do(t, rt, http.MethodPost, path, authed(repo, `{"reason":" "}`)); rec.Code != http.StatusBadRequest {
+		t.Errorf("reject without a reason = %d, want 400", rec.Code)
+	}
+	if rec := do(t, rt, http.MethodPost, path, authed(repo, `{"reason":"Ask first"}`)); rec.Code != http.StatusOK {
+		t.Fatalf("reject = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if len(repo.Pending) != 0 {
+		t.Errorf("pending = %d entries, want none left", len(repo.Pending))
+	}
+	if last := repo.Audit[len(repo.Audit)-1]; last.Action != model.AuditExpenseReject || last.Reason != "Ask first" {
+		t.Errorf("journal = %+v, want the rejection and its reason", last)
+	}
+}
+
+func TestChoreEndpoints(t *testing.T) {
+	rt, repo := newTestRouter(t)
+	month := time.Now().UTC().Format("2006-01")
+	testutil.SeedMonth(repo, month, 0, 100, 0, 100)
+	repo.Balance.TotalBalance = model.Dollars(100)
+	const tok = "member-session-token"
+	repo.Sessions[tok] = &model.Session{Token: tok, Role: model.RoleMember}
+	member := func(body string) reqOpts { return reqOpts{origin: testOrigin, token: tok, body: body} }
+
+	if rec := do(t, rt, http.MethodPost, "/api/chores", member(`{"name":"Dishes","reward":2}`)); rec.Code != http.StatusForbidden {
+		t.Errorf("member create = %d, want 403", rec.Code)
+	}
+	rec := do(t, rt, http.MethodPost, "/api/chores", authed(repo, `{"name":"Dishes","reward":2}`))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create = %d, want 201: %s", rec.Code, rec.Body)
+	}
+	var chore model.Chore
+	if err := json.Unmarshal(rec.Body.Bytes(), &chore); err != nil {
+		t.Fatalf("decode chore: %v", err)
+	}
+	if rec := do(t, rt, http.MethodPost, "/api/chores", authed(repo, `{"name":"Free money","reward":0}`)); rec.Code != http.StatusBadRequest {
+		t.Errorf("zero reward = %d, want 400", rec.Code)
+	}
+
+	submit := func() model.ChoreCompletion {
+		t.Helper()
+		rec := do(t, rt, http.MethodPost, "/api/chores/"+chore.ID+"/complete", member(""))
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("complete = %d, want 202: %s", rec.Code, rec.Body)
+		}
+		var c model.ChoreCompletion
+		if err := json.Unmarshal(rec.Body.Bytes(), &c); err != nil {
+			t.Fatalf("decode completion: %v", err)
+		}
+		return c
+	}
+	first, second := submit(), submit()
+	if rec := do(t, rt, http.MethodPost, "/api/chores/missing/complete", member("")); rec.Code != http.StatusNotFound {
+		t.Errorf("complete a missing chore = %d, want 404", rec.Code)
+	}
+
+	rec = do(t, rt, http.MethodGet, "/api/chores/completions", member(""))
+	var list model.ChoreCompletionsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil || len(list.Completions) != 2 {
+		t.Fatalf("completions = %d %s, want both", rec.Code, rec.Body)
+	}
+
+	approve := "/api/chores/completions/" + first.ID + "/approve"
+	if rec := do(t, rt, http.MethodPost, approve, member("")); rec.Code != http.StatusForbidden {
+		t.Errorf("member approve = %d, want 403", rec.Code)
+	}
+	if rec := do(t, rt, http.MethodPost, approve, authed(repo, "")); rec.Code != http.StatusOK {
+		t.Fatalf("approve = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if got := repo.Months[month]; got.EarningsAdded != model.Dollars(2) || got.EndingBalance != model.Dollars(102) {
+		t.Errorf("month = %+v, want 2 earned on top of 100", got)
+	}
+	if rec := do(t, rt, http.MethodPost, approve, authed(repo, "")); rec.Code != http.StatusNotFound {
+		t.Errorf("second approve = %d, want 404", rec.Code)
+	}
+
+	if rec := do(t, rt, http.MethodPost, "/api/chores/completions/"+second.ID+"/reject", authed(repo, "")); rec.Code != http.StatusOK {
+		t.Fatalf("reject = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if len(repo.ChoreCompletions) != 0 || repo.Balance.TotalBalance != model.Dollars(102) {
+		t.Errorf("after reject: %d completions, balance %v, want none and 102", len(repo.ChoreCompletions), repo.Balance.TotalBalance)
+	}
+
+	if rec := do(t, rt, http.MethodDelete, "/api/chores/"+chore.ID, authed(repo, "")); rec.Code != http.StatusOK {
+		t.Errorf("delete = %d, want 200", rec.Code)
+	}
+}
+
 // =====================================================================
 // Auth endpoints: setup → verify → logout end-to-end (real Argon2 twice)
 // =====================================================================
@@ -283,6 +468,46 @@ func TestMonthEndpoints(t *testing.T) {
 		}
 	})
 
+	t.Run("withdraw funds", func(t *testing.T) {
+		rec := do(t, rt, http.MethodPost, "/api/month/2026-02/withdraw", authed(repo, `{"amount":30}`))
+		if rec.Code != http.StatusOK {
+			t.Errorf("withdraw = %d, want 200 (body %s)", rec.Code, rec.Body)
+		}
+		rec = do(t, rt, http.MethodPost, "/api/month/2026-02/withdraw", authed(repo, `{"amount":500}`))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("withdraw beyond the allowance = %d, want 400", rec.Code)
+		}
+		rec = do(t, rt, http.MethodPost, "/api/month/2030-01/withdraw", authed(repo, `{"amount":5}`))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("withdraw from missing month = %d, want 404", rec.Code)
+		}
+	})
+
+	t.Run("funds credits", func(t *testing.T) {
+		rec := do(t, rt, http.MethodPost, "/api/month/2026-02/funds", authed(repo, `{"amount":10,"description":"Chores"}`))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("funds = %d, want 200 (body %s)", rec.Code, rec.Body)
+		}
+		var added model.AddFundsResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &added); err != nil || added.Entry == nil {
+			t.Fatalf("funds body = %s, want the credit", rec.Body)
+		}
+		path := "/api/month/2026-02/funds/" + url.PathEscape(added.Entry.SK)
+
+		rec = do(t, rt, http.MethodPut, path, authed(repo, `{"description":"Chores and dishes"}`))
+		if rec.Code != http.StatusOK {
+			t.Errorf("edit credit = %d, want 200 (body %s)", rec.Code, rec.Body)
+		}
+		rec = do(t, rt, http.MethodDelete, path, authed(repo, ""))
+		if rec.Code != http.StatusOK {
+			t.Errorf("delete credit = %d, want 200 (body %s)", rec.Code, rec.Body)
+		}
+		rec = do(t, rt, http.MethodDelete, path, authed(repo, ""))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("delete missing credit = %d, want 404", rec.Code)
+		}
+	})
+
 	t.Run("list months rejects bad cursor", func(t *testing.T) {
 		rec := do(t, rt, http.MethodGet, "/api/months?cursor=%25%25", authed(repo, ""))
 		if rec.Code != http.StatusBadRequest {
@@ -320,6 +545,35 @@ func TestExpenseEndpoints(t *testing.T) {
 		}
 	})
 
+	t.Run("categories", func(t *testing.T) {
+		rec := do(t, rt, http.MethodPost, "/api/expense", authed(repo, `{"amount":5,"description":"gum","month":"2026-02","category":"Snacks"}`))
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("add categorized = %d, want 201 (body %s)", rec.Code, rec.Body)
+		}
+		long := strings.Repeat("x", 31)
+		rec = do(t, rt, http.MethodPost, "/api/expense", authed(repo, `{"amount":5,"month":"2026-02","category":"`+long+`"}`))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("long category = %d, want 400", rec.Code)
+		}
+		rec = do(t, rt, http.MethodGet, "/api/month/2026-02", authed(repo, ""))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("get month = %d, want 200", rec.Code)
+		}
+		var data model.MonthDataResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &data); err != nil {
+			t.Fatalf("decode month: %v", err)
+		}
+		found := false
+		for _, c := range data.Categories {
+			if c.Category == "snacks" && c.Total == model.Dollars(5) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("categories = %+v, want a snacks entry of 5", data.Categories)
+		}
+	})
+
 	t.Run("update expense path validation", func(t *testing.T) {
 		rec := do(t, rt, http.MethodPut, "/api/expense/2026-02", authed(repo, `{"amount":10}`))
 		if rec.Code != http.StatusBadRequest {
@@ -339,7 +593,7 @@ func TestExpenseEndpoints(t *testing.T) {
 
 	t.Run("update and delete round trip", func(t *testing.T) {
 		id := "EXP#1#abc"
-		repo.Expenses[testutil.ExpenseKey("2026-02", id)] = &model.Expense{SK: id, Amount: 30, Description: "book"}
+		repo.Expenses[testutil.ExpenseKey("2026-02", id)] = &model.Expense{SK: id, Amount: model.Dollars(30), Description: "book"}
 
 		rec := do(t, rt, http.MethodPut, "/api/expense/2026-02/EXP%231%23abc", authed(repo, `{"amount":20}`))
 		if rec.Code != http.StatusOK {
@@ -356,6 +610,563 @@ func TestExpenseEndpoints(t *testing.T) {
 	})
 }
 
+// =====================================================================
+// Category budgets: PUT/GET round trip and the enforced refusal, which
+// carries the remaining budget like the insufficient-funds body does.
+// =====================================================================
+
+func TestBudgetEndpoints(t *testing.T) {
+	rt, repo := newTestRouter(t)
+	repo.Config = &model.Config{PinHash: "x"}
+	testutil.SeedMonth(repo, "2026-02", 0, 100, 0, 100)
+
+	rec := do(t, rt, http.MethodPut, "/api/budgets", authed(repo, `{"budgets":{"Coffee":40},"enforce":true}`))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("put budgets = %d, want 200 (body %s)", rec.Code, rec.Body)
+	}
+	rec = do(t, rt, http.MethodPut, "/api/budgets", authed(repo, `{"budgets":{"coffee":-1}}`))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("negative budget = %d, want 400", rec.Code)
+	}
+	rec = do(t, rt, http.MethodGet, "/api/budgets", authed(repo, ""))
+	var got model.CategoryBudgets
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || got.Budgets["coffee"] != model.Dollars(40) || !got.Enforce {
+		t.Fatalf("get budgets = %s (err %v), want coffee 40 enforced", rec.Body, err)
+	}
+
+	rec = do(t, rt, http.MethodPost, "/api/expense", authed(repo, `{"amount":45,"month":"2026-02","category":"coffee"}`))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("over-budget add = %d, want 400 (body %s)", rec.Code, rec.Body)
+	}
+	var body struct {
+		Category  string  `json:"category"`
+		Remaining float64 `json:"remaining"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Category != "coffee" || body.Remaining != 40 {
+		t.Errorf("refusal body = %s, want coffee with 40 remaining", rec.Body)
+	}
+}
+
+// =====================================================================
+// Allowance schedule: PUT/GET round trip, and a new month granted the
+// step in force for it.
+// =====================================================================
+
+func TestAllowanceEndpoints(t *testing.T) {
+	rt, repo := newTestRouter(t)
+	repo.Config = &model.Config{PinHash: "x"}
+
+	rec := do(t, rt, http.MethodPut, "/api/allowance", authed(repo, `{"schedule":[{"from":"2026-13","amount":5}]}`))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("bad month = %d, want 400", rec.Code)
+	}
+	rec = do(t, rt, http.MethodPut, "/api/allowance", authed(repo, `{"schedule":[{"from":"2026-03","amount":150},{"from":"2026-01","amount":120}]}`))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("put allowance = %d, want 200 (body %s)", rec.Code, rec.Body)
+	}
+	rec = do(t, rt, http.MethodGet, "/api/allowance", authed(repo, ""))
+	var got model.AllowanceSchedule
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || got.Default != model.Dollars(100) ||
+		len(got.Schedule) != 2 || got.Schedule[0].From != "2026-01" {
+		t.Fatalf("get allowance = %s (err %v), want default 100 and two sorted steps", rec.Body, err)
+	}
+
+	rec = do(t, rt, http.MethodPost, "/api/month", authed(repo, `{"month":"2026-02"}`))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create month = %d, want 201 (body %s)", rec.Code, rec.Body)
+	}
+	if got := repo.Months["2026-02"].AllowanceAdded; got != model.Dollars(120) {
+		t.Errorf("February allowance = %v, want the 2026-01 step's 120", got)
+	}
+}
+
+// =====================================================================
+// Runtime settings: PUT/GET round trip over the deployment defaults.
+// =====================================================================
+
+func TestSettingsEndpoints(t *testing.T) {
+	rt, repo := newTestRouter(t)
+	repo.Config = &model.Config{PinHash: "x"}
+
+	rec := do(t, rt, http.MethodGet, "/api/settings", authed(repo, ""))
+	var got model.Settings
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || got.AllowOverspending || !got.CarryOverBalance {
+		t.Fatalf("get settings = %s (err %v), want the deployment's hard-stop with carry-over", rec.Body, err)
+	}
+	rec = do(t, rt, http.MethodPut, "/api/settings", authed(repo, `{}`))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("empty put = %d, want 400", rec.Code)
+	}
+	rec = do(t, rt, http.MethodPut, "/api/settings", authed(repo, `{"allow_overspending":true}`))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("put settings = %d, want 200 (body %s)", rec.Code, rec.Body)
+	}
+	if repo.Config.AllowOverspending == nil || !*repo.Config.AllowOverspending || repo.Config.CarryOverBalance != nil {
+		t.Errorf("config = %+v, want only the overspending override", repo.Config)
+	}
+
+	// A ledger written to mid-way leaves its account for a repeat.
+	testutil.SeedMonth(repo, "2026-01", 0, 100, 0, 100)
+	testutil.SeedMonth(repo, "2026-02", 100, 50, 0, 150)
+	repo.BeforeLedgerRepair = func() { repo.Months["2026-02"].TotalExpenses++ }
+	rec = do(t, rt, http.MethodPut, "/api/settings", authed(repo, `{"carry_over_balance":false}`))
+	var body struct {
+		Settings  model.Settings `json:"settings"`
+		Rechained []string       `json:"rechained"`
+		Remaining []string       `json:"remaining"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusConflict ||
+		body.Settings.CarryOverBalance || len(body.Rechained) != 0 || len(body.Remaining) != 1 || body.Remaining[0] != model.DefaultAccountID {
+		t.Errorf("interrupted re-chain = %d %s, want 409 naming the default account left", rec.Code, rec.Body)
+	}
+}
+
+func TestLimitsEndpoints(t *testing.T) {
+	rt, repo := newTestRouter(t)
+	repo.Config = &model.Config{PinHash: "x"}
+	testutil.SeedMonth(repo, "2026-02", 0, 100, 0, 100)
+
+	rec := do(t, rt, http.MethodPut, "/api/limits", authed(repo, `{"daily":-1,"weekly":0}`))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("negative limit = %d, want 400", rec.Code)
+	}
+	rec = do(t, rt, http.MethodPut, "/api/limits", authed(repo, `{"daily":5,"weekly":0}`))
+	if rec.Code != http.StatusOK || repo.Config.DailyLimit != model.Dollars(5) {
+		t.Fatalf("put limits = %d %s, want a 5 daily limit", rec.Code, rec.Body)
+	}
+
+	rec = do(t, rt, http.MethodPost, "/api/expense", authed(repo, `{"amount":6,"description":"Toy","date":"2026-02-10"}`))
+	var body struct {
+		Error           string       `json:"error"`
+		Period          string       `json:"period"`
+		DailyRemaining  *model.Money `json:"daily_remaining"`
+		WeeklyRemaining *model.Money `json:"weekly_remaining"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusBadRequest ||
+		body.Period != "day" || body.DailyRemaining == nil || *body.DailyRemaining != model.Dollars(5) || body.WeeklyRemaining != nil {
+		t.Fatalf("over-limit add = %d %s (err %v), want 400 with 5 left today", rec.Code, rec.Body, err)
+	}
+}
+
+func TestStatsEndpoint(t *testing.T) {
+	rt, repo := newTestRouter(t)
+	testutil.SeedMonth(repo, "2026-02", 0, 100, 0, 100)
+
+	rec := do(t, rt, http.MethodGet, "/api/stats?months=0", authed(repo, ""))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("months=0 = %d, want 400", rec.Code)
+	}
+	rec = do(t, rt, http.MethodGet, "/api/stats?months=2&to=2026-02", authed(repo, ""))
+	var got model.StatsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || rec.Code != http.StatusOK ||
+		len(got.Months) != 1 || got.Months[0].Income != model.Dollars(100) || len(got.ByHour) != 24 {
+		t.Fatalf("stats = %d %s (err %v), want February's 100 income", rec.Code, rec.Body, err)
+	}
+}
+
+func TestForecastEndpoint(t *testing.T) {
+	rt, repo := newTestRouter(t)
+	month := time.Now().UTC().Format("2006-01")
+	testutil.SeedMonth(repo, month, 0, 100, 0, 100)
+
+	rec := do(t, rt, http.MethodGet, "/api/forecast?month=2026-13", authed(repo, ""))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("bad month = %d, want 400", rec.Code)
+	}
+	rec = do(t, rt, http.MethodGet, "/api/forecast", authed(repo, ""))
+	var got model.ForecastResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || rec.Code != http.StatusOK ||
+		got.Month != month || got.ProjectedEnding != model.Dollars(100) || !got.HardStop {
+		t.Fatalf("forecast = %d %s (err %v), want this month ending on 100", rec.Code, rec.Body, err)
+	}
+}
+
+func TestRecurringEndpoints(t *testing.T) {
+	rt, repo := newTestRouter(t)
+
+	rec := do(t, rt, http.MethodPost, "/api/recurring", authed(repo, `{"amount":5,"description":"Comic","day_of_month":40}`))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("day 40 = %d, want 400", rec.Code)
+	}
+	rec = do(t, rt, http.MethodPost, "/api/recurring", authed(repo, `{"amount":5,"description":"Comic","day_of_month":1,"start_month":"2026-01"}`))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create = %d, want 201 (body %s)", rec.Code, rec.Body)
+	}
+	var created model.RecurringExpense
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil || created.ID == "" {
+		t.Fatalf("create body = %s (err %v)", rec.Body, err)
+	}
+
+	rec = do(t, rt, http.MethodPut, "/api/recurring/"+created.ID, authed(repo, `{"amount":6}`))
+	if rec.Code != http.StatusOK {
+		t.Errorf("update = %d, want 200 (body %s)", rec.Code, rec.Body)
+	}
+	rec = do(t, rt, http.MethodPut, "/api/recurring/nope", authed(repo, `{"amount":6}`))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("update missing = %d, want 404", rec.Code)
+	}
+
+	rec = do(t, rt, http.MethodGet, "/api/recurring", authed(repo, ""))
+	var list model.RecurringListResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil || len(list.Recurring) != 1 || list.Recurring[0].Amount != model.Dollars(6) {
+		t.Fatalf("list = %s (err %v), want the one schedule at 6", rec.Body, err)
+	}
+
+	// The test router is hard-stop with nothing funded: every due
+	// occurrence is reported as skipped, not failed.
+	rec = do(t, rt, http.MethodPost, "/api/recurring/run", authed(repo, ""))
+	var run model.RecurringRunResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &run); err != nil || rec.Code != http.StatusOK || len(run.Skipped) != 1 {
+		t.Errorf("run = %d %s, want 200 with one skipped occurrence", rec.Code, rec.Body)
+	}
+
+	rec = do(t, rt, http.MethodDelete, "/api/recurring/"+created.ID, authed(repo, ""))
+	if rec.Code != http.StatusOK {
+		t.Errorf("delete = %d, want 200", rec.Code)
+	}
+	rec = do(t, rt, http.MethodDelete, "/api/recurring/"+created.ID, authed(repo, ""))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("second delete = %d, want 404", rec.Code)
+	}
+}
+
+func TestGoalEndpoints(t *testing.T) {
+	rt, repo := newTestRouter(t)
+
+	rec := do(t, rt, http.MethodPost, "/api/goals", authed(repo, `{"name":"Bike","target":0}`))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("zero target = %d, want 400", rec.Code)
+	}
+	rec = do(t, rt, http.MethodPost, "/api/goals", authed(repo, `{"name":"Bike","target":180,"deadline":"2099-01","priority":1}`))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create = %d, want 201 (body %s)", rec.Code, rec.Body)
+	}
+	var created model.Goal
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil || created.ID == "" {
+		t.Fatalf("create body = %s (err %v)", rec.Body, err)
+	}
+
+	rec = do(t, rt, http.MethodPut, "/api/goals/"+created.ID, authed(repo, `{"deadline":"soon"}`))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("bad deadline = %d, want 400", rec.Code)
+	}
+	rec = do(t, rt, http.MethodPut, "/api/goals/nope", authed(repo, `{"target":200}`))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("update missing = %d, want 404", rec.Code)
+	}
+
+	repo.Balance.TotalBalance = model.Dollars(45)
+	rec = do(t, rt, http.MethodGet, "/api/goals", authed(repo, ""))
+	var list model.GoalsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil || len(list.Goals) != 1 || list.Goals[0].Saved != model.Dollars(45) || list.Goals[0].Percent != 25 {
+		t.Fatalf("list = %s (err %v), want the bike 45 saved, 25%%", rec.Body, err)
+	}
+
+	rec = do(t, rt, http.MethodDelete, "/api/goals/"+created.ID, authed(repo, ""))
+	if rec.Code != http.StatusOK {
+		t.Errorf("delete = %d, want 200", rec.Code)
+	}
+	rec = do(t, rt, http.MethodDelete, "/api/goals/"+created.ID, authed(repo, ""))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("second delete = %d, want 404", rec.Code)
+	}
+}
+
+// TestAccountEndpoints covers the account lifecycle and the header that
+// points every other endpoint at an account's ledger.
+func TestAccountEndpoints(t *testing.T) {
+	rt, repo := newTestRouter(t)
+	inAccount := func(id, body string) reqOpts {
+		o := authed(repo, body)
+		o.account = id
+		return o
+	}
+
+	rec := do(t, rt, http.MethodPost, "/api/accounts", authed(repo, `{"name":""}`))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("blank name = %d, want 400", rec.Code)
+	}
+	rec = do(t, rt, http.MethodPost, "/api/accounts", authed(repo, `{"name":"Sam"}`))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create = %d, want 201 (body %s)", rec.Code, rec.Body)
+	}
+	var sam model.Account
+	if err := json.Unmarshal(rec.Body.Bytes(), &sam); err != nil || sam.ID == "" {
+		t.Fatalf("create body = %s (err %v)", rec.Body, err)
+	}
+
+	rec = do(t, rt, http.MethodGet, "/api/balance", inAccount("nope", ""))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown account = %d, want 404", rec.Code)
+	}
+	rec = do(t, rt, http.MethodPost, "/api/month", inAccount(sam.ID, `{"month":"2026-02"}`))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create month in account = %d, want 201 (body %s)", rec.Code, rec.Body)
+	}
+	if repo.Ledger(sam.ID).Months["2026-02"] == nil || repo.Months["2026-02"] != nil {
+		t.Errorf("month landed in the wrong ledger")
+	}
+
+	rec = do(t, rt, http.MethodPut, "/api/accounts/"+sam.ID, authed(repo, `{"name":"Samantha"}`))
+	if rec.Code != http.StatusOK {
+		t.Errorf("rename = %d, want 200", rec.Code)
+	}
+	rec = do(t, rt, http.MethodGet, "/api/accounts", authed(repo, ""))
+	var list model.AccountsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil || len(list.Accounts) != 2 || list.Accounts[1].Name != "Samantha" {
+		t.Fatalf("list = %s (err %v), want the default and Samantha", rec.Body, err)
+	}
+
+	rec = do(t, rt, http.MethodDelete, "/api/accounts/"+sam.ID, authed(repo, ""))
+	if rec.Code != http.StatusConflict {
+		t.Errorf("delete with a month = %d, want 409", rec.Code)
+	}
+	rec = do(t, rt, http.MethodDelete, "/api/accounts/"+model.DefaultAccountID, authed(repo, ""))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("delete default = %d, want 400", rec.Code)
+	}
+}
+
+// TestInstalmentEndpoints covers the plan lifecycle over HTTP, including
+// the 409 when the expense API is pointed at one of the plan's rows.
+func TestInstalmentEndpoints(t *testing.T) {
+	rt, repo := newTestRouter(t)
+	testutil.SeedMonth(repo, service.GetCurrentMonth(), 0, 100, 0, 100)
+	repo.Balance.TotalBalance = model.Dollars(100)
+
+	rec := do(t, rt, http.MethodPost, "/api/instalments", authed(repo, `{"amount":60,"count":1}`))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("one instalment = %d, want 400", rec.Code)
+	}
+	rec = do(t, rt, http.MethodPost, "/api/instalments", authed(repo, `{"amount":60,"description":"Bike","count":2}`))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create = %d, want 201 (body %s)", rec.Code, rec.Body)
+	}
+	var created model.InstalmentPlan
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil || len(created.Instalments) != 2 {
+		t.Fatalf("create body = %s (err %v)", rec.Body, err)
+	}
+	first := created.Instalments[0]
+	if !first.Paid || created.Instalments[1].Paid || first.Amount != model.Dollars(30) {
+		t.Errorf("instalments = %+v, want 30 paid this month and 30 unpaid next", created.Instalments)
+	}
+
+	rec = do(t, rt, http.MethodDelete, "/api/expense/"+first.Month+"/"+first.ExpenseID, authed(repo, ""))
+	if rec.Code != http.StatusConflict {
+		t.Errorf("expense delete of an instalment = %d, want 409", rec.Code)
+	}
+
+	rec = do(t, rt, http.MethodPut, "/api/instalments/"+created.ID, authed(repo, `{"amount":50}`))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("update = %d, want 200 (body %s)", rec.Code, rec.Body)
+	}
+	second := created.Instalments[1]
+	if got := repo.Expenses[testutil.ExpenseKey(second.Month, second.ExpenseID)].Amount; got != model.Dollars(20) {
+		t.Errorf("unpaid instalment = %v, want 20", got)
+	}
+	rec = do(t, rt, http.MethodPut, "/api/instalments/nope", authed(repo, `{"amount":50}`))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("update missing = %d, want 404", rec.Code)
+	}
+
+	rec = do(t, rt, http.MethodGet, "/api/instalments", authed(repo, ""))
+	var list model.InstalmentListResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil || len(list.Instalments) != 1 || list.Instalments[0].Total != model.Dollars(50) {
+		t.Fatalf("list = %s (err %v), want the one plan at 50", rec.Body, err)
+	}
+
+	rec = do(t, rt, http.MethodDelete, "/api/instalments/"+created.ID, authed(repo, ""))
+	if rec.Code != http.StatusOK {
+		t.Errorf("delete = %d, want 200", rec.Code)
+	}
+	if _, ok := repo.Expenses[testutil.ExpenseKey(second.Month, second.ExpenseID)]; ok {
+		t.Error("unpaid instalment kept after the plan was deleted")
+	}
+	rec = do(t, rt, http.MethodDelete, "/api/instalments/"+created.ID, authed(repo, ""))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("second delete = %d, want 404", rec.Code)
+	}
+}
+
+// TestAttachmentEndpoints covers upload, list, download and delete, and the
+// 503 an instance without a blob store answers.
+func TestAttachmentEndpoints(t *testing.T) {
+	rt, repo := newTestRouter(t)
+	const id = "EXP#1000#receipt1"
+	repo.Expenses[testutil.ExpenseKey("2026-02", id)] = &model.Expense{SK: id, Amount: model.Dollars(30), Description: "book"}
+	base := "/api/expense/2026-02/" + id + "/attachments"
+	png := "\x89PNG\r\n\x1a\n" + strings.Repeat("\x00", 64)
+
+	rec := do(t, rt, http.MethodPost, base, authed(repo, png))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("upload without a blob store = %d, want 503", rec.Code)
+	}
+
+	store, err := blobstore.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	rt.expenseService.SetBlobStore(store)
+
+	rec = do(t, rt, http.MethodPost, base, authed(repo, "<html></html>"))
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("html upload = %d, want 415", rec.Code)
+	}
+	rec = do(t, rt, http.MethodPost, "/api/expense/2026-02/EXP#1#missing/attachments", authed(repo, png))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("upload to a missing expense = %d, want 404", rec.Code)
+	}
+	rec = do(t, rt, http.MethodPost, base, authed(repo, png))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("upload = %d, want 201 (body %s)", rec.Code, rec.Body)
+	}
+	var created model.Attachment
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil || created.ContentType != "image/png" {
+		t.Fatalf("upload body = %s (err %v)", rec.Body, err)
+	}
+
+	rec = do(t, rt, http.MethodGet, base, authed(repo, ""))
+	var list model.AttachmentListResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil || len(list.Attachments) != 1 {
+		t.Fatalf("list = %s (err %v), want one attachment", rec.Body, err)
+	}
+	rec = do(t, rt, http.MethodGet, base+"/"+created.ID, authed(repo, ""))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/png" || rec.Body.String() != png {
+		t.Errorf("download = %d %q (%d bytes), want the png back", rec.Code, rec.Header().Get("Content-Type"), rec.Body.Len())
+	}
+
+	rec = do(t, rt, http.MethodDelete, base+"/"+created.ID, authed(repo, ""))
+	if rec.Code != http.StatusOK {
+		t.Errorf("delete = %d, want 200", rec.Code)
+	}
+	rec = do(t, rt, http.MethodGet, base+"/"+created.ID, authed(repo, ""))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("download after delete = %d, want 404", rec.Code)
+	}
+}
+
+func TestSearchEndpoint(t *testing.T) {
+	rt, repo := newTestRouter(t)
+	repo.Expenses[testutil.ExpenseKey("2026-02", "EXP#1000#a")] = &model.Expense{
+		PK: "MONTH#2026-02", SK: "EXP#1000#a", Amount: model.Dollars(12), Description: "Pizza night",
+	}
+
+	rec := do(t, rt, http.MethodGet, "/api/search?q=piz", authed(repo, ""))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("search = %d, want 200 (body %s)", rec.Code, rec.Body)
+	}
+	var resp model.SearchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || len(resp.Results) != 1 || resp.Results[0].Month != "2026-02" {
+		t.Errorf("search body = %s (err %v), want the one pizza in 2026-02", rec.Body, err)
+	}
+
+	for _, q := range []string{"/api/search", "/api/search?q=x", "/api/search?q=pizza&cursor=bogus"} {
+		if rec := do(t, rt, http.MethodGet, q, authed(repo, "")); rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %s = %d, want 400", q, rec.Code)
+		}
+	}
+}
+
+func TestListExpensesEndpoint(t *testing.T) {
+	rt, repo := newTestRouter(t)
+	for _, e := range []struct{ month, sk string }{
+		{"2026-01", fmt.Sprintf("EXP#%d#a", time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC).UnixNano())},
+		{"2026-02", fmt.Sprintf("EXP#%d#b", time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC).UnixNano())},
+	} {
+		repo.Expenses[testutil.ExpenseKey(e.month, e.sk)] = &model.Expense{
+			PK: "MONTH#" + e.month, SK: e.sk, Amount: model.Dollars(5), Description: "Snack",
+		}
+	}
+
+	rec := do(t, rt, http.MethodGet, "/api/expenses?from=2026-01-15&to=2026-02-14&limit=1", authed(repo, ""))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("list = %d, want 200 (body %s)", rec.Code, rec.Body)
+	}
+	var resp model.ExpenseRangeResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || len(resp.Expenses) != 1 || resp.Expenses[0].Month != "2026-02" || resp.NextCursor == "" {
+		t.Fatalf("first page = %s (err %v), want the 2026-02 expense and a cursor", rec.Body, err)
+	}
+	rec = do(t, rt, http.MethodGet, "/api/expenses?from=2026-01-15&to=2026-02-14&limit=1&cursor="+resp.NextCursor, authed(repo, ""))
+	resp = model.ExpenseRangeResponse{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || len(resp.Expenses) != 1 || resp.Expenses[0].Month != "2026-01" || resp.NextCursor != "" {
+		t.Errorf("second page = %s (err %v), want the 2026-01 expense and no cursor", rec.Body, err)
+	}
+
+	for _, q := range []string{
+		"/api/expenses",
+		"/api/expenses?from=2026-02-01&to=2026-01-01",
+		"/api/expenses?from=2026-01-01&to=2026-02-01&cursor=bogus",
+	} {
+		if rec := do(t, rt, http.MethodGet, q, authed(repo, "")); rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %s = %d, want 400", q, rec.Code)
+		}
+	}
+}
+
+func TestTrashEndpoints(t *testing.T) {
+	rt, repo := newTestRouter(t)
+	testutil.SeedMonth(repo, "2026-02", 0, 100, 30, 70)
+	repo.Balance.TotalBalance = model.Dollars(70)
+	id := "EXP#1#abc"
+	repo.Expenses[testutil.ExpenseKey("2026-02", id)] = &model.Expense{
+		PK: "MONTH#2026-02", SK: id, Amount: model.Dollars(30), Description: "book",
+	}
+
+	if rec := do(t, rt, http.MethodDelete, "/api/expense/2026-02/EXP%231%23abc", authed(repo, "")); rec.Code != http.StatusOK {
+		t.Fatalf("delete = %d, want 200 (body %s)", rec.Code, rec.Body)
+	}
+	rec := do(t, rt, http.MethodGet, "/api/trash", authed(repo, ""))
+	var list model.TrashResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil || len(list.Expenses) != 1 || list.Expenses[0].ID != id {
+		t.Fatalf("trash = %s (err %v), want the deleted book", rec.Body, err)
+	}
+
+	rec = do(t, rt, http.MethodPost, "/api/trash/EXP%231%23abc/restore", authed(repo, ""))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("restore = %d, want 200 (body %s)", rec.Code, rec.Body)
+	}
+	if repo.Expenses[testutil.ExpenseKey("2026-02", id)] == nil {
+		t.Error("restored expense is not back in its month")
+	}
+	if rec := do(t, rt, http.MethodPost, "/api/trash/EXP%231%23abc/restore", authed(repo, "")); rec.Code != http.StatusNotFound {
+		t.Errorf("second restore = %d, want 404", rec.Code)
+	}
+	if rec := do(t, rt, http.MethodPost, "/api/trash/SUMMARY/restore", authed(repo, "")); rec.Code != http.StatusBadRequest {
+		t.Errorf("non-expense id = %d, want 400", rec.Code)
+	}
+}
+
+func TestAuditEndpoint(t *testing.T) {
+	rt, repo := newTestRouter(t)
+	testutil.SeedMonth(repo, "2026-02", 0, 100, 0, 100)
+	repo.Balance.TotalBalance = model.Dollars(100)
+
+	opts := authed(repo, `{"amount":12.5,"description":"Book","date":"2026-02-03"}`)
+	opts.sourceIP = "192.0.2.9"
+	if rec := do(t, rt, http.MethodPost, "/api/expense", opts); rec.Code != http.StatusCreated {
+		t.Fatalf("add = %d, want 201 (body %s)", rec.Code, rec.Body)
+	}
+
+	rec := do(t, rt, http.MethodGet, "/api/audit?limit=10", authed(repo, ""))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("audit = %d, want 200 (body %s)", rec.Code, rec.Body)
+	}
+	var list model.AuditResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil || len(list.Entries) != 1 {
+		t.Fatalf("audit = %s (err %v), want the one add", rec.Body, err)
+	}
+	e := list.Entries[0]
+	if e.Action != model.AuditExpenseAdd || e.SourceIP != "192.0.2.9" || e.After == nil || *e.After.Amount != model.Dollars(12.5) {
+		t.Errorf("entry = %+v, want the attributed 12.50 add", e)
+	}
+	if e.SessionID == "" || strings.Contains(rec.Body.String(), opts.token) {
+		t.Errorf("session id = %q, want a digest and never the token itself", e.SessionID)
+	}
+	if rec := do(t, rt, http.MethodGet, "/api/audit?cursor=bogus", authed(repo, "")); rec.Code != http.StatusBadRequest {
+		t.Errorf("bad cursor = %d, want 400", rec.Code)
+	}
+}
+
 // =====================================================================
 // TestDeleteMonthEndpoint pins U2: DELETE /api/month/{m} removes an empty
 // month (200) and refuses a month with expenses (409).
@@ -414,7 +1225,7 @@ func TestCreateMonthIdempotentAllowance(t *testing.T) {
 	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
 		t.Fatalf("unmarshal: %v", err)
 	}
-	if resp.Summary.AllowanceAdded != 100 {
+	if resp.Summary.AllowanceAdded != model.Dollars(100) {
 		t.Errorf("AllowanceAdded = %v, want 100", resp.Summary.AllowanceAdded)
 	}
 }
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vppillai/passbook/backend/internal/httperr"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/service"
+)
+
+// instalmentIDFromPath extracts {id} from /api/instalments/{id}, with the
+// same rules as recurringIDFromPath.
+func instalmentIDFromPath(path string) (string, bool) {
+	id := strings.TrimPrefix(path, "/api/instalments/")
+	if id == "" || strings.ContainsAny(id, "/#") {
+		return "", false
+	}
+	return id, true
+}
+
+// writeInstalmentError maps the errors shared by creating and editing a
+// plan — input validation and the booking refusals — and reports false for
+// anything else.
+func writeInstalmentError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrInvalidAmount):
+		httperr.WriteJSON(w, http.StatusBadRequest, amountRangeMessage)
+	case errors.Is(err, service.ErrInvalidInstalmentCount):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Number of instalments must be between 2 and 24")
+	case errors.Is(err, service.ErrInstalmentTotalTooLow):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Amount must be more than the instalments already paid")
+	case errors.Is(err, service.ErrDescriptionTooLong):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Description too long (max 100 characters)")
+	case errors.Is(err, service.ErrCategoryTooLong):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Category too long (max 30 characters)")
+	case errors.Is(err, service.ErrInvalidDate):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid date format. Use YYYY-MM-DD")
+	case errors.Is(err, service.ErrFutureDate):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Date cannot be in the future")
+	case errors.Is(err, service.ErrInsufficientFunds):
+		writeInsufficientFunds(w, err)
+	case errors.Is(err, service.ErrCategoryBudgetExceeded):
+		writeCategoryBudgetExceeded(w, err)
+	default:
+		return false
+	}
+	return true
+}
+
+func (rt *Router) handleListInstalmentPlans(w http.ResponseWriter, r *http.Request) {
+	response, err := rt.expenseService.ListInstalmentPlans(r.Context(), time.Now())
+	if err != nil {
+		log.Printf("instalments.list: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to list instalment plans")
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (rt *Router) handleCreateInstalmentPlan(w http.ResponseWriter, r *http.Request) {
+	var req model.CreateInstalmentRequest
+	if err := decodeStrict(&req, r); err != nil {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := rt.expenseService.CreateInstalmentPlan(r.Context(), &req)
+	if err != nil {
+		if writeInstalmentError(w, err) {
+			return
+		}
+		log.Printf("instalments.create: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to create instalment plan")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (rt *Router) handleUpdateInstalmentPlan(w http.ResponseWriter, r *http.Request) {
+	id, ok := instalmentIDFromPath(r.URL.Path)
+	if !ok {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid instalment plan ID")
+		return
+	}
+	var req model.UpdateInstalmentRequest
+	if err := decodeStrict(&req, r); err != nil {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := rt.expenseService.UpdateInstalmentPlan(r.Context(), id, &req, time.Now())
+	if err != nil {
+		if writeInstalmentError(w, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrNoChanges):
+			httperr.WriteJSON(w, http.StatusBadRequest, "No changes provided")
+		case errors.Is(err, service.ErrInstalmentNotFound):
+			httperr.WriteJSON(w, http.StatusNotFound, "Instalment plan not found")
+		case errors.Is(err, service.ErrInstalmentSettled):
+			httperr.WriteJSON(w, http.StatusConflict, "Every instalment has been paid; nothing left to change")
+		case errors.Is(err, service.ErrExpenseModified):
+			httperr.WriteJSON(w, http.StatusConflict, "Instalment plan was modified, please refresh and try again")
+		default:
+			log.Printf("instalments.update: %v", err)
+			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to update instalment plan")
+		}
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (rt *Router) handleDeleteInstalmentPlan(w http.ResponseWriter, r *http.Request) {
+	id, ok := instalmentIDFromPath(r.URL.Path)
+	if !ok {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid instalment plan ID")
+		return
+	}
+
+	if err := rt.expenseService.DeleteInstalmentPlan(r.Context(), id, time.Now()); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInstalmentNotFound):
+			httperr.WriteJSON(w, http.StatusNotFound, "Instalment plan not found")
+		case errors.Is(err, service.ErrExpenseModified):
+			httperr.WriteJSON(w, http.StatusConflict, "Instalment plan was modified, please refresh and try again")
+		default:
+			log.Printf("instalments.delete: %v", err)
+			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to delete instalment plan")
+		}
+		return
+	}
+	json.NewEncoder(w).Encode(model.SuccessResponse{Success: true, Message: "Instalment plan deleted"})
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/vppillai/passbook/backend/internal/httperr"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/service"
+)
+
+func (rt *Router) handleGetLimits(w http.ResponseWriter, r *http.Request) {
+	response, err := rt.expenseService.GetSpendingLimits(r.Context())
+	if err != nil {
+		log.Printf("limits.get: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to get spending limits")
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (rt *Router) handleSetLimits(w http.ResponseWriter, r *http.Request) {
+	var req model.SpendingLimits
+	if err := decodeStrict(&req, r); err != nil {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := rt.expenseService.SetSpendingLimits(r.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidSpendingLimit):
+			httperr.WriteJSON(w, http.StatusBadRequest, "Spending limit must be between $0 (off) and $99,999.99")
+		default:
+			log.Printf("limits.set: %v", err)
+			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to save spending limits")
+		}
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeSpendingLimitExceeded returns a 400 naming the period whose limit was
+// hit and the headroom left in the day and the week (null where that limit
+// is off), the limit counterpart of writeInsufficientFunds.
+func writeSpendingLimitExceeded(w http.ResponseWriter, err error) {
+	var exceeded *service.SpendingLimitError
+	if errors.As(err, &exceeded) {
+		message := "Daily spending limit reached"
+		if exceeded.Period == service.LimitPeriodWeek {
+			message = "Weekly spending limit reached"
+		}
+		body := struct {
+			Error           string       `json:"error"`
+			Period          string       `json:"period"`
+			DailyRemaining  *model.Money `json:"daily_remaining"`
+			WeeklyRemaining *model.Money `json:"weekly_remaining"`
+		}{
+			Error:           message,
+			Period:          exceeded.Period,
+			DailyRemaining:  exceeded.DailyRemaining,
+			WeeklyRemaining: exceeded.WeeklyRemaining,
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(body)
+		return
+	}
+	httperr.WriteJSON(w, http.StatusBadRequest, "Spending limit reached")
+}
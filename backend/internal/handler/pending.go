@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/vppillai/passbook/backend/internal/httperr"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/service"
+)
+
+// pendingIDFromPath returns the expense id of /api/pending/{id}/{action},
+// or "" when path is not one.
+func pendingIDFromPath(path, action string) string {
+	rest, ok := strings.CutPrefix(path, "/api/pending/")
+	if !ok {
+		return ""
+	}
+	id, ok := strings.CutSuffix(rest, "/"+action)
+	if !ok {
+		return ""
+	}
+	return id
+}
+
+func (rt *Router) handleListPending(w http.ResponseWriter, r *http.Request) {
+	response, err := rt.expenseService.ListPendingExpenses(r.Context())
+	if err != nil {
+		log.Printf("pending.list: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to list pending expenses")
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleApprovePending serves POST /api/pending/{id}/approve, where {id} is
+// the pending expense's id (already URL-decoded by API Gateway, like the
+// expense routes). An approval is refused for the same reasons as an add,
+// and the expense then stays pending.
+func (rt *Router) handleApprovePending(w http.ResponseWriter, r *http.Request) {
+	id := pendingIDFromPath(r.URL.Path, "approve")
+	if !validateExpenseID(id) {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid expense ID")
+		return
+	}
+
+	response, err := rt.expenseService.ApprovePendingExpense(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPendingExpenseNotFound):
+			httperr.WriteJSON(w, http.StatusNotFound, "Pending expense not found")
+		case errors.Is(err, service.ErrDuplicateExpense):
+			httperr.WriteJSON(w, http.StatusConflict, "Expense has already been approved")
+		case errors.Is(err, service.ErrInsufficientFunds):
+			writeInsufficientFunds(w, err)
+		case errors.Is(err, service.ErrCategoryBudgetExceeded):
+			writeCategoryBudgetExceeded(w, err)
+		case errors.Is(err, service.ErrSpendingLimitExceeded):
+			writeSpendingLimitExceeded(w, err)
+		default:
+			log.Printf("pending.approve: %v", err)
+			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to approve expense")
+		}
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleRejectPending serves POST /api/pending/{id}/reject with a reason,
+// which the journal keeps.
+func (rt *Router) handleRejectPending(w http.ResponseWriter, r *http.Request) {
+	id := pendingIDFromPath(r.URL.Path, "reject")
+	if !validateExpenseID(id) {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid expense ID")
+		return
+	}
+	var req model.RejectExpenseRequest
+	if err := decodeStrict(&req, r); err != nil {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := rt.expenseService.RejectPendingExpense(r.Context(), id, req.Reason); err != nil {
+		switch {
+		case errors.Is(err, service.ErrPendingExpenseNotFound):
+			httperr.WriteJSON(w, http.StatusNotFound, "Pending expense not found")
+		case errors.Is(err, service.ErrRejectReasonRequired):
+			httperr.WriteJSON(w, http.StatusBadRequest, "A reason is required")
+		case errors.Is(err, service.ErrDescriptionTooLong):
+			httperr.WriteJSON(w, http.StatusBadRequest, "Reason too long (max 100 characters)")
+		default:
+			log.Printf("pending.reject: %v", err)
+			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to reject expense")
+		}
+		return
+	}
+	json.NewEncoder(w).Encode(model.SuccessResponse{Success: true})
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vppillai/passbook/backend/internal/httperr"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/service"
+)
+
+// recurringIDFromPath extracts {id} from /api/recurring/{id}. Ids are the
+// 8-character uuid fragments CreateRecurring mints; anything containing a
+// separator is rejected rather than looked up.
+func recurringIDFromPath(path string) (string, bool) {
+	id := strings.TrimPrefix(path, "/api/recurring/")
+	if id == "" || strings.ContainsAny(id, "/#") {
+		return "", false
+	}
+	return id, true
+}
+
+// writeRecurringValidationError maps the schedule input errors shared by
+// create and update; it reports false for anything else.
+func writeRecurringValidationError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrInvalidAmount):
+		httperr.WriteJSON(w, http.StatusBadRequest, amountRangeMessage)
+	case errors.Is(err, service.ErrDescriptionTooLong):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Description too long (max 100 characters)")
+	case errors.Is(err, service.ErrCategoryTooLong):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Category too long (max 30 characters)")
+	case errors.Is(err, service.ErrInvalidDayOfMonth):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Day of month must be between 1 and 31")
+	case errors.Is(err, service.ErrInvalidMonth):
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid month format. Use YYYY-MM")
+	case errors.Is(err, service.ErrInvalidRecurringRange):
+		httperr.WriteJSON(w, http.StatusBadRequest, "End month cannot be before start month")
+	default:
+		return false
+	}
+	return true
+}
+
+func (rt *Router) handleListRecurring(w http.ResponseWriter, r *http.Request) {
+	response, err := rt.expenseService.ListRecurring(r.Context())
+	if err != nil {
+		log.Printf("recurring.list: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to list recurring expenses")
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (rt *Router) handleCreateRecurring(w http.ResponseWriter, r *http.Request) {
+	var req model.CreateRecurringRequest
+	if err := decodeStrict(&req, r); err != nil {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := rt.expenseService.CreateRecurring(r.Context(), &req)
+	if err != nil {
+		if writeRecurringValidationError(w, err) {
+			return
+		}
+		log.Printf("recurring.create: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to create recurring expense")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (rt *Router) handleUpdateRecurring(w http.ResponseWriter, r *http.Request) {
+	id, ok := recurringIDFromPath(r.URL.Path)
+	if !ok {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid recurring expense ID")
+		return
+	}
+	var req model.UpdateRecurringRequest
+	if err := decodeStrict(&req, r); err != nil {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := rt.expenseService.UpdateRecurring(r.Context(), id, &req)
+	if err != nil {
+		if writeRecurringValidationError(w, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrNoChanges):
+			httperr.WriteJSON(w, http.StatusBadRequest, "No changes provided")
+		case errors.Is(err, service.ErrRecurringNotFound):
+			httperr.WriteJSON(w, http.StatusNotFound, "Recurring expense not found")
+		default:
+			log.Printf("recurring.update: %v", err)
+			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to update recurring expense")
+		}
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (rt *Router) handleDeleteRecurring(w http.ResponseWriter, r *http.Request) {
+	id, ok := recurringIDFromPath(r.URL.Path)
+	if !ok {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid recurring expense ID")
+		return
+	}
+
+	if err := rt.expenseService.DeleteRecurring(r.Context(), id); err != nil {
+		if errors.Is(err, service.ErrRecurringNotFound) {
+			httperr.WriteJSON(w, http.StatusNotFound, "Recurring expense not found")
+			return
+		}
+		log.Printf("recurring.delete: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to delete recurring expense")
+		return
+	}
+	json.NewEncoder(w).Encode(model.SuccessResponse{Success: true, Message: "Recurring expense deleted"})
+}
+
+// handleRunRecurring books whatever is due right now. The scheduled run does
+// the same thing unattended; this lets the app catch up on open instead of
+// waiting for it.
+func (rt *Router) handleRunRecurring(w http.ResponseWriter, r *http.Request) {
+	response, err := rt.expenseService.RunRecurring(r.Context(), time.Now())
+	if err != nil {
+		log.Printf("recurring.run: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to run recurring expenses")
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
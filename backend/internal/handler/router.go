@@ -1,12 +1,18 @@
 package handler
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
 	"net/http"
 	"net/url"
 	"strings"
 
 	"github.com/vppillai/passbook/backend/internal/httperr"
 	"github.com/vppillai/passbook/backend/internal/middleware"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
 	"github.com/vppillai/passbook/backend/internal/service"
 )
 
@@ -103,11 +109,42 @@ func (rt *Router) route(w http.ResponseWriter, r *http.Request) {
 	// Protected routes - require auth
 	authMiddleware := middleware.Auth(rt.authService)
 	protectedHandler := authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		rt.protectedRoute(w, r)
+		// Attribute whatever this request changes in the audit journal.
+		actor := repository.Actor{
+			SessionID: sessionDigest(middleware.GetSessionToken(r.Context())),
+			SourceIP:  r.Header.Get(SourceIPHeader),
+			Role:      middleware.GetSessionRole(r.Context()),
+		}
+		ctx := repository.WithActor(r.Context(), actor)
+		// Scope it to the selected account's ledger.
+		if id := r.Header.Get(AccountHeader); id != "" {
+			if err := rt.expenseService.CheckAccount(ctx, id); err != nil {
+				if errors.Is(err, service.ErrAccountNotFound) {
+					httperr.WriteJSON(w, http.StatusNotFound, "Account not found")
+					return
+				}
+				log.Printf("accounts.check: %v", err)
+				httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to load account")
+				return
+			}
+			ctx = repository.WithAccount(ctx, id)
+		}
+		rt.protectedRoute(w, r.WithContext(ctx))
 	}))
 	protectedHandler.ServeHTTP(w, r)
 }
 
+// sessionDigest identifies a session in the audit journal without
+// recording its token: the journal is readable by every session, and a
+// token read from it would be a working credential until it expires.
+func sessionDigest(token string) string {
+	if token == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8])
+}
+
 // sameOrigin reports whether refererURL has the same scheme://host[:port]
 // origin as allowedOrigin. Both are parsed; a referer with a different host,
 // scheme, or port (including a sneaky suffix like "app.example.evil.com")
@@ -127,10 +164,38 @@ func sameOrigin(refererURL, allowedOrigin string) bool {
 	return ref.Scheme == allowed.Scheme && ref.Host == allowed.Host
 }
 
+// memberMay is the permission matrix for member sessions: they may read
+// anything, submit an expense or a done chore (both wait for approval),
+// attach a receipt to an expense, and log out.
+// Everything else — funds, months, the allowance, settings, limits, edits
+// and deletes, schedules, goals, chores, plans, budgets, accounts, PINs and
+// WebAuthn — needs an admin session.
+// An allow-list, so a route added later is admin-only until listed here.
+func memberMay(method, path string) bool {
+	switch {
+	case method == http.MethodGet:
+		return true
+	case path == "/api/expense" && method == http.MethodPost:
+		return true
+	case isAttachmentPath(path) && method == http.MethodPost:
+		return true
+	case choreToComplete(path) != "" && method == http.MethodPost:
+		return true
+	case path == "/api/auth/logout" && method == http.MethodPost:
+		return true
+	}
+	return false
+}
+
 func (rt *Router) protectedRoute(w http.ResponseWriter, r *http.Request) {
 	path := r.URL.Path
 	method := r.Method
 
+	if middleware.GetSessionRole(r.Context()) == model.RoleMember && !memberMay(method, path) {
+		httperr.WriteJSON(w, http.StatusForbidden, "Admin PIN required")
+		return
+	}
+
 	switch {
 	case path == "/api/auth/change" && method == http.MethodPost:
 		rt.handleChangePIN(w, r)
@@ -138,6 +203,15 @@ func (rt *Router) protectedRoute(w http.ResponseWriter, r *http.Request) {
 	case path == "/api/auth/logout" && method == http.MethodPost:
 		rt.handleLogout(w, r)
 		return
+	case path == "/api/auth/member-pin" && method == http.MethodGet:
+		rt.handleGetMemberPIN(w, r)
+		return
+	case path == "/api/auth/member-pin" && method == http.MethodPut:
+		rt.handleSetMemberPIN(w, r)
+		return
+	case path == "/api/auth/member-pin" && method == http.MethodDelete:
+		rt.handleRemoveMemberPIN(w, r)
+		return
 	case path == "/api/auth/webauthn/register/options" && method == http.MethodPost:
 		rt.handleWebAuthnRegisterOptions(w, r)
 		return
@@ -147,9 +221,27 @@ func (rt *Router) protectedRoute(w http.ResponseWriter, r *http.Request) {
 	case path == "/api/auth/webauthn" && method == http.MethodDelete:
 		rt.handleWebAuthnDisable(w, r)
 		return
+	case path == "/api/accounts" && method == http.MethodGet:
+		rt.handleListAccounts(w, r)
+		return
+	case path == "/api/accounts" && method == http.MethodPost:
+		rt.handleCreateAccount(w, r)
+		return
+	case strings.HasPrefix(path, "/api/accounts/") && method == http.MethodPut:
+		rt.handleRenameAccount(w, r)
+		return
+	case strings.HasPrefix(path, "/api/accounts/") && method == http.MethodDelete:
+		rt.handleDeleteAccount(w, r)
+		return
 	case path == "/api/balance" && method == http.MethodGet:
 		rt.handleGetBalance(w, r)
 		return
+	case path == "/api/stats" && method == http.MethodGet:
+		rt.handleGetStats(w, r)
+		return
+	case path == "/api/forecast" && method == http.MethodGet:
+		rt.handleGetForecast(w, r)
+		return
 	case path == "/api/months" && method == http.MethodGet:
 		rt.handleListMonths(w, r)
 		return
@@ -159,12 +251,141 @@ func (rt *Router) protectedRoute(w http.ResponseWriter, r *http.Request) {
 	case strings.HasPrefix(path, "/api/month/") && strings.HasSuffix(path, "/funds") && method == http.MethodPost:
 		rt.handleAddFunds(w, r)
 		return
+	case strings.HasPrefix(path, "/api/month/") && strings.HasSuffix(path, "/withdraw") && method == http.MethodPost:
+		rt.handleWithdrawFunds(w, r)
+		return
+	case strings.HasPrefix(path, "/api/month/") && strings.Contains(path, "/funds/") && method == http.MethodPut:
+		rt.handleUpdateFundEntry(w, r)
+		return
+	case strings.HasPrefix(path, "/api/month/") && strings.Contains(path, "/funds/") && method == http.MethodDelete:
+		rt.handleDeleteFundEntry(w, r)
+		return
 	case strings.HasPrefix(path, "/api/month/") && method == http.MethodGet:
 		rt.handleGetMonth(w, r)
 		return
 	case strings.HasPrefix(path, "/api/month/") && method == http.MethodDelete:
 		rt.handleDeleteMonth(w, r)
 		return
+	case path == "/api/budgets" && method == http.MethodGet:
+		rt.handleGetBudgets(w, r)
+		return
+	case path == "/api/budgets" && method == http.MethodPut:
+		rt.handleSetBudgets(w, r)
+		return
+	case path == "/api/allowance" && method == http.MethodGet:
+		rt.handleGetAllowance(w, r)
+		return
+	case path == "/api/allowance" && method == http.MethodPut:
+		rt.handleSetAllowance(w, r)
+		return
+	case path == "/api/limits" && method == http.MethodGet:
+		rt.handleGetLimits(w, r)
+		return
+	case path == "/api/limits" && method == http.MethodPut:
+		rt.handleSetLimits(w, r)
+		return
+	case path == "/api/settings" && method == http.MethodGet:
+		rt.handleGetSettings(w, r)
+		return
+	case path == "/api/settings" && method == http.MethodPut:
+		rt.handleUpdateSettings(w, r)
+		return
+	case path == "/api/recurring" && method == http.MethodGet:
+		rt.handleListRecurring(w, r)
+		return
+	case path == "/api/recurring" && method == http.MethodPost:
+		rt.handleCreateRecurring(w, r)
+		return
+	case path == "/api/recurring/run" && method == http.MethodPost:
+		rt.handleRunRecurring(w, r)
+		return
+	case strings.HasPrefix(path, "/api/recurring/") && method == http.MethodPut:
+		rt.handleUpdateRecurring(w, r)
+		return
+	case strings.HasPrefix(path, "/api/recurring/") && method == http.MethodDelete:
+		rt.handleDeleteRecurring(w, r)
+		return
+	case path == "/api/goals" && method == http.MethodGet:
+		rt.handleListGoals(w, r)
+		return
+	case path == "/api/goals" && method == http.MethodPost:
+		rt.handleCreateGoal(w, r)
+		return
+	case strings.HasPrefix(path, "/api/goals/") && method == http.MethodPut:
+		rt.handleUpdateGoal(w, r)
+		return
+	case strings.HasPrefix(path, "/api/goals/") && method == http.MethodDelete:
+		rt.handleDeleteGoal(w, r)
+		return
+	case path == "/api/chores" && method == http.MethodGet:
+		rt.handleListChores(w, r)
+		return
+	case path == "/api/chores" && method == http.MethodPost:
+		rt.handleCreateChore(w, r)
+		return
+	case path == "/api/chores/completions" && method == http.MethodGet:
+		rt.handleListChoreCompletions(w, r)
+		return
+	case choreCompletionIDFromPath(path, "approve") != "" && method == http.MethodPost:
+		rt.handleApproveChoreCompletion(w, r)
+		return
+	case choreCompletionIDFromPath(path, "reject") != "" && method == http.MethodPost:
+		rt.handleRejectChoreCompletion(w, r)
+		return
+	case choreToComplete(path) != "" && method == http.MethodPost:
+		rt.handleCompleteChore(w, r)
+		return
+	case strings.HasPrefix(path, "/api/chores/") && method == http.MethodPut:
+		rt.handleUpdateChore(w, r)
+		return
+	case strings.HasPrefix(path, "/api/chores/") && method == http.MethodDelete:
+		rt.handleDeleteChore(w, r)
+		return
+	case path == "/api/instalments" && method == http.MethodGet:
+		rt.handleListInstalmentPlans(w, r)
+		return
+	case path == "/api/instalments" && method == http.MethodPost:
+		rt.handleCreateInstalmentPlan(w, r)
+		return
+	case strings.HasPrefix(path, "/api/instalments/") && method == http.MethodPut:
+		rt.handleUpdateInstalmentPlan(w, r)
+		return
+	case strings.HasPrefix(path, "/api/instalments/") && method == http.MethodDelete:
+		rt.handleDeleteInstalmentPlan(w, r)
+		return
+	case isAttachmentPath(path) && method == http.MethodPost:
+		rt.handleUploadAttachment(w, r)
+		return
+	case isAttachmentPath(path) && method == http.MethodGet:
+		rt.handleGetAttachments(w, r)
+		return
+	case isAttachmentPath(path) && method == http.MethodDelete:
+		rt.handleDeleteAttachment(w, r)
+		return
+	case path == "/api/expenses" && method == http.MethodGet:
+		rt.handleListExpenses(w, r)
+		return
+	case path == "/api/audit" && method == http.MethodGet:
+		rt.handleListAudit(w, r)
+		return
+	case path == "/api/trash" && method == http.MethodGet:
+		rt.handleListTrash(w, r)
+		return
+	case isTrashRestorePath(path) && method == http.MethodPost:
+		rt.handleRestoreExpense(w, r)
+		return
+	case path == "/api/pending" && method == http.MethodGet:
+		rt.handleListPending(w, r)
+		return
+	case pendingIDFromPath(path, "approve") != "" && method == http.MethodPost:
+		rt.handleApprovePending(w, r)
+		return
+	case pendingIDFromPath(path, "reject") != "" && method == http.MethodPost:
+		rt.handleRejectPending(w, r)
+		return
+	case path == "/api/search" && method == http.MethodGet:
+		rt.handleSearch(w, r)
+		return
 	case path == "/api/expense" && method == http.MethodPost:
 		rt.handleAddExpense(w, r)
 		return
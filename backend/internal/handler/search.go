@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/vppillai/passbook/backend/internal/httperr"
+	"github.com/vppillai/passbook/backend/internal/service"
+)
+
+// handleSearch serves GET /api/search?q=&limit=&cursor=, paginated like
+// the month view.
+func (rt *Router) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := int32(50)
+	if l := query.Get("limit"); l != "" {
+		if parsed, err := strconv.ParseInt(l, 10, 32); err == nil && parsed > 0 && parsed <= 100 {
+			limit = int32(parsed)
+		}
+	}
+
+	response, err := rt.expenseService.Search(r.Context(), query.Get("q"), limit, query.Get("cursor"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSearchQueryTooShort):
+			httperr.WriteJSON(w, http.StatusBadRequest, "Search for a word of at least 2 letters or digits")
+		case errors.Is(err, service.ErrInvalidCursor):
+			httperr.WriteJSON(w, http.StatusBadRequest, "Invalid pagination cursor")
+		default:
+			log.Printf("search: %v", err)
+			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to search expenses")
+		}
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/vppillai/passbook/backend/internal/httperr"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/service"
+)
+
+func (rt *Router) handleGetSettings(w http.ResponseWriter, r *http.Request) {
+	response, err := rt.expenseService.Settings(r.Context())
+	if err != nil {
+		log.Printf("settings.get: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to get settings")
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleUpdateSettings serves PUT /api/settings. Naming carry_over_balance
+// re-chains every account. When some could not be, the settings are still
+// saved and the body names the accounts re-chained and remaining: a 409
+// means their ledgers changed mid-way, and repeating the request finishes
+// the job.
+func (rt *Router) handleUpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var req model.UpdateSettingsRequest
+	if err := decodeStrict(&req, r); err != nil {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := rt.expenseService.UpdateSettings(r.Context(), &req)
+	if err != nil {
+		var incomplete *service.RechainIncompleteError
+		switch {
+		case errors.Is(err, service.ErrNoChanges):
+			httperr.WriteJSON(w, http.StatusBadRequest, "No changes provided")
+		case errors.As(err, &incomplete):
+			writeRechainIncomplete(w, incomplete)
+		default:
+			log.Printf("settings.update: %v", err)
+			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to save settings")
+		}
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeRechainIncomplete reports a settings change whose re-chain stopped
+// short: 409 when only concurrent ledger writes stopped it, 500 otherwise.
+func writeRechainIncomplete(w http.ResponseWriter, incomplete *service.RechainIncompleteError) {
+	status, message := http.StatusConflict, "Settings saved, but some ledgers changed while they were being re-chained; try again"
+	for _, err := range incomplete.Unwrap() {
+		if !errors.Is(err, service.ErrLedgerModified) {
+			log.Printf("settings.update: %v", incomplete)
+			status, message = http.StatusInternalServerError, "Settings saved, but some ledgers could not be re-chained; try again"
+			break
+		}
+	}
+	body := struct {
+		Error     string         `json:"error"`
+		Settings  model.Settings `json:"settings"`
+		Rechained []string       `json:"rechained"`
+		Remaining []string       `json:"remaining"`
+	}{
+		Error:     message,
+		Settings:  incomplete.Settings,
+		Rechained: incomplete.Rechained,
+		Remaining: incomplete.Remaining,
+	}
+	if body.Rechained == nil {
+		body.Rechained = []string{}
+	}
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vppillai/passbook/backend/internal/httperr"
+	"github.com/vppillai/passbook/backend/internal/service"
+)
+
+// handleGetStats serves GET /api/stats?months=&to=, the spending analytics
+// over the months months ending with to (default: six, to this month).
+func (rt *Router) handleGetStats(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var months int
+	if m := query.Get("months"); m != "" {
+		parsed, err := strconv.Atoi(m)
+		if err != nil || parsed < 1 {
+			httperr.WriteJSON(w, http.StatusBadRequest, "months must be between 1 and 24")
+			return
+		}
+		months = parsed
+	}
+
+	response, err := rt.expenseService.GetStats(r.Context(), query.Get("to"), months, time.Now())
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidMonth):
+			httperr.WriteJSON(w, http.StatusBadRequest, "Invalid month format. Use YYYY-MM")
+		case errors.Is(err, service.ErrInvalidStatsWindow):
+			httperr.WriteJSON(w, http.StatusBadRequest, "months must be between 1 and 24")
+		default:
+			log.Printf("stats.get: %v", err)
+			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to compute stats")
+		}
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vppillai/passbook/backend/internal/httperr"
+	"github.com/vppillai/passbook/backend/internal/service"
+)
+
+// isTrashRestorePath reports whether path is /api/trash/{id}/restore.
+func isTrashRestorePath(path string) bool {
+	return strings.HasPrefix(path, "/api/trash/") && strings.HasSuffix(path, "/restore")
+}
+
+func (rt *Router) handleListTrash(w http.ResponseWriter, r *http.Request) {
+	response, err := rt.expenseService.ListTrash(r.Context(), time.Now())
+	if err != nil {
+		log.Printf("trash.list: %v", err)
+		httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to list deleted expenses")
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleRestoreExpense serves POST /api/trash/{id}/restore, where {id} is
+// the deleted expense's id (already URL-decoded by API Gateway, like the
+// expense routes). A restore is refused for the same reasons as an add.
+func (rt *Router) handleRestoreExpense(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/trash/"), "/restore")
+	if !validateExpenseID(id) {
+		httperr.WriteJSON(w, http.StatusBadRequest, "Invalid expense ID")
+		return
+	}
+
+	response, err := rt.expenseService.RestoreExpense(r.Context(), id, time.Now())
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrTrashedExpenseNotFound):
+			httperr.WriteJSON(w, http.StatusNotFound, "Deleted expense not found")
+		case errors.Is(err, service.ErrDuplicateExpense):
+			httperr.WriteJSON(w, http.StatusConflict, "Expense has already been restored")
+		case errors.Is(err, service.ErrInsufficientFunds):
+			writeInsufficientFunds(w, err)
+		case errors.Is(err, service.ErrCategoryBudgetExceeded):
+			writeCategoryBudgetExceeded(w, err)
+		default:
+			log.Printf("trash.restore: %v", err)
+			httperr.WriteJSON(w, http.StatusInternalServerError, "Failed to restore expense")
+		}
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
@@ -11,7 +11,10 @@ import (
 
 type contextKey string
 
-const SessionTokenKey contextKey = "session_token"
+const (
+	SessionTokenKey contextKey = "session_token"
+	SessionRoleKey  contextKey = "session_role"
+)
 
 // Auth returns a middleware that validates session tokens
 func Auth(authService *service.AuthService) func(http.Handler) http.Handler {
@@ -19,20 +22,21 @@ func Auth(authService *service.AuthService) func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			token := r.Header.Get("X-Session-Token")
 
-			valid, err := authService.ValidateSession(r.Context(), token)
+			session, err := authService.Authenticate(r.Context(), token)
 			if err != nil {
 				log.Printf("auth.middleware: session validation failed: %v", err)
 				httperr.WriteJSON(w, http.StatusInternalServerError, "Internal server error")
 				return
 			}
 
-			if !valid {
+			if session == nil {
 				httperr.WriteJSON(w, http.StatusUnauthorized, "Unauthorized")
 				return
 			}
 
-			// Add token to context
+			// Add token and role to context
 			ctx := context.WithValue(r.Context(), SessionTokenKey, token)
+			ctx = context.WithValue(ctx, SessionRoleKey, session.Role)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
@@ -43,3 +47,9 @@ func GetSessionToken(ctx context.Context) string {
 	token, _ := ctx.Value(SessionTokenKey).(string)
 	return token
 }
+
+// GetSessionRole extracts the session's role from context
+func GetSessionRole(ctx context.Context) string {
+	role, _ := ctx.Value(SessionRoleKey).(string)
+	return role
+}
@@ -14,7 +14,7 @@ func CORS(allowedOrigin string) func(http.Handler) http.Handler {
 			if origin == allowedOrigin {
 				w.Header().Set("Access-Control-Allow-Origin", allowedOrigin)
 				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Session-Token")
+				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Session-Token, X-Account-Id")
 				w.Header().Set("Access-Control-Max-Age", "86400")
 				w.Header().Set("Vary", "Origin")
 			}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// DefaultAccountID is the account of the ledger an instance had before it
+// could hold several. Its rows keep their original, unprefixed keys.
+const DefaultAccountID = "default"
+
+// Account is one ledger of the instance (PK="ACCOUNTS", SK="ACCT#<id>"):
+// its own balance, months, expenses, schedules, goals and trash. The PIN,
+// biometrics, sessions and category budgets are shared by every account.
+//
+// The default account has a row only once it has been renamed; until then
+// it is listed with DefaultAccountName.
+type Account struct {
+	PK        string    `dynamodbav:"PK" json:"-"`
+	SK        string    `dynamodbav:"SK" json:"-"`
+	ID        string    `dynamodbav:"id" json:"id"`
+	Name      string    `dynamodbav:"name" json:"name"`
+	CreatedAt time.Time `dynamodbav:"created_at" json:"created_at"`
+	UpdatedAt time.Time `dynamodbav:"updated_at" json:"updated_at"`
+}
+
+// DefaultAccountName is the default account's name until it is renamed.
+const DefaultAccountName = "Main"
+
+// AccountRequest is the JSON body for POST /api/accounts and
+// PUT /api/accounts/{id}.
+type AccountRequest struct {
+	Name string `json:"name"`
+}
+
+// AccountsResponse is returned by GET /api/accounts, the default account
+// first and the rest by name.
+type AccountsResponse struct {
+	Accounts []Account `json:"accounts"`
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// Attachment is one file attached to an expense — a receipt photo, say. The
+// expense row carries only this metadata; the bytes live in the blob store
+// under a key derived from ID.
+type Attachment struct {
+	ID          string    `dynamodbav:"id" json:"id"`
+	ContentType string    `dynamodbav:"content_type" json:"content_type"`
+	Size        int       `dynamodbav:"size_bytes" json:"size"`
+	CreatedAt   time.Time `dynamodbav:"created_at" json:"created_at"`
+}
+
+// AttachmentListResponse is returned by GET
+// /api/expense/{month}/{id}/attachments.
+type AttachmentListResponse struct {
+	Attachments []Attachment `json:"attachments"`
+}
@@ -0,0 +1,78 @@
+package model
+
+import "time"
+
+// Audit actions, the Action of an AuditEntry.
+const (
+	AuditExpenseAdd      = "expense.add"
+	AuditExpenseUpdate   = "expense.update"
+	AuditExpenseDelete   = "expense.delete"
+	AuditExpenseReject   = "expense.reject"
+	AuditMonthCreate     = "month.create"
+	AuditMonthDelete     = "month.delete"
+	AuditFundsAdd        = "funds.add"
+	AuditFundsWithdraw   = "funds.withdraw"
+	AuditFundsUpdate     = "funds.update"
+	AuditFundsDelete     = "funds.delete"
+	AuditPINChange       = "pin.change"
+	AuditMemberPINSet    = "pin.member.set"
+	AuditMemberPINRemove = "pin.member.remove"
+	AuditWebAuthnEnrol   = "webauthn.enrol"
+	AuditWebAuthnDisable = "webauthn.disable"
+	AuditLedgerRepair    = "ledger.repair"
+)
+
+// AuditEntry is one row of the append-only audit journal (PK="AUDIT",
+// SK="AUDIT#<unixnano>#<id>"), written in the same transaction as the
+// change it records. SessionID and SourceIP identify who made the change;
+// both are empty for the daily scheduled run.
+type AuditEntry struct {
+	PK     string    `dynamodbav:"PK" json:"-"`
+	SK     string    `dynamodbav:"SK" json:"-"`
+	ID     string    `dynamodbav:"id" json:"id"`
+	Action string    `dynamodbav:"action" json:"action"`
+	At     time.Time `dynamodbav:"at" json:"at"`
+	// SessionID is a digest of the session token, never the token itself:
+	// the journal is readable by any session.
+	SessionID string `dynamodbav:"session_id,omitempty" json:"session_id,omitempty"`
+	SourceIP  string `dynamodbav:"source_ip,omitempty" json:"source_ip,omitempty"`
+	// Role is the session's role, RoleAdmin or RoleMember.
+	Role string `dynamodbav:"role,omitempty" json:"role,omitempty"`
+	// Month and Target locate what changed: the month, and the expense or
+	// credential id within it. For a re-dated expense they name where it
+	// ended up; Before says where it was.
+	Month  string      `dynamodbav:"month,omitempty" json:"month,omitempty"`
+	Target string      `dynamodbav:"target,omitempty" json:"target,omitempty"`
+	Before *AuditState `dynamodbav:"before,omitempty" json:"before,omitempty"`
+	After  *AuditState `dynamodbav:"after,omitempty" json:"after,omitempty"`
+	// Account is the ledger the change was made in; empty for the default
+	// account and for the instance-wide PIN and biometric changes.
+	Account string `dynamodbav:"account,omitempty" json:"account,omitempty"`
+	// Reason is why a pending expense was rejected.
+	Reason string `dynamodbav:"reason,omitempty" json:"reason,omitempty"`
+}
+
+// AuditState is the part of a row an audited change touched, as it was
+// before or after. Only the fields that apply are set: an expense's month,
+// id, amount, description, category and date; a month's balances; the
+// amount of a funds top-up; the global balance.
+type AuditState struct {
+	Month           string     `dynamodbav:"month,omitempty" json:"month,omitempty"`
+	ExpenseID       string     `dynamodbav:"expense_id,omitempty" json:"expense_id,omitempty"`
+	Amount          *Money     `dynamodbav:"amount_cents,omitempty" json:"amount,omitempty"`
+	Description     string     `dynamodbav:"description,omitempty" json:"description,omitempty"`
+	Category        string     `dynamodbav:"category,omitempty" json:"category,omitempty"`
+	CreatedAt       *time.Time `dynamodbav:"created_at,omitempty" json:"created_at,omitempty"`
+	StartingBalance *Money     `dynamodbav:"starting_balance_cents,omitempty" json:"starting_balance,omitempty"`
+	AllowanceAdded  *Money     `dynamodbav:"allowance_added_cents,omitempty" json:"allowance_added,omitempty"`
+	EndingBalance   *Money     `dynamodbav:"ending_balance_cents,omitempty" json:"ending_balance,omitempty"`
+	TotalExpenses   *Money     `dynamodbav:"total_expenses_cents,omitempty" json:"total_expenses,omitempty"`
+	TotalBalance    *Money     `dynamodbav:"total_balance_cents,omitempty" json:"total_balance,omitempty"`
+}
+
+// AuditResponse is returned by GET /api/audit, newest first. NextCursor is
+// empty when there are no more entries.
+type AuditResponse struct {
+	Entries    []AuditEntry `json:"entries"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+// Chore is a task a child can do for a reward (PK="CHORES",
+// SK="CHORE#<id>"). Defining one moves no money; each approved
+// ChoreCompletion credits its reward to the month it was done in.
+type Chore struct {
+	PK        string    `dynamodbav:"PK" json:"-"`
+	SK        string    `dynamodbav:"SK" json:"-"`
+	ID        string    `dynamodbav:"id" json:"id"`
+	Name      string    `dynamodbav:"name" json:"name"`
+	Reward    Money     `dynamodbav:"reward_cents" json:"reward"`
+	CreatedAt time.Time `dynamodbav:"created_at" json:"created_at"`
+	UpdatedAt time.Time `dynamodbav:"updated_at" json:"updated_at"`
+}
+
+// CreateChoreRequest is the JSON body for POST /api/chores.
+type CreateChoreRequest struct {
+	Name   string `json:"name"`
+	Reward Money  `json:"reward"`
+}
+
+// UpdateChoreRequest is the JSON body for PUT /api/chores/{id}. A nil
+// field means "do not change"; at least one must be present.
+type UpdateChoreRequest struct {
+	Name   *string `json:"name,omitempty"`
+	Reward *Money  `json:"reward,omitempty"`
+}
+
+// ChoresResponse is returned by GET /api/chores, by name.
+type ChoresResponse struct {
+	Chores []Chore `json:"chores"`
+}
+
+// ChoreCompletion is a report that a chore was done, awaiting an admin's
+// approval (PK="CHOREDONE", SK="DONE#<id>"). It keeps the chore's name and
+// reward as they were when it was submitted, so editing or deleting the
+// chore later does not change what the approval credits.
+type ChoreCompletion struct {
+	PK      string `dynamodbav:"PK" json:"-"`
+	SK      string `dynamodbav:"SK" json:"-"`
+	ID      string `dynamodbav:"id" json:"id"`
+	ChoreID string `dynamodbav:"chore_id" json:"chore_id"`
+	Name    string `dynamodbav:"name" json:"name"`
+	Reward  Money  `dynamodbav:"reward_cents" json:"reward"`
+	// Month is the month the chore was done in, which the reward is
+	// credited to.
+	Month       string    `dynamodbav:"month" json:"month"`
+	SubmittedAt time.Time `dynamodbav:"submitted_at" json:"submitted_at"`
+}
+
+// ChoreCompletionsResponse is returned by GET /api/chores/completions,
+// oldest submission first.
+type ChoreCompletionsResponse struct {
+	Completions []ChoreCompletion `json:"completions"`
+}
@@ -0,0 +1,11 @@
+package model
+
+// ExpenseRangeResponse is returned by GET /api/expenses. Expenses are newest
+// first and span months, so each carries its Month. NextCursor is empty when
+// there are no more expenses in the range.
+type ExpenseRangeResponse struct {
+	From       string        `json:"from"`
+	To         string        `json:"to"`
+	Expenses   []ExpenseItem `json:"expenses"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,48 @@
+package model
+
+// ForecastResponse is returned by GET /api/forecast: a projection of the
+// month's ending balance as of AsOf. For a month that has ended it is the
+// actual ending balance with nothing left to project.
+type ForecastResponse struct {
+	Month       string `json:"month"`
+	AsOf        string `json:"as_of"` // YYYY-MM-DD, the last day counted as spent
+	DaysElapsed int    `json:"days_elapsed"`
+	DaysInMonth int    `json:"days_in_month"`
+	// CurrentBalance is the month's ending balance now: every expense
+	// booked so far, scheduled ones included, is already taken out.
+	CurrentBalance Money `json:"current_balance"`
+	// SpentSoFar is the month's discretionary spending so far: its
+	// expenses less recurring and instalment ones.
+	SpentSoFar Money `json:"spent_so_far"`
+	// RateProjection is the discretionary spending still to come at the
+	// month's rate so far; HistoricalProjection what the previous months
+	// spent over the same remaining fraction of the month, on average and
+	// scaled to this month's days left (null without history).
+	// ProjectedSpending is their mean, or RateProjection alone.
+	RateProjection       Money  `json:"rate_projection"`
+	HistoricalProjection *Money `json:"historical_projection"`
+	ProjectedSpending    Money  `json:"projected_spending"`
+	// UpcomingRecurring lists the recurring expenses due after AsOf that
+	// are not booked yet.
+	UpcomingRecurring []ForecastItem `json:"upcoming_recurring"`
+	// UpcomingInstalments lists the instalments dated after AsOf. They are
+	// booked with their plan, so CurrentBalance already excludes them; the
+	// projection takes them out on their days instead.
+	UpcomingInstalments []ForecastItem `json:"upcoming_instalments"`
+	// ProjectedEnding is CurrentBalance less the projected spending and the
+	// upcoming recurring expenses. Under hard-stop (HardStop), spending
+	// stops at zero, so it is never negative there.
+	ProjectedEnding Money `json:"projected_ending"`
+	HardStop        bool  `json:"hard_stop"`
+	// RunsOutOn is the day the projected balance first drops below zero,
+	// from which a hard-stop instance refuses expenses and any other goes
+	// negative; omitted when it lasts the month.
+	RunsOutOn string `json:"runs_out_on,omitempty"`
+}
+
+// ForecastItem is one known expense still to come in a forecast.
+type ForecastItem struct {
+	Date        string `json:"date"` // YYYY-MM-DD
+	Description string `json:"description"`
+	Amount      Money  `json:"amount"`
+}
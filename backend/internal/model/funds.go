@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// FundEntry is one itemized funds credit to a month (PK="MONTH#<m>",
+// SK="FUND#<unixnano>#<id>"), written in the same transaction that adds its
+// amount to the month's allowance_added. A withdrawal is itemized too, as
+// an entry of Kind FundKindWithdrawal holding the negative amount taken
+// back. Only the monthly allowance itself is not, so the entries sum to
+// allowance_added less the allowance the month was opened with.
+//
+// A month's interest credit is a FundEntry too, with Kind FundKindInterest
+// and the fixed SK "FUND#0#interest"; it may hold zero when the carried
+// balance earned nothing. A chore's reward is a FundEntry with Kind
+// FundKindEarning, and also counts towards the month's earnings_added.
+type FundEntry struct {
+	PK          string    `dynamodbav:"PK" json:"-"`
+	SK          string    `dynamodbav:"SK" json:"id"`
+	Amount      Money     `dynamodbav:"amount_cents" json:"amount"`
+	Description string    `dynamodbav:"description,omitempty" json:"description,omitempty"`
+	Kind        string    `dynamodbav:"kind,omitempty" json:"kind,omitempty"`
+	CreatedAt   time.Time `dynamodbav:"created_at" json:"created_at"`
+}
+
+// Kinds of FundEntry. A top-up has none.
+const (
+	// FundKindInterest marks the FundEntry that holds a month's interest.
+	FundKindInterest = "interest"
+	// FundKindEarning marks the reward of an approved chore.
+	FundKindEarning = "earning"
+	// FundKindWithdrawal marks money taken back out of the month; its
+	// Amount is negative.
+	FundKindWithdrawal = "withdrawal"
+)
+
+// UpdateFundEntryRequest is the JSON body for editing a funds credit. A nil
+// field means "do not change"; at least one must be present.
+type UpdateFundEntryRequest struct {
+	Amount      *Money  `json:"amount,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// WithdrawFundsRequest is the JSON body for taking funds back out of a
+// month. Amount must be a positive value.
+type WithdrawFundsRequest struct {
+	Amount Money `json:"amount"`
+}
@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+// Goal is a savings target (PK="GOALS", SK="GOAL#<id>"). Nothing is set
+// aside for a goal: its progress is computed on read by sharing the total
+// balance out across the goals in priority order.
+type Goal struct {
+	PK     string `dynamodbav:"PK" json:"-"`
+	SK     string `dynamodbav:"SK" json:"-"`
+	ID     string `dynamodbav:"id" json:"id"`
+	Name   string `dynamodbav:"name" json:"name"`
+	Target Money  `dynamodbav:"target_cents" json:"target"`
+	// Deadline is an optional "YYYY-MM" month the goal should be reached by.
+	Deadline string `dynamodbav:"deadline,omitempty" json:"deadline,omitempty"`
+	// Priority orders goals when they compete for the same balance: 1 is
+	// funded first. 0 (unset) ranks after every prioritized goal.
+	Priority  int       `dynamodbav:"priority" json:"priority"`
+	CreatedAt time.Time `dynamodbav:"created_at" json:"created_at"`
+	UpdatedAt time.Time `dynamodbav:"updated_at" json:"updated_at"`
+}
+
+// CreateGoalRequest is the JSON body for POST /api/goals.
+type CreateGoalRequest struct {
+	Name     string `json:"name"`
+	Target   Money  `json:"target"`
+	Deadline string `json:"deadline,omitempty"`
+	Priority int    `json:"priority,omitempty"`
+}
+
+// UpdateGoalRequest is the JSON body for PUT /api/goals/{id}. A nil field
+// means "do not change"; an empty Deadline clears it.
+type UpdateGoalRequest struct {
+	Name     *string `json:"name,omitempty"`
+	Target   *Money  `json:"target,omitempty"`
+	Deadline *string `json:"deadline,omitempty"`
+	Priority *int    `json:"priority,omitempty"`
+}
+
+// GoalProgress is a goal with its computed standing against the balance.
+type GoalProgress struct {
+	Goal
+	// Saved is the part of the total balance this goal gets once every
+	// goal funded before it has taken its share; Remaining is the rest of
+	// the target.
+	Saved     Money `json:"saved"`
+	Remaining Money `json:"remaining"`
+	// Percent is Saved as a whole percentage of Target (0-100).
+	Percent  int  `json:"percent"`
+	Complete bool `json:"complete"`
+	// ProjectedMonth is when the goal is expected to be reached at the
+	// average monthly saving. Omitted once complete, and when nothing is
+	// being saved on average (the goal would never be reached).
+	ProjectedMonth string `json:"projected_month,omitempty"`
+	// OnTrack is set only for a goal with a deadline: complete, or
+	// projected to complete by the deadline month.
+	OnTrack *bool `json:"on_track,omitempty"`
+}
+
+// GoalsResponse is returned by GET /api/goals. Goals are in funding order.
+type GoalsResponse struct {
+	Goals        []GoalProgress `json:"goals"`
+	TotalBalance Money          `json:"total_balance"`
+	// AverageMonthlySaved is the mean monthly_saved of the recent completed
+	// months the projections are based on; 0 when there are none.
+	AverageMonthlySaved Money `json:"average_monthly_saved"`
+}
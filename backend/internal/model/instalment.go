@@ -0,0 +1,63 @@
+package model
+
+import "time"
+
+// InstalmentPlan is a purchase spread over consecutive months
+// (PK="INSTALMENT", SK="INST#<id>"). Each instalment is an ordinary EXP#
+// row in its own month, tagged with the plan id; the plan records where
+// each one is, so an edit or delete can rewrite the unpaid ones in a
+// single transaction.
+type InstalmentPlan struct {
+	PK          string       `dynamodbav:"PK" json:"-"`
+	SK          string       `dynamodbav:"SK" json:"-"`
+	ID          string       `dynamodbav:"id" json:"id"`
+	Total       Money        `dynamodbav:"total_cents" json:"amount"`
+	Description string       `dynamodbav:"description" json:"description"`
+	Category    string       `dynamodbav:"category,omitempty" json:"category,omitempty"`
+	Instalments []Instalment `dynamodbav:"instalments" json:"instalments"`
+	// Version is the plan's optimistic lock: every rewrite is conditioned
+	// on the version it read and bumps it.
+	Version   int       `dynamodbav:"version" json:"-"`
+	CreatedAt time.Time `dynamodbav:"created_at" json:"created_at"`
+	UpdatedAt time.Time `dynamodbav:"updated_at" json:"updated_at"`
+}
+
+// Instalment is one month's share of a plan. ExpenseID is the SK of its
+// EXP# row in Month.
+type Instalment struct {
+	Number    int    `dynamodbav:"number" json:"number"`
+	Month     string `dynamodbav:"month" json:"month"`
+	ExpenseID string `dynamodbav:"expense_id" json:"expense_id"`
+	Amount    Money  `dynamodbav:"amount_cents" json:"amount"`
+	// Paid is computed on read, never stored: an instalment is paid once
+	// its month has begun. Only unpaid instalments follow plan edits.
+	Paid bool `dynamodbav:"-" json:"paid"`
+}
+
+// CreateInstalmentRequest is the JSON body for POST /api/instalments.
+// Amount is the purchase total, split as evenly as cents allow over Count
+// months (any odd cents go to the earliest instalments). Date is the first
+// instalment's "YYYY-MM-DD" date and defaults to today; later instalments
+// fall on the same day of each following month.
+type CreateInstalmentRequest struct {
+	Amount      Money  `json:"amount"`
+	Description string `json:"description"`
+	Category    string `json:"category,omitempty"`
+	Count       int    `json:"count"`
+	Date        string `json:"date,omitempty"`
+}
+
+// UpdateInstalmentRequest is the JSON body for PUT /api/instalments/{id}.
+// A nil field means "do not change". A new Amount is the new purchase
+// total: what the paid instalments already charged stays, and the rest is
+// re-split over the unpaid ones.
+type UpdateInstalmentRequest struct {
+	Amount      *Money  `json:"amount,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Category    *string `json:"category,omitempty"`
+}
+
+// InstalmentListResponse is returned by GET /api/instalments.
+type InstalmentListResponse struct {
+	Instalments []InstalmentPlan `json:"instalments"`
+}
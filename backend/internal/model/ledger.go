@@ -0,0 +1,47 @@
+package model
+
+// Ledger check fields, the Field of a LedgerIssue. The month fields name the
+// canonical summary row; the monthlist ones its MONTHLIST mirror.
+const (
+	LedgerTotalExpenses   = "total_expenses"
+	LedgerStartingBalance = "starting_balance"
+	LedgerEndingBalance   = "ending_balance"
+	LedgerBalance         = "balance"
+	// LedgerEarnings is a month whose earnings_added is not the sum of its
+	// chore rewards' funds entries.
+	LedgerEarnings = "earnings_added"
+	// LedgerFunds is a month whose funds entries do not sum to
+	// allowance_added less its base allowance; Stored is their sum.
+	LedgerFunds = "funds"
+	// LedgerEarningsOverAllowance is a month whose chore earnings exceed
+	// its allowance_added: money they earned was taken back.
+	LedgerEarningsOverAllowance = "earnings_added.over_allowance"
+	// LedgerMirror is a mirror whose money fields disagree with the
+	// canonical row; Stored and Expected are the mirror's and the row's
+	// ending balances.
+	LedgerMirror        = "monthlist"
+	LedgerMirrorMissing = "monthlist.missing"
+	LedgerMirrorOrphan  = "monthlist.orphan"
+)
+
+// LedgerIssue is one discrepancy the ledger check found: the value a row
+// holds and the value the carry chain says it should. Month is empty for
+// the global balance.
+type LedgerIssue struct {
+	Month    string `json:"month,omitempty"`
+	Field    string `json:"field"`
+	Stored   Money  `json:"stored"`
+	Expected Money  `json:"expected"`
+	// Manual marks an issue the repair leaves alone: it would mean
+	// rewriting allowance_added or a funds entry, the records of money
+	// granted, which nothing derives.
+	Manual bool `json:"manual,omitempty"`
+}
+
+// LedgerReport is the result of a ledger check. Repaired is set when the
+// issues were also fixed.
+type LedgerReport struct {
+	Months   int           `json:"months"`
+	Issues   []LedgerIssue `json:"issues"`
+	Repaired bool          `json:"repaired"`
+}
@@ -0,0 +1,171 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Money is an amount in integer cents. Every ledger figure — balances,
+// allowances, expense amounts, budgets — is a Money, so month arithmetic is
+// exact and a DynamoDB condition compares integers, where float64 dollars
+// needed roundCents at every boundary and a carried -522.16 + 500 once
+// reached the table as -22.159999999999968.
+//
+// On the wire Money is still a decimal dollar figure (12.5 in, 12.5 out),
+// so the JSON API is unchanged. In DynamoDB it is an integer Number under a
+// *_cents attribute; rows written before the switch carry the old float
+// attribute instead and are converted on read (see repository/money.go).
+type Money int64
+
+// ErrInvalidMoney is returned when a JSON amount is not a finite decimal
+// number within range.
+var ErrInvalidMoney = errors.New("invalid money amount")
+
+// maxMoneyDigits bounds the integer part accepted from JSON. Far above any
+// real figure (inputs are capped at 99999.99) yet well inside int64 cents.
+const maxMoneyDigits = 15
+
+// Dollars converts a float dollar figure (configuration, test fixtures) to
+// Money, rounding half away from zero to the nearest cent.
+func Dollars(v float64) Money {
+	return Money(math.Round(v * 100))
+}
+
+// Float returns the amount in dollars as a float64, for display-only
+// arithmetic such as averages. Never feed it back into the ledger.
+func (m Money) Float() float64 {
+	return float64(m) / 100
+}
+
+// Abs returns the magnitude of m.
+func (m Money) Abs() Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
+
+// String renders m as a plain decimal with trailing fractional zeros
+// dropped: 1250 → "12.5", 1200 → "12", -7 → "-0.07". This is also the JSON
+// form, matching what float64 dollars used to serialize as.
+func (m Money) String() string {
+	sign := ""
+	v := int64(m)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	whole, cents := v/100, v%100
+	switch {
+	case cents == 0:
+		return fmt.Sprintf("%s%d", sign, whole)
+	case cents%10 == 0:
+		return fmt.Sprintf("%s%d.%d", sign, whole, cents/10)
+	default:
+		return fmt.Sprintf("%s%d.%02d", sign, whole, cents)
+	}
+}
+
+// ParseMoney parses a decimal dollar string exactly — no float64 in between
+// — rounding half away from zero past the second decimal place. Exponent
+// forms ("1e2"), which JSON permits, go through float64 and are rounded
+// the same way.
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalidMoney
+	}
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) || math.Abs(f) >= 1e15 {
+			return 0, ErrInvalidMoney
+		}
+		return Dollars(f), nil
+	}
+
+	neg := false
+	switch s[0] {
+	case '-':
+		neg = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" || len(whole) > maxMoneyDigits {
+		return 0, ErrInvalidMoney
+	}
+	for _, part := range []string{whole, frac} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return 0, ErrInvalidMoney
+			}
+		}
+	}
+
+	var cents int64
+	for _, c := range whole {
+		cents = cents*10 + int64(c-'0')
+	}
+	for i := 0; i < 2; i++ {
+		cents *= 10
+		if i < len(frac) {
+			cents += int64(frac[i] - '0')
+		}
+	}
+	if len(frac) > 2 && frac[2] >= '5' {
+		cents++
+	}
+	if neg {
+		cents = -cents
+	}
+	return Money(cents), nil
+}
+
+// MarshalJSON writes m as a JSON number in dollars.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON reads a JSON number in dollars. JSON null leaves m
+// unchanged, as for any other Go value.
+func (m *Money) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		return nil
+	}
+	v, err := ParseMoney(s)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidMoney, s)
+	}
+	*m = v
+	return nil
+}
+
+// MarshalDynamoDBAttributeValue stores m as an integer Number of cents.
+func (m Money) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return &types.AttributeValueMemberN{Value: strconv.FormatInt(int64(m), 10)}, nil
+}
+
+// UnmarshalDynamoDBAttributeValue reads an integer Number of cents.
+func (m *Money) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberN:
+		cents, err := strconv.ParseInt(v.Value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: %q is not whole cents", ErrInvalidMoney, v.Value)
+		}
+		*m = Money(cents)
+		return nil
+	case *types.AttributeValueMemberNULL:
+		*m = 0
+		return nil
+	default:
+		return fmt.Errorf("%w: unexpected attribute type %T", ErrInvalidMoney, av)
+	}
+}
@@ -0,0 +1,118 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestParseMoney(t *testing.T) {
+	cases := []struct {
+		in   string
+		want Money
+	}{
+		{"12.5", 1250},
+		{"12", 1200},
+		{"0.07", 7},
+		{"-22.16", -2216},
+		{"12.349", 1235},             // half-up past the cent
+		{"12.344", 1234},             // rounds down below half
+		{"-0.005", -1},               // half away from zero
+		{"12.349999999999994", 1235}, // float dust from a legacy row
+		{"-22.159999999999968", -2216},
+		{"1e2", 10000},
+		{"99999.99", 9999999},
+	}
+	for _, tc := range cases {
+		got, err := ParseMoney(tc.in)
+		if err != nil || got != tc.want {
+			t.Errorf("ParseMoney(%q) = (%d, %v), want %d", tc.in, got, err, tc.want)
+		}
+	}
+
+	for _, bad := range []string{"", "-", ".", "1.2.3", "abc", "1,5", "1e400", "NaN", "1234567890123456"} {
+		if _, err := ParseMoney(bad); !errors.Is(err, ErrInvalidMoney) {
+			t.Errorf("ParseMoney(%q) err = %v, want ErrInvalidMoney", bad, err)
+		}
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	cases := map[Money]string{
+		0:       "0",
+		1250:    "12.5",
+		1200:    "12",
+		1234:    "12.34",
+		-7:      "-0.07",
+		-52216:  "-522.16",
+		9999999: "99999.99",
+	}
+	for m, want := range cases {
+		if got := m.String(); got != want {
+			t.Errorf("Money(%d).String() = %q, want %q", int64(m), got, want)
+		}
+	}
+}
+
+// The JSON API carried float64 dollars before Money existed; the wire form
+// must not change for existing clients.
+func TestMoneyJSON_IsDecimalDollars(t *testing.T) {
+	var req AddExpenseRequest
+	if err := json.Unmarshal([]byte(`{"amount":12.349,"description":"x"}`), &req); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if req.Amount != 1235 {
+		t.Errorf("Amount = %d cents, want 1235", int64(req.Amount))
+	}
+
+	b, err := json.Marshal(MonthSummary{StartingBalance: -52216, EndingBalance: 1250})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		t.Fatalf("Unmarshal raw: %v", err)
+	}
+	if got := string(raw["starting_balance"]); got != "-522.16" {
+		t.Errorf("starting_balance = %s, want -522.16", got)
+	}
+	if got := string(raw["ending_balance"]); got != "12.5" {
+		t.Errorf("ending_balance = %s, want 12.5", got)
+	}
+
+	if err := json.Unmarshal([]byte(`{"amount":"12"}`), &req); err == nil {
+		t.Error("a quoted amount decoded, want an error as with float64")
+	}
+}
+
+func TestMoneyDynamoDB_IsIntegerCents(t *testing.T) {
+	item, err := attributevalue.MarshalMap(Expense{SK: "EXP#1#a", Amount: 1235})
+	if err != nil {
+		t.Fatalf("MarshalMap: %v", err)
+	}
+	n, ok := item["amount_cents"].(*types.AttributeValueMemberN)
+	if !ok || n.Value != "1235" {
+		t.Fatalf("amount_cents = %#v, want N 1235", item["amount_cents"])
+	}
+	if _, ok := item["amount"]; ok {
+		t.Error("legacy amount attribute written")
+	}
+
+	var back Expense
+	if err := attributevalue.UnmarshalMap(item, &back); err != nil {
+		t.Fatalf("UnmarshalMap: %v", err)
+	}
+	if back.Amount != 1235 {
+		t.Errorf("round-trip Amount = %d, want 1235", int64(back.Amount))
+	}
+
+	// A fractional Number is a float row that skipped the legacy upgrade;
+	// decoding it as cents would be off by 100x, so it must fail loudly.
+	item["amount_cents"] = &types.AttributeValueMemberN{Value: "12.35"}
+	if err := attributevalue.UnmarshalMap(item, &back); !errors.Is(err, ErrInvalidMoney) {
+		t.Errorf("fractional cents err = %v, want ErrInvalidMoney", err)
+	}
+}
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// PendingExpense is an expense a member submitted, awaiting an admin's
+// approval (PK="PENDING", SK="PEND#<expense id>"). It touches no balance
+// until approved, when it is booked under ID through the same checks as
+// any add; a rejection deletes it and journals the reason.
+type PendingExpense struct {
+	PK string `dynamodbav:"PK" json:"-"`
+	SK string `dynamodbav:"SK" json:"-"`
+	// ID is the SK the expense will be booked under.
+	ID          string    `dynamodbav:"id" json:"id"`
+	Month       string    `dynamodbav:"month" json:"month"`
+	Amount      Money     `dynamodbav:"amount_cents" json:"amount"`
+	Description string    `dynamodbav:"description" json:"description"`
+	Category    string    `dynamodbav:"category,omitempty" json:"category,omitempty"`
+	CreatedAt   time.Time `dynamodbav:"created_at" json:"created_at"`
+	SubmittedAt time.Time `dynamodbav:"submitted_at" json:"submitted_at"`
+}
+
+// PendingResponse is returned by GET /api/pending, oldest submission
+// first.
+type PendingResponse struct {
+	Expenses []PendingExpense `json:"expenses"`
+}
+
+// SubmitExpenseResponse is returned by POST /api/expense for a member
+// session: the expense is pending, not booked.
+type SubmitExpenseResponse struct {
+	Success bool            `json:"success"`
+	Pending *PendingExpense `json:"pending"`
+}
+
+// RejectExpenseRequest is the JSON body for
+// POST /api/pending/{id}/reject.
+type RejectExpenseRequest struct {
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,76 @@
+package model
+
+import "time"
+
+// RecurringExpense is a schedule that books the same expense every month
+// on a fixed day (PK="RECURRING", SK="RECUR#<id>"). Each occurrence becomes
+// an ordinary EXP# row whose SK is derived from the due date and the
+// schedule id, so booking the same occurrence twice is refused by the
+// expense put's attribute_not_exists condition.
+type RecurringExpense struct {
+	PK          string `dynamodbav:"PK" json:"-"`
+	SK          string `dynamodbav:"SK" json:"-"`
+	ID          string `dynamodbav:"id" json:"id"`
+	Amount      Money  `dynamodbav:"amount_cents" json:"amount"`
+	Description string `dynamodbav:"description" json:"description"`
+	Category    string `dynamodbav:"category,omitempty" json:"category,omitempty"`
+	// DayOfMonth is 1-31; in a shorter month the occurrence falls on the
+	// month's last day.
+	DayOfMonth int `dynamodbav:"day_of_month" json:"day_of_month"`
+	// StartMonth is the first month with an occurrence; EndMonth, when set,
+	// is the last (inclusive).
+	StartMonth string `dynamodbav:"start_month" json:"start_month"`
+	EndMonth   string `dynamodbav:"end_month,omitempty" json:"end_month,omitempty"`
+	// LastBookedMonth is the newest month whose occurrence has been booked.
+	// The run resumes after it; it is a progress marker, not the
+	// idempotency guarantee (the deterministic SK is).
+	LastBookedMonth string    `dynamodbav:"last_booked_month,omitempty" json:"last_booked_month,omitempty"`
+	CreatedAt       time.Time `dynamodbav:"created_at" json:"created_at"`
+	UpdatedAt       time.Time `dynamodbav:"updated_at" json:"updated_at"`
+}
+
+// CreateRecurringRequest is the JSON body for POST /api/recurring.
+// StartMonth defaults to the current month.
+type CreateRecurringRequest struct {
+	Amount      Money  `json:"amount"`
+	Description string `json:"description"`
+	Category    string `json:"category,omitempty"`
+	DayOfMonth  int    `json:"day_of_month"`
+	StartMonth  string `json:"start_month,omitempty"`
+	EndMonth    string `json:"end_month,omitempty"`
+}
+
+// UpdateRecurringRequest is the JSON body for PUT /api/recurring/{id}. A nil
+// field means "do not change"; an empty EndMonth or Category clears it.
+// Changes apply to occurrences not yet booked — booked expenses are
+// ordinary expenses and are edited through the expense API.
+type UpdateRecurringRequest struct {
+	Amount      *Money  `json:"amount,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Category    *string `json:"category,omitempty"`
+	DayOfMonth  *int    `json:"day_of_month,omitempty"`
+	EndMonth    *string `json:"end_month,omitempty"`
+}
+
+// RecurringListResponse is returned by GET /api/recurring.
+type RecurringListResponse struct {
+	Recurring []RecurringExpense `json:"recurring"`
+}
+
+// RecurringRunResponse reports what one pass over the schedules did:
+// the occurrences booked, and the ones that were due but could not be
+// booked (they are retried on the next run).
+type RecurringRunResponse struct {
+	Booked  []RecurringOccurrence `json:"booked"`
+	Skipped []RecurringOccurrence `json:"skipped"`
+}
+
+// RecurringOccurrence is one due occurrence of a schedule. ExpenseID is
+// set when it was booked; Reason when it was skipped.
+type RecurringOccurrence struct {
+	RecurringID string `json:"recurring_id"`
+	Month       string `json:"month"`
+	Amount      Money  `json:"amount"`
+	ExpenseID   string `json:"expense_id,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
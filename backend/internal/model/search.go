@@ -0,0 +1,13 @@
+package model
+
+// SearchResponse is returned by GET /api/search. Each result carries its
+// Month, since the matches span months. Results are grouped by the word
+// that matched and newest first within a word, so for a whole-word query
+// such as "pizza" the first result is the latest one. NextCursor is empty
+// when there are no more results; a page can come back short of the limit
+// (even empty) with a cursor when the search had to stop scanning early.
+type SearchResponse struct {
+	Query      string        `json:"query"`
+	Results    []ExpenseItem `json:"results"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,47 @@
+package model
+
+// StatsResponse is returned by GET /api/stats: spending analytics over the
+// months From..To. Months the ledger has no row for are left out.
+type StatsResponse struct {
+	From   string       `json:"from"`
+	To     string       `json:"to"`
+	Months []StatsMonth `json:"months"` // ascending
+	// AverageSpent and AverageSaved are per month over Months, rounded
+	// down to the cent.
+	AverageSpent Money `json:"average_spent"`
+	AverageSaved Money `json:"average_saved"`
+	// SavingsRate is the percentage of the window's income that was saved,
+	// to one decimal; null when the window had no income.
+	SavingsRate     *float64          `json:"savings_rate"`
+	BiggestExpenses []ExpenseItem     `json:"biggest_expenses"`
+	TopDescriptions []DescriptionStat `json:"top_descriptions"`
+	// ByWeekday (Sunday first) and ByHour total the window's spending by
+	// when each expense is dated, in UTC.
+	ByWeekday []Money `json:"by_weekday"`
+	ByHour    []Money `json:"by_hour"`
+}
+
+// StatsMonth is one month of a StatsResponse. Income is allowance_added,
+// which includes top-ups, interest and earnings, and Saved is income less
+// spending.
+type StatsMonth struct {
+	Month        string `json:"month"`
+	Income       Money  `json:"income"`
+	Spent        Money  `json:"spent"`
+	Saved        Money  `json:"saved"`
+	ExpenseCount int    `json:"expense_count"`
+	// RollingAverageSpent averages Spent over this month and the two
+	// before it, counting only months the ledger has.
+	RollingAverageSpent Money `json:"rolling_average_spent"`
+	// SpentChange is Spent less the previous month's; null when the
+	// previous month has no row.
+	SpentChange *Money `json:"spent_change"`
+}
+
+// DescriptionStat is how often one expense description recurs in a
+// StatsResponse window, matched case-insensitively.
+type DescriptionStat struct {
+	Description string `json:"description"`
+	Count       int    `json:"count"`
+	Total       Money  `json:"total"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// TrashedExpense is a deleted expense kept so it can be restored
+// (PK="TRASH", SK="TRASH#<expense id>"). It carries everything needed to
+// book the expense again under its original id, month and timestamp;
+// attachment blobs stay in the blob store until the entry is purged.
+type TrashedExpense struct {
+	PK string `dynamodbav:"PK" json:"-"`
+	SK string `dynamodbav:"SK" json:"-"`
+	// ID is the expense's original SK, which a restore books it under.
+	ID          string       `dynamodbav:"id" json:"id"`
+	Month       string       `dynamodbav:"month" json:"month"`
+	Amount      Money        `dynamodbav:"amount_cents" json:"amount"`
+	Description string       `dynamodbav:"description" json:"description"`
+	Category    string       `dynamodbav:"category,omitempty" json:"category,omitempty"`
+	CreatedAt   time.Time    `dynamodbav:"created_at" json:"created_at"`
+	Attachments []Attachment `dynamodbav:"attachments,omitempty" json:"attachments,omitempty"`
+	DeletedAt   time.Time    `dynamodbav:"deleted_at" json:"deleted_at"`
+	// ExpiresAt is when the entry stops being restorable. TTL is the
+	// DynamoDB expiry that removes the row: ExpiresAt itself, or a grace
+	// period later when the daily job has attachment blobs to delete first.
+	ExpiresAt time.Time `dynamodbav:"expires_at" json:"expires_at"`
+	TTL       int64     `dynamodbav:"ttl" json:"-"`
+}
+
+// TrashResponse is returned by GET /api/trash, most recently deleted first.
+type TrashResponse struct {
+	Expenses      []TrashedExpense `json:"expenses"`
+	RetentionDays int              `json:"retention_days"`
+}
@@ -5,20 +5,50 @@ import (
 	"time"
 )
 
-// Config holds the application configuration (PIN hash)
+// Config holds the application configuration (PIN hash, category budgets)
 type Config struct {
 	PK        string    `dynamodbav:"PK"`
 	SK        string    `dynamodbav:"SK"`
 	PinHash   string    `dynamodbav:"pin_hash"`
 	CreatedAt time.Time `dynamodbav:"created_at"`
 	UpdatedAt time.Time `dynamodbav:"updated_at"`
+	// MemberPinHash is the member PIN's hash; empty until an admin sets
+	// one. A session unlocked with it has RoleMember.
+	MemberPinHash string `dynamodbav:"member_pin_hash,omitempty"`
+	// CategoryBudgets caps the monthly spend per expense category, keyed by
+	// the normalized category label. Absent when no budgets are set.
+	CategoryBudgets map[string]Money `dynamodbav:"category_budgets_cents,omitempty"`
+	// EnforceCategoryBudgets makes a hard-stop instance refuse an expense
+	// that would take its category past the cap, instead of only reporting
+	// the overrun. Has no effect when overspending is allowed.
+	EnforceCategoryBudgets bool `dynamodbav:"enforce_category_budgets,omitempty"`
+	// AllowanceSchedule is the dated monthly allowance, ascending by From.
+	// Absent when none is set, in which case every month gets the
+	// deployment's MONTHLY_ALLOWANCE.
+	AllowanceSchedule []AllowanceRate `dynamodbav:"allowance_schedule,omitempty"`
+	// AllowOverspending and CarryOverBalance override the deployment's
+	// ALLOW_OVERSPENDING and CARRY_OVER_BALANCE once set through
+	// PUT /api/settings. Nil means the deployment value.
+	AllowOverspending *bool `dynamodbav:"allow_overspending,omitempty"`
+	CarryOverBalance  *bool `dynamodbav:"carry_over_balance,omitempty"`
+	// DailyLimit and WeeklyLimit cap the spending dated in one UTC day and
+	// one Monday-to-Sunday UTC week. Zero means no limit.
+	DailyLimit  Money `dynamodbav:"daily_limit_cents,omitempty"`
+	WeeklyLimit Money `dynamodbav:"weekly_limit_cents,omitempty"`
+}
+
+// AllowanceRate is one step of the allowance schedule: Amount is granted to
+// every month from From (YYYY-MM) until the next step takes over.
+type AllowanceRate struct {
+	From   string `dynamodbav:"from" json:"from"`
+	Amount Money  `dynamodbav:"amount_cents" json:"amount"`
 }
 
 // Balance holds the total accumulated balance
 type Balance struct {
 	PK           string    `dynamodbav:"PK"`
 	SK           string    `dynamodbav:"SK"`
-	TotalBalance float64   `dynamodbav:"total_balance"`
+	TotalBalance Money     `dynamodbav:"total_balance_cents"`
 	UpdatedAt    time.Time `dynamodbav:"updated_at"`
 }
 
@@ -27,23 +57,54 @@ type MonthSummary struct {
 	PK              string    `dynamodbav:"PK" json:"-"`
 	SK              string    `dynamodbav:"SK" json:"-"`
 	Month           string    `dynamodbav:"month" json:"month"`
-	StartingBalance float64   `dynamodbav:"starting_balance" json:"starting_balance"`
-	AllowanceAdded  float64   `dynamodbav:"allowance_added" json:"allowance_added"`
-	TotalExpenses   float64   `dynamodbav:"total_expenses" json:"total_expenses"`
-	EndingBalance   float64   `dynamodbav:"ending_balance" json:"ending_balance"`
+	StartingBalance Money     `dynamodbav:"starting_balance_cents" json:"starting_balance"`
+	AllowanceAdded  Money     `dynamodbav:"allowance_added_cents" json:"allowance_added"`
+	TotalExpenses   Money     `dynamodbav:"total_expenses_cents" json:"total_expenses"`
+	EndingBalance   Money     `dynamodbav:"ending_balance_cents" json:"ending_balance"`
 	CreatedAt       time.Time `dynamodbav:"created_at" json:"created_at"`
 	UpdatedAt       time.Time `dynamodbav:"updated_at" json:"updated_at"`
+	// CategoryTotals is the running spend per expense category, maintained as
+	// a delta inside the same transactions that move total_expenses. Only
+	// categorized spend is counted: legacy and uncategorized expenses are
+	// the difference between TotalExpenses and the sum of this map. Absent
+	// on rows that have never seen a categorized expense.
+	CategoryTotals map[string]Money `dynamodbav:"category_totals_cents,omitempty" json:"category_totals,omitempty"`
+	// EarningsAdded is the part of AllowanceAdded the month's approved
+	// chores earned, kept beside it so the base allowance and top-ups can
+	// be told from income. Absent on rows with no earnings.
+	EarningsAdded Money `dynamodbav:"earnings_added_cents,omitempty" json:"earnings_added"`
+	// BaseAllowance is the part of AllowanceAdded granted as the monthly
+	// allowance, the one credit with no FUND# row: the month's funds
+	// entries sum to AllowanceAdded less it. Nil on months opened before
+	// it was recorded.
+	BaseAllowance *Money `dynamodbav:"base_allowance_cents,omitempty" json:"-"`
 }
 
 // Expense represents a single expense entry
 type Expense struct {
 	PK          string    `dynamodbav:"PK"`
 	SK          string    `dynamodbav:"SK"`
-	Amount      float64   `dynamodbav:"amount"`
+	Amount      Money     `dynamodbav:"amount_cents"`
 	Description string    `dynamodbav:"description"`
 	CreatedAt   time.Time `dynamodbav:"created_at"`
+	// Category is the normalized category label; empty means uncategorized
+	// and is left off the row entirely, so legacy rows read the same way.
+	Category string `dynamodbav:"category,omitempty"`
+	// InstalmentID names the instalment plan the row belongs to; empty for
+	// an ordinary expense.
+	InstalmentID string `dynamodbav:"instalment_id,omitempty"`
+	// Attachments lists the files attached to the expense, oldest first.
+	Attachments []Attachment `dynamodbav:"attachments,omitempty"`
 }
 
+// Session roles. The admin PIN (and biometric unlock, which only an admin
+// can enrol) grants RoleAdmin; the member PIN grants RoleMember, which may
+// view everything but only add expenses.
+const (
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+)
+
 // Session represents an authenticated session
 type Session struct {
 	PK        string `dynamodbav:"PK"`
@@ -51,6 +112,9 @@ type Session struct {
 	Token     string `dynamodbav:"token"`
 	CreatedAt int64  `dynamodbav:"created_at"`
 	TTL       int64  `dynamodbav:"ttl"` // DynamoDB TTL for auto-expiry
+	// Role is RoleAdmin or RoleMember. Empty on a session minted before
+	// roles existed, which the single PIN of the time made an admin one.
+	Role string `dynamodbav:"role,omitempty"`
 }
 
 // RateLimitEntry tracks failed PIN attempts
@@ -132,6 +196,9 @@ type VerifyPinResponse struct {
 	// number of seconds until the per-IP window's TTL expires and the
 	// caller may try again. Omitted when not rate-limited.
 	RetryAfterSeconds *int64 `json:"retry_after_seconds,omitempty"`
+	// Role is the new session's role, so the client can hide what a
+	// member may not do. Set on success only.
+	Role string `json:"role,omitempty"`
 }
 
 type ChangePinRequest struct {
@@ -145,9 +212,9 @@ type ChangePinRequest struct {
 // monthly bucket. Clients aware of their local timezone SHOULD include
 // it in YYYY-MM format.
 type AddExpenseRequest struct {
-	Amount      float64 `json:"amount"`
-	Description string  `json:"description"`
-	Month       string  `json:"month,omitempty"` // optional YYYY-MM; defaults to server UTC current month
+	Amount      Money  `json:"amount"`
+	Description string `json:"description"`
+	Month       string `json:"month,omitempty"` // optional YYYY-MM; defaults to server UTC current month
 	// Date is an optional "YYYY-MM-DD" calendar date for back-dating an
 	// expense. When present it must be a valid date that is not in the
 	// future (UTC, today allowed); the month is derived from it (and must
@@ -155,14 +222,71 @@ type AddExpenseRequest struct {
 	// the current time when Date is today, else 12:00:00 UTC on that date.
 	// Absent → current behavior (timestamp = now, month from Month/UTC).
 	Date string `json:"date,omitempty"`
+	// Category is an optional free-form label ("snacks", "games"). It is
+	// trimmed and lower-cased server-side; empty means uncategorized.
+	Category string `json:"category,omitempty"`
 }
 
 type AddExpenseResponse struct {
 	Success      bool     `json:"success"`
 	Expense      *Expense `json:"expense,omitempty"`
-	MonthBalance float64  `json:"month_balance"`
-	TotalBalance float64  `json:"total_balance"`
+	MonthBalance Money    `json:"month_balance"`
+	TotalBalance Money    `json:"total_balance"`
 	Error        string   `json:"error,omitempty"`
+	// CategoryBudget is where the expense's category stands against its
+	// monthly cap after the expense landed. Absent when the expense has no
+	// category or the category has no budget.
+	CategoryBudget *CategoryBudgetStatus `json:"category_budget,omitempty"`
+}
+
+// CategoryBudgetStatus reports one category's month-to-date spend against
+// its budget. Remaining goes negative once the category is over budget.
+type CategoryBudgetStatus struct {
+	Category   string `json:"category"`
+	Budget     Money  `json:"budget"`
+	Spent      Money  `json:"spent"`
+	Remaining  Money  `json:"remaining"`
+	OverBudget bool   `json:"over_budget"`
+}
+
+// CategoryBudgets is the body of PUT /api/budgets and the response of both
+// budget endpoints. Budgets maps a category label to its monthly cap; the
+// PUT replaces the whole set. Enforce is the hard-stop refusal switch
+// (Config.EnforceCategoryBudgets).
+type CategoryBudgets struct {
+	Budgets map[string]Money `json:"budgets"`
+	Enforce bool             `json:"enforce"`
+}
+
+// AllowanceSchedule is the body of PUT /api/allowance and the response of
+// both allowance endpoints. The PUT replaces the whole schedule. Default is
+// the deployment's MONTHLY_ALLOWANCE, granted to months before the first
+// step (or to all months when the schedule is empty); it is read-only and
+// ignored on a PUT.
+type AllowanceSchedule struct {
+	Default  Money           `json:"default"`
+	Schedule []AllowanceRate `json:"schedule"`
+}
+
+// SpendingLimits is the body of PUT /api/limits and the response of both
+// limit endpoints. Zero turns a limit off.
+type SpendingLimits struct {
+	Daily  Money `json:"daily"`
+	Weekly Money `json:"weekly"`
+}
+
+// Settings is the response of both settings endpoints: the instance's
+// effective overspending and carry-over modes.
+type Settings struct {
+	AllowOverspending bool `json:"allow_overspending"`
+	CarryOverBalance  bool `json:"carry_over_balance"`
+}
+
+// UpdateSettingsRequest is the body of PUT /api/settings. Omitted fields
+// are left as they are.
+type UpdateSettingsRequest struct {
+	AllowOverspending *bool `json:"allow_overspending"`
+	CarryOverBalance  *bool `json:"carry_over_balance"`
 }
 
 // MonthDataResponse is returned when fetching data for a single month.
@@ -172,14 +296,29 @@ type MonthDataResponse struct {
 	Month        string        `json:"month"`
 	Summary      *MonthSummary `json:"summary"`
 	Expenses     []ExpenseItem `json:"expenses"`
-	TotalBalance float64       `json:"total_balance"`
+	TotalBalance Money         `json:"total_balance"`
 	NextCursor   string        `json:"next_cursor,omitempty"` // Base64-encoded pagination cursor; empty when no more pages
+	// Categories is the month's spend broken down by category, largest
+	// first. Spend with no category (including every expense written
+	// before categories existed) is reported as a single "uncategorized"
+	// entry so the rows always add up to the summary's total_expenses.
+	Categories []CategoryTotal `json:"categories"`
+	// Funds lists the month's itemized credits, oldest first. It is not
+	// paginated: a month holds a handful at most.
+	Funds []FundEntry `json:"funds"`
+}
+
+// CategoryTotal is one row of a month's per-category spend breakdown.
+type CategoryTotal struct {
+	Category string `json:"category"`
+	Total    Money  `json:"total"`
 }
 
 type ExpenseItem struct {
 	ID          string    `json:"id"`
-	Amount      float64   `json:"amount"`
+	Amount      Money     `json:"amount"`
 	Description string    `json:"description"`
+	Category    string    `json:"category,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 	// Month is the "YYYY-MM" bucket the expense belongs to. It is populated
 	// on the UpdateExpenseResponse so a client can tell whether a date edit
@@ -187,17 +326,23 @@ type ExpenseItem struct {
 	// Omitted from the month-data list payload, where the month is implied by
 	// the request, so existing list serialization is unaffected.
 	Month string `json:"month,omitempty"`
+	// InstalmentID is set when the expense is one instalment of a plan;
+	// such rows are edited through /api/instalments/{id}.
+	InstalmentID string `json:"instalment_id,omitempty"`
+	// Attachments is the metadata of the expense's attached files; the
+	// bytes are fetched one at a time from the attachments endpoint.
+	Attachments []Attachment `json:"attachments,omitempty"`
 }
 
 type MonthListItem struct {
-	Month        string  `json:"month"`
-	MonthlySaved float64 `json:"monthly_saved"` // ending_balance - starting_balance
+	Month        string `json:"month"`
+	MonthlySaved Money  `json:"monthly_saved"` // ending_balance - starting_balance
 	// TotalExpenses is what was spent in the month. The history menu scales
 	// each row's spend bar against the largest value across the listed months,
 	// so without this field that scale was always zero and the bar could never
 	// render. Added without omitempty so a genuine $0 month still reports 0
 	// rather than being indistinguishable from an older server that omits it.
-	TotalExpenses float64 `json:"total_expenses"`
+	TotalExpenses Money `json:"total_expenses"`
 }
 
 // MonthsResponse is returned when listing all months. Months are sorted in
@@ -209,7 +354,7 @@ type MonthsResponse struct {
 }
 
 type BalanceResponse struct {
-	TotalBalance float64 `json:"total_balance"`
+	TotalBalance Money `json:"total_balance"`
 }
 
 type SetupStatusResponse struct {
@@ -221,18 +366,32 @@ type SetupStatusResponse struct {
 	WebauthnEnrolled bool `json:"webauthn_enrolled"`
 }
 
+// MemberPinRequest is the JSON body for PUT /api/auth/member-pin. AdminPin
+// is the admin PIN, checked like ChangePinRequest's CurrentPin.
+type MemberPinRequest struct {
+	AdminPin string `json:"admin_pin"`
+	Pin      string `json:"pin"`
+}
+
+// MemberPinStatusResponse is returned by GET /api/auth/member-pin.
+type MemberPinStatusResponse struct {
+	IsSet bool `json:"is_set"`
+}
+
 // UpdateExpenseRequest is the JSON body for updating an existing expense.
-// Amount/Description are optional pointers: a nil field means "do not change".
+// Amount/Description/Category are optional pointers: a nil field means "do
+// not change" (an empty Category clears it).
 // Date is an optional "YYYY-MM-DD" calendar date for re-dating the expense,
 // validated exactly like the add path (valid date, not in the future in UTC,
 // today allowed). When present it changes the expense's timestamp (and, when
 // the date's month differs from the path month, moves it to that month). An
-// absent date leaves the timestamp/month unchanged. At least one of the three
+// absent date leaves the timestamp/month unchanged. At least one of the four
 // fields must be present for the request to be valid.
 type UpdateExpenseRequest struct {
-	Amount      *float64 `json:"amount,omitempty"`
-	Description *string  `json:"description,omitempty"`
-	Date        string   `json:"date,omitempty"`
+	Amount      *Money  `json:"amount,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Date        string  `json:"date,omitempty"`
+	Category    *string `json:"category,omitempty"`
 }
 
 // UpdateExpenseResponse is returned after a successful expense update.
@@ -241,8 +400,8 @@ type UpdateExpenseRequest struct {
 type UpdateExpenseResponse struct {
 	Success      bool         `json:"success"`
 	Expense      *ExpenseItem `json:"expense,omitempty"`
-	MonthBalance float64      `json:"month_balance"`
-	TotalBalance float64      `json:"total_balance"`
+	MonthBalance Money        `json:"month_balance"`
+	TotalBalance Money        `json:"total_balance"`
 }
 
 // CreateMonthRequest is the JSON body for creating a new monthly period.
@@ -257,13 +416,15 @@ type CreateMonthRequest struct {
 type CreateMonthResponse struct {
 	Success      bool          `json:"success"`
 	Summary      *MonthSummary `json:"summary"`
-	TotalBalance float64       `json:"total_balance"`
+	TotalBalance Money         `json:"total_balance"`
 }
 
 // AddFundsRequest is the JSON body for adding extra funds (allowance top-up)
-// to an existing month. Amount must be a positive value.
+// to an existing month. Amount must be a positive value; the optional
+// Description says where the money came from.
 type AddFundsRequest struct {
-	Amount float64 `json:"amount"`
+	Amount      Money  `json:"amount"`
+	Description string `json:"description,omitempty"`
 }
 
 // AddFundsResponse is returned after successfully adding funds to a month.
@@ -272,7 +433,9 @@ type AddFundsRequest struct {
 type AddFundsResponse struct {
 	Success      bool          `json:"success"`
 	Summary      *MonthSummary `json:"summary"`
-	TotalBalance float64       `json:"total_balance"`
+	TotalBalance Money         `json:"total_balance"`
+	// Entry is the itemized credit a top-up wrote; absent for a withdrawal.
+	Entry *FundEntry `json:"entry,omitempty"`
 }
 
 type ErrorResponse struct {
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vppillai/passbook/backend/internal/model"
+)
+
+// Account keys. The registry is one partition (PK="ACCOUNTS",
+// SK="ACCT#<id>"). Every ledger partition of an account other than the
+// default one is the default's key behind "ACCT#<id>#": its months are
+// "ACCT#<id>#MONTH#2026-02", its balance "ACCT#<id>#BALANCE", and so on.
+// The default account keeps the unprefixed keys, so a table written before
+// accounts existed is already its default account and needs no migration.
+//
+// The instance-wide rows — CONFIG, sessions, rate limits, WebAuthn, the
+// AUDIT journal and the migration markers — are not scoped.
+const (
+	PKAccounts    = "ACCOUNTS"
+	AccountPrefix = "ACCT#"
+)
+
+// ErrAccountNotFound is returned by UpdateAccount and DeleteAccount when
+// the account's row is gone.
+var ErrAccountNotFound = errors.New("account not found")
+
+// ErrAccountHasBalance is returned by DeleteAccount when the account's
+// balance is not zero, i.e. it has been given money since the caller
+// checked it was empty.
+var ErrAccountHasBalance = errors.New("account has a balance")
+
+type accountCtxKey struct{}
+
+// WithAccount returns a context whose repository calls read and write the
+// ledger of account id. The router sets it from the request's account
+// header; the scheduled run sets it for each account in turn.
+func WithAccount(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, accountCtxKey{}, id)
+}
+
+// AccountFrom returns the account set by WithAccount, or the default one.
+func AccountFrom(ctx context.Context) string {
+	if id, _ := ctx.Value(accountCtxKey{}).(string); id != "" {
+		return id
+	}
+	return model.DefaultAccountID
+}
+
+// AccountPK returns the partition key pk has in the context's account.
+func AccountPK(ctx context.Context, pk string) string {
+	id := AccountFrom(ctx)
+	if id == model.DefaultAccountID {
+		return pk
+	}
+	return AccountPrefix + id + "#" + pk
+}
+
+// splitAccountPK splits a stored partition key into its account and the
+// key it has in that account, the inverse of AccountPK.
+func splitAccountPK(pk string) (account, key string) {
+	if rest, ok := strings.CutPrefix(pk, AccountPrefix); ok {
+		if id, key, ok := strings.Cut(rest, "#"); ok {
+			return id, key
+		}
+	}
+	return model.DefaultAccountID, pk
+}
+
+// MonthOfPK returns the month of a MONTH# partition key in any account.
+func MonthOfPK(pk string) string {
+	_, key := splitAccountPK(pk)
+	return strings.TrimPrefix(key, MonthPrefix)
+}
+
+func accountKey(id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: PKAccounts},
+		"SK": &types.AttributeValueMemberS{Value: AccountPrefix + id},
+	}
+}
+
+// GetAccount fetches one account's row. Returns nil (no error) when absent,
+// which for the default account means it was never renamed.
+func (r *Repository) GetAccount(ctx context.Context, id string) (*model.Account, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       accountKey(id),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+	var account model.Account
+	if err := unmarshalItem(result.Item, &account); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal account: %w", err)
+	}
+	return &account, nil
+}
+
+// ListAccounts returns every account row in id order.
+func (r *Repository) ListAccounts(ctx context.Context) ([]model.Account, error) {
+	var out []model.Account
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(r.tableName),
+			KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :prefix)"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":pk":     &types.AttributeValueMemberS{Value: PKAccounts},
+				":prefix": &types.AttributeValueMemberS{Value: AccountPrefix},
+			},
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list accounts: %w", err)
+		}
+		var page []model.Account
+		if err := unmarshalItems(result.Items, &page); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal accounts: %w", err)
+		}
+		out = append(out, page...)
+		if len(result.LastEvaluatedKey) == 0 {
+			return out, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
+
+// CreateAccount writes a new account row. The attribute_not_exists guard
+// only matters on an id collision.
+func (r *Repository) CreateAccount(ctx context.Context, account *model.Account) error {
+	return r.putAccount(ctx, account, "attribute_not_exists(PK)")
+}
+
+// UpdateAccount replaces an account row; ErrAccountNotFound when it is
+// gone. The default account's first rename creates its row, so its write
+// is unconditional.
+func (r *Repository) UpdateAccount(ctx context.Context, account *model.Account) error {
+	condition := "attribute_exists(PK)"
+	if account.ID == model.DefaultAccountID {
+		condition = ""
+	}
+	err := r.putAccount(ctx, account, condition)
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		return ErrAccountNotFound
+	}
+	return err
+}
+
+func (r *Repository) putAccount(ctx context.Context, account *model.Account, condition string) error {
+	account.PK = PKAccounts
+	account.SK = AccountPrefix + account.ID
+	item, err := attributevalue.MarshalMap(account)
+	if err != nil {
+		return fmt.Errorf("failed to marshal account: %w", err)
+	}
+	input := &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	}
+	if condition != "" {
+		input.ConditionExpression = aws.String(condition)
+	}
+	if _, err := r.client.PutItem(ctx, input); err != nil {
+		return fmt.Errorf("failed to save account: %w", err)
+	}
+	return nil
+}
+
+// DeleteAccount removes an account's row and its BALANCE row in one
+// transaction. The caller checks the account has no months, schedules,
+// goals or plans left; the transaction re-checks the balance is zero, so
+// money granted since that check is never deleted with it
+// (ErrAccountHasBalance). ErrAccountNotFound when the row is already gone.
+func (r *Repository) DeleteAccount(ctx context.Context, id string) error {
+	scoped := WithAccount(ctx, id)
+	_, err := r.transactWrite(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Delete: &types.Delete{
+				TableName:           aws.String(r.tableName),
+				Key:                 accountKey(id),
+				ConditionExpression: aws.String("attribute_exists(PK)"),
+			}},
+			{Delete: &types.Delete{
+				TableName: aws.String(r.tableName),
+				Key: map[string]types.AttributeValue{
+					"PK": &types.AttributeValueMemberS{Value: AccountPK(scoped, PKBalance)},
+					"SK": &types.AttributeValueMemberS{Value: SKBalance},
+				},
+				ConditionExpression:       aws.String("attribute_not_exists(PK) OR total_balance_cents = :zero"),
+				ExpressionAttributeValues: map[string]types.AttributeValue{":zero": moneyValue(0)},
+			}},
+		},
+	})
+	if err != nil {
+		if idx, ok := txConditionFailedIndex(err); ok {
+			if idx == 0 {
+				return ErrAccountNotFound
+			}
+			return ErrAccountHasBalance
+		}
+		return fmt.Errorf("failed to delete account: %w", err)
+	}
+	return nil
+}
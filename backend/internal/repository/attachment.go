@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vppillai/passbook/backend/internal/model"
+)
+
+// AddExpenseAttachment appends an attachment's metadata to an expense row.
+// The append is conditioned on the row still holding exactly count
+// attachments, the number the caller read, so two concurrent uploads cannot
+// both squeeze under the limit; a failed condition (including a row that
+// has gone) is ErrExpenseStateMismatch.
+func (r *Repository) AddExpenseAttachment(ctx context.Context, month, expenseID string, count int, attachment *model.Attachment) error {
+	item, err := attributevalue.Marshal([]model.Attachment{*attachment})
+	if err != nil {
+		return fmt.Errorf("failed to marshal attachment: %w", err)
+	}
+	condition := "attribute_exists(PK) AND size(attachments) = :count"
+	if count == 0 {
+		condition = "attribute_exists(PK) AND (attribute_not_exists(attachments) OR size(attachments) = :count)"
+	}
+	_, err = r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(r.tableName),
+		Key:                 expenseKey(ctx, month, expenseID),
+		UpdateExpression:    aws.String("SET attachments = list_append(if_not_exists(attachments, :empty), :new)"),
+		ConditionExpression: aws.String(condition),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":new":   item,
+			":empty": &types.AttributeValueMemberL{Value: []types.AttributeValue{}},
+			":count": &types.AttributeValueMemberN{Value: strconv.Itoa(count)},
+		},
+	})
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		return ErrExpenseStateMismatch
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add attachment: %w", err)
+	}
+	return nil
+}
+
+// RemoveExpenseAttachment removes the attachment at index from an expense
+// row, conditioned on that slot still holding attachmentID; otherwise
+// ErrExpenseStateMismatch.
+func (r *Repository) RemoveExpenseAttachment(ctx context.Context, month, expenseID string, index int, attachmentID string) error {
+	_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(r.tableName),
+		Key:                 expenseKey(ctx, month, expenseID),
+		UpdateExpression:    aws.String(fmt.Sprintf("REMOVE attachments[%d]", index)),
+		ConditionExpression: aws.String(fmt.Sprintf("attachments[%d].#id = :id", index)),
+		ExpressionAttributeNames: map[string]string{
+			"#id": "id",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":id": &types.AttributeValueMemberS{Value: attachmentID},
+		},
+	})
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		return ErrExpenseStateMismatch
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove attachment: %w", err)
+	}
+	return nil
+}
+
+func expenseKey(ctx context.Context, month, expenseID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, MonthPrefix+month)},
+		"SK": &types.AttributeValueMemberS{Value: expenseID},
+	}
+}
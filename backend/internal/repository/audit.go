@@ -0,0 +1,263 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+	"github.com/vppillai/passbook/backend/internal/model"
+)
+
+// Audit journal keys. Every entry shares one partition (PK="AUDIT",
+// SK="AUDIT#<unixnano>#<id>"), so the journal reads newest first with one
+// descending Query. Entries are only ever put, never updated or deleted.
+const (
+	PKAudit     = "AUDIT"
+	AuditPrefix = "AUDIT#"
+)
+
+// Actor is who is making a change, as the audit journal records it.
+type Actor struct {
+	SessionID string
+	SourceIP  string
+	Role      string
+}
+
+type actorKey struct{}
+
+// WithActor returns a context that attributes the changes made with it to
+// actor. The router sets it for every authenticated request; a context
+// without one (the scheduled run) journals its changes unattributed.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor set by WithActor, or the zero Actor.
+func ActorFrom(ctx context.Context) Actor {
+	actor, _ := ctx.Value(actorKey{}).(Actor)
+	return actor
+}
+
+// NewAuditEntry stamps an entry for action with its key, the time, the
+// context's actor and its account. The fake repository builds its journal
+// with it too.
+func NewAuditEntry(ctx context.Context, action, month, target string, before, after *model.AuditState) *model.AuditEntry {
+	now := time.Now().UTC()
+	id := fmt.Sprintf("%d#%s", now.UnixNano(), uuid.New().String()[:8])
+	actor := ActorFrom(ctx)
+	entry := &model.AuditEntry{
+		PK:        PKAudit,
+		SK:        AuditPrefix + id,
+		ID:        id,
+		Action:    action,
+		At:        now,
+		SessionID: actor.SessionID,
+		SourceIP:  actor.SourceIP,
+		Role:      actor.Role,
+		Month:     month,
+		Target:    target,
+		Before:    before,
+		After:     after,
+	}
+	if account := AccountFrom(ctx); account != model.DefaultAccountID {
+		entry.Account = account
+	}
+	return entry
+}
+
+// ExpenseAuditState is the journal's view of expense e in month. A zero
+// CreatedAt (an instalment rewrite, which does not carry the date) is
+// left out.
+func ExpenseAuditState(month string, e *model.Expense) *model.AuditState {
+	amount := e.Amount
+	state := &model.AuditState{
+		Month:       month,
+		ExpenseID:   e.SK,
+		Amount:      &amount,
+		Description: e.Description,
+		Category:    e.Category,
+	}
+	if !e.CreatedAt.IsZero() {
+		createdAt := e.CreatedAt
+		state.CreatedAt = &createdAt
+	}
+	return state
+}
+
+// MonthAuditState is the journal's view of a month's balances.
+func MonthAuditState(summary *model.MonthSummary) *model.AuditState {
+	starting, allowance, ending, total := summary.StartingBalance, summary.AllowanceAdded, summary.EndingBalance, summary.TotalExpenses
+	return &model.AuditState{
+		Month:           summary.Month,
+		StartingBalance: &starting,
+		AllowanceAdded:  &allowance,
+		EndingBalance:   &ending,
+		TotalExpenses:   &total,
+	}
+}
+
+// NewInstanceAuditEntry is NewAuditEntry for a change to the instance-wide
+// rows (the PIN and biometrics), which belongs to no account whichever one
+// the request had selected.
+func NewInstanceAuditEntry(ctx context.Context, action, target string) *model.AuditEntry {
+	entry := NewAuditEntry(ctx, action, "", target, nil, nil)
+	entry.Account = ""
+	return entry
+}
+
+// auditPut is the transaction item that journals entry. It is appended
+// after a transaction's other items, so the indexes txConditionFailedIndex
+// reports for them are unchanged, and it carries no condition of its own.
+func (r *Repository) auditPut(entry *model.AuditEntry) (types.TransactWriteItem, error) {
+	item, err := attributevalue.MarshalMap(entry)
+	if err != nil {
+		return types.TransactWriteItem{}, fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+	return types.TransactWriteItem{Put: &types.Put{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	}}, nil
+}
+
+// withAudit appends the journal entry for a change to a transaction's
+// items, after everything else (see auditPut).
+func (r *Repository) withAudit(ctx context.Context, items []types.TransactWriteItem, action, month, target string, before, after *model.AuditState) ([]types.TransactWriteItem, error) {
+	put, err := r.auditPut(NewAuditEntry(ctx, action, month, target, before, after))
+	if err != nil {
+		return nil, err
+	}
+	return append(items, put), nil
+}
+
+// ListAudit pages through the journal newest first.
+func (r *Repository) ListAudit(ctx context.Context, limit int32, cursor map[string]types.AttributeValue) ([]model.AuditEntry, map[string]types.AttributeValue, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :prefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":     &types.AttributeValueMemberS{Value: PKAudit},
+			":prefix": &types.AttributeValueMemberS{Value: AuditPrefix},
+		},
+		ScanIndexForward: aws.Bool(false),
+	}
+	if limit > 0 {
+		input.Limit = aws.Int32(limit)
+	}
+	if cursor != nil {
+		input.ExclusiveStartKey = cursor
+	}
+
+	result, err := r.client.Query(ctx, input)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	var entries []model.AuditEntry
+	if err := unmarshalItems(result.Items, &entries); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal audit entries: %w", err)
+	}
+	return entries, result.LastEvaluatedKey, nil
+}
+
+// AtomicChangePIN writes config's PIN hash to the CONFIG row and journals
+// the change in one transaction. The hash itself is not journaled.
+func (r *Repository) AtomicChangePIN(ctx context.Context, config *model.Config) error {
+	return r.updateConfigJournaled(ctx, config, model.AuditPINChange, ConfigPinHash)
+}
+
+// AtomicSaveMemberPIN writes config's member PIN hash to the CONFIG row,
+// removing it when empty, and journals it as pin.member.set or
+// pin.member.remove.
+func (r *Repository) AtomicSaveMemberPIN(ctx context.Context, config *model.Config) error {
+	action := model.AuditMemberPINSet
+	if config.MemberPinHash == "" {
+		action = model.AuditMemberPINRemove
+	}
+	return r.updateConfigJournaled(ctx, config, action, ConfigMemberPinHash)
+}
+
+// updateConfigJournaled writes only field of config, as UpdateConfig does,
+// and journals action in the same transaction. The callers read the row
+// before revoking sessions, a slow step; writing the whole row back would
+// undo any settings saved in between. A missing row is ErrConfigNotFound.
+func (r *Repository) updateConfigJournaled(ctx context.Context, config *model.Config, action, field string) error {
+	update, err := r.configUpdate(config, field)
+	if err != nil {
+		return err
+	}
+	audit, err := r.auditPut(NewInstanceAuditEntry(ctx, action, ""))
+	if err != nil {
+		return err
+	}
+	_, err = r.transactWrite(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{{Update: update}, audit},
+	})
+	if err != nil {
+		if _, ok := txConditionFailedIndex(err); ok {
+			return ErrConfigNotFound
+		}
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// AtomicEnrolWebAuthnCredential stores a newly registered credential like
+// PutWebAuthnCredential and journals the enrolment in the same transaction.
+func (r *Repository) AtomicEnrolWebAuthnCredential(ctx context.Context, cred *model.WebAuthnCredential) error {
+	audit, err := r.auditPut(NewInstanceAuditEntry(ctx, model.AuditWebAuthnEnrol, cred.CredentialID))
+	if err != nil {
+		return err
+	}
+	return r.putWebAuthnCredential(ctx, cred, audit)
+}
+
+// AtomicDisableWebAuthn removes every stored credential and its
+// WACREDLIST mirror, journaling the change. A single-user instance holds
+// one or two credentials, so this is normally one transaction; a larger
+// set is removed in transaction-sized chunks with the entry in the last.
+func (r *Repository) AtomicDisableWebAuthn(ctx context.Context) error {
+	creds, err := r.ListWebAuthnCredentials(ctx)
+	if err != nil {
+		return err
+	}
+	ids := make([]string, len(creds))
+	var items []types.TransactWriteItem
+	for i, cred := range creds {
+		ids[i] = cred.CredentialID
+		pk := WebAuthnCredentialPrefix + cred.CredentialID
+		items = append(items,
+			types.TransactWriteItem{Delete: &types.Delete{
+				TableName: aws.String(r.tableName),
+				Key: map[string]types.AttributeValue{
+					"PK": &types.AttributeValueMemberS{Value: pk},
+					"SK": &types.AttributeValueMemberS{Value: pk},
+				},
+			}},
+			types.TransactWriteItem{Delete: &types.Delete{
+				TableName: aws.String(r.tableName),
+				Key: map[string]types.AttributeValue{
+					"PK": &types.AttributeValueMemberS{Value: PKWebAuthnCredentialList},
+					"SK": &types.AttributeValueMemberS{Value: cred.CredentialID},
+				},
+			}},
+		)
+	}
+	audit, err := r.auditPut(NewInstanceAuditEntry(ctx, model.AuditWebAuthnDisable, strings.Join(ids, ",")))
+	if err != nil {
+		return err
+	}
+	items = append(items, audit)
+	for len(items) > 0 {
+		n := min(len(items), maxTransactItems)
+		if _, err := r.transactWrite(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items[:n]}); err != nil {
+			return fmt.Errorf("failed to delete webauthn credentials: %w", err)
+		}
+		items = items[n:]
+	}
+	return nil
+}
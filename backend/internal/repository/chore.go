@@ -0,0 +1,284 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vppillai/passbook/backend/internal/model"
+)
+
+// Chore storage keys. Chores share one partition (PK="CHORES",
+// SK="CHORE#<id>") and completions awaiting approval another
+// (PK="CHOREDONE", SK="DONE#<id>"), so listing either is a single Query.
+const (
+	PKChores        = "CHORES"
+	ChorePrefix     = "CHORE#"
+	PKChoreDone     = "CHOREDONE"
+	ChoreDonePrefix = "DONE#"
+)
+
+// ErrChoreNotFound is returned by UpdateChore when the chore does not
+// exist (never created, or deleted concurrently).
+var ErrChoreNotFound = errors.New("chore not found")
+
+// ErrChoreCompletionNotFound is returned by AtomicApproveChoreCompletion
+// when the completion is already gone — approved or rejected by another
+// request.
+var ErrChoreCompletionNotFound = errors.New("chore completion not found")
+
+func choreKey(ctx context.Context, id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKChores)},
+		"SK": &types.AttributeValueMemberS{Value: ChorePrefix + id},
+	}
+}
+
+func choreCompletionKey(ctx context.Context, id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKChoreDone)},
+		"SK": &types.AttributeValueMemberS{Value: ChoreDonePrefix + id},
+	}
+}
+
+// CreateChore writes a new chore row. The attribute_not_exists guard only
+// matters on an id collision.
+func (r *Repository) CreateChore(ctx context.Context, chore *model.Chore) error {
+	return r.putChore(ctx, chore, "attribute_not_exists(PK)")
+}
+
+// UpdateChore replaces an existing chore row; ErrChoreNotFound when the
+// row is gone.
+func (r *Repository) UpdateChore(ctx context.Context, chore *model.Chore) error {
+	err := r.putChore(ctx, chore, "attribute_exists(PK)")
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		return ErrChoreNotFound
+	}
+	return err
+}
+
+func (r *Repository) putChore(ctx context.Context, chore *model.Chore, condition string) error {
+	chore.PK = AccountPK(ctx, PKChores)
+	chore.SK = ChorePrefix + chore.ID
+	item, err := attributevalue.MarshalMap(chore)
+	if err != nil {
+		return fmt.Errorf("failed to marshal chore: %w", err)
+	}
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                item,
+		ConditionExpression: aws.String(condition),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save chore: %w", err)
+	}
+	return nil
+}
+
+// GetChore fetches one chore by id. Returns nil (no error) when absent.
+func (r *Repository) GetChore(ctx context.Context, id string) (*model.Chore, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       choreKey(ctx, id),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chore: %w", err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+	var chore model.Chore
+	if err := unmarshalItem(result.Item, &chore); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chore: %w", err)
+	}
+	return &chore, nil
+}
+
+// ListChores returns every chore in id order.
+func (r *Repository) ListChores(ctx context.Context) ([]model.Chore, error) {
+	var out []model.Chore
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(r.tableName),
+			KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :prefix)"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":pk":     &types.AttributeValueMemberS{Value: AccountPK(ctx, PKChores)},
+				":prefix": &types.AttributeValueMemberS{Value: ChorePrefix},
+			},
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list chores: %w", err)
+		}
+		var page []model.Chore
+		if err := unmarshalItems(result.Items, &page); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal chores: %w", err)
+		}
+		out = append(out, page...)
+		if len(result.LastEvaluatedKey) == 0 {
+			return out, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
+
+// DeleteChore removes a chore and returns it, or nil if it did not exist.
+// Completions already submitted for it keep their own copy of its name and
+// reward.
+func (r *Repository) DeleteChore(ctx context.Context, id string) (*model.Chore, error) {
+	result, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:    aws.String(r.tableName),
+		Key:          choreKey(ctx, id),
+		ReturnValues: types.ReturnValueAllOld,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete chore: %w", err)
+	}
+	if result.Attributes == nil {
+		return nil, nil
+	}
+	var chore model.Chore
+	if err := unmarshalItem(result.Attributes, &chore); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chore: %w", err)
+	}
+	return &chore, nil
+}
+
+// PutChoreCompletion files a submitted completion. Its id is freshly
+// generated, so the put is unconditional.
+func (r *Repository) PutChoreCompletion(ctx context.Context, c *model.ChoreCompletion) error {
+	c.PK = AccountPK(ctx, PKChoreDone)
+	c.SK = ChoreDonePrefix + c.ID
+	item, err := attributevalue.MarshalMap(c)
+	if err != nil {
+		return fmt.Errorf("failed to marshal chore completion: %w", err)
+	}
+	if _, err := r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	}); err != nil {
+		return fmt.Errorf("failed to save chore completion: %w", err)
+	}
+	return nil
+}
+
+// GetChoreCompletion fetches one completion by id. Returns nil (no error)
+// when absent.
+func (r *Repository) GetChoreCompletion(ctx context.Context, id string) (*model.ChoreCompletion, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       choreCompletionKey(ctx, id),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chore completion: %w", err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+	var c model.ChoreCompletion
+	if err := unmarshalItem(result.Item, &c); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chore completion: %w", err)
+	}
+	return &c, nil
+}
+
+// ListChoreCompletions returns every completion awaiting approval in id
+// order.
+func (r *Repository) ListChoreCompletions(ctx context.Context) ([]model.ChoreCompletion, error) {
+	var out []model.ChoreCompletion
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(r.tableName),
+			KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :prefix)"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":pk":     &types.AttributeValueMemberS{Value: AccountPK(ctx, PKChoreDone)},
+				":prefix": &types.AttributeValueMemberS{Value: ChoreDonePrefix},
+			},
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list chore completions: %w", err)
+		}
+		var page []model.ChoreCompletion
+		if err := unmarshalItems(result.Items, &page); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal chore completions: %w", err)
+		}
+		out = append(out, page...)
+		if len(result.LastEvaluatedKey) == 0 {
+			return out, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
+
+// DeleteChoreCompletion removes a completion that was rejected and returns
+// it, or nil if it did not exist. A rejection moves no money, so it is not
+// journaled.
+func (r *Repository) DeleteChoreCompletion(ctx context.Context, id string) (*model.ChoreCompletion, error) {
+	result, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:    aws.String(r.tableName),
+		Key:          choreCompletionKey(ctx, id),
+		ReturnValues: types.ReturnValueAllOld,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete chore completion: %w", err)
+	}
+	if result.Attributes == nil {
+		return nil, nil
+	}
+	var c model.ChoreCompletion
+	if err := unmarshalItem(result.Attributes, &c); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chore completion: %w", err)
+	}
+	return &c, nil
+}
+
+// AtomicApproveChoreCompletion deletes completion c and credits its reward
+// to c.Month as funds credit entry — the month's allowance_added,
+// earnings_added and ending balance, its mirror and the global balance —
+// journaled as funds.add, in one transaction. ErrChoreCompletionNotFound
+// when the completion is already gone, so a reward is never credited
+// twice; ErrExpenseStateMismatch when the month does not exist.
+func (r *Repository) AtomicApproveChoreCompletion(ctx context.Context, c *model.ChoreCompletion, entry *model.FundEntry) error {
+	entry.PK = AccountPK(ctx, MonthPrefix+c.Month)
+	item, err := attributevalue.MarshalMap(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal funds entry: %w", err)
+	}
+	items := []types.TransactWriteItem{
+		{Delete: &types.Delete{
+			TableName:           aws.String(r.tableName),
+			Key:                 choreCompletionKey(ctx, c.ID),
+			ConditionExpression: aws.String("attribute_exists(PK)"),
+		}},
+	}
+	items = append(items, r.entryDeltaItems(ctx, c.Month, entry, entry.Amount, false)...)
+	items = append(items, types.TransactWriteItem{Put: &types.Put{
+		TableName:           aws.String(r.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(PK)"),
+	}})
+	items, err = r.withAudit(ctx, items, model.AuditFundsAdd, c.Month, entry.SK, nil, FundAuditState(c.Month, entry))
+	if err != nil {
+		return err
+	}
+
+	_, err = r.transactWrite(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+	if err != nil {
+		if idx, ok := txConditionFailedIndex(err); ok {
+			if idx == 0 {
+				return ErrChoreCompletionNotFound
+			}
+			return ErrExpenseStateMismatch
+		}
+		return fmt.Errorf("failed to approve chore completion: %w", err)
+	}
+	return nil
+}
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"math/rand/v2"
-	"strconv"
+	"sort"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -58,6 +60,24 @@ const (
 // already exists. SetupPIN translates this to service.ErrPINAlreadySet.
 var ErrConfigAlreadyExists = errors.New("config already exists")
 
+// ErrConfigNotFound is returned by UpdateConfig and the journaled PIN
+// writes when there is no CONFIG row to update.
+var ErrConfigNotFound = errors.New("config not found")
+
+// CONFIG attributes UpdateConfig and the journaled PIN writes set, each
+// owned by the one service call that sets it.
+const (
+	ConfigPinHash                = "pin_hash"
+	ConfigMemberPinHash          = "member_pin_hash"
+	ConfigCategoryBudgets        = "category_budgets_cents"
+	ConfigEnforceCategoryBudgets = "enforce_category_budgets"
+	ConfigAllowanceSchedule      = "allowance_schedule"
+	ConfigAllowOverspending      = "allow_overspending"
+	ConfigCarryOverBalance       = "carry_over_balance"
+	ConfigDailyLimit             = "daily_limit_cents"
+	ConfigWeeklyLimit            = "weekly_limit_cents"
+)
+
 // ErrInsufficientBalance is returned by atomic expense methods when the
 // transactional overspend check fails (ending_balance < amount). Service
 // layer maps this to ErrInsufficientFunds.
@@ -69,6 +89,11 @@ var ErrInsufficientBalance = errors.New("insufficient balance")
 // ErrExpenseNotFound so the client can re-fetch and try again.
 var ErrExpenseStateMismatch = errors.New("expense state mismatch")
 
+// ErrExpenseAlreadyExists is returned by AtomicAddExpense when a row with
+// the new expense's SK is already present. Service layer maps to
+// ErrDuplicateExpense.
+var ErrExpenseAlreadyExists = errors.New("expense already exists")
+
 // ErrMonthAlreadyExists is returned by AtomicCreateMonth when the month
 // summary already exists. Service layer maps to ErrMonthExists.
 var ErrMonthAlreadyExists = errors.New("month already exists")
@@ -96,6 +121,9 @@ func rateLimitPK(sourceIP string) string {
 type Repository struct {
 	client    *dynamodb.Client
 	tableName string
+	// moneyMigrated caches that the money migration marker exists, so
+	// transactWrite stops looking for legacy rows. See upgradeMoneyRows.
+	moneyMigrated atomic.Bool
 }
 
 func NewRepository(client *dynamodb.Client, tableName string) *Repository {
@@ -162,7 +190,7 @@ func (r *Repository) GetConfig(ctx context.Context) (*model.Config, error) {
 	}
 
 	var config model.Config
-	if err := attributevalue.UnmarshalMap(result.Item, &config); err != nil {
+	if err := unmarshalItem(result.Item, &config); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 	return &config, nil
@@ -191,10 +219,86 @@ func (r *Repository) SaveConfig(ctx context.Context, config *model.Config) error
 	return nil
 }
 
+// UpdateConfig writes only the named attributes of config to the CONFIG
+// row, in one UpdateItem: each is SET to config's value, or REMOVEd where
+// config leaves it empty (omitempty). Every other attribute, the PIN
+// hashes above all, is left as stored, so a PIN change landing between the
+// caller's read and this write is not undone the way SaveConfig's whole-row
+// Put would undo it. A missing row is ErrConfigNotFound.
+func (r *Repository) UpdateConfig(ctx context.Context, config *model.Config, fields ...string) error {
+	update, err := r.configUpdate(config, fields...)
+	if err != nil {
+		return err
+	}
+	// A legacy category_budgets map would outlive a REMOVE of its cents
+	// form and read back in its place.
+	if err := r.upgradeMoneyRows(ctx, update.Key); err != nil {
+		return err
+	}
+	_, err = r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 update.TableName,
+		Key:                       update.Key,
+		UpdateExpression:          update.UpdateExpression,
+		ConditionExpression:       update.ConditionExpression,
+		ExpressionAttributeNames:  update.ExpressionAttributeNames,
+		ExpressionAttributeValues: update.ExpressionAttributeValues,
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return ErrConfigNotFound
+		}
+		return fmt.Errorf("failed to update config: %w", err)
+	}
+	return nil
+}
+
+// configUpdate builds the update UpdateConfig makes: the named attributes
+// of config SET or REMOVEd, updated_at stamped, on a row that must exist.
+func (r *Repository) configUpdate(config *model.Config, fields ...string) (*types.Update, error) {
+	config.UpdatedAt = time.Now()
+	item, err := attributevalue.MarshalMap(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	names := map[string]string{"#updatedAt": "updated_at"}
+	values := map[string]types.AttributeValue{":updatedAt": item["updated_at"]}
+	set := []string{"#updatedAt = :updatedAt"}
+	var remove []string
+	for i, field := range fields {
+		name := fmt.Sprintf("#f%d", i)
+		names[name] = field
+		if value, ok := item[field]; ok {
+			placeholder := fmt.Sprintf(":f%d", i)
+			values[placeholder] = value
+			set = append(set, name+" = "+placeholder)
+		} else {
+			remove = append(remove, name)
+		}
+	}
+	expr := "SET " + strings.Join(set, ", ")
+	if len(remove) > 0 {
+		expr += " REMOVE " + strings.Join(remove, ", ")
+	}
+
+	return &types.Update{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: PKConfig},
+			"SK": &types.AttributeValueMemberS{Value: SKConfig},
+		},
+		UpdateExpression:          aws.String(expr),
+		ConditionExpression:       aws.String("attribute_exists(PK)"),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	}, nil
+}
+
 // CreateConfig writes a new CONFIG row atomically, refusing if one already
 // exists. Used by SetupPIN to close the first-deploy race where an adversary
 // scraping new instance config from GitHub could curl /api/auth/setup before
-// the owner does. ChangePIN continues to use SaveConfig for in-place update.
+// the owner does. ChangePIN updates the hash in place (AtomicChangePIN).
 func (r *Repository) CreateConfig(ctx context.Context, config *model.Config) error {
 	config.PK = PKConfig
 	config.SK = SKConfig
@@ -229,7 +333,7 @@ func (r *Repository) GetBalance(ctx context.Context) (*model.Balance, error) {
 	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(r.tableName),
 		Key: map[string]types.AttributeValue{
-			"PK": &types.AttributeValueMemberS{Value: PKBalance},
+			"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKBalance)},
 			"SK": &types.AttributeValueMemberS{Value: SKBalance},
 		},
 	})
@@ -241,7 +345,7 @@ func (r *Repository) GetBalance(ctx context.Context) (*model.Balance, error) {
 	}
 
 	var balance model.Balance
-	if err := attributevalue.UnmarshalMap(result.Item, &balance); err != nil {
+	if err := unmarshalItem(result.Item, &balance); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal balance: %w", err)
 	}
 	return &balance, nil
@@ -250,7 +354,7 @@ func (r *Repository) GetBalance(ctx context.Context) (*model.Balance, error) {
 // Month operations
 
 func (r *Repository) GetMonthSummary(ctx context.Context, month string) (*model.MonthSummary, error) {
-	pk := MonthPrefix + month
+	pk := AccountPK(ctx, MonthPrefix+month)
 	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(r.tableName),
 		Key: map[string]types.AttributeValue{
@@ -266,14 +370,14 @@ func (r *Repository) GetMonthSummary(ctx context.Context, month string) (*model.
 	}
 
 	var summary model.MonthSummary
-	if err := attributevalue.UnmarshalMap(result.Item, &summary); err != nil {
+	if err := unmarshalItem(result.Item, &summary); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal month summary: %w", err)
 	}
 	return &summary, nil
 }
 
 func (r *Repository) SaveMonthSummary(ctx context.Context, summary *model.MonthSummary) error {
-	summary.PK = MonthPrefix + summary.Month
+	summary.PK = AccountPK(ctx, MonthPrefix+summary.Month)
 	summary.SK = SKSummary
 	summary.UpdatedAt = time.Now()
 	if summary.CreatedAt.IsZero() {
@@ -288,11 +392,11 @@ func (r *Repository) SaveMonthSummary(ctx context.Context, summary *model.MonthS
 	// Write the canonical MONTH#<m>/SUMMARY row and the MONTHLIST/<m>
 	// index copy in a single transaction so the list never drifts from
 	// the source of truth.
-	listItem, err := monthListItem(summary)
+	listItem, err := monthListItem(ctx, summary)
 	if err != nil {
 		return err
 	}
-	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+	_, err = r.transactWrite(ctx, &dynamodb.TransactWriteItemsInput{
 		TransactItems: []types.TransactWriteItem{
 			{Put: &types.Put{TableName: aws.String(r.tableName), Item: item}},
 			{Put: &types.Put{TableName: aws.String(r.tableName), Item: listItem}},
@@ -307,9 +411,9 @@ func (r *Repository) SaveMonthSummary(ctx context.Context, summary *model.MonthS
 // monthListItem builds the MONTHLIST index copy of a month summary: same
 // attributes as the canonical row, but keyed PK="MONTHLIST", SK="<yyyy-mm>"
 // so the list can be served by a single sorted Query instead of a Scan.
-func monthListItem(summary *model.MonthSummary) (map[string]types.AttributeValue, error) {
+func monthListItem(ctx context.Context, summary *model.MonthSummary) (map[string]types.AttributeValue, error) {
 	copy := *summary
-	copy.PK = PKMonthList
+	copy.PK = AccountPK(ctx, PKMonthList)
 	copy.SK = summary.Month
 	item, err := attributevalue.MarshalMap(&copy)
 	if err != nil {
@@ -321,8 +425,8 @@ func monthListItem(summary *model.MonthSummary) (map[string]types.AttributeValue
 // monthListPut returns a TransactWriteItem that upserts the MONTHLIST copy
 // of a summary. Used by AtomicCreateMonth so the index gets its first copy
 // inside the same transaction as the canonical row.
-func (r *Repository) monthListPut(summary *model.MonthSummary) (types.TransactWriteItem, error) {
-	item, err := monthListItem(summary)
+func (r *Repository) monthListPut(ctx context.Context, summary *model.MonthSummary) (types.TransactWriteItem, error) {
+	item, err := monthListItem(ctx, summary)
 	if err != nil {
 		return types.TransactWriteItem{}, err
 	}
@@ -337,20 +441,152 @@ func (r *Repository) monthListPut(summary *model.MonthSummary) (types.TransactWr
 // alongside the canonical row (SaveMonthSummary / AtomicCreateMonth), and on
 // legacy tables that predate the mirror the service back-fills it via
 // EnsureMonthListMirror immediately before the mutation transaction — so for
-// any month that can be mutated the copy is guaranteed to exist.
-func (r *Repository) monthListUpdate(month, updateExpr string, values map[string]types.AttributeValue) types.TransactWriteItem {
+// any month that can be mutated the copy is guaranteed to exist. names
+// carries the #cat placeholders of any category_totals clauses (nil when
+// the expression has none — DynamoDB rejects an empty names map).
+func (r *Repository) monthListUpdate(ctx context.Context, month, updateExpr string, names map[string]string, values map[string]types.AttributeValue) types.TransactWriteItem {
 	return types.TransactWriteItem{Update: &types.Update{
 		TableName: aws.String(r.tableName),
 		Key: map[string]types.AttributeValue{
-			"PK": &types.AttributeValueMemberS{Value: PKMonthList},
+			"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKMonthList)},
 			"SK": &types.AttributeValueMemberS{Value: month},
 		},
 		UpdateExpression:          aws.String(updateExpr),
 		ConditionExpression:       aws.String("attribute_exists(PK)"),
+		ExpressionAttributeNames:  names,
 		ExpressionAttributeValues: values,
 	}}
 }
 
+// categoryDeltas collects the per-category changes one transaction makes to
+// a single month's category_totals. Uncategorized spend ("") is not tracked,
+// and a category that appears on both sides of an edit nets out here, which
+// matters: DynamoDB rejects an UpdateExpression that names the same document
+// path twice.
+func categoryDeltas(changes ...categoryChange) map[string]model.Money {
+	deltas := map[string]model.Money{}
+	for _, c := range changes {
+		if c.category == "" {
+			continue
+		}
+		deltas[c.category] += c.delta
+	}
+	for cat, d := range deltas {
+		if d == 0 {
+			delete(deltas, cat)
+		}
+	}
+	return deltas
+}
+
+// categoryChange is one signed contribution to a category's running total.
+type categoryChange struct {
+	category string
+	delta    model.Money
+}
+
+// withCategoryTotals appends one `category_totals.#catN` clause per delta to
+// a summary SET expression and adds the matching placeholders to values (in
+// place). Category labels are user text, so they always travel as
+// ExpressionAttributeNames, never spliced into the expression itself. The
+// map attribute itself must already exist — see EnsureCategoryTotals —
+// because DynamoDB cannot SET a nested path under a missing parent.
+func withCategoryTotals(expr string, values map[string]types.AttributeValue, deltas map[string]model.Money) (string, map[string]string) {
+	if len(deltas) == 0 {
+		return expr, nil
+	}
+	cats := make([]string, 0, len(deltas))
+	for cat := range deltas {
+		cats = append(cats, cat)
+	}
+	sort.Strings(cats)
+
+	names := make(map[string]string, len(cats))
+	var b strings.Builder
+	b.WriteString(expr)
+	for i, cat := range cats {
+		name := fmt.Sprintf("#cat%d", i)
+		value := fmt.Sprintf(":cat%d", i)
+		names[name] = cat
+		values[value] = moneyValue(deltas[cat])
+		fmt.Fprintf(&b, ", category_totals_cents.%s = if_not_exists(category_totals_cents.%s, :catZero) + %s", name, name, value)
+	}
+	values[":catZero"] = &types.AttributeValueMemberN{Value: "0"}
+	return b.String(), names
+}
+
+// expenseLock is the optimistic-lock condition on an expense row the caller
+// read as old, adding its placeholders to values (in place). The row must
+// still be an expense with old's amount and old's category: the
+// category_totals deltas of the same transaction are computed from both, so
+// a recategorization landing between the read and the write would
+// otherwise debit the wrong category's total. An uncategorized row stores
+// no category attribute at all.
+func expenseLock(old *model.Expense, values map[string]types.AttributeValue) string {
+	values[":expensePrefix"] = &types.AttributeValueMemberS{Value: ExpensePrefix}
+	values[":oldAmount"] = moneyValue(old.Amount)
+	lock := "attribute_exists(PK) AND begins_with(SK, :expensePrefix) AND amount_cents = :oldAmount"
+	if old.Category == "" {
+		return lock + " AND attribute_not_exists(category)"
+	}
+	values[":oldCategory"] = &types.AttributeValueMemberS{Value: old.Category}
+	return lock + " AND category = :oldCategory"
+}
+
+// cloneValues copies an ExpressionAttributeValues map so the canonical and
+// mirror legs of a transaction never share (and later mutate) one map.
+func cloneValues(values map[string]types.AttributeValue) map[string]types.AttributeValue {
+	out := make(map[string]types.AttributeValue, len(values))
+	for k, v := range values {
+		out[k] = v
+	}
+	return out
+}
+
+// timestamp renders t as an updated_at value: RFC 3339 to the nanosecond,
+// so two writes to one row within a second still leave it different. The
+// stats cache relies on that to notice a month has changed.
+func timestamp(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+// EnsureCategoryTotals makes sure the month's canonical summary AND its
+// MONTHLIST mirror carry a category_totals map, so the nested
+// `category_totals.#cat` updates inside the expense transactions have a
+// parent to land in. Rows written before categories existed have no such
+// attribute, and DynamoDB refuses to SET a path beneath a missing map — the
+// whole transaction would cancel. The write is an idempotent
+// if_not_exists seed of an empty map, applied to both rows in one
+// transaction so they cannot end up disagreeing; an existing map (and its
+// totals) is left alone. The caller must have back-filled the mirror first
+// (EnsureMonthListMirror).
+func (r *Repository) EnsureCategoryTotals(ctx context.Context, month string) error {
+	update := func(pk, sk string) types.TransactWriteItem {
+		return types.TransactWriteItem{Update: &types.Update{
+			TableName: aws.String(r.tableName),
+			Key: map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberS{Value: pk},
+				"SK": &types.AttributeValueMemberS{Value: sk},
+			},
+			UpdateExpression:    aws.String("SET category_totals_cents = if_not_exists(category_totals_cents, :empty)"),
+			ConditionExpression: aws.String("attribute_exists(PK)"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":empty": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{}},
+			},
+		}}
+	}
+	_, err := r.transactWrite(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			update(AccountPK(ctx, MonthPrefix+month), SKSummary),
+			update(AccountPK(ctx, PKMonthList), month),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialise category totals: %w", err)
+	}
+	return nil
+}
+
 // EnsureMonthListMirror guarantees the MONTHLIST mirror row (PK="MONTHLIST",
 // SK="<yyyy-mm>") exists for a month before any mutation transaction that
 // delta-updates it runs. On live tables written before the MONTHLIST scheme
@@ -376,7 +612,7 @@ func (r *Repository) EnsureMonthListMirror(ctx context.Context, month string) er
 	mirror, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(r.tableName),
 		Key: map[string]types.AttributeValue{
-			"PK": &types.AttributeValueMemberS{Value: PKMonthList},
+			"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKMonthList)},
 			"SK": &types.AttributeValueMemberS{Value: month},
 		},
 	})
@@ -397,7 +633,7 @@ func (r *Repository) EnsureMonthListMirror(ctx context.Context, month string) er
 		return nil
 	}
 
-	listItem, err := monthListItem(summary)
+	listItem, err := monthListItem(ctx, summary)
 	if err != nil {
 		return err
 	}
@@ -428,7 +664,7 @@ func (r *Repository) ListMonths(ctx context.Context, limit int32, cursor map[str
 		TableName:              aws.String(r.tableName),
 		KeyConditionExpression: aws.String("PK = :pk"),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":pk": &types.AttributeValueMemberS{Value: PKMonthList},
+			":pk": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKMonthList)},
 		},
 		ScanIndexForward: aws.Bool(false), // most recent first
 	}
@@ -445,7 +681,7 @@ func (r *Repository) ListMonths(ctx context.Context, limit int32, cursor map[str
 	}
 
 	var months []model.MonthSummary
-	if err := attributevalue.UnmarshalListOfMaps(result.Items, &months); err != nil {
+	if err := unmarshalItems(result.Items, &months); err != nil {
 		return nil, nil, fmt.Errorf("failed to unmarshal months: %w", err)
 	}
 	return months, result.LastEvaluatedKey, nil
@@ -456,7 +692,8 @@ func (r *Repository) ListMonths(ctx context.Context, limit int32, cursor map[str
 // source for tables written before the MONTHLIST index existed: when a
 // MONTHLIST Query returns nothing, the service falls back to this scan and
 // backfills the index (see BackfillMonthList). After backfill, the scan is
-// never hit again.
+// never hit again. It reads the context's account only: the default
+// account's prefix, MONTH#, is not a prefix of any other account's keys.
 func (r *Repository) listAllMonthsLegacy(ctx context.Context) ([]model.MonthSummary, error) {
 	var allMonths []model.MonthSummary
 	var lastKey map[string]types.AttributeValue
@@ -466,7 +703,7 @@ func (r *Repository) listAllMonthsLegacy(ctx context.Context) ([]model.MonthSumm
 			TableName:        aws.String(r.tableName),
 			FilterExpression: aws.String("begins_with(PK, :prefix) AND SK = :summary"),
 			ExpressionAttributeValues: map[string]types.AttributeValue{
-				":prefix":  &types.AttributeValueMemberS{Value: MonthPrefix},
+				":prefix":  &types.AttributeValueMemberS{Value: AccountPK(ctx, MonthPrefix)},
 				":summary": &types.AttributeValueMemberS{Value: SKSummary},
 			},
 		}
@@ -480,7 +717,7 @@ func (r *Repository) listAllMonthsLegacy(ctx context.Context) ([]model.MonthSumm
 		}
 
 		var months []model.MonthSummary
-		if err := attributevalue.UnmarshalListOfMaps(result.Items, &months); err != nil {
+		if err := unmarshalItems(result.Items, &months); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal months: %w", err)
 		}
 		allMonths = append(allMonths, months...)
@@ -515,7 +752,7 @@ func (r *Repository) BackfillMonthList(ctx context.Context, summaries []model.Mo
 		writes := make([]types.WriteRequest, 0, end-i)
 		for j := i; j < end; j++ {
 			s := summaries[j]
-			item, err := monthListItem(&s)
+			item, err := monthListItem(ctx, &s)
 			if err != nil {
 				return err
 			}
@@ -541,7 +778,7 @@ func (r *Repository) BackfillMonthList(ctx context.Context, summaries []model.Mo
 // Returns the expenses, the DynamoDB LastEvaluatedKey for the next page
 // (nil when there are no more results), and any error.
 func (r *Repository) GetExpenses(ctx context.Context, month string, limit int32, cursor map[string]types.AttributeValue) ([]model.Expense, map[string]types.AttributeValue, error) {
-	pk := MonthPrefix + month
+	pk := AccountPK(ctx, MonthPrefix+month)
 	input := &dynamodb.QueryInput{
 		TableName:              aws.String(r.tableName),
 		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :prefix)"),
@@ -565,7 +802,42 @@ func (r *Repository) GetExpenses(ctx context.Context, month string, limit int32,
 	}
 
 	var expenses []model.Expense
-	if err := attributevalue.UnmarshalListOfMaps(result.Items, &expenses); err != nil {
+	if err := unmarshalItems(result.Items, &expenses); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal expenses: %w", err)
+	}
+	return expenses, result.LastEvaluatedKey, nil
+}
+
+// GetExpensesInRange is GetExpenses narrowed to the expenses timestamped in
+// [from, to): the SK encodes the expense time as EXP#<unixnano>#<id>, so the
+// range is a key condition on SK rather than a filter. Newest first.
+func (r *Repository) GetExpensesInRange(ctx context.Context, month string, from, to time.Time, limit int32, cursor map[string]types.AttributeValue) ([]model.Expense, map[string]types.AttributeValue, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String("PK = :pk AND SK BETWEEN :lo AND :hi"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: AccountPK(ctx, MonthPrefix+month)},
+			// "EXP#<n>" sorts before every "EXP#<n>#<id>" and after every SK
+			// with a smaller (same-width) timestamp, so these bounds include
+			// from and exclude to.
+			":lo": &types.AttributeValueMemberS{Value: fmt.Sprintf("%s%d", ExpensePrefix, from.UnixNano())},
+			":hi": &types.AttributeValueMemberS{Value: fmt.Sprintf("%s%d", ExpensePrefix, to.UnixNano())},
+		},
+		ScanIndexForward: aws.Bool(false),
+	}
+	if limit > 0 {
+		input.Limit = aws.Int32(limit)
+	}
+	if cursor != nil {
+		input.ExclusiveStartKey = cursor
+	}
+
+	result, err := r.client.Query(ctx, input)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get expenses in range: %w", err)
+	}
+	var expenses []model.Expense
+	if err := unmarshalItems(result.Items, &expenses); err != nil {
 		return nil, nil, fmt.Errorf("failed to unmarshal expenses: %w", err)
 	}
 	return expenses, result.LastEvaluatedKey, nil
@@ -574,7 +846,7 @@ func (r *Repository) GetExpenses(ctx context.Context, month string, limit int32,
 // GetExpense fetches a single expense by its month and sort key (expenseID).
 // Returns nil (without error) if the expense does not exist.
 func (r *Repository) GetExpense(ctx context.Context, month string, expenseID string) (*model.Expense, error) {
-	pk := MonthPrefix + month
+	pk := AccountPK(ctx, MonthPrefix+month)
 	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(r.tableName),
 		Key: map[string]types.AttributeValue{
@@ -590,7 +862,7 @@ func (r *Repository) GetExpense(ctx context.Context, month string, expenseID str
 	}
 
 	var expense model.Expense
-	if err := attributevalue.UnmarshalMap(result.Item, &expense); err != nil {
+	if err := unmarshalItem(result.Item, &expense); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal expense: %w", err)
 	}
 	return &expense, nil
@@ -603,18 +875,18 @@ func (r *Repository) GetExpense(ctx context.Context, month string, expenseID str
 // other non-expense SK in the same partition. Returns the OLD expense
 // values so the caller can compute the amount delta. Returns nil (without
 // error) if the expense does not exist (ConditionalCheckFailedException).
-func (r *Repository) UpdateExpense(ctx context.Context, month string, expenseID string, amount float64, description string) (*model.Expense, error) {
-	pk := MonthPrefix + month
+func (r *Repository) UpdateExpense(ctx context.Context, month string, expenseID string, amount model.Money, description string) (*model.Expense, error) {
+	pk := AccountPK(ctx, MonthPrefix+month)
 	result, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
 		TableName: aws.String(r.tableName),
 		Key: map[string]types.AttributeValue{
 			"PK": &types.AttributeValueMemberS{Value: pk},
 			"SK": &types.AttributeValueMemberS{Value: expenseID},
 		},
-		UpdateExpression:    aws.String("SET amount = :amount, description = :desc"),
+		UpdateExpression:    aws.String("SET amount_cents = :amount, description = :desc"),
 		ConditionExpression: aws.String("attribute_exists(PK) AND begins_with(SK, :expensePrefix)"),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":amount":        &types.AttributeValueMemberN{Value: fmt.Sprintf("%.2f", amount)},
+			":amount":        moneyValue(amount),
 			":desc":          &types.AttributeValueMemberS{Value: description},
 			":expensePrefix": &types.AttributeValueMemberS{Value: ExpensePrefix},
 		},
@@ -629,7 +901,7 @@ func (r *Repository) UpdateExpense(ctx context.Context, month string, expenseID
 	}
 
 	var oldExpense model.Expense
-	if err := attributevalue.UnmarshalMap(result.Attributes, &oldExpense); err != nil {
+	if err := unmarshalItem(result.Attributes, &oldExpense); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal old expense: %w", err)
 	}
 	return &oldExpense, nil
@@ -641,7 +913,7 @@ func (r *Repository) UpdateExpense(ctx context.Context, month string, expenseID
 // Returns the deleted expense (for caller bookkeeping), nil if the row
 // did not exist or did not satisfy the condition.
 func (r *Repository) DeleteExpense(ctx context.Context, month string, expenseID string) (*model.Expense, error) {
-	pk := MonthPrefix + month
+	pk := AccountPK(ctx, MonthPrefix+month)
 	result, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
 		TableName: aws.String(r.tableName),
 		Key: map[string]types.AttributeValue{
@@ -666,7 +938,7 @@ func (r *Repository) DeleteExpense(ctx context.Context, month string, expenseID
 	}
 
 	var expense model.Expense
-	if err := attributevalue.UnmarshalMap(result.Attributes, &expense); err != nil {
+	if err := unmarshalItem(result.Attributes, &expense); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal expense: %w", err)
 	}
 	return &expense, nil
@@ -674,7 +946,7 @@ func (r *Repository) DeleteExpense(ctx context.Context, month string, expenseID
 
 // Session operations
 
-func (r *Repository) CreateSession(ctx context.Context, token string, ttlHours int) error {
+func (r *Repository) CreateSession(ctx context.Context, token, role string, ttlHours int) error {
 	now := time.Now()
 	session := model.Session{
 		PK:        SessionPrefix + token,
@@ -682,6 +954,7 @@ func (r *Repository) CreateSession(ctx context.Context, token string, ttlHours i
 		Token:     token,
 		CreatedAt: now.Unix(),
 		TTL:       now.Add(time.Duration(ttlHours) * time.Hour).Unix(),
+		Role:      role,
 	}
 
 	item, err := attributevalue.MarshalMap(session)
@@ -749,16 +1022,37 @@ func (r *Repository) DeleteSession(ctx context.Context, token string) error {
 // For a single-user family app the session-row count is tiny so this
 // is fine; if it ever grows, replace with a GSI on entity_type.
 func (r *Repository) DeleteAllSessions(ctx context.Context) error {
+	return r.deleteSessions(ctx, "")
+}
+
+// DeleteMemberSessions removes every member session, leaving the admin
+// ones. Called when the member PIN is changed or removed.
+func (r *Repository) DeleteMemberSessions(ctx context.Context) error {
+	return r.deleteSessions(ctx, model.RoleMember)
+}
+
+// deleteSessions removes the sessions of role, or all of them when role
+// is empty.
+func (r *Repository) deleteSessions(ctx context.Context, role string) error {
+	filter := "begins_with(PK, :prefix)"
+	values := map[string]types.AttributeValue{
+		":prefix": &types.AttributeValueMemberS{Value: SessionPrefix},
+	}
+	var names map[string]string
+	if role != "" {
+		filter += " AND #role = :role"
+		values[":role"] = &types.AttributeValueMemberS{Value: role}
+		names = map[string]string{"#role": "role"}
+	}
 	var lastKey map[string]types.AttributeValue
 	for {
 		scanResult, err := r.client.Scan(ctx, &dynamodb.ScanInput{
-			TableName:            aws.String(r.tableName),
-			FilterExpression:     aws.String("begins_with(PK, :prefix)"),
-			ProjectionExpression: aws.String("PK, SK"),
-			ExpressionAttributeValues: map[string]types.AttributeValue{
-				":prefix": &types.AttributeValueMemberS{Value: SessionPrefix},
-			},
-			ExclusiveStartKey: lastKey,
+			TableName:                 aws.String(r.tableName),
+			FilterExpression:          aws.String(filter),
+			ProjectionExpression:      aws.String("PK, SK"),
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+			ExclusiveStartKey:         lastKey,
 		})
 		if err != nil {
 			return fmt.Errorf("failed to scan sessions: %w", err)
@@ -992,6 +1286,12 @@ func webAuthnCredentialListItem(cred *model.WebAuthnCredential) (map[string]type
 // WACREDLIST mirror in a single transaction so the enumeration index never
 // drifts from the source of truth (mirrors SaveMonthSummary's dual-write).
 func (r *Repository) PutWebAuthnCredential(ctx context.Context, cred *model.WebAuthnCredential) error {
+	return r.putWebAuthnCredential(ctx, cred)
+}
+
+// putWebAuthnCredential is PutWebAuthnCredential with extra items (an
+// audit entry) riding in the same transaction.
+func (r *Repository) putWebAuthnCredential(ctx context.Context, cred *model.WebAuthnCredential, extra ...types.TransactWriteItem) error {
 	pk := WebAuthnCredentialPrefix + cred.CredentialID
 	cred.PK = pk
 	cred.SK = pk
@@ -1006,11 +1306,11 @@ func (r *Repository) PutWebAuthnCredential(ctx context.Context, cred *model.WebA
 	if err != nil {
 		return err
 	}
-	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
-		TransactItems: []types.TransactWriteItem{
+	_, err = r.transactWrite(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: append([]types.TransactWriteItem{
 			{Put: &types.Put{TableName: aws.String(r.tableName), Item: item}},
 			{Put: &types.Put{TableName: aws.String(r.tableName), Item: listItem}},
-		},
+		}, extra...),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to save webauthn credential: %w", err)
@@ -1107,13 +1407,15 @@ func (r *Repository) DeleteAllWebAuthnCredentials(ctx context.Context) error {
 //
 // Conditional checks on the month-summary update double as concurrency
 // guards:
-//   • `ending_balance >= :delta` enforces non-overspending when the
+//   • `ending_balance_cents >= :delta` enforces non-overspending when the
 //     instance forbids it. A parallel AddExpense that drops the balance
 //     below the requested amount will fail its transaction rather than
 //     succeed and leave the ledger negative.
-//   • `amount = :oldAmount` on the expense's update or delete catches a
-//     concurrent edit landing between the service's read and the
-//     transact — surfaces as ErrExpenseStateMismatch.
+//   • `amount_cents = :oldAmount` and the old category on the expense's
+//     update or delete (expenseLock) catch a concurrent edit landing
+//     between the service's read and the transact — surfaces as
+//     ErrExpenseStateMismatch. Both amounts are integer cents (see
+//     money.go), so the comparison is exact.
 //
 // TransactWriteItems uses 2x WCU vs separate writes. At this app's
 // traffic (a family of four) the cost difference is rounding error.
@@ -1137,38 +1439,57 @@ func txConditionFailedIndex(err error) (int, bool) {
 // AtomicAddExpense puts the expense row and updates the month summary +
 // global balance in a single transaction. If checkBalance is true, the
 // month summary update is conditioned on ending_balance >= amount; on
-// failure, returns ErrInsufficientBalance.
+// failure, returns ErrInsufficientBalance. A categorized expense also bumps
+// category_totals on the summary and its mirror in the same transaction.
+// The put is conditioned on the SK being new, so re-booking a deterministic
+// SK (a recurring occurrence) returns ErrExpenseAlreadyExists instead of
+// overwriting the row and charging the month twice.
 func (r *Repository) AtomicAddExpense(ctx context.Context, month string, expense *model.Expense, checkBalance bool) error {
-	expense.PK = MonthPrefix + month
+	return r.atomicAddExpense(ctx, month, expense, checkBalance, nil, nil)
+}
+
+// atomicAddExpense is AtomicAddExpense, also deleting source when set: the
+// entry the expense is booked from, conditioned on it still existing.
+// gone is returned when that condition fails.
+func (r *Repository) atomicAddExpense(ctx context.Context, month string, expense *model.Expense, checkBalance bool, source *types.Delete, gone error) error {
+	expense.PK = AccountPK(ctx, MonthPrefix+month)
 	expenseItem, err := attributevalue.MarshalMap(expense)
 	if err != nil {
 		return fmt.Errorf("failed to marshal expense: %w", err)
 	}
-	pkMonth := MonthPrefix + month
-	nowStr := time.Now().Format(time.RFC3339)
-	amountStr := fmt.Sprintf("%.2f", expense.Amount)
-	negAmountStr := fmt.Sprintf("%.2f", -expense.Amount)
+	pkMonth := AccountPK(ctx, MonthPrefix+month)
+	nowStr := timestamp(time.Now())
 
 	monthCondition := "attribute_exists(PK)"
 	if checkBalance {
-		monthCondition = "attribute_exists(PK) AND ending_balance >= :amount"
+		monthCondition = "attribute_exists(PK) AND ending_balance_cents >= :amount"
 	}
 
-	summaryExpr := "SET total_expenses = total_expenses + :amount, ending_balance = ending_balance - :amount, updated_at = :now"
 	summaryValues := map[string]types.AttributeValue{
-		":amount": &types.AttributeValueMemberN{Value: amountStr},
-		":now":    &types.AttributeValueMemberS{Value: nowStr},
-	}
-	listValues := map[string]types.AttributeValue{
-		":amount": &types.AttributeValueMemberN{Value: amountStr},
+		":amount": moneyValue(expense.Amount),
 		":now":    &types.AttributeValueMemberS{Value: nowStr},
 	}
+	summaryExpr, names := withCategoryTotals(
+		"SET total_expenses_cents = total_expenses_cents + :amount, ending_balance_cents = ending_balance_cents - :amount, updated_at = :now",
+		summaryValues,
+		categoryDeltas(categoryChange{expense.Category, expense.Amount}),
+	)
+	listValues := cloneValues(summaryValues)
 
-	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
-		TransactItems: []types.TransactWriteItem{
+	items, err := r.withAudit(ctx, r.searchIndexItems(ctx, "", nil, month, expense),
+		model.AuditExpenseAdd, month, expense.SK, nil, ExpenseAuditState(month, expense))
+	if err != nil {
+		return err
+	}
+	if source != nil {
+		items = append([]types.TransactWriteItem{{Delete: source}}, items...)
+	}
+	_, err = r.transactWrite(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: append([]types.TransactWriteItem{
 			{Put: &types.Put{
-				TableName: aws.String(r.tableName),
-				Item:      expenseItem,
+				TableName:           aws.String(r.tableName),
+				Item:                expenseItem,
+				ConditionExpression: aws.String("attribute_not_exists(PK)"),
 			}},
 			{Update: &types.Update{
 				TableName: aws.String(r.tableName),
@@ -1178,85 +1499,105 @@ func (r *Repository) AtomicAddExpense(ctx context.Context, month string, expense
 				},
 				UpdateExpression:          aws.String(summaryExpr),
 				ConditionExpression:       aws.String(monthCondition),
+				ExpressionAttributeNames:  names,
 				ExpressionAttributeValues: summaryValues,
 			}},
 			{Update: &types.Update{
 				TableName: aws.String(r.tableName),
 				Key: map[string]types.AttributeValue{
-					"PK": &types.AttributeValueMemberS{Value: PKBalance},
+					"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKBalance)},
 					"SK": &types.AttributeValueMemberS{Value: SKBalance},
 				},
-				UpdateExpression: aws.String("SET total_balance = if_not_exists(total_balance, :zero) + :delta, updated_at = :now"),
+				UpdateExpression: aws.String("SET total_balance_cents = if_not_exists(total_balance_cents, :zero) + :delta, updated_at = :now"),
 				ExpressionAttributeValues: map[string]types.AttributeValue{
-					":delta": &types.AttributeValueMemberN{Value: negAmountStr},
+					":delta": moneyValue(-expense.Amount),
 					":zero":  &types.AttributeValueMemberN{Value: "0"},
 					":now":   &types.AttributeValueMemberS{Value: nowStr},
 				},
 			}},
-			r.monthListUpdate(month, summaryExpr, listValues),
-		},
+			r.monthListUpdate(ctx, month, summaryExpr, names, listValues),
+		}, items...),
 	})
 	if err != nil {
-		if idx, ok := txConditionFailedIndex(err); ok && idx == 1 {
-			// Index 1 is the month-summary update — overspend or summary missing.
-			return ErrInsufficientBalance
+		if idx, ok := txConditionFailedIndex(err); ok {
+			switch idx {
+			case 0:
+				return ErrExpenseAlreadyExists
+			case 1:
+				// Index 1 is the month-summary update — overspend or summary missing.
+				return ErrInsufficientBalance
+			case 4:
+				if source != nil {
+					return gone
+				}
+			}
 		}
 		return fmt.Errorf("failed to add expense atomically: %w", err)
 	}
 	return nil
 }
 
-// AtomicUpdateExpense updates the expense's amount/description and adjusts
-// the month summary + global balance by the supplied delta in a single
-// transaction. The expense update is conditioned on `amount = :oldAmount`
-// to detect concurrent edits → ErrExpenseStateMismatch on mismatch.
-// If amountDelta > 0 and checkBalance is true, the month summary update
-// is also conditioned on ending_balance >= delta → ErrInsufficientBalance.
-func (r *Repository) AtomicUpdateExpense(ctx context.Context, month string, expenseID string, oldAmount, newAmount float64, newDescription string, checkBalance bool) error {
-	pkMonth := MonthPrefix + month
-	nowStr := time.Now().Format(time.RFC3339)
-	// Format :oldAmount as the shortest round-trip of the value actually
-	// read from DynamoDB, NOT "%.2f". Legacy rows written before
-	// cent-rounding can hold e.g. 12.349999; "%.2f" would render "12.35",
-	// the amount = :oldAmount condition would never match, and that
-	// expense could never be edited or deleted (perpetual mismatch).
-	oldAmountStr := strconv.FormatFloat(oldAmount, 'f', -1, 64)
-	newAmountStr := fmt.Sprintf("%.2f", newAmount)
-	delta := newAmount - oldAmount
-	deltaStr := fmt.Sprintf("%.2f", delta)
-	negDeltaStr := fmt.Sprintf("%.2f", -delta)
+// AtomicUpdateExpense rewrites the expense's amount/description/category in
+// place (same SK) and adjusts the month summary + global balance by the
+// amount delta in a single transaction. The expense update is conditioned
+// on old's amount and category, as read by the caller (expenseLock), to
+// detect concurrent edits → ErrExpenseStateMismatch on mismatch. If the delta is
+// positive and checkBalance is true, the month summary update is also
+// conditioned on ending_balance >= delta → ErrInsufficientBalance. A
+// category change moves the amount between category_totals entries.
+func (r *Repository) AtomicUpdateExpense(ctx context.Context, month string, old, updated *model.Expense, checkBalance bool) error {
+	pkMonth := AccountPK(ctx, MonthPrefix+month)
+	nowStr := timestamp(time.Now())
+	delta := updated.Amount - old.Amount
 
 	monthCondition := "attribute_exists(PK)"
 	if checkBalance && delta > 0 {
-		monthCondition = "attribute_exists(PK) AND ending_balance >= :delta"
+		monthCondition = "attribute_exists(PK) AND ending_balance_cents >= :delta"
 	}
 
-	summaryExpr := "SET total_expenses = total_expenses + :delta, ending_balance = ending_balance - :delta, updated_at = :now"
 	summaryValues := map[string]types.AttributeValue{
-		":delta": &types.AttributeValueMemberN{Value: deltaStr},
+		":delta": moneyValue(delta),
 		":now":   &types.AttributeValueMemberS{Value: nowStr},
 	}
-	listValues := map[string]types.AttributeValue{
-		":delta": &types.AttributeValueMemberN{Value: deltaStr},
-		":now":   &types.AttributeValueMemberS{Value: nowStr},
+	summaryExpr, names := withCategoryTotals(
+		"SET total_expenses_cents = total_expenses_cents + :delta, ending_balance_cents = ending_balance_cents - :delta, updated_at = :now",
+		summaryValues,
+		categoryDeltas(
+			categoryChange{old.Category, -old.Amount},
+			categoryChange{updated.Category, updated.Amount},
+		),
+	)
+	listValues := cloneValues(summaryValues)
+
+	expenseValues := map[string]types.AttributeValue{
+		":newAmount": moneyValue(updated.Amount),
+		":desc":      &types.AttributeValueMemberS{Value: updated.Description},
+	}
+	lock := expenseLock(old, expenseValues)
+	expenseExpr := "SET amount_cents = :newAmount, description = :desc REMOVE category"
+	if updated.Category != "" {
+		expenseExpr = "SET amount_cents = :newAmount, description = :desc, category = :category"
+		expenseValues[":category"] = &types.AttributeValueMemberS{Value: updated.Category}
+	}
+
+	after := *old
+	after.Amount, after.Description, after.Category = updated.Amount, updated.Description, updated.Category
+	items, err := r.withAudit(ctx, r.searchIndexItems(ctx, month, old, month, updated),
+		model.AuditExpenseUpdate, month, old.SK, ExpenseAuditState(month, old), ExpenseAuditState(month, &after))
+	if err != nil {
+		return err
 	}
-
-	_, err := r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
-		TransactItems: []types.TransactWriteItem{
+	_, err = r.transactWrite(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: append([]types.TransactWriteItem{
 			{Update: &types.Update{
 				TableName: aws.String(r.tableName),
 				Key: map[string]types.AttributeValue{
 					"PK": &types.AttributeValueMemberS{Value: pkMonth},
-					"SK": &types.AttributeValueMemberS{Value: expenseID},
-				},
-				UpdateExpression:    aws.String("SET amount = :newAmount, description = :desc"),
-				ConditionExpression: aws.String("attribute_exists(PK) AND begins_with(SK, :expensePrefix) AND amount = :oldAmount"),
-				ExpressionAttributeValues: map[string]types.AttributeValue{
-					":newAmount":     &types.AttributeValueMemberN{Value: newAmountStr},
-					":oldAmount":     &types.AttributeValueMemberN{Value: oldAmountStr},
-					":desc":          &types.AttributeValueMemberS{Value: newDescription},
-					":expensePrefix": &types.AttributeValueMemberS{Value: ExpensePrefix},
+					"SK": &types.AttributeValueMemberS{Value: old.SK},
 				},
+				UpdateExpression:          aws.String(expenseExpr),
+				ConditionExpression:       aws.String(lock),
+				ExpressionAttributeValues: expenseValues,
 			}},
 			{Update: &types.Update{
 				TableName: aws.String(r.tableName),
@@ -1266,23 +1607,24 @@ func (r *Repository) AtomicUpdateExpense(ctx context.Context, month string, expe
 				},
 				UpdateExpression:          aws.String(summaryExpr),
 				ConditionExpression:       aws.String(monthCondition),
+				ExpressionAttributeNames:  names,
 				ExpressionAttributeValues: summaryValues,
 			}},
 			{Update: &types.Update{
 				TableName: aws.String(r.tableName),
 				Key: map[string]types.AttributeValue{
-					"PK": &types.AttributeValueMemberS{Value: PKBalance},
+					"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKBalance)},
 					"SK": &types.AttributeValueMemberS{Value: SKBalance},
 				},
-				UpdateExpression: aws.String("SET total_balance = if_not_exists(total_balance, :zero) + :balanceDelta, updated_at = :now"),
+				UpdateExpression: aws.String("SET total_balance_cents = if_not_exists(total_balance_cents, :zero) + :balanceDelta, updated_at = :now"),
 				ExpressionAttributeValues: map[string]types.AttributeValue{
-					":balanceDelta": &types.AttributeValueMemberN{Value: negDeltaStr},
+					":balanceDelta": moneyValue(-delta),
 					":zero":         &types.AttributeValueMemberN{Value: "0"},
 					":now":          &types.AttributeValueMemberS{Value: nowStr},
 				},
 			}},
-			r.monthListUpdate(month, summaryExpr, listValues),
-		},
+			r.monthListUpdate(ctx, month, summaryExpr, names, listValues),
+		}, items...),
 	})
 	if err != nil {
 		if idx, ok := txConditionFailedIndex(err); ok {
@@ -1299,40 +1641,49 @@ func (r *Repository) AtomicUpdateExpense(ctx context.Context, month string, expe
 }
 
 // AtomicDeleteExpense deletes the expense row and refunds the month
-// summary + global balance in a single transaction. The delete is
-// conditioned on `amount = :oldAmount` so a concurrent edit between
-// the service's read and the transact surfaces as ErrExpenseStateMismatch.
-func (r *Repository) AtomicDeleteExpense(ctx context.Context, month string, expenseID string, oldAmount float64) error {
-	pkMonth := MonthPrefix + month
-	nowStr := time.Now().Format(time.RFC3339)
-	// Shortest round-trip of the value actually read — see AtomicUpdateExpense
-	// for why "%.2f" would orphan legacy unrounded amounts (B7).
-	oldAmountStr := strconv.FormatFloat(oldAmount, 'f', -1, 64)
-	negAmountStr := strconv.FormatFloat(-oldAmount, 'f', -1, 64)
-
-	summaryExpr := "SET total_expenses = total_expenses - :amount, ending_balance = ending_balance + :amount, updated_at = :now"
+// summary + global balance (and the expense's category total) in a single
+// transaction. The delete is conditioned on old's amount and category
+// (expenseLock) so a concurrent edit between the service's read and the
+// transact surfaces as ErrExpenseStateMismatch. A non-nil trash entry is written in the same
+// transaction, so a deleted expense is never lost between the two.
+func (r *Repository) AtomicDeleteExpense(ctx context.Context, month string, old *model.Expense, trash *model.TrashedExpense) error {
+	pkMonth := AccountPK(ctx, MonthPrefix+month)
+	nowStr := timestamp(time.Now())
 	summaryValues := map[string]types.AttributeValue{
-		":amount": &types.AttributeValueMemberN{Value: oldAmountStr},
-		":now":    &types.AttributeValueMemberS{Value: nowStr},
-	}
-	listValues := map[string]types.AttributeValue{
-		":amount": &types.AttributeValueMemberN{Value: oldAmountStr},
+		":amount": moneyValue(old.Amount),
 		":now":    &types.AttributeValueMemberS{Value: nowStr},
 	}
+	summaryExpr, names := withCategoryTotals(
+		"SET total_expenses_cents = total_expenses_cents - :amount, ending_balance_cents = ending_balance_cents + :amount, updated_at = :now",
+		summaryValues,
+		categoryDeltas(categoryChange{old.Category, -old.Amount}),
+	)
+	listValues := cloneValues(summaryValues)
 
-	_, err := r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
-		TransactItems: []types.TransactWriteItem{
+	items := r.searchIndexItems(ctx, month, old, "", nil)
+	if trash != nil {
+		put, err := r.trashPut(ctx, trash)
+		if err != nil {
+			return err
+		}
+		items = append(items, put)
+	}
+	items, err := r.withAudit(ctx, items, model.AuditExpenseDelete, month, old.SK, ExpenseAuditState(month, old), nil)
+	if err != nil {
+		return err
+	}
+	lockValues := map[string]types.AttributeValue{}
+	lock := expenseLock(old, lockValues)
+	_, err = r.transactWrite(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: append([]types.TransactWriteItem{
 			{Delete: &types.Delete{
 				TableName: aws.String(r.tableName),
 				Key: map[string]types.AttributeValue{
 					"PK": &types.AttributeValueMemberS{Value: pkMonth},
-					"SK": &types.AttributeValueMemberS{Value: expenseID},
-				},
-				ConditionExpression: aws.String("attribute_exists(PK) AND begins_with(SK, :expensePrefix) AND amount = :oldAmount"),
-				ExpressionAttributeValues: map[string]types.AttributeValue{
-					":expensePrefix": &types.AttributeValueMemberS{Value: ExpensePrefix},
-					":oldAmount":     &types.AttributeValueMemberN{Value: oldAmountStr},
+					"SK": &types.AttributeValueMemberS{Value: old.SK},
 				},
+				ConditionExpression:       aws.String(lock),
+				ExpressionAttributeValues: lockValues,
 			}},
 			{Update: &types.Update{
 				TableName: aws.String(r.tableName),
@@ -1342,24 +1693,25 @@ func (r *Repository) AtomicDeleteExpense(ctx context.Context, month string, expe
 				},
 				UpdateExpression:          aws.String(summaryExpr),
 				ConditionExpression:       aws.String("attribute_exists(PK)"),
+				ExpressionAttributeNames:  names,
 				ExpressionAttributeValues: summaryValues,
 			}},
 			{Update: &types.Update{
 				TableName: aws.String(r.tableName),
 				Key: map[string]types.AttributeValue{
-					"PK": &types.AttributeValueMemberS{Value: PKBalance},
+					"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKBalance)},
 					"SK": &types.AttributeValueMemberS{Value: SKBalance},
 				},
 				// Refund: subtract a negative = add the amount.
-				UpdateExpression: aws.String("SET total_balance = if_not_exists(total_balance, :zero) - :neg, updated_at = :now"),
+				UpdateExpression: aws.String("SET total_balance_cents = if_not_exists(total_balance_cents, :zero) - :neg, updated_at = :now"),
 				ExpressionAttributeValues: map[string]types.AttributeValue{
-					":neg":  &types.AttributeValueMemberN{Value: negAmountStr},
+					":neg":  moneyValue(-old.Amount),
 					":zero": &types.AttributeValueMemberN{Value: "0"},
 					":now":  &types.AttributeValueMemberS{Value: nowStr},
 				},
 			}},
-			r.monthListUpdate(month, summaryExpr, listValues),
-		},
+			r.monthListUpdate(ctx, month, summaryExpr, names, listValues),
+		}, items...),
 	})
 	if err != nil {
 		if _, ok := txConditionFailedIndex(err); ok {
@@ -1373,56 +1725,58 @@ func (r *Repository) AtomicDeleteExpense(ctx context.Context, month string, expe
 // AtomicMoveExpenseSameMonth re-dates an expense within one month. The SK
 // encodes the expense timestamp (EXP#<unixnano>#<id>), so a date change to a
 // new day requires deleting the old SK row and putting a new SK row — both in
-// one transaction. The delete is conditioned on
-// `amount = :oldAmount` (the existing optimistic-lock convention; rendered as
-// the shortest round-trip of the value read) → ErrExpenseStateMismatch on a
-// concurrent edit. Any amount/description change rides along: the summary +
-// global balance + MONTHLIST mirror are shifted by the amount delta in the
-// same transaction. When checkBalance && delta > 0 the summary update is
-// conditioned on ending_balance >= :delta → ErrInsufficientBalance.
-func (r *Repository) AtomicMoveExpenseSameMonth(ctx context.Context, month string, oldExpenseID string, newExpense *model.Expense, oldAmount float64, checkBalance bool) error {
-	pkMonth := MonthPrefix + month
+// one transaction. The delete is conditioned on old's amount and category
+// (expenseLock, the existing optimistic-lock convention) →
+// ErrExpenseStateMismatch on a concurrent edit. Any amount, description or
+// category change rides along: the summary + global balance + MONTHLIST
+// mirror are shifted by the amount delta in the same transaction. When checkBalance && delta > 0 the summary update
+// is conditioned on ending_balance >= :delta → ErrInsufficientBalance.
+func (r *Repository) AtomicMoveExpenseSameMonth(ctx context.Context, month string, old, newExpense *model.Expense, checkBalance bool) error {
+	pkMonth := AccountPK(ctx, MonthPrefix+month)
 	newExpense.PK = pkMonth
 	newExpenseItem, err := attributevalue.MarshalMap(newExpense)
 	if err != nil {
 		return fmt.Errorf("failed to marshal expense: %w", err)
 	}
-	nowStr := time.Now().Format(time.RFC3339)
-	// Shortest round-trip of the read value — see AtomicUpdateExpense for why
-	// "%.2f" would orphan legacy unrounded amounts (B7).
-	oldAmountStr := strconv.FormatFloat(oldAmount, 'f', -1, 64)
-	delta := newExpense.Amount - oldAmount
-	deltaStr := fmt.Sprintf("%.2f", delta)
-	negDeltaStr := fmt.Sprintf("%.2f", -delta)
+	nowStr := timestamp(time.Now())
+	delta := newExpense.Amount - old.Amount
 
 	monthCondition := "attribute_exists(PK)"
 	if checkBalance && delta > 0 {
-		monthCondition = "attribute_exists(PK) AND ending_balance >= :delta"
+		monthCondition = "attribute_exists(PK) AND ending_balance_cents >= :delta"
 	}
 
-	summaryExpr := "SET total_expenses = total_expenses + :delta, ending_balance = ending_balance - :delta, updated_at = :now"
 	summaryValues := map[string]types.AttributeValue{
-		":delta": &types.AttributeValueMemberN{Value: deltaStr},
+		":delta": moneyValue(delta),
 		":now":   &types.AttributeValueMemberS{Value: nowStr},
 	}
-	listValues := map[string]types.AttributeValue{
-		":delta": &types.AttributeValueMemberN{Value: deltaStr},
-		":now":   &types.AttributeValueMemberS{Value: nowStr},
+	summaryExpr, names := withCategoryTotals(
+		"SET total_expenses_cents = total_expenses_cents + :delta, ending_balance_cents = ending_balance_cents - :delta, updated_at = :now",
+		summaryValues,
+		categoryDeltas(
+			categoryChange{old.Category, -old.Amount},
+			categoryChange{newExpense.Category, newExpense.Amount},
+		),
+	)
+	listValues := cloneValues(summaryValues)
+
+	items, err := r.withAudit(ctx, r.searchIndexItems(ctx, month, old, month, newExpense),
+		model.AuditExpenseUpdate, month, newExpense.SK, ExpenseAuditState(month, old), ExpenseAuditState(month, newExpense))
+	if err != nil {
+		return err
 	}
-
-	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
-		TransactItems: []types.TransactWriteItem{
+	lockValues := map[string]types.AttributeValue{}
+	lock := expenseLock(old, lockValues)
+	_, err = r.transactWrite(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: append([]types.TransactWriteItem{
 			{Delete: &types.Delete{
 				TableName: aws.String(r.tableName),
 				Key: map[string]types.AttributeValue{
 					"PK": &types.AttributeValueMemberS{Value: pkMonth},
-					"SK": &types.AttributeValueMemberS{Value: oldExpenseID},
-				},
-				ConditionExpression: aws.String("attribute_exists(PK) AND begins_with(SK, :expensePrefix) AND amount = :oldAmount"),
-				ExpressionAttributeValues: map[string]types.AttributeValue{
-					":expensePrefix": &types.AttributeValueMemberS{Value: ExpensePrefix},
-					":oldAmount":     &types.AttributeValueMemberN{Value: oldAmountStr},
+					"SK": &types.AttributeValueMemberS{Value: old.SK},
 				},
+				ConditionExpression:       aws.String(lock),
+				ExpressionAttributeValues: lockValues,
 			}},
 			{Put: &types.Put{
 				TableName: aws.String(r.tableName),
@@ -1436,23 +1790,24 @@ func (r *Repository) AtomicMoveExpenseSameMonth(ctx context.Context, month strin
 				},
 				UpdateExpression:          aws.String(summaryExpr),
 				ConditionExpression:       aws.String(monthCondition),
+				ExpressionAttributeNames:  names,
 				ExpressionAttributeValues: summaryValues,
 			}},
 			{Update: &types.Update{
 				TableName: aws.String(r.tableName),
 				Key: map[string]types.AttributeValue{
-					"PK": &types.AttributeValueMemberS{Value: PKBalance},
+					"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKBalance)},
 					"SK": &types.AttributeValueMemberS{Value: SKBalance},
 				},
-				UpdateExpression: aws.String("SET total_balance = if_not_exists(total_balance, :zero) + :balanceDelta, updated_at = :now"),
+				UpdateExpression: aws.String("SET total_balance_cents = if_not_exists(total_balance_cents, :zero) + :balanceDelta, updated_at = :now"),
 				ExpressionAttributeValues: map[string]types.AttributeValue{
-					":balanceDelta": &types.AttributeValueMemberN{Value: negDeltaStr},
+					":balanceDelta": moneyValue(-delta),
 					":zero":         &types.AttributeValueMemberN{Value: "0"},
 					":now":          &types.AttributeValueMemberS{Value: nowStr},
 				},
 			}},
-			r.monthListUpdate(month, summaryExpr, listValues),
-		},
+			r.monthListUpdate(ctx, month, summaryExpr, names, listValues),
+		}, items...),
 	})
 	if err != nil {
 		if idx, ok := txConditionFailedIndex(err); ok {
@@ -1469,9 +1824,11 @@ func (r *Repository) AtomicMoveExpenseSameMonth(ctx context.Context, month strin
 }
 
 // AtomicMoveExpenseAcrossMonths moves an expense from srcMonth to dstMonth in
-// a single transaction (7 items, well within the 100-item cap):
+// a single transaction (7 items, plus the search-index entries the move
+// re-keys — see searchIndexItems — and the audit entry, within the 100-item
+// cap):
 //
-//	[0] delete old expense in srcMonth (optimistic-lock: amount = :oldAmount)
+//	[0] delete old expense in srcMonth (optimistic-lock: expenseLock)
 //	[1] src summary  -= oldAmount  (total_expenses & ending_balance refund)
 //	[2] src mirror   -= oldAmount
 //	[3] put new expense in dstMonth (new SK encodes the new timestamp)
@@ -1480,6 +1837,10 @@ func (r *Repository) AtomicMoveExpenseSameMonth(ctx context.Context, month strin
 //	[5] dst mirror   += newAmount
 //	[6] BALANCE shifts by (oldAmount - newAmount) only
 //
+// Category totals follow the money: the source's entry for the old category
+// drops by oldAmount and the destination's entry for the new category rises
+// by newAmount, on the same summary and mirror updates.
+//
 // A failed delete condition (index 0) surfaces as ErrExpenseStateMismatch.
 // Both months' mirror rows must already exist (caller back-fills via
 // EnsureMonthListMirror) — the mirror updates are conditional deltas.
@@ -1491,34 +1852,38 @@ func (r *Repository) AtomicMoveExpenseSameMonth(ctx context.Context, month strin
 // balance is the wrong question when the refund is about to arrive, and it
 // refused moves that net to zero across the chain (e.g. moving an expense
 // forward a month unchanged, which cannot alter any balance).
-func (r *Repository) AtomicMoveExpenseAcrossMonths(ctx context.Context, srcMonth, dstMonth, oldExpenseID string, newExpense *model.Expense, oldAmount float64, checkBalance bool, srcRefundReachesDst bool) error {
-	pkSrc := MonthPrefix + srcMonth
-	pkDst := MonthPrefix + dstMonth
+func (r *Repository) AtomicMoveExpenseAcrossMonths(ctx context.Context, srcMonth, dstMonth string, old, newExpense *model.Expense, checkBalance bool, srcRefundReachesDst bool) error {
+	pkSrc := AccountPK(ctx, MonthPrefix+srcMonth)
+	pkDst := AccountPK(ctx, MonthPrefix+dstMonth)
 	newExpense.PK = pkDst
 	newExpenseItem, err := attributevalue.MarshalMap(newExpense)
 	if err != nil {
 		return fmt.Errorf("failed to marshal expense: %w", err)
 	}
-	nowStr := time.Now().Format(time.RFC3339)
-	oldAmountStr := strconv.FormatFloat(oldAmount, 'f', -1, 64)
-	newAmountStr := fmt.Sprintf("%.2f", newExpense.Amount)
-	balanceDeltaStr := fmt.Sprintf("%.2f", oldAmount-newExpense.Amount)
+	nowStr := timestamp(time.Now())
 
-	srcSummaryExpr := "SET total_expenses = total_expenses - :oldAmount, ending_balance = ending_balance + :oldAmount, updated_at = :now"
 	srcValues := map[string]types.AttributeValue{
-		":oldAmount": &types.AttributeValueMemberN{Value: oldAmountStr},
-		":now":       &types.AttributeValueMemberS{Value: nowStr},
-	}
-	srcListValues := map[string]types.AttributeValue{
-		":oldAmount": &types.AttributeValueMemberN{Value: oldAmountStr},
+		":oldAmount": moneyValue(old.Amount),
 		":now":       &types.AttributeValueMemberS{Value: nowStr},
 	}
+	srcSummaryExpr, srcNames := withCategoryTotals(
+		"SET total_expenses_cents = total_expenses_cents - :oldAmount, ending_balance_cents = ending_balance_cents + :oldAmount, updated_at = :now",
+		srcValues,
+		categoryDeltas(categoryChange{old.Category, -old.Amount}),
+	)
+	srcListValues := cloneValues(srcValues)
 
 	dstCondition := "attribute_exists(PK)"
 	dstValues := map[string]types.AttributeValue{
-		":newAmount": &types.AttributeValueMemberN{Value: newAmountStr},
+		":newAmount": moneyValue(newExpense.Amount),
 		":now":       &types.AttributeValueMemberS{Value: nowStr},
 	}
+	dstSummaryExpr, dstNames := withCategoryTotals(
+		"SET total_expenses_cents = total_expenses_cents + :newAmount, ending_balance_cents = ending_balance_cents - :newAmount, updated_at = :now",
+		dstValues,
+		categoryDeltas(categoryChange{newExpense.Category, newExpense.Amount}),
+	)
+	dstListValues := cloneValues(dstValues)
 	if checkBalance {
 		// The threshold is computed here rather than in the expression, because
 		// a ConditionExpression cannot do arithmetic on its right-hand side.
@@ -1526,32 +1891,29 @@ func (r *Repository) AtomicMoveExpenseAcrossMonths(ctx context.Context, srcMonth
 		// the charge, so any non-negative balance satisfies it.
 		threshold := newExpense.Amount
 		if srcRefundReachesDst {
-			threshold -= oldAmount
-		}
-		dstCondition = "attribute_exists(PK) AND ending_balance >= :minDstEnding"
-		dstValues[":minDstEnding"] = &types.AttributeValueMemberN{
-			Value: fmt.Sprintf("%.2f", threshold),
+			threshold -= old.Amount
 		}
-	}
-	dstSummaryExpr := "SET total_expenses = total_expenses + :newAmount, ending_balance = ending_balance - :newAmount, updated_at = :now"
-	dstListValues := map[string]types.AttributeValue{
-		":newAmount": &types.AttributeValueMemberN{Value: newAmountStr},
-		":now":       &types.AttributeValueMemberS{Value: nowStr},
+		dstCondition = "attribute_exists(PK) AND ending_balance_cents >= :minDstEnding"
+		dstValues[":minDstEnding"] = moneyValue(threshold)
 	}
 
-	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
-		TransactItems: []types.TransactWriteItem{
+	items, err := r.withAudit(ctx, r.searchIndexItems(ctx, srcMonth, old, dstMonth, newExpense),
+		model.AuditExpenseUpdate, dstMonth, newExpense.SK, ExpenseAuditState(srcMonth, old), ExpenseAuditState(dstMonth, newExpense))
+	if err != nil {
+		return err
+	}
+	lockValues := map[string]types.AttributeValue{}
+	lock := expenseLock(old, lockValues)
+	_, err = r.transactWrite(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: append([]types.TransactWriteItem{
 			{Delete: &types.Delete{
 				TableName: aws.String(r.tableName),
 				Key: map[string]types.AttributeValue{
 					"PK": &types.AttributeValueMemberS{Value: pkSrc},
-					"SK": &types.AttributeValueMemberS{Value: oldExpenseID},
-				},
-				ConditionExpression: aws.String("attribute_exists(PK) AND begins_with(SK, :expensePrefix) AND amount = :oldAmount"),
-				ExpressionAttributeValues: map[string]types.AttributeValue{
-					":expensePrefix": &types.AttributeValueMemberS{Value: ExpensePrefix},
-					":oldAmount":     &types.AttributeValueMemberN{Value: oldAmountStr},
+					"SK": &types.AttributeValueMemberS{Value: old.SK},
 				},
+				ConditionExpression:       aws.String(lock),
+				ExpressionAttributeValues: lockValues,
 			}},
 			{Update: &types.Update{
 				TableName: aws.String(r.tableName),
@@ -1561,9 +1923,10 @@ func (r *Repository) AtomicMoveExpenseAcrossMonths(ctx context.Context, srcMonth
 				},
 				UpdateExpression:          aws.String(srcSummaryExpr),
 				ConditionExpression:       aws.String("attribute_exists(PK)"),
+				ExpressionAttributeNames:  srcNames,
 				ExpressionAttributeValues: srcValues,
 			}},
-			r.monthListUpdate(srcMonth, srcSummaryExpr, srcListValues),
+			r.monthListUpdate(ctx, srcMonth, srcSummaryExpr, srcNames, srcListValues),
 			{Put: &types.Put{
 				TableName: aws.String(r.tableName),
 				Item:      newExpenseItem,
@@ -1576,23 +1939,24 @@ func (r *Repository) AtomicMoveExpenseAcrossMonths(ctx context.Context, srcMonth
 				},
 				UpdateExpression:          aws.String(dstSummaryExpr),
 				ConditionExpression:       aws.String(dstCondition),
+				ExpressionAttributeNames:  dstNames,
 				ExpressionAttributeValues: dstValues,
 			}},
-			r.monthListUpdate(dstMonth, dstSummaryExpr, dstListValues),
+			r.monthListUpdate(ctx, dstMonth, dstSummaryExpr, dstNames, dstListValues),
 			{Update: &types.Update{
 				TableName: aws.String(r.tableName),
 				Key: map[string]types.AttributeValue{
-					"PK": &types.AttributeValueMemberS{Value: PKBalance},
+					"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKBalance)},
 					"SK": &types.AttributeValueMemberS{Value: SKBalance},
 				},
-				UpdateExpression: aws.String("SET total_balance = if_not_exists(total_balance, :zero) + :balanceDelta, updated_at = :now"),
+				UpdateExpression: aws.String("SET total_balance_cents = if_not_exists(total_balance_cents, :zero) + :balanceDelta, updated_at = :now"),
 				ExpressionAttributeValues: map[string]types.AttributeValue{
-					":balanceDelta": &types.AttributeValueMemberN{Value: balanceDeltaStr},
+					":balanceDelta": moneyValue(old.Amount - newExpense.Amount),
 					":zero":         &types.AttributeValueMemberN{Value: "0"},
 					":now":          &types.AttributeValueMemberS{Value: nowStr},
 				},
 			}},
-		},
+		}, items...),
 	})
 	if err != nil {
 		if idx, ok := txConditionFailedIndex(err); ok {
@@ -1612,8 +1976,8 @@ func (r *Repository) AtomicMoveExpenseAcrossMonths(ctx context.Context, srcMonth
 // balance by the allowance amount in a single transaction. The put is
 // conditioned on `attribute_not_exists(PK)` so concurrent creates can't
 // both succeed → ErrMonthAlreadyExists on the loser.
-func (r *Repository) AtomicCreateMonth(ctx context.Context, summary *model.MonthSummary, allowance float64) error {
-	summary.PK = MonthPrefix + summary.Month
+func (r *Repository) AtomicCreateMonth(ctx context.Context, summary *model.MonthSummary, allowance model.Money) error {
+	summary.PK = AccountPK(ctx, MonthPrefix+summary.Month)
 	summary.SK = SKSummary
 	summary.UpdatedAt = time.Now()
 	if summary.CreatedAt.IsZero() {
@@ -1623,15 +1987,18 @@ func (r *Repository) AtomicCreateMonth(ctx context.Context, summary *model.Month
 	if err != nil {
 		return fmt.Errorf("failed to marshal month summary: %w", err)
 	}
-	nowStr := time.Now().Format(time.RFC3339)
-	allowanceStr := fmt.Sprintf("%.2f", allowance)
+	nowStr := timestamp(time.Now())
 
-	listPut, err := r.monthListPut(summary)
+	listPut, err := r.monthListPut(ctx, summary)
+	if err != nil {
+		return err
+	}
+	audit, err := r.auditPut(NewAuditEntry(ctx, model.AuditMonthCreate, summary.Month, "", nil, MonthAuditState(summary)))
 	if err != nil {
 		return err
 	}
 
-	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+	_, err = r.transactWrite(ctx, &dynamodb.TransactWriteItemsInput{
 		TransactItems: []types.TransactWriteItem{
 			{Put: &types.Put{
 				TableName:           aws.String(r.tableName),
@@ -1641,17 +2008,18 @@ func (r *Repository) AtomicCreateMonth(ctx context.Context, summary *model.Month
 			{Update: &types.Update{
 				TableName: aws.String(r.tableName),
 				Key: map[string]types.AttributeValue{
-					"PK": &types.AttributeValueMemberS{Value: PKBalance},
+					"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKBalance)},
 					"SK": &types.AttributeValueMemberS{Value: SKBalance},
 				},
-				UpdateExpression: aws.String("SET total_balance = if_not_exists(total_balance, :zero) + :delta, updated_at = :now"),
+				UpdateExpression: aws.String("SET total_balance_cents = if_not_exists(total_balance_cents, :zero) + :delta, updated_at = :now"),
 				ExpressionAttributeValues: map[string]types.AttributeValue{
-					":delta": &types.AttributeValueMemberN{Value: allowanceStr},
+					":delta": moneyValue(allowance),
 					":zero":  &types.AttributeValueMemberN{Value: "0"},
 					":now":   &types.AttributeValueMemberS{Value: nowStr},
 				},
 			}},
 			listPut,
+			audit,
 		},
 	})
 	if err != nil {
@@ -1666,50 +2034,37 @@ func (r *Repository) AtomicCreateMonth(ctx context.Context, summary *model.Month
 // AtomicAddFunds credits the month summary and the global balance by the
 // same amount in a single transaction. The month summary update is
 // conditioned on attribute_exists(PK) — returns a wrapped error if the
-// month doesn't exist (caller maps to ErrMonthNotFound via lookup).
-func (r *Repository) AtomicAddFunds(ctx context.Context, month string, amount float64) error {
-	pkMonth := MonthPrefix + month
-	nowStr := time.Now().Format(time.RFC3339)
-	amountStr := fmt.Sprintf("%.2f", amount)
-
-	summaryExpr := "SET allowance_added = allowance_added + :amount, ending_balance = ending_balance + :amount, updated_at = :now"
-	summaryValues := map[string]types.AttributeValue{
-		":amount": &types.AttributeValueMemberN{Value: amountStr},
-		":now":    &types.AttributeValueMemberS{Value: nowStr},
-	}
-	listValues := map[string]types.AttributeValue{
-		":amount": &types.AttributeValueMemberN{Value: amountStr},
-		":now":    &types.AttributeValueMemberS{Value: nowStr},
+// month doesn't exist (caller maps to ErrMonthNotFound via lookup). entry,
+// when non-nil, is the itemized credit for a top-up, written in the same
+// transaction; its Amount must be amount. The monthly allowance is credited
+// without one, and adds to the month's base_allowance instead.
+func (r *Repository) AtomicAddFunds(ctx context.Context, month string, amount model.Money, entry *model.FundEntry) error {
+	items := r.fundDeltaItems(ctx, month, amount, false)
+	target, after := "", &model.AuditState{Amount: &amount}
+	if entry == nil {
+		summary := items[0].Update
+		summary.UpdateExpression = aws.String(*summary.UpdateExpression +
+			", base_allowance_cents = if_not_exists(base_allowance_cents, :zero) + :delta")
+		summary.ExpressionAttributeValues[":zero"] = moneyValue(0)
+	} else {
+		entry.PK = AccountPK(ctx, MonthPrefix+month)
+		item, err := attributevalue.MarshalMap(entry)
+		if err != nil {
+			return fmt.Errorf("failed to marshal funds entry: %w", err)
+		}
+		items = append(items, types.TransactWriteItem{Put: &types.Put{
+			TableName:           aws.String(r.tableName),
+			Item:                item,
+			ConditionExpression: aws.String("attribute_not_exists(PK)"),
+		}})
+		target, after = entry.SK, FundAuditState(month, entry)
+	}
+	items, err := r.withAudit(ctx, items, model.AuditFundsAdd, month, target, nil, after)
+	if err != nil {
+		return err
 	}
 
-	_, err := r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
-		TransactItems: []types.TransactWriteItem{
-			{Update: &types.Update{
-				TableName: aws.String(r.tableName),
-				Key: map[string]types.AttributeValue{
-					"PK": &types.AttributeValueMemberS{Value: pkMonth},
-					"SK": &types.AttributeValueMemberS{Value: SKSummary},
-				},
-				UpdateExpression:          aws.String(summaryExpr),
-				ConditionExpression:       aws.String("attribute_exists(PK)"),
-				ExpressionAttributeValues: summaryValues,
-			}},
-			{Update: &types.Update{
-				TableName: aws.String(r.tableName),
-				Key: map[string]types.AttributeValue{
-					"PK": &types.AttributeValueMemberS{Value: PKBalance},
-					"SK": &types.AttributeValueMemberS{Value: SKBalance},
-				},
-				UpdateExpression: aws.String("SET total_balance = if_not_exists(total_balance, :zero) + :delta, updated_at = :now"),
-				ExpressionAttributeValues: map[string]types.AttributeValue{
-					":delta": &types.AttributeValueMemberN{Value: amountStr},
-					":zero":  &types.AttributeValueMemberN{Value: "0"},
-					":now":   &types.AttributeValueMemberS{Value: nowStr},
-				},
-			}},
-			r.monthListUpdate(month, summaryExpr, listValues),
-		},
-	})
+	_, err = r.transactWrite(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
 	if err != nil {
 		if _, ok := txConditionFailedIndex(err); ok {
 			// Month summary doesn't exist (or some other conditional). The
@@ -1722,6 +2077,50 @@ func (r *Repository) AtomicAddFunds(ctx context.Context, month string, amount fl
 	return nil
 }
 
+// AtomicWithdrawFunds is AtomicAddFunds in reverse: it debits the month's
+// allowance_added and ending_balance and the global balance by the amount
+// entry takes back, and writes entry, the itemized withdrawal (negative
+// Amount), in one transaction. The month update is conditioned on the
+// month existing and holding at least that amount of allowance on top of
+// its chore earnings — a withdrawal takes back money that was granted,
+// never more and never a reward — with earnings_added pinned to earnings,
+// the figure the caller read, and, when checkBalance is true, on
+// ending_balance >= amount. Any of those failing returns
+// ErrExpenseStateMismatch; the caller re-reads the month to tell which.
+func (r *Repository) AtomicWithdrawFunds(ctx context.Context, month string, entry *model.FundEntry, earnings model.Money, checkBalance bool) error {
+	amount := -entry.Amount
+	items := r.fundDeltaItems(ctx, month, entry.Amount, checkBalance)
+	summary := items[0].Update
+	summary.ConditionExpression = aws.String(*summary.ConditionExpression +
+		" AND allowance_added_cents >= :floor AND (attribute_not_exists(earnings_added_cents) OR earnings_added_cents = :earnings)")
+	summary.ExpressionAttributeValues[":floor"] = moneyValue(amount + earnings)
+	summary.ExpressionAttributeValues[":earnings"] = moneyValue(earnings)
+
+	entry.PK = AccountPK(ctx, MonthPrefix+month)
+	item, err := attributevalue.MarshalMap(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal funds entry: %w", err)
+	}
+	items = append(items, types.TransactWriteItem{Put: &types.Put{
+		TableName:           aws.String(r.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(PK)"),
+	}})
+	items, err = r.withAudit(ctx, items, model.AuditFundsWithdraw, month, entry.SK, nil, &model.AuditState{Amount: &amount})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.transactWrite(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+	if err != nil {
+		if _, ok := txConditionFailedIndex(err); ok {
+			return ErrExpenseStateMismatch
+		}
+		return fmt.Errorf("failed to withdraw funds atomically: %w", err)
+	}
+	return nil
+}
+
 // maxTransactItems is DynamoDB's hard cap of 100 items per
 // TransactWriteItems call. PropagateLaterMonthDeltas chunks against it.
 const maxTransactItems = 100
@@ -1749,16 +2148,15 @@ const maxTransactItems = 100
 // lost. Because the updates are now composable deltas (not snapshots),
 // re-running propagation would re-apply — so callers must not retry this
 // blindly; recovery is a one-shot recompute, out of scope here.
-func (r *Repository) PropagateLaterMonthDeltas(ctx context.Context, months []string, delta float64) error {
+func (r *Repository) PropagateLaterMonthDeltas(ctx context.Context, months []string, delta model.Money) error {
 	if len(months) == 0 || delta == 0 {
 		return nil
 	}
-	nowStr := time.Now().Format(time.RFC3339)
-	deltaStr := strconv.FormatFloat(delta, 'f', -1, 64)
+	nowStr := timestamp(time.Now())
 	// if_not_exists guards: very old rows (pre-carry-over app versions) may
 	// lack starting_balance/ending_balance, and DynamoDB rejects arithmetic
 	// on a missing attribute, which would cancel the whole transaction.
-	expr := aws.String("SET starting_balance = if_not_exists(starting_balance, :zero) + :d, ending_balance = if_not_exists(ending_balance, :zero) + :d, updated_at = :now")
+	expr := aws.String("SET starting_balance_cents = if_not_exists(starting_balance_cents, :zero) + :d, ending_balance_cents = if_not_exists(ending_balance_cents, :zero) + :d, updated_at = :now")
 
 	// Two transact items per month → chunk so 2*chunk <= maxTransactItems.
 	const monthsPerChunk = maxTransactItems / 2
@@ -1770,14 +2168,14 @@ func (r *Repository) PropagateLaterMonthDeltas(ctx context.Context, months []str
 		items := make([]types.TransactWriteItem, 0, (end-start)*2)
 		for _, m := range months[start:end] {
 			values := map[string]types.AttributeValue{
-				":d":    &types.AttributeValueMemberN{Value: deltaStr},
+				":d":    moneyValue(delta),
 				":zero": &types.AttributeValueMemberN{Value: "0"},
 				":now":  &types.AttributeValueMemberS{Value: nowStr},
 			}
 			items = append(items, types.TransactWriteItem{Update: &types.Update{
 				TableName: aws.String(r.tableName),
 				Key: map[string]types.AttributeValue{
-					"PK": &types.AttributeValueMemberS{Value: MonthPrefix + m},
+					"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, MonthPrefix+m)},
 					"SK": &types.AttributeValueMemberS{Value: SKSummary},
 				},
 				UpdateExpression:          expr,
@@ -1787,7 +2185,7 @@ func (r *Repository) PropagateLaterMonthDeltas(ctx context.Context, months []str
 			items = append(items, types.TransactWriteItem{Update: &types.Update{
 				TableName: aws.String(r.tableName),
 				Key: map[string]types.AttributeValue{
-					"PK": &types.AttributeValueMemberS{Value: PKMonthList},
+					"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKMonthList)},
 					"SK": &types.AttributeValueMemberS{Value: m},
 				},
 				UpdateExpression:          expr,
@@ -1795,7 +2193,7 @@ func (r *Repository) PropagateLaterMonthDeltas(ctx context.Context, months []str
 				ExpressionAttributeValues: values,
 			}})
 		}
-		_, err := r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		_, err := r.transactWrite(ctx, &dynamodb.TransactWriteItemsInput{
 			TransactItems: items,
 		})
 		if err != nil {
@@ -1814,7 +2212,7 @@ func (r *Repository) PropagateLaterMonthDeltas(ctx context.Context, months []str
 // global balance credit happens here — an auto-created month carries $0
 // allowance.
 func (r *Repository) CreateMonthSummaryIfAbsent(ctx context.Context, summary *model.MonthSummary) error {
-	summary.PK = MonthPrefix + summary.Month
+	summary.PK = AccountPK(ctx, MonthPrefix+summary.Month)
 	summary.SK = SKSummary
 	summary.UpdatedAt = time.Now()
 	if summary.CreatedAt.IsZero() {
@@ -1824,12 +2222,16 @@ func (r *Repository) CreateMonthSummaryIfAbsent(ctx context.Context, summary *mo
 	if err != nil {
 		return fmt.Errorf("failed to marshal month summary: %w", err)
 	}
-	listItem, err := monthListItem(summary)
+	listItem, err := monthListItem(ctx, summary)
+	if err != nil {
+		return err
+	}
+	audit, err := r.auditPut(NewAuditEntry(ctx, model.AuditMonthCreate, summary.Month, "", nil, MonthAuditState(summary)))
 	if err != nil {
 		return err
 	}
 
-	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+	_, err = r.transactWrite(ctx, &dynamodb.TransactWriteItemsInput{
 		TransactItems: []types.TransactWriteItem{
 			{Put: &types.Put{
 				TableName:           aws.String(r.tableName),
@@ -1837,6 +2239,7 @@ func (r *Repository) CreateMonthSummaryIfAbsent(ctx context.Context, summary *mo
 				ConditionExpression: aws.String("attribute_not_exists(PK)"),
 			}},
 			{Put: &types.Put{TableName: aws.String(r.tableName), Item: listItem}},
+			audit,
 		},
 	})
 	if err != nil {
@@ -1856,55 +2259,71 @@ func (r *Repository) CreateMonthSummaryIfAbsent(ctx context.Context, summary *mo
 // being debited cannot have changed since the caller read it. Any of the three
 // failing surfaces as ErrMonthHasExpenses — DynamoDB reports only WHICH item's
 // condition failed, not which clause — so the caller re-reads to say which.
-// allowanceAdded is formatted as the shortest round-trip of the value read from
-// the summary.
-func (r *Repository) AtomicDeleteMonth(ctx context.Context, month string, allowanceAdded float64) error {
-	pkMonth := MonthPrefix + month
-	nowStr := time.Now().Format(time.RFC3339)
-	allowanceStr := strconv.FormatFloat(allowanceAdded, 'f', -1, 64)
-
-	_, err := r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
-		TransactItems: []types.TransactWriteItem{
-			{Delete: &types.Delete{
-				TableName: aws.String(r.tableName),
-				Key: map[string]types.AttributeValue{
-					"PK": &types.AttributeValueMemberS{Value: pkMonth},
-					"SK": &types.AttributeValueMemberS{Value: SKSummary},
-				},
-				// allowance_added is pinned as well as total_expenses. The caller
-				// pre-reads the summary and hands its allowance figure to the
-				// balance debit below; without this guard a top-up landing in
-				// between made that figure stale, so the month row vanished while
-				// the global balance kept the difference — permanent drift, since
-				// nothing recomputes the balance afterwards.
-				ConditionExpression: aws.String(
-					"attribute_exists(PK) AND total_expenses = :zero AND allowance_added = :allowance"),
-				ExpressionAttributeValues: map[string]types.AttributeValue{
-					":zero":      &types.AttributeValueMemberN{Value: "0"},
-					":allowance": &types.AttributeValueMemberN{Value: allowanceStr},
-				},
-			}},
-			{Delete: &types.Delete{
-				TableName: aws.String(r.tableName),
-				Key: map[string]types.AttributeValue{
-					"PK": &types.AttributeValueMemberS{Value: PKMonthList},
-					"SK": &types.AttributeValueMemberS{Value: month},
-				},
-			}},
-			{Update: &types.Update{
-				TableName: aws.String(r.tableName),
-				Key: map[string]types.AttributeValue{
-					"PK": &types.AttributeValueMemberS{Value: PKBalance},
-					"SK": &types.AttributeValueMemberS{Value: SKBalance},
-				},
-				UpdateExpression: aws.String("SET total_balance = if_not_exists(total_balance, :zero) - :allowance, updated_at = :now"),
-				ExpressionAttributeValues: map[string]types.AttributeValue{
-					":allowance": &types.AttributeValueMemberN{Value: allowanceStr},
-					":zero":      &types.AttributeValueMemberN{Value: "0"},
-					":now":       &types.AttributeValueMemberS{Value: nowStr},
-				},
-			}},
-		},
+// The month's funds credits (fundIDs) are deleted with it; they need no
+// condition of their own, as adding or removing one moves allowance_added.
+func (r *Repository) AtomicDeleteMonth(ctx context.Context, month string, allowanceAdded model.Money, fundIDs []string) error {
+	pkMonth := AccountPK(ctx, MonthPrefix+month)
+	nowStr := timestamp(time.Now())
+	if len(fundIDs)+4 > maxTransactItems {
+		return fmt.Errorf("month %s has too many funds entries (%d) to delete in one transaction", month, len(fundIDs))
+	}
+	audit, err := r.auditPut(NewAuditEntry(ctx, model.AuditMonthDelete, month, "", &model.AuditState{AllowanceAdded: &allowanceAdded}, nil))
+	if err != nil {
+		return err
+	}
+
+	items := []types.TransactWriteItem{
+		{Delete: &types.Delete{
+			TableName: aws.String(r.tableName),
+			Key: map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberS{Value: pkMonth},
+				"SK": &types.AttributeValueMemberS{Value: SKSummary},
+			},
+			// allowance_added is pinned as well as total_expenses. The caller
+			// pre-reads the summary and hands its allowance figure to the
+			// balance debit below; without this guard a top-up landing in
+			// between made that figure stale, so the month row vanished while
+			// the global balance kept the difference — permanent drift, since
+			// nothing recomputes the balance afterwards.
+			ConditionExpression: aws.String(
+				"attribute_exists(PK) AND total_expenses_cents = :zero AND allowance_added_cents = :allowance"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":zero":      &types.AttributeValueMemberN{Value: "0"},
+				":allowance": moneyValue(allowanceAdded),
+			},
+		}},
+		{Delete: &types.Delete{
+			TableName: aws.String(r.tableName),
+			Key: map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKMonthList)},
+				"SK": &types.AttributeValueMemberS{Value: month},
+			},
+		}},
+		{Update: &types.Update{
+			TableName: aws.String(r.tableName),
+			Key: map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKBalance)},
+				"SK": &types.AttributeValueMemberS{Value: SKBalance},
+			},
+			UpdateExpression: aws.String("SET total_balance_cents = if_not_exists(total_balance_cents, :zero) - :allowance, updated_at = :now"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":allowance": moneyValue(allowanceAdded),
+				":zero":      &types.AttributeValueMemberN{Value: "0"},
+				":now":       &types.AttributeValueMemberS{Value: nowStr},
+			},
+		}},
+	}
+	for _, id := range fundIDs {
+		items = append(items, types.TransactWriteItem{Delete: &types.Delete{
+			TableName: aws.String(r.tableName),
+			Key: map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberS{Value: pkMonth},
+				"SK": &types.AttributeValueMemberS{Value: id},
+			},
+		}})
+	}
+	_, err = r.transactWrite(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: append(items, audit),
 	})
 	if err != nil {
 		if idx, ok := txConditionFailedIndex(err); ok && idx == 0 {
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vppillai/passbook/backend/internal/model"
+)
+
+// FundPrefix starts the SK of an itemized funds credit, which lives in its
+// month's partition next to the expenses: SK="FUND#<unixnano>#<id>".
+const FundPrefix = "FUND#"
+
+// InterestFundSK is the SK of a month's interest credit. It is fixed, so a
+// month is credited interest at most once, and its zero timestamp lists it
+// before the month's other credits.
+const InterestFundSK = FundPrefix + "0#interest"
+
+// FundAuditState is the journal's view of funds credit e in month.
+func FundAuditState(month string, e *model.FundEntry) *model.AuditState {
+	amount := e.Amount
+	return &model.AuditState{Month: month, Amount: &amount, Description: e.Description}
+}
+
+// GetFundEntries returns every funds credit of a month, oldest first.
+func (r *Repository) GetFundEntries(ctx context.Context, month string) ([]model.FundEntry, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :prefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":     &types.AttributeValueMemberS{Value: AccountPK(ctx, MonthPrefix+month)},
+			":prefix": &types.AttributeValueMemberS{Value: FundPrefix},
+		},
+	}
+	var entries []model.FundEntry
+	for {
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get funds entries: %w", err)
+		}
+		var page []model.FundEntry
+		if err := unmarshalItems(result.Items, &page); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal funds entries: %w", err)
+		}
+		entries = append(entries, page...)
+		if result.LastEvaluatedKey == nil {
+			return entries, nil
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
+
+// GetFundEntry returns one funds credit, or nil when there is none.
+func (r *Repository) GetFundEntry(ctx context.Context, month, id string) (*model.FundEntry, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, MonthPrefix+month)},
+			"SK": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get funds entry: %w", err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+	var entry model.FundEntry
+	if err := unmarshalItem(result.Item, &entry); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal funds entry: %w", err)
+	}
+	return &entry, nil
+}
+
+// fundDeltaItems moves a month's allowance_added and ending_balance, its
+// mirror and the global balance by delta — the same three updates
+// AtomicAddFunds and AtomicWithdrawFunds make. A debit is conditioned like a
+// withdrawal: the month must hold at least that much allowance and, when
+// checkBalance is true, that much ending balance.
+func (r *Repository) fundDeltaItems(ctx context.Context, month string, delta model.Money, checkBalance bool) []types.TransactWriteItem {
+	return r.creditDeltaItems(ctx, month, delta, 0, checkBalance)
+}
+
+// entryDeltaItems is fundDeltaItems for a change of delta to funds credit
+// e, which also moves earnings_added when e is a chore's reward.
+func (r *Repository) entryDeltaItems(ctx context.Context, month string, e *model.FundEntry, delta model.Money, checkBalance bool) []types.TransactWriteItem {
+	var earned model.Money
+	if e.Kind == model.FundKindEarning {
+		earned = delta
+	}
+	return r.creditDeltaItems(ctx, month, delta, earned, checkBalance)
+}
+
+// creditDeltaItems is fundDeltaItems moving the month's earnings_added by
+// earned as well, the part of delta that chores earned.
+func (r *Repository) creditDeltaItems(ctx context.Context, month string, delta, earned model.Money, checkBalance bool) []types.TransactWriteItem {
+	nowStr := timestamp(time.Now())
+	summaryExpr := "SET allowance_added_cents = allowance_added_cents + :delta, ending_balance_cents = ending_balance_cents + :delta, updated_at = :now"
+	condition := "attribute_exists(PK)"
+	summaryValues := map[string]types.AttributeValue{
+		":delta": moneyValue(delta),
+		":now":   &types.AttributeValueMemberS{Value: nowStr},
+	}
+	listValues := map[string]types.AttributeValue{
+		":delta": moneyValue(delta),
+		":now":   &types.AttributeValueMemberS{Value: nowStr},
+	}
+	if earned != 0 {
+		summaryExpr += ", earnings_added_cents = if_not_exists(earnings_added_cents, :zero) + :earned"
+		for _, values := range []map[string]types.AttributeValue{summaryValues, listValues} {
+			values[":earned"] = moneyValue(earned)
+			values[":zero"] = moneyValue(0)
+		}
+	}
+	if delta < 0 {
+		condition += " AND allowance_added_cents >= :debit"
+		if checkBalance {
+			condition += " AND ending_balance_cents >= :debit"
+		}
+		summaryValues[":debit"] = moneyValue(-delta)
+	}
+	return []types.TransactWriteItem{
+		{Update: &types.Update{
+			TableName: aws.String(r.tableName),
+			Key: map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, MonthPrefix+month)},
+				"SK": &types.AttributeValueMemberS{Value: SKSummary},
+			},
+			UpdateExpression:          aws.String(summaryExpr),
+			ConditionExpression:       aws.String(condition),
+			ExpressionAttributeValues: summaryValues,
+		}},
+		{Update: &types.Update{
+			TableName: aws.String(r.tableName),
+			Key: map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKBalance)},
+				"SK": &types.AttributeValueMemberS{Value: SKBalance},
+			},
+			UpdateExpression: aws.String("SET total_balance_cents = if_not_exists(total_balance_cents, :zero) + :delta, updated_at = :now"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":delta": moneyValue(delta),
+				":zero":  &types.AttributeValueMemberN{Value: "0"},
+				":now":   &types.AttributeValueMemberS{Value: nowStr},
+			},
+		}},
+		r.monthListUpdate(ctx, month, summaryExpr, nil, listValues),
+	}
+}
+
+// AtomicUpdateFundEntry rewrites a funds credit and moves the month, its
+// mirror and the global balance by the change in amount, in one journaled
+// transaction. The rewrite is conditioned on the entry still holding
+// old.Amount → ErrExpenseStateMismatch; a debit the month cannot cover (see
+// fundDeltaItems) → ErrInsufficientBalance.
+func (r *Repository) AtomicUpdateFundEntry(ctx context.Context, month string, old, updated *model.FundEntry, checkBalance bool) error {
+	updated.PK = AccountPK(ctx, MonthPrefix+month)
+	item, err := attributevalue.MarshalMap(updated)
+	if err != nil {
+		return fmt.Errorf("failed to marshal funds entry: %w", err)
+	}
+	items := []types.TransactWriteItem{
+		{Put: &types.Put{
+			TableName:           aws.String(r.tableName),
+			Item:                item,
+			ConditionExpression: aws.String("amount_cents = :old"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":old": moneyValue(old.Amount),
+			},
+		}},
+	}
+	if delta := updated.Amount - old.Amount; delta != 0 {
+		items = append(items, r.entryDeltaItems(ctx, month, old, delta, checkBalance)...)
+	}
+	items, err = r.withAudit(ctx, items, model.AuditFundsUpdate, month, updated.SK, FundAuditState(month, old), FundAuditState(month, updated))
+	if err != nil {
+		return err
+	}
+	return r.transactFundEntry(ctx, items, "update")
+}
+
+// AtomicDeleteFundEntry removes a funds credit and takes its amount back out
+// of the month, its mirror and the global balance, like a withdrawal, in one
+// journaled transaction. Errors as AtomicUpdateFundEntry.
+func (r *Repository) AtomicDeleteFundEntry(ctx context.Context, month string, old *model.FundEntry, checkBalance bool) error {
+	items := []types.TransactWriteItem{
+		{Delete: &types.Delete{
+			TableName: aws.String(r.tableName),
+			Key: map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, MonthPrefix+month)},
+				"SK": &types.AttributeValueMemberS{Value: old.SK},
+			},
+			ConditionExpression: aws.String("amount_cents = :old"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":old": moneyValue(old.Amount),
+			},
+		}},
+	}
+	items = append(items, r.entryDeltaItems(ctx, month, old, -old.Amount, checkBalance)...)
+	items, err := r.withAudit(ctx, items, model.AuditFundsDelete, month, old.SK, FundAuditState(month, old), nil)
+	if err != nil {
+		return err
+	}
+	return r.transactFundEntry(ctx, items, "delete")
+}
+
+func (r *Repository) transactFundEntry(ctx context.Context, items []types.TransactWriteItem, op string) error {
+	_, err := r.transactWrite(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+	if err != nil {
+		if idx, ok := txConditionFailedIndex(err); ok {
+			if idx == 0 {
+				return ErrExpenseStateMismatch
+			}
+			return ErrInsufficientBalance
+		}
+		return fmt.Errorf("failed to %s funds entry atomically: %w", op, err)
+	}
+	return nil
+}
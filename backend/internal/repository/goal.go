@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vppillai/passbook/backend/internal/model"
+)
+
+// Savings-goal storage keys. Goals share one partition (PK="GOALS",
+// SK="GOAL#<id>") so listing them is a single Query, like RECURRING.
+const (
+	PKGoals    = "GOALS"
+	GoalPrefix = "GOAL#"
+)
+
+// ErrGoalNotFound is returned by UpdateGoal when the goal does not exist
+// (never created, or deleted concurrently).
+var ErrGoalNotFound = errors.New("goal not found")
+
+func goalKey(ctx context.Context, id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKGoals)},
+		"SK": &types.AttributeValueMemberS{Value: GoalPrefix + id},
+	}
+}
+
+// CreateGoal writes a new goal row. The attribute_not_exists guard only
+// matters on an id collision.
+func (r *Repository) CreateGoal(ctx context.Context, goal *model.Goal) error {
+	return r.putGoal(ctx, goal, "attribute_not_exists(PK)")
+}
+
+// UpdateGoal replaces an existing goal row. A goal has no server-maintained
+// fields (unlike a recurring schedule's booking cursor), so a conditional
+// Put of the whole row is safe; ErrGoalNotFound when the row is gone.
+func (r *Repository) UpdateGoal(ctx context.Context, goal *model.Goal) error {
+	err := r.putGoal(ctx, goal, "attribute_exists(PK)")
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		return ErrGoalNotFound
+	}
+	return err
+}
+
+func (r *Repository) putGoal(ctx context.Context, goal *model.Goal, condition string) error {
+	goal.PK = AccountPK(ctx, PKGoals)
+	goal.SK = GoalPrefix + goal.ID
+	item, err := attributevalue.MarshalMap(goal)
+	if err != nil {
+		return fmt.Errorf("failed to marshal goal: %w", err)
+	}
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                item,
+		ConditionExpression: aws.String(condition),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save goal: %w", err)
+	}
+	return nil
+}
+
+// GetGoal fetches one goal by id. Returns nil (no error) when absent.
+func (r *Repository) GetGoal(ctx context.Context, id string) (*model.Goal, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       goalKey(ctx, id),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get goal: %w", err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+	var goal model.Goal
+	if err := unmarshalItem(result.Item, &goal); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal goal: %w", err)
+	}
+	return &goal, nil
+}
+
+// ListGoals returns every goal in id order; the service sorts them into
+// funding order.
+func (r *Repository) ListGoals(ctx context.Context) ([]model.Goal, error) {
+	var out []model.Goal
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(r.tableName),
+			KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :prefix)"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":pk":     &types.AttributeValueMemberS{Value: AccountPK(ctx, PKGoals)},
+				":prefix": &types.AttributeValueMemberS{Value: GoalPrefix},
+			},
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list goals: %w", err)
+		}
+		var page []model.Goal
+		if err := unmarshalItems(result.Items, &page); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal goals: %w", err)
+		}
+		out = append(out, page...)
+		if len(result.LastEvaluatedKey) == 0 {
+			return out, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
+
+// DeleteGoal removes a goal and returns it, or nil if it did not exist.
+func (r *Repository) DeleteGoal(ctx context.Context, id string) (*model.Goal, error) {
+	result, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:    aws.String(r.tableName),
+		Key:          goalKey(ctx, id),
+		ReturnValues: types.ReturnValueAllOld,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete goal: %w", err)
+	}
+	if result.Attributes == nil {
+		return nil, nil
+	}
+	var goal model.Goal
+	if err := unmarshalItem(result.Attributes, &goal); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal goal: %w", err)
+	}
+	return &goal, nil
+}
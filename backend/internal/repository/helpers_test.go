@@ -1,18 +1,21 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vppillai/passbook/backend/internal/model"
 )
 
 // The repository's DynamoDB calls are exercised against the real service;
 // these tests cover the pure helpers — especially txConditionFailedIndex,
 // whose index mapping decides whether a failed transaction surfaces as
-// "insufficient funds" vs "state mismatch" to the user.
+// "insufficient funds" vs "state mismatch" to the user — and the legacy
+// float-to-cents conversion every read goes through.
 
 func TestTxConditionFailedIndex(t *testing.T) {
 	t.Run("finds the first ConditionalCheckFailed reason", func(t *testing.T) {
@@ -69,3 +72,171 @@ func TestRateLimitPK(t *testing.T) {
 		t.Errorf("rateLimitPK(\"\") = %q, want \"RATELIMIT#unknown\"", got)
 	}
 }
+
+func TestUpgradeLegacyMoney(t *testing.T) {
+	t.Run("float dollars become integer cents", func(t *testing.T) {
+		item := map[string]types.AttributeValue{
+			"PK":             &types.AttributeValueMemberS{Value: "MONTH#2026-06"},
+			"ending_balance": &types.AttributeValueMemberN{Value: "-22.159999999999968"},
+			"total_expenses": &types.AttributeValueMemberN{Value: "12.349999"},
+			"category_totals": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+				"food": &types.AttributeValueMemberN{Value: "7.5"},
+			}},
+		}
+		found, err := upgradeLegacyMoney(item)
+		if err != nil || !found {
+			t.Fatalf("upgradeLegacyMoney = (%v, %v), want (true, nil)", found, err)
+		}
+		for attr, want := range map[string]string{"ending_balance_cents": "-2216", "total_expenses_cents": "1235"} {
+			if n, ok := item[attr].(*types.AttributeValueMemberN); !ok || n.Value != want {
+				t.Errorf("%s = %#v, want N %s", attr, item[attr], want)
+			}
+		}
+		totals, ok := item["category_totals_cents"].(*types.AttributeValueMemberM)
+		if !ok {
+			t.Fatalf("category_totals_cents = %#v, want a map", item["category_totals_cents"])
+		}
+		if n, ok := totals.Value["food"].(*types.AttributeValueMemberN); !ok || n.Value != "750" {
+			t.Errorf("category_totals_cents.food = %#v, want N 750", totals.Value["food"])
+		}
+		for _, legacy := range []string{"ending_balance", "total_expenses", "category_totals"} {
+			if _, ok := item[legacy]; ok {
+				t.Errorf("legacy %s left behind", legacy)
+			}
+		}
+	})
+
+	t.Run("cents attribute wins over a stale legacy one", func(t *testing.T) {
+		item := map[string]types.AttributeValue{
+			"amount":       &types.AttributeValueMemberN{Value: "1"},
+			"amount_cents": &types.AttributeValueMemberN{Value: "500"},
+		}
+		if _, err := upgradeLegacyMoney(item); err != nil {
+			t.Fatalf("upgradeLegacyMoney: %v", err)
+		}
+		if n := item["amount_cents"].(*types.AttributeValueMemberN); n.Value != "500" {
+			t.Errorf("amount_cents = %s, want 500", n.Value)
+		}
+		if _, ok := item["amount"]; ok {
+			t.Error("legacy amount left behind")
+		}
+	})
+
+	t.Run("migrated rows are untouched", func(t *testing.T) {
+		item := map[string]types.AttributeValue{
+			"amount_cents": &types.AttributeValueMemberN{Value: "500"},
+		}
+		if found, err := upgradeLegacyMoney(item); found || err != nil {
+			t.Errorf("upgradeLegacyMoney = (%v, %v), want (false, nil)", found, err)
+		}
+	})
+
+	t.Run("unmarshalItem leaves the caller's map alone", func(t *testing.T) {
+		item := map[string]types.AttributeValue{
+			"SK":     &types.AttributeValueMemberS{Value: "EXP#1#a"},
+			"amount": &types.AttributeValueMemberN{Value: "12.5"},
+		}
+		var exp model.Expense
+		if err := unmarshalItem(item, &exp); err != nil {
+			t.Fatalf("unmarshalItem: %v", err)
+		}
+		if exp.Amount != model.Dollars(12.5) {
+			t.Errorf("Amount = %v, want 12.5", exp.Amount)
+		}
+		if _, ok := item["amount"]; !ok {
+			t.Error("unmarshalItem rewrote the raw item")
+		}
+	})
+}
+
+func TestSearchTokens(t *testing.T) {
+	cases := []struct {
+		in   string
+		want []string
+	}{
+		{"Pizza night", []string{"pizza", "night"}},
+		{"pizza & PIZZA, again!", []string{"pizza", "again"}},
+		{"a b 7 up", []string{"up"}},
+		{"Café crème", []string{"café", "crème"}},
+		{"  ", nil},
+	}
+	for _, tc := range cases {
+		got := SearchTokens(tc.in)
+		if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+			t.Errorf("SearchTokens(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+// searchIndexDiff decides which entries an expense transaction writes; an
+// entry on both sides must be neither deleted nor re-put, since a
+// transaction may not name the same item twice.
+func TestSearchIndexDiff(t *testing.T) {
+	sks := func(items []map[string]types.AttributeValue) []string {
+		var out []string
+		for _, item := range items {
+			out = append(out, item["SK"].(*types.AttributeValueMemberS).Value)
+		}
+		return out
+	}
+	ctx := context.Background()
+	old := &model.Expense{SK: "EXP#1#a", Description: "Pizza night"}
+
+	t.Run("add", func(t *testing.T) {
+		puts, deletes := searchIndexDiff(ctx, "", nil, "2026-03", old)
+		if got := sks(puts); fmt.Sprint(got) != "[pizza#2026-03#EXP#1#a night#2026-03#EXP#1#a]" || len(deletes) != 0 {
+			t.Errorf("puts %q deletes %d", got, len(deletes))
+		}
+	})
+
+	t.Run("edit keeps shared words", func(t *testing.T) {
+		updated := &model.Expense{SK: "EXP#1#a", Description: "pizza lunch"}
+		puts, deletes := searchIndexDiff(ctx, "2026-03", old, "2026-03", updated)
+		if got := sks(puts); fmt.Sprint(got) != "[lunch#2026-03#EXP#1#a]" {
+			t.Errorf("puts = %q", got)
+		}
+		if got := sks(deletes); fmt.Sprint(got) != "[night#2026-03#EXP#1#a]" {
+			t.Errorf("deletes = %q", got)
+		}
+	})
+
+	t.Run("move re-keys every word", func(t *testing.T) {
+		moved := &model.Expense{SK: "EXP#2#b", Description: "Pizza night"}
+		puts, deletes := searchIndexDiff(ctx, "2026-03", old, "2026-04", moved)
+		if len(puts) != 2 || len(deletes) != 2 {
+			t.Errorf("puts %q deletes %q, want two of each", sks(puts), sks(deletes))
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		puts, deletes := searchIndexDiff(ctx, "2026-03", old, "", nil)
+		if len(puts) != 0 || len(deletes) != 2 {
+			t.Errorf("puts %d deletes %d, want 0 and 2", len(puts), len(deletes))
+		}
+	})
+}
+
+// A non-default account's partitions sit behind its ACCT# prefix, and the
+// default account keeps the keys the table had before accounts.
+func TestAccountPK(t *testing.T) {
+	ctx := context.Background()
+	if got := AccountPK(ctx, MonthPrefix+"2026-03"); got != "MONTH#2026-03" {
+		t.Errorf("default month key = %q", got)
+	}
+	kid := WithAccount(ctx, "a1b2c3d4")
+	if got := AccountPK(kid, MonthPrefix+"2026-03"); got != "ACCT#a1b2c3d4#MONTH#2026-03" {
+		t.Errorf("account month key = %q", got)
+	}
+	if got := AccountPK(kid, PKBalance); got != "ACCT#a1b2c3d4#BALANCE" {
+		t.Errorf("account balance key = %q", got)
+	}
+	for _, pk := range []string{"MONTH#2026-03", "ACCT#a1b2c3d4#MONTH#2026-03"} {
+		if got := MonthOfPK(pk); got != "2026-03" {
+			t.Errorf("MonthOfPK(%q) = %q", pk, got)
+		}
+	}
+	kidKeys, _ := searchIndexDiff(kid, "", nil, "2026-03", &model.Expense{SK: "EXP#1#a", Description: "pizza"})
+	if got := kidKeys[0]["PK"].(*types.AttributeValueMemberS).Value; got != "ACCT#a1b2c3d4#SEARCH" {
+		t.Errorf("account search partition = %q", got)
+	}
+}
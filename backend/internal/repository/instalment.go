@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vppillai/passbook/backend/internal/model"
+)
+
+// Instalment-plan storage keys. Plans share one partition
+// (PK="INSTALMENT", SK="INST#<id>"), like RECURRING and GOALS.
+const (
+	PKInstalment     = "INSTALMENT"
+	InstalmentPrefix = "INST#"
+)
+
+// InstalmentRewrite is one unpaid instalment's part in a plan transaction.
+// Old is the row as the plan records it: its SK locates the row, its
+// amount and category are the optimistic lock (expenseLock), and its
+// category is the category_totals entry the amount leaves. New is the replacement row, or nil to delete it.
+type InstalmentRewrite struct {
+	Month string
+	Old   *model.Expense
+	New   *model.Expense
+}
+
+func (w InstalmentRewrite) delta() model.Money {
+	if w.New == nil {
+		return -w.Old.Amount
+	}
+	return w.New.Amount - w.Old.Amount
+}
+
+func instalmentKey(ctx context.Context, id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKInstalment)},
+		"SK": &types.AttributeValueMemberS{Value: InstalmentPrefix + id},
+	}
+}
+
+// CreateInstalmentPlan writes a new plan row. The service books the
+// instalments first, so a plan row never points at rows that were not
+// written.
+func (r *Repository) CreateInstalmentPlan(ctx context.Context, plan *model.InstalmentPlan) error {
+	plan.PK = AccountPK(ctx, PKInstalment)
+	plan.SK = InstalmentPrefix + plan.ID
+	item, err := attributevalue.MarshalMap(plan)
+	if err != nil {
+		return fmt.Errorf("failed to marshal instalment plan: %w", err)
+	}
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(PK)"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create instalment plan: %w", err)
+	}
+	return nil
+}
+
+// GetInstalmentPlan fetches one plan by id. Returns nil (no error) when
+// absent.
+func (r *Repository) GetInstalmentPlan(ctx context.Context, id string) (*model.InstalmentPlan, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       instalmentKey(ctx, id),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instalment plan: %w", err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+	var plan model.InstalmentPlan
+	if err := unmarshalItem(result.Item, &plan); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal instalment plan: %w", err)
+	}
+	return &plan, nil
+}
+
+// ListInstalmentPlans returns every plan in id order.
+func (r *Repository) ListInstalmentPlans(ctx context.Context) ([]model.InstalmentPlan, error) {
+	var out []model.InstalmentPlan
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(r.tableName),
+			KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :prefix)"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":pk":     &types.AttributeValueMemberS{Value: AccountPK(ctx, PKInstalment)},
+				":prefix": &types.AttributeValueMemberS{Value: InstalmentPrefix},
+			},
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list instalment plans: %w", err)
+		}
+		var page []model.InstalmentPlan
+		if err := unmarshalItems(result.Items, &page); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal instalment plans: %w", err)
+		}
+		out = append(out, page...)
+		if len(result.LastEvaluatedKey) == 0 {
+			return out, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
+
+// AtomicUpdateInstalmentPlan rewrites the unpaid instalments of a plan and
+// the plan row itself in ONE transaction: per instalment the expense row,
+// its month summary and the summary's mirror, then a single BALANCE update
+// for the net change and the plan Put. The Put is conditioned on the
+// version old was read at (updated is written at old.Version+1), and each
+// expense row on its recorded amount, so a concurrent plan edit or a row
+// changed underneath surfaces as ErrExpenseStateMismatch. When checkBalance
+// is set, a month whose instalment grows is conditioned on its
+// ending_balance covering the increase → ErrInsufficientBalance.
+func (r *Repository) AtomicUpdateInstalmentPlan(ctx context.Context, old, updated *model.InstalmentPlan, rewrites []InstalmentRewrite, checkBalance bool) error {
+	updated.PK = AccountPK(ctx, PKInstalment)
+	updated.SK = InstalmentPrefix + updated.ID
+	updated.Version = old.Version + 1
+	item, err := attributevalue.MarshalMap(updated)
+	if err != nil {
+		return fmt.Errorf("failed to marshal instalment plan: %w", err)
+	}
+	planItem := types.TransactWriteItem{Put: &types.Put{
+		TableName:           aws.String(r.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("version = :version"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":version": &types.AttributeValueMemberN{Value: strconv.Itoa(old.Version)},
+		},
+	}}
+	return r.transactInstalments(ctx, "update", rewrites, planItem, checkBalance)
+}
+
+// AtomicDeleteInstalmentPlan deletes the plan row and every rewrite's
+// expense row (New must be nil) in one transaction, refunding each month
+// summary, its mirror and BALANCE. The conditions are the same as
+// AtomicUpdateInstalmentPlan's.
+func (r *Repository) AtomicDeleteInstalmentPlan(ctx context.Context, old *model.InstalmentPlan, rewrites []InstalmentRewrite) error {
+	planItem := types.TransactWriteItem{Delete: &types.Delete{
+		TableName:           aws.String(r.tableName),
+		Key:                 instalmentKey(ctx, old.ID),
+		ConditionExpression: aws.String("version = :version"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":version": &types.AttributeValueMemberN{Value: strconv.Itoa(old.Version)},
+		},
+	}}
+	return r.transactInstalments(ctx, "delete", rewrites, planItem, false)
+}
+
+// transactInstalments runs the shared instalment transaction: the
+// rewrites, the net BALANCE update, planItem and then an audit entry per
+// rewrite; op names the operation in errors. A plan has at most one
+// instalment per month (its months are consecutive), so no item is named
+// twice. Each rewrite costs four items, which is what bounds a plan's
+// length (see maxInstalments in the service).
+func (r *Repository) transactInstalments(ctx context.Context, op string, rewrites []InstalmentRewrite, planItem types.TransactWriteItem, checkBalance bool) error {
+	sorted := append([]InstalmentRewrite(nil), rewrites...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Month < sorted[j].Month })
+
+	nowStr := timestamp(time.Now())
+	var (
+		items []types.TransactWriteItem
+		// failures[i] is what a failed condition on items[i] means.
+		failures []error
+		net      model.Money
+	)
+	for _, w := range sorted {
+		pkMonth := AccountPK(ctx, MonthPrefix+w.Month)
+		delta := w.delta()
+		net += delta
+
+		expenseKey := map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: pkMonth},
+			"SK": &types.AttributeValueMemberS{Value: w.Old.SK},
+		}
+		expenseValues := map[string]types.AttributeValue{}
+		lock := expenseLock(w.Old, expenseValues)
+		changes := []categoryChange{{w.Old.Category, -w.Old.Amount}}
+		if w.New == nil {
+			items = append(items, types.TransactWriteItem{Delete: &types.Delete{
+				TableName:                 aws.String(r.tableName),
+				Key:                       expenseKey,
+				ConditionExpression:       aws.String(lock),
+				ExpressionAttributeValues: expenseValues,
+			}})
+		} else {
+			expenseValues[":newAmount"] = moneyValue(w.New.Amount)
+			expenseValues[":desc"] = &types.AttributeValueMemberS{Value: w.New.Description}
+			expenseExpr := "SET amount_cents = :newAmount, description = :desc REMOVE category"
+			if w.New.Category != "" {
+				expenseExpr = "SET amount_cents = :newAmount, description = :desc, category = :category"
+				expenseValues[":category"] = &types.AttributeValueMemberS{Value: w.New.Category}
+			}
+			items = append(items, types.TransactWriteItem{Update: &types.Update{
+				TableName:                 aws.String(r.tableName),
+				Key:                       expenseKey,
+				UpdateExpression:          aws.String(expenseExpr),
+				ConditionExpression:       aws.String(lock),
+				ExpressionAttributeValues: expenseValues,
+			}})
+			changes = append(changes, categoryChange{w.New.Category, w.New.Amount})
+		}
+		failures = append(failures, ErrExpenseStateMismatch)
+
+		cats := categoryDeltas(changes...)
+		if delta == 0 && len(cats) == 0 {
+			// A description-only change leaves the month's totals alone.
+			continue
+		}
+		summaryValues := map[string]types.AttributeValue{
+			":delta": moneyValue(delta),
+			":now":   &types.AttributeValueMemberS{Value: nowStr},
+		}
+		summaryExpr, names := withCategoryTotals(
+			"SET total_expenses_cents = total_expenses_cents + :delta, ending_balance_cents = ending_balance_cents - :delta, updated_at = :now",
+			summaryValues,
+			cats,
+		)
+		listValues := cloneValues(summaryValues)
+		monthCondition := "attribute_exists(PK)"
+		monthFailure := ErrExpenseStateMismatch
+		if checkBalance && delta > 0 {
+			monthCondition = "attribute_exists(PK) AND ending_balance_cents >= :delta"
+			monthFailure = ErrInsufficientBalance
+		}
+		items = append(items,
+			types.TransactWriteItem{Update: &types.Update{
+				TableName: aws.String(r.tableName),
+				Key: map[string]types.AttributeValue{
+					"PK": &types.AttributeValueMemberS{Value: pkMonth},
+					"SK": &types.AttributeValueMemberS{Value: SKSummary},
+				},
+				UpdateExpression:          aws.String(summaryExpr),
+				ConditionExpression:       aws.String(monthCondition),
+				ExpressionAttributeNames:  names,
+				ExpressionAttributeValues: summaryValues,
+			}},
+			r.monthListUpdate(ctx, w.Month, summaryExpr, names, listValues),
+		)
+		failures = append(failures, monthFailure, nil)
+	}
+	if net != 0 {
+		items = append(items, types.TransactWriteItem{Update: &types.Update{
+			TableName: aws.String(r.tableName),
+			Key: map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKBalance)},
+				"SK": &types.AttributeValueMemberS{Value: SKBalance},
+			},
+			UpdateExpression: aws.String("SET total_balance_cents = if_not_exists(total_balance_cents, :zero) + :delta, updated_at = :now"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":delta": moneyValue(-net),
+				":zero":  &types.AttributeValueMemberN{Value: "0"},
+				":now":   &types.AttributeValueMemberS{Value: nowStr},
+			},
+		}})
+		failures = append(failures, nil)
+	}
+	items = append(items, planItem)
+	failures = append(failures, ErrExpenseStateMismatch)
+	// One journal entry per rewritten instalment, after every conditioned
+	// item so failures still lines up with items.
+	for _, w := range sorted {
+		var err error
+		if w.New == nil {
+			items, err = r.withAudit(ctx, items, model.AuditExpenseDelete, w.Month, w.Old.SK, ExpenseAuditState(w.Month, w.Old), nil)
+		} else {
+			items, err = r.withAudit(ctx, items, model.AuditExpenseUpdate, w.Month, w.New.SK, ExpenseAuditState(w.Month, w.Old), ExpenseAuditState(w.Month, w.New))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(items) > maxTransactItems {
+		return fmt.Errorf("failed to %s instalment plan: %d transaction items, over the %d cap", op, len(items), maxTransactItems)
+	}
+	_, err := r.transactWrite(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+	if err != nil {
+		if idx, ok := txConditionFailedIndex(err); ok && idx < len(failures) && failures[idx] != nil {
+			return failures[idx]
+		}
+		return fmt.Errorf("failed to %s instalment plan atomically: %w", op, err)
+	}
+	return nil
+}
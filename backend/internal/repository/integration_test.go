@@ -123,8 +123,8 @@ func newIntegrationRepo(t *testing.T) (*Repository, context.Context) {
 func seedMonth(t *testing.T, r *Repository, ctx context.Context, month string, start, allow, expenses, ending float64) {
 	t.Helper()
 	s := &model.MonthSummary{
-		Month: month, StartingBalance: start, AllowanceAdded: allow,
-		TotalExpenses: expenses, EndingBalance: ending,
+		Month: month, StartingBalance: model.Dollars(start), AllowanceAdded: model.Dollars(allow),
+		TotalExpenses: model.Dollars(expenses), EndingBalance: model.Dollars(ending),
 	}
 	if err := r.SaveMonthSummary(ctx, s); err != nil {
 		t.Fatalf("SaveMonthSummary(%s): %v", month, err)
@@ -150,15 +150,15 @@ func mustSummary(t *testing.T, r *Repository, ctx context.Context, month string)
 // The overspend ConditionExpression
 // =====================================================================
 
-// The hard stop is `ending_balance >= :amount` inside a transaction. A fake that
-// implements the same comparison in Go proves the SERVICE reads its result
-// correctly; only the real service proves the expression fires at all.
+// The hard stop is `ending_balance_cents >= :amount` inside a transaction. A
+// fake that implements the same comparison in Go proves the SERVICE reads its
+// result correctly; only the real service proves the expression fires at all.
 func TestIntegration_AtomicAddExpense_OverspendConditionActuallyFires(t *testing.T) {
 	r, ctx := newIntegrationRepo(t)
 	seedMonth(t, r, ctx, "2026-01", 0, 100, 0, 100)
 
 	exp := &model.Expense{
-		SK: ExpensePrefix + "1700000000000#over", Amount: 250,
+		SK: ExpensePrefix + "1700000000000#over", Amount: model.Dollars(250),
 		Description: "too much", CreatedAt: time.Now(),
 	}
 	err := r.AtomicAddExpense(ctx, "2026-01", exp, true)
@@ -169,7 +169,7 @@ func TestIntegration_AtomicAddExpense_OverspendConditionActuallyFires(t *testing
 	// And nothing landed: a transaction is all-or-nothing, so the refused
 	// expense must not exist and the summary must be untouched.
 	s := mustSummary(t, r, ctx, "2026-01")
-	if s.TotalExpenses != 0 || s.EndingBalance != 100 {
+	if s.TotalExpenses != 0 || s.EndingBalance != model.Dollars(100) {
 		t.Errorf("summary moved despite refusal: expenses=%v ending=%v", s.TotalExpenses, s.EndingBalance)
 	}
 	items, _, err := r.GetExpenses(ctx, "2026-01", 10, nil)
@@ -181,19 +181,45 @@ func TestIntegration_AtomicAddExpense_OverspendConditionActuallyFires(t *testing
 	}
 }
 
+// The stats cache reuses a month while its summary's total and updated_at
+// stay put, so an edit that keeps the total has to move updated_at even
+// within the second of the write before it.
+func TestIntegration_SummaryUpdatedAtMovesWithinASecond(t *testing.T) {
+	r, ctx := newIntegrationRepo(t)
+	seedMonth(t, r, ctx, "2026-01", 0, 100, 0, 100)
+
+	exp := &model.Expense{
+		SK: ExpensePrefix + "1700000000000#at", Amount: model.Dollars(10),
+		Description: "book", CreatedAt: time.Now(),
+	}
+	if err := r.AtomicAddExpense(ctx, "2026-01", exp, true); err != nil {
+		t.Fatalf("AtomicAddExpense: %v", err)
+	}
+	before := mustSummary(t, r, ctx, "2026-01")
+	renamed := *exp
+	renamed.Description = "comic"
+	if err := r.AtomicUpdateExpense(ctx, "2026-01", exp, &renamed, true); err != nil {
+		t.Fatalf("AtomicUpdateExpense: %v", err)
+	}
+	after := mustSummary(t, r, ctx, "2026-01")
+	if after.TotalExpenses != before.TotalExpenses || after.UpdatedAt.Equal(before.UpdatedAt) {
+		t.Errorf("updated_at %v -> %v, want it moved with the total kept", before.UpdatedAt, after.UpdatedAt)
+	}
+}
+
 func TestIntegration_AtomicAddExpense_AllowedWhenAffordable(t *testing.T) {
 	r, ctx := newIntegrationRepo(t)
 	seedMonth(t, r, ctx, "2026-01", 0, 100, 0, 100)
 
 	exp := &model.Expense{
-		SK: ExpensePrefix + "1700000000000#ok", Amount: 40,
+		SK: ExpensePrefix + "1700000000000#ok", Amount: model.Dollars(40),
 		Description: "book", CreatedAt: time.Now(),
 	}
 	if err := r.AtomicAddExpense(ctx, "2026-01", exp, true); err != nil {
 		t.Fatalf("AtomicAddExpense: %v", err)
 	}
 	s := mustSummary(t, r, ctx, "2026-01")
-	if s.TotalExpenses != 40 || s.EndingBalance != 60 {
+	if s.TotalExpenses != model.Dollars(40) || s.EndingBalance != model.Dollars(60) {
 		t.Errorf("expenses=%v ending=%v, want 40 / 60", s.TotalExpenses, s.EndingBalance)
 	}
 	// The mirror must have moved in the same transaction.
@@ -204,7 +230,7 @@ func TestIntegration_AtomicAddExpense_AllowedWhenAffordable(t *testing.T) {
 	if err != nil {
 		t.Fatalf("ListMonths: %v", err)
 	}
-	if len(months) != 1 || months[0].TotalExpenses != 40 {
+	if len(months) != 1 || months[0].TotalExpenses != model.Dollars(40) {
 		t.Errorf("mirror not updated in the transaction: %+v", months)
 	}
 }
@@ -216,13 +242,13 @@ func TestIntegration_AtomicAddExpense_NoCheckPermitsNegative(t *testing.T) {
 	seedMonth(t, r, ctx, "2026-01", 0, 100, 0, 100)
 
 	exp := &model.Expense{
-		SK: ExpensePrefix + "1700000000000#neg", Amount: 250,
+		SK: ExpensePrefix + "1700000000000#neg", Amount: model.Dollars(250),
 		Description: "over", CreatedAt: time.Now(),
 	}
 	if err := r.AtomicAddExpense(ctx, "2026-01", exp, false); err != nil {
 		t.Fatalf("AtomicAddExpense with checkBalance=false: %v", err)
 	}
-	if s := mustSummary(t, r, ctx, "2026-01"); s.EndingBalance != -150 {
+	if s := mustSummary(t, r, ctx, "2026-01"); s.EndingBalance != model.Dollars(-150) {
 		t.Errorf("ending = %v, want -150", s.EndingBalance)
 	}
 }
@@ -235,7 +261,7 @@ func TestIntegration_AtomicAddExpense_NoCheckPermitsNegative(t *testing.T) {
 func TestIntegration_AtomicDeleteMonth_RefusesWhenAllowanceMoved(t *testing.T) {
 	r, ctx := newIntegrationRepo(t)
 	const month = "2026-03"
-	// One month, total_expenses 0 throughout, stored allowance 150. The two
+	// One month, total_expenses_cents 0 throughout, stored allowance 150. The two
 	// calls below differ in NOTHING except the allowance figure passed, so a
 	// refusal can only be attributable to that clause.
 	//
@@ -246,7 +272,7 @@ func TestIntegration_AtomicDeleteMonth_RefusesWhenAllowanceMoved(t *testing.T) {
 	seedMonth(t, r, ctx, month, 0, 150, 0, 150)
 
 	// Stale figure — a top-up landed between the caller's pre-read and here.
-	if err := r.AtomicDeleteMonth(ctx, month, 100); !errors.Is(err, ErrMonthHasExpenses) {
+	if err := r.AtomicDeleteMonth(ctx, month, model.Dollars(100), nil); !errors.Is(err, ErrMonthHasExpenses) {
 		t.Fatalf("stale allowance: err = %v, want ErrMonthHasExpenses", err)
 	}
 	if s, _ := r.GetMonthSummary(ctx, month); s == nil {
@@ -256,7 +282,7 @@ func TestIntegration_AtomicDeleteMonth_RefusesWhenAllowanceMoved(t *testing.T) {
 
 	// Same call, correct figure: must go through, or the refusal above proved
 	// nothing about the allowance clause.
-	if err := r.AtomicDeleteMonth(ctx, month, 150); err != nil {
+	if err := r.AtomicDeleteMonth(ctx, month, model.Dollars(150), nil); err != nil {
 		t.Fatalf("matching allowance was refused: %v", err)
 	}
 	if s, _ := r.GetMonthSummary(ctx, month); s != nil {
@@ -268,7 +294,7 @@ func TestIntegration_AtomicDeleteMonth_RefusesWhenExpensesRemain(t *testing.T) {
 	r, ctx := newIntegrationRepo(t)
 	seedMonth(t, r, ctx, "2026-03", 0, 100, 25, 75)
 
-	if err := r.AtomicDeleteMonth(ctx, "2026-03", 100); !errors.Is(err, ErrMonthHasExpenses) {
+	if err := r.AtomicDeleteMonth(ctx, "2026-03", model.Dollars(100), nil); !errors.Is(err, ErrMonthHasExpenses) {
 		t.Fatalf("err = %v, want ErrMonthHasExpenses", err)
 	}
 	if s, _ := r.GetMonthSummary(ctx, "2026-03"); s == nil {
@@ -280,7 +306,7 @@ func TestIntegration_AtomicDeleteMonth_SucceedsAndDebitsTheBalance(t *testing.T)
 	r, ctx := newIntegrationRepo(t)
 	seedMonth(t, r, ctx, "2026-03", 0, 100, 0, 100)
 
-	if err := r.AtomicDeleteMonth(ctx, "2026-03", 100); err != nil {
+	if err := r.AtomicDeleteMonth(ctx, "2026-03", model.Dollars(100), nil); err != nil {
 		t.Fatalf("AtomicDeleteMonth: %v", err)
 	}
 	if s, _ := r.GetMonthSummary(ctx, "2026-03"); s != nil {
@@ -313,7 +339,7 @@ func TestIntegration_MoveAcrossMonths_RefundOffsetsTheCharge(t *testing.T) {
 	seedMonth(t, r, ctx, "2026-02", 0, 0, 0, 0)
 
 	oldSK := ExpensePrefix + "1700000000000#move"
-	seed := &model.Expense{SK: oldSK, Amount: 100, Description: "trip", CreatedAt: time.Now()}
+	seed := &model.Expense{SK: oldSK, Amount: model.Dollars(100), Description: "trip", CreatedAt: time.Now()}
 	if err := r.AtomicAddExpense(ctx, "2026-01", seed, false); err != nil {
 		t.Fatalf("seeding the expense: %v", err)
 	}
@@ -321,15 +347,15 @@ func TestIntegration_MoveAcrossMonths_RefundOffsetsTheCharge(t *testing.T) {
 	seedMonth(t, r, ctx, "2026-01", 0, 100, 100, 0)
 
 	newExp := &model.Expense{
-		SK: ExpensePrefix + "1700000001000#move", Amount: 100,
+		SK: ExpensePrefix + "1700000001000#move", Amount: model.Dollars(100),
 		Description: "trip", CreatedAt: time.Now(),
 	}
 	// checkBalance=true, and the destination's own ending balance is 0 — so
 	// without the refund offset this must fail.
-	if err := r.AtomicMoveExpenseAcrossMonths(ctx, "2026-01", "2026-02", oldSK, newExp, 100, true, true); err != nil {
+	if err := r.AtomicMoveExpenseAcrossMonths(ctx, "2026-01", "2026-02", seed, newExp, true, true); err != nil {
 		t.Fatalf("move refused although the refund covers it: %v", err)
 	}
-	if s := mustSummary(t, r, ctx, "2026-02"); s.TotalExpenses != 100 {
+	if s := mustSummary(t, r, ctx, "2026-02"); s.TotalExpenses != model.Dollars(100) {
 		t.Errorf("destination expenses = %v, want 100", s.TotalExpenses)
 	}
 	if s := mustSummary(t, r, ctx, "2026-01"); s.TotalExpenses != 0 {
@@ -345,17 +371,17 @@ func TestIntegration_MoveAcrossMonths_NoOffsetStillGuardsTheDestination(t *testi
 	seedMonth(t, r, ctx, "2026-02", 0, 0, 0, 0)
 
 	oldSK := ExpensePrefix + "1700000000000#move2"
-	seed := &model.Expense{SK: oldSK, Amount: 100, Description: "trip", CreatedAt: time.Now()}
+	seed := &model.Expense{SK: oldSK, Amount: model.Dollars(100), Description: "trip", CreatedAt: time.Now()}
 	if err := r.AtomicAddExpense(ctx, "2026-01", seed, false); err != nil {
 		t.Fatalf("seeding: %v", err)
 	}
 	seedMonth(t, r, ctx, "2026-01", 0, 100, 100, 0)
 
 	newExp := &model.Expense{
-		SK: ExpensePrefix + "1700000001000#move2", Amount: 100,
+		SK: ExpensePrefix + "1700000001000#move2", Amount: model.Dollars(100),
 		Description: "trip", CreatedAt: time.Now(),
 	}
-	err := r.AtomicMoveExpenseAcrossMonths(ctx, "2026-01", "2026-02", oldSK, newExp, 100, true, false)
+	err := r.AtomicMoveExpenseAcrossMonths(ctx, "2026-01", "2026-02", seed, newExp, true, false)
 	if !errors.Is(err, ErrInsufficientBalance) {
 		t.Fatalf("err = %v, want ErrInsufficientBalance when no refund reaches the destination", err)
 	}
@@ -371,19 +397,21 @@ func TestIntegration_MoveAcrossMonths_StaleAmountIsDistinguishedFromOverspend(t
 	seedMonth(t, r, ctx, "2026-02", 0, 500, 0, 500)
 
 	oldSK := ExpensePrefix + "1700000000000#stale"
-	seed := &model.Expense{SK: oldSK, Amount: 30, Description: "book", CreatedAt: time.Now()}
+	seed := &model.Expense{SK: oldSK, Amount: model.Dollars(30), Description: "book", CreatedAt: time.Now()}
 	if err := r.AtomicAddExpense(ctx, "2026-01", seed, false); err != nil {
 		t.Fatalf("seeding: %v", err)
 	}
 
 	newExp := &model.Expense{
-		SK: ExpensePrefix + "1700000001000#stale", Amount: 30,
+		SK: ExpensePrefix + "1700000001000#stale", Amount: model.Dollars(30),
 		Description: "book", CreatedAt: time.Now(),
 	}
 	// Claim the old amount was 99 when it is really 30. Both months can easily
 	// afford everything, so a balance failure cannot be the cause — the error
 	// must be the expense-state one.
-	err := r.AtomicMoveExpenseAcrossMonths(ctx, "2026-01", "2026-02", oldSK, newExp, 99, true, false)
+	stale := *seed
+	stale.Amount = model.Dollars(99)
+	err := r.AtomicMoveExpenseAcrossMonths(ctx, "2026-01", "2026-02", &stale, newExp, true, false)
 	if !errors.Is(err, ErrExpenseStateMismatch) {
 		t.Fatalf("err = %v, want ErrExpenseStateMismatch — the cancellation-reason "+
 			"parsing must distinguish the delete condition from the balance one", err)
@@ -396,18 +424,18 @@ func TestIntegration_MoveAcrossMonths_StaleAmountIsDistinguishedFromOverspend(t
 
 func TestIntegration_CreateMonthSummaryIfAbsent_SecondCallLoses(t *testing.T) {
 	r, ctx := newIntegrationRepo(t)
-	s := &model.MonthSummary{Month: "2026-05", StartingBalance: 10, AllowanceAdded: 20, EndingBalance: 30}
+	s := &model.MonthSummary{Month: "2026-05", StartingBalance: model.Dollars(10), AllowanceAdded: model.Dollars(20), EndingBalance: model.Dollars(30)}
 
 	if err := r.CreateMonthSummaryIfAbsent(ctx, s); err != nil {
 		t.Fatalf("first create: %v", err)
 	}
-	other := &model.MonthSummary{Month: "2026-05", StartingBalance: 999, AllowanceAdded: 999, EndingBalance: 999}
+	other := &model.MonthSummary{Month: "2026-05", StartingBalance: model.Dollars(999), AllowanceAdded: model.Dollars(999), EndingBalance: model.Dollars(999)}
 	if err := r.CreateMonthSummaryIfAbsent(ctx, other); !errors.Is(err, ErrMonthAlreadyExists) {
 		t.Fatalf("second create err = %v, want ErrMonthAlreadyExists", err)
 	}
 	// The winner's values must survive: this guard exists so a concurrent
 	// create cannot clobber a month and desync it from the global balance.
-	if got := mustSummary(t, r, ctx, "2026-05"); got.StartingBalance != 10 {
+	if got := mustSummary(t, r, ctx, "2026-05"); got.StartingBalance != model.Dollars(10) {
 		t.Errorf("starting = %v, want 10 — the loser overwrote the winner", got.StartingBalance)
 	}
 }
@@ -432,6 +460,49 @@ func TestIntegration_CreateConfig_IsExclusive(t *testing.T) {
 	}
 }
 
+// UpdateConfig must touch only the attributes it is given: a caller holding
+// a stale copy of the row (read before a PIN change) may not put the old
+// hash back. An empty value removes its attribute; a missing row is an
+// error, not an upsert.
+func TestIntegration_UpdateConfig_WritesOnlyTheNamedAttributes(t *testing.T) {
+	r, ctx := newIntegrationRepo(t)
+	if err := r.UpdateConfig(ctx, &model.Config{}, ConfigCategoryBudgets); !errors.Is(err, ErrConfigNotFound) {
+		t.Fatalf("update without a row: err = %v, want ErrConfigNotFound", err)
+	}
+	if err := r.CreateConfig(ctx, &model.Config{PinHash: "old", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("CreateConfig: %v", err)
+	}
+	stale, err := r.GetConfig(ctx)
+	if err != nil {
+		t.Fatalf("GetConfig: %v", err)
+	}
+	changed := *stale
+	changed.PinHash = "new"
+	if err := r.SaveConfig(ctx, &changed); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+
+	stale.CategoryBudgets = map[string]model.Money{"coffee": model.Dollars(40)}
+	stale.EnforceCategoryBudgets = true
+	if err := r.UpdateConfig(ctx, stale, ConfigCategoryBudgets, ConfigEnforceCategoryBudgets); err != nil {
+		t.Fatalf("UpdateConfig: %v", err)
+	}
+	c, err := r.GetConfig(ctx)
+	if err != nil {
+		t.Fatalf("GetConfig: %v", err)
+	}
+	if c.PinHash != "new" || c.CategoryBudgets["coffee"] != model.Dollars(40) || !c.EnforceCategoryBudgets {
+		t.Fatalf("config = %+v, want the new PIN and the budget", c)
+	}
+
+	if err := r.UpdateConfig(ctx, &model.Config{}, ConfigCategoryBudgets, ConfigEnforceCategoryBudgets); err != nil {
+		t.Fatalf("UpdateConfig clearing: %v", err)
+	}
+	if c, _ = r.GetConfig(ctx); c.CategoryBudgets != nil || c.EnforceCategoryBudgets || c.PinHash != "new" {
+		t.Errorf("config = %+v, want the budgets removed and the PIN kept", c)
+	}
+}
+
 func TestIntegration_EnsureMonthListMirror_IsIdempotent(t *testing.T) {
 	r, ctx := newIntegrationRepo(t)
 	seedMonth(t, r, ctx, "2026-06", 0, 50, 10, 40)
@@ -449,11 +520,225 @@ func TestIntegration_EnsureMonthListMirror_IsIdempotent(t *testing.T) {
 	if len(months) != 1 {
 		t.Fatalf("mirrors = %d, want 1", len(months))
 	}
-	if months[0].TotalExpenses != 10 {
+	if months[0].TotalExpenses != model.Dollars(10) {
 		t.Errorf("mirror expenses = %v, want 10", months[0].TotalExpenses)
 	}
 }
 
+// =====================================================================
+// Category totals — nested document-path updates the fake cannot vet
+// =====================================================================
+
+// A month written before categories existed has no category_totals_cents
+// map, and DynamoDB refuses a nested SET beneath a missing parent.
+// EnsureCategoryTotals seeds it on both rows; a re-categorize must then move
+// the amount between entries on the canonical row AND the mirror.
+func TestIntegration_CategoryTotals_FollowARecategorize(t *testing.T) {
+	r, ctx := newIntegrationRepo(t)
+	seedMonth(t, r, ctx, "2026-07", 0, 100, 0, 100)
+
+	if err := r.EnsureCategoryTotals(ctx, "2026-07"); err != nil {
+		t.Fatalf("EnsureCategoryTotals: %v", err)
+	}
+	exp := &model.Expense{
+		SK: ExpensePrefix + "1700000000000#cats", Amount: model.Dollars(12.5),
+		Description: "lunch", Category: "food", CreatedAt: time.Now(),
+	}
+	if err := r.AtomicAddExpense(ctx, "2026-07", exp, true); err != nil {
+		t.Fatalf("AtomicAddExpense: %v", err)
+	}
+	updated := *exp
+	updated.Category = "treats"
+	if err := r.AtomicUpdateExpense(ctx, "2026-07", exp, &updated, true); err != nil {
+		t.Fatalf("AtomicUpdateExpense: %v", err)
+	}
+
+	s := mustSummary(t, r, ctx, "2026-07")
+	if s.CategoryTotals["food"] != 0 || s.CategoryTotals["treats"] != model.Dollars(12.5) {
+		t.Errorf("canonical category totals = %v, want food 0, treats 12.5", s.CategoryTotals)
+	}
+	months, _, err := r.ListMonths(ctx, 10, nil)
+	if err != nil {
+		t.Fatalf("ListMonths: %v", err)
+	}
+	if len(months) != 1 || months[0].CategoryTotals["treats"] != model.Dollars(12.5) {
+		t.Errorf("mirror category totals = %v, want treats 12.5", months)
+	}
+}
+
+// The category_totals deltas are computed from the caller's read of the
+// row, so a recategorization landing after that read must cancel the write
+// like a changed amount does, or the wrong category would be debited.
+func TestIntegration_CategoryTotals_StaleCategoryIsAMismatch(t *testing.T) {
+	r, ctx := newIntegrationRepo(t)
+	seedMonth(t, r, ctx, "2026-07", 0, 100, 0, 100)
+	if err := r.EnsureCategoryTotals(ctx, "2026-07"); err != nil {
+		t.Fatalf("EnsureCategoryTotals: %v", err)
+	}
+	exp := &model.Expense{
+		SK: ExpensePrefix + "1700000000000#stalecat", Amount: model.Dollars(10),
+		Description: "lunch", Category: "food", CreatedAt: time.Now(),
+	}
+	if err := r.AtomicAddExpense(ctx, "2026-07", exp, true); err != nil {
+		t.Fatalf("AtomicAddExpense: %v", err)
+	}
+
+	// Read as uncategorized and as another category: both are stale.
+	for _, category := range []string{"", "treats"} {
+		stale := *exp
+		stale.Category = category
+		updated := stale
+		updated.Amount = model.Dollars(12)
+		if err := r.AtomicUpdateExpense(ctx, "2026-07", &stale, &updated, true); !errors.Is(err, ErrExpenseStateMismatch) {
+			t.Errorf("update read as %q: err = %v, want ErrExpenseStateMismatch", category, err)
+		}
+		if err := r.AtomicDeleteExpense(ctx, "2026-07", &stale, nil); !errors.Is(err, ErrExpenseStateMismatch) {
+			t.Errorf("delete read as %q: err = %v, want ErrExpenseStateMismatch", category, err)
+		}
+	}
+	if s := mustSummary(t, r, ctx, "2026-07"); s.CategoryTotals["food"] != model.Dollars(10) || s.TotalExpenses != model.Dollars(10) {
+		t.Errorf("summary = %+v, want food still 10", s)
+	}
+}
+
+// =====================================================================
+// Money migration — float-dollar rows rewritten to integer cents
+// =====================================================================
+
+// A month written before the switch to cents holds float dollars, dust and
+// all. The migration must rewrite it so the *_cents arithmetic of the next
+// transaction lands on a real attribute, and must leave a marker so it runs
+// once.
+func TestIntegration_EnsureMoneyMigrated_RewritesLegacyRows(t *testing.T) {
+	r, ctx := newIntegrationRepo(t)
+	const month = "2026-06"
+	for _, item := range []map[string]types.AttributeValue{
+		{
+			"PK":               &types.AttributeValueMemberS{Value: MonthPrefix + month},
+			"SK":               &types.AttributeValueMemberS{Value: SKSummary},
+			"month":            &types.AttributeValueMemberS{Value: month},
+			"starting_balance": &types.AttributeValueMemberN{Value: "-522.16"},
+			"allowance_added":  &types.AttributeValueMemberN{Value: "500"},
+			"total_expenses":   &types.AttributeValueMemberN{Value: "0"},
+			"ending_balance":   &types.AttributeValueMemberN{Value: "-22.159999999999968"},
+		},
+		{
+			"PK":            &types.AttributeValueMemberS{Value: PKBalance},
+			"SK":            &types.AttributeValueMemberS{Value: SKBalance},
+			"total_balance": &types.AttributeValueMemberN{Value: "-22.159999999999968"},
+		},
+	} {
+		if _, err := r.client.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName: aws.String(r.tableName), Item: item,
+		}); err != nil {
+			t.Fatalf("PutItem: %v", err)
+		}
+	}
+	if err := r.EnsureMonthListMirror(ctx, month); err != nil {
+		t.Fatalf("EnsureMonthListMirror: %v", err)
+	}
+
+	// Legacy rows read as cents before any migration.
+	if s := mustSummary(t, r, ctx, month); s.EndingBalance != model.Dollars(-22.16) {
+		t.Errorf("pre-migration ending = %v, want -22.16", s.EndingBalance)
+	}
+
+	if err := r.EnsureMoneyMigrated(ctx); err != nil {
+		t.Fatalf("EnsureMoneyMigrated: %v", err)
+	}
+	raw, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: MonthPrefix + month},
+			"SK": &types.AttributeValueMemberS{Value: SKSummary},
+		},
+	})
+	if err != nil {
+		t.Fatalf("GetItem: %v", err)
+	}
+	if n, ok := raw.Item["ending_balance_cents"].(*types.AttributeValueMemberN); !ok || n.Value != "-2216" {
+		t.Errorf("ending_balance_cents = %#v, want N -2216", raw.Item["ending_balance_cents"])
+	}
+	if _, ok := raw.Item["ending_balance"]; ok {
+		t.Error("legacy ending_balance survived the migration")
+	}
+
+	// The next transaction's arithmetic works on the rewritten rows.
+	if err := r.AtomicAddFunds(ctx, month, model.Dollars(50), nil); err != nil {
+		t.Fatalf("AtomicAddFunds after migration: %v", err)
+	}
+	if s := mustSummary(t, r, ctx, month); s.EndingBalance != model.Dollars(27.84) {
+		t.Errorf("ending after +50 = %v, want 27.84", s.EndingBalance)
+	}
+	b, err := r.GetBalance(ctx)
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	if b.TotalBalance != model.Dollars(27.84) {
+		t.Errorf("balance = %v, want 27.84", b.TotalBalance)
+	}
+
+	// The marker is written, so a second call is a no-op.
+	if err := r.EnsureMoneyMigrated(ctx); err != nil {
+		t.Errorf("second EnsureMoneyMigrated: %v", err)
+	}
+}
+
+// Before the sweep has run, a write converts the legacy rows it updates on
+// its own, so a request never waits for the table-wide Scan.
+func TestIntegration_TransactWrite_UpgradesLegacyRowsItUpdates(t *testing.T) {
+	r, ctx := newIntegrationRepo(t)
+	const month = "2026-06"
+	for _, item := range []map[string]types.AttributeValue{
+		{
+			"PK":               &types.AttributeValueMemberS{Value: MonthPrefix + month},
+			"SK":               &types.AttributeValueMemberS{Value: SKSummary},
+			"month":            &types.AttributeValueMemberS{Value: month},
+			"starting_balance": &types.AttributeValueMemberN{Value: "0"},
+			"allowance_added":  &types.AttributeValueMemberN{Value: "100.1"},
+			"total_expenses":   &types.AttributeValueMemberN{Value: "0"},
+			"ending_balance":   &types.AttributeValueMemberN{Value: "100.09999999999999"},
+		},
+		{
+			"PK":            &types.AttributeValueMemberS{Value: PKBalance},
+			"SK":            &types.AttributeValueMemberS{Value: SKBalance},
+			"total_balance": &types.AttributeValueMemberN{Value: "100.1"},
+		},
+	} {
+		if _, err := r.client.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName: aws.String(r.tableName), Item: item,
+		}); err != nil {
+			t.Fatalf("PutItem: %v", err)
+		}
+	}
+	if err := r.EnsureMonthListMirror(ctx, month); err != nil {
+		t.Fatalf("EnsureMonthListMirror: %v", err)
+	}
+
+	if err := r.AtomicAddFunds(ctx, month, model.Dollars(5), nil); err != nil {
+		t.Fatalf("AtomicAddFunds on legacy rows: %v", err)
+	}
+	if s := mustSummary(t, r, ctx, month); s.EndingBalance != model.Dollars(105.1) {
+		t.Errorf("ending = %v, want 105.10", s.EndingBalance)
+	}
+	b, err := r.GetBalance(ctx)
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	if b.TotalBalance != model.Dollars(105.1) {
+		t.Errorf("balance = %v, want 105.10", b.TotalBalance)
+	}
+	marker, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName), Key: moneyMarkerKey,
+	})
+	if err != nil {
+		t.Fatalf("GetItem: %v", err)
+	}
+	if marker.Item != nil {
+		t.Error("a write recorded the sweep's marker")
+	}
+}
+
 // =====================================================================
 // The rate limiter's conditional increment and TTL
 // =====================================================================
@@ -537,3 +822,48 @@ func TestIntegration_ClearRateLimit_RemovesTheRow(t *testing.T) {
 		t.Errorf("attempts = %d after clear", e.Attempts)
 	}
 }
+
+// =====================================================================
+// Accounts — the key prefix and DeleteAccount's balance guard
+// =====================================================================
+
+// An account's month must land in its own partitions and leave the default
+// ledger alone, and DeleteAccount must refuse while the account's BALANCE
+// row holds money — the window between the service's emptiness check and
+// the transaction.
+func TestIntegration_Accounts_ScopedLedgerAndBalanceGuard(t *testing.T) {
+	r, ctx := newIntegrationRepo(t)
+	now := time.Now()
+	if err := r.CreateAccount(ctx, &model.Account{ID: "sam", Name: "Sam", CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	sam := WithAccount(ctx, "sam")
+	summary := &model.MonthSummary{Month: "2026-03", AllowanceAdded: model.Dollars(100), EndingBalance: model.Dollars(100)}
+	if err := r.AtomicCreateMonth(sam, summary, model.Dollars(100)); err != nil {
+		t.Fatalf("AtomicCreateMonth: %v", err)
+	}
+	if summary.PK != "ACCT#sam#MONTH#2026-03" {
+		t.Errorf("PK = %q, want the account prefix", summary.PK)
+	}
+	if s, _ := r.GetMonthSummary(ctx, "2026-03"); s != nil {
+		t.Error("the account's month is visible in the default ledger")
+	}
+
+	if err := r.DeleteAccount(ctx, "sam"); !errors.Is(err, ErrAccountHasBalance) {
+		t.Fatalf("err = %v, want ErrAccountHasBalance", err)
+	}
+	if a, _ := r.GetAccount(ctx, "sam"); a == nil {
+		t.Fatal("the account row was deleted with money in its balance")
+	}
+
+	// A never-funded account has no BALANCE row at all: must go through.
+	if err := r.CreateAccount(ctx, &model.Account{ID: "empty", Name: "Empty", CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	if err := r.DeleteAccount(ctx, "empty"); err != nil {
+		t.Fatalf("DeleteAccount(empty): %v", err)
+	}
+	if err := r.DeleteAccount(ctx, "empty"); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("second delete err = %v, want ErrAccountNotFound", err)
+	}
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/vppillai/passbook/backend/internal/model"
@@ -19,8 +20,26 @@ type RepositoryInterface interface {
 	// Config
 	GetConfig(ctx context.Context) (*model.Config, error)
 	SaveConfig(ctx context.Context, config *model.Config) error
+	// UpdateConfig writes only the named attributes (Config* constants),
+	// leaving the rest of the row alone.
+	UpdateConfig(ctx context.Context, config *model.Config, fields ...string) error
 	CreateConfig(ctx context.Context, config *model.Config) error
 
+	// Accounts — the ACCOUNTS registry. Every other ledger method below
+	// reads and writes the ledger of the context's account (see
+	// WithAccount); the config, sessions, rate limits, WebAuthn rows and
+	// the journal are instance-wide.
+	// GetAccount returns nil (no error) when the account has no row.
+	GetAccount(ctx context.Context, id string) (*model.Account, error)
+	ListAccounts(ctx context.Context) ([]model.Account, error)
+	CreateAccount(ctx context.Context, account *model.Account) error
+	// UpdateAccount replaces the row; ErrAccountNotFound when it is gone
+	// (the default account's row is created on its first rename).
+	UpdateAccount(ctx context.Context, account *model.Account) error
+	// DeleteAccount removes the row and the account's BALANCE, refusing
+	// a non-zero balance with ErrAccountHasBalance.
+	DeleteAccount(ctx context.Context, id string) error
+
 	// Balance
 	GetBalance(ctx context.Context) (*model.Balance, error)
 
@@ -43,32 +62,56 @@ type RepositoryInterface interface {
 	// attribute_exists(PK) condition on the atomic mutations' monthListUpdate
 	// cannot cancel the transaction on legacy tables that predate the mirror.
 	EnsureMonthListMirror(ctx context.Context, month string) error
+	// EnsureCategoryTotals seeds an empty category_totals map on the month's
+	// canonical row and mirror (idempotent, if_not_exists) so the nested
+	// per-category deltas in the expense transactions have a parent map.
+	// Call it after EnsureMonthListMirror.
+	EnsureCategoryTotals(ctx context.Context, month string) error
 	// PropagateLaterMonthDeltas applies a conditional delta to
 	// starting_balance + ending_balance on each named month's canonical row
 	// AND its mirror, batched into one TransactWriteItems (chunked at the
 	// 100-item cap) so carry-chain propagation is atomic and composes with
 	// concurrent writes.
-	PropagateLaterMonthDeltas(ctx context.Context, months []string, delta float64) error
+	PropagateLaterMonthDeltas(ctx context.Context, months []string, delta model.Money) error
+	// EnsureMoneyMigrated rewrites any rows still holding float-dollar
+	// money attributes to integer cents (one-time Scan, then a marker row).
+	// Writes convert the rows they update on their own; this sweeps the
+	// rest, from the scheduled jobs rather than a request.
+	EnsureMoneyMigrated(ctx context.Context) error
 
 	// Expenses
 	GetExpense(ctx context.Context, month string, expenseID string) (*model.Expense, error)
 	GetExpenses(ctx context.Context, month string, limit int32, cursor map[string]types.AttributeValue) ([]model.Expense, map[string]types.AttributeValue, error)
-	UpdateExpense(ctx context.Context, month string, expenseID string, amount float64, description string) (*model.Expense, error)
+	// GetExpensesInRange pages through one month's expenses timestamped in
+	// [from, to), newest first.
+	GetExpensesInRange(ctx context.Context, month string, from, to time.Time, limit int32, cursor map[string]types.AttributeValue) ([]model.Expense, map[string]types.AttributeValue, error)
+	UpdateExpense(ctx context.Context, month string, expenseID string, amount model.Money, description string) (*model.Expense, error)
 	DeleteExpense(ctx context.Context, month string, expenseID string) (*model.Expense, error)
+	// AddExpenseAttachment appends attachment metadata to an expense row,
+	// conditioned on the row holding exactly count attachments;
+	// ErrExpenseStateMismatch otherwise (or when the row is gone).
+	AddExpenseAttachment(ctx context.Context, month, expenseID string, count int, attachment *model.Attachment) error
+	// RemoveExpenseAttachment removes the attachment at index, conditioned
+	// on that slot holding attachmentID; ErrExpenseStateMismatch otherwise.
+	RemoveExpenseAttachment(ctx context.Context, month, expenseID string, index int, attachmentID string) error
 
 	// Atomic (TransactWriteItems) operations — service's preferred path
 	// for any multi-row mutation. See dynamodb.go for rationale.
 	AtomicAddExpense(ctx context.Context, month string, expense *model.Expense, checkBalance bool) error
-	AtomicUpdateExpense(ctx context.Context, month string, expenseID string, oldAmount, newAmount float64, newDescription string, checkBalance bool) error
-	AtomicDeleteExpense(ctx context.Context, month string, expenseID string, oldAmount float64) error
+	// AtomicUpdateExpense and AtomicDeleteExpense take the expense as read
+	// (old): its SK locates the row, its amount is the optimistic lock, and
+	// its category is the category_totals entry the amount leaves. A
+	// non-nil trash entry is filed in the delete's transaction.
+	AtomicUpdateExpense(ctx context.Context, month string, old, updated *model.Expense, checkBalance bool) error
+	AtomicDeleteExpense(ctx context.Context, month string, old *model.Expense, trash *model.TrashedExpense) error
 	// AtomicMoveExpenseSameMonth re-dates an expense WITHIN one month: the SK
 	// encodes the timestamp, so the old SK is deleted and the new SK is put in
-	// a single transaction. The delete is conditioned on amount = oldAmount
-	// (optimistic lock) → ErrExpenseStateMismatch. Amount/description changes
-	// compose via the summary + balance + mirror deltas; the summary update is
+	// a single transaction. The delete is conditioned on amount = old.Amount
+	// (optimistic lock) → ErrExpenseStateMismatch. Amount/description/category
+	// changes compose via the summary + balance + mirror deltas; the summary update is
 	// conditioned on ending_balance >= amountDelta when checkBalance && delta>0
 	// → ErrInsufficientBalance.
-	AtomicMoveExpenseSameMonth(ctx context.Context, month string, oldExpenseID string, newExpense *model.Expense, oldAmount float64, checkBalance bool) error
+	AtomicMoveExpenseSameMonth(ctx context.Context, month string, old, newExpense *model.Expense, checkBalance bool) error
 	// AtomicMoveExpenseAcrossMonths moves an expense from srcMonth to
 	// dstMonth in a single transaction: delete the old expense (conditioned
 	// on amount = oldAmount → ErrExpenseStateMismatch), debit the source
@@ -83,16 +126,143 @@ type RepositoryInterface interface {
 	// destination can afford the gross amount out of its current balance
 	// refuses moves that net to zero across the chain. Both months' mirror
 	// rows must already exist.
-	AtomicMoveExpenseAcrossMonths(ctx context.Context, srcMonth, dstMonth, oldExpenseID string, newExpense *model.Expense, oldAmount float64, checkBalance bool, srcRefundReachesDst bool) error
-	AtomicCreateMonth(ctx context.Context, summary *model.MonthSummary, allowance float64) error
-	AtomicAddFunds(ctx context.Context, month string, amount float64) error
-	AtomicDeleteMonth(ctx context.Context, month string, allowanceAdded float64) error
+	AtomicMoveExpenseAcrossMonths(ctx context.Context, srcMonth, dstMonth string, old, newExpense *model.Expense, checkBalance bool, srcRefundReachesDst bool) error
+	AtomicCreateMonth(ctx context.Context, summary *model.MonthSummary, allowance model.Money) error
+	// AtomicAddFunds credits a month; entry is the itemized credit written
+	// with a top-up, nil for the monthly allowance.
+	AtomicAddFunds(ctx context.Context, month string, amount model.Money, entry *model.FundEntry) error
+	// AtomicWithdrawFunds debits the month and the global balance by what
+	// entry, the itemized withdrawal, takes back, leaving earnings (the
+	// month's earnings_added as read) in place; see the repository for the
+	// conditions. The month's mirror row must exist.
+	AtomicWithdrawFunds(ctx context.Context, month string, entry *model.FundEntry, earnings model.Money, checkBalance bool) error
+	AtomicDeleteMonth(ctx context.Context, month string, allowanceAdded model.Money, fundIDs []string) error
+
+	// Funds credits (FUND# rows in a month's partition). The update and
+	// delete move the month and the balance by the change in amount, in the
+	// same transaction.
+	GetFundEntries(ctx context.Context, month string) ([]model.FundEntry, error)
+	GetFundEntry(ctx context.Context, month, id string) (*model.FundEntry, error)
+	AtomicUpdateFundEntry(ctx context.Context, month string, old, updated *model.FundEntry, checkBalance bool) error
+	AtomicDeleteFundEntry(ctx context.Context, month string, old *model.FundEntry, checkBalance bool) error
+
+	// Recurring expenses — schedule rows under PK="RECURRING". Booking an
+	// occurrence goes through AtomicAddExpense; these only manage the
+	// schedules and their booking cursor.
+	CreateRecurringExpense(ctx context.Context, rec *model.RecurringExpense) error
+	// GetRecurringExpense returns nil (no error) when the schedule is absent.
+	GetRecurringExpense(ctx context.Context, id string) (*model.RecurringExpense, error)
+	ListRecurringExpenses(ctx context.Context) ([]model.RecurringExpense, error)
+	// UpdateRecurringExpense rewrites the editable fields but never the
+	// booking cursor; ErrRecurringNotFound when the schedule is gone.
+	UpdateRecurringExpense(ctx context.Context, rec *model.RecurringExpense) error
+	// DeleteRecurringExpense returns the removed schedule, nil if absent.
+	DeleteRecurringExpense(ctx context.Context, id string) (*model.RecurringExpense, error)
+	// MarkRecurringBooked moves last_booked_month forward (never back); a
+	// no-op when the cursor is already at or past month.
+	MarkRecurringBooked(ctx context.Context, id, month string) error
+
+	// Savings goals — rows under PK="GOALS". Progress is computed by the
+	// service from the balance and month list; nothing here touches money.
+	CreateGoal(ctx context.Context, goal *model.Goal) error
+	// GetGoal returns nil (no error) when the goal is absent.
+	GetGoal(ctx context.Context, id string) (*model.Goal, error)
+	ListGoals(ctx context.Context) ([]model.Goal, error)
+	// UpdateGoal replaces the goal row; ErrGoalNotFound when it is gone.
+	UpdateGoal(ctx context.Context, goal *model.Goal) error
+	// DeleteGoal returns the removed goal, nil if absent.
+	DeleteGoal(ctx context.Context, id string) (*model.Goal, error)
+
+	// Instalment plans — rows under PK="INSTALMENT". Each instalment is
+	// booked as an ordinary expense through AtomicAddExpense; these manage
+	// the plan and rewrite its unpaid instalments.
+	CreateInstalmentPlan(ctx context.Context, plan *model.InstalmentPlan) error
+	// GetInstalmentPlan returns nil (no error) when the plan is absent.
+	GetInstalmentPlan(ctx context.Context, id string) (*model.InstalmentPlan, error)
+	ListInstalmentPlans(ctx context.Context) ([]model.InstalmentPlan, error)
+	// AtomicUpdateInstalmentPlan rewrites the given instalment rows, their
+	// months' summaries + mirrors, BALANCE and the plan (at old.Version+1)
+	// in one transaction. A stale plan version or a row whose amount moved
+	// → ErrExpenseStateMismatch; an unaffordable increase under
+	// checkBalance → ErrInsufficientBalance.
+	AtomicUpdateInstalmentPlan(ctx context.Context, old, updated *model.InstalmentPlan, rewrites []InstalmentRewrite, checkBalance bool) error
+	// AtomicDeleteInstalmentPlan deletes the plan and the given instalment
+	// rows, refunding their months and BALANCE, in one transaction.
+	AtomicDeleteInstalmentPlan(ctx context.Context, old *model.InstalmentPlan, rewrites []InstalmentRewrite) error
+
+	// Search — the PK="SEARCH" word index. The Atomic*Expense methods keep
+	// it in step inside their transactions.
+	// SearchExpenses queries the entries whose word begins with prefix,
+	// newest first within a word, natively paginated.
+	SearchExpenses(ctx context.Context, prefix string, limit int32, cursor map[string]types.AttributeValue) ([]SearchHit, map[string]types.AttributeValue, error)
+	// BatchGetExpenses reads up to 100 distinct expense rows; missing rows
+	// are left out of the result.
+	BatchGetExpenses(ctx context.Context, refs []ExpenseRef) ([]model.Expense, error)
+	// ReindexExpense moves an expense's entries from old to updated (either
+	// may be nil) outside a transaction, for writers that cannot fit the
+	// entries into theirs.
+	ReindexExpense(ctx context.Context, oldMonth string, old *model.Expense, newMonth string, updated *model.Expense) error
+	// EnsureSearchIndex indexes expenses written before the index existed
+	// (one-time Scan, then a marker row).
+	EnsureSearchIndex(ctx context.Context) error
+
+	// Trash — deleted expenses awaiting restore or expiry, filed by
+	// AtomicDeleteExpense. Expired entries linger until the TTL sweep. A
+	// restore books one and deletes it in one transaction.
+	GetTrashedExpense(ctx context.Context, id string) (*model.TrashedExpense, error)
+	ListTrash(ctx context.Context) ([]model.TrashedExpense, error)
+	DeleteTrashedExpense(ctx context.Context, id string) error
+	AtomicRestoreTrashedExpense(ctx context.Context, month string, expense *model.Expense, checkBalance bool) error
+
+	// Pending — expenses members submitted, awaiting approval. Approval
+	// books one and deletes it in one transaction; a rejection deletes it
+	// and journals the reason in one transaction.
+	PutPendingExpense(ctx context.Context, p *model.PendingExpense) error
+	GetPendingExpense(ctx context.Context, id string) (*model.PendingExpense, error)
+	ListPendingExpenses(ctx context.Context) ([]model.PendingExpense, error)
+	DeletePendingExpense(ctx context.Context, id string) error
+	AtomicApprovePendingExpense(ctx context.Context, month string, expense *model.Expense, checkBalance bool) error
+	AtomicRejectPendingExpense(ctx context.Context, p *model.PendingExpense, reason string) error
+
+	// Chores — tasks with a reward, and completions awaiting approval.
+	// Approval deletes the completion and credits its reward as a funds
+	// credit in one transaction; a rejection just deletes it.
+	CreateChore(ctx context.Context, chore *model.Chore) error
+	GetChore(ctx context.Context, id string) (*model.Chore, error)
+	ListChores(ctx context.Context) ([]model.Chore, error)
+	UpdateChore(ctx context.Context, chore *model.Chore) error
+	DeleteChore(ctx context.Context, id string) (*model.Chore, error)
+	PutChoreCompletion(ctx context.Context, c *model.ChoreCompletion) error
+	GetChoreCompletion(ctx context.Context, id string) (*model.ChoreCompletion, error)
+	ListChoreCompletions(ctx context.Context) ([]model.ChoreCompletion, error)
+	DeleteChoreCompletion(ctx context.Context, id string) (*model.ChoreCompletion, error)
+	AtomicApproveChoreCompletion(ctx context.Context, c *model.ChoreCompletion, entry *model.FundEntry) error
+
+	// Audit — the append-only PK="AUDIT" journal. Every Atomic* method
+	// writes its entry inside its own transaction, attributed to the
+	// context's Actor (see WithActor).
+	// ListAudit pages through the journal newest first.
+	ListAudit(ctx context.Context, limit int32, cursor map[string]types.AttributeValue) ([]model.AuditEntry, map[string]types.AttributeValue, error)
+	// AtomicChangePIN writes only the config's new PIN hash and journals
+	// the change.
+	AtomicChangePIN(ctx context.Context, config *model.Config) error
+	// AtomicSaveMemberPIN writes only the config's new member PIN hash
+	// (empty to remove it) and journals the change.
+	AtomicSaveMemberPIN(ctx context.Context, config *model.Config) error
+
+	// Ledger repair — the writes behind the service's RepairLedger. Each is
+	// conditioned on the values the check read (ErrLedgerRowChanged when
+	// the app has moved them since) and journals itself.
+	RepairMonthSummary(ctx context.Context, stored, fixed *model.MonthSummary) error
+	RepairBalance(ctx context.Context, stored, expected model.Money) error
+	DeleteOrphanMonthListMirror(ctx context.Context, month string) error
 
 	// Sessions
-	CreateSession(ctx context.Context, token string, ttlHours int) error
+	CreateSession(ctx context.Context, token, role string, ttlHours int) error
 	GetSession(ctx context.Context, token string) (*model.Session, error)
 	DeleteSession(ctx context.Context, token string) error
 	DeleteAllSessions(ctx context.Context) error
+	DeleteMemberSessions(ctx context.Context) error
 
 	// Rate limiting — per-IP scoping (PK = "RATELIMIT#<ip>").
 	GetRateLimitEntry(ctx context.Context, sourceIP string) (*model.RateLimitEntry, error)
@@ -122,6 +292,12 @@ type RepositoryInterface interface {
 	// DeleteAllWebAuthnCredentials removes every stored credential (canonical
 	// rows + WACREDLIST mirrors) so the user can disable biometrics.
 	DeleteAllWebAuthnCredentials(ctx context.Context) error
+	// AtomicEnrolWebAuthnCredential is PutWebAuthnCredential for a newly
+	// registered credential, journaling the enrolment.
+	AtomicEnrolWebAuthnCredential(ctx context.Context, cred *model.WebAuthnCredential) error
+	// AtomicDisableWebAuthn is DeleteAllWebAuthnCredentials for a user
+	// turning biometrics off, journaling it.
+	AtomicDisableWebAuthn(ctx context.Context) error
 }
 
 // Compile-time assertion that the concrete Repository implements the interface.
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vppillai/passbook/backend/internal/model"
+)
+
+// ErrLedgerRowChanged is returned by the ledger repair writes when the row
+// no longer holds the values the check read, i.e. the app changed it in
+// between. Nothing was written; the service asks for a fresh check.
+var ErrLedgerRowChanged = errors.New("ledger row changed since it was checked")
+
+// moneyPinned builds a condition clause pinning attr to the value the
+// checker read. A zero reading also accepts a missing attribute: rows from
+// before carry-over have no starting balance, and a table without a
+// BALANCE row reads as zero.
+func moneyPinned(attr, placeholder string, read model.Money, values map[string]types.AttributeValue) string {
+	values[placeholder] = moneyValue(read)
+	if read == 0 {
+		return fmt.Sprintf("(attribute_not_exists(%s) OR %s = %s)", attr, attr, placeholder)
+	}
+	return fmt.Sprintf("%s = %s", attr, placeholder)
+}
+
+// RepairMonthSummary overwrites a month's total_expenses, starting_balance,
+// ending_balance and earnings_added with fixed's, and replaces its
+// MONTHLIST mirror with a copy of the repaired row (creating it if it was
+// missing), journaling the change — one transaction. The canonical update
+// is conditioned on the row still holding stored's five money fields, so an
+// app write since the check surfaces as ErrLedgerRowChanged rather than
+// being overwritten. fixed must be stored with only those four fields
+// changed.
+func (r *Repository) RepairMonthSummary(ctx context.Context, stored, fixed *model.MonthSummary) error {
+	fixed.UpdatedAt = time.Now()
+	listItem, err := monthListItem(ctx, fixed)
+	if err != nil {
+		return err
+	}
+
+	values := map[string]types.AttributeValue{
+		":total":    moneyValue(fixed.TotalExpenses),
+		":starting": moneyValue(fixed.StartingBalance),
+		":ending":   moneyValue(fixed.EndingBalance),
+		":earnings": moneyValue(fixed.EarningsAdded),
+		":now":      &types.AttributeValueMemberS{Value: timestamp(fixed.UpdatedAt)},
+	}
+	condition := strings.Join([]string{
+		"attribute_exists(PK)",
+		moneyPinned("total_expenses_cents", ":oldTotal", stored.TotalExpenses, values),
+		moneyPinned("starting_balance_cents", ":oldStarting", stored.StartingBalance, values),
+		moneyPinned("ending_balance_cents", ":oldEnding", stored.EndingBalance, values),
+		moneyPinned("allowance_added_cents", ":allowance", stored.AllowanceAdded, values),
+		moneyPinned("earnings_added_cents", ":oldEarnings", stored.EarningsAdded, values),
+	}, " AND ")
+
+	audit, err := r.auditPut(NewAuditEntry(ctx, model.AuditLedgerRepair, fixed.Month, "", MonthAuditState(stored), MonthAuditState(fixed)))
+	if err != nil {
+		return err
+	}
+
+	_, err = r.transactWrite(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Update: &types.Update{
+				TableName: aws.String(r.tableName),
+				Key: map[string]types.AttributeValue{
+					"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, MonthPrefix+fixed.Month)},
+					"SK": &types.AttributeValueMemberS{Value: SKSummary},
+				},
+				UpdateExpression:          aws.String("SET total_expenses_cents = :total, starting_balance_cents = :starting, ending_balance_cents = :ending, earnings_added_cents = :earnings, updated_at = :now"),
+				ConditionExpression:       aws.String(condition),
+				ExpressionAttributeValues: values,
+			}},
+			{Put: &types.Put{TableName: aws.String(r.tableName), Item: listItem}},
+			audit,
+		},
+	})
+	if err != nil {
+		if _, ok := txConditionFailedIndex(err); ok {
+			return ErrLedgerRowChanged
+		}
+		return fmt.Errorf("failed to repair month summary: %w", err)
+	}
+	return nil
+}
+
+// RepairBalance sets the global balance to expected, conditioned on it still
+// being stored, and journals the change. ErrLedgerRowChanged when it moved.
+func (r *Repository) RepairBalance(ctx context.Context, stored, expected model.Money) error {
+	values := map[string]types.AttributeValue{
+		":expected": moneyValue(expected),
+		":now":      &types.AttributeValueMemberS{Value: timestamp(time.Now())},
+	}
+	condition := moneyPinned("total_balance_cents", ":stored", stored, values)
+	audit, err := r.auditPut(NewAuditEntry(ctx, model.AuditLedgerRepair, "", PKBalance,
+		&model.AuditState{TotalBalance: &stored}, &model.AuditState{TotalBalance: &expected}))
+	if err != nil {
+		return err
+	}
+
+	_, err = r.transactWrite(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Update: &types.Update{
+				TableName: aws.String(r.tableName),
+				Key: map[string]types.AttributeValue{
+					"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKBalance)},
+					"SK": &types.AttributeValueMemberS{Value: SKBalance},
+				},
+				UpdateExpression:          aws.String("SET total_balance_cents = :expected, updated_at = :now"),
+				ConditionExpression:       aws.String(condition),
+				ExpressionAttributeValues: values,
+			}},
+			audit,
+		},
+	})
+	if err != nil {
+		if _, ok := txConditionFailedIndex(err); ok {
+			return ErrLedgerRowChanged
+		}
+		return fmt.Errorf("failed to repair balance: %w", err)
+	}
+	return nil
+}
+
+// DeleteOrphanMonthListMirror removes a MONTHLIST row whose canonical month
+// is gone, journaling it. The transaction checks the canonical row is still
+// absent, so a month created since the check keeps its mirror
+// (ErrLedgerRowChanged).
+func (r *Repository) DeleteOrphanMonthListMirror(ctx context.Context, month string) error {
+	audit, err := r.auditPut(NewAuditEntry(ctx, model.AuditLedgerRepair, month, PKMonthList, nil, nil))
+	if err != nil {
+		return err
+	}
+	_, err = r.transactWrite(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{ConditionCheck: &types.ConditionCheck{
+				TableName: aws.String(r.tableName),
+				Key: map[string]types.AttributeValue{
+					"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, MonthPrefix+month)},
+					"SK": &types.AttributeValueMemberS{Value: SKSummary},
+				},
+				ConditionExpression: aws.String("attribute_not_exists(PK)"),
+			}},
+			{Delete: &types.Delete{
+				TableName: aws.String(r.tableName),
+				Key: map[string]types.AttributeValue{
+					"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKMonthList)},
+					"SK": &types.AttributeValueMemberS{Value: month},
+				},
+			}},
+			audit,
+		},
+	})
+	if err != nil {
+		if _, ok := txConditionFailedIndex(err); ok {
+			return ErrLedgerRowChanged
+		}
+		return fmt.Errorf("failed to delete orphan month list row: %w", err)
+	}
+	return nil
+}
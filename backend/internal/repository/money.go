@@ -0,0 +1,381 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vppillai/passbook/backend/internal/model"
+)
+
+// Money storage.
+//
+// Every ledger figure is stored as an integer Number of cents under a
+// *_cents attribute (see model.Money). Rows written before the switch hold
+// float dollars under the bare name instead — amount, ending_balance, … —
+// some of them carrying float dust such as 12.349999 or
+// -22.159999999999968. Two mechanisms bring those rows across:
+//
+//   - On read, unmarshalItem / unmarshalItems convert any legacy attribute
+//     to its cents form before decoding, so a legacy row reads exactly like
+//     a migrated one.
+//   - On write, transactWrite converts the rows a transaction is about to
+//     update, one at a time, before sending it. The write paths only know
+//     the *_cents names — an arithmetic update of ending_balance_cents on a
+//     row that still holds ending_balance would cancel its transaction.
+//
+// EnsureMoneyMigrated then sweeps whatever rows no write has reached and
+// records the MIGRATION marker, after which the per-write conversion costs
+// nothing. It scans the whole table, so it runs from the scheduled jobs,
+// never on a request.
+const (
+	PKMigration      = "MIGRATION"
+	SKMigrationMoney = "MONEY_CENTS"
+)
+
+// legacyMoneyAttrs maps each float-dollar attribute written before the
+// switch to integer cents onto the attribute that replaced it. The two map
+// attributes hold float dollars per category and convert value by value.
+var legacyMoneyAttrs = map[string]string{
+	"total_balance":    "total_balance_cents",
+	"starting_balance": "starting_balance_cents",
+	"allowance_added":  "allowance_added_cents",
+	"total_expenses":   "total_expenses_cents",
+	"ending_balance":   "ending_balance_cents",
+	"amount":           "amount_cents",
+	"category_totals":  "category_totals_cents",
+	"category_budgets": "category_budgets_cents",
+}
+
+// maxMigrationAttempts bounds the re-read-and-retry loop for one row whose
+// legacy values changed between the scan and the conditional rewrite.
+const maxMigrationAttempts = 3
+
+// moneyValue renders m as a DynamoDB Number of cents for an expression
+// value. Integer cents compare exactly, so conditions such as
+// amount_cents = :oldAmount need none of the formatting care float dollars
+// did.
+func moneyValue(m model.Money) *types.AttributeValueMemberN {
+	return &types.AttributeValueMemberN{Value: strconv.FormatInt(int64(m), 10)}
+}
+
+// legacyCents converts one legacy float-dollar attribute value (a Number,
+// or a map of Numbers) to the equivalent cents value. The decimal string is
+// parsed exactly and rounded to the cent, which is also what strips float
+// dust from legacy rows.
+func legacyCents(av types.AttributeValue) (types.AttributeValue, error) {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberN:
+		m, err := model.ParseMoney(v.Value)
+		if err != nil {
+			return nil, err
+		}
+		return moneyValue(m), nil
+	case *types.AttributeValueMemberM:
+		out := make(map[string]types.AttributeValue, len(v.Value))
+		for k, e := range v.Value {
+			c, err := legacyCents(e)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+			out[k] = c
+		}
+		return &types.AttributeValueMemberM{Value: out}, nil
+	case *types.AttributeValueMemberNULL:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("%w: unexpected attribute type %T", model.ErrInvalidMoney, av)
+	}
+}
+
+// upgradeLegacyMoney rewrites a raw item's legacy money attributes to their
+// cents form, in place. Where both forms are present the cents attribute
+// wins: it is the one every current write path maintains. Reports whether
+// the item held any legacy attribute.
+func upgradeLegacyMoney(item map[string]types.AttributeValue) (bool, error) {
+	found := false
+	for legacy, cents := range legacyMoneyAttrs {
+		av, ok := item[legacy]
+		if !ok {
+			continue
+		}
+		found = true
+		delete(item, legacy)
+		if _, ok := item[cents]; ok {
+			continue
+		}
+		converted, err := legacyCents(av)
+		if err != nil {
+			return found, fmt.Errorf("legacy %s: %w", legacy, err)
+		}
+		item[cents] = converted
+	}
+	return found, nil
+}
+
+// unmarshalItem decodes a single item, converting any legacy money
+// attributes first. The caller's map is not modified. Every read of a row
+// that may carry money goes through here rather than
+// attributevalue.UnmarshalMap.
+func unmarshalItem(item map[string]types.AttributeValue, out interface{}) error {
+	upgraded := cloneValues(item)
+	if _, err := upgradeLegacyMoney(upgraded); err != nil {
+		return err
+	}
+	return attributevalue.UnmarshalMap(upgraded, out)
+}
+
+// unmarshalItems is unmarshalItem for a page of items.
+func unmarshalItems(items []map[string]types.AttributeValue, out interface{}) error {
+	upgraded := make([]map[string]types.AttributeValue, len(items))
+	for i, item := range items {
+		upgraded[i] = cloneValues(item)
+		if _, err := upgradeLegacyMoney(upgraded[i]); err != nil {
+			return err
+		}
+	}
+	return attributevalue.UnmarshalListOfMaps(upgraded, out)
+}
+
+// moneyMarkerKey is the key of the row EnsureMoneyMigrated writes once no
+// legacy row is left.
+var moneyMarkerKey = map[string]types.AttributeValue{
+	"PK": &types.AttributeValueMemberS{Value: PKMigration},
+	"SK": &types.AttributeValueMemberS{Value: SKMigrationMoney},
+}
+
+// moneyMarked reports whether the money migration marker exists, caching a
+// yes for the life of the process: once written, it is never removed.
+func (r *Repository) moneyMarked(ctx context.Context) (bool, error) {
+	if r.moneyMigrated.Load() {
+		return true, nil
+	}
+	marker, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       moneyMarkerKey,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to read money migration marker: %w", err)
+	}
+	if marker.Item == nil {
+		return false, nil
+	}
+	r.moneyMigrated.Store(true)
+	return true, nil
+}
+
+// upgradeMoneyRows converts whichever of the rows at keys still hold
+// float-dollar attributes, one conditional rewrite per legacy row. Until
+// the marker exists it costs a point read per key, so it is only handed
+// the rows a write is about to update.
+func (r *Repository) upgradeMoneyRows(ctx context.Context, keys ...map[string]types.AttributeValue) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	if done, err := r.moneyMarked(ctx); err != nil || done {
+		return err
+	}
+	for _, key := range keys {
+		result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+			TableName:      aws.String(r.tableName),
+			Key:            key,
+			ConsistentRead: aws.Bool(true),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to read row for money upgrade: %w", err)
+		}
+		if result.Item == nil {
+			continue
+		}
+		if err := r.migrateMoneyItem(ctx, result.Item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// transactWrite is TransactWriteItems for every transaction the repository
+// sends. The rows its updates, condition checks and conditional deletes
+// touch are brought across to cents first (upgradeMoneyRows); a Put
+// replaces its row whole and needs nothing.
+func (r *Repository) transactWrite(ctx context.Context, input *dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error) {
+	var keys []map[string]types.AttributeValue
+	for _, item := range input.TransactItems {
+		switch {
+		case item.Update != nil:
+			keys = append(keys, item.Update.Key)
+		case item.ConditionCheck != nil:
+			keys = append(keys, item.ConditionCheck.Key)
+		case item.Delete != nil && item.Delete.ConditionExpression != nil:
+			keys = append(keys, item.Delete.Key)
+		}
+	}
+	if err := r.upgradeMoneyRows(ctx, keys...); err != nil {
+		return nil, err
+	}
+	return r.transactWrite(ctx, input)
+}
+
+// EnsureMoneyMigrated rewrites every row still holding float-dollar money
+// attributes to integer cents, then records a marker row so later calls
+// cost a single point read. The rewrite is a one-time Scan: each legacy row
+// gets a conditional UpdateItem that SETs the cents attributes and REMOVEs
+// the legacy ones, pinned to the legacy values that were read. A row that
+// changed in between is re-read and retried; one that another caller has
+// already migrated is skipped. Safe to run concurrently and to re-run after
+// a partial failure — the marker is written only once every row is done.
+// The Scan reads the whole table, so this belongs to the scheduled jobs.
+func (r *Repository) EnsureMoneyMigrated(ctx context.Context) error {
+	if done, err := r.moneyMarked(ctx); err != nil || done {
+		return err
+	}
+
+	legacy := make([]string, 0, len(legacyMoneyAttrs))
+	for attr := range legacyMoneyAttrs {
+		legacy = append(legacy, attr)
+	}
+	sort.Strings(legacy)
+	names := make(map[string]string, len(legacy))
+	exists := make([]string, len(legacy))
+	for i, attr := range legacy {
+		name := fmt.Sprintf("#m%d", i)
+		names[name] = attr
+		exists[i] = "attribute_exists(" + name + ")"
+	}
+	filter := strings.Join(exists, " OR ")
+
+	var lastKey map[string]types.AttributeValue
+	for {
+		input := &dynamodb.ScanInput{
+			TableName:                aws.String(r.tableName),
+			FilterExpression:         aws.String(filter),
+			ExpressionAttributeNames: names,
+			ConsistentRead:           aws.Bool(true),
+		}
+		if lastKey != nil {
+			input.ExclusiveStartKey = lastKey
+		}
+		result, err := r.client.Scan(ctx, input)
+		if err != nil {
+			return fmt.Errorf("failed to scan for legacy money rows: %w", err)
+		}
+		for _, item := range result.Items {
+			if err := r.migrateMoneyItem(ctx, item); err != nil {
+				return err
+			}
+		}
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		lastKey = result.LastEvaluatedKey
+	}
+
+	_, err := r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item: map[string]types.AttributeValue{
+			"PK":         moneyMarkerKey["PK"],
+			"SK":         moneyMarkerKey["SK"],
+			"created_at": &types.AttributeValueMemberS{Value: time.Now().Format(time.RFC3339)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write money migration marker: %w", err)
+	}
+	r.moneyMigrated.Store(true)
+	return nil
+}
+
+// migrateMoneyItem rewrites one legacy row in place. Number attributes are
+// pinned by value (attr = :old) so a stale writer's change between the scan
+// and this update is not overwritten with the older figure; the category
+// maps, which no deployed version writes outside the gated mutation paths,
+// are pinned by existence.
+func (r *Repository) migrateMoneyItem(ctx context.Context, item map[string]types.AttributeValue) error {
+	key := map[string]types.AttributeValue{"PK": item["PK"], "SK": item["SK"]}
+
+	for attempt := 0; ; attempt++ {
+		upgraded := cloneValues(item)
+		found, err := upgradeLegacyMoney(upgraded)
+		if err != nil {
+			return fmt.Errorf("failed to convert legacy money row: %w", err)
+		}
+		if !found {
+			return nil
+		}
+
+		legacy := make([]string, 0, len(legacyMoneyAttrs))
+		for attr := range legacyMoneyAttrs {
+			if _, ok := item[attr]; ok {
+				legacy = append(legacy, attr)
+			}
+		}
+		sort.Strings(legacy)
+
+		names := map[string]string{}
+		values := map[string]types.AttributeValue{}
+		var set, remove, cond []string
+		for i, attr := range legacy {
+			cents := legacyMoneyAttrs[attr]
+			l, c := fmt.Sprintf("#l%d", i), fmt.Sprintf("#c%d", i)
+			names[l] = attr
+			remove = append(remove, l)
+			if _, ok := item[cents]; !ok {
+				names[c] = cents
+				values[":c"+strconv.Itoa(i)] = upgraded[cents]
+				set = append(set, fmt.Sprintf("%s = :c%d", c, i))
+			}
+			if n, ok := item[attr].(*types.AttributeValueMemberN); ok {
+				values[":l"+strconv.Itoa(i)] = n
+				cond = append(cond, fmt.Sprintf("%s = :l%d", l, i))
+			} else {
+				cond = append(cond, fmt.Sprintf("attribute_exists(%s)", l))
+			}
+		}
+
+		expr := ""
+		if len(set) > 0 {
+			expr = "SET " + strings.Join(set, ", ") + " "
+		}
+		expr += "REMOVE " + strings.Join(remove, ", ")
+		input := &dynamodb.UpdateItemInput{
+			TableName:                aws.String(r.tableName),
+			Key:                      key,
+			UpdateExpression:         aws.String(expr),
+			ConditionExpression:      aws.String(strings.Join(cond, " AND ")),
+			ExpressionAttributeNames: names,
+		}
+		if len(values) > 0 {
+			input.ExpressionAttributeValues = values
+		}
+		_, err = r.client.UpdateItem(ctx, input)
+		if err == nil {
+			return nil
+		}
+		var condErr *types.ConditionalCheckFailedException
+		if !errors.As(err, &condErr) || attempt+1 >= maxMigrationAttempts {
+			return fmt.Errorf("failed to migrate legacy money row: %w", err)
+		}
+
+		// Changed since the scan: re-read and convert what is there now.
+		fresh, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+			TableName:      aws.String(r.tableName),
+			Key:            key,
+			ConsistentRead: aws.Bool(true),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to re-read legacy money row: %w", err)
+		}
+		if fresh.Item == nil {
+			return nil
+		}
+		item = fresh.Item
+	}
+}
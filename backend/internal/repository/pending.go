@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vppillai/passbook/backend/internal/model"
+)
+
+// Pending storage keys. Expenses awaiting approval share one partition
+// (PK="PENDING", SK="PEND#<expense id>") so listing them is a single
+// Query.
+const (
+	PKPending     = "PENDING"
+	PendingPrefix = "PEND#"
+)
+
+// ErrPendingExpenseNotFound is returned by AtomicApprovePendingExpense and
+// AtomicRejectPendingExpense when the entry is already gone — approved or
+// rejected by another request.
+var ErrPendingExpenseNotFound = errors.New("pending expense not found")
+
+func pendingKey(ctx context.Context, id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKPending)},
+		"SK": &types.AttributeValueMemberS{Value: PendingPrefix + id},
+	}
+}
+
+// PendingAuditState is the journal's view of a pending expense.
+func PendingAuditState(p *model.PendingExpense) *model.AuditState {
+	amount, createdAt := p.Amount, p.CreatedAt
+	return &model.AuditState{
+		Month:       p.Month,
+		ExpenseID:   p.ID,
+		Amount:      &amount,
+		Description: p.Description,
+		Category:    p.Category,
+		CreatedAt:   &createdAt,
+	}
+}
+
+// PutPendingExpense files a submitted expense. Its id is freshly
+// generated, so the put is unconditional.
+func (r *Repository) PutPendingExpense(ctx context.Context, p *model.PendingExpense) error {
+	p.PK = AccountPK(ctx, PKPending)
+	p.SK = PendingPrefix + p.ID
+	item, err := attributevalue.MarshalMap(p)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pending expense: %w", err)
+	}
+	if _, err := r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	}); err != nil {
+		return fmt.Errorf("failed to save pending expense: %w", err)
+	}
+	return nil
+}
+
+// GetPendingExpense fetches one pending expense by id. Returns nil (no
+// error) when absent.
+func (r *Repository) GetPendingExpense(ctx context.Context, id string) (*model.PendingExpense, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       pendingKey(ctx, id),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending expense: %w", err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+	var p model.PendingExpense
+	if err := unmarshalItem(result.Item, &p); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pending expense: %w", err)
+	}
+	return &p, nil
+}
+
+// ListPendingExpenses returns every pending expense in id order.
+func (r *Repository) ListPendingExpenses(ctx context.Context) ([]model.PendingExpense, error) {
+	var out []model.PendingExpense
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(r.tableName),
+			KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :prefix)"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":pk":     &types.AttributeValueMemberS{Value: AccountPK(ctx, PKPending)},
+				":prefix": &types.AttributeValueMemberS{Value: PendingPrefix},
+			},
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pending expenses: %w", err)
+		}
+		var page []model.PendingExpense
+		if err := unmarshalItems(result.Items, &page); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal pending expenses: %w", err)
+		}
+		out = append(out, page...)
+		if len(result.LastEvaluatedKey) == 0 {
+			return out, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
+
+// DeletePendingExpense removes the entry of an expense that is already
+// booked. Deleting one that is already gone is not an error.
+func (r *Repository) DeletePendingExpense(ctx context.Context, id string) error {
+	_, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       pendingKey(ctx, id),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete pending expense: %w", err)
+	}
+	return nil
+}
+
+// AtomicApprovePendingExpense books a pending expense as AtomicAddExpense
+// does and deletes its PENDING row in the same transaction, conditioned on
+// the row still being there: ErrPendingExpenseNotFound when a concurrent
+// rejection removed it first, and nothing is booked.
+func (r *Repository) AtomicApprovePendingExpense(ctx context.Context, month string, expense *model.Expense, checkBalance bool) error {
+	return r.atomicAddExpense(ctx, month, expense, checkBalance, &types.Delete{
+		TableName:           aws.String(r.tableName),
+		Key:                 pendingKey(ctx, expense.SK),
+		ConditionExpression: aws.String("attribute_exists(PK)"),
+	}, ErrPendingExpenseNotFound)
+}
+
+// AtomicRejectPendingExpense deletes a pending expense and journals its
+// rejection with reason, in one transaction. ErrPendingExpenseNotFound
+// when it is already gone, so a rejection never journals an expense that
+// was approved meanwhile.
+func (r *Repository) AtomicRejectPendingExpense(ctx context.Context, p *model.PendingExpense, reason string) error {
+	entry := NewAuditEntry(ctx, model.AuditExpenseReject, p.Month, p.ID, PendingAuditState(p), nil)
+	entry.Reason = reason
+	audit, err := r.auditPut(entry)
+	if err != nil {
+		return err
+	}
+	_, err = r.transactWrite(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Delete: &types.Delete{
+				TableName:           aws.String(r.tableName),
+				Key:                 pendingKey(ctx, p.ID),
+				ConditionExpression: aws.String("attribute_exists(PK)"),
+			}},
+			audit,
+		},
+	})
+	if err != nil {
+		if idx, ok := txConditionFailedIndex(err); ok && idx == 0 {
+			return ErrPendingExpenseNotFound
+		}
+		return fmt.Errorf("failed to reject pending expense: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vppillai/passbook/backend/internal/model"
+)
+
+// Recurring-expense storage keys. Every schedule lives in one partition
+// (PK="RECURRING", SK="RECUR#<id>") so the run can enumerate them with a
+// single Query, the same shape as MONTHLIST and WACREDLIST.
+const (
+	PKRecurring     = "RECURRING"
+	RecurringPrefix = "RECUR#"
+)
+
+// ErrRecurringNotFound is returned by UpdateRecurringExpense when the
+// schedule does not exist (never created, or deleted concurrently).
+var ErrRecurringNotFound = errors.New("recurring expense not found")
+
+func recurringKey(ctx context.Context, id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKRecurring)},
+		"SK": &types.AttributeValueMemberS{Value: RecurringPrefix + id},
+	}
+}
+
+// CreateRecurringExpense writes a new schedule row. The id is a fresh uuid
+// fragment, so the attribute_not_exists guard only matters on a collision.
+func (r *Repository) CreateRecurringExpense(ctx context.Context, rec *model.RecurringExpense) error {
+	rec.PK = AccountPK(ctx, PKRecurring)
+	rec.SK = RecurringPrefix + rec.ID
+	item, err := attributevalue.MarshalMap(rec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal recurring expense: %w", err)
+	}
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(PK)"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create recurring expense: %w", err)
+	}
+	return nil
+}
+
+// GetRecurringExpense fetches one schedule by id. Returns nil (no error)
+// when absent.
+func (r *Repository) GetRecurringExpense(ctx context.Context, id string) (*model.RecurringExpense, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       recurringKey(ctx, id),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recurring expense: %w", err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+	var rec model.RecurringExpense
+	if err := unmarshalItem(result.Item, &rec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal recurring expense: %w", err)
+	}
+	return &rec, nil
+}
+
+// ListRecurringExpenses returns every schedule, oldest id order. A family
+// keeps a handful of these; the loop only guards against the 1MB page cap.
+func (r *Repository) ListRecurringExpenses(ctx context.Context) ([]model.RecurringExpense, error) {
+	var out []model.RecurringExpense
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(r.tableName),
+			KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :prefix)"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":pk":     &types.AttributeValueMemberS{Value: AccountPK(ctx, PKRecurring)},
+				":prefix": &types.AttributeValueMemberS{Value: RecurringPrefix},
+			},
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list recurring expenses: %w", err)
+		}
+		var page []model.RecurringExpense
+		if err := unmarshalItems(result.Items, &page); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal recurring expenses: %w", err)
+		}
+		out = append(out, page...)
+		if len(result.LastEvaluatedKey) == 0 {
+			return out, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
+
+// UpdateRecurringExpense rewrites a schedule's editable fields. It is an
+// UpdateItem rather than a Put so it never touches last_booked_month: a
+// Put racing a run could move the booking cursor backwards, and with a new
+// day of month the re-booked occurrence would get a different SK and slip
+// past the duplicate guard.
+func (r *Repository) UpdateRecurringExpense(ctx context.Context, rec *model.RecurringExpense) error {
+	rec.UpdatedAt = time.Now()
+	updateExpr := "SET amount_cents = :amount, description = :desc, category = :cat, day_of_month = :day, end_month = :end, updated_at = :now"
+	values := map[string]types.AttributeValue{
+		":amount": moneyValue(rec.Amount),
+		":desc":   &types.AttributeValueMemberS{Value: rec.Description},
+		":cat":    &types.AttributeValueMemberS{Value: rec.Category},
+		":day":    &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", rec.DayOfMonth)},
+		":end":    &types.AttributeValueMemberS{Value: rec.EndMonth},
+		":now":    &types.AttributeValueMemberS{Value: rec.UpdatedAt.Format(time.RFC3339Nano)},
+	}
+	_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.tableName),
+		Key:                       recurringKey(ctx, rec.ID),
+		UpdateExpression:          aws.String(updateExpr),
+		ConditionExpression:       aws.String("attribute_exists(PK)"),
+		ExpressionAttributeValues: values,
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return ErrRecurringNotFound
+		}
+		return fmt.Errorf("failed to update recurring expense: %w", err)
+	}
+	return nil
+}
+
+// DeleteRecurringExpense removes a schedule and returns it, or nil if it
+// did not exist. Occurrences already booked are ordinary expenses and stay.
+func (r *Repository) DeleteRecurringExpense(ctx context.Context, id string) (*model.RecurringExpense, error) {
+	result, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:    aws.String(r.tableName),
+		Key:          recurringKey(ctx, id),
+		ReturnValues: types.ReturnValueAllOld,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete recurring expense: %w", err)
+	}
+	if result.Attributes == nil {
+		return nil, nil
+	}
+	var rec model.RecurringExpense
+	if err := unmarshalItem(result.Attributes, &rec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal recurring expense: %w", err)
+	}
+	return &rec, nil
+}
+
+// MarkRecurringBooked advances a schedule's last_booked_month to month.
+// The condition only lets the cursor move forward, so two overlapping runs
+// cannot rewind it; a failed condition (cursor already past, or the
+// schedule deleted mid-run) is not an error.
+func (r *Repository) MarkRecurringBooked(ctx context.Context, id, month string) error {
+	_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(r.tableName),
+		Key:                 recurringKey(ctx, id),
+		UpdateExpression:    aws.String("SET last_booked_month = :month"),
+		ConditionExpression: aws.String("attribute_exists(PK) AND (attribute_not_exists(last_booked_month) OR last_booked_month < :month)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":month": &types.AttributeValueMemberS{Value: month},
+		},
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return nil
+		}
+		return fmt.Errorf("failed to mark recurring expense booked: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,352 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vppillai/passbook/backend/internal/model"
+)
+
+// Search index.
+//
+// Every word of an expense description gets one row in a single partition,
+// PK="SEARCH", SK="<word>#<yyyy-mm>#<expense SK>". Words are lower-cased
+// runs of letters and digits, so a prefix search is one Query with
+// begins_with(SK, :prefix) — no scan of the month partitions — and within
+// one word the rows sort chronologically, because the month and the
+// expense SK's unixnano do.
+//
+// The entries are written by the same TransactWriteItems that add, edit,
+// re-date or delete the expense (see searchIndexItems), so the index never
+// misses a committed description. The two writers that cannot afford the
+// extra items — the instalment-plan transactions, which already carry
+// three items per month — re-index afterwards with ReindexExpense. Readers
+// re-check every hit against the expense row itself, so an entry left
+// behind by that follow-up or a backfill race is skipped, never served.
+const (
+	PKSearch          = "SEARCH"
+	SKMigrationSearch = "SEARCH_INDEX"
+
+	// minSearchTokenRunes drops one-character words ("a", "&" remnants),
+	// which would match nearly everything as a prefix.
+	minSearchTokenRunes = 2
+	// maxSearchTokens bounds the entries one expense can own. An edit
+	// that replaces every word costs a delete and a put per word inside
+	// the cross-month move's transaction (7 items), so this keeps that well
+	// under maxTransactItems. A 100-character description cannot hold more
+	// distinct two-letter words than this anyway.
+	maxSearchTokens = 32
+)
+
+// SearchHit is one search-index entry: Token, a word of the expense's
+// description, matched the searched prefix.
+type SearchHit struct {
+	Token     string
+	Month     string
+	ExpenseID string
+}
+
+// Key is the entry's primary key, which is also the ExclusiveStartKey to
+// resume a search after it.
+func (h SearchHit) Key(ctx context.Context) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKSearch)},
+		"SK": &types.AttributeValueMemberS{Value: searchEntrySK(h.Token, h.Month, h.ExpenseID)},
+	}
+}
+
+// ExpenseRef addresses one expense row for BatchGetExpenses.
+type ExpenseRef struct {
+	Month     string
+	ExpenseID string
+}
+
+// SearchTokens splits text into the distinct words the search index files
+// it under, in order of first appearance: lower-cased runs of letters and
+// digits of at least two characters, at most maxSearchTokens of them. The
+// service tokenizes queries with the same function, so a query word and an
+// indexed word always compare like for like.
+func SearchTokens(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	seen := make(map[string]bool, len(fields))
+	var out []string
+	for _, f := range fields {
+		if utf8.RuneCountInString(f) < minSearchTokenRunes || seen[f] {
+			continue
+		}
+		seen[f] = true
+		out = append(out, f)
+		if len(out) == maxSearchTokens {
+			break
+		}
+	}
+	return out
+}
+
+func searchEntrySK(token, month, expenseID string) string {
+	return token + "#" + month + "#" + expenseID
+}
+
+// searchIndexDiff returns the index entries to put and the keys to delete
+// to move an expense's entries from old (in oldMonth) to updated (in
+// newMonth). Either expense may be nil: nil old is a new expense, nil
+// updated a deleted one. Entries present on both sides are left alone, so
+// an edit that keeps the description and the SK touches nothing.
+func searchIndexDiff(ctx context.Context, oldMonth string, old *model.Expense, newMonth string, updated *model.Expense) (puts, deletes []map[string]types.AttributeValue) {
+	keep := map[string]bool{}
+	if updated != nil {
+		for _, t := range SearchTokens(updated.Description) {
+			keep[searchEntrySK(t, newMonth, updated.SK)] = true
+		}
+	}
+	had := map[string]bool{}
+	if old != nil {
+		for _, t := range SearchTokens(old.Description) {
+			sk := searchEntrySK(t, oldMonth, old.SK)
+			had[sk] = true
+			if !keep[sk] {
+				deletes = append(deletes, map[string]types.AttributeValue{
+					"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKSearch)},
+					"SK": &types.AttributeValueMemberS{Value: sk},
+				})
+			}
+		}
+	}
+	if updated != nil {
+		for _, t := range SearchTokens(updated.Description) {
+			sk := searchEntrySK(t, newMonth, updated.SK)
+			if had[sk] {
+				continue
+			}
+			puts = append(puts, map[string]types.AttributeValue{
+				"PK":         &types.AttributeValueMemberS{Value: AccountPK(ctx, PKSearch)},
+				"SK":         &types.AttributeValueMemberS{Value: sk},
+				"token":      &types.AttributeValueMemberS{Value: t},
+				"month":      &types.AttributeValueMemberS{Value: newMonth},
+				"expense_id": &types.AttributeValueMemberS{Value: updated.SK},
+			})
+		}
+	}
+	return puts, deletes
+}
+
+// searchIndexItems is searchIndexDiff as transaction items, appended after
+// a mutation's own items so the indexes its failure mapping relies on do
+// not move.
+func (r *Repository) searchIndexItems(ctx context.Context, oldMonth string, old *model.Expense, newMonth string, updated *model.Expense) []types.TransactWriteItem {
+	puts, deletes := searchIndexDiff(ctx, oldMonth, old, newMonth, updated)
+	items := make([]types.TransactWriteItem, 0, len(puts)+len(deletes))
+	for _, key := range deletes {
+		items = append(items, types.TransactWriteItem{Delete: &types.Delete{
+			TableName: aws.String(r.tableName),
+			Key:       key,
+		}})
+	}
+	for _, item := range puts {
+		items = append(items, types.TransactWriteItem{Put: &types.Put{
+			TableName: aws.String(r.tableName),
+			Item:      item,
+		}})
+	}
+	return items
+}
+
+// ReindexExpense brings the search entries of one expense from old to
+// updated outside any transaction (either may be nil, as in
+// searchIndexDiff). It is for writers whose transaction has no room for
+// the index; the entries it writes are idempotent, so a retry is safe.
+func (r *Repository) ReindexExpense(ctx context.Context, oldMonth string, old *model.Expense, newMonth string, updated *model.Expense) error {
+	puts, deletes := searchIndexDiff(ctx, oldMonth, old, newMonth, updated)
+	writes := make([]types.WriteRequest, 0, len(puts)+len(deletes))
+	for _, key := range deletes {
+		writes = append(writes, types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: key}})
+	}
+	for _, item := range puts {
+		writes = append(writes, types.WriteRequest{PutRequest: &types.PutRequest{Item: item}})
+	}
+	if err := r.writeSearchEntries(ctx, writes); err != nil {
+		return fmt.Errorf("failed to reindex expense: %w", err)
+	}
+	return nil
+}
+
+// writeSearchEntries batch-writes index entries in chunks of 25 (the
+// BatchWriteItem limit).
+func (r *Repository) writeSearchEntries(ctx context.Context, writes []types.WriteRequest) error {
+	for i := 0; i < len(writes); i += 25 {
+		end := min(i+25, len(writes))
+		if err := r.batchWriteWithRetry(ctx, map[string][]types.WriteRequest{
+			r.tableName: writes[i:end],
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SearchExpenses returns up to limit index entries whose word begins with
+// prefix, newest first within each word, resuming after cursor (a previous
+// hit's Key or LastEvaluatedKey). prefix must be one SearchTokens word.
+func (r *Repository) SearchExpenses(ctx context.Context, prefix string, limit int32, cursor map[string]types.AttributeValue) ([]SearchHit, map[string]types.AttributeValue, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :prefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":     &types.AttributeValueMemberS{Value: AccountPK(ctx, PKSearch)},
+			":prefix": &types.AttributeValueMemberS{Value: prefix},
+		},
+		ScanIndexForward: aws.Bool(false),
+	}
+	if limit > 0 {
+		input.Limit = aws.Int32(limit)
+	}
+	if cursor != nil {
+		input.ExclusiveStartKey = cursor
+	}
+	result, err := r.client.Query(ctx, input)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to search expenses: %w", err)
+	}
+	hits := make([]SearchHit, 0, len(result.Items))
+	for _, item := range result.Items {
+		token, _ := item["token"].(*types.AttributeValueMemberS)
+		month, _ := item["month"].(*types.AttributeValueMemberS)
+		id, _ := item["expense_id"].(*types.AttributeValueMemberS)
+		if token == nil || month == nil || id == nil {
+			continue
+		}
+		hits = append(hits, SearchHit{Token: token.Value, Month: month.Value, ExpenseID: id.Value})
+	}
+	return hits, result.LastEvaluatedKey, nil
+}
+
+// BatchGetExpenses fetches the referenced expense rows with BatchGetItem
+// (up to 100 refs, which must be distinct). Rows that no longer exist are
+// simply absent from the result; the order is not the refs' order.
+func (r *Repository) BatchGetExpenses(ctx context.Context, refs []ExpenseRef) ([]model.Expense, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	keys := make([]map[string]types.AttributeValue, len(refs))
+	for i, ref := range refs {
+		keys[i] = map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, MonthPrefix+ref.Month)},
+			"SK": &types.AttributeValueMemberS{Value: ref.ExpenseID},
+		}
+	}
+
+	const maxAttempts = 5
+	const baseDelay = 50 * time.Millisecond
+	var out []model.Expense
+	pending := map[string]types.KeysAndAttributes{r.tableName: {Keys: keys}}
+	for attempt := 0; ; attempt++ {
+		result, err := r.client.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{RequestItems: pending})
+		if err != nil {
+			return nil, fmt.Errorf("failed to batch get expenses: %w", err)
+		}
+		var page []model.Expense
+		if err := unmarshalItems(result.Responses[r.tableName], &page); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal expenses: %w", err)
+		}
+		out = append(out, page...)
+		if len(result.UnprocessedKeys) == 0 {
+			return out, nil
+		}
+		if attempt+1 >= maxAttempts {
+			return nil, fmt.Errorf("failed to batch get expenses: keys still unprocessed after %d attempts", maxAttempts)
+		}
+		// Same backoff as batchWriteWithRetry.
+		cap := baseDelay * (1 << (attempt + 1))
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Duration(rand.Int64N(int64(cap)))):
+		}
+		pending = result.UnprocessedKeys
+	}
+}
+
+// EnsureSearchIndex indexes every expense written before the search index
+// existed, then records a marker row so later calls cost a single point
+// read. The backfill is a one-time Scan of the expense rows with idempotent
+// Puts; an expense edited or deleted while it runs can leave an extra entry
+// behind, which readers skip (see the package comment above). Safe to run
+// concurrently and to re-run after a partial failure.
+func (r *Repository) EnsureSearchIndex(ctx context.Context) error {
+	marker, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: PKMigration},
+			"SK": &types.AttributeValueMemberS{Value: SKMigrationSearch},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read search index marker: %w", err)
+	}
+	if marker.Item != nil {
+		return nil
+	}
+
+	// Only the default account can hold expenses from before the index:
+	// every other account was created since, and indexed as it went. The
+	// scan's MONTH# prefix matches the default account alone, and its
+	// entries belong in that account's partition whatever ctx says.
+	ledger := WithAccount(ctx, model.DefaultAccountID)
+	var lastKey map[string]types.AttributeValue
+	for {
+		result, err := r.client.Scan(ctx, &dynamodb.ScanInput{
+			TableName:            aws.String(r.tableName),
+			FilterExpression:     aws.String("begins_with(PK, :monthPrefix) AND begins_with(SK, :expensePrefix)"),
+			ProjectionExpression: aws.String("PK, SK, description"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":monthPrefix":   &types.AttributeValueMemberS{Value: MonthPrefix},
+				":expensePrefix": &types.AttributeValueMemberS{Value: ExpensePrefix},
+			},
+			ExclusiveStartKey: lastKey,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan expenses for search index: %w", err)
+		}
+		var writes []types.WriteRequest
+		for _, item := range result.Items {
+			var e model.Expense
+			if err := unmarshalItem(item, &e); err != nil {
+				return fmt.Errorf("failed to unmarshal expense: %w", err)
+			}
+			puts, _ := searchIndexDiff(ledger, "", nil, MonthOfPK(e.PK), &e)
+			for _, put := range puts {
+				writes = append(writes, types.WriteRequest{PutRequest: &types.PutRequest{Item: put}})
+			}
+		}
+		if err := r.writeSearchEntries(ctx, writes); err != nil {
+			return fmt.Errorf("failed to backfill search index: %w", err)
+		}
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		lastKey = result.LastEvaluatedKey
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item: map[string]types.AttributeValue{
+			"PK":         &types.AttributeValueMemberS{Value: PKMigration},
+			"SK":         &types.AttributeValueMemberS{Value: SKMigrationSearch},
+			"created_at": &types.AttributeValueMemberS{Value: time.Now().Format(time.RFC3339)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write search index marker: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vppillai/passbook/backend/internal/model"
+)
+
+// Trash storage keys. Deleted expenses share one partition (PK="TRASH",
+// SK="TRASH#<expense SK>") so listing them is a single Query; the table's
+// TTL removes each entry once its retention is up.
+const (
+	PKTrash     = "TRASH"
+	TrashPrefix = "TRASH#"
+)
+
+// ErrTrashedExpenseNotFound is returned by AtomicRestoreTrashedExpense when
+// the entry is already gone — restored by another request, or expired.
+var ErrTrashedExpenseNotFound = errors.New("trashed expense not found")
+
+func trashKey(ctx context.Context, id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: AccountPK(ctx, PKTrash)},
+		"SK": &types.AttributeValueMemberS{Value: TrashPrefix + id},
+	}
+}
+
+// trashPut is the transaction item that files a deleted expense in the
+// trash. It is unconditional: the only entry it can replace is a leftover
+// of the same expense from a restore that did not finish cleaning up.
+func (r *Repository) trashPut(ctx context.Context, trash *model.TrashedExpense) (types.TransactWriteItem, error) {
+	trash.PK = AccountPK(ctx, PKTrash)
+	trash.SK = TrashPrefix + trash.ID
+	item, err := attributevalue.MarshalMap(trash)
+	if err != nil {
+		return types.TransactWriteItem{}, fmt.Errorf("failed to marshal trashed expense: %w", err)
+	}
+	return types.TransactWriteItem{Put: &types.Put{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	}}, nil
+}
+
+// GetTrashedExpense fetches one trash entry by expense id. Returns nil (no
+// error) when absent. An entry past its expiry may still be returned until
+// the TTL sweep reaches it; the service checks ExpiresAt.
+func (r *Repository) GetTrashedExpense(ctx context.Context, id string) (*model.TrashedExpense, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       trashKey(ctx, id),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trashed expense: %w", err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+	var trash model.TrashedExpense
+	if err := unmarshalItem(result.Item, &trash); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal trashed expense: %w", err)
+	}
+	return &trash, nil
+}
+
+// ListTrash returns every trash entry in expense-id order, including any
+// that have expired but not yet been swept.
+func (r *Repository) ListTrash(ctx context.Context) ([]model.TrashedExpense, error) {
+	var out []model.TrashedExpense
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(r.tableName),
+			KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :prefix)"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":pk":     &types.AttributeValueMemberS{Value: AccountPK(ctx, PKTrash)},
+				":prefix": &types.AttributeValueMemberS{Value: TrashPrefix},
+			},
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list trash: %w", err)
+		}
+		var page []model.TrashedExpense
+		if err := unmarshalItems(result.Items, &page); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal trash: %w", err)
+		}
+		out = append(out, page...)
+		if len(result.LastEvaluatedKey) == 0 {
+			return out, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
+
+// DeleteTrashedExpense removes a trash entry. Deleting one that is already
+// gone is not an error.
+func (r *Repository) DeleteTrashedExpense(ctx context.Context, id string) error {
+	_, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       trashKey(ctx, id),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete trashed expense: %w", err)
+	}
+	return nil
+}
+
+// AtomicRestoreTrashedExpense books a trashed expense as AtomicAddExpense
+// does and deletes its TRASH row in the same transaction, conditioned on
+// the row still being there: ErrTrashedExpenseNotFound when a concurrent
+// restore removed it first, and nothing is booked.
+func (r *Repository) AtomicRestoreTrashedExpense(ctx context.Context, month string, expense *model.Expense, checkBalance bool) error {
+	return r.atomicAddExpense(ctx, month, expense, checkBalance, &types.Delete{
+		TableName:           aws.String(r.tableName),
+		Key:                 trashKey(ctx, expense.SK),
+		ConditionExpression: aws.String("attribute_exists(PK)"),
+	}, ErrTrashedExpenseNotFound)
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+)
+
+var (
+	// ErrAccountNotFound is returned when an account id, in a path or in
+	// the account header, is not registered. Handler maps to 404.
+	ErrAccountNotFound = errors.New("account not found")
+	// ErrAccountNameRequired is returned when an account's name is empty
+	// after trimming. Handler maps to 400.
+	ErrAccountNameRequired = errors.New("account name is required")
+	// ErrDefaultAccountDelete is returned when asked to delete the default
+	// account, which holds the instance's original ledger. Handler maps
+	// to 400.
+	ErrDefaultAccountDelete = errors.New("the default account cannot be deleted")
+	// ErrAccountNotEmpty is returned when deleting an account that still
+	// has months, recurring expenses, goals, instalment plans, pending
+	// expenses, chores or chore completions. Handler maps to 409.
+	ErrAccountNotEmpty = errors.New("account is not empty")
+)
+
+// ListAccounts returns every account, the default one first and the rest
+// by name.
+func (s *ExpenseService) ListAccounts(ctx context.Context) (*model.AccountsResponse, error) {
+	rows, err := s.repo.ListAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	def := model.Account{ID: model.DefaultAccountID, Name: model.DefaultAccountName}
+	others := make([]model.Account, 0, len(rows))
+	for _, a := range rows {
+		if a.ID == model.DefaultAccountID {
+			def = a
+			continue
+		}
+		others = append(others, a)
+	}
+	sort.SliceStable(others, func(i, j int) bool {
+		return strings.ToLower(others[i].Name) < strings.ToLower(others[j].Name)
+	})
+	return &model.AccountsResponse{Accounts: append([]model.Account{def}, others...)}, nil
+}
+
+// CheckAccount reports whether id names an account: the default one, or
+// one with a registry row. ErrAccountNotFound otherwise.
+func (s *ExpenseService) CheckAccount(ctx context.Context, id string) error {
+	if id == model.DefaultAccountID {
+		return nil
+	}
+	account, err := s.repo.GetAccount(ctx, id)
+	if err != nil {
+		return err
+	}
+	if account == nil {
+		return ErrAccountNotFound
+	}
+	return nil
+}
+
+// CreateAccount registers a new, empty ledger. Its first month is
+// activated like any other: by the daily run or an explicit POST
+// /api/month made with the account selected.
+func (s *ExpenseService) CreateAccount(ctx context.Context, name string) (*model.Account, error) {
+	name, err := validateAccountName(name)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	account := &model.Account{
+		ID:        uuid.New().String()[:8],
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.CreateAccount(ctx, account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// RenameAccount changes an account's name. The default account's first
+// rename gives it a registry row.
+func (s *ExpenseService) RenameAccount(ctx context.Context, id, name string) (*model.Account, error) {
+	name, err := validateAccountName(name)
+	if err != nil {
+		return nil, err
+	}
+	account, err := s.repo.GetAccount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		if id != model.DefaultAccountID {
+			return nil, ErrAccountNotFound
+		}
+		account = &model.Account{ID: id}
+	}
+	account.Name = name
+	account.UpdatedAt = time.Now()
+	if err := s.repo.UpdateAccount(ctx, account); err != nil {
+		if errors.Is(err, repository.ErrAccountNotFound) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+	return account, nil
+}
+
+// DeleteAccount removes an empty account: no months (and so no expenses,
+// credits or balance), recurring expenses, goals, instalment plans,
+// pending expenses, chores or chore completions. Its expired trash and journal entries are left to
+// their TTL and the journal.
+func (s *ExpenseService) DeleteAccount(ctx context.Context, id string) error {
+	if id == model.DefaultAccountID {
+		return ErrDefaultAccountDelete
+	}
+	if err := s.CheckAccount(ctx, id); err != nil {
+		return err
+	}
+	empty, err := s.accountIsEmpty(repository.WithAccount(ctx, id))
+	if err != nil {
+		return err
+	}
+	if !empty {
+		return ErrAccountNotEmpty
+	}
+	switch err := s.repo.DeleteAccount(ctx, id); {
+	case errors.Is(err, repository.ErrAccountNotFound):
+		return ErrAccountNotFound
+	case errors.Is(err, repository.ErrAccountHasBalance):
+		// A month was activated since the check above.
+		return ErrAccountNotEmpty
+	default:
+		return err
+	}
+}
+
+// accountIsEmpty reports whether the context's account holds nothing that
+// deleting it would lose.
+func (s *ExpenseService) accountIsEmpty(ctx context.Context) (bool, error) {
+	months, _, err := s.repo.ListMonths(ctx, 1, nil)
+	if err != nil || len(months) > 0 {
+		return false, err
+	}
+	recurring, err := s.repo.ListRecurringExpenses(ctx)
+	if err != nil || len(recurring) > 0 {
+		return false, err
+	}
+	goals, err := s.repo.ListGoals(ctx)
+	if err != nil || len(goals) > 0 {
+		return false, err
+	}
+	plans, err := s.repo.ListInstalmentPlans(ctx)
+	if err != nil || len(plans) > 0 {
+		return false, err
+	}
+	pending, err := s.repo.ListPendingExpenses(ctx)
+	if err != nil || len(pending) > 0 {
+		return false, err
+	}
+	chores, err := s.repo.ListChores(ctx)
+	if err != nil || len(chores) > 0 {
+		return false, err
+	}
+	completions, err := s.repo.ListChoreCompletions(ctx)
+	if err != nil || len(completions) > 0 {
+		return false, err
+	}
+	return true, nil
+}
+
+// validateAccountName trims a name and bounds it like a description.
+func validateAccountName(name string) (string, error) {
+	name, err := validateDescription(name)
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		return "", ErrAccountNameRequired
+	}
+	return name, nil
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+)
+
+// =====================================================================
+// Accounts — each account is its own ledger, selected by the context;
+// the default account is the instance's original one.
+// =====================================================================
+
+func TestAccounts_LedgersAreSeparate(t *testing.T) {
+	svc, repo := newExpenseService(t, false, true, 100)
+	ctx := context.Background()
+	month := time.Now().UTC().Format("2006-01")
+
+	account, err := svc.CreateAccount(ctx, "  Sam  ")
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	if account.Name != "Sam" {
+		t.Errorf("name = %q, want it trimmed", account.Name)
+	}
+	sam := repository.WithAccount(ctx, account.ID)
+	if _, err := svc.CreateMonth(sam, month); err != nil {
+		t.Fatalf("CreateMonth: %v", err)
+	}
+	if _, err := svc.AddExpense(sam, &model.AddExpenseRequest{Amount: model.Dollars(30), Description: "Lego"}); err != nil {
+		t.Fatalf("AddExpense: %v", err)
+	}
+
+	balance, err := svc.GetBalance(sam)
+	if err != nil || balance.TotalBalance != model.Dollars(70) {
+		t.Fatalf("account balance = %+v, %v, want 70", balance, err)
+	}
+	if len(repo.Months) != 0 || repo.Balance.TotalBalance != 0 {
+		t.Errorf("default ledger = %v months, balance %v, want it untouched", len(repo.Months), repo.Balance.TotalBalance)
+	}
+	if got := repo.Ledger(account.ID).Months[month]; got == nil || got.EndingBalance != model.Dollars(70) {
+		t.Errorf("account month = %+v, want 100 - 30 = 70", got)
+	}
+	if hits, err := svc.Search(sam, "lego", 10, ""); err != nil || len(hits.Results) != 1 {
+		t.Errorf("account search = %+v, %v, want the expense", hits, err)
+	}
+	if hits, err := svc.Search(ctx, "lego", 10, ""); err != nil || len(hits.Results) != 0 {
+		t.Errorf("default search = %+v, %v, want nothing from another account", hits, err)
+	}
+	if last := repo.Audit[len(repo.Audit)-1]; last.Action != model.AuditExpenseAdd || last.Account != account.ID {
+		t.Errorf("journal = %+v, want the expense journaled under %s", last, account.ID)
+	}
+}
+
+func TestAccounts_ListAndRename(t *testing.T) {
+	svc, _ := newExpenseService(t, false, true, 100)
+	ctx := context.Background()
+	for _, name := range []string{"Zoe", "ava"} {
+		if _, err := svc.CreateAccount(ctx, name); err != nil {
+			t.Fatalf("CreateAccount(%s): %v", name, err)
+		}
+	}
+	if _, err := svc.RenameAccount(ctx, model.DefaultAccountID, "Family"); err != nil {
+		t.Fatalf("rename default: %v", err)
+	}
+
+	resp, err := svc.ListAccounts(ctx)
+	if err != nil {
+		t.Fatalf("ListAccounts: %v", err)
+	}
+	var names []string
+	for _, a := range resp.Accounts {
+		names = append(names, a.Name)
+	}
+	if len(names) != 3 || names[0] != "Family" || names[1] != "ava" || names[2] != "Zoe" {
+		t.Errorf("accounts = %v, want the default first, then by name", names)
+	}
+	if resp.Accounts[0].ID != model.DefaultAccountID {
+		t.Errorf("first id = %q, want the default account", resp.Accounts[0].ID)
+	}
+
+	if _, err := svc.RenameAccount(ctx, "missing", "X"); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("rename missing err = %v, want ErrAccountNotFound", err)
+	}
+	if _, err := svc.CreateAccount(ctx, " "); !errors.Is(err, ErrAccountNameRequired) {
+		t.Errorf("blank name err = %v, want ErrAccountNameRequired", err)
+	}
+}
+
+func TestAccounts_DeleteOnlyWhenEmpty(t *testing.T) {
+	svc, _ := newExpenseService(t, false, true, 100)
+	ctx := context.Background()
+	account, err := svc.CreateAccount(ctx, "Sam")
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	sam := repository.WithAccount(ctx, account.ID)
+	if _, err := svc.CreateMonth(sam, time.Now().UTC().Format("2006-01")); err != nil {
+		t.Fatalf("CreateMonth: %v", err)
+	}
+
+	if err := svc.DeleteAccount(ctx, model.DefaultAccountID); !errors.Is(err, ErrDefaultAccountDelete) {
+		t.Errorf("delete default err = %v, want ErrDefaultAccountDelete", err)
+	}
+	if err := svc.DeleteAccount(ctx, account.ID); !errors.Is(err, ErrAccountNotEmpty) {
+		t.Fatalf("delete with a month err = %v, want ErrAccountNotEmpty", err)
+	}
+
+	empty, err := svc.CreateAccount(ctx, "Unused")
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	if err := svc.DeleteAccount(ctx, empty.ID); err != nil {
+		t.Fatalf("delete empty: %v", err)
+	}
+	if err := svc.CheckAccount(ctx, empty.ID); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("CheckAccount after delete err = %v, want ErrAccountNotFound", err)
+	}
+	if err := svc.DeleteAccount(ctx, empty.ID); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("second delete err = %v, want ErrAccountNotFound", err)
+	}
+}
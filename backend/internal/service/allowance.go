@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+)
+
+var (
+	// ErrInvalidAllowance is returned when a schedule step's amount is
+	// negative or above maxAmount. Handler maps to 400.
+	ErrInvalidAllowance = errors.New("allowance must be between 0 and 99999.99")
+	// ErrInvalidAllowanceSchedule is returned when a schedule step has a
+	// malformed month, two steps start in the same month, or the schedule
+	// has more than maxAllowanceSteps steps. Handler maps to 400.
+	ErrInvalidAllowanceSchedule = errors.New("invalid allowance schedule")
+)
+
+// maxAllowanceSteps bounds the CONFIG row's schedule. Ten years of monthly
+// raises is far more than any family needs; the cap only keeps a runaway
+// client from growing the item toward DynamoDB's 400KB limit.
+const maxAllowanceSteps = 120
+
+// GetAllowanceSchedule returns the dated allowance schedule and the
+// deployment default it falls back to. An instance with no schedule (or no
+// CONFIG row yet) gets an empty one.
+func (s *ExpenseService) GetAllowanceSchedule(ctx context.Context) (*model.AllowanceSchedule, error) {
+	config, err := s.repo.GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := &model.AllowanceSchedule{Default: s.monthlyAllowance, Schedule: []model.AllowanceRate{}}
+	if config != nil {
+		out.Schedule = append(out.Schedule, config.AllowanceSchedule...)
+	}
+	return out, nil
+}
+
+// SetAllowanceSchedule replaces the whole schedule on the CONFIG row, sorted
+// by month. A change only applies to months created after it: a month
+// keeps the allowance it was granted, so raising the allowance from a past
+// month does not rewrite history. Use add-funds to back-pay a month.
+//
+// Only the schedule attribute of the CONFIG row is written (UpdateConfig),
+// as SetCategoryBudgets does.
+func (s *ExpenseService) SetAllowanceSchedule(ctx context.Context, req *model.AllowanceSchedule) (*model.AllowanceSchedule, error) {
+	if len(req.Schedule) > maxAllowanceSteps {
+		return nil, ErrInvalidAllowanceSchedule
+	}
+	schedule := make([]model.AllowanceRate, 0, len(req.Schedule))
+	seen := make(map[string]bool, len(req.Schedule))
+	for _, step := range req.Schedule {
+		if ValidateMonth(step.From) != nil || seen[step.From] {
+			return nil, ErrInvalidAllowanceSchedule
+		}
+		if step.Amount < 0 || step.Amount > maxAmount {
+			return nil, ErrInvalidAllowance
+		}
+		seen[step.From] = true
+		schedule = append(schedule, step)
+	}
+	sort.Slice(schedule, func(i, j int) bool { return schedule[i].From < schedule[j].From })
+
+	config := &model.Config{AllowanceSchedule: schedule}
+	if err := s.updateConfig(ctx, "set allowance schedule", config, repository.ConfigAllowanceSchedule); err != nil {
+		return nil, err
+	}
+	return &model.AllowanceSchedule{Default: s.monthlyAllowance, Schedule: schedule}, nil
+}
+
+// allowanceFor returns the allowance month is granted when it is created:
+// the amount of the latest schedule step starting on or before month, or
+// the deployment default when no step does.
+func (s *ExpenseService) allowanceFor(ctx context.Context, month string) (model.Money, error) {
+	config, err := s.repo.GetConfig(ctx)
+	if err != nil {
+		return 0, err
+	}
+	allowance := s.monthlyAllowance
+	if config == nil {
+		return allowance, nil
+	}
+	for _, step := range config.AllowanceSchedule {
+		if step.From > month {
+			break
+		}
+		allowance = step.Amount
+	}
+	return allowance, nil
+}
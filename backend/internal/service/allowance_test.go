@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/testutil"
+)
+
+// =====================================================================
+// Allowance schedule — each month is granted the step in force for it,
+// and changing the schedule leaves existing months alone.
+// =====================================================================
+
+func TestAllowanceSchedule_PicksTheStepInForce(t *testing.T) {
+	svc, repo := newExpenseService(t, false, true, 100)
+	repo.Config = &model.Config{PinHash: "x"}
+	ctx := context.Background()
+
+	if _, err := svc.SetAllowanceSchedule(ctx, &model.AllowanceSchedule{Schedule: []model.AllowanceRate{
+		{From: "2026-03", Amount: model.Dollars(150)},
+		{From: "2026-02", Amount: model.Dollars(120)},
+	}}); err != nil {
+		t.Fatalf("SetAllowanceSchedule: %v", err)
+	}
+	for month, want := range map[string]model.Money{
+		"2026-01": model.Dollars(100), // before the first step: the default
+		"2026-02": model.Dollars(120),
+		"2026-05": model.Dollars(150),
+	} {
+		resp, err := svc.CreateMonth(ctx, month)
+		if err != nil {
+			t.Fatalf("CreateMonth(%s): %v", month, err)
+		}
+		if resp.Summary.AllowanceAdded != want {
+			t.Errorf("%s allowance = %v, want %v", month, resp.Summary.AllowanceAdded, want)
+		}
+	}
+
+	// A raise from February on does not re-grant February.
+	if _, err := svc.SetAllowanceSchedule(ctx, &model.AllowanceSchedule{Schedule: []model.AllowanceRate{
+		{From: "2026-02", Amount: model.Dollars(200)},
+	}}); err != nil {
+		t.Fatalf("SetAllowanceSchedule: %v", err)
+	}
+	if got := repo.Months["2026-02"].AllowanceAdded; got != model.Dollars(120) {
+		t.Errorf("February after the raise = %v, want 120 kept", got)
+	}
+	if repo.SaveConfigCalls != 0 || repo.Config.PinHash != "x" {
+		t.Errorf("SaveConfig calls %d, PIN hash %q; want only the schedule written", repo.SaveConfigCalls, repo.Config.PinHash)
+	}
+}
+
+func TestAllowanceSchedule_TopsUpAnAutoCreatedMonth(t *testing.T) {
+	svc, repo := newExpenseService(t, true, true, 100)
+	repo.Config = &model.Config{PinHash: "x", AllowanceSchedule: []model.AllowanceRate{{From: "2026-01", Amount: model.Dollars(40)}}}
+	testutil.SeedMonth(repo, "2026-02", 0, 0, 0, 0)
+	ctx := context.Background()
+
+	resp, err := svc.CreateMonth(ctx, "2026-02")
+	if err != nil {
+		t.Fatalf("CreateMonth: %v", err)
+	}
+	if resp.Summary.AllowanceAdded != model.Dollars(40) {
+		t.Errorf("allowance = %v, want the scheduled 40", resp.Summary.AllowanceAdded)
+	}
+}
+
+func TestAllowanceSchedule_Validation(t *testing.T) {
+	svc, repo := newExpenseService(t, false, true, 100)
+	repo.Config = &model.Config{PinHash: "x"}
+	ctx := context.Background()
+
+	cases := []struct {
+		name     string
+		schedule []model.AllowanceRate
+		want     error
+	}{
+		{"bad month", []model.AllowanceRate{{From: "2026-1", Amount: 1}}, ErrInvalidAllowanceSchedule},
+		{"duplicate month", []model.AllowanceRate{{From: "2026-01", Amount: 1}, {From: "2026-01", Amount: 2}}, ErrInvalidAllowanceSchedule},
+		{"negative", []model.AllowanceRate{{From: "2026-01", Amount: -1}}, ErrInvalidAllowance},
+		{"too large", []model.AllowanceRate{{From: "2026-01", Amount: maxAmount + 1}}, ErrInvalidAllowance},
+	}
+	for _, tc := range cases {
+		if _, err := svc.SetAllowanceSchedule(ctx, &model.AllowanceSchedule{Schedule: tc.schedule}); !errors.Is(err, tc.want) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+	// A zero step pauses the allowance and is allowed.
+	if _, err := svc.SetAllowanceSchedule(ctx, &model.AllowanceSchedule{Schedule: []model.AllowanceRate{{From: "2026-01", Amount: 0}}}); err != nil {
+		t.Errorf("zero step: %v", err)
+	}
+}
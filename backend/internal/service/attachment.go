@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vppillai/passbook/backend/internal/blobstore"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+)
+
+var (
+	// ErrAttachmentsDisabled is returned when no blob store is configured.
+	// Handler maps to 503.
+	ErrAttachmentsDisabled = errors.New("attachments are not configured")
+	// ErrAttachmentNotFound is returned when an attachment id is not on the
+	// expense. Handler maps to 404.
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	// ErrAttachmentTooLarge is returned for an empty upload or one over
+	// MaxAttachmentBytes. Handler maps to 413.
+	ErrAttachmentTooLarge = errors.New("attachment is empty or too large")
+	// ErrUnsupportedAttachment is returned when the uploaded bytes are not
+	// one of the accepted file types. Handler maps to 415.
+	ErrUnsupportedAttachment = errors.New("unsupported attachment type")
+	// ErrTooManyAttachments is returned when an expense already holds
+	// maxAttachments files. Handler maps to 409.
+	ErrTooManyAttachments = errors.New("too many attachments")
+)
+
+const (
+	// MaxAttachmentBytes caps one upload. The whole request, base64-encoded
+	// by API Gateway, has to fit Lambda's 6 MB synchronous payload limit.
+	MaxAttachmentBytes = 4 * 1024 * 1024
+	// maxAttachments bounds the files on one expense, which keeps the row
+	// far from DynamoDB's item size limit.
+	maxAttachments = 5
+	// attachmentKeyPrefix is the blob-store namespace for attachments.
+	attachmentKeyPrefix = "attachments/"
+)
+
+// attachmentTypes are the content types accepted for upload, as sniffed by
+// http.DetectContentType: photos of receipts, or a PDF of one.
+var attachmentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+}
+
+// SetBlobStore enables attachments, stored in store. Without one, every
+// attachment call returns ErrAttachmentsDisabled.
+func (s *ExpenseService) SetBlobStore(store blobstore.BlobStore) {
+	s.blobs = store
+}
+
+// attachmentKey is the blob key for an attachment. It depends only on the
+// attachment id, so the blob does not move when its expense is re-dated.
+func attachmentKey(id string) string {
+	return attachmentKeyPrefix + id
+}
+
+// AddAttachment stores data as a new attachment on an expense. The type is
+// sniffed from the bytes rather than taken from the request, so what is
+// served back later is what was actually uploaded. The blob is written
+// first and the metadata second: a failure in between leaves an unreferenced
+// blob, never metadata pointing at nothing.
+func (s *ExpenseService) AddAttachment(ctx context.Context, month, expenseID string, data []byte) (*model.Attachment, error) {
+	if s.blobs == nil {
+		return nil, ErrAttachmentsDisabled
+	}
+	if len(data) == 0 || len(data) > MaxAttachmentBytes {
+		return nil, ErrAttachmentTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	if !attachmentTypes[contentType] {
+		return nil, ErrUnsupportedAttachment
+	}
+	expense, err := s.repo.GetExpense(ctx, month, expenseID)
+	if err != nil {
+		return nil, err
+	}
+	if expense == nil {
+		return nil, ErrExpenseNotFound
+	}
+	if len(expense.Attachments) >= maxAttachments {
+		return nil, ErrTooManyAttachments
+	}
+
+	attachment := &model.Attachment{
+		ID:          uuid.New().String(),
+		ContentType: contentType,
+		Size:        len(data),
+		CreatedAt:   time.Now(),
+	}
+	key := attachmentKey(attachment.ID)
+	if err := s.blobs.Put(ctx, key, contentType, data); err != nil {
+		return nil, err
+	}
+	if err := s.repo.AddExpenseAttachment(ctx, month, expenseID, len(expense.Attachments), attachment); err != nil {
+		s.deleteBlob(ctx, key)
+		if errors.Is(err, repository.ErrExpenseStateMismatch) {
+			return nil, ErrExpenseModified
+		}
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// ListAttachments returns the metadata of an expense's attachments.
+func (s *ExpenseService) ListAttachments(ctx context.Context, month, expenseID string) (*model.AttachmentListResponse, error) {
+	if s.blobs == nil {
+		return nil, ErrAttachmentsDisabled
+	}
+	expense, err := s.repo.GetExpense(ctx, month, expenseID)
+	if err != nil {
+		return nil, err
+	}
+	if expense == nil {
+		return nil, ErrExpenseNotFound
+	}
+	attachments := expense.Attachments
+	if attachments == nil {
+		attachments = []model.Attachment{}
+	}
+	return &model.AttachmentListResponse{Attachments: attachments}, nil
+}
+
+// GetAttachment returns one attachment's metadata and bytes.
+func (s *ExpenseService) GetAttachment(ctx context.Context, month, expenseID, attachmentID string) (*model.Attachment, []byte, error) {
+	if s.blobs == nil {
+		return nil, nil, ErrAttachmentsDisabled
+	}
+	_, attachment, err := s.findAttachment(ctx, month, expenseID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := s.blobs.Get(ctx, attachmentKey(attachment.ID))
+	if errors.Is(err, blobstore.ErrNotFound) {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, data, nil
+}
+
+// DeleteAttachment removes an attachment: the metadata first, then the
+// blob, for the same reason AddAttachment writes them the other way round.
+func (s *ExpenseService) DeleteAttachment(ctx context.Context, month, expenseID, attachmentID string) error {
+	if s.blobs == nil {
+		return ErrAttachmentsDisabled
+	}
+	index, attachment, err := s.findAttachment(ctx, month, expenseID, attachmentID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.RemoveExpenseAttachment(ctx, month, expenseID, index, attachment.ID); err != nil {
+		if errors.Is(err, repository.ErrExpenseStateMismatch) {
+			return ErrExpenseModified
+		}
+		return err
+	}
+	s.deleteBlob(ctx, attachmentKey(attachment.ID))
+	return nil
+}
+
+// findAttachment reads the expense and locates attachmentID on it.
+func (s *ExpenseService) findAttachment(ctx context.Context, month, expenseID, attachmentID string) (int, *model.Attachment, error) {
+	expense, err := s.repo.GetExpense(ctx, month, expenseID)
+	if err != nil {
+		return 0, nil, err
+	}
+	if expense == nil {
+		return 0, nil, ErrExpenseNotFound
+	}
+	for i := range expense.Attachments {
+		if expense.Attachments[i].ID == attachmentID {
+			return i, &expense.Attachments[i], nil
+		}
+	}
+	return 0, nil, ErrAttachmentNotFound
+}
+
+// deleteAttachmentBlobs removes the blobs of an expense that has itself been
+// deleted.
+func (s *ExpenseService) deleteAttachmentBlobs(ctx context.Context, attachments []model.Attachment) {
+	if s.blobs == nil {
+		return
+	}
+	for _, a := range attachments {
+		s.deleteBlob(ctx, attachmentKey(a.ID))
+	}
+}
+
+// deleteBlob is a best-effort blob removal: by the time it runs nothing
+// references the blob any more, so a failure only leaves an orphan behind,
+// which is logged.
+func (s *ExpenseService) deleteBlob(ctx context.Context, key string) {
+	if err := s.blobs.Delete(ctx, key); err != nil {
+		log.Printf("warn: could not delete blob %s: %v", key, err)
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vppillai/passbook/backend/internal/blobstore"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/testutil"
+)
+
+// =====================================================================
+// Attachments — the bytes go to the blob store, the metadata onto the
+// expense row, and the two are written and removed in the order that never
+// leaves metadata pointing at a missing blob.
+// =====================================================================
+
+// pngBytes is enough of a PNG for http.DetectContentType.
+var pngBytes = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 64)...)
+
+func newAttachmentService(t *testing.T) (*ExpenseService, *testutil.FakeRepo, *blobstore.LocalStore, *model.Expense) {
+	t.Helper()
+	svc, repo := newExpenseService(t, true, true, 0)
+	store, err := blobstore.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	svc.SetBlobStore(store)
+	month, date := pastMonthDay(1, 10)
+	testutil.SeedMonth(repo, month, 0, 100, 0, 100)
+	return svc, repo, store, addCategorized(t, svc, 12, "", date)
+}
+
+func TestAttachment_Lifecycle(t *testing.T) {
+	svc, repo, store, e := newAttachmentService(t)
+	month, _ := pastMonthDay(1, 10)
+	ctx := context.Background()
+
+	a, err := svc.AddAttachment(ctx, month, e.SK, pngBytes)
+	if err != nil {
+		t.Fatalf("AddAttachment: %v", err)
+	}
+	if a.ContentType != "image/png" || a.Size != len(pngBytes) {
+		t.Errorf("attachment = %+v, want a sniffed image/png of %d bytes", a, len(pngBytes))
+	}
+	if got := repo.Expenses[testutil.ExpenseKey(month, e.SK)].Attachments; len(got) != 1 || got[0].ID != a.ID {
+		t.Fatalf("row attachments = %+v, want the new one", got)
+	}
+
+	list, err := svc.ListAttachments(ctx, month, e.SK)
+	if err != nil || len(list.Attachments) != 1 {
+		t.Fatalf("ListAttachments = %+v, %v", list, err)
+	}
+	meta, data, err := svc.GetAttachment(ctx, month, e.SK, a.ID)
+	if err != nil || !bytes.Equal(data, pngBytes) || meta.ID != a.ID {
+		t.Fatalf("GetAttachment = %+v, %d bytes, %v", meta, len(data), err)
+	}
+
+	if err := svc.DeleteAttachment(ctx, month, e.SK, a.ID); err != nil {
+		t.Fatalf("DeleteAttachment: %v", err)
+	}
+	if got := repo.Expenses[testutil.ExpenseKey(month, e.SK)].Attachments; len(got) != 0 {
+		t.Errorf("row attachments = %+v after delete, want none", got)
+	}
+	if _, err := store.Get(ctx, attachmentKey(a.ID)); !errors.Is(err, blobstore.ErrNotFound) {
+		t.Errorf("blob still stored after delete (err %v)", err)
+	}
+	if err := svc.DeleteAttachment(ctx, month, e.SK, a.ID); !errors.Is(err, ErrAttachmentNotFound) {
+		t.Errorf("second delete err = %v, want ErrAttachmentNotFound", err)
+	}
+}
+
+func TestAddAttachment_Refusals(t *testing.T) {
+	svc, _, _, e := newAttachmentService(t)
+	month, _ := pastMonthDay(1, 10)
+	ctx := context.Background()
+
+	cases := []struct {
+		name      string
+		expenseID string
+		data      []byte
+		want      error
+	}{
+		{"empty", e.SK, nil, ErrAttachmentTooLarge},
+		{"too large", e.SK, bytes.Repeat([]byte{0xff}, MaxAttachmentBytes+1), ErrAttachmentTooLarge},
+		{"html", e.SK, []byte("<html><script>alert(1)</script></html>"), ErrUnsupportedAttachment},
+		{"missing expense", "EXP#1#nothere", pngBytes, ErrExpenseNotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := svc.AddAttachment(ctx, month, tc.expenseID, tc.data); !errors.Is(err, tc.want) {
+				t.Errorf("err = %v, want %v", err, tc.want)
+			}
+		})
+	}
+
+	for i := 0; i < maxAttachments; i++ {
+		if _, err := svc.AddAttachment(ctx, month, e.SK, pngBytes); err != nil {
+			t.Fatalf("AddAttachment %d: %v", i+1, err)
+		}
+	}
+	if _, err := svc.AddAttachment(ctx, month, e.SK, pngBytes); !errors.Is(err, ErrTooManyAttachments) {
+		t.Errorf("over the limit err = %v, want ErrTooManyAttachments", err)
+	}
+}
+
+func TestAttachments_DisabledWithoutBlobStore(t *testing.T) {
+	svc, _ := newExpenseService(t, true, true, 0)
+	if _, err := svc.AddAttachment(context.Background(), "2025-01", "EXP#1#abc", pngBytes); !errors.Is(err, ErrAttachmentsDisabled) {
+		t.Errorf("err = %v, want ErrAttachmentsDisabled", err)
+	}
+}
+
+// Re-dating an expense into another month moves its attachments with it;
+// deleting the expense keeps their blobs for a restore until the trash
+// entry is purged.
+func TestAttachments_FollowExpense(t *testing.T) {
+	svc, repo, store, e := newAttachmentService(t)
+	month, _ := pastMonthDay(1, 10)
+	ctx := context.Background()
+	a, err := svc.AddAttachment(ctx, month, e.SK, pngBytes)
+	if err != nil {
+		t.Fatalf("AddAttachment: %v", err)
+	}
+
+	target, date := pastMonthDay(2, 5)
+	testutil.SeedMonth(repo, target, 0, 100, 0, 100)
+	resp, err := svc.UpdateExpense(ctx, month, e.SK, &model.UpdateExpenseRequest{Date: date})
+	if err != nil {
+		t.Fatalf("UpdateExpense: %v", err)
+	}
+	moved := repo.Expenses[testutil.ExpenseKey(target, resp.Expense.ID)]
+	if moved == nil || len(moved.Attachments) != 1 || moved.Attachments[0].ID != a.ID {
+		t.Fatalf("moved expense = %+v, want it to keep its attachment", moved)
+	}
+
+	if err := svc.DeleteExpense(ctx, target, resp.Expense.ID); err != nil {
+		t.Fatalf("DeleteExpense: %v", err)
+	}
+	if _, err := store.Get(ctx, attachmentKey(a.ID)); err != nil {
+		t.Errorf("blob gone while its expense is in the trash (err %v)", err)
+	}
+	if _, err := svc.PurgeTrash(ctx, time.Now().AddDate(0, 0, defaultTrashRetentionDays+1)); err != nil {
+		t.Fatalf("PurgeTrash: %v", err)
+	}
+	if _, err := store.Get(ctx, attachmentKey(a.ID)); !errors.Is(err, blobstore.ErrNotFound) {
+		t.Errorf("blob outlived its purged expense (err %v)", err)
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+)
+
+// ListAudit returns a page of the audit journal, newest first. The journal
+// is written by the repository inside each mutation's own transaction, so
+// there is nothing for the service to add; it only owns the cursor.
+func (s *ExpenseService) ListAudit(ctx context.Context, limit int32, cursorStr string) (*model.AuditResponse, error) {
+	var cursor map[string]types.AttributeValue
+	if cursorStr != "" {
+		var err error
+		cursor, err = decodeCursor(cursorStr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+		}
+		if err := validateAuditCursor(cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	entries, lastKey, err := s.repo.ListAudit(ctx, limit, cursor)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []model.AuditEntry{}
+	}
+
+	nextCursor := ""
+	if lastKey != nil {
+		nextCursor, err = encodeCursor(lastKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &model.AuditResponse{Entries: entries, NextCursor: nextCursor}, nil
+}
+
+// validateAuditCursor checks that a decoded cursor is a resume key for the
+// journal Query: exactly {PK, SK}, PK="AUDIT" and an AUDIT# SK. Same purpose
+// as validateExpenseCursor.
+func validateAuditCursor(cursor map[string]types.AttributeValue) error {
+	if len(cursor) != 2 {
+		return ErrInvalidCursor
+	}
+	pkAV, pkOK := cursor["PK"].(*types.AttributeValueMemberS)
+	skAV, skOK := cursor["SK"].(*types.AttributeValueMemberS)
+	if !pkOK || !skOK || pkAV.Value != repository.PKAudit || !strings.HasPrefix(skAV.Value, repository.AuditPrefix) {
+		return ErrInvalidCursor
+	}
+	return nil
+}
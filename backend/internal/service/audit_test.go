@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+	"github.com/vppillai/passbook/backend/internal/testutil"
+)
+
+// =====================================================================
+// Audit — every ledger mutation leaves one journal entry, written with the
+// change itself and attributed to the request's actor.
+// =====================================================================
+
+func TestAudit_JournalsLedgerChanges(t *testing.T) {
+	svc, repo := newExpenseService(t, false, true, 100)
+	actor := repository.Actor{SessionID: "5e55", SourceIP: "192.0.2.7"}
+	ctx := repository.WithActor(context.Background(), actor)
+	month := time.Now().UTC().Format("2006-01")
+
+	if _, err := svc.CreateMonth(ctx, month); err != nil {
+		t.Fatalf("CreateMonth: %v", err)
+	}
+	if _, err := svc.AddFunds(ctx, month, model.Dollars(20), ""); err != nil {
+		t.Fatalf("AddFunds: %v", err)
+	}
+	added, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(30), Description: "Lunch", Category: "food"})
+	if err != nil {
+		t.Fatalf("AddExpense: %v", err)
+	}
+	id := added.Expense.SK
+	amount := model.Dollars(45)
+	if _, err := svc.UpdateExpense(ctx, month, id, &model.UpdateExpenseRequest{Amount: &amount}); err != nil {
+		t.Fatalf("UpdateExpense: %v", err)
+	}
+	if err := svc.DeleteExpense(ctx, month, id); err != nil {
+		t.Fatalf("DeleteExpense: %v", err)
+	}
+
+	want := []string{
+		model.AuditMonthCreate, model.AuditFundsAdd,
+		model.AuditExpenseAdd, model.AuditExpenseUpdate, model.AuditExpenseDelete,
+	}
+	if got := repo.AuditActions(); !slices.Equal(got, want) {
+		t.Fatalf("journal = %v, want %v", got, want)
+	}
+	for _, e := range repo.Audit {
+		if e.SessionID != actor.SessionID || e.SourceIP != actor.SourceIP || e.Month != month {
+			t.Errorf("%s entry = %+v, want it attributed to %+v in %s", e.Action, e, actor, month)
+		}
+	}
+	if after := repo.Audit[0].After; after == nil || *after.AllowanceAdded != model.Dollars(100) {
+		t.Errorf("month.create after = %+v, want the 100 allowance", after)
+	}
+	if after := repo.Audit[1].After; after == nil || *after.Amount != model.Dollars(20) {
+		t.Errorf("funds.add after = %+v, want the 20 top-up", after)
+	}
+	update := repo.Audit[3]
+	if update.Target != id || *update.Before.Amount != model.Dollars(30) || *update.After.Amount != model.Dollars(45) ||
+		update.After.Description != "Lunch" || update.After.Category != "food" {
+		t.Errorf("expense.update = %+v / %+v, want 30 -> 45 on %s", update.Before, update.After, id)
+	}
+	if del := repo.Audit[4]; del.Before == nil || *del.Before.Amount != model.Dollars(45) || del.After != nil {
+		t.Errorf("expense.delete = %+v / %+v, want before = the 45 expense, no after", del.Before, del.After)
+	}
+}
+
+func TestAudit_RefusedChangeIsNotJournaled(t *testing.T) {
+	svc, repo := newExpenseService(t, false, true, 0)
+	month, date := pastMonthDay(1, 10)
+	testutil.SeedMonth(repo, month, 0, 10, 0, 10)
+	repo.Balance.TotalBalance = model.Dollars(10)
+
+	_, err := svc.AddExpense(context.Background(), &model.AddExpenseRequest{Amount: model.Dollars(25), Description: "Too much", Date: date})
+	if !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("AddExpense err = %v, want ErrInsufficientFunds", err)
+	}
+	if len(repo.Audit) != 0 {
+		t.Errorf("journal = %v, want nothing for a refused add", repo.AuditActions())
+	}
+}
+
+func TestAudit_ListPagesNewestFirst(t *testing.T) {
+	svc, repo := newExpenseService(t, true, true, 0)
+	ctx := context.Background()
+	month, date := pastMonthDay(1, 10)
+	testutil.SeedMonth(repo, month, 0, 100, 0, 100)
+	repo.Balance.TotalBalance = model.Dollars(100)
+	for _, amount := range []float64{1, 2, 3} {
+		addCategorized(t, svc, amount, "", date)
+	}
+
+	first, err := svc.ListAudit(ctx, 2, "")
+	if err != nil {
+		t.Fatalf("ListAudit: %v", err)
+	}
+	if len(first.Entries) != 2 || first.NextCursor == "" || *first.Entries[0].After.Amount != model.Dollars(3) {
+		t.Fatalf("first page = %+v, want the two newest entries and a cursor", first)
+	}
+	second, err := svc.ListAudit(ctx, 2, first.NextCursor)
+	if err != nil {
+		t.Fatalf("ListAudit page 2: %v", err)
+	}
+	if len(second.Entries) != 1 || second.NextCursor != "" || *second.Entries[0].After.Amount != model.Dollars(1) {
+		t.Errorf("second page = %+v, want the oldest entry and no cursor", second)
+	}
+
+	bogus, _ := encodeCursor(map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: repository.MonthPrefix + month},
+		"SK": &types.AttributeValueMemberS{Value: repository.ExpensePrefix + "1"},
+	})
+	if _, err := svc.ListAudit(ctx, 2, bogus); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("foreign cursor err = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestAudit_ChangePINIsJournaled(t *testing.T) {
+	svc, repo := newAuthService(t)
+	seedPIN(t, repo, "1234")
+	ctx := repository.WithActor(context.Background(), repository.Actor{SessionID: "abcd"})
+
+	if err := svc.ChangePIN(ctx, "1234", "5678", "192.0.2.1"); err != nil {
+		t.Fatalf("ChangePIN: %v", err)
+	}
+	if got := repo.AuditActions(); !slices.Equal(got, []string{model.AuditPINChange}) || repo.Audit[0].SessionID != "abcd" {
+		t.Errorf("journal = %+v, want one attributed pin.change", repo.Audit)
+	}
+}
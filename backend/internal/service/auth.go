@@ -95,6 +95,10 @@ var (
 	ErrInvalidSession = errors.New("invalid session")
 	ErrPINTooShort    = errors.New("PIN must be 4-6 digits")
 	ErrPINNotNumeric  = errors.New("PIN must contain only digits")
+	// ErrPINsMustDiffer is returned when the admin and member PINs would
+	// be the same, which would make every unlock a member one or an admin
+	// one. Handler maps to 400.
+	ErrPINsMustDiffer = errors.New("the admin and member PINs must differ")
 )
 
 type AuthService struct {
@@ -178,6 +182,17 @@ func (s *AuthService) VerifyPIN(ctx context.Context, pin string, sourceIP string
 	}
 
 	if !match {
+		// Not the admin PIN; the member PIN, if one is set, is the other
+		// way in. Both draw on the one attempt budget above.
+		if config.MemberPinHash != "" {
+			memberMatch, err := verifyPINHash(pin, config.MemberPinHash)
+			if err != nil {
+				return nil, err
+			}
+			if memberMatch {
+				return mintSession(ctx, s.repo, sourceIP, model.RoleMember)
+			}
+		}
 		return s.failedAttempt(ctx, sourceIP)
 	}
 
@@ -186,7 +201,7 @@ func (s *AuthService) VerifyPIN(ctx context.Context, pin string, sourceIP string
 	// stored hash remains perfectly valid and the next unlock will try again.
 	s.upgradePINHash(ctx, pin, config)
 
-	return mintSession(ctx, s.repo, sourceIP)
+	return mintSession(ctx, s.repo, sourceIP, model.RoleAdmin)
 }
 
 // upgradePINHash re-derives and stores the PIN hash when the stored one was
@@ -229,19 +244,23 @@ func (s *AuthService) upgradePINHash(ctx context.Context, pin string, config *mo
 // The session write is the opposite case: it IS the login, so a failure there
 // must be surfaced rather than reporting success with a token that was never
 // stored.
-func mintSession(ctx context.Context, repo repository.RepositoryInterface, sourceIP string) (*model.VerifyPinResponse, error) {
+//
+// role is the session's: RoleAdmin for the admin PIN and biometric unlock,
+// RoleMember for the member PIN.
+func mintSession(ctx context.Context, repo repository.RepositoryInterface, sourceIP, role string) (*model.VerifyPinResponse, error) {
 	if err := repo.ClearRateLimit(ctx, sourceIP); err != nil {
 		log.Printf("warn: ClearRateLimit failed for ip=%s: %v", sourceIP, err)
 	}
 
 	token := uuid.New().String()
-	if err := repo.CreateSession(ctx, token, sessionTTLHours); err != nil {
+	if err := repo.CreateSession(ctx, token, role, sessionTTLHours); err != nil {
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
 
 	return &model.VerifyPinResponse{
 		Success: true,
 		Token:   token,
+		Role:    role,
 	}, nil
 }
 
@@ -395,25 +414,78 @@ func (s *AuthService) ChangePIN(ctx context.Context, currentPIN, newPIN, sourceI
 		return err
 	}
 
-	blocked, err := s.pinGuessBlocked(ctx, sourceIP, "change")
+	config, err := s.checkAdminPIN(ctx, currentPIN, sourceIP, "change")
 	if err != nil {
 		return err
 	}
+
+	// Checked only once the current PIN is proven, so it tells nobody but
+	// the admin anything about the member PIN.
+	if config.MemberPinHash != "" {
+		same, err := verifyPINHash(newPIN, config.MemberPinHash)
+		if err != nil {
+			return err
+		}
+		if same {
+			return ErrPINsMustDiffer
+		}
+	}
+
+	hash, err := hashPIN(newPIN)
+	if err != nil {
+		return fmt.Errorf("failed to hash PIN: %w", err)
+	}
+
+	// Step 1: revoke every session. Hard failure aborts the change.
+	if err := s.repo.DeleteAllSessions(ctx); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	// Step 1b: revoke every enrolled biometric credential, for the same
+	// reason and with the same hard-failure handling. Biometric unlock is an
+	// independent path to a session that never involves the PIN, so an
+	// attacker who reached an authenticated session once could enroll their
+	// own authenticator and keep minting sessions straight through a PIN
+	// rotation. Rotating the PIN has to close every door, not just the one
+	// the PIN opens. The user re-enrols from the post-login prompt.
+	if err := s.repo.DeleteAllWebAuthnCredentials(ctx); err != nil {
+		return fmt.Errorf("failed to revoke biometric credentials: %w", err)
+	}
+
+	// Step 2: update the PIN hash, journaled in the same transaction. If
+	// this fails after the revoke, the user has to re-authenticate with the
+	// old PIN — annoying but not a security problem.
+	config.PinHash = hash
+	if err := s.repo.AtomicChangePIN(ctx, config); err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkAdminPIN verifies the admin PIN a PIN-management call was given,
+// spending from the lock screen's attempt budget exactly as ChangePIN
+// describes, and returns the config it was checked against. action only
+// labels the log lines.
+func (s *AuthService) checkAdminPIN(ctx context.Context, pin, sourceIP, action string) (*model.Config, error) {
+	blocked, err := s.pinGuessBlocked(ctx, sourceIP, action)
+	if err != nil {
+		return nil, err
+	}
 	if blocked != nil {
-		return ErrRateLimited
+		return nil, ErrRateLimited
 	}
 
 	config, err := s.repo.GetConfig(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if config == nil || config.PinHash == "" {
-		return ErrPINNotSetup
+		return nil, ErrPINNotSetup
 	}
 
-	match, err := verifyPINHash(currentPIN, config.PinHash)
+	match, err := verifyPINHash(pin, config.PinHash)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if !match {
 		// Spend from the shared budget. failedAttempt's response shape is for
@@ -423,9 +495,9 @@ func (s *AuthService) ChangePIN(ctx context.Context, currentPIN, newPIN, sourceI
 		if _, ferr := s.failedAttempt(ctx, sourceIP); ferr != nil {
 			// Losing the record must not hand out a free guess, so it is logged
 			// and the guess still counts as wrong.
-			log.Printf("warn: recording failed change-PIN attempt for ip=%s: %v", sourceIP, ferr)
+			log.Printf("warn: recording failed %s-PIN attempt for ip=%s: %v", action, sourceIP, ferr)
 		}
-		return ErrInvalidPIN
+		return nil, ErrInvalidPIN
 	}
 
 	// The current PIN was right, so any earlier fumble from this address was a
@@ -434,54 +506,92 @@ func (s *AuthService) ChangePIN(ctx context.Context, currentPIN, newPIN, sourceI
 	// budget cheap enough to be worth it. Same treatment a successful verify
 	// gets; a warning is enough on failure, since the window self-expires.
 	if err := s.repo.ClearRateLimit(ctx, sourceIP); err != nil {
-		log.Printf("warn: ClearRateLimit failed after PIN change for ip=%s: %v", sourceIP, err)
+		log.Printf("warn: ClearRateLimit failed after PIN %s for ip=%s: %v", action, sourceIP, err)
 	}
 
-	hash, err := hashPIN(newPIN)
+	return config, nil
+}
+
+// SetMemberPIN sets or replaces the member PIN. adminPIN must be the admin
+// PIN: comparing the two is otherwise an unlimited oracle for the admin
+// PIN to anyone holding an admin session, which is why it is checked like
+// ChangePIN's current PIN. Every member session is revoked first, so a
+// replaced PIN's sessions do not outlive it; like ChangePIN, a failed
+// revoke aborts.
+func (s *AuthService) SetMemberPIN(ctx context.Context, adminPIN, pin, sourceIP string) error {
+	if err := validatePIN(pin); err != nil {
+		return err
+	}
+	config, err := s.checkAdminPIN(ctx, adminPIN, sourceIP, "member")
+	if err != nil {
+		return err
+	}
+	if pin == adminPIN {
+		return ErrPINsMustDiffer
+	}
+	hash, err := hashPIN(pin)
 	if err != nil {
 		return fmt.Errorf("failed to hash PIN: %w", err)
 	}
-
-	// Step 1: revoke every session. Hard failure aborts the change.
-	if err := s.repo.DeleteAllSessions(ctx); err != nil {
-		return fmt.Errorf("failed to revoke sessions: %w", err)
+	if err := s.repo.DeleteMemberSessions(ctx); err != nil {
+		return fmt.Errorf("failed to revoke member sessions: %w", err)
 	}
+	config.MemberPinHash = hash
+	return s.repo.AtomicSaveMemberPIN(ctx, config)
+}
 
-	// Step 1b: revoke every enrolled biometric credential, for the same
-	// reason and with the same hard-failure handling. Biometric unlock is an
-	// independent path to a session that never involves the PIN, so an
-	// attacker who reached an authenticated session once could enroll their
-	// own authenticator and keep minting sessions straight through a PIN
-	// rotation. Rotating the PIN has to close every door, not just the one
-	// the PIN opens. The user re-enrols from the post-login prompt.
-	if err := s.repo.DeleteAllWebAuthnCredentials(ctx); err != nil {
-		return fmt.Errorf("failed to revoke biometric credentials: %w", err)
+// RemoveMemberPIN removes the member PIN and revokes every member session.
+// Removing a PIN that is not set is a no-op.
+func (s *AuthService) RemoveMemberPIN(ctx context.Context) error {
+	config, err := s.repo.GetConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if config == nil || config.MemberPinHash == "" {
+		return nil
+	}
+	if err := s.repo.DeleteMemberSessions(ctx); err != nil {
+		return fmt.Errorf("failed to revoke member sessions: %w", err)
 	}
+	config.MemberPinHash = ""
+	return s.repo.AtomicSaveMemberPIN(ctx, config)
+}
 
-	// Step 2: update the PIN hash. If this fails after the revoke,
-	// the user has to re-authenticate with the old PIN — annoying but
-	// not a security problem.
-	config.PinHash = hash
-	if err := s.repo.SaveConfig(ctx, config); err != nil {
-		return err
+// HasMemberPIN reports whether a member PIN is set.
+func (s *AuthService) HasMemberPIN(ctx context.Context) (bool, error) {
+	config, err := s.repo.GetConfig(ctx)
+	if err != nil {
+		return false, err
 	}
-	return nil
+	return config != nil && config.MemberPinHash != "", nil
 }
 
 // ValidateSession validates a session token
 func (s *AuthService) ValidateSession(ctx context.Context, token string) (bool, error) {
-	if token == "" {
-		return false, nil
-	}
-
-	session, err := s.repo.GetSession(ctx, token)
+	session, err := s.Authenticate(ctx, token)
 	if err != nil {
 		return false, err
 	}
-
 	return session != nil, nil
 }
 
+// Authenticate returns the session of token, or nil when it is missing or
+// expired. A session minted before roles existed is reported as an admin
+// one.
+func (s *AuthService) Authenticate(ctx context.Context, token string) (*model.Session, error) {
+	if token == "" {
+		return nil, nil
+	}
+	session, err := s.repo.GetSession(ctx, token)
+	if err != nil || session == nil {
+		return nil, err
+	}
+	if session.Role == "" {
+		session.Role = model.RoleAdmin
+	}
+	return session, nil
+}
+
 // Logout invalidates a session
 func (s *AuthService) Logout(ctx context.Context, token string) error {
 	return s.repo.DeleteSession(ctx, token)
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
 	"github.com/vppillai/passbook/backend/internal/testutil"
 )
 
@@ -325,6 +326,94 @@ func TestChangePIN(t *testing.T) {
 	})
 }
 
+// TestMemberPIN covers the member PIN: it unlocks a member session, must
+// differ from the admin PIN, and replacing or removing it revokes only
+// the member sessions.
+func TestMemberPIN(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newAuthService(t)
+	seedPIN(t, repo, "1234")
+	const ip = "192.168.1.80"
+
+	if err := svc.SetMemberPIN(ctx, "0000", "5678", ip); err != ErrInvalidPIN {
+		t.Fatalf("wrong admin PIN: got %v, want ErrInvalidPIN", err)
+	}
+	if e := repo.RateLimits[ip]; e == nil || e.Attempts != 1 {
+		t.Errorf("rate limit = %+v, want the wrong admin PIN counted", e)
+	}
+	if err := svc.SetMemberPIN(ctx, "1234", "1234", ip); err != ErrPINsMustDiffer {
+		t.Fatalf("same as admin: got %v, want ErrPINsMustDiffer", err)
+	}
+	if err := svc.SetMemberPIN(ctx, "1234", "5678", ip); err != nil {
+		t.Fatalf("SetMemberPIN: %v", err)
+	}
+
+	member, err := svc.VerifyPIN(ctx, "5678", ip)
+	if err != nil || !member.Success || member.Role != model.RoleMember {
+		t.Fatalf("member verify = %+v, %v, want a member session", member, err)
+	}
+	admin, err := svc.VerifyPIN(ctx, "1234", ip)
+	if err != nil || !admin.Success || admin.Role != model.RoleAdmin {
+		t.Fatalf("admin verify = %+v, %v, want an admin session", admin, err)
+	}
+	if s, _ := svc.Authenticate(ctx, member.Token); s == nil || s.Role != model.RoleMember {
+		t.Errorf("member session = %+v, want RoleMember", s)
+	}
+	if err := svc.ChangePIN(ctx, "1234", "5678", ip); err != ErrPINsMustDiffer {
+		t.Errorf("admin PIN changed to the member one: got %v, want ErrPINsMustDiffer", err)
+	}
+
+	if err := svc.RemoveMemberPIN(ctx); err != nil {
+		t.Fatalf("RemoveMemberPIN: %v", err)
+	}
+	if repo.Sessions[member.Token] != nil || repo.Sessions[admin.Token] == nil {
+		t.Error("removing the member PIN must revoke the member sessions and only those")
+	}
+	if resp, _ := svc.VerifyPIN(ctx, "5678", ip); resp.Success {
+		t.Error("removed member PIN still unlocks")
+	}
+	if got := repo.AuditActions(); len(got) != 2 || got[0] != model.AuditMemberPINSet || got[1] != model.AuditMemberPINRemove {
+		t.Errorf("journal = %v, want the set and the removal", got)
+	}
+}
+
+// TestPINWrites_KeepSettingsSavedMeanwhile checks that changing the PIN and
+// setting or removing the member PIN write only their hash: settings saved
+// while the sessions were being revoked, after the row was read, survive.
+func TestPINWrites_KeepSettingsSavedMeanwhile(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newAuthService(t)
+	seedPIN(t, repo, "1234")
+	const ip = "192.168.1.81"
+
+	limit := model.Money(0)
+	repo.BeforeSessionRevoke = func() {
+		limit += model.Dollars(1)
+		if err := repo.UpdateConfig(ctx, &model.Config{DailyLimit: limit}, repository.ConfigDailyLimit); err != nil {
+			t.Fatalf("UpdateConfig: %v", err)
+		}
+	}
+	steps := []struct {
+		name string
+		run  func() error
+	}{
+		{"ChangePIN", func() error { return svc.ChangePIN(ctx, "1234", "5678", ip) }},
+		{"SetMemberPIN", func() error { return svc.SetMemberPIN(ctx, "5678", "1111", ip) }},
+		{"RemoveMemberPIN", func() error { return svc.RemoveMemberPIN(ctx) }},
+	}
+	for _, step := range steps {
+		if err := step.run(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if repo.Config.DailyLimit != limit {
+			t.Errorf("after %s daily limit = %v, want the %v saved during it", step.name, repo.Config.DailyLimit, limit)
+		}
+	}
+	if ok, _ := verifyPINHash("5678", repo.Config.PinHash); !ok || repo.Config.MemberPinHash != "" {
+		t.Errorf("config = %+v, want the new admin PIN and no member PIN", repo.Config)
+	}
+}
+
 // TestSessionLifecycle pins ValidateSession + Logout against the fake.
 func TestSessionLifecycle(t *testing.T) {
 	ctx := context.Background()
@@ -341,6 +430,10 @@ func TestSessionLifecycle(t *testing.T) {
 	if ok, err := svc.ValidateSession(ctx, "tok"); err != nil || !ok {
 		t.Errorf("known token: got (%v, %v), want (true, nil)", ok, err)
 	}
+	// A session from before roles existed is an admin one.
+	if s, err := svc.Authenticate(ctx, "tok"); err != nil || s == nil || s.Role != model.RoleAdmin {
+		t.Errorf("legacy session: got (%+v, %v), want RoleAdmin", s, err)
+	}
 
 	if err := svc.Logout(ctx, "tok"); err != nil {
 		t.Fatalf("Logout failed: %v", err)
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+)
+
+var (
+	// ErrInvalidBudget is returned when a category budget is not a positive
+	// amount within maxAmount. Handler maps to 400.
+	ErrInvalidBudget = errors.New("category budget must be between 0.01 and 99999.99")
+	// ErrInvalidBudgetCategory is returned when a budget names no category
+	// (empty, or the reserved "uncategorized"), names one that is too long,
+	// or names the same category twice once labels are normalized ("Food"
+	// and "food"). Handler maps to 400.
+	ErrInvalidBudgetCategory = errors.New("invalid budget category")
+	// ErrTooManyBudgets is returned when a PUT carries more than
+	// maxCategoryBudgets entries. Handler maps to 400.
+	ErrTooManyBudgets = errors.New("too many category budgets")
+	// ErrCategoryBudgetExceeded is returned under hard-stop with budget
+	// enforcement on, when an expense would take its category past the cap.
+	ErrCategoryBudgetExceeded = errors.New("category budget exceeded")
+)
+
+// maxCategoryBudgets bounds the CONFIG row's budget map. A family ledger has
+// a handful of categories; the cap only keeps a runaway client from growing
+// the item toward DynamoDB's 400KB limit.
+const maxCategoryBudgets = 50
+
+// CategoryBudgetError carries the category and what was left of its budget
+// when an expense was refused, so the handler can tell the user how much
+// they can still spend there — the category counterpart of
+// InsufficientFundsError. It wraps ErrCategoryBudgetExceeded.
+type CategoryBudgetError struct {
+	Category  string
+	Remaining model.Money
+}
+
+func (e *CategoryBudgetError) Error() string { return ErrCategoryBudgetExceeded.Error() }
+func (e *CategoryBudgetError) Unwrap() error { return ErrCategoryBudgetExceeded }
+
+// GetCategoryBudgets returns the configured per-category monthly budgets.
+// An instance with no budgets (or no CONFIG row yet) gets an empty set.
+func (s *ExpenseService) GetCategoryBudgets(ctx context.Context) (*model.CategoryBudgets, error) {
+	config, err := s.repo.GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := &model.CategoryBudgets{Budgets: map[string]model.Money{}}
+	if config == nil {
+		return out, nil
+	}
+	for cat, budget := range config.CategoryBudgets {
+		out.Budgets[cat] = budget
+	}
+	out.Enforce = config.EnforceCategoryBudgets
+	return out, nil
+}
+
+// SetCategoryBudgets replaces the whole budget set on the CONFIG row.
+// Category labels go through the same normalization as expense categories
+// so a budget for "Coffee " applies to expenses filed as "coffee".
+//
+// Only the budget attributes of the CONFIG row are written (UpdateConfig),
+// so a concurrent PIN change is never overwritten with the hash it
+// replaced.
+func (s *ExpenseService) SetCategoryBudgets(ctx context.Context, req *model.CategoryBudgets) (*model.CategoryBudgets, error) {
+	if len(req.Budgets) > maxCategoryBudgets {
+		return nil, ErrTooManyBudgets
+	}
+	budgets := make(map[string]model.Money, len(req.Budgets))
+	for label, amount := range req.Budgets {
+		cat, err := validateCategory(label)
+		if err != nil || cat == "" {
+			return nil, ErrInvalidBudgetCategory
+		}
+		if _, dup := budgets[cat]; dup {
+			return nil, ErrInvalidBudgetCategory
+		}
+		if amount <= 0 || amount > maxAmount {
+			return nil, ErrInvalidBudget
+		}
+		budgets[cat] = amount
+	}
+
+	config := &model.Config{CategoryBudgets: budgets, EnforceCategoryBudgets: req.Enforce}
+	if err := s.updateConfig(ctx, "set category budgets", config, repository.ConfigCategoryBudgets, repository.ConfigEnforceCategoryBudgets); err != nil {
+		return nil, err
+	}
+	return &model.CategoryBudgets{Budgets: budgets, Enforce: req.Enforce}, nil
+}
+
+// updateConfig writes the named attributes of config onto the CONFIG row
+// (UpdateConfig), wrapping a missing row as ErrPINNotSetup under op. The
+// callers' routes sit behind auth, and a session cannot exist before the
+// PIN (and with it the CONFIG row) does.
+func (s *ExpenseService) updateConfig(ctx context.Context, op string, config *model.Config, fields ...string) error {
+	err := s.repo.UpdateConfig(ctx, config, fields...)
+	if errors.Is(err, repository.ErrConfigNotFound) {
+		return fmt.Errorf("%s: %w", op, ErrPINNotSetup)
+	}
+	return err
+}
+
+// categoryBudget looks up the budget for one category. ok is false when the
+// category is empty or has no budget, in which case nothing is reported or
+// enforced. enforce is only true on a hard-stop instance.
+func (s *ExpenseService) categoryBudget(ctx context.Context, category string) (budget model.Money, enforce, ok bool, err error) {
+	if category == "" {
+		return 0, false, false, nil
+	}
+	config, err := s.repo.GetConfig(ctx)
+	if err != nil || config == nil {
+		return 0, false, false, err
+	}
+	budget, ok = config.CategoryBudgets[category]
+	enforce = config.EnforceCategoryBudgets && !s.settingsFrom(config).AllowOverspending
+	return budget, enforce, ok, nil
+}
+
+// ensureCategoryBudgetAffordable refuses an expense that would take its
+// category past the monthly cap, when the instance is hard-stop and budget
+// enforcement is on. Like ensureCarryChainAffordable it is a pre-check on
+// the state just read, not a transaction condition: a budget is a spending
+// guideline the family set for itself, not a ledger invariant, so the
+// narrow race between two concurrent adds is acceptable.
+func (s *ExpenseService) ensureCategoryBudgetAffordable(summary *model.MonthSummary, category string, amount, budget model.Money, enforce bool) error {
+	if !enforce {
+		return nil
+	}
+	var spent model.Money
+	if summary != nil {
+		spent = summary.CategoryTotals[category]
+	}
+	remaining := budget - spent
+	if amount > remaining {
+		return &CategoryBudgetError{Category: category, Remaining: remaining}
+	}
+	return nil
+}
+
+// ensureCategoryBudgetForEdit is ensureCategoryBudgetAffordable for an
+// edit or re-date that leaves old (read from oldMonth) as amount filed under
+// category in month. Where old was already counted in that month and
+// category, it is left out of the total, so raising an expense is measured
+// by its new amount rather than on top of the old one. An edit that adds
+// nothing to the category's total (same month and category, amount not
+// raised) is never refused, so a budget lowered below what is already
+// spent does not lock the expenses under it.
+func (s *ExpenseService) ensureCategoryBudgetForEdit(ctx context.Context, oldMonth string, old *model.Expense, month, category string, amount model.Money) error {
+	counted := oldMonth == month && old.Category == category
+	if counted && amount <= old.Amount {
+		return nil
+	}
+	budget, enforce, ok, err := s.categoryBudget(ctx, category)
+	if err != nil || !ok || !enforce {
+		return err
+	}
+	summary, err := s.repo.GetMonthSummary(ctx, month)
+	if err != nil {
+		return err
+	}
+	var spent model.Money
+	if summary != nil {
+		spent = summary.CategoryTotals[category]
+	}
+	if counted {
+		spent -= old.Amount
+	}
+	if remaining := budget - spent; amount > remaining {
+		return &CategoryBudgetError{Category: category, Remaining: remaining}
+	}
+	return nil
+}
+
+// categoryBudgetStatus builds the AddExpenseResponse budget report from the
+// post-transaction summary.
+func categoryBudgetStatus(summary *model.MonthSummary, category string, budget model.Money) *model.CategoryBudgetStatus {
+	var spent model.Money
+	if summary != nil {
+		spent = summary.CategoryTotals[category]
+	}
+	remaining := budget - spent
+	return &model.CategoryBudgetStatus{
+		Category:   category,
+		Budget:     budget,
+		Spent:      spent,
+		Remaining:  remaining,
+		OverBudget: remaining < 0,
+	}
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+)
+
+var (
+	// ErrChoreNotFound is returned when a chore id does not exist. Handler
+	// maps to 404.
+	ErrChoreNotFound = errors.New("chore not found")
+	// ErrChoreNameRequired is returned when a chore's name is empty after
+	// trimming. Handler maps to 400.
+	ErrChoreNameRequired = errors.New("chore name is required")
+	// ErrChoreCompletionNotFound is returned when an approval or rejection
+	// names a completion that is not awaiting approval, including one
+	// another request has just approved or rejected. Handler maps to 404.
+	ErrChoreCompletionNotFound = errors.New("chore completion not found")
+)
+
+// CreateChore validates and stores a new chore.
+func (s *ExpenseService) CreateChore(ctx context.Context, req *model.CreateChoreRequest) (*model.Chore, error) {
+	chore := &model.Chore{
+		ID:     uuid.New().String()[:8],
+		Name:   req.Name,
+		Reward: req.Reward,
+	}
+	if err := validateChore(chore); err != nil {
+		return nil, err
+	}
+	chore.CreatedAt = time.Now()
+	chore.UpdatedAt = chore.CreatedAt
+	if err := s.repo.CreateChore(ctx, chore); err != nil {
+		return nil, err
+	}
+	return chore, nil
+}
+
+// ListChores returns every chore by name.
+func (s *ExpenseService) ListChores(ctx context.Context) (*model.ChoresResponse, error) {
+	chores, err := s.repo.ListChores(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if chores == nil {
+		chores = []model.Chore{}
+	}
+	sort.SliceStable(chores, func(i, j int) bool {
+		return strings.ToLower(chores[i].Name) < strings.ToLower(chores[j].Name)
+	})
+	return &model.ChoresResponse{Chores: chores}, nil
+}
+
+// UpdateChore changes a chore's name or reward. Completions already
+// submitted keep the reward they were submitted with.
+func (s *ExpenseService) UpdateChore(ctx context.Context, id string, req *model.UpdateChoreRequest) (*model.Chore, error) {
+	if req.Name == nil && req.Reward == nil {
+		return nil, ErrNoChanges
+	}
+	chore, err := s.repo.GetChore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if chore == nil {
+		return nil, ErrChoreNotFound
+	}
+	if req.Name != nil {
+		chore.Name = *req.Name
+	}
+	if req.Reward != nil {
+		chore.Reward = *req.Reward
+	}
+	if err := validateChore(chore); err != nil {
+		return nil, err
+	}
+	chore.UpdatedAt = time.Now()
+	if err := s.repo.UpdateChore(ctx, chore); err != nil {
+		if errors.Is(err, repository.ErrChoreNotFound) {
+			return nil, ErrChoreNotFound
+		}
+		return nil, err
+	}
+	return chore, nil
+}
+
+// DeleteChore removes a chore. Completions already submitted for it can
+// still be approved or rejected.
+func (s *ExpenseService) DeleteChore(ctx context.Context, id string) error {
+	chore, err := s.repo.DeleteChore(ctx, id)
+	if err != nil {
+		return err
+	}
+	if chore == nil {
+		return ErrChoreNotFound
+	}
+	return nil
+}
+
+// CompleteChore files a report that chore id was done in the month of
+// now, awaiting approval. No money moves until it is approved.
+func (s *ExpenseService) CompleteChore(ctx context.Context, id string, now time.Time) (*model.ChoreCompletion, error) {
+	chore, err := s.repo.GetChore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if chore == nil {
+		return nil, ErrChoreNotFound
+	}
+	completion := &model.ChoreCompletion{
+		ID:          uuid.New().String()[:8],
+		ChoreID:     chore.ID,
+		Name:        chore.Name,
+		Reward:      chore.Reward,
+		Month:       monthOf(now),
+		SubmittedAt: now,
+	}
+	if err := s.repo.PutChoreCompletion(ctx, completion); err != nil {
+		return nil, err
+	}
+	return completion, nil
+}
+
+// ListChoreCompletions returns the completions awaiting approval, oldest
+// submission first.
+func (s *ExpenseService) ListChoreCompletions(ctx context.Context) (*model.ChoreCompletionsResponse, error) {
+	entries, err := s.repo.ListChoreCompletions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []model.ChoreCompletion{}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].SubmittedAt.Before(entries[j].SubmittedAt) })
+	return &model.ChoreCompletionsResponse{Completions: entries}, nil
+}
+
+// ApproveChoreCompletion credits a completion's reward to the month it was
+// done in, creating the month if missing, as an itemized funds credit of
+// kind FundKindEarning: it raises allowance_added and earnings_added and
+// ripples through the later months like any top-up. The completion is
+// deleted in the same transaction, so it is credited at most once.
+func (s *ExpenseService) ApproveChoreCompletion(ctx context.Context, id string) (*model.AddFundsResponse, error) {
+	completion, err := s.repo.GetChoreCompletion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if completion == nil {
+		return nil, ErrChoreCompletionNotFound
+	}
+	if _, err := s.ensureMonthExists(ctx, completion.Month); err != nil {
+		return nil, err
+	}
+	if err := s.repo.EnsureMonthListMirror(ctx, completion.Month); err != nil {
+		return nil, err
+	}
+
+	entry := &model.FundEntry{
+		SK:          fmt.Sprintf("%s%d#%s", repository.FundPrefix, completion.SubmittedAt.UnixNano(), completion.ID),
+		Amount:      completion.Reward,
+		Description: completion.Name,
+		Kind:        model.FundKindEarning,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if err := s.repo.AtomicApproveChoreCompletion(ctx, completion, entry); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrChoreCompletionNotFound):
+			return nil, ErrChoreCompletionNotFound
+		case errors.Is(err, repository.ErrExpenseStateMismatch):
+			// The month was deleted between its creation and the credit.
+			return nil, ErrMonthNotFound
+		}
+		return nil, err
+	}
+	if err := s.propagateToLaterMonths(ctx, completion.Month, entry.Amount); err != nil {
+		return nil, err
+	}
+
+	summary, balance, err := s.fetchSummaryAndBalance(ctx, completion.Month)
+	if err != nil {
+		return nil, err
+	}
+	return &model.AddFundsResponse{
+		Success:      true,
+		Summary:      summary,
+		TotalBalance: balance.TotalBalance,
+		Entry:        entry,
+	}, nil
+}
+
+// RejectChoreCompletion discards a completion. It moves no money.
+func (s *ExpenseService) RejectChoreCompletion(ctx context.Context, id string) error {
+	completion, err := s.repo.DeleteChoreCompletion(ctx, id)
+	if err != nil {
+		return err
+	}
+	if completion == nil {
+		return ErrChoreCompletionNotFound
+	}
+	return nil
+}
+
+// validateChore checks a chore's fields and normalizes its name in place.
+// The reward has the same bounds as a funds top-up.
+func validateChore(chore *model.Chore) error {
+	name, err := validateDescription(chore.Name)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		return ErrChoreNameRequired
+	}
+	chore.Name = name
+	if chore.Reward <= 0 || chore.Reward > maxAmount {
+		return ErrInvalidAmount
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/testutil"
+)
+
+// =====================================================================
+// Chores — a done chore waits for approval, which credits its reward to
+// the month as earnings: part of allowance_added, counted apart from it.
+// =====================================================================
+
+func TestChores_ApprovalCreditsEarnings(t *testing.T) {
+	svc, repo := newExpenseService(t, false, true, 100)
+	ctx := context.Background()
+	testutil.SeedMonth(repo, "2026-01", 0, 100, 0, 100)
+	testutil.SeedMonth(repo, "2026-02", 100, 100, 0, 200)
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(200)}
+
+	chore, err := svc.CreateChore(ctx, &model.CreateChoreRequest{Name: " Mow the lawn ", Reward: model.Dollars(5)})
+	if err != nil {
+		t.Fatalf("CreateChore: %v", err)
+	}
+	if chore.Name != "Mow the lawn" {
+		t.Errorf("name = %q, want it trimmed", chore.Name)
+	}
+	done := time.Date(2026, 1, 20, 12, 0, 0, 0, time.UTC)
+	completion, err := svc.CompleteChore(ctx, chore.ID, done)
+	if err != nil {
+		t.Fatalf("CompleteChore: %v", err)
+	}
+	if completion.Month != "2026-01" || completion.Reward != model.Dollars(5) {
+		t.Fatalf("completion = %+v, want January's 5", completion)
+	}
+	// Raising the reward later does not change what was submitted.
+	reward := model.Dollars(8)
+	if _, err := svc.UpdateChore(ctx, chore.ID, &model.UpdateChoreRequest{Reward: &reward}); err != nil {
+		t.Fatalf("UpdateChore: %v", err)
+	}
+	if repo.Balance.TotalBalance != model.Dollars(200) {
+		t.Fatalf("balance = %v, want nothing credited before approval", repo.Balance.TotalBalance)
+	}
+
+	resp, err := svc.ApproveChoreCompletion(ctx, completion.ID)
+	if err != nil {
+		t.Fatalf("ApproveChoreCompletion: %v", err)
+	}
+	if resp.Entry.Kind != model.FundKindEarning || resp.Entry.Amount != model.Dollars(5) || resp.Entry.Description != "Mow the lawn" {
+		t.Errorf("entry = %+v, want a 5 earning named after the chore", resp.Entry)
+	}
+	jan := repo.Months["2026-01"]
+	if jan.AllowanceAdded != model.Dollars(105) || jan.EarningsAdded != model.Dollars(5) || jan.EndingBalance != model.Dollars(105) {
+		t.Errorf("January = %+v, want 5 earned within 105 added", jan)
+	}
+	if got := repo.MonthList["2026-01"].EarningsAdded; got != model.Dollars(5) {
+		t.Errorf("January mirror earnings = %v, want 5", got)
+	}
+	if feb := repo.Months["2026-02"]; feb.StartingBalance != model.Dollars(105) || feb.EarningsAdded != 0 {
+		t.Errorf("February = %+v, want it to carry the earning, not count it", feb)
+	}
+	if repo.Balance.TotalBalance != model.Dollars(205) {
+		t.Errorf("balance = %v, want 205", repo.Balance.TotalBalance)
+	}
+	if _, err := svc.ApproveChoreCompletion(ctx, completion.ID); !errors.Is(err, ErrChoreCompletionNotFound) {
+		t.Errorf("second approval err = %v, want ErrChoreCompletionNotFound", err)
+	}
+
+	// Editing the earning through the funds routes keeps the total in step.
+	lower := model.Dollars(3)
+	if _, err := svc.UpdateFundEntry(ctx, "2026-01", resp.Entry.SK, &model.UpdateFundEntryRequest{Amount: &lower}); err != nil {
+		t.Fatalf("UpdateFundEntry: %v", err)
+	}
+	if got := repo.Months["2026-01"].EarningsAdded; got != model.Dollars(3) {
+		t.Errorf("earnings after edit = %v, want 3", got)
+	}
+}
+
+// Approving a chore done in a month nobody has opened yet creates that
+// month at $0; its earning must not pass for the allowance, which the
+// rollover still grants.
+func TestChores_EarningBeforeRolloverKeepsTheAllowance(t *testing.T) {
+	svc, repo := newExpenseService(t, false, true, 100)
+	ctx := context.Background()
+	testutil.SeedMonth(repo, "2026-01", 0, 100, 0, 100)
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(100)}
+
+	chore, err := svc.CreateChore(ctx, &model.CreateChoreRequest{Name: "Dishes", Reward: model.Dollars(5)})
+	if err != nil {
+		t.Fatalf("CreateChore: %v", err)
+	}
+	completion, err := svc.CompleteChore(ctx, chore.ID, time.Date(2026, 2, 3, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("CompleteChore: %v", err)
+	}
+	if _, err := svc.ApproveChoreCompletion(ctx, completion.ID); err != nil {
+		t.Fatalf("ApproveChoreCompletion: %v", err)
+	}
+
+	activated, err := svc.RolloverMonths(ctx, time.Date(2026, 2, 4, 0, 5, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("RolloverMonths: %v", err)
+	}
+	if len(activated) != 1 || activated[0] != "2026-02" {
+		t.Errorf("activated = %v, want February", activated)
+	}
+	feb := repo.Months["2026-02"]
+	if feb.AllowanceAdded != model.Dollars(105) || feb.EarningsAdded != model.Dollars(5) || feb.EndingBalance != model.Dollars(205) {
+		t.Errorf("February = %+v, want the 100 allowance beside the 5 earned", feb)
+	}
+	if repo.Balance.TotalBalance != model.Dollars(205) {
+		t.Errorf("balance = %v, want 205", repo.Balance.TotalBalance)
+	}
+	if _, err := svc.CreateMonth(ctx, "2026-02"); !errors.Is(err, ErrMonthExists) {
+		t.Errorf("second CreateMonth err = %v, want ErrMonthExists", err)
+	}
+}
+
+func TestChores_RejectAndValidation(t *testing.T) {
+	svc, repo := newExpenseService(t, false, true, 100)
+	ctx := context.Background()
+
+	if _, err := svc.CreateChore(ctx, &model.CreateChoreRequest{Name: " ", Reward: model.Dollars(1)}); !errors.Is(err, ErrChoreNameRequired) {
+		t.Errorf("blank name err = %v, want ErrChoreNameRequired", err)
+	}
+	if _, err := svc.CreateChore(ctx, &model.CreateChoreRequest{Name: "Beds", Reward: 0}); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("zero reward err = %v, want ErrInvalidAmount", err)
+	}
+	if _, err := svc.CompleteChore(ctx, "missing", time.Now()); !errors.Is(err, ErrChoreNotFound) {
+		t.Errorf("complete missing err = %v, want ErrChoreNotFound", err)
+	}
+
+	chore, err := svc.CreateChore(ctx, &model.CreateChoreRequest{Name: "Beds", Reward: model.Dollars(1)})
+	if err != nil {
+		t.Fatalf("CreateChore: %v", err)
+	}
+	completion, err := svc.CompleteChore(ctx, chore.ID, time.Now())
+	if err != nil {
+		t.Fatalf("CompleteChore: %v", err)
+	}
+	if err := svc.DeleteChore(ctx, chore.ID); err != nil {
+		t.Fatalf("DeleteChore: %v", err)
+	}
+	if err := svc.RejectChoreCompletion(ctx, completion.ID); err != nil {
+		t.Fatalf("RejectChoreCompletion: %v", err)
+	}
+	if err := svc.RejectChoreCompletion(ctx, completion.ID); !errors.Is(err, ErrChoreCompletionNotFound) {
+		t.Errorf("second reject err = %v, want ErrChoreCompletionNotFound", err)
+	}
+	if len(repo.Months) != 0 || len(repo.Audit) != 0 {
+		t.Errorf("months %d, journal %v, want a rejection to touch nothing", len(repo.Months), repo.AuditActions())
+	}
+}
@@ -14,7 +14,6 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"math"
 	"sort"
 	"strings"
 	"sync"
@@ -24,6 +23,7 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/google/uuid"
+	"github.com/vppillai/passbook/backend/internal/blobstore"
 	"github.com/vppillai/passbook/backend/internal/model"
 	"github.com/vppillai/passbook/backend/internal/repository"
 )
@@ -57,7 +57,8 @@ var (
 	ErrMonthHasExpenses = errors.New("month has expenses")
 	// ErrMonthModified is returned by DeleteMonth when the month changed
 	// between the pre-read and the conditional delete — specifically when its
-	// allowance_added moved, which would have made the balance debit stale.
+	// allowance_added moved, which would have made the balance debit stale —
+	// and by WithdrawFunds when its earnings_added moved under the cap.
 	// Distinct from ErrMonthHasExpenses so the client is told to refresh rather
 	// than being given a reason that is not true. Handler maps to 409.
 	ErrMonthModified = errors.New("month modified")
@@ -66,6 +67,20 @@ var (
 	// point (e.g. cursorMonth not found in the current month list).
 	// Handler maps to 400. Replaces the previous strings.Contains check.
 	ErrInvalidCursor = errors.New("invalid pagination cursor")
+	// ErrCategoryTooLong is returned when an expense category exceeds
+	// maxCategoryRunes after trimming. Handler maps to 400.
+	ErrCategoryTooLong = errors.New("category too long (max 30 characters)")
+	// ErrDuplicateExpense is returned when a new expense's SK is already
+	// taken. Random SKs never collide in practice; deterministic ones
+	// (recurring occurrences) collide exactly when the occurrence is
+	// already booked, which is what makes booking idempotent.
+	ErrDuplicateExpense = errors.New("expense already exists")
+	// ErrWithdrawalExceedsAllowance is returned when a withdrawal, or a
+	// lowered or deleted funds credit, takes back more than the month's
+	// allowance_added: only money that was granted can be taken back. A
+	// withdrawal is also held to what is left once the month's chore
+	// earnings are set aside. Handler maps to 400.
+	ErrWithdrawalExceedsAllowance = errors.New("withdrawal exceeds the month's allowance")
 )
 
 // InsufficientFundsError carries the amount that WAS available when an
@@ -74,31 +89,56 @@ var (
 // (U4). It wraps ErrInsufficientFunds so existing errors.Is checks still
 // match.
 type InsufficientFundsError struct {
-	Available float64
+	Available model.Money
 }
 
 func (e *InsufficientFundsError) Error() string { return ErrInsufficientFunds.Error() }
 func (e *InsufficientFundsError) Unwrap() error { return ErrInsufficientFunds }
 
 type ExpenseService struct {
-	repo              repository.RepositoryInterface
-	monthlyAllowance  float64
+	repo             repository.RepositoryInterface
+	monthlyAllowance model.Money
+	// allowOverspending and carryOverBalance are the deployment's modes,
+	// which the CONFIG row can override at runtime. Read them through
+	// Settings, hardStop or carriesOver, never directly.
 	allowOverspending bool
 	carryOverBalance  bool
+	// settingsMu guards the in-process copy of the runtime settings. See
+	// Settings.
+	settingsMu     sync.Mutex
+	settingsCache  model.Settings
+	settingsExpiry time.Time
+	// statsCache holds the stats of closed months, keyed by the month's
+	// account-scoped partition, guarded by statsMu. Per process, like
+	// monthListReady. See statsFor.
+	statsMu    sync.Mutex
+	statsCache map[string]*monthStats
 	// monthListReady records that the MONTHLIST index has been proven
 	// complete for this process, so carry propagation can discover later
 	// months with a sorted Query instead of a full-table Scan. See
 	// ensureMonthListComplete. Reset per process (a Lambda cold start
 	// re-verifies once), never persisted.
 	monthListReady atomic.Bool
+	// searchIndexed records that the search index has been back-filled.
+	// See ensureSearchIndex. Per process, like monthListReady.
+	searchIndexed atomic.Bool
+	// blobs holds expense attachments; nil disables them. See SetBlobStore.
+	blobs blobstore.BlobStore
+	// trashRetention is how long a deleted expense stays restorable. See
+	// SetTrashRetention.
+	trashRetention time.Duration
+	// interest is the monthly interest paid on carried balances; the zero
+	// policy pays none. See SetInterestPolicy.
+	interest InterestPolicy
 }
 
 func NewExpenseService(repo repository.RepositoryInterface, monthlyAllowance float64, allowOverspending bool, carryOverBalance bool) *ExpenseService {
 	return &ExpenseService{
 		repo:              repo,
-		monthlyAllowance:  monthlyAllowance,
+		monthlyAllowance:  model.Dollars(monthlyAllowance),
 		allowOverspending: allowOverspending,
 		carryOverBalance:  carryOverBalance,
+		trashRetention:    defaultTrashRetentionDays * 24 * time.Hour,
 	}
 }
 
@@ -107,14 +147,23 @@ const (
 	// edit, or a funds top-up. The frontend's number inputs already carry
 	// max="99999.99"; this is the server-side half of that rule, so a
 	// request that bypasses the form cannot credit or debit an arbitrary
-	// figure into the ledger.
-	maxAmount = 99999.99
+	// figure into the ledger. 99999.99 in cents.
+	maxAmount model.Money = 9999999
 
 	// maxDescriptionRunes is the description limit in CHARACTERS, matching
 	// both the user-facing error text and the HTML input's maxlength.
 	// Measuring bytes instead rejected any 100-character description in a
 	// non-Latin script that the form had already accepted.
 	maxDescriptionRunes = 100
+
+	// maxCategoryRunes bounds a category label. Labels become keys of the
+	// month summary's category_totals map, so they are kept short.
+	maxCategoryRunes = 30
+
+	// UncategorizedCategory is the label the month breakdown reports for
+	// spend with no category. It is never stored: a request naming it
+	// explicitly is the same as naming no category.
+	UncategorizedCategory = "uncategorized"
 )
 
 // validateDescription trims surrounding whitespace and enforces
@@ -129,14 +178,18 @@ func validateDescription(s string) (string, error) {
 	return s, nil
 }
 
-// roundCents rounds a dollar amount to 2 decimal places. JSON inputs and
-// month arithmetic arrive/happen in float64, and attributevalue marshals
-// float64 at full precision — without rounding, a carried balance like
-// -522.16 + 500 was written to DynamoDB as -22.159999999999968 (observed
-// in prod). Repository deltas already go through %.2f; this closes the
-// remaining absolute-value writes and the service-boundary inputs.
-func roundCents(v float64) float64 {
-	return math.Round(v*100) / 100
+// validateCategory normalizes a category label: surrounding whitespace is
+// trimmed and the label lower-cased, so "Food " and "food" share one total.
+// An empty label (or UncategorizedCategory itself) means no category.
+func validateCategory(s string) (string, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if utf8.RuneCountInString(s) > maxCategoryRunes {
+		return "", ErrCategoryTooLong
+	}
+	if s == UncategorizedCategory {
+		return "", nil
+	}
+	return s, nil
 }
 
 // GetCurrentMonth returns the current month key in YYYY-MM format. Uses
@@ -147,6 +200,14 @@ func GetCurrentMonth() string {
 	return fmt.Sprintf("%04d-%02d", now.Year(), now.Month())
 }
 
+// monthOf is the month key of t, in UTC like GetCurrentMonth. Callers that
+// take the current time as a parameter (scheduled runs, tests) use it instead
+// of GetCurrentMonth.
+func monthOf(t time.Time) string {
+	t = t.UTC()
+	return fmt.Sprintf("%04d-%02d", t.Year(), t.Month())
+}
+
 // ValidateMonth is the single source of truth for the "YYYY-MM" month-key
 // rule, used by both the HTTP handlers and the service layer (previously
 // the rule was implemented three ways: len()==7, inline time.Parse, and a
@@ -169,6 +230,13 @@ func GetPreviousMonth(month string) string {
 	return fmt.Sprintf("%04d-%02d", prev.Year(), prev.Month())
 }
 
+// GetNextMonth returns the month after the given YYYY-MM key.
+func GetNextMonth(month string) string {
+	t, _ := time.Parse("2006-01", month)
+	next := t.AddDate(0, 1, 0)
+	return fmt.Sprintf("%04d-%02d", next.Year(), next.Month())
+}
+
 // Cursor helpers for pagination
 
 // encodeCursor serializes a DynamoDB LastEvaluatedKey into a URL-safe
@@ -210,11 +278,11 @@ func decodeCursor(cursor string) (map[string]types.AttributeValue, error) {
 
 // validateExpenseCursor checks that a decoded expense-pagination cursor is a
 // well-formed ExclusiveStartKey for the GetExpenses query on `month`: exactly
-// the keys PK and SK, PK equal to this month's partition, and SK beginning
-// with the EXP# prefix. A fabricated or cross-month cursor returns
-// ErrInvalidCursor (→ 400) instead of reaching DynamoDB and triggering a
-// ValidationException → 500 (B5).
-func validateExpenseCursor(cursor map[string]types.AttributeValue, month string) error {
+// the keys PK and SK, PK equal to this month's partition in the context's
+// account, and SK beginning with the EXP# prefix. A fabricated, cross-month
+// or cross-account cursor returns ErrInvalidCursor (→ 400) instead of
+// reaching DynamoDB and triggering a ValidationException → 500 (B5).
+func validateExpenseCursor(ctx context.Context, cursor map[string]types.AttributeValue, month string) error {
 	if len(cursor) != 2 {
 		return ErrInvalidCursor
 	}
@@ -223,7 +291,7 @@ func validateExpenseCursor(cursor map[string]types.AttributeValue, month string)
 	if !pkOK || !skOK {
 		return ErrInvalidCursor
 	}
-	if pkAV.Value != repository.MonthPrefix+month {
+	if pkAV.Value != repository.AccountPK(ctx, repository.MonthPrefix+month) {
 		return ErrInvalidCursor
 	}
 	if !strings.HasPrefix(skAV.Value, repository.ExpensePrefix) {
@@ -267,7 +335,7 @@ func (s *ExpenseService) GetMonthData(ctx context.Context, month string, limit i
 		// (ErrInvalidCursor) rather than a DynamoDB ValidationException →
 		// 500 (B5). A resume key for this query must be exactly {PK, SK}
 		// where PK is this month's partition and SK is an EXP# row.
-		if err := validateExpenseCursor(cursor, month); err != nil {
+		if err := validateExpenseCursor(ctx, cursor, month); err != nil {
 			return nil, err
 		}
 	}
@@ -282,13 +350,24 @@ func (s *ExpenseService) GetMonthData(ctx context.Context, month string, limit i
 	expenseItems := make([]model.ExpenseItem, len(expenses))
 	for i, exp := range expenses {
 		expenseItems[i] = model.ExpenseItem{
-			ID:          exp.SK,
-			Amount:      exp.Amount,
-			Description: exp.Description,
-			CreatedAt:   exp.CreatedAt,
+			ID:           exp.SK,
+			Amount:       exp.Amount,
+			Description:  exp.Description,
+			Category:     exp.Category,
+			CreatedAt:    exp.CreatedAt,
+			InstalmentID: exp.InstalmentID,
+			Attachments:  exp.Attachments,
 		}
 	}
 
+	funds, err := s.repo.GetFundEntries(ctx, month)
+	if err != nil {
+		return nil, err
+	}
+	if funds == nil {
+		funds = []model.FundEntry{}
+	}
+
 	// Get total balance
 	balance, err := s.repo.GetBalance(ctx)
 	if err != nil {
@@ -307,17 +386,61 @@ func (s *ExpenseService) GetMonthData(ctx context.Context, month string, limit i
 	return &model.MonthDataResponse{
 		Month:        month,
 		Summary:      summary,
+		Categories:   categoryBreakdown(summary),
 		Expenses:     expenseItems,
 		TotalBalance: balance.TotalBalance,
 		NextCursor:   nextCursor,
+		Funds:        funds,
 	}, nil
 }
 
+// categoryBreakdown turns a summary's category_totals into the response's
+// sorted breakdown (largest first, ties by name). Spend that carries no
+// category is not tracked per row, so it is reported as the remainder of
+// total_expenses under UncategorizedCategory. Entries that net to zero —
+// a category whose last expense was deleted or re-categorized — are dropped.
+func categoryBreakdown(summary *model.MonthSummary) []model.CategoryTotal {
+	out := make([]model.CategoryTotal, 0, len(summary.CategoryTotals)+1)
+	var categorized model.Money
+	for cat, total := range summary.CategoryTotals {
+		if total == 0 {
+			continue
+		}
+		categorized += total
+		out = append(out, model.CategoryTotal{Category: cat, Total: total})
+	}
+	if rest := summary.TotalExpenses - categorized; rest > 0 {
+		out = append(out, model.CategoryTotal{Category: UncategorizedCategory, Total: rest})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Total != out[j].Total {
+			return out[i].Total > out[j].Total
+		}
+		return out[i].Category < out[j].Category
+	})
+	return out
+}
+
+// ensureCategoryTotals seeds the month's category_totals map (canonical row
+// and mirror) when the coming transaction touches any category. Months
+// written before categories existed have no map, and the nested per-category
+// update would otherwise cancel the whole transaction. Must run after
+// EnsureMonthListMirror for the same month.
+func (s *ExpenseService) ensureCategoryTotals(ctx context.Context, month string, categories ...string) error {
+	for _, c := range categories {
+		if c != "" {
+			return s.repo.EnsureCategoryTotals(ctx, month)
+		}
+	}
+	return nil
+}
+
 // ensureMonthExists gets or creates a month summary with $0 allowance (the
-// monthly allowance is only applied by an explicit CreateMonth). When
+// month's scheduled allowance is only applied by an explicit CreateMonth,
+// which tops the month up to it). When
 // carry-over is enabled, the previous month's ending balance is carried
-// forward as the starting balance; no global balance credit occurs here —
-// carrying moves no money.
+// forward as the starting balance; carrying moves no money, so the only
+// global balance credit here is the new month's interest, if any.
 func (s *ExpenseService) ensureMonthExists(ctx context.Context, month string) (*model.MonthSummary, error) {
 	summary, err := s.repo.GetMonthSummary(ctx, month)
 	if err != nil {
@@ -350,6 +473,7 @@ func (s *ExpenseService) ensureMonthExists(ctx context.Context, month string) (*
 		AllowanceAdded:  0,
 		TotalExpenses:   0,
 		EndingBalance:   startingBalance,
+		BaseAllowance:   new(model.Money),
 	}
 
 	if err := s.repo.CreateMonthSummaryIfAbsent(ctx, summary); err != nil {
@@ -368,6 +492,22 @@ func (s *ExpenseService) ensureMonthExists(ctx context.Context, month string) (*
 		return nil, err
 	}
 
+	// Interest on the carried balance is the one credit a $0 month does
+	// get: it is earned by the month existing, not granted like the
+	// allowance. Crediting it raises the ending balance, so it ripples into
+	// any later month.
+	interest, err := s.creditInterest(ctx, summary)
+	if err != nil {
+		return nil, err
+	}
+	if interest != 0 {
+		summary.AllowanceAdded += interest
+		summary.EndingBalance += interest
+		if err := s.propagateToLaterMonths(ctx, month, interest); err != nil {
+			return nil, err
+		}
+	}
+
 	return summary, nil
 }
 
@@ -411,7 +551,7 @@ func (s *ExpenseService) insufficientFunds(ctx context.Context, month string) er
 	if err != nil || summary == nil {
 		return ErrInsufficientFunds
 	}
-	return &InsufficientFundsError{Available: roundCents(summary.EndingBalance)}
+	return &InsufficientFundsError{Available: summary.EndingBalance}
 }
 
 // carriedStartingBalance returns the balance a newly-opened `month` should start
@@ -431,9 +571,10 @@ func (s *ExpenseService) insufficientFunds(ctx context.Context, month string) er
 // reports. It does not self-heal, because nothing recomputes a starting balance
 // once written. It also blocks legitimate spending: the orphaned month starts at
 // 0, so the next expense is refused for insufficient funds.
-func (s *ExpenseService) carriedStartingBalance(ctx context.Context, month string) (float64, error) {
-	if !s.carryOverBalance {
-		return 0, nil
+func (s *ExpenseService) carriedStartingBalance(ctx context.Context, month string) (model.Money, error) {
+	carry, err := s.carriesOver(ctx)
+	if err != nil || !carry {
+		return 0, err
 	}
 
 	// Fast path: the immediately preceding calendar month, which is the anchor
@@ -444,7 +585,7 @@ func (s *ExpenseService) carriedStartingBalance(ctx context.Context, month strin
 		return 0, err
 	}
 	if prev != nil {
-		return roundCents(prev.EndingBalance), nil
+		return prev.EndingBalance, nil
 	}
 
 	// There is a hole. Walk back for the newest month that actually exists.
@@ -455,7 +596,7 @@ func (s *ExpenseService) carriedStartingBalance(ctx context.Context, month strin
 	if anchor == nil {
 		return 0, nil
 	}
-	return roundCents(anchor.EndingBalance), nil
+	return anchor.EndingBalance, nil
 }
 
 // latestMonthBefore returns the canonical summary of the newest month strictly
@@ -496,7 +637,7 @@ func (s *ExpenseService) latestMonthBefore(ctx context.Context, month string) (*
 // and a charge at the destination.
 type monthImpulse struct {
 	month string
-	delta float64
+	delta model.Money
 }
 
 // ensureCarryChainAffordable refuses a mutation that would drive ANY month's
@@ -527,14 +668,15 @@ type monthImpulse struct {
 // separate transaction from the mutation it follows — and it is the cheaper side
 // of the trade against a silent overspend or a wrongly refused edit.
 func (s *ExpenseService) ensureCarryChainAffordable(ctx context.Context, impulses ...monthImpulse) error {
-	if s.allowOverspending {
-		return nil
+	settings, err := s.Settings(ctx)
+	if err != nil || settings.AllowOverspending {
+		return err
 	}
 
 	// With carry off, months are independent and the per-month
 	// ConditionExpression on each written row already says everything there is
 	// to say. Nothing here can add to it.
-	if !s.carryOverBalance {
+	if !settings.CarryOverBalance {
 		return nil
 	}
 
@@ -571,10 +713,10 @@ func (s *ExpenseService) ensureCarryChainAffordable(ctx context.Context, impulse
 	// month — that running total IS the carry propagation. The tightest
 	// resulting balance decides, and the tightest PRE-mutation balance is what
 	// the user can actually spend, so it is what the error reports.
-	carried := 0.0
-	worstShortfall := 0.0
-	available := math.Inf(1)
+	var carried, worstShortfall model.Money
+	var available *model.Money
 	for _, m := range months {
+		carriedIn := carried
 		for _, imp := range impulses {
 			if imp.month == m {
 				carried += imp.delta
@@ -589,12 +731,17 @@ func (s *ExpenseService) ensureCarryChainAffordable(ctx context.Context, impulse
 			// entry lands here too, and is reported by propagateToLaterMonths.
 			continue
 		}
-		if summary.EndingBalance < available {
-			available = summary.EndingBalance
+		// The shifted balance it carries in re-prices the month's interest
+		// (reconcileInterest), and that carries on too.
+		repriced, err := s.interestRepricing(ctx, summary, carriedIn)
+		if err != nil {
+			return err
+		}
+		carried += repriced
+		if available == nil || summary.EndingBalance < *available {
+			available = &summary.EndingBalance
 		}
-		// Cents, not floats: a 0.005 rounding artifact must not refuse a
-		// legitimate expense.
-		if shortfall := -roundCents(summary.EndingBalance + carried); shortfall > worstShortfall {
+		if shortfall := -(summary.EndingBalance + carried); shortfall > worstShortfall {
 			worstShortfall = shortfall
 		}
 	}
@@ -602,10 +749,10 @@ func (s *ExpenseService) ensureCarryChainAffordable(ctx context.Context, impulse
 	if worstShortfall <= 0 {
 		return nil
 	}
-	if math.IsInf(available, 1) {
+	if available == nil {
 		return ErrInsufficientFunds
 	}
-	return &InsufficientFundsError{Available: roundCents(available)}
+	return &InsufficientFundsError{Available: *available}
 }
 
 // propagateToLaterMonths shifts every month strictly after `month` by
@@ -631,10 +778,13 @@ func (s *ExpenseService) ensureCarryChainAffordable(ctx context.Context, impulse
 // mega-transaction). The propagation itself is all-or-nothing, but a crash
 // after the mutation commits and before propagation lands would leave later
 // months stale — see PropagateLaterMonthDeltas's residual-gap note.
-func (s *ExpenseService) propagateToLaterMonths(ctx context.Context, month string, endingDelta float64) error {
-	if !s.carryOverBalance || endingDelta == 0 {
+func (s *ExpenseService) propagateToLaterMonths(ctx context.Context, month string, endingDelta model.Money) error {
+	if endingDelta == 0 {
 		return nil
 	}
+	if carry, err := s.carriesOver(ctx); err != nil || !carry {
+		return err
+	}
 	later, err := s.monthsAfter(ctx, month)
 	if err != nil {
 		return err
@@ -678,7 +828,12 @@ func (s *ExpenseService) propagateToLaterMonths(ctx context.Context, month strin
 	if len(targets) == 0 {
 		return nil
 	}
-	return s.repo.PropagateLaterMonthDeltas(ctx, targets, roundCents(endingDelta))
+	if err := s.repo.PropagateLaterMonthDeltas(ctx, targets, endingDelta); err != nil {
+		return err
+	}
+	// The later months now carry in a different balance, which is what
+	// their interest was computed from.
+	return s.reconcileInterest(ctx, targets)
 }
 
 // monthsAfterPageSize bounds each MONTHLIST Query page. Months are one row
@@ -799,6 +954,15 @@ func (s *ExpenseService) ensureMonthListComplete(ctx context.Context) error {
 	return nil
 }
 
+// MigrateMoney sweeps the table for rows still holding float-dollar money
+// and rewrites them to integer cents. It scans the whole table, so only the
+// scheduled jobs call it: requests never need it, as the repository
+// converts a legacy row when it reads it and again before a write updates
+// it. Once the sweep has recorded its marker, a call is one point read.
+func (s *ExpenseService) MigrateMoney(ctx context.Context) error {
+	return s.repo.EnsureMoneyMigrated(ctx)
+}
+
 // mirroredMonths returns the set of months present in the MONTHLIST index,
 // paging through the partition.
 func (s *ExpenseService) mirroredMonths(ctx context.Context) (map[string]bool, error) {
@@ -836,19 +1000,41 @@ func (s *ExpenseService) mirroredMonths(ctx context.Context) (map[string]bool, e
 //
 // If the targeted month is not the latest, subsequent months' carried
 // balances are walked forward to keep the ledger consistent (B3).
+//
+// The daily and weekly spending limits are checked against the day and
+// week the expense is dated in, so a back-dated expense counts where it
+// lands. Scheduled bookings (recurring, instalments) and restores are not
+// held to them.
 func (s *ExpenseService) AddExpense(ctx context.Context, req *model.AddExpenseRequest) (*model.AddExpenseResponse, error) {
-	req.Amount = roundCents(req.Amount)
+	month, expense, err := s.newExpense(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureWithinSpendingLimits(ctx, expense.CreatedAt, expense.Amount, nil); err != nil {
+		return nil, err
+	}
+	return s.addExpense(ctx, month, expense)
+}
+
+// newExpense validates an add request and builds the expense row it
+// describes, with a fresh SK, and the month it belongs in.
+func (s *ExpenseService) newExpense(req *model.AddExpenseRequest) (string, *model.Expense, error) {
 	if req.Amount <= 0 || req.Amount > maxAmount {
-		return nil, ErrInvalidAmount
+		return "", nil, ErrInvalidAmount
 	}
 	description, err := validateDescription(req.Description)
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
 	req.Description = description
 	if req.Description == "" {
 		req.Description = "Expense"
 	}
+	category, err := validateCategory(req.Category)
+	if err != nil {
+		return "", nil, err
+	}
+	req.Category = category
 
 	// Resolve the target month and the expense timestamp together: an
 	// optional req.Date back-dates the expense (deriving/validating the
@@ -856,13 +1042,37 @@ func (s *ExpenseService) AddExpense(ctx context.Context, req *model.AddExpenseRe
 	// with a now() timestamp.
 	month, expenseTime, err := s.resolveMonthAndTime(req.Month, req.Date)
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
 
+	return month, &model.Expense{
+		SK:          fmt.Sprintf("%s%d#%s", repository.ExpensePrefix, expenseTime.UnixNano(), uuid.New().String()[:8]),
+		Amount:      req.Amount,
+		Description: req.Description,
+		Category:    req.Category,
+		CreatedAt:   expenseTime,
+	}, nil
+}
+
+// addExpense is the checked write behind AddExpense, shared with every
+// other path that books a new expense row (recurring schedules): the month
+// is created if missing, the category budget, carry chain and overspend
+// rules are all applied, and later months are re-chained. The expense must
+// arrive validated, with its SK and timestamp set. An SK that already exists
+// is refused with ErrDuplicateExpense rather than overwritten.
+func (s *ExpenseService) addExpense(ctx context.Context, month string, expense *model.Expense) (*model.AddExpenseResponse, error) {
+	return s.bookExpense(ctx, month, expense, s.repo.AtomicAddExpense)
+}
+
+// bookExpense is addExpense with the final write made by book: the
+// repository's AtomicAddExpense, or a variant that also deletes the pending
+// or trash entry the expense is booked from in the same transaction.
+func (s *ExpenseService) bookExpense(ctx context.Context, month string, expense *model.Expense, book func(ctx context.Context, month string, expense *model.Expense, checkBalance bool) error) (*model.AddExpenseResponse, error) {
 	// Ensure month summary exists. This non-atomic create-if-missing is
 	// idempotent and rare (once per month); the atomic transaction below
 	// then guarantees correctness of the actual expense write.
-	if _, err := s.ensureMonthExists(ctx, month); err != nil {
+	summary, err := s.ensureMonthExists(ctx, month)
+	if err != nil {
 		return nil, err
 	}
 	// Back-fill the MONTHLIST mirror on legacy tables before the atomic
@@ -873,31 +1083,48 @@ func (s *ExpenseService) AddExpense(ctx context.Context, req *model.AddExpenseRe
 	if err := s.repo.EnsureMonthListMirror(ctx, month); err != nil {
 		return nil, err
 	}
+	if err := s.ensureCategoryTotals(ctx, month, expense.Category); err != nil {
+		return nil, err
+	}
 
-	expense := &model.Expense{
-		SK:          fmt.Sprintf("%s%d#%s", repository.ExpensePrefix, expenseTime.UnixNano(), uuid.New().String()[:8]),
-		Amount:      req.Amount,
-		Description: req.Description,
-		CreatedAt:   expenseTime,
+	budget, enforceBudget, hasBudget, err := s.categoryBudget(ctx, expense.Category)
+	if err != nil {
+		return nil, err
+	}
+	if hasBudget {
+		if err := s.ensureCategoryBudgetAffordable(summary, expense.Category, expense.Amount, budget, enforceBudget); err != nil {
+			return nil, err
+		}
 	}
 
 	// The per-month condition inside AtomicAddExpense only guards THIS month.
 	// With carry on, a back-dated expense also reaches every later month, so the
 	// whole affected span has to be affordable before anything is written.
-	if err := s.ensureCarryChainAffordable(ctx, monthImpulse{month, -req.Amount}); err != nil {
+	if err := s.ensureCarryChainAffordable(ctx, monthImpulse{month, -expense.Amount}); err != nil {
 		return nil, err
 	}
 
-	if err := s.repo.AtomicAddExpense(ctx, month, expense, !s.allowOverspending); err != nil {
-		if errors.Is(err, repository.ErrInsufficientBalance) {
+	hardStop, err := s.hardStop(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := book(ctx, month, expense, hardStop); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrInsufficientBalance):
 			return nil, s.insufficientFunds(ctx, month)
+		case errors.Is(err, repository.ErrExpenseAlreadyExists):
+			return nil, ErrDuplicateExpense
+		case errors.Is(err, repository.ErrPendingExpenseNotFound):
+			return nil, ErrPendingExpenseNotFound
+		case errors.Is(err, repository.ErrTrashedExpenseNotFound):
+			return nil, ErrTrashedExpenseNotFound
 		}
 		return nil, err
 	}
 
 	// Adding an expense lowers this month's ending balance by the amount;
 	// ripple that through later months' carry chain.
-	if err := s.propagateToLaterMonths(ctx, month, -req.Amount); err != nil {
+	if err := s.propagateToLaterMonths(ctx, month, -expense.Amount); err != nil {
 		return nil, err
 	}
 
@@ -907,17 +1134,21 @@ func (s *ExpenseService) AddExpense(ctx context.Context, req *model.AddExpenseRe
 		return nil, err
 	}
 
-	monthBalance := 0.0
+	var monthBalance model.Money
 	if updatedSummary != nil {
 		monthBalance = updatedSummary.EndingBalance
 	}
 
-	return &model.AddExpenseResponse{
+	response := &model.AddExpenseResponse{
 		Success:      true,
 		Expense:      expense,
 		MonthBalance: monthBalance,
 		TotalBalance: balance.TotalBalance,
-	}, nil
+	}
+	if hasBudget {
+		response.CategoryBudget = categoryBudgetStatus(updatedSummary, expense.Category, budget)
+	}
+	return response, nil
 }
 
 // resolveMonth validates the optional client-supplied month parameter.
@@ -1004,13 +1235,16 @@ func (s *ExpenseService) resolveMonthAndTime(clientMonth, clientDate string) (st
 // SK is unchanged and the edit collapses to the existing amount/description
 // path so amount-only and description-only edits keep their established
 // behavior.
+//
+// Every path is held to the category budget of the month and category the
+// expense ends up in, as an add is (ensureCategoryBudgetForEdit). An edit
+// that raises the amount or re-dates the expense is held to the daily and
+// weekly spending limits of the day it ends up on, like an add.
 func (s *ExpenseService) UpdateExpense(ctx context.Context, month string, expenseID string, req *model.UpdateExpenseRequest) (*model.UpdateExpenseResponse, error) {
-	if req.Amount == nil && req.Description == nil && req.Date == "" {
+	if req.Amount == nil && req.Description == nil && req.Category == nil && req.Date == "" {
 		return nil, ErrNoChanges
 	}
 	if req.Amount != nil {
-		rounded := roundCents(*req.Amount)
-		req.Amount = &rounded
 		if *req.Amount <= 0 || *req.Amount > maxAmount {
 			return nil, ErrInvalidAmount
 		}
@@ -1022,6 +1256,13 @@ func (s *ExpenseService) UpdateExpense(ctx context.Context, month string, expens
 		}
 		req.Description = &trimmed
 	}
+	if req.Category != nil {
+		normalized, cerr := validateCategory(*req.Category)
+		if cerr != nil {
+			return nil, cerr
+		}
+		req.Category = &normalized
+	}
 
 	currentExpense, err := s.repo.GetExpense(ctx, month, expenseID)
 	if err != nil {
@@ -1030,6 +1271,9 @@ func (s *ExpenseService) UpdateExpense(ctx context.Context, month string, expens
 	if currentExpense == nil {
 		return nil, ErrExpenseNotFound
 	}
+	if err := s.ensureNotInstalment(ctx, currentExpense); err != nil {
+		return nil, err
+	}
 
 	newAmount := currentExpense.Amount
 	if req.Amount != nil {
@@ -1042,6 +1286,10 @@ func (s *ExpenseService) UpdateExpense(ctx context.Context, month string, expens
 	if newDescription == "" {
 		newDescription = "Expense"
 	}
+	newCategory := currentExpense.Category
+	if req.Category != nil {
+		newCategory = *req.Category
+	}
 
 	// Resolve the (possibly new) timestamp and target month from the optional
 	// date, using the same rule as the add path. An absent date keeps the
@@ -1065,13 +1313,21 @@ func (s *ExpenseService) UpdateExpense(ctx context.Context, month string, expens
 
 	dateChanged := !newTime.Equal(currentExpense.CreatedAt)
 	monthChanged := targetMonth != month
+	if err := s.ensureCategoryBudgetForEdit(ctx, month, currentExpense, targetMonth, newCategory, newAmount); err != nil {
+		return nil, err
+	}
+	if dateChanged || newAmount > currentExpense.Amount {
+		if err := s.ensureWithinSpendingLimits(ctx, newTime, newAmount, currentExpense); err != nil {
+			return nil, err
+		}
+	}
 
 	switch {
 	case monthChanged:
 		// Cross-month move: a full transaction that refunds the source month,
 		// charges the destination, and re-keys the expense (new SK encodes the
 		// new timestamp; keep the random id suffix stable).
-		newExpense, rerr := s.redatedExpense(currentExpense, newTime, newAmount, newDescription)
+		newExpense, rerr := s.redatedExpense(currentExpense, newTime, newAmount, newDescription, newCategory)
 		if rerr != nil {
 			return nil, rerr
 		}
@@ -1089,6 +1345,12 @@ func (s *ExpenseService) UpdateExpense(ctx context.Context, month string, expens
 		if err := s.repo.EnsureMonthListMirror(ctx, targetMonth); err != nil {
 			return nil, err
 		}
+		if err := s.ensureCategoryTotals(ctx, month, currentExpense.Category); err != nil {
+			return nil, err
+		}
+		if err := s.ensureCategoryTotals(ctx, targetMonth, newCategory); err != nil {
+			return nil, err
+		}
 		// A move is a refund at the source and a charge at the destination.
 		// Checking them together is what lets an affordable move through: when
 		// the destination is LATER than the source, the refund propagates into
@@ -1103,8 +1365,13 @@ func (s *ExpenseService) UpdateExpense(ctx context.Context, month string, expens
 		// account for that refund. Without it the condition asks whether the
 		// destination can afford the charge on its own, which is the wrong
 		// question and refuses moves that net to zero across the chain.
-		srcRefundReachesDst := s.carryOverBalance && month < targetMonth
-		if err := s.repo.AtomicMoveExpenseAcrossMonths(ctx, month, targetMonth, expenseID, newExpense, currentExpense.Amount, !s.allowOverspending, srcRefundReachesDst); err != nil {
+		settings, err := s.Settings(ctx)
+		if err != nil {
+			return nil, err
+		}
+		srcRefundReachesDst := settings.CarryOverBalance && month < targetMonth
+		hardStop := !settings.AllowOverspending
+		if err := s.repo.AtomicMoveExpenseAcrossMonths(ctx, month, targetMonth, currentExpense, newExpense, hardStop, srcRefundReachesDst); err != nil {
 			switch {
 			case errors.Is(err, repository.ErrInsufficientBalance):
 				return nil, s.insufficientFunds(ctx, targetMonth)
@@ -1123,24 +1390,31 @@ func (s *ExpenseService) UpdateExpense(ctx context.Context, month string, expens
 		if err := s.propagateToLaterMonths(ctx, targetMonth, -newAmount); err != nil {
 			return nil, err
 		}
-		return s.updateExpenseResponse(ctx, targetMonth, newExpense.SK, newAmount, newDescription, newTime)
+		return s.updateExpenseResponse(ctx, targetMonth, newExpense)
 
 	case dateChanged:
 		// Same-month re-date: the SK changes (it encodes the timestamp), so
-		// delete the old SK + put the new SK, composing any amount/description
-		// change in the same transaction.
-		newExpense, rerr := s.redatedExpense(currentExpense, newTime, newAmount, newDescription)
+		// delete the old SK + put the new SK, composing any
+		// amount/description/category change in the same transaction.
+		newExpense, rerr := s.redatedExpense(currentExpense, newTime, newAmount, newDescription, newCategory)
 		if rerr != nil {
 			return nil, rerr
 		}
 		if err := s.repo.EnsureMonthListMirror(ctx, month); err != nil {
 			return nil, err
 		}
+		if err := s.ensureCategoryTotals(ctx, month, currentExpense.Category, newCategory); err != nil {
+			return nil, err
+		}
 		if err := s.ensureCarryChainAffordable(ctx,
 			monthImpulse{month, -(newAmount - currentExpense.Amount)}); err != nil {
 			return nil, err
 		}
-		if err := s.repo.AtomicMoveExpenseSameMonth(ctx, month, expenseID, newExpense, currentExpense.Amount, !s.allowOverspending); err != nil {
+		hardStop, err := s.hardStop(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.repo.AtomicMoveExpenseSameMonth(ctx, month, currentExpense, newExpense, hardStop); err != nil {
 			switch {
 			case errors.Is(err, repository.ErrInsufficientBalance):
 				return nil, s.insufficientFunds(ctx, month)
@@ -1154,18 +1428,28 @@ func (s *ExpenseService) UpdateExpense(ctx context.Context, month string, expens
 		if err := s.propagateToLaterMonths(ctx, month, -amountDelta); err != nil {
 			return nil, err
 		}
-		return s.updateExpenseResponse(ctx, month, newExpense.SK, newAmount, newDescription, newTime)
+		return s.updateExpenseResponse(ctx, month, newExpense)
 
 	default:
 		// No date change (or same-day re-date): the SK is stable, so this is
-		// the established amount/description path.
+		// the established amount/description path. A category change moves
+		// money between category totals, and a description change re-files
+		// the expense in the search index, so either takes the transactional
+		// path even when the amount is unchanged.
 		amountDelta := newAmount - currentExpense.Amount
-		if amountDelta != 0 {
+		updated := *currentExpense
+		updated.Amount = newAmount
+		updated.Description = newDescription
+		updated.Category = newCategory
+		if amountDelta != 0 || newCategory != currentExpense.Category || newDescription != currentExpense.Description {
 			// Back-fill the MONTHLIST mirror on legacy tables so the atomic
 			// transaction's monthListUpdate condition can't cancel it (→ 500).
 			if err := s.repo.EnsureMonthListMirror(ctx, month); err != nil {
 				return nil, err
 			}
+			if err := s.ensureCategoryTotals(ctx, month, currentExpense.Category, newCategory); err != nil {
+				return nil, err
+			}
 			// Raising an amount in a PAST month reaches every later month, same
 			// as back-dating a new expense does.
 			if err := s.ensureCarryChainAffordable(ctx,
@@ -1173,7 +1457,11 @@ func (s *ExpenseService) UpdateExpense(ctx context.Context, month string, expens
 				return nil, err
 			}
 			// Atomic transaction with optimistic concurrency on amount.
-			if err := s.repo.AtomicUpdateExpense(ctx, month, expenseID, currentExpense.Amount, newAmount, newDescription, !s.allowOverspending); err != nil {
+			hardStop, err := s.hardStop(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if err := s.repo.AtomicUpdateExpense(ctx, month, currentExpense, &updated, hardStop); err != nil {
 				switch {
 				case errors.Is(err, repository.ErrInsufficientBalance):
 					return nil, s.insufficientFunds(ctx, month)
@@ -1191,7 +1479,7 @@ func (s *ExpenseService) UpdateExpense(ctx context.Context, month string, expens
 				return nil, err
 			}
 		} else {
-			// Description-only update doesn't touch summary/balance — single write is fine.
+			// Nothing indexed or totalled changed — single write is fine.
 			oldExpense, err := s.repo.UpdateExpense(ctx, month, expenseID, newAmount, newDescription)
 			if err != nil {
 				return nil, err
@@ -1200,7 +1488,8 @@ func (s *ExpenseService) UpdateExpense(ctx context.Context, month string, expens
 				return nil, ErrExpenseNotFound
 			}
 		}
-		return s.updateExpenseResponse(ctx, month, currentExpense.SK, newAmount, newDescription, newTime)
+		updated.CreatedAt = newTime
+		return s.updateExpenseResponse(ctx, month, &updated)
 	}
 }
 
@@ -1234,13 +1523,15 @@ func (s *ExpenseService) resolveExpenseTime(clientDate string) (string, time.Tim
 // stamped with newTime but reusing the SAME random id suffix as the old SK so
 // the expense keeps a stable identity across the move. Falls back to a fresh
 // suffix only if the old SK is malformed (no embedded suffix).
-func (s *ExpenseService) redatedExpense(old *model.Expense, newTime time.Time, newAmount float64, newDescription string) (*model.Expense, error) {
+func (s *ExpenseService) redatedExpense(old *model.Expense, newTime time.Time, newAmount model.Money, newDescription, newCategory string) (*model.Expense, error) {
 	suffix := expenseIDSuffix(old.SK)
 	return &model.Expense{
 		SK:          fmt.Sprintf("%s%d#%s", repository.ExpensePrefix, newTime.UnixNano(), suffix),
 		Amount:      newAmount,
 		Description: newDescription,
+		Category:    newCategory,
 		CreatedAt:   newTime,
+		Attachments: old.Attachments,
 	}, nil
 }
 
@@ -1260,22 +1551,23 @@ func expenseIDSuffix(sk string) string {
 // and assembles the UpdateExpenseResponse. The returned ExpenseItem carries
 // the possibly-new id (SK) and the target month so the client can detect a
 // cross-month move.
-func (s *ExpenseService) updateExpenseResponse(ctx context.Context, month, sk string, amount float64, description string, createdAt time.Time) (*model.UpdateExpenseResponse, error) {
+func (s *ExpenseService) updateExpenseResponse(ctx context.Context, month string, e *model.Expense) (*model.UpdateExpenseResponse, error) {
 	updatedSummary, balance, err := s.fetchSummaryAndBalance(ctx, month)
 	if err != nil {
 		return nil, err
 	}
-	monthBalance := 0.0
+	var monthBalance model.Money
 	if updatedSummary != nil {
 		monthBalance = updatedSummary.EndingBalance
 	}
 	return &model.UpdateExpenseResponse{
 		Success: true,
 		Expense: &model.ExpenseItem{
-			ID:          sk,
-			Amount:      amount,
-			Description: description,
-			CreatedAt:   createdAt,
+			ID:          e.SK,
+			Amount:      e.Amount,
+			Description: e.Description,
+			Category:    e.Category,
+			CreatedAt:   e.CreatedAt,
 			Month:       month,
 		},
 		MonthBalance: monthBalance,
@@ -1286,7 +1578,8 @@ func (s *ExpenseService) updateExpenseResponse(ctx context.Context, month, sk st
 // DeleteExpense deletes an expense and refunds the balance in a single
 // transaction. The expense's amount is read first so the refund delta is
 // known; the transaction conditions the delete on that amount to catch
-// any concurrent edit that landed in between.
+// any concurrent edit that landed in between. The expense moves to the
+// trash, from which RestoreExpense can bring it back until it expires.
 func (s *ExpenseService) DeleteExpense(ctx context.Context, month string, expenseID string) error {
 	currentExpense, err := s.repo.GetExpense(ctx, month, expenseID)
 	if err != nil {
@@ -1295,20 +1588,38 @@ func (s *ExpenseService) DeleteExpense(ctx context.Context, month string, expens
 	if currentExpense == nil {
 		return ErrExpenseNotFound
 	}
+	if err := s.ensureNotInstalment(ctx, currentExpense); err != nil {
+		return err
+	}
+	return s.deleteExpense(ctx, month, currentExpense, s.trashEntry(month, currentExpense, time.Now()))
+}
 
+// deleteExpense is the checked removal behind DeleteExpense, shared with
+// the instalment-plan rollback: it refunds the month, BALANCE and the
+// category total in one transaction (locked on currentExpense's amount) and
+// re-chains later months. With a trash entry the expense is filed in the
+// trash by the same transaction and its attachment blobs are kept for a
+// restore; without one it is gone, blobs included.
+func (s *ExpenseService) deleteExpense(ctx context.Context, month string, currentExpense *model.Expense, trash *model.TrashedExpense) error {
 	// Back-fill the MONTHLIST mirror on legacy tables so the atomic
 	// transaction's monthListUpdate condition can't cancel it (→ 500).
 	if err := s.repo.EnsureMonthListMirror(ctx, month); err != nil {
 		return err
 	}
+	if err := s.ensureCategoryTotals(ctx, month, currentExpense.Category); err != nil {
+		return err
+	}
 
-	if err := s.repo.AtomicDeleteExpense(ctx, month, expenseID, currentExpense.Amount); err != nil {
+	if err := s.repo.AtomicDeleteExpense(ctx, month, currentExpense, trash); err != nil {
 		if errors.Is(err, repository.ErrExpenseStateMismatch) {
 			// Found on read, changed before the conditional delete → 409 (U4).
 			return ErrExpenseModified
 		}
 		return err
 	}
+	if trash == nil {
+		s.deleteAttachmentBlobs(ctx, currentExpense.Attachments)
+	}
 
 	// Deleting refunds this month's ending balance by the amount; ripple
 	// that through later months' carry chain.
@@ -1356,7 +1667,7 @@ func (s *ExpenseService) ListMonths(ctx context.Context, limit int, cursorMonth
 			return nil, ErrInvalidCursor
 		}
 		cursor = map[string]types.AttributeValue{
-			"PK": &types.AttributeValueMemberS{Value: repository.PKMonthList},
+			"PK": &types.AttributeValueMemberS{Value: repository.AccountPK(ctx, repository.PKMonthList)},
 			"SK": &types.AttributeValueMemberS{Value: cursorMonth},
 		}
 	}
@@ -1389,11 +1700,11 @@ func (s *ExpenseService) ListMonths(ctx context.Context, limit int, cursorMonth
 		// MonthlySaved is the user-visible "delta this month" — what the
 		// month contributed above (or below) its starting balance. Using
 		// EndingBalance - StartingBalance is the carry-over-consistent
-		// answer; roundCents keeps float dust out of the JSON (B4).
+		// answer, and exact in cents (B4).
 		items[i] = model.MonthListItem{
 			Month:         m.Month,
-			MonthlySaved:  roundCents(m.EndingBalance - m.StartingBalance),
-			TotalExpenses: roundCents(m.TotalExpenses),
+			MonthlySaved:  m.EndingBalance - m.StartingBalance,
+			TotalExpenses: m.TotalExpenses,
 		}
 	}
 
@@ -1412,10 +1723,13 @@ func (s *ExpenseService) ListMonths(ctx context.Context, limit int, cursorMonth
 	}, nil
 }
 
-// CreateMonth creates a new month with the configured monthly allowance.
+// CreateMonth creates a new month with the allowance the schedule sets for
+// it (see allowanceFor).
 // The month summary write and the balance credit are wrapped in a single
 // DynamoDB transaction; an attribute_not_exists condition on the put
-// prevents two concurrent creates from both succeeding.
+// prevents two concurrent creates from both succeeding. The month's
+// interest on its carried balance is credited straight after, see
+// creditInterest.
 //
 // Idempotent allowance activation (U1): if the month already exists but
 // carries a $0 allowance — the state produced when an expense is filed
@@ -1423,7 +1737,8 @@ func (s *ExpenseService) ListMonths(ctx context.Context, limit int, cursorMonth
 // CreateMonth applies the allowance via an AddFunds-style transaction
 // instead of returning 409. This closes the "allowance trap" where that
 // month's allowance was silently never granted. A month that already has
-// a non-zero allowance still returns ErrMonthExists.
+// a non-zero allowance still returns ErrMonthExists; chore earnings,
+// interest and funds credited to the auto-created row are not one.
 func (s *ExpenseService) CreateMonth(ctx context.Context, month string) (*model.CreateMonthResponse, error) {
 	if err := ValidateMonth(month); err != nil {
 		return nil, ErrInvalidMonth
@@ -1435,17 +1750,40 @@ func (s *ExpenseService) CreateMonth(ctx context.Context, month string) (*model.
 	}
 	if existing != nil {
 		// Already activated with a real allowance — genuine duplicate.
-		if existing.AllowanceAdded != 0 {
+		// BaseAllowance is the allowance alone; a row from before it was
+		// recorded has its earnings and interest credit taken out instead.
+		granted := existing.AllowanceAdded - existing.EarningsAdded
+		if existing.BaseAllowance != nil {
+			granted = *existing.BaseAllowance
+		} else {
+			interest, err := s.interestCredited(ctx, month)
+			if err != nil {
+				return nil, err
+			}
+			granted -= interest
+		}
+		if granted != 0 {
 			return nil, ErrMonthExists
 		}
-		// Auto-created $0 month: top it up to the configured allowance.
-		allowance := roundCents(s.monthlyAllowance)
+		// Auto-created $0 month: top it up to its scheduled allowance, and
+		// credit its interest if it was opened before interest was on.
+		interest, err := s.creditInterest(ctx, existing)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.propagateToLaterMonths(ctx, month, interest); err != nil {
+			return nil, err
+		}
+		allowance, err := s.allowanceFor(ctx, month)
+		if err != nil {
+			return nil, err
+		}
 		if allowance > 0 {
 			// Back-fill the mirror on legacy tables before the atomic top-up.
 			if err := s.repo.EnsureMonthListMirror(ctx, month); err != nil {
 				return nil, err
 			}
-			if err := s.repo.AtomicAddFunds(ctx, month, allowance); err != nil {
+			if err := s.repo.AtomicAddFunds(ctx, month, allowance, nil); err != nil {
 				if errors.Is(err, repository.ErrExpenseStateMismatch) {
 					return nil, ErrMonthNotFound
 				}
@@ -1472,13 +1810,17 @@ func (s *ExpenseService) CreateMonth(ctx context.Context, month string) (*model.
 	if err != nil {
 		return nil, err
 	}
-	allowance := s.monthlyAllowance
+	allowance, err := s.allowanceFor(ctx, month)
+	if err != nil {
+		return nil, err
+	}
 	summary := &model.MonthSummary{
 		Month:           month,
 		StartingBalance: startingBalance,
 		AllowanceAdded:  allowance,
 		TotalExpenses:   0,
-		EndingBalance:   roundCents(startingBalance + allowance),
+		EndingBalance:   startingBalance + allowance,
+		BaseAllowance:   &allowance,
 	}
 
 	if err := s.repo.AtomicCreateMonth(ctx, summary, allowance); err != nil {
@@ -1487,13 +1829,19 @@ func (s *ExpenseService) CreateMonth(ctx context.Context, month string) (*model.
 		}
 		return nil, err
 	}
+	interest, err := s.creditInterest(ctx, summary)
+	if err != nil {
+		return nil, err
+	}
+	summary.AllowanceAdded += interest
+	summary.EndingBalance += interest
 
 	// Creating a month that is NOT the latest splices it into the middle of
 	// the carry chain: months after it were carrying from whatever preceded
 	// this gap, and must now carry from this month's ending balance instead.
-	// The shift is exactly what this month contributes — its allowance, since
-	// a freshly created month has no expenses.
-	if err := s.propagateToLaterMonths(ctx, month, allowance); err != nil {
+	// The shift is exactly what this month contributes — its allowance and
+	// interest, since a freshly created month has no expenses.
+	if err := s.propagateToLaterMonths(ctx, month, allowance+interest); err != nil {
 		return nil, err
 	}
 
@@ -1529,8 +1877,18 @@ func (s *ExpenseService) DeleteMonth(ctx context.Context, month string) error {
 	if summary.TotalExpenses != 0 {
 		return ErrMonthHasExpenses
 	}
+	// The month's funds credits go with it. One added or removed after this
+	// read moves allowance_added, which the delete pins.
+	funds, err := s.repo.GetFundEntries(ctx, month)
+	if err != nil {
+		return err
+	}
+	fundIDs := make([]string, len(funds))
+	for i, e := range funds {
+		fundIDs[i] = e.SK
+	}
 
-	if err := s.repo.AtomicDeleteMonth(ctx, month, summary.AllowanceAdded); err != nil {
+	if err := s.repo.AtomicDeleteMonth(ctx, month, summary.AllowanceAdded, fundIDs); err != nil {
 		if errors.Is(err, repository.ErrMonthHasExpenses) {
 			// Lost a race between the pre-read and the conditional delete. The
 			// transaction pins attribute_exists, total_expenses AND
@@ -1579,9 +1937,9 @@ func (s *ExpenseService) explainFailedMonthDelete(ctx context.Context, month str
 }
 
 // AddFunds tops up an existing month's allowance and credits the global
-// balance by the same amount in a single transaction.
-func (s *ExpenseService) AddFunds(ctx context.Context, month string, amount float64) (*model.AddFundsResponse, error) {
-	amount = roundCents(amount)
+// balance by the same amount in a single transaction, recording the top-up
+// as an itemized FUND# credit with its description.
+func (s *ExpenseService) AddFunds(ctx context.Context, month string, amount model.Money, description string) (*model.AddFundsResponse, error) {
 	if amount <= 0 {
 		return nil, ErrFundsNotPositive
 	}
@@ -1592,6 +1950,10 @@ func (s *ExpenseService) AddFunds(ctx context.Context, month string, amount floa
 	if amount > maxAmount {
 		return nil, ErrInvalidAmount
 	}
+	description, err := validateDescription(description)
+	if err != nil {
+		return nil, err
+	}
 
 	// Pre-check existence so the user gets a precise ErrMonthNotFound
 	// instead of a generic state-mismatch from the transaction.
@@ -1609,7 +1971,14 @@ func (s *ExpenseService) AddFunds(ctx context.Context, month string, amount floa
 		return nil, err
 	}
 
-	if err := s.repo.AtomicAddFunds(ctx, month, amount); err != nil {
+	now := time.Now().UTC()
+	entry := &model.FundEntry{
+		SK:          fmt.Sprintf("%s%d#%s", repository.FundPrefix, now.UnixNano(), uuid.New().String()[:8]),
+		Amount:      amount,
+		Description: description,
+		CreatedAt:   now,
+	}
+	if err := s.repo.AtomicAddFunds(ctx, month, amount, entry); err != nil {
 		if errors.Is(err, repository.ErrExpenseStateMismatch) {
 			// Month vanished between our pre-check and the transaction.
 			return nil, ErrMonthNotFound
@@ -1633,5 +2002,90 @@ func (s *ExpenseService) AddFunds(ctx context.Context, month string, amount floa
 		Success:      true,
 		Summary:      updatedSummary,
 		TotalBalance: balance.TotalBalance,
+		Entry:        entry,
+	}, nil
+}
+
+// WithdrawFunds takes amount back out of a month's allowance — a parent
+// reclaiming part of what was granted. It is AddFunds in reverse: it debits
+// allowance_added, ending_balance and the global balance together, itemizes
+// the withdrawal as a negative funds entry, and ripples the lower ending
+// balance through the later months. It cannot take back more than the
+// month's allowance_added less its earnings_added — what chores earned is
+// the child's, not the parent's to reclaim — and on a hard-stop instance it
+// is refused like an expense would be if it would drive this or any later
+// month below zero.
+func (s *ExpenseService) WithdrawFunds(ctx context.Context, month string, amount model.Money) (*model.AddFundsResponse, error) {
+	if amount <= 0 {
+		return nil, ErrFundsNotPositive
+	}
+	if amount > maxAmount {
+		return nil, ErrInvalidAmount
+	}
+
+	summary, err := s.repo.GetMonthSummary(ctx, month)
+	if err != nil {
+		return nil, err
+	}
+	if summary == nil {
+		return nil, ErrMonthNotFound
+	}
+	if amount > summary.AllowanceAdded-summary.EarningsAdded {
+		return nil, ErrWithdrawalExceedsAllowance
+	}
+	if err := s.ensureCarryChainAffordable(ctx, monthImpulse{month, -amount}); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.EnsureMonthListMirror(ctx, month); err != nil {
+		return nil, err
+	}
+
+	hardStop, err := s.hardStop(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	entry := &model.FundEntry{
+		SK:        fmt.Sprintf("%s%d#%s", repository.FundPrefix, now.UnixNano(), uuid.New().String()[:8]),
+		Amount:    -amount,
+		Kind:      model.FundKindWithdrawal,
+		CreatedAt: now,
+	}
+	if err := s.repo.AtomicWithdrawFunds(ctx, month, entry, summary.EarningsAdded, hardStop); err != nil {
+		if !errors.Is(err, repository.ErrExpenseStateMismatch) {
+			return nil, err
+		}
+		// One condition covers three rules; the month as it is now says
+		// which of them refused the withdrawal.
+		current, rerr := s.repo.GetMonthSummary(ctx, month)
+		switch {
+		case rerr != nil:
+			return nil, rerr
+		case current == nil:
+			return nil, ErrMonthNotFound
+		case amount > current.AllowanceAdded-current.EarningsAdded:
+			return nil, ErrWithdrawalExceedsAllowance
+		case current.EarningsAdded != summary.EarningsAdded:
+			// A chore was approved (or undone) in between; the cap moved.
+			return nil, ErrMonthModified
+		default:
+			return nil, &InsufficientFundsError{Available: current.EndingBalance}
+		}
+	}
+
+	if err := s.propagateToLaterMonths(ctx, month, -amount); err != nil {
+		return nil, err
+	}
+
+	updatedSummary, balance, err := s.fetchSummaryAndBalance(ctx, month)
+	if err != nil {
+		return nil, err
+	}
+	return &model.AddFundsResponse{
+		Success:      true,
+		Summary:      updatedSummary,
+		TotalBalance: balance.TotalBalance,
+		Entry:        entry,
 	}, nil
 }
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/testutil"
+)
+
+// =====================================================================
+// Per-category budgets — reported on every categorized add, and refused
+// only under hard-stop with enforcement switched on.
+// =====================================================================
+
+func setBudgets(t *testing.T, svc *ExpenseService, repo *testutil.FakeRepo, budgets map[string]model.Money, enforce bool) {
+	t.Helper()
+	if repo.Config == nil {
+		repo.Config = &model.Config{PinHash: "x"}
+	}
+	if _, err := svc.SetCategoryBudgets(context.Background(), &model.CategoryBudgets{Budgets: budgets, Enforce: enforce}); err != nil {
+		t.Fatalf("SetCategoryBudgets: %v", err)
+	}
+}
+
+func TestSetCategoryBudgets_NormalizesAndPreservesThePIN(t *testing.T) {
+	svc, repo := newExpenseService(t, false, false, 0)
+	setBudgets(t, svc, repo, map[string]model.Money{" Coffee ": model.Dollars(40.004)}, true)
+
+	if repo.Config.PinHash != "x" {
+		t.Errorf("PinHash = %q, the budget write must not disturb it", repo.Config.PinHash)
+	}
+	got, err := svc.GetCategoryBudgets(context.Background())
+	if err != nil {
+		t.Fatalf("GetCategoryBudgets: %v", err)
+	}
+	if got.Budgets["coffee"] != model.Dollars(40) || !got.Enforce || len(got.Budgets) != 1 {
+		t.Errorf("budgets = %+v, want coffee 40 enforced", got)
+	}
+}
+
+func TestSetCategoryBudgets_Validation(t *testing.T) {
+	svc, repo := newExpenseService(t, false, false, 0)
+	repo.Config = &model.Config{PinHash: "x"}
+
+	cases := []struct {
+		name    string
+		budgets map[string]model.Money
+		want    error
+	}{
+		{"zero amount", map[string]model.Money{"coffee": model.Dollars(0)}, ErrInvalidBudget},
+		{"over ceiling", map[string]model.Money{"coffee": model.Dollars(100000)}, ErrInvalidBudget},
+		{"empty category", map[string]model.Money{" ": model.Dollars(10)}, ErrInvalidBudgetCategory},
+		{"reserved label", map[string]model.Money{"Uncategorized": model.Dollars(10)}, ErrInvalidBudgetCategory},
+		{"duplicate after normalizing", map[string]model.Money{"Food": model.Dollars(10), "food": model.Dollars(20)}, ErrInvalidBudgetCategory},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.SetCategoryBudgets(context.Background(), &model.CategoryBudgets{Budgets: tc.budgets})
+			if !errors.Is(err, tc.want) {
+				t.Errorf("err = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestAddExpense_ReportsRemainingCategoryBudget(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	month, date := pastMonthDay(1, 10)
+	testutil.SeedMonth(repo, month, 0, 100, 0, 100)
+	setBudgets(t, svc, repo, map[string]model.Money{"coffee": model.Dollars(40)}, false)
+
+	resp, err := svc.AddExpense(context.Background(), &model.AddExpenseRequest{Amount: model.Dollars(15), Category: "coffee", Date: date})
+	if err != nil {
+		t.Fatalf("AddExpense: %v", err)
+	}
+	want := model.CategoryBudgetStatus{Category: "coffee", Budget: model.Dollars(40), Spent: model.Dollars(15), Remaining: model.Dollars(25)}
+	if resp.CategoryBudget == nil || *resp.CategoryBudget != want {
+		t.Fatalf("budget status = %+v, want %+v", resp.CategoryBudget, want)
+	}
+
+	// Without enforcement an overrun is allowed and flagged.
+	resp, err = svc.AddExpense(context.Background(), &model.AddExpenseRequest{Amount: model.Dollars(30), Category: "coffee", Date: date})
+	if err != nil {
+		t.Fatalf("AddExpense over budget: %v", err)
+	}
+	if !resp.CategoryBudget.OverBudget || resp.CategoryBudget.Remaining != model.Dollars(-5) {
+		t.Errorf("budget status = %+v, want over budget by 5", resp.CategoryBudget)
+	}
+
+	// A category with no budget reports nothing.
+	resp, err = svc.AddExpense(context.Background(), &model.AddExpenseRequest{Amount: model.Dollars(1), Category: "games", Date: date})
+	if err != nil {
+		t.Fatalf("AddExpense unbudgeted: %v", err)
+	}
+	if resp.CategoryBudget != nil {
+		t.Errorf("budget status = %+v, want nil for an unbudgeted category", resp.CategoryBudget)
+	}
+}
+
+func TestAddExpense_EnforcedBudgetRefusesUnderHardStop(t *testing.T) {
+	svc, repo := newExpenseService(t, false, false, 0)
+	month, date := pastMonthDay(1, 10)
+	testutil.SeedMonth(repo, month, 0, 100, 0, 100)
+	setBudgets(t, svc, repo, map[string]model.Money{"coffee": model.Dollars(40)}, true)
+
+	if _, err := svc.AddExpense(context.Background(), &model.AddExpenseRequest{Amount: model.Dollars(30), Category: "coffee", Date: date}); err != nil {
+		t.Fatalf("AddExpense within budget: %v", err)
+	}
+	_, err := svc.AddExpense(context.Background(), &model.AddExpenseRequest{Amount: model.Dollars(10.01), Category: "coffee", Date: date})
+	var exceeded *CategoryBudgetError
+	if !errors.As(err, &exceeded) {
+		t.Fatalf("err = %v, want *CategoryBudgetError", err)
+	}
+	if exceeded.Category != "coffee" || exceeded.Remaining != model.Dollars(10) {
+		t.Errorf("error = %+v, want coffee with 10 remaining", exceeded)
+	}
+	if repo.Months[month].TotalExpenses != model.Dollars(30) {
+		t.Errorf("total expenses = %v, the refused expense must not land", repo.Months[month].TotalExpenses)
+	}
+
+	// Exactly the remainder is still allowed.
+	if _, err := svc.AddExpense(context.Background(), &model.AddExpenseRequest{Amount: model.Dollars(10), Category: "coffee", Date: date}); err != nil {
+		t.Fatalf("AddExpense of the exact remainder: %v", err)
+	}
+}
+
+// Enforcement only applies to hard-stop instances; with overspending allowed
+// the budget is informational.
+func TestAddExpense_EnforcedBudgetIgnoredWhenOverspendingAllowed(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	month, date := pastMonthDay(1, 10)
+	testutil.SeedMonth(repo, month, 0, 100, 0, 100)
+	setBudgets(t, svc, repo, map[string]model.Money{"coffee": model.Dollars(5)}, true)
+
+	resp, err := svc.AddExpense(context.Background(), &model.AddExpenseRequest{Amount: model.Dollars(8), Category: "coffee", Date: date})
+	if err != nil {
+		t.Fatalf("AddExpense: %v", err)
+	}
+	if !resp.CategoryBudget.OverBudget {
+		t.Errorf("budget status = %+v, want over budget", resp.CategoryBudget)
+	}
+}
+
+// An edit is held to the budget of the month and category it ends up in,
+// with the expense's old amount left out of the total it was counted in.
+func TestUpdateExpense_EnforcedBudget(t *testing.T) {
+	ctx := context.Background()
+	srcMonth, srcDate := pastMonthDay(2, 10)
+	dstMonth, dstDate := pastMonthDay(1, 10)
+	add := func(t *testing.T, svc *ExpenseService, amount float64, category, date string) *model.Expense {
+		t.Helper()
+		resp, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(amount), Category: category, Date: date})
+		if err != nil {
+			t.Fatalf("AddExpense: %v", err)
+		}
+		return resp.Expense
+	}
+	setup := func(t *testing.T) (*ExpenseService, *testutil.FakeRepo) {
+		t.Helper()
+		svc, repo := newExpenseService(t, false, false, 0)
+		testutil.SeedMonth(repo, srcMonth, 0, 100, 0, 100)
+		testutil.SeedMonth(repo, dstMonth, 0, 100, 0, 100)
+		setBudgets(t, svc, repo, map[string]model.Money{"coffee": model.Dollars(40)}, true)
+		return svc, repo
+	}
+
+	t.Run("raise", func(t *testing.T) {
+		svc, repo := setup(t)
+		add(t, svc, 15, "coffee", srcDate)
+		e := add(t, svc, 20, "coffee", srcDate)
+
+		upTo := model.Dollars(25)
+		if _, err := svc.UpdateExpense(ctx, srcMonth, e.SK, &model.UpdateExpenseRequest{Amount: &upTo}); err != nil {
+			t.Fatalf("raise to the budget: %v", err)
+		}
+		over := model.Dollars(25.01)
+		_, err := svc.UpdateExpense(ctx, srcMonth, e.SK, &model.UpdateExpenseRequest{Amount: &over})
+		var exceeded *CategoryBudgetError
+		if !errors.As(err, &exceeded) || exceeded.Remaining != model.Dollars(25) {
+			t.Fatalf("err = %v, want a CategoryBudgetError with 25 remaining", err)
+		}
+		if repo.Months[srcMonth].CategoryTotals["coffee"] != model.Dollars(40) {
+			t.Errorf("coffee total = %v, the refused raise must not land", repo.Months[srcMonth].CategoryTotals["coffee"])
+		}
+
+		// Once the budget is lowered below what is spent, an edit that adds
+		// nothing to the category still goes through.
+		setBudgets(t, svc, repo, map[string]model.Money{"coffee": model.Dollars(10)}, true)
+		lower := model.Dollars(24)
+		if _, err := svc.UpdateExpense(ctx, srcMonth, e.SK, &model.UpdateExpenseRequest{Amount: &lower}); err != nil {
+			t.Fatalf("lowering under a lowered budget: %v", err)
+		}
+	})
+
+	t.Run("recategorize", func(t *testing.T) {
+		svc, _ := setup(t)
+		add(t, svc, 30, "coffee", srcDate)
+		e := add(t, svc, 15, "snacks", srcDate)
+
+		coffee := "coffee"
+		if _, err := svc.UpdateExpense(ctx, srcMonth, e.SK, &model.UpdateExpenseRequest{Category: &coffee}); !errors.Is(err, ErrCategoryBudgetExceeded) {
+			t.Fatalf("recategorize err = %v, want ErrCategoryBudgetExceeded", err)
+		}
+		amount := model.Dollars(10)
+		if _, err := svc.UpdateExpense(ctx, srcMonth, e.SK, &model.UpdateExpenseRequest{Category: &coffee, Amount: &amount}); err != nil {
+			t.Fatalf("recategorize within the budget: %v", err)
+		}
+	})
+
+	t.Run("cross-month re-date", func(t *testing.T) {
+		svc, repo := setup(t)
+		add(t, svc, 30, "coffee", dstDate)
+		e := add(t, svc, 15, "coffee", srcDate)
+
+		// The destination month's coffee total is what counts, whatever the
+		// expense was counted as in its source month.
+		_, err := svc.UpdateExpense(ctx, srcMonth, e.SK, &model.UpdateExpenseRequest{Date: dstDate})
+		var exceeded *CategoryBudgetError
+		if !errors.As(err, &exceeded) || exceeded.Remaining != model.Dollars(10) {
+			t.Fatalf("re-date err = %v, want a CategoryBudgetError with 10 remaining", err)
+		}
+		if repo.Months[srcMonth].CategoryTotals["coffee"] != model.Dollars(15) {
+			t.Errorf("source coffee total = %v, the refused move must not land", repo.Months[srcMonth].CategoryTotals["coffee"])
+		}
+	})
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/vppillai/passbook/backend/internal/model"
@@ -40,7 +41,7 @@ func TestExpenseMutation_DoesNotScanTablePerWrite(t *testing.T) {
 		testutil.SeedMonth(repo, "2026-01", 0, 100, 0, 100)
 		testutil.SeedMonth(repo, "2026-02", 100, 100, 0, 200)
 		testutil.SeedMonth(repo, "2026-03", 200, 100, 0, 300)
-		repo.Balance = &model.Balance{TotalBalance: 300}
+		repo.Balance = &model.Balance{TotalBalance: model.Dollars(300)}
 		return svc, repo
 	}
 
@@ -48,7 +49,7 @@ func TestExpenseMutation_DoesNotScanTablePerWrite(t *testing.T) {
 		svc, repo := seed(t)
 		for i := 0; i < 5; i++ {
 			if _, err := svc.AddExpense(ctx, &model.AddExpenseRequest{
-				Amount: 5, Description: "book", Month: "2026-03",
+				Amount: model.Dollars(5), Description: "book", Month: "2026-03",
 			}); err != nil {
 				t.Fatalf("AddExpense #%d: %v", i, err)
 			}
@@ -62,17 +63,17 @@ func TestExpenseMutation_DoesNotScanTablePerWrite(t *testing.T) {
 	t.Run("mixed mutations scan at most once", func(t *testing.T) {
 		svc, repo := seed(t)
 		id := "EXP#1#abc"
-		repo.Expenses[testutil.ExpenseKey("2026-03", id)] = &model.Expense{SK: id, Amount: 25}
-		repo.Months["2026-03"].TotalExpenses = 25
-		repo.Months["2026-03"].EndingBalance = 275
+		repo.Expenses[testutil.ExpenseKey("2026-03", id)] = &model.Expense{SK: id, Amount: model.Dollars(25)}
+		repo.Months["2026-03"].TotalExpenses = model.Dollars(25)
+		repo.Months["2026-03"].EndingBalance = model.Dollars(275)
 
-		newAmount := 40.0
+		newAmount := model.Dollars(40)
 		if _, err := svc.UpdateExpense(ctx, "2026-03", id,
 			&model.UpdateExpenseRequest{Amount: &newAmount}); err != nil {
 			t.Fatalf("UpdateExpense: %v", err)
 		}
 		if _, err := svc.AddExpense(ctx, &model.AddExpenseRequest{
-			Amount: 5, Description: "x", Month: "2026-02",
+			Amount: model.Dollars(5), Description: "x", Month: "2026-02",
 		}); err != nil {
 			t.Fatalf("AddExpense: %v", err)
 		}
@@ -89,15 +90,15 @@ func TestExpenseMutation_DoesNotScanTablePerWrite(t *testing.T) {
 	t.Run("past month still propagates", func(t *testing.T) {
 		svc, repo := seed(t)
 		_, err := svc.AddExpense(ctx, &model.AddExpenseRequest{
-			Amount: 30, Description: "past", Month: "2026-01",
+			Amount: model.Dollars(30), Description: "past", Month: "2026-01",
 		})
 		if err != nil {
 			t.Fatalf("AddExpense: %v", err)
 		}
-		if got := repo.Months["2026-02"].StartingBalance; got != 70 {
+		if got := repo.Months["2026-02"].StartingBalance; got != model.Dollars(70) {
 			t.Errorf("Feb starting = %v, want 70 (carry shifted by -30)", got)
 		}
-		if got := repo.Months["2026-03"].EndingBalance; got != 270 {
+		if got := repo.Months["2026-03"].EndingBalance; got != model.Dollars(270) {
 			t.Errorf("Mar ending = %v, want 270 (carry shifted by -30)", got)
 		}
 	})
@@ -115,11 +116,11 @@ func TestExpenseMutation_DoesNotScanTablePerWrite(t *testing.T) {
 		svc, repo := newExpenseService(t, true, true, 100)
 		testutil.SeedLegacyMonth(repo, "2026-01", 0, 100, 0, 100)
 		testutil.SeedLegacyMonth(repo, "2026-02", 100, 100, 0, 200)
-		repo.Balance = &model.Balance{TotalBalance: 200}
+		repo.Balance = &model.Balance{TotalBalance: model.Dollars(200)}
 
-		for i, want := range []float64{70, 40} {
+		for i, want := range []model.Money{model.Dollars(70), model.Dollars(40)} {
 			if _, err := svc.AddExpense(ctx, &model.AddExpenseRequest{
-				Amount: 30, Description: "past", Month: "2026-01",
+				Amount: model.Dollars(30), Description: "past", Month: "2026-01",
 			}); err != nil {
 				t.Fatalf("AddExpense #%d on legacy table: %v", i, err)
 			}
@@ -157,24 +158,24 @@ func TestPropagation_IgnoresOrphanMonthListMirror(t *testing.T) {
 	svc, repo := newExpenseService(t, true, true, 100)
 	testutil.SeedMonth(repo, "2026-01", 0, 100, 0, 100)
 	testutil.SeedMonth(repo, "2026-02", 100, 100, 0, 200)
-	repo.Balance = &model.Balance{TotalBalance: 200}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(200)}
 
 	// A mirror with no canonical row behind it.
 	repo.MonthList["2026-03"] = &model.MonthSummary{
-		Month: "2026-03", StartingBalance: 200, EndingBalance: 300,
+		Month: "2026-03", StartingBalance: model.Dollars(200), EndingBalance: model.Dollars(300),
 	}
 
 	if _, err := svc.AddExpense(ctx, &model.AddExpenseRequest{
-		Amount: 30, Description: "past", Month: "2026-01",
+		Amount: model.Dollars(30), Description: "past", Month: "2026-01",
 	}); err != nil {
 		t.Fatalf("AddExpense failed because of an orphan mirror: %v", err)
 	}
 
 	// The real later month must still have been shifted.
-	if got := repo.Months["2026-02"].StartingBalance; got != 70 {
+	if got := repo.Months["2026-02"].StartingBalance; got != model.Dollars(70) {
 		t.Errorf("Feb starting = %v, want 70 (propagation must survive the orphan)", got)
 	}
-	if got := repo.Months["2026-02"].EndingBalance; got != 170 {
+	if got := repo.Months["2026-02"].EndingBalance; got != model.Dollars(170) {
 		t.Errorf("Feb ending = %v, want 170", got)
 	}
 	assertLedgerConsistent(t, repo, "2026-01", "2026-02")
@@ -202,7 +203,7 @@ func TestPropagation_IgnoresOrphanMonthListMirror(t *testing.T) {
 // newest month's ending balance. months must be in ascending order.
 func assertLedgerConsistent(t *testing.T, repo *testutil.FakeRepo, months ...string) {
 	t.Helper()
-	var prevEnding float64
+	var prevEnding model.Money
 	for i, m := range months {
 		s := repo.Months[m]
 		if s == nil {
@@ -237,26 +238,79 @@ func TestAddFunds_PropagatesCarryToLaterMonths(t *testing.T) {
 	testutil.SeedMonth(repo, "2026-01", 0, 100, 0, 100)
 	testutil.SeedMonth(repo, "2026-02", 100, 100, 0, 200)
 	testutil.SeedMonth(repo, "2026-03", 200, 100, 0, 300)
-	repo.Balance = &model.Balance{TotalBalance: 300}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(300)}
 
 	// Top up a PAST month: January's ending rises by 50, so February and
 	// March must each carry 50 more.
-	if _, err := svc.AddFunds(ctx, "2026-01", 50); err != nil {
+	if _, err := svc.AddFunds(ctx, "2026-01", model.Dollars(50), ""); err != nil {
 		t.Fatalf("AddFunds: %v", err)
 	}
 
-	if got := repo.Months["2026-01"].EndingBalance; got != 150 {
+	if got := repo.Months["2026-01"].EndingBalance; got != model.Dollars(150) {
 		t.Errorf("Jan ending = %v, want 150", got)
 	}
-	if got := repo.Months["2026-02"].StartingBalance; got != 150 {
+	if got := repo.Months["2026-02"].StartingBalance; got != model.Dollars(150) {
 		t.Errorf("Feb starting = %v, want 150 (carry shifted by +50)", got)
 	}
-	if got := repo.Months["2026-03"].EndingBalance; got != 350 {
+	if got := repo.Months["2026-03"].EndingBalance; got != model.Dollars(350) {
 		t.Errorf("Mar ending = %v, want 350 (carry shifted by +50)", got)
 	}
 	assertLedgerConsistent(t, repo, "2026-01", "2026-02", "2026-03")
 }
 
+func TestWithdrawFunds_PropagatesCarryToLaterMonths(t *testing.T) {
+	ctx := context.Background()
+	seed := func(t *testing.T, allow bool) (*ExpenseService, *testutil.FakeRepo) {
+		svc, repo := newExpenseService(t, allow, true, 100)
+		testutil.SeedMonth(repo, "2026-01", 0, 100, 0, 100)
+		testutil.SeedMonth(repo, "2026-02", 100, 100, 0, 200)
+		testutil.SeedMonth(repo, "2026-03", 200, 100, 250, 50)
+		repo.Balance = &model.Balance{TotalBalance: model.Dollars(50)}
+		return svc, repo
+	}
+
+	t.Run("a past withdrawal shifts every later month", func(t *testing.T) {
+		svc, repo := seed(t, true)
+		resp, err := svc.WithdrawFunds(ctx, "2026-01", model.Dollars(40))
+		if err != nil {
+			t.Fatalf("WithdrawFunds: %v", err)
+		}
+		if got := repo.Months["2026-01"].AllowanceAdded; got != model.Dollars(60) {
+			t.Errorf("Jan allowance = %v, want 60", got)
+		}
+		if got := repo.Months["2026-03"].EndingBalance; got != model.Dollars(10) {
+			t.Errorf("Mar ending = %v, want 10 (carry shifted by -40)", got)
+		}
+		if resp.TotalBalance != model.Dollars(10) {
+			t.Errorf("total balance = %v, want 10", resp.TotalBalance)
+		}
+		assertLedgerConsistent(t, repo, "2026-01", "2026-02", "2026-03")
+	})
+
+	t.Run("hard stop refuses driving a later month negative", func(t *testing.T) {
+		svc, repo := seed(t, false)
+		// January has 100 to give back, but March ends on only 50.
+		_, err := svc.WithdrawFunds(ctx, "2026-01", model.Dollars(60))
+		var insufficient *InsufficientFundsError
+		if !errors.As(err, &insufficient) || insufficient.Available != model.Dollars(50) {
+			t.Fatalf("WithdrawFunds err = %v, want InsufficientFundsError with 50 available", err)
+		}
+		if got := repo.Months["2026-01"].AllowanceAdded; got != model.Dollars(100) {
+			t.Errorf("Jan allowance = %v, want it untouched", got)
+		}
+	})
+
+	t.Run("cannot take back more than was granted", func(t *testing.T) {
+		svc, _ := seed(t, true)
+		if _, err := svc.WithdrawFunds(ctx, "2026-02", model.Dollars(101)); !errors.Is(err, ErrWithdrawalExceedsAllowance) {
+			t.Errorf("WithdrawFunds err = %v, want ErrWithdrawalExceedsAllowance", err)
+		}
+		if _, err := svc.WithdrawFunds(ctx, "2026-02", 0); !errors.Is(err, ErrFundsNotPositive) {
+			t.Errorf("zero withdrawal err = %v, want ErrFundsNotPositive", err)
+		}
+	})
+}
+
 func TestCreateMonth_PropagatesCarryToLaterMonths(t *testing.T) {
 	ctx := context.Background()
 
@@ -266,7 +320,7 @@ func TestCreateMonth_PropagatesCarryToLaterMonths(t *testing.T) {
 		// straight from January.
 		testutil.SeedMonth(repo, "2026-01", 0, 100, 0, 100)
 		testutil.SeedMonth(repo, "2026-03", 100, 100, 0, 200)
-		repo.Balance = &model.Balance{TotalBalance: 200}
+		repo.Balance = &model.Balance{TotalBalance: model.Dollars(200)}
 
 		if _, err := svc.CreateMonth(ctx, "2026-02"); err != nil {
 			t.Fatalf("CreateMonth: %v", err)
@@ -274,10 +328,10 @@ func TestCreateMonth_PropagatesCarryToLaterMonths(t *testing.T) {
 
 		// February carries from January and adds its own allowance, so March
 		// must now start from February's ending, not January's.
-		if got := repo.Months["2026-02"].EndingBalance; got != 200 {
+		if got := repo.Months["2026-02"].EndingBalance; got != model.Dollars(200) {
 			t.Errorf("Feb ending = %v, want 200", got)
 		}
-		if got := repo.Months["2026-03"].StartingBalance; got != 200 {
+		if got := repo.Months["2026-03"].StartingBalance; got != model.Dollars(200) {
 			t.Errorf("Mar starting = %v, want 200 (should carry from Feb, not Jan)", got)
 		}
 		assertLedgerConsistent(t, repo, "2026-01", "2026-02", "2026-03")
@@ -290,16 +344,16 @@ func TestCreateMonth_PropagatesCarryToLaterMonths(t *testing.T) {
 		// a $0 allowance. CreateMonth activates the real allowance (U1).
 		testutil.SeedMonth(repo, "2026-02", 100, 0, 20, 80)
 		testutil.SeedMonth(repo, "2026-03", 80, 100, 0, 180)
-		repo.Balance = &model.Balance{TotalBalance: 180}
+		repo.Balance = &model.Balance{TotalBalance: model.Dollars(180)}
 
 		if _, err := svc.CreateMonth(ctx, "2026-02"); err != nil {
 			t.Fatalf("CreateMonth (activation): %v", err)
 		}
 
-		if got := repo.Months["2026-02"].AllowanceAdded; got != 100 {
+		if got := repo.Months["2026-02"].AllowanceAdded; got != model.Dollars(100) {
 			t.Errorf("Feb allowance = %v, want 100", got)
 		}
-		if got := repo.Months["2026-03"].StartingBalance; got != 180 {
+		if got := repo.Months["2026-03"].StartingBalance; got != model.Dollars(180) {
 			t.Errorf("Mar starting = %v, want 180 (carry shifted by +100)", got)
 		}
 		assertLedgerConsistent(t, repo, "2026-01", "2026-02", "2026-03")
@@ -312,7 +366,7 @@ func TestDeleteMonth_PropagatesCarryToLaterMonths(t *testing.T) {
 	testutil.SeedMonth(repo, "2026-01", 0, 100, 0, 100)
 	testutil.SeedMonth(repo, "2026-02", 100, 100, 0, 200)
 	testutil.SeedMonth(repo, "2026-03", 200, 100, 0, 300)
-	repo.Balance = &model.Balance{TotalBalance: 300}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(300)}
 
 	// Removing February takes its $100 allowance out of the ledger, so March
 	// must fall back to carrying from January.
@@ -323,10 +377,10 @@ func TestDeleteMonth_PropagatesCarryToLaterMonths(t *testing.T) {
 	if repo.Months["2026-02"] != nil {
 		t.Fatal("Feb still present after delete")
 	}
-	if got := repo.Months["2026-03"].StartingBalance; got != 100 {
+	if got := repo.Months["2026-03"].StartingBalance; got != model.Dollars(100) {
 		t.Errorf("Mar starting = %v, want 100 (should carry from Jan now)", got)
 	}
-	if got := repo.Months["2026-03"].EndingBalance; got != 200 {
+	if got := repo.Months["2026-03"].EndingBalance; got != model.Dollars(200) {
 		t.Errorf("Mar ending = %v, want 200", got)
 	}
 	assertLedgerConsistent(t, repo, "2026-01", "2026-03")
@@ -342,13 +396,13 @@ func TestMonthMutations_NoPropagationWhenCarryDisabled(t *testing.T) {
 		svc, repo := newExpenseService(t, true, false, 100)
 		testutil.SeedMonth(repo, "2026-01", 0, 100, 0, 100)
 		testutil.SeedMonth(repo, "2026-02", 0, 100, 0, 100)
-		repo.Balance = &model.Balance{TotalBalance: 200}
+		repo.Balance = &model.Balance{TotalBalance: model.Dollars(200)}
 		return svc, repo
 	}
 
 	t.Run("AddFunds", func(t *testing.T) {
 		svc, repo := newSvc(t)
-		if _, err := svc.AddFunds(ctx, "2026-01", 50); err != nil {
+		if _, err := svc.AddFunds(ctx, "2026-01", model.Dollars(50), ""); err != nil {
 			t.Fatalf("AddFunds: %v", err)
 		}
 		if got := repo.Months["2026-02"].StartingBalance; got != 0 {
@@ -380,7 +434,7 @@ func TestListMonths_ReturnsTotalExpenses(t *testing.T) {
 	svc, repo := newExpenseService(t, true, true, 100)
 	testutil.SeedMonth(repo, "2026-01", 0, 100, 30, 70)
 	testutil.SeedMonth(repo, "2026-02", 70, 100, 55.5, 114.5)
-	repo.Balance = &model.Balance{TotalBalance: 114.5}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(114.5)}
 
 	resp, err := svc.ListMonths(ctx, 50, "")
 	if err != nil {
@@ -390,14 +444,14 @@ func TestListMonths_ReturnsTotalExpenses(t *testing.T) {
 		t.Fatalf("months = %d, want 2", len(resp.Months))
 	}
 
-	got := map[string]float64{}
+	got := map[string]model.Money{}
 	for _, m := range resp.Months {
 		got[m.Month] = m.TotalExpenses
 	}
-	if got["2026-01"] != 30 {
+	if got["2026-01"] != model.Dollars(30) {
 		t.Errorf("2026-01 total_expenses = %v, want 30", got["2026-01"])
 	}
-	if got["2026-02"] != 55.5 {
+	if got["2026-02"] != model.Dollars(55.5) {
 		t.Errorf("2026-02 total_expenses = %v, want 55.5", got["2026-02"])
 	}
 }
@@ -421,7 +475,7 @@ func TestEnsureMonthListComplete_BackfillsProductionShape(t *testing.T) {
 	testutil.SeedLegacyMonth(repo, "2025-12", 0, 100, 52, 48)
 	testutil.SeedMonth(repo, "2026-01", 48, 100, 46, 102)
 	testutil.SeedMonth(repo, "2026-02", 102, 100, 0, 202)
-	repo.Balance = &model.Balance{TotalBalance: 202}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(202)}
 
 	if _, ok := repo.MonthList["2025-12"]; ok {
 		t.Fatal("fixture is wrong: 2025-12 should start unmirrored")
@@ -429,7 +483,7 @@ func TestEnsureMonthListComplete_BackfillsProductionShape(t *testing.T) {
 
 	// An ordinary expense in the LATEST month — nothing to propagate to.
 	if _, err := svc.AddExpense(ctx, &model.AddExpenseRequest{
-		Amount: 5, Description: "snack", Month: "2026-02",
+		Amount: model.Dollars(5), Description: "snack", Month: "2026-02",
 	}); err != nil {
 		t.Fatalf("AddExpense: %v", err)
 	}
@@ -441,11 +495,11 @@ func TestEnsureMonthListComplete_BackfillsProductionShape(t *testing.T) {
 	}
 	// The back-fill must copy the canonical values, not fabricate zeroes: the
 	// months list reads monthly_saved from the mirror.
-	if mirror.AllowanceAdded != 100 || mirror.TotalExpenses != 52 {
+	if mirror.AllowanceAdded != model.Dollars(100) || mirror.TotalExpenses != model.Dollars(52) {
 		t.Errorf("mirror = allowance %v / expenses %v, want 100 / 52",
 			mirror.AllowanceAdded, mirror.TotalExpenses)
 	}
-	if mirror.StartingBalance != 0 || mirror.EndingBalance != 48 {
+	if mirror.StartingBalance != 0 || mirror.EndingBalance != model.Dollars(48) {
 		t.Errorf("mirror = start %v / end %v, want 0 / 48",
 			mirror.StartingBalance, mirror.EndingBalance)
 	}
@@ -32,7 +32,7 @@ import (
 func seedChainExpense(repo *testutil.FakeRepo, month string, amount float64, desc string, day time.Time) string {
 	sk := "EXP#1#chain"
 	repo.Expenses[testutil.ExpenseKey(month, sk)] = &model.Expense{
-		SK: sk, Amount: amount, Description: desc, CreatedAt: day,
+		SK: sk, Amount: model.Dollars(amount), Description: desc, CreatedAt: day,
 	}
 	return sk
 }
@@ -46,8 +46,8 @@ func day(t *testing.T, date string) time.Time {
 	return d.Add(12 * time.Hour)
 }
 
-func amt(v float64) *float64 { return &v }
-func desc(v string) *string  { return &v }
+func amt(v float64) *model.Money { m := model.Dollars(v); return &m }
+func desc(v string) *string      { return &v }
 
 // A back-dated expense the earlier month can afford, but which the carry chain
 // cannot: the balance has to stay non-negative for EVERY month, not just the one
@@ -59,12 +59,12 @@ func TestAddExpense_BackDatedExpenseCannotDriveALaterMonthNegative(t *testing.T)
 	// January is flush; February has already spent nearly everything it carried.
 	testutil.SeedMonth(repo, "2026-01", 0, 100, 0, 100)
 	testutil.SeedMonth(repo, "2026-02", 100, 100, 190, 10)
-	repo.Balance = &model.Balance{TotalBalance: 10}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(10)}
 
 	// 50 <= January's ending balance of 100, so the per-month condition passes.
 	// But only 10 is actually spendable: February would land at -40.
 	_, err := svc.AddExpense(ctx, &model.AddExpenseRequest{
-		Amount: 50, Description: "back-dated book", Month: "2026-01", Date: "2026-01-15",
+		Amount: model.Dollars(50), Description: "back-dated book", Month: "2026-01", Date: "2026-01-15",
 	})
 	if !errors.Is(err, ErrInsufficientFunds) {
 		t.Fatalf("err = %v, want ErrInsufficientFunds", err)
@@ -73,15 +73,15 @@ func TestAddExpense_BackDatedExpenseCannotDriveALaterMonthNegative(t *testing.T)
 	// Nothing may have been written — in particular February must not be
 	// negative on an instance that disallows overspending.
 	if feb := repo.Months["2026-02"]; feb.EndingBalance < 0 {
-		t.Errorf("February ending_balance = %.2f: a back-dated expense drove a later "+
+		t.Errorf("February ending_balance = %v: a back-dated expense drove a later "+
 			"month negative despite allow_overspending=false", feb.EndingBalance)
 	}
 	if jan := repo.Months["2026-01"]; jan.TotalExpenses != 0 {
-		t.Errorf("January total_expenses = %.2f, want 0 — the refused expense was "+
+		t.Errorf("January total_expenses = %v, want 0 — the refused expense was "+
 			"still written", jan.TotalExpenses)
 	}
 	if repo.Balance.TotalBalance < 0 {
-		t.Errorf("global balance = %.2f, want >= 0", repo.Balance.TotalBalance)
+		t.Errorf("global balance = %v, want >= 0", repo.Balance.TotalBalance)
 	}
 }
 
@@ -93,17 +93,17 @@ func TestAddExpense_ReportsTheChainsAvailableBalanceNotTheMonths(t *testing.T) {
 	svc, repo := newExpenseService(t, false, true, 100)
 	testutil.SeedMonth(repo, "2026-01", 0, 100, 0, 100)
 	testutil.SeedMonth(repo, "2026-02", 100, 100, 190, 10)
-	repo.Balance = &model.Balance{TotalBalance: 10}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(10)}
 
 	_, err := svc.AddExpense(ctx, &model.AddExpenseRequest{
-		Amount: 50, Description: "book", Month: "2026-01", Date: "2026-01-15",
+		Amount: model.Dollars(50), Description: "book", Month: "2026-01", Date: "2026-01-15",
 	})
 	var rich *InsufficientFundsError
 	if !errors.As(err, &rich) {
 		t.Fatalf("err = %v, want an *InsufficientFundsError carrying the available amount", err)
 	}
-	if rich.Available != 10 {
-		t.Errorf("Available = %.2f, want 10 (February's headroom, the tightest in the "+
+	if rich.Available != model.Dollars(10) {
+		t.Errorf("Available = %v, want 10 (February's headroom, the tightest in the "+
 			"chain) — reporting January's 100 invites the user to retry an amount that "+
 			"cannot work", rich.Available)
 	}
@@ -116,15 +116,15 @@ func TestAddExpense_BackDatedExpenseAtTheChainLimitIsAllowed(t *testing.T) {
 	svc, repo := newExpenseService(t, false, true, 100)
 	testutil.SeedMonth(repo, "2026-01", 0, 100, 0, 100)
 	testutil.SeedMonth(repo, "2026-02", 100, 100, 190, 10)
-	repo.Balance = &model.Balance{TotalBalance: 10}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(10)}
 
 	if _, err := svc.AddExpense(ctx, &model.AddExpenseRequest{
-		Amount: 10, Description: "exactly affordable", Month: "2026-01", Date: "2026-01-15",
+		Amount: model.Dollars(10), Description: "exactly affordable", Month: "2026-01", Date: "2026-01-15",
 	}); err != nil {
 		t.Fatalf("an expense exactly equal to the chain headroom was refused: %v", err)
 	}
 	if got := repo.Months["2026-02"].EndingBalance; got != 0 {
-		t.Errorf("February ending_balance = %.2f, want 0", got)
+		t.Errorf("February ending_balance = %v, want 0", got)
 	}
 }
 
@@ -135,15 +135,15 @@ func TestAddExpense_ChainGuardDoesNotApplyWhenOverspendingIsAllowed(t *testing.T
 	svc, repo := newExpenseService(t, true, true, 100)
 	testutil.SeedMonth(repo, "2026-01", 0, 100, 0, 100)
 	testutil.SeedMonth(repo, "2026-02", 100, 100, 190, 10)
-	repo.Balance = &model.Balance{TotalBalance: 10}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(10)}
 
 	if _, err := svc.AddExpense(ctx, &model.AddExpenseRequest{
-		Amount: 50, Description: "over", Month: "2026-01", Date: "2026-01-15",
+		Amount: model.Dollars(50), Description: "over", Month: "2026-01", Date: "2026-01-15",
 	}); err != nil {
 		t.Fatalf("AddExpense refused on an overspending-allowed instance: %v", err)
 	}
-	if got := repo.Months["2026-02"].EndingBalance; got != -40 {
-		t.Errorf("February ending_balance = %.2f, want -40 (carry still propagates)", got)
+	if got := repo.Months["2026-02"].EndingBalance; got != model.Dollars(-40) {
+		t.Errorf("February ending_balance = %v, want -40 (carry still propagates)", got)
 	}
 }
 
@@ -154,15 +154,15 @@ func TestAddExpense_ChainGuardDoesNotApplyWhenCarryIsOff(t *testing.T) {
 	svc, repo := newExpenseService(t, false, false, 100)
 	testutil.SeedMonth(repo, "2026-01", 0, 100, 0, 100)
 	testutil.SeedMonth(repo, "2026-02", 0, 100, 95, 5)
-	repo.Balance = &model.Balance{TotalBalance: 105}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(105)}
 
 	if _, err := svc.AddExpense(ctx, &model.AddExpenseRequest{
-		Amount: 50, Description: "book", Month: "2026-01", Date: "2026-01-15",
+		Amount: model.Dollars(50), Description: "book", Month: "2026-01", Date: "2026-01-15",
 	}); err != nil {
 		t.Fatalf("AddExpense refused although carry is off and January can afford it: %v", err)
 	}
-	if got := repo.Months["2026-02"].EndingBalance; got != 5 {
-		t.Errorf("February ending_balance = %.2f, want 5 (untouched with carry off)", got)
+	if got := repo.Months["2026-02"].EndingBalance; got != model.Dollars(5) {
+		t.Errorf("February ending_balance = %v, want 5 (untouched with carry off)", got)
 	}
 }
 
@@ -173,7 +173,7 @@ func TestUpdateExpense_RaisingAPastAmountCannotDriveALaterMonthNegative(t *testi
 	svc, repo := newExpenseService(t, false, true, 100)
 	testutil.SeedMonth(repo, "2026-01", 0, 100, 20, 80)
 	testutil.SeedMonth(repo, "2026-02", 80, 100, 175, 5)
-	repo.Balance = &model.Balance{TotalBalance: 5}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(5)}
 	sk := seedChainExpense(repo, "2026-01", 20, "book", day(t, "2026-01-10"))
 
 	// 20 -> 60 is a delta of 40. January can absorb it (80 headroom); the chain
@@ -184,7 +184,7 @@ func TestUpdateExpense_RaisingAPastAmountCannotDriveALaterMonthNegative(t *testi
 		t.Fatalf("err = %v, want ErrInsufficientFunds", err)
 	}
 	if feb := repo.Months["2026-02"]; feb.EndingBalance < 0 {
-		t.Errorf("February ending_balance = %.2f, want >= 0", feb.EndingBalance)
+		t.Errorf("February ending_balance = %v, want >= 0", feb.EndingBalance)
 	}
 }
 
@@ -213,12 +213,12 @@ func TestUpdateExpense_MoveToALaterMonthIsNotRefusedWhenTheRefundCoversIt(t *tes
 			"so this is affordable", err)
 	}
 
-	if jan := repo.Months["2026-01"]; jan.TotalExpenses != 0 || jan.EndingBalance != 100 {
-		t.Errorf("January = expenses %.2f / ending %.2f, want 0 / 100",
+	if jan := repo.Months["2026-01"]; jan.TotalExpenses != 0 || jan.EndingBalance != model.Dollars(100) {
+		t.Errorf("January = expenses %v / ending %v, want 0 / 100",
 			jan.TotalExpenses, jan.EndingBalance)
 	}
-	if feb := repo.Months["2026-02"]; feb.TotalExpenses != 100 || feb.EndingBalance != 0 {
-		t.Errorf("February = expenses %.2f / ending %.2f, want 100 / 0",
+	if feb := repo.Months["2026-02"]; feb.TotalExpenses != model.Dollars(100) || feb.EndingBalance != 0 {
+		t.Errorf("February = expenses %v / ending %v, want 100 / 0",
 			feb.TotalExpenses, feb.EndingBalance)
 	}
 }
@@ -230,7 +230,7 @@ func TestUpdateExpense_MoveToALaterMonthStillRefusedWhenTrulyUnaffordable(t *tes
 	svc, repo := newExpenseService(t, false, true, 100)
 	testutil.SeedMonth(repo, "2026-01", 0, 100, 10, 90)
 	testutil.SeedMonth(repo, "2026-02", 90, 0, 0, 90)
-	repo.Balance = &model.Balance{TotalBalance: 90}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(90)}
 	sk := seedChainExpense(repo, "2026-01", 10, "snack", day(t, "2026-01-10"))
 
 	// Raising 10 -> 500 while moving to February. The refund is only 10, so the
@@ -241,7 +241,7 @@ func TestUpdateExpense_MoveToALaterMonthStillRefusedWhenTrulyUnaffordable(t *tes
 		t.Fatalf("err = %v, want ErrInsufficientFunds", err)
 	}
 	if feb := repo.Months["2026-02"]; feb.EndingBalance < 0 {
-		t.Errorf("February ending_balance = %.2f, want >= 0", feb.EndingBalance)
+		t.Errorf("February ending_balance = %v, want >= 0", feb.EndingBalance)
 	}
 }
 
@@ -253,7 +253,7 @@ func TestUpdateExpense_MoveToAnEarlierMonthStillChecksTheDestination(t *testing.
 	svc, repo := newExpenseService(t, false, true, 100)
 	testutil.SeedMonth(repo, "2026-01", 0, 20, 15, 5)
 	testutil.SeedMonth(repo, "2026-02", 5, 100, 50, 55)
-	repo.Balance = &model.Balance{TotalBalance: 55}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(55)}
 	sk := seedChainExpense(repo, "2026-02", 50, "shoes", day(t, "2026-02-10"))
 
 	// January only has 5 of headroom; a 50 expense moved back into it would take
@@ -264,6 +264,6 @@ func TestUpdateExpense_MoveToAnEarlierMonthStillChecksTheDestination(t *testing.
 		t.Fatalf("err = %v, want ErrInsufficientFunds", err)
 	}
 	if jan := repo.Months["2026-01"]; jan.EndingBalance < 0 {
-		t.Errorf("January ending_balance = %.2f, want >= 0", jan.EndingBalance)
+		t.Errorf("January ending_balance = %v, want >= 0", jan.EndingBalance)
 	}
 }
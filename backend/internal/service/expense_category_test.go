@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/testutil"
+)
+
+// =====================================================================
+// Expense categories — the per-category totals ride inside the same
+// transactions as total_expenses, on the summary AND its mirror, so every
+// add/edit/move/delete has to leave them agreeing with the expense rows.
+// =====================================================================
+
+func addCategorized(t *testing.T, svc *ExpenseService, amount float64, category, date string) *model.Expense {
+	t.Helper()
+	resp, err := svc.AddExpense(context.Background(), &model.AddExpenseRequest{
+		Amount:      model.Dollars(amount),
+		Description: "item",
+		Category:    category,
+		Date:        date,
+	})
+	if err != nil {
+		t.Fatalf("AddExpense(%s %v): %v", category, amount, err)
+	}
+	return resp.Expense
+}
+
+// assertCategoryTotal checks one category on both the canonical row and the
+// mirror — the two are separate items in DynamoDB and must not drift.
+func assertCategoryTotal(t *testing.T, repo *testutil.FakeRepo, month, category string, want float64) {
+	t.Helper()
+	if got := repo.Months[month].CategoryTotals[category]; got != model.Dollars(want) {
+		t.Errorf("%s canonical %q total = %v, want %v", month, category, got, want)
+	}
+	if got := repo.MonthList[month].CategoryTotals[category]; got != model.Dollars(want) {
+		t.Errorf("%s mirror %q total = %v, want %v", month, category, got, want)
+	}
+}
+
+func TestAddExpense_CategoryIsNormalizedAndTotalled(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	month, date := pastMonthDay(1, 10)
+	testutil.SeedMonth(repo, month, 0, 100, 0, 100)
+
+	e := addCategorized(t, svc, 12.5, "  Snacks ", date)
+	if e.Category != "snacks" {
+		t.Errorf("stored category = %q, want %q", e.Category, "snacks")
+	}
+	addCategorized(t, svc, 7.5, "snacks", date)
+	addCategorized(t, svc, 5, "", date)
+
+	assertCategoryTotal(t, repo, month, "snacks", 20)
+	if _, ok := repo.Months[month].CategoryTotals[""]; ok {
+		t.Error("uncategorized spend must not get a category_totals entry")
+	}
+}
+
+// Naming the breakdown's own "uncategorized" label is the same as naming no
+// category — otherwise the remainder and a real entry would share one name.
+func TestAddExpense_UncategorizedLabelIsNotStored(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	month, date := pastMonthDay(1, 10)
+	testutil.SeedMonth(repo, month, 0, 100, 0, 100)
+
+	e := addCategorized(t, svc, 3, "Uncategorized", date)
+	if e.Category != "" {
+		t.Errorf("category = %q, want empty", e.Category)
+	}
+	if len(repo.Months[month].CategoryTotals) != 0 {
+		t.Errorf("category totals = %v, want none", repo.Months[month].CategoryTotals)
+	}
+}
+
+func TestAddExpense_CategoryTooLong(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	month, date := pastMonthDay(1, 10)
+	testutil.SeedMonth(repo, month, 0, 100, 0, 100)
+
+	_, err := svc.AddExpense(context.Background(), &model.AddExpenseRequest{
+		Amount: model.Dollars(1), Category: strings.Repeat("x", 31), Date: date,
+	})
+	if !errors.Is(err, ErrCategoryTooLong) {
+		t.Fatalf("err = %v, want ErrCategoryTooLong", err)
+	}
+	// Measured in characters, like descriptions.
+	if _, err := svc.AddExpense(context.Background(), &model.AddExpenseRequest{
+		Amount: model.Dollars(1), Category: strings.Repeat("é", 30), Date: date,
+	}); err != nil {
+		t.Fatalf("30-character category rejected: %v", err)
+	}
+}
+
+// A category-only edit leaves the amount alone but still has to move the
+// money between totals, so it cannot take the description-only shortcut.
+func TestUpdateExpense_RecategorizeMovesTheTotal(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	month, date := pastMonthDay(1, 10)
+	testutil.SeedMonth(repo, month, 0, 100, 0, 100)
+	e := addCategorized(t, svc, 12, "snacks", date)
+
+	games := "Games"
+	resp, err := svc.UpdateExpense(context.Background(), month, e.SK, &model.UpdateExpenseRequest{Category: &games})
+	if err != nil {
+		t.Fatalf("UpdateExpense: %v", err)
+	}
+	if resp.Expense.Category != "games" {
+		t.Errorf("response category = %q, want games", resp.Expense.Category)
+	}
+	assertCategoryTotal(t, repo, month, "snacks", 0)
+	assertCategoryTotal(t, repo, month, "games", 12)
+	if got := repo.Expenses[testutil.ExpenseKey(month, e.SK)].Category; got != "games" {
+		t.Errorf("stored category = %q, want games", got)
+	}
+	if repo.Months[month].TotalExpenses != model.Dollars(12) {
+		t.Errorf("total expenses = %v, want 12 (unchanged)", repo.Months[month].TotalExpenses)
+	}
+
+	// Clearing the category drops it from every total.
+	empty := ""
+	if _, err := svc.UpdateExpense(context.Background(), month, e.SK, &model.UpdateExpenseRequest{Category: &empty}); err != nil {
+		t.Fatalf("clear category: %v", err)
+	}
+	assertCategoryTotal(t, repo, month, "games", 0)
+}
+
+func TestUpdateExpense_AmountChangeKeepsCategoryTotalInStep(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	month, date := pastMonthDay(1, 10)
+	testutil.SeedMonth(repo, month, 0, 100, 0, 100)
+	e := addCategorized(t, svc, 12, "snacks", date)
+
+	amount := model.Dollars(20)
+	if _, err := svc.UpdateExpense(context.Background(), month, e.SK, &model.UpdateExpenseRequest{Amount: &amount}); err != nil {
+		t.Fatalf("UpdateExpense: %v", err)
+	}
+	assertCategoryTotal(t, repo, month, "snacks", 20)
+}
+
+// A cross-month re-date takes the old category's total out of the source and
+// puts the new one into the destination.
+func TestUpdateExpense_MoveAcrossMonthsCarriesTheCategory(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	srcMonth, srcDate := pastMonthDay(3, 10)
+	dstMonth, dstDate := pastMonthDay(2, 10)
+	testutil.SeedMonth(repo, srcMonth, 0, 100, 0, 100)
+	testutil.SeedMonth(repo, dstMonth, 0, 100, 0, 100)
+	e := addCategorized(t, svc, 15, "snacks", srcDate)
+
+	if _, err := svc.UpdateExpense(context.Background(), srcMonth, e.SK, &model.UpdateExpenseRequest{Date: dstDate}); err != nil {
+		t.Fatalf("UpdateExpense: %v", err)
+	}
+	assertCategoryTotal(t, repo, srcMonth, "snacks", 0)
+	assertCategoryTotal(t, repo, dstMonth, "snacks", 15)
+}
+
+func TestDeleteExpense_RefundsTheCategoryTotal(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	month, date := pastMonthDay(1, 10)
+	testutil.SeedMonth(repo, month, 0, 100, 0, 100)
+	e := addCategorized(t, svc, 9, "snacks", date)
+	addCategorized(t, svc, 4, "snacks", date)
+
+	if err := svc.DeleteExpense(context.Background(), month, e.SK); err != nil {
+		t.Fatalf("DeleteExpense: %v", err)
+	}
+	assertCategoryTotal(t, repo, month, "snacks", 4)
+}
+
+// Months written before categories existed carry no category_totals map; the
+// service seeds one before the first categorized write instead of letting the
+// nested update cancel the transaction.
+func TestAddExpense_CategoryOnLegacyMonthWithoutTotals(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	month, date := pastMonthDay(1, 10)
+	testutil.SeedLegacyMonth(repo, month, 0, 100, 0, 100)
+
+	addCategorized(t, svc, 6, "snacks", date)
+	assertCategoryTotal(t, repo, month, "snacks", 6)
+}
+
+func TestGetMonthData_CategoryBreakdown(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	month, date := pastMonthDay(1, 10)
+	// 10 of pre-existing, uncategorized spend.
+	testutil.SeedMonth(repo, month, 0, 100, 10, 90)
+	addCategorized(t, svc, 5, "games", date)
+	addCategorized(t, svc, 25, "snacks", date)
+	addCategorized(t, svc, 5, "books", date)
+	zeroed := addCategorized(t, svc, 2, "toys", date)
+	if err := svc.DeleteExpense(context.Background(), month, zeroed.SK); err != nil {
+		t.Fatalf("DeleteExpense: %v", err)
+	}
+
+	data, err := svc.GetMonthData(context.Background(), month, 50, "")
+	if err != nil {
+		t.Fatalf("GetMonthData: %v", err)
+	}
+	want := []model.CategoryTotal{
+		{Category: "snacks", Total: model.Dollars(25)},
+		{Category: UncategorizedCategory, Total: model.Dollars(10)},
+		{Category: "books", Total: model.Dollars(5)},
+		{Category: "games", Total: model.Dollars(5)},
+	}
+	if len(data.Categories) != len(want) {
+		t.Fatalf("categories = %+v, want %+v", data.Categories, want)
+	}
+	for i := range want {
+		if data.Categories[i] != want[i] {
+			t.Errorf("categories[%d] = %+v, want %+v", i, data.Categories[i], want[i])
+		}
+	}
+	for _, item := range data.Expenses {
+		if item.Category == "" {
+			t.Errorf("expense %s lost its category in the month listing", item.ID)
+		}
+	}
+}
@@ -26,7 +26,7 @@ func TestAddExpense_PastDateLandsInRightMonth(t *testing.T) {
 	dateStr := fmt.Sprintf("%04d-%02d-%02d", pastDay.Year(), pastDay.Month(), pastDay.Day())
 
 	resp, err := svc.AddExpense(ctx, &model.AddExpenseRequest{
-		Amount:      20,
+		Amount:      model.Dollars(20),
 		Description: "back-dated book",
 		Date:        dateStr,
 	})
@@ -67,11 +67,11 @@ func TestAddExpense_PastDateSKOrdering(t *testing.T) {
 	day2 := fmt.Sprintf("%04d-%02d-02", base.Year(), base.Month())
 	day20 := fmt.Sprintf("%04d-%02d-20", base.Year(), base.Month())
 
-	earlier, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: 5, Description: "a", Date: day2})
+	earlier, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(5), Description: "a", Date: day2})
 	if err != nil {
 		t.Fatalf("earlier add: %v", err)
 	}
-	later, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: 5, Description: "b", Date: day20})
+	later, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(5), Description: "b", Date: day20})
 	if err != nil {
 		t.Fatalf("later add: %v", err)
 	}
@@ -96,7 +96,7 @@ func TestAddExpense_TodayUsesCurrentTime(t *testing.T) {
 	noon := time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, time.UTC)
 
 	before := time.Now()
-	resp, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: 5, Description: "today", Date: today})
+	resp, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(5), Description: "today", Date: today})
 	after := time.Now()
 	if err != nil {
 		t.Fatalf("AddExpense today: %v", err)
@@ -123,7 +123,7 @@ func TestAddExpense_FutureDateRejected(t *testing.T) {
 	future := time.Now().UTC().AddDate(0, 0, 2)
 	dateStr := fmt.Sprintf("%04d-%02d-%02d", future.Year(), future.Month(), future.Day())
 
-	_, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: 5, Description: "future", Date: dateStr})
+	_, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(5), Description: "future", Date: dateStr})
 	if !errors.Is(err, ErrFutureDate) {
 		t.Fatalf("future date = %v, want ErrFutureDate", err)
 	}
@@ -140,7 +140,7 @@ func TestAddExpense_DateMonthMismatchRejected(t *testing.T) {
 	svc, _ := newExpenseService(t, true, false, 0)
 
 	_, err := svc.AddExpense(ctx, &model.AddExpenseRequest{
-		Amount:      5,
+		Amount:      model.Dollars(5),
 		Description: "mismatch",
 		Month:       "2026-04",
 		Date:        "2026-03-15",
@@ -158,7 +158,7 @@ func TestAddExpense_DateMatchingMonthAccepted(t *testing.T) {
 	svc, repo := newExpenseService(t, true, false, 0)
 
 	_, err := svc.AddExpense(ctx, &model.AddExpenseRequest{
-		Amount:      5,
+		Amount:      model.Dollars(5),
 		Description: "match",
 		Month:       "2026-03",
 		Date:        "2026-03-15",
@@ -178,7 +178,7 @@ func TestAddExpense_InvalidDateRejected(t *testing.T) {
 	svc, _ := newExpenseService(t, true, false, 0)
 
 	for _, bad := range []string{"2026-13-01", "2026-02-30", "03/15/2026", "2026-3-5", "not-a-date"} {
-		_, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: 5, Description: "x", Date: bad})
+		_, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(5), Description: "x", Date: bad})
 		if !errors.Is(err, ErrInvalidDate) {
 			t.Errorf("date %q = %v, want ErrInvalidDate", bad, err)
 		}
@@ -193,7 +193,7 @@ func TestAddExpense_AbsentDateUnchanged(t *testing.T) {
 	svc, repo := newExpenseService(t, true, false, 0)
 
 	before := time.Now()
-	resp, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: 5, Description: "x", Month: "2026-05"})
+	resp, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(5), Description: "x", Month: "2026-05"})
 	after := time.Now()
 	if err != nil {
 		t.Fatalf("AddExpense (no date): %v", err)
@@ -18,7 +18,7 @@ import (
 func seedExpenseOnDate(t *testing.T, svc *ExpenseService, amount float64, desc, date string) string {
 	t.Helper()
 	resp, err := svc.AddExpense(context.Background(), &model.AddExpenseRequest{
-		Amount:      amount,
+		Amount:      model.Dollars(amount),
 		Description: desc,
 		Date:        date,
 	})
@@ -106,17 +106,17 @@ func TestUpdateExpense_SameMonthDateChange(t *testing.T) {
 	}
 
 	// Summaries + balance unchanged (amount didn't move).
-	if !testutil.AlmostEqual(repo.Months[month].TotalExpenses, beforeExp) {
+	if repo.Months[month].TotalExpenses != beforeExp {
 		t.Errorf("total_expenses changed: %v -> %v", beforeExp, repo.Months[month].TotalExpenses)
 	}
-	if !testutil.AlmostEqual(repo.Months[month].EndingBalance, beforeEnd) {
+	if repo.Months[month].EndingBalance != beforeEnd {
 		t.Errorf("ending_balance changed: %v -> %v", beforeEnd, repo.Months[month].EndingBalance)
 	}
-	if !testutil.AlmostEqual(repo.Balance.TotalBalance, beforeBal) {
+	if repo.Balance.TotalBalance != beforeBal {
 		t.Errorf("total_balance changed: %v -> %v", beforeBal, repo.Balance.TotalBalance)
 	}
 	// Mirror stays in sync with the canonical summary.
-	if !testutil.AlmostEqual(repo.MonthList[month].TotalExpenses, repo.Months[month].TotalExpenses) {
+	if repo.MonthList[month].TotalExpenses != repo.Months[month].TotalExpenses {
 		t.Errorf("mirror total_expenses drifted: %v vs %v", repo.MonthList[month].TotalExpenses, repo.Months[month].TotalExpenses)
 	}
 
@@ -157,19 +157,19 @@ func TestUpdateExpense_SameMonthDatePlusAmount(t *testing.T) {
 
 	beforeBal := repo.Balance.TotalBalance // -30
 
-	newAmt := 50.0
+	newAmt := model.Dollars(50)
 	resp, err := svc.UpdateExpense(ctx, month, oldSK, &model.UpdateExpenseRequest{Date: day10, Amount: &newAmt})
 	if err != nil {
 		t.Fatalf("same-month date+amount: %v", err)
 	}
-	if !testutil.AlmostEqual(repo.Months[month].TotalExpenses, 50) {
+	if repo.Months[month].TotalExpenses != model.Dollars(50) {
 		t.Errorf("total_expenses = %v, want 50", repo.Months[month].TotalExpenses)
 	}
 	// Balance dropped by the +20 amount delta.
-	if !testutil.AlmostEqual(repo.Balance.TotalBalance, beforeBal-20) {
-		t.Errorf("total_balance = %v, want %v", repo.Balance.TotalBalance, beforeBal-20)
+	if repo.Balance.TotalBalance != beforeBal-model.Dollars(20) {
+		t.Errorf("total_balance = %v, want %v", repo.Balance.TotalBalance, beforeBal-model.Dollars(20))
 	}
-	if !testutil.AlmostEqual(resp.Expense.Amount, 50) {
+	if resp.Expense.Amount != model.Dollars(50) {
 		t.Errorf("response amount = %v, want 50", resp.Expense.Amount)
 	}
 }
@@ -193,21 +193,21 @@ func TestUpdateExpense_CrossMonthMoveEqualAmount(t *testing.T) {
 	}
 
 	// Source refunded to zero spend; destination charged 40.
-	if !testutil.AlmostEqual(repo.Months[srcMonth].TotalExpenses, 0) {
+	if repo.Months[srcMonth].TotalExpenses != model.Dollars(0) {
 		t.Errorf("source total_expenses = %v, want 0", repo.Months[srcMonth].TotalExpenses)
 	}
-	if !testutil.AlmostEqual(repo.Months[dstMonth].TotalExpenses, 40) {
+	if repo.Months[dstMonth].TotalExpenses != model.Dollars(40) {
 		t.Errorf("dest total_expenses = %v, want 40", repo.Months[dstMonth].TotalExpenses)
 	}
 	// Mirrors track their canonical rows.
-	if !testutil.AlmostEqual(repo.MonthList[srcMonth].TotalExpenses, 0) {
+	if repo.MonthList[srcMonth].TotalExpenses != model.Dollars(0) {
 		t.Errorf("source mirror total_expenses = %v, want 0", repo.MonthList[srcMonth].TotalExpenses)
 	}
-	if !testutil.AlmostEqual(repo.MonthList[dstMonth].TotalExpenses, 40) {
+	if repo.MonthList[dstMonth].TotalExpenses != model.Dollars(40) {
 		t.Errorf("dest mirror total_expenses = %v, want 40", repo.MonthList[dstMonth].TotalExpenses)
 	}
 	// Global balance unchanged for an equal-amount move.
-	if !testutil.AlmostEqual(repo.Balance.TotalBalance, beforeBal) {
+	if repo.Balance.TotalBalance != beforeBal {
 		t.Errorf("total_balance = %v, want %v (unchanged)", repo.Balance.TotalBalance, beforeBal)
 	}
 	// Expense row physically moved partitions.
@@ -235,20 +235,20 @@ func TestUpdateExpense_CrossMonthMovePlusAmount(t *testing.T) {
 
 	beforeBal := repo.Balance.TotalBalance // -40
 
-	newAmt := 25.0
+	newAmt := model.Dollars(25)
 	_, err := svc.UpdateExpense(ctx, srcMonth, oldSK, &model.UpdateExpenseRequest{Date: dstDay, Amount: &newAmt})
 	if err != nil {
 		t.Fatalf("cross-month move + amount: %v", err)
 	}
-	if !testutil.AlmostEqual(repo.Months[srcMonth].TotalExpenses, 0) {
+	if repo.Months[srcMonth].TotalExpenses != model.Dollars(0) {
 		t.Errorf("source total_expenses = %v, want 0", repo.Months[srcMonth].TotalExpenses)
 	}
-	if !testutil.AlmostEqual(repo.Months[dstMonth].TotalExpenses, 25) {
+	if repo.Months[dstMonth].TotalExpenses != model.Dollars(25) {
 		t.Errorf("dest total_expenses = %v, want 25", repo.Months[dstMonth].TotalExpenses)
 	}
 	// Balance shifts by (oldAmount - newAmount) = (40 - 25) = +15.
-	if !testutil.AlmostEqual(repo.Balance.TotalBalance, beforeBal+15) {
-		t.Errorf("total_balance = %v, want %v", repo.Balance.TotalBalance, beforeBal+15)
+	if repo.Balance.TotalBalance != beforeBal+model.Dollars(15) {
+		t.Errorf("total_balance = %v, want %v", repo.Balance.TotalBalance, beforeBal+model.Dollars(15))
 	}
 }
 
@@ -264,12 +264,12 @@ func TestUpdateExpense_MoveInsufficientFundsRejected(t *testing.T) {
 
 	// Source has $100 allowance, one $40 expense → $60 left.
 	testutil.SeedMonth(repo, srcMonth, 0, 100, 0, 100)
-	repo.Balance.TotalBalance = 100
+	repo.Balance.TotalBalance = model.Dollars(100)
 	oldSK := seedExpenseOnDate(t, svc, 40, "books", srcDay)
 
 	// Destination exists but is broke: $10 allowance, nothing spent.
 	testutil.SeedMonth(repo, dstMonth, 0, 10, 0, 10)
-	repo.Balance.TotalBalance += 10
+	repo.Balance.TotalBalance += model.Dollars(10)
 
 	balBefore := repo.Balance.TotalBalance
 	srcSpendBefore := repo.Months[srcMonth].TotalExpenses
@@ -285,15 +285,15 @@ func TestUpdateExpense_MoveInsufficientFundsRejected(t *testing.T) {
 	if _, ok := repo.Expenses[testutil.ExpenseKey(dstMonth, oldSK)]; ok {
 		t.Error("expense leaked into destination despite rejected move")
 	}
-	if !testutil.AlmostEqual(repo.Months[srcMonth].TotalExpenses, srcSpendBefore) {
+	if repo.Months[srcMonth].TotalExpenses != srcSpendBefore {
 		t.Errorf("source spend mutated on rejected move: %v -> %v", srcSpendBefore, repo.Months[srcMonth].TotalExpenses)
 	}
-	if !testutil.AlmostEqual(repo.Balance.TotalBalance, balBefore) {
+	if repo.Balance.TotalBalance != balBefore {
 		t.Errorf("balance mutated on rejected move: %v -> %v", balBefore, repo.Balance.TotalBalance)
 	}
 	// And the InsufficientFundsError names the destination's available balance.
 	var insufficient *InsufficientFundsError
-	if errors.As(err, &insufficient) && !testutil.AlmostEqual(insufficient.Available, 10) {
+	if errors.As(err, &insufficient) && insufficient.Available != model.Dollars(10) {
 		t.Errorf("available = %v, want destination's 10", insufficient.Available)
 	}
 }
@@ -357,7 +357,7 @@ func TestUpdateExpense_CarryPropagatesBothMonths(t *testing.T) {
 	testutil.SeedMonth(repo, srcMonth, 0, 200, 0, 200)
 	testutil.SeedMonth(repo, dstMonth, 200, 0, 0, 200)
 	testutil.SeedMonth(repo, laterMonth, 200, 0, 0, 200)
-	repo.Balance.TotalBalance = 200
+	repo.Balance.TotalBalance = model.Dollars(200)
 
 	oldSK := seedExpenseOnDate(t, svc, 40, "books", srcDay)
 	// Seeding the $40 expense in src lowered src ending by 40 and (carry)
@@ -375,19 +375,19 @@ func TestUpdateExpense_CarryPropagatesBothMonths(t *testing.T) {
 	// the source-refund propagation, months after src) and -40 (from the
 	// destination-charge propagation, months after dst) → net zero relative to
 	// the post-seed state. This proves BOTH propagations fired.
-	if !testutil.AlmostEqual(repo.Months[laterMonth].StartingBalance, laterStartAfterSeed) {
+	if repo.Months[laterMonth].StartingBalance != laterStartAfterSeed {
 		t.Errorf("later-month starting_balance = %v, want %v (net-zero across both propagations)",
 			repo.Months[laterMonth].StartingBalance, laterStartAfterSeed)
 	}
-	if !testutil.AlmostEqual(repo.MonthList[laterMonth].StartingBalance, laterStartAfterSeed) {
+	if repo.MonthList[laterMonth].StartingBalance != laterStartAfterSeed {
 		t.Errorf("later-month mirror starting_balance = %v, want %v", repo.MonthList[laterMonth].StartingBalance, laterStartAfterSeed)
 	}
 	// dstMonth is strictly after src (so it gets the +40 source-refund ripple)
 	// but is the charge site itself (not strictly after itself), so it does NOT
 	// get the destination-charge ripple — net +40 over its post-seed start.
-	if !testutil.AlmostEqual(repo.Months[dstMonth].StartingBalance, dstStartAfterSeed+40) {
+	if repo.Months[dstMonth].StartingBalance != dstStartAfterSeed+model.Dollars(40) {
 		t.Errorf("dest starting_balance = %v, want %v (source-refund carry ripple only)",
-			repo.Months[dstMonth].StartingBalance, dstStartAfterSeed+40)
+			repo.Months[dstMonth].StartingBalance, dstStartAfterSeed+model.Dollars(40))
 	}
 }
 
@@ -400,7 +400,7 @@ func TestUpdateExpense_AmountOnlyUnaffected(t *testing.T) {
 	month, day5 := pastMonthDay(2, 5)
 	oldSK := seedExpenseOnDate(t, svc, 12, "snack", day5)
 
-	newAmt := 20.0
+	newAmt := model.Dollars(20)
 	resp, err := svc.UpdateExpense(ctx, month, oldSK, &model.UpdateExpenseRequest{Amount: &newAmt})
 	if err != nil {
 		t.Fatalf("amount-only edit: %v", err)
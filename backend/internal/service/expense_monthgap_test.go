@@ -32,24 +32,24 @@ func TestCreateMonth_CarriesAcrossAGapInTheMonthSequence(t *testing.T) {
 
 	// January saved 250. February was never opened.
 	testutil.SeedMonth(repo, "2026-01", 0, 250, 0, 250)
-	repo.Balance = &model.Balance{TotalBalance: 250}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(250)}
 
 	resp, err := svc.CreateMonth(ctx, "2026-03")
 	if err != nil {
 		t.Fatalf("CreateMonth: %v", err)
 	}
 
-	if got := resp.Summary.StartingBalance; got != 250 {
-		t.Errorf("starting_balance = %.2f, want 250 — the balance carried by the last "+
+	if got := resp.Summary.StartingBalance; got != model.Dollars(250) {
+		t.Errorf("starting_balance = %v, want 250 — the balance carried by the last "+
 			"month before the gap was dropped", got)
 	}
-	if got := resp.Summary.EndingBalance; got != 350 {
-		t.Errorf("ending_balance = %.2f, want 350 (250 carried + 100 allowance)", got)
+	if got := resp.Summary.EndingBalance; got != model.Dollars(350) {
+		t.Errorf("ending_balance = %v, want 350 (250 carried + 100 allowance)", got)
 	}
 	// The invariant that matters: the newest month's ending balance is the
 	// global balance.
 	if resp.TotalBalance != resp.Summary.EndingBalance {
-		t.Errorf("global balance %.2f != newest month ending %.2f — the chain and the "+
+		t.Errorf("global balance %v != newest month ending %v — the chain and the "+
 			"balance row have diverged", resp.TotalBalance, resp.Summary.EndingBalance)
 	}
 }
@@ -61,10 +61,10 @@ func TestAddExpense_ImplicitMonthCarriesAcrossAGap(t *testing.T) {
 	svc, repo := newExpenseService(t, false, true, 100)
 
 	testutil.SeedMonth(repo, "2026-01", 0, 250, 0, 250)
-	repo.Balance = &model.Balance{TotalBalance: 250}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(250)}
 
 	if _, err := svc.AddExpense(ctx, &model.AddExpenseRequest{
-		Amount: 30, Description: "gap expense", Date: "2026-03-10",
+		Amount: model.Dollars(30), Description: "gap expense", Date: "2026-03-10",
 	}); err != nil {
 		t.Fatalf("AddExpense: %v", err)
 	}
@@ -73,12 +73,12 @@ func TestAddExpense_ImplicitMonthCarriesAcrossAGap(t *testing.T) {
 	if march == nil {
 		t.Fatal("March was not created")
 	}
-	if march.StartingBalance != 250 {
-		t.Errorf("starting_balance = %.2f, want 250", march.StartingBalance)
+	if march.StartingBalance != model.Dollars(250) {
+		t.Errorf("starting_balance = %v, want 250", march.StartingBalance)
 	}
 	// An implicitly opened month gets no allowance, so 250 - 30.
-	if march.EndingBalance != 220 {
-		t.Errorf("ending_balance = %.2f, want 220", march.EndingBalance)
+	if march.EndingBalance != model.Dollars(220) {
+		t.Errorf("ending_balance = %v, want 220", march.EndingBalance)
 	}
 }
 
@@ -88,14 +88,14 @@ func TestCreateMonth_CarriesAcrossAMultiMonthGap(t *testing.T) {
 	svc, repo := newExpenseService(t, false, true, 100)
 
 	testutil.SeedMonth(repo, "2025-11", 0, 75, 0, 75)
-	repo.Balance = &model.Balance{TotalBalance: 75}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(75)}
 
 	resp, err := svc.CreateMonth(ctx, "2026-04")
 	if err != nil {
 		t.Fatalf("CreateMonth: %v", err)
 	}
-	if got := resp.Summary.StartingBalance; got != 75 {
-		t.Errorf("starting_balance = %.2f, want 75 across a four-month hole", got)
+	if got := resp.Summary.StartingBalance; got != model.Dollars(75) {
+		t.Errorf("starting_balance = %v, want 75 across a four-month hole", got)
 	}
 }
 
@@ -108,14 +108,14 @@ func TestCreateMonth_CarriesFromTheLatestPriorMonthNotTheOldest(t *testing.T) {
 	testutil.SeedMonth(repo, "2025-09", 0, 10, 0, 10)
 	testutil.SeedMonth(repo, "2025-10", 10, 10, 0, 20)
 	testutil.SeedMonth(repo, "2026-01", 20, 10, 0, 30)
-	repo.Balance = &model.Balance{TotalBalance: 30}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(30)}
 
 	resp, err := svc.CreateMonth(ctx, "2026-05")
 	if err != nil {
 		t.Fatalf("CreateMonth: %v", err)
 	}
-	if got := resp.Summary.StartingBalance; got != 30 {
-		t.Errorf("starting_balance = %.2f, want 30 (2026-01's ending, the latest before "+
+	if got := resp.Summary.StartingBalance; got != model.Dollars(30) {
+		t.Errorf("starting_balance = %v, want 30 (2026-01's ending, the latest before "+
 			"the target)", got)
 	}
 }
@@ -127,14 +127,14 @@ func TestCreateMonth_ContiguousMonthIsUnaffected(t *testing.T) {
 	svc, repo := newExpenseService(t, false, true, 100)
 
 	testutil.SeedMonth(repo, "2026-01", 0, 250, 50, 200)
-	repo.Balance = &model.Balance{TotalBalance: 200}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(200)}
 
 	resp, err := svc.CreateMonth(ctx, "2026-02")
 	if err != nil {
 		t.Fatalf("CreateMonth: %v", err)
 	}
-	if got := resp.Summary.StartingBalance; got != 200 {
-		t.Errorf("starting_balance = %.2f, want 200", got)
+	if got := resp.Summary.StartingBalance; got != model.Dollars(200) {
+		t.Errorf("starting_balance = %v, want 200", got)
 	}
 }
 
@@ -144,14 +144,14 @@ func TestCreateMonth_GapCarriesNothingWhenCarryIsOff(t *testing.T) {
 	svc, repo := newExpenseService(t, false, false, 100)
 
 	testutil.SeedMonth(repo, "2026-01", 0, 250, 0, 250)
-	repo.Balance = &model.Balance{TotalBalance: 250}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(250)}
 
 	resp, err := svc.CreateMonth(ctx, "2026-03")
 	if err != nil {
 		t.Fatalf("CreateMonth: %v", err)
 	}
 	if got := resp.Summary.StartingBalance; got != 0 {
-		t.Errorf("starting_balance = %.2f, want 0 with carry off", got)
+		t.Errorf("starting_balance = %v, want 0 with carry off", got)
 	}
 }
 
@@ -166,7 +166,7 @@ func TestCreateMonth_FirstEverMonthStartsAtZero(t *testing.T) {
 		t.Fatalf("CreateMonth: %v", err)
 	}
 	if got := resp.Summary.StartingBalance; got != 0 {
-		t.Errorf("starting_balance = %.2f, want 0 for the first month", got)
+		t.Errorf("starting_balance = %v, want 0 for the first month", got)
 	}
 }
 
@@ -180,14 +180,14 @@ func TestCreateMonth_FindsThePriorMonthOnALegacyTable(t *testing.T) {
 
 	// Canonical row present, no MONTHLIST mirror — a pre-index table.
 	testutil.SeedLegacyMonth(repo, "2026-01", 0, 250, 0, 250)
-	repo.Balance = &model.Balance{TotalBalance: 250}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(250)}
 
 	resp, err := svc.CreateMonth(ctx, "2026-03")
 	if err != nil {
 		t.Fatalf("CreateMonth: %v", err)
 	}
-	if got := resp.Summary.StartingBalance; got != 250 {
-		t.Errorf("starting_balance = %.2f, want 250 — the prior month was invisible "+
+	if got := resp.Summary.StartingBalance; got != model.Dollars(250) {
+		t.Errorf("starting_balance = %v, want 250 — the prior month was invisible "+
 			"because the MONTHLIST index had no mirror for it", got)
 	}
 }
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+)
+
+// ErrInvalidDateRange is returned when a date-range query's from is after
+// its to, or the range spans more than maxExpenseRangeDays. Handler maps
+// to 400.
+var ErrInvalidDateRange = errors.New("invalid date range")
+
+// maxExpenseRangeDays bounds a date-range query to about a year, so one
+// page never walks more than thirteen month partitions.
+const maxExpenseRangeDays = 366
+
+// GetExpensesInRange lists the expenses dated from..to (inclusive
+// "YYYY-MM-DD" days, UTC), newest first, across as many months as the
+// range covers. The months are queried newest first and each with what is
+// left of the limit, so one opaque cursor spans the partitions: it is the
+// key of the last expense returned, and its PK says which month to resume
+// in. Each item carries its Month.
+func (s *ExpenseService) GetExpensesInRange(ctx context.Context, fromStr, toStr string, limit int32, cursorStr string) (*model.ExpenseRangeResponse, error) {
+	from, err := time.Parse("2006-01-02", fromStr)
+	if err != nil {
+		return nil, ErrInvalidDate
+	}
+	to, err := time.Parse("2006-01-02", toStr)
+	if err != nil {
+		return nil, ErrInvalidDate
+	}
+	if from.After(to) || to.Sub(from) >= maxExpenseRangeDays*24*time.Hour {
+		return nil, ErrInvalidDateRange
+	}
+	// The query bounds are half-open, so the last day ends at the next
+	// day's midnight.
+	end := to.AddDate(0, 0, 1)
+	fromMonth, month := monthOf(from), monthOf(to)
+
+	var cursor map[string]types.AttributeValue
+	if cursorStr != "" {
+		cursor, err = decodeCursor(cursorStr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+		}
+		// The cursor picks the month to resume in; it must be one this
+		// range covers, and then a valid resume key for that month.
+		pk, _ := cursor["PK"].(*types.AttributeValueMemberS)
+		if pk == nil {
+			return nil, ErrInvalidCursor
+		}
+		month = repository.MonthOfPK(pk.Value)
+		if ValidateMonth(month) != nil || month < fromMonth || month > monthOf(to) {
+			return nil, ErrInvalidCursor
+		}
+		if err := validateExpenseCursor(ctx, cursor, month); err != nil {
+			return nil, err
+		}
+	}
+
+	items := []model.ExpenseItem{}
+	var next map[string]types.AttributeValue
+	for month >= fromMonth {
+		expenses, lastKey, err := s.repo.GetExpensesInRange(ctx, month, from, end, limit-int32(len(items)), cursor)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range expenses {
+			items = append(items, model.ExpenseItem{
+				ID:           e.SK,
+				Amount:       e.Amount,
+				Description:  e.Description,
+				Category:     e.Category,
+				CreatedAt:    e.CreatedAt,
+				Month:        month,
+				InstalmentID: e.InstalmentID,
+				Attachments:  e.Attachments,
+			})
+		}
+		if int32(len(items)) == limit {
+			// A full page resumes after its last expense, unless that was
+			// the last one in the oldest month.
+			if lastKey != nil || month > fromMonth {
+				next = map[string]types.AttributeValue{
+					"PK": &types.AttributeValueMemberS{Value: repository.AccountPK(ctx, repository.MonthPrefix+month)},
+					"SK": &types.AttributeValueMemberS{Value: items[len(items)-1].ID},
+				}
+			}
+			break
+		}
+		if lastKey != nil {
+			// Query stopped at its 1 MB page before the limit.
+			cursor = lastKey
+			continue
+		}
+		month, cursor = GetPreviousMonth(month), nil
+	}
+
+	response := &model.ExpenseRangeResponse{From: fromStr, To: toStr, Expenses: items}
+	if next != nil {
+		nextCursor, err := encodeCursor(next)
+		if err != nil {
+			return nil, err
+		}
+		response.NextCursor = nextCursor
+	}
+	return response, nil
+}
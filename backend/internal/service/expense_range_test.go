@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/testutil"
+)
+
+// =====================================================================
+// Date-range listing — one cursor paging newest first across the month
+// partitions a from..to range covers.
+// =====================================================================
+
+// seedDated stores an expense stamped at noon UTC on date, the way a
+// back-dated add stamps it.
+func seedDated(repo *testutil.FakeRepo, date, description string) {
+	d, _ := time.Parse("2006-01-02", date)
+	ts := d.Add(12 * time.Hour)
+	month := monthOf(ts)
+	sk := fmt.Sprintf("EXP#%d#%s", ts.UnixNano(), description)
+	repo.Expenses[testutil.ExpenseKey(month, sk)] = &model.Expense{
+		PK: "MONTH#" + month, SK: sk, Amount: model.Dollars(1), Description: description, CreatedAt: ts,
+	}
+}
+
+// rangeAll pages through every expense in from..to, limit at a time.
+func rangeAll(t *testing.T, svc *ExpenseService, from, to string, limit int32) []model.ExpenseItem {
+	t.Helper()
+	var out []model.ExpenseItem
+	cursor := ""
+	for page := 0; page < 20; page++ {
+		resp, err := svc.GetExpensesInRange(context.Background(), from, to, limit, cursor)
+		if err != nil {
+			t.Fatalf("GetExpensesInRange(%s, %s): %v", from, to, err)
+		}
+		out = append(out, resp.Expenses...)
+		if resp.NextCursor == "" {
+			return out
+		}
+		cursor = resp.NextCursor
+	}
+	t.Fatalf("GetExpensesInRange(%s, %s) did not finish paging", from, to)
+	return nil
+}
+
+func newRangeService(t *testing.T) (*ExpenseService, *testutil.FakeRepo) {
+	t.Helper()
+	svc, repo := newExpenseService(t, true, true, 0)
+	for _, e := range []struct{ date, description string }{
+		{"2025-01-31", "before"},
+		{"2025-02-01", "feb-first"},
+		{"2025-02-20", "feb-late"},
+		{"2025-04-02", "april"},
+		{"2025-04-10", "after"},
+	} {
+		seedDated(repo, e.date, e.description)
+	}
+	return svc, repo
+}
+
+func TestGetExpensesInRange_PagesAcrossMonths(t *testing.T) {
+	svc, _ := newRangeService(t)
+
+	want := []string{"april", "feb-late", "feb-first"}
+	for _, limit := range []int32{1, 2, 50} {
+		got := rangeAll(t, svc, "2025-02-01", "2025-04-02", limit)
+		if !slices.Equal(descriptions(got), want) {
+			t.Errorf("limit %d: expenses = %q, want %q", limit, descriptions(got), want)
+		}
+		if len(got) > 0 && (got[0].Month != "2025-04" || got[len(got)-1].Month != "2025-02") {
+			t.Errorf("limit %d: months = %q..%q, want 2025-04..2025-02", limit, got[0].Month, got[len(got)-1].Month)
+		}
+	}
+
+	if got := rangeAll(t, svc, "2025-03-01", "2025-03-31", 10); len(got) != 0 {
+		t.Errorf("empty month returned %q", descriptions(got))
+	}
+	if got := rangeAll(t, svc, "2025-01-31", "2025-01-31", 10); !slices.Equal(descriptions(got), []string{"before"}) {
+		t.Errorf("single day = %q, want [before]", descriptions(got))
+	}
+}
+
+func TestGetExpensesInRange_Refusals(t *testing.T) {
+	svc, _ := newRangeService(t)
+	ctx := context.Background()
+
+	for _, r := range []struct {
+		from, to string
+		want     error
+	}{
+		{"2025-02-30", "2025-03-01", ErrInvalidDate},
+		{"2025-02-01", "", ErrInvalidDate},
+		{"2025-03-01", "2025-02-01", ErrInvalidDateRange},
+		{"2024-01-01", "2025-01-01", ErrInvalidDateRange},
+	} {
+		if _, err := svc.GetExpensesInRange(ctx, r.from, r.to, 10, ""); !errors.Is(err, r.want) {
+			t.Errorf("range %q..%q err = %v, want %v", r.from, r.to, err, r.want)
+		}
+	}
+	if _, err := svc.GetExpensesInRange(ctx, "2024-01-01", "2024-12-31", 10, ""); err != nil {
+		t.Errorf("366-day range err = %v, want nil", err)
+	}
+
+	// A cursor must name a month inside the range and an expense row.
+	key := func(pk, sk string) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: pk},
+			"SK": &types.AttributeValueMemberS{Value: sk},
+		}
+	}
+	outside, _ := encodeCursor(key("MONTH#2025-01", "EXP#1#a"))
+	notExpense, _ := encodeCursor(key("MONTH#2025-02", "SUMMARY"))
+	for _, cursor := range []string{"not-base64!", outside, notExpense} {
+		if _, err := svc.GetExpensesInRange(ctx, "2025-02-01", "2025-04-02", 10, cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("cursor %q err = %v, want ErrInvalidCursor", cursor, err)
+		}
+	}
+}
@@ -32,7 +32,7 @@ func TestAddExpense_BudgetMode(t *testing.T) {
 	t.Run("HardStop refuses when balance insufficient", func(t *testing.T) {
 		svc, repo := newExpenseService(t, false, true, 0)
 		testutil.SeedMonth(repo, month, 0, 10, 0, 10)
-		_, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: 12, Description: "ice cream"})
+		_, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(12), Description: "ice cream"})
 		if !errors.Is(err, ErrInsufficientFunds) {
 			t.Fatalf("expected ErrInsufficientFunds, got %v", err)
 		}
@@ -41,7 +41,7 @@ func TestAddExpense_BudgetMode(t *testing.T) {
 		if !errors.As(err, &insufficient) {
 			t.Fatalf("expected *InsufficientFundsError, got %T", err)
 		}
-		if insufficient.Available != 10 {
+		if insufficient.Available != model.Dollars(10) {
 			t.Errorf("Available = %v, want 10", insufficient.Available)
 		}
 	})
@@ -49,11 +49,11 @@ func TestAddExpense_BudgetMode(t *testing.T) {
 	t.Run("HardStop allows expense within balance", func(t *testing.T) {
 		svc, repo := newExpenseService(t, false, true, 0)
 		testutil.SeedMonth(repo, month, 0, 10, 0, 10)
-		resp, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: 7, Description: "book"})
+		resp, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(7), Description: "book"})
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
-		if resp.MonthBalance != 3 {
+		if resp.MonthBalance != model.Dollars(3) {
 			t.Errorf("MonthBalance = %v, want 3", resp.MonthBalance)
 		}
 	})
@@ -61,11 +61,11 @@ func TestAddExpense_BudgetMode(t *testing.T) {
 	t.Run("Overspend permits negative ending balance", func(t *testing.T) {
 		svc, repo := newExpenseService(t, true, false, 0)
 		testutil.SeedMonth(repo, month, 0, 10, 0, 10)
-		resp, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: 15, Description: "splurge"})
+		resp, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(15), Description: "splurge"})
 		if err != nil {
 			t.Fatalf("unexpected error in overspend mode: %v", err)
 		}
-		if resp.MonthBalance != -5 {
+		if resp.MonthBalance != model.Dollars(-5) {
 			t.Errorf("MonthBalance = %v, want -5 (overspend allowed)", resp.MonthBalance)
 		}
 	})
@@ -85,7 +85,7 @@ func TestAddExpense_Validation(t *testing.T) {
 
 	t.Run("zero or negative amount rejected", func(t *testing.T) {
 		svc, _ := newSeeded(t)
-		for _, amt := range []float64{0, -5, 0.004} { // 0.004 rounds to 0.00
+		for _, amt := range []model.Money{0, model.Dollars(-5), model.Dollars(0.004)} { // 0.004 rounds to 0.00
 			if _, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: amt, Description: "x"}); err != ErrInvalidAmount {
 				t.Errorf("amount %v: expected ErrInvalidAmount, got %v", amt, err)
 			}
@@ -94,7 +94,7 @@ func TestAddExpense_Validation(t *testing.T) {
 
 	t.Run("amount above cap rejected", func(t *testing.T) {
 		svc, _ := newSeeded(t)
-		if _, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: 100000, Description: "x"}); err != ErrInvalidAmount {
+		if _, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(100000), Description: "x"}); err != ErrInvalidAmount {
 			t.Errorf("expected ErrInvalidAmount, got %v", err)
 		}
 	})
@@ -105,14 +105,14 @@ func TestAddExpense_Validation(t *testing.T) {
 		for i := range long {
 			long[i] = 'a'
 		}
-		if _, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: 1, Description: string(long)}); err != ErrDescriptionTooLong {
+		if _, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(1), Description: string(long)}); err != ErrDescriptionTooLong {
 			t.Errorf("expected ErrDescriptionTooLong, got %v", err)
 		}
 	})
 
 	t.Run("blank description defaults to Expense", func(t *testing.T) {
 		svc, _ := newSeeded(t)
-		resp, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: 1, Description: "   "})
+		resp, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(1), Description: "   "})
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -123,7 +123,7 @@ func TestAddExpense_Validation(t *testing.T) {
 
 	t.Run("bad month format rejected", func(t *testing.T) {
 		svc, _ := newSeeded(t)
-		if _, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: 1, Description: "x", Month: "junk"}); err != ErrInvalidMonth {
+		if _, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(1), Description: "x", Month: "junk"}); err != ErrInvalidMonth {
 			t.Errorf("expected ErrInvalidMonth, got %v", err)
 		}
 	})
@@ -143,10 +143,10 @@ func TestCreateMonth(t *testing.T) {
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
-		if resp.Summary.StartingBalance != 70 {
+		if resp.Summary.StartingBalance != model.Dollars(70) {
 			t.Errorf("StartingBalance = %v, want 70 (carried)", resp.Summary.StartingBalance)
 		}
-		if resp.Summary.EndingBalance != 170 {
+		if resp.Summary.EndingBalance != model.Dollars(170) {
 			t.Errorf("EndingBalance = %v, want 170 (70 carry + 100 allowance)", resp.Summary.EndingBalance)
 		}
 	})
@@ -161,7 +161,7 @@ func TestCreateMonth(t *testing.T) {
 		if resp.Summary.StartingBalance != 0 {
 			t.Errorf("StartingBalance = %v, want 0 (fresh start)", resp.Summary.StartingBalance)
 		}
-		if resp.Summary.EndingBalance != 500 {
+		if resp.Summary.EndingBalance != model.Dollars(500) {
 			t.Errorf("EndingBalance = %v, want 500 (no carry-over of -171 prev)", resp.Summary.EndingBalance)
 		}
 	})
@@ -188,7 +188,7 @@ func TestCreateMonth(t *testing.T) {
 		if _, err := svc.CreateMonth(ctx, "2026-02"); err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
-		if repo.Balance.TotalBalance != 100 {
+		if repo.Balance.TotalBalance != model.Dollars(100) {
 			t.Errorf("TotalBalance = %v, want 100", repo.Balance.TotalBalance)
 		}
 	})
@@ -197,7 +197,7 @@ func TestCreateMonth(t *testing.T) {
 // =====================================================================
 // TestCreateMonth_RoundsCarriedBalance replays the prod incident: May
 // ended at -522.16; carrying it into June computed -522.16 + 500 in
-// float64 and marshaled -22.159999999999968 into the ledger. roundCents
+// float64 and marshaled -22.159999999999968 into the ledger. Integer cents
 // must keep both the carried start and the computed ending clean.
 // =====================================================================
 func TestCreateMonth_RoundsCarriedBalance(t *testing.T) {
@@ -209,10 +209,10 @@ func TestCreateMonth_RoundsCarriedBalance(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if got := testutil.FmtFloat(resp.Summary.StartingBalance); got != "-522.16" {
+	if got := resp.Summary.StartingBalance.String(); got != "-522.16" {
 		t.Errorf("StartingBalance marshals as %q, want \"-522.16\"", got)
 	}
-	if got := testutil.FmtFloat(resp.Summary.EndingBalance); got != "-22.16" {
+	if got := resp.Summary.EndingBalance.String(); got != "-22.16" {
 		t.Errorf("EndingBalance marshals as %q, want \"-22.16\"", got)
 	}
 }
@@ -230,7 +230,7 @@ func TestAddExpense_AutoCreatedMonth(t *testing.T) {
 		svc, repo := newExpenseService(t, true, true, 500)
 		testutil.SeedMonth(repo, "2026-05", 0, 500, 1022.16, -522.16)
 
-		_, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: 10, Description: "tacos", Month: "2026-06"})
+		_, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(10), Description: "tacos", Month: "2026-06"})
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -238,10 +238,10 @@ func TestAddExpense_AutoCreatedMonth(t *testing.T) {
 		if s == nil {
 			t.Fatal("2026-06 was not auto-created")
 		}
-		if got := testutil.FmtFloat(s.StartingBalance); got != "-522.16" {
+		if got := s.StartingBalance.String(); got != "-522.16" {
 			t.Errorf("StartingBalance marshals as %q, want \"-522.16\"", got)
 		}
-		if !testutil.AlmostEqual(s.EndingBalance, -532.16) {
+		if s.EndingBalance != model.Dollars(-532.16) {
 			t.Errorf("EndingBalance = %v, want -532.16 (carried -522.16 minus 10)", s.EndingBalance)
 		}
 	})
@@ -250,7 +250,7 @@ func TestAddExpense_AutoCreatedMonth(t *testing.T) {
 		svc, repo := newExpenseService(t, true, false, 500)
 		testutil.SeedMonth(repo, "2026-05", 0, 500, 1022.16, -522.16)
 
-		_, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: 10, Description: "tacos", Month: "2026-06"})
+		_, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(10), Description: "tacos", Month: "2026-06"})
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -264,22 +264,30 @@ func TestAddExpense_AutoCreatedMonth(t *testing.T) {
 	})
 }
 
-// TestAddExpense_RoundsAmountToCents pins service-boundary rounding —
-// a client sending 12.349 must not put a 3-decimal amount in the ledger
-// while the summary delta rounds to 12.35 (a 0.001 drift per edit).
+// TestAddExpense_RoundsAmountToCents pins boundary rounding — a client
+// sending 12.349 must not put a 3-decimal amount in the ledger while the
+// summary moves by 12.35. The request decodes straight into cents, so the
+// expense and the summary see the same figure.
 func TestAddExpense_RoundsAmountToCents(t *testing.T) {
 	ctx := context.Background()
 	svc, repo := newExpenseService(t, true, true, 0)
 	month := GetCurrentMonth()
 	testutil.SeedMonth(repo, month, 0, 500, 0, 500)
 
-	resp, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: 12.349, Description: "x"})
+	var req model.AddExpenseRequest
+	if err := json.Unmarshal([]byte(`{"amount":12.349,"description":"x"}`), &req); err != nil {
+		t.Fatalf("decode request: %v", err)
+	}
+	resp, err := svc.AddExpense(ctx, &req)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if got := testutil.FmtFloat(resp.Expense.Amount); got != "12.35" {
+	if got := resp.Expense.Amount.String(); got != "12.35" {
 		t.Errorf("Amount marshals as %q, want \"12.35\"", got)
 	}
+	if got := repo.Months[month].TotalExpenses; got != resp.Expense.Amount {
+		t.Errorf("TotalExpenses = %v, want %v (same cents as the expense)", got, resp.Expense.Amount)
+	}
 }
 
 // =====================================================================
@@ -294,50 +302,50 @@ func TestUpdateExpense_DeltaMath(t *testing.T) {
 	setup := func(t *testing.T) (*ExpenseService, *testutil.FakeRepo, string) {
 		svc, repo := newExpenseService(t, false, true, 0)
 		testutil.SeedMonth(repo, month, 0, 100, 30, 70)
-		repo.Balance = &model.Balance{TotalBalance: 70}
+		repo.Balance = &model.Balance{TotalBalance: model.Dollars(70)}
 		expenseID := "EXP#1#abc"
 		repo.Expenses[testutil.ExpenseKey(month, expenseID)] = &model.Expense{
-			SK: expenseID, Amount: 30, Description: "old",
+			SK: expenseID, Amount: model.Dollars(30), Description: "old",
 		}
 		return svc, repo, expenseID
 	}
 
 	t.Run("Increase deducts only the delta", func(t *testing.T) {
 		svc, repo, id := setup(t)
-		newAmt := 50.0
+		newAmt := model.Dollars(50)
 		_, err := svc.UpdateExpense(ctx, month, id, &model.UpdateExpenseRequest{Amount: &newAmt})
 		if err != nil {
 			t.Fatalf("UpdateExpense failed: %v", err)
 		}
 		// Started: total_expenses=30, ending=70, balance=70
 		// Increase by 20 (50-30): total_expenses=50, ending=50, balance=50
-		if got := repo.Months[month].TotalExpenses; got != 50 {
+		if got := repo.Months[month].TotalExpenses; got != model.Dollars(50) {
 			t.Errorf("TotalExpenses = %v, want 50", got)
 		}
-		if got := repo.Months[month].EndingBalance; got != 50 {
+		if got := repo.Months[month].EndingBalance; got != model.Dollars(50) {
 			t.Errorf("EndingBalance = %v, want 50", got)
 		}
-		if got := repo.Balance.TotalBalance; got != 50 {
+		if got := repo.Balance.TotalBalance; got != model.Dollars(50) {
 			t.Errorf("TotalBalance = %v, want 50", got)
 		}
 	})
 
 	t.Run("Decrease refunds the delta", func(t *testing.T) {
 		svc, repo, id := setup(t)
-		newAmt := 10.0
+		newAmt := model.Dollars(10)
 		_, err := svc.UpdateExpense(ctx, month, id, &model.UpdateExpenseRequest{Amount: &newAmt})
 		if err != nil {
 			t.Fatalf("UpdateExpense failed: %v", err)
 		}
 		// Started: total_expenses=30, ending=70, balance=70
 		// Decrease by 20 (10-30=-20): total_expenses=10, ending=90, balance=90
-		if got := repo.Months[month].TotalExpenses; got != 10 {
+		if got := repo.Months[month].TotalExpenses; got != model.Dollars(10) {
 			t.Errorf("TotalExpenses = %v, want 10", got)
 		}
-		if got := repo.Months[month].EndingBalance; got != 90 {
+		if got := repo.Months[month].EndingBalance; got != model.Dollars(90) {
 			t.Errorf("EndingBalance = %v, want 90", got)
 		}
-		if got := repo.Balance.TotalBalance; got != 90 {
+		if got := repo.Balance.TotalBalance; got != model.Dollars(90) {
 			t.Errorf("TotalBalance = %v, want 90", got)
 		}
 	})
@@ -349,23 +357,23 @@ func TestUpdateExpense_DeltaMath(t *testing.T) {
 		if err != nil {
 			t.Fatalf("UpdateExpense failed: %v", err)
 		}
-		if got := repo.Months[month].TotalExpenses; got != 30 {
+		if got := repo.Months[month].TotalExpenses; got != model.Dollars(30) {
 			t.Errorf("TotalExpenses = %v, want 30 (unchanged)", got)
 		}
-		if got := repo.Balance.TotalBalance; got != 70 {
+		if got := repo.Balance.TotalBalance; got != model.Dollars(70) {
 			t.Errorf("TotalBalance = %v, want 70 (unchanged)", got)
 		}
 	})
 
 	t.Run("Increase exceeding balance refused under hard-stop", func(t *testing.T) {
 		svc, repo, id := setup(t)
-		newAmt := 200.0 // delta = +170, exceeds available 70
+		newAmt := model.Dollars(200) // delta = +170, exceeds available 70
 		_, err := svc.UpdateExpense(ctx, month, id, &model.UpdateExpenseRequest{Amount: &newAmt})
 		if !errors.Is(err, ErrInsufficientFunds) {
 			t.Errorf("expected ErrInsufficientFunds, got %v", err)
 		}
 		// Balance untouched after rejection.
-		if got := repo.Months[month].TotalExpenses; got != 30 {
+		if got := repo.Months[month].TotalExpenses; got != model.Dollars(30) {
 			t.Errorf("TotalExpenses = %v, want 30 (no mutation after reject)", got)
 		}
 	})
@@ -380,7 +388,7 @@ func TestUpdateExpense_DeltaMath(t *testing.T) {
 
 	t.Run("Unknown expense returns not found", func(t *testing.T) {
 		svc, _, _ := setup(t)
-		newAmt := 10.0
+		newAmt := model.Dollars(10)
 		_, err := svc.UpdateExpense(ctx, month, "EXP#nope", &model.UpdateExpenseRequest{Amount: &newAmt})
 		if err != ErrExpenseNotFound {
 			t.Errorf("expected ErrExpenseNotFound, got %v", err)
@@ -399,9 +407,9 @@ func TestDeleteExpense(t *testing.T) {
 	t.Run("delete refunds month and total", func(t *testing.T) {
 		svc, repo := newExpenseService(t, false, true, 0)
 		testutil.SeedMonth(repo, month, 0, 100, 30, 70)
-		repo.Balance = &model.Balance{TotalBalance: 70}
+		repo.Balance = &model.Balance{TotalBalance: model.Dollars(70)}
 		id := "EXP#1#abc"
-		repo.Expenses[testutil.ExpenseKey(month, id)] = &model.Expense{SK: id, Amount: 30}
+		repo.Expenses[testutil.ExpenseKey(month, id)] = &model.Expense{SK: id, Amount: model.Dollars(30)}
 
 		if err := svc.DeleteExpense(ctx, month, id); err != nil {
 			t.Fatalf("DeleteExpense failed: %v", err)
@@ -409,10 +417,10 @@ func TestDeleteExpense(t *testing.T) {
 		if _, exists := repo.Expenses[testutil.ExpenseKey(month, id)]; exists {
 			t.Error("expense row still present after delete")
 		}
-		if got := repo.Months[month].EndingBalance; got != 100 {
+		if got := repo.Months[month].EndingBalance; got != model.Dollars(100) {
 			t.Errorf("EndingBalance = %v, want 100 (refunded)", got)
 		}
-		if got := repo.Balance.TotalBalance; got != 100 {
+		if got := repo.Balance.TotalBalance; got != model.Dollars(100) {
 			t.Errorf("TotalBalance = %v, want 100 (refunded)", got)
 		}
 	})
@@ -437,19 +445,19 @@ func TestAddFunds(t *testing.T) {
 	t.Run("credits month and total", func(t *testing.T) {
 		svc, repo := newExpenseService(t, true, false, 500)
 		testutil.SeedMonth(repo, month, 0, 500, 100, 400)
-		repo.Balance = &model.Balance{TotalBalance: 400}
+		repo.Balance = &model.Balance{TotalBalance: model.Dollars(400)}
 
-		resp, err := svc.AddFunds(ctx, month, 50)
+		resp, err := svc.AddFunds(ctx, month, model.Dollars(50), "")
 		if err != nil {
 			t.Fatalf("AddFunds failed: %v", err)
 		}
-		if resp.Summary.AllowanceAdded != 550 {
+		if resp.Summary.AllowanceAdded != model.Dollars(550) {
 			t.Errorf("AllowanceAdded = %v, want 550", resp.Summary.AllowanceAdded)
 		}
-		if resp.Summary.EndingBalance != 450 {
+		if resp.Summary.EndingBalance != model.Dollars(450) {
 			t.Errorf("EndingBalance = %v, want 450", resp.Summary.EndingBalance)
 		}
-		if resp.TotalBalance != 450 {
+		if resp.TotalBalance != model.Dollars(450) {
 			t.Errorf("TotalBalance = %v, want 450", resp.TotalBalance)
 		}
 	})
@@ -457,8 +465,8 @@ func TestAddFunds(t *testing.T) {
 	t.Run("non-positive amount rejected", func(t *testing.T) {
 		svc, repo := newExpenseService(t, true, false, 500)
 		testutil.SeedMonth(repo, month, 0, 500, 0, 500)
-		for _, amt := range []float64{0, -5, 0.004} {
-			if _, err := svc.AddFunds(ctx, month, amt); err != ErrFundsNotPositive {
+		for _, amt := range []model.Money{0, model.Dollars(-5), model.Dollars(0.004)} {
+			if _, err := svc.AddFunds(ctx, month, amt, ""); err != ErrFundsNotPositive {
 				t.Errorf("amount %v: expected ErrFundsNotPositive, got %v", amt, err)
 			}
 		}
@@ -466,7 +474,7 @@ func TestAddFunds(t *testing.T) {
 
 	t.Run("missing month rejected", func(t *testing.T) {
 		svc, _ := newExpenseService(t, true, false, 500)
-		if _, err := svc.AddFunds(ctx, "2026-03", 50); err != ErrMonthNotFound {
+		if _, err := svc.AddFunds(ctx, "2026-03", model.Dollars(50), ""); err != ErrMonthNotFound {
 			t.Errorf("expected ErrMonthNotFound, got %v", err)
 		}
 	})
@@ -578,7 +586,7 @@ func TestEnsureMonthExists_ConditionalPut(t *testing.T) {
 		if err != nil {
 			t.Fatalf("ensureMonthExists failed: %v", err)
 		}
-		if s.AllowanceAdded != 100 || s.TotalExpenses != 40 || s.EndingBalance != 60 {
+		if s.AllowanceAdded != model.Dollars(100) || s.TotalExpenses != model.Dollars(40) || s.EndingBalance != model.Dollars(60) {
 			t.Errorf("got %+v, want the existing winner (allow=100, exp=40, end=60) — not a clobber", s)
 		}
 	})
@@ -596,7 +604,7 @@ func TestCreateMonth_IdempotentAllowance(t *testing.T) {
 	t.Run("activates a $0 auto-created month", func(t *testing.T) {
 		svc, repo := newExpenseService(t, true, true, 100)
 		// Auto-create the month via an expense (gives it $0 allowance).
-		if _, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: 10, Description: "x", Month: "2026-08"}); err != nil {
+		if _, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(10), Description: "x", Month: "2026-08"}); err != nil {
 			t.Fatalf("AddExpense failed: %v", err)
 		}
 		if repo.Months["2026-08"].AllowanceAdded != 0 {
@@ -608,11 +616,11 @@ func TestCreateMonth_IdempotentAllowance(t *testing.T) {
 		if err != nil {
 			t.Fatalf("CreateMonth should idempotently activate, got: %v", err)
 		}
-		if resp.Summary.AllowanceAdded != 100 {
+		if resp.Summary.AllowanceAdded != model.Dollars(100) {
 			t.Errorf("AllowanceAdded = %v, want 100 (allowance applied)", resp.Summary.AllowanceAdded)
 		}
-		if got := repo.Balance.TotalBalance; got != balanceBefore+100 {
-			t.Errorf("TotalBalance = %v, want %v (credited by allowance)", got, balanceBefore+100)
+		if got := repo.Balance.TotalBalance; got != balanceBefore+model.Dollars(100) {
+			t.Errorf("TotalBalance = %v, want %v (credited by allowance)", got, balanceBefore+model.Dollars(100))
 		}
 	})
 
@@ -635,7 +643,7 @@ func TestDeleteMonth(t *testing.T) {
 	t.Run("deletes an empty month and reverses the allowance", func(t *testing.T) {
 		svc, repo := newExpenseService(t, true, true, 100)
 		testutil.SeedMonth(repo, "2026-09", 0, 100, 0, 100)
-		repo.Balance = &model.Balance{TotalBalance: 100}
+		repo.Balance = &model.Balance{TotalBalance: model.Dollars(100)}
 
 		if err := svc.DeleteMonth(ctx, "2026-09"); err != nil {
 			t.Fatalf("DeleteMonth failed: %v", err)
@@ -772,38 +780,38 @@ func TestPropagateToLaterMonths(t *testing.T) {
 		testutil.SeedMonth(repo, "2026-02", 70, 100, 0, 170)
 		// Mar carried 170: +100 allowance → ending 270. Multi-month ripple.
 		testutil.SeedMonth(repo, "2026-03", 170, 100, 0, 270)
-		repo.Balance = &model.Balance{TotalBalance: 170}
+		repo.Balance = &model.Balance{TotalBalance: model.Dollars(170)}
 		id := "EXP#1#abc"
-		repo.Expenses[testutil.ExpenseKey("2026-01", id)] = &model.Expense{SK: id, Amount: 30}
+		repo.Expenses[testutil.ExpenseKey("2026-01", id)] = &model.Expense{SK: id, Amount: model.Dollars(30)}
 
 		if err := svc.DeleteExpense(ctx, "2026-01", id); err != nil {
 			t.Fatalf("DeleteExpense failed: %v", err)
 		}
 		// Jan refunded: ending 100. Feb and Mar must carry the +30 forward.
-		if got := repo.Months["2026-01"].EndingBalance; got != 100 {
+		if got := repo.Months["2026-01"].EndingBalance; got != model.Dollars(100) {
 			t.Errorf("Jan ending = %v, want 100", got)
 		}
-		if got := repo.Months["2026-02"].StartingBalance; got != 100 {
+		if got := repo.Months["2026-02"].StartingBalance; got != model.Dollars(100) {
 			t.Errorf("Feb starting = %v, want 100 (carry shifted by +30)", got)
 		}
-		if got := repo.Months["2026-02"].EndingBalance; got != 200 {
+		if got := repo.Months["2026-02"].EndingBalance; got != model.Dollars(200) {
 			t.Errorf("Feb ending = %v, want 200 (carry shifted by +30)", got)
 		}
-		if got := repo.Months["2026-03"].StartingBalance; got != 200 {
+		if got := repo.Months["2026-03"].StartingBalance; got != model.Dollars(200) {
 			t.Errorf("Mar starting = %v, want 200 (carry shifted by +30)", got)
 		}
-		if got := repo.Months["2026-03"].EndingBalance; got != 300 {
+		if got := repo.Months["2026-03"].EndingBalance; got != model.Dollars(300) {
 			t.Errorf("Mar ending = %v, want 300 (carry shifted by +30)", got)
 		}
 		// The mirror rows must be shifted in lockstep — the delta is applied
 		// to MONTHLIST too, not just the canonical row.
-		if got := repo.MonthList["2026-02"].StartingBalance; got != 100 {
+		if got := repo.MonthList["2026-02"].StartingBalance; got != model.Dollars(100) {
 			t.Errorf("Feb mirror starting = %v, want 100", got)
 		}
-		if got := repo.MonthList["2026-02"].EndingBalance; got != 200 {
+		if got := repo.MonthList["2026-02"].EndingBalance; got != model.Dollars(200) {
 			t.Errorf("Feb mirror ending = %v, want 200", got)
 		}
-		if got := repo.MonthList["2026-03"].EndingBalance; got != 300 {
+		if got := repo.MonthList["2026-03"].EndingBalance; got != model.Dollars(300) {
 			t.Errorf("Mar mirror ending = %v, want 300", got)
 		}
 	})
@@ -812,9 +820,9 @@ func TestPropagateToLaterMonths(t *testing.T) {
 		svc, repo := newExpenseService(t, true, false, 0)
 		testutil.SeedMonth(repo, "2026-01", 0, 100, 30, 70)
 		testutil.SeedMonth(repo, "2026-02", 0, 100, 0, 100)
-		repo.Balance = &model.Balance{TotalBalance: 170}
+		repo.Balance = &model.Balance{TotalBalance: model.Dollars(170)}
 		id := "EXP#1#abc"
-		repo.Expenses[testutil.ExpenseKey("2026-01", id)] = &model.Expense{SK: id, Amount: 30}
+		repo.Expenses[testutil.ExpenseKey("2026-01", id)] = &model.Expense{SK: id, Amount: model.Dollars(30)}
 
 		if err := svc.DeleteExpense(ctx, "2026-01", id); err != nil {
 			t.Fatalf("DeleteExpense failed: %v", err)
@@ -836,19 +844,19 @@ func TestPropagateToLaterMonths(t *testing.T) {
 		// AddFunds that landed between this op's read and write): start 95,
 		// ending 195 rather than the 70/170 a stale snapshot would carry.
 		testutil.SeedMonth(repo, "2026-02", 95, 100, 0, 195)
-		repo.Balance = &model.Balance{TotalBalance: 195}
+		repo.Balance = &model.Balance{TotalBalance: model.Dollars(195)}
 		id := "EXP#1#abc"
-		repo.Expenses[testutil.ExpenseKey("2026-01", id)] = &model.Expense{SK: id, Amount: 30}
+		repo.Expenses[testutil.ExpenseKey("2026-01", id)] = &model.Expense{SK: id, Amount: model.Dollars(30)}
 
 		if err := svc.DeleteExpense(ctx, "2026-01", id); err != nil {
 			t.Fatalf("DeleteExpense failed: %v", err)
 		}
 		// +30 delta composes on top of the concurrent 95/195 → 125/225, NOT
 		// the 100/200 a clobbering snapshot Put would have written.
-		if got := repo.Months["2026-02"].StartingBalance; got != 125 {
+		if got := repo.Months["2026-02"].StartingBalance; got != model.Dollars(125) {
 			t.Errorf("Feb starting = %v, want 125 (concurrent +25 preserved, delta +30 applied)", got)
 		}
-		if got := repo.Months["2026-02"].EndingBalance; got != 225 {
+		if got := repo.Months["2026-02"].EndingBalance; got != model.Dollars(225) {
 			t.Errorf("Feb ending = %v, want 225 (concurrent +25 preserved, delta +30 applied)", got)
 		}
 	})
@@ -862,24 +870,24 @@ func TestPropagateToLaterMonths(t *testing.T) {
 		svc, repo := newExpenseService(t, false, true, 0)
 		testutil.SeedLegacyMonth(repo, "2026-01", 0, 100, 30, 70)
 		testutil.SeedLegacyMonth(repo, "2026-02", 70, 100, 0, 170)
-		repo.Balance = &model.Balance{TotalBalance: 170}
+		repo.Balance = &model.Balance{TotalBalance: model.Dollars(170)}
 		id := "EXP#1#abc"
-		repo.Expenses[testutil.ExpenseKey("2026-01", id)] = &model.Expense{SK: id, Amount: 30}
+		repo.Expenses[testutil.ExpenseKey("2026-01", id)] = &model.Expense{SK: id, Amount: model.Dollars(30)}
 
 		if err := svc.DeleteExpense(ctx, "2026-01", id); err != nil {
 			t.Fatalf("DeleteExpense on legacy table failed: %v", err)
 		}
-		if got := repo.Months["2026-02"].StartingBalance; got != 100 {
+		if got := repo.Months["2026-02"].StartingBalance; got != model.Dollars(100) {
 			t.Errorf("Feb starting = %v, want 100 (propagation must fire on legacy table)", got)
 		}
-		if got := repo.Months["2026-02"].EndingBalance; got != 200 {
+		if got := repo.Months["2026-02"].EndingBalance; got != model.Dollars(200) {
 			t.Errorf("Feb ending = %v, want 200 (propagation must fire on legacy table)", got)
 		}
 		// Both later month's mirror must now exist and match.
 		if repo.MonthList["2026-02"] == nil {
 			t.Fatal("Feb mirror was not backfilled during propagation")
 		}
-		if got := repo.MonthList["2026-02"].EndingBalance; got != 200 {
+		if got := repo.MonthList["2026-02"].EndingBalance; got != model.Dollars(200) {
 			t.Errorf("Feb mirror ending = %v, want 200", got)
 		}
 	})
@@ -914,10 +922,10 @@ func TestLegacyMonthMirrorBackfill(t *testing.T) {
 		if mirror == nil {
 			t.Fatalf("MONTHLIST mirror for %s was never created", m)
 		}
-		if !testutil.AlmostEqual(mirror.TotalExpenses, canon.TotalExpenses) ||
-			!testutil.AlmostEqual(mirror.EndingBalance, canon.EndingBalance) ||
-			!testutil.AlmostEqual(mirror.AllowanceAdded, canon.AllowanceAdded) ||
-			!testutil.AlmostEqual(mirror.StartingBalance, canon.StartingBalance) {
+		if mirror.TotalExpenses != canon.TotalExpenses ||
+			mirror.EndingBalance != canon.EndingBalance ||
+			mirror.AllowanceAdded != canon.AllowanceAdded ||
+			mirror.StartingBalance != canon.StartingBalance {
 			t.Errorf("mirror %+v diverged from canonical %+v", mirror, canon)
 		}
 	}
@@ -925,13 +933,13 @@ func TestLegacyMonthMirrorBackfill(t *testing.T) {
 	t.Run("AddExpense on legacy month creates mirror and succeeds", func(t *testing.T) {
 		svc, repo := newExpenseService(t, false, true, 0)
 		testutil.SeedLegacyMonth(repo, month, 0, 100, 0, 100)
-		repo.Balance = &model.Balance{TotalBalance: 100}
+		repo.Balance = &model.Balance{TotalBalance: model.Dollars(100)}
 
-		resp, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: 30, Description: "x", Month: month})
+		resp, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(30), Description: "x", Month: month})
 		if err != nil {
 			t.Fatalf("AddExpense on legacy month failed (would 500 in prod): %v", err)
 		}
-		if resp.MonthBalance != 70 {
+		if resp.MonthBalance != model.Dollars(70) {
 			t.Errorf("MonthBalance = %v, want 70", resp.MonthBalance)
 		}
 		assertMirrorConsistent(t, repo, month)
@@ -940,15 +948,15 @@ func TestLegacyMonthMirrorBackfill(t *testing.T) {
 	t.Run("UpdateExpense on legacy month creates mirror and succeeds", func(t *testing.T) {
 		svc, repo := newExpenseService(t, false, true, 0)
 		testutil.SeedLegacyMonth(repo, month, 0, 100, 30, 70)
-		repo.Balance = &model.Balance{TotalBalance: 70}
+		repo.Balance = &model.Balance{TotalBalance: model.Dollars(70)}
 		id := "EXP#1#abc"
-		repo.Expenses[testutil.ExpenseKey(month, id)] = &model.Expense{SK: id, Amount: 30, Description: "old"}
+		repo.Expenses[testutil.ExpenseKey(month, id)] = &model.Expense{SK: id, Amount: model.Dollars(30), Description: "old"}
 
-		newAmt := 50.0
+		newAmt := model.Dollars(50)
 		if _, err := svc.UpdateExpense(ctx, month, id, &model.UpdateExpenseRequest{Amount: &newAmt}); err != nil {
 			t.Fatalf("UpdateExpense on legacy month failed (would 500 in prod): %v", err)
 		}
-		if got := repo.Months[month].EndingBalance; got != 50 {
+		if got := repo.Months[month].EndingBalance; got != model.Dollars(50) {
 			t.Errorf("EndingBalance = %v, want 50", got)
 		}
 		assertMirrorConsistent(t, repo, month)
@@ -957,14 +965,14 @@ func TestLegacyMonthMirrorBackfill(t *testing.T) {
 	t.Run("DeleteExpense on legacy month creates mirror and succeeds", func(t *testing.T) {
 		svc, repo := newExpenseService(t, false, true, 0)
 		testutil.SeedLegacyMonth(repo, month, 0, 100, 30, 70)
-		repo.Balance = &model.Balance{TotalBalance: 70}
+		repo.Balance = &model.Balance{TotalBalance: model.Dollars(70)}
 		id := "EXP#1#abc"
-		repo.Expenses[testutil.ExpenseKey(month, id)] = &model.Expense{SK: id, Amount: 30}
+		repo.Expenses[testutil.ExpenseKey(month, id)] = &model.Expense{SK: id, Amount: model.Dollars(30)}
 
 		if err := svc.DeleteExpense(ctx, month, id); err != nil {
 			t.Fatalf("DeleteExpense on legacy month failed (would 500 in prod): %v", err)
 		}
-		if got := repo.Months[month].EndingBalance; got != 100 {
+		if got := repo.Months[month].EndingBalance; got != model.Dollars(100) {
 			t.Errorf("EndingBalance = %v, want 100 (refunded)", got)
 		}
 		assertMirrorConsistent(t, repo, month)
@@ -973,13 +981,13 @@ func TestLegacyMonthMirrorBackfill(t *testing.T) {
 	t.Run("AddFunds on legacy month creates mirror and succeeds", func(t *testing.T) {
 		svc, repo := newExpenseService(t, true, false, 500)
 		testutil.SeedLegacyMonth(repo, month, 0, 500, 100, 400)
-		repo.Balance = &model.Balance{TotalBalance: 400}
+		repo.Balance = &model.Balance{TotalBalance: model.Dollars(400)}
 
-		resp, err := svc.AddFunds(ctx, month, 50)
+		resp, err := svc.AddFunds(ctx, month, model.Dollars(50), "")
 		if err != nil {
 			t.Fatalf("AddFunds on legacy month failed (would 500 in prod): %v", err)
 		}
-		if resp.Summary.AllowanceAdded != 550 || resp.Summary.EndingBalance != 450 {
+		if resp.Summary.AllowanceAdded != model.Dollars(550) || resp.Summary.EndingBalance != model.Dollars(450) {
 			t.Errorf("got allowance=%v ending=%v, want 550/450", resp.Summary.AllowanceAdded, resp.Summary.EndingBalance)
 		}
 		assertMirrorConsistent(t, repo, month)
@@ -989,12 +997,40 @@ func TestLegacyMonthMirrorBackfill(t *testing.T) {
 // TestGetBalance pins the trivial read path.
 func TestGetBalance(t *testing.T) {
 	svc, repo := newExpenseService(t, false, true, 0)
-	repo.Balance = &model.Balance{TotalBalance: 123.45}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(123.45)}
 	resp, err := svc.GetBalance(context.Background())
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if resp.TotalBalance != 123.45 {
+	if resp.TotalBalance != model.Dollars(123.45) {
 		t.Errorf("TotalBalance = %v, want 123.45", resp.TotalBalance)
 	}
 }
+
+// TestMutations_LeaveMoneySweepToScheduler pins that no request pays for
+// the table-wide money sweep: the repository converts the rows a write
+// updates on its own, and the sweep runs from the scheduled jobs only.
+func TestMutations_LeaveMoneySweepToScheduler(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newExpenseService(t, true, true, 100)
+	month := GetCurrentMonth()
+	testutil.SeedMonth(repo, month, 0, 100, 0, 100)
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(100)}
+
+	resp, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(5), Description: "x"})
+	if err != nil {
+		t.Fatalf("AddExpense: %v", err)
+	}
+	if _, err := svc.AddFunds(ctx, month, model.Dollars(5), ""); err != nil {
+		t.Fatalf("AddFunds: %v", err)
+	}
+	if err := svc.DeleteExpense(ctx, month, resp.Expense.SK); err != nil {
+		t.Fatalf("DeleteExpense: %v", err)
+	}
+	if _, err := svc.RepairLedger(ctx); err != nil {
+		t.Fatalf("RepairLedger: %v", err)
+	}
+	if repo.MoneyMigrations != 0 {
+		t.Errorf("MoneyMigrations = %d after writes, want 0", repo.MoneyMigrations)
+	}
+}
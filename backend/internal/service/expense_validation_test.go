@@ -20,21 +20,21 @@ func TestAddFunds_RejectsAbsurdAmount(t *testing.T) {
 	ctx := context.Background()
 	svc, repo := newExpenseService(t, true, true, 100)
 	testutil.SeedMonth(repo, "2026-02", 0, 100, 0, 100)
-	repo.Balance = &model.Balance{TotalBalance: 100}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(100)}
 
-	if _, err := svc.AddFunds(ctx, "2026-02", 1e15); err != ErrInvalidAmount {
+	if _, err := svc.AddFunds(ctx, "2026-02", model.Dollars(1e15), ""); err != ErrInvalidAmount {
 		t.Errorf("AddFunds(1e15) = %v, want ErrInvalidAmount", err)
 	}
-	if _, err := svc.AddFunds(ctx, "2026-02", 100000); err != ErrInvalidAmount {
+	if _, err := svc.AddFunds(ctx, "2026-02", model.Dollars(100000), ""); err != ErrInvalidAmount {
 		t.Errorf("AddFunds(100000) = %v, want ErrInvalidAmount (cap is 99999.99)", err)
 	}
 	// The boundary itself must still be accepted.
-	if _, err := svc.AddFunds(ctx, "2026-02", 99999.99); err != nil {
+	if _, err := svc.AddFunds(ctx, "2026-02", model.Dollars(99999.99), ""); err != nil {
 		t.Errorf("AddFunds(99999.99) = %v, want nil", err)
 	}
 	// And nothing was credited by the rejected calls.
-	if got := repo.Months["2026-02"].AllowanceAdded; got != 100+99999.99 {
-		t.Errorf("AllowanceAdded = %v, want %v", got, 100+99999.99)
+	if got := repo.Months["2026-02"].AllowanceAdded; got != model.Dollars(100+99999.99) {
+		t.Errorf("AllowanceAdded = %v, want %v", got, model.Dollars(100+99999.99))
 	}
 }
 
@@ -59,9 +59,9 @@ func TestDescriptionLimit_CountsCharactersNotBytes(t *testing.T) {
 	t.Run("add accepts 100 characters", func(t *testing.T) {
 		svc, repo := newExpenseService(t, true, true, 0)
 		testutil.SeedMonth(repo, month, 0, 1000, 0, 1000)
-		repo.Balance = &model.Balance{TotalBalance: 1000}
+		repo.Balance = &model.Balance{TotalBalance: model.Dollars(1000)}
 		if _, err := svc.AddExpense(ctx, &model.AddExpenseRequest{
-			Amount: 5, Description: hundredChars,
+			Amount: model.Dollars(5), Description: hundredChars,
 		}); err != nil {
 			t.Errorf("AddExpense with 100 multi-byte chars = %v, want nil", err)
 		}
@@ -70,9 +70,9 @@ func TestDescriptionLimit_CountsCharactersNotBytes(t *testing.T) {
 	t.Run("add rejects 101 characters", func(t *testing.T) {
 		svc, repo := newExpenseService(t, true, true, 0)
 		testutil.SeedMonth(repo, month, 0, 1000, 0, 1000)
-		repo.Balance = &model.Balance{TotalBalance: 1000}
+		repo.Balance = &model.Balance{TotalBalance: model.Dollars(1000)}
 		if _, err := svc.AddExpense(ctx, &model.AddExpenseRequest{
-			Amount: 5, Description: strings.Repeat("a", 101),
+			Amount: model.Dollars(5), Description: strings.Repeat("a", 101),
 		}); err != ErrDescriptionTooLong {
 			t.Errorf("got %v, want ErrDescriptionTooLong", err)
 		}
@@ -83,10 +83,10 @@ func TestDescriptionLimit_CountsCharactersNotBytes(t *testing.T) {
 	t.Run("add trims before measuring, like edit", func(t *testing.T) {
 		svc, repo := newExpenseService(t, true, true, 0)
 		testutil.SeedMonth(repo, month, 0, 1000, 0, 1000)
-		repo.Balance = &model.Balance{TotalBalance: 1000}
+		repo.Balance = &model.Balance{TotalBalance: model.Dollars(1000)}
 		padded := "   " + strings.Repeat("a", 100) + "   "
 		if _, err := svc.AddExpense(ctx, &model.AddExpenseRequest{
-			Amount: 5, Description: padded,
+			Amount: model.Dollars(5), Description: padded,
 		}); err != nil {
 			t.Errorf("AddExpense with padded 100-char description = %v, want nil "+
 				"(edit accepts this, add must too)", err)
@@ -96,9 +96,9 @@ func TestDescriptionLimit_CountsCharactersNotBytes(t *testing.T) {
 	t.Run("edit rejects 101 characters", func(t *testing.T) {
 		svc, repo := newExpenseService(t, true, true, 0)
 		testutil.SeedMonth(repo, month, 0, 1000, 5, 995)
-		repo.Balance = &model.Balance{TotalBalance: 995}
+		repo.Balance = &model.Balance{TotalBalance: model.Dollars(995)}
 		id := "EXP#1#abc"
-		repo.Expenses[testutil.ExpenseKey(month, id)] = &model.Expense{SK: id, Amount: 5}
+		repo.Expenses[testutil.ExpenseKey(month, id)] = &model.Expense{SK: id, Amount: model.Dollars(5)}
 		desc := strings.Repeat("a", 101)
 		if _, err := svc.UpdateExpense(ctx, month, id,
 			&model.UpdateExpenseRequest{Description: &desc}); err != ErrDescriptionTooLong {
@@ -109,9 +109,9 @@ func TestDescriptionLimit_CountsCharactersNotBytes(t *testing.T) {
 	t.Run("edit accepts 100 characters", func(t *testing.T) {
 		svc, repo := newExpenseService(t, true, true, 0)
 		testutil.SeedMonth(repo, month, 0, 1000, 5, 995)
-		repo.Balance = &model.Balance{TotalBalance: 995}
+		repo.Balance = &model.Balance{TotalBalance: model.Dollars(995)}
 		id := "EXP#1#abc"
-		repo.Expenses[testutil.ExpenseKey(month, id)] = &model.Expense{SK: id, Amount: 5}
+		repo.Expenses[testutil.ExpenseKey(month, id)] = &model.Expense{SK: id, Amount: model.Dollars(5)}
 		if _, err := svc.UpdateExpense(ctx, month, id,
 			&model.UpdateExpenseRequest{Description: &hundredChars}); err != nil {
 			t.Errorf("UpdateExpense with 100 multi-byte chars = %v, want nil", err)
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vppillai/passbook/backend/internal/model"
+)
+
+// ErrForecastFutureMonth is returned when a forecast names a month that
+// has not begun. Handler maps to 400.
+var ErrForecastFutureMonth = errors.New("cannot forecast a month that has not begun")
+
+// forecastHistoryMonths is how many previous months the historical
+// projection averages over, counting only months the ledger has.
+const forecastHistoryMonths = 3
+
+// GetForecast projects month's ending balance ("YYYY-MM", the month of now
+// when empty) from what is known at now.
+//
+// Spending is split in two. Recurring and instalment expenses are known in
+// advance: the booked ones are already in the balance, and the recurring
+// ones still to come are taken out on their due days. Everything else is
+// discretionary, and the rest of the month's is projected twice — at the
+// month's own daily rate so far, and as the average of what the previous
+// months spent over the same remaining fraction of the month, each scaled
+// to the days this one has left — and the two averaged.
+//
+// The projected balance is walked day by day to find the first day it
+// drops below zero. Instalments are booked with their plan, so the ones
+// dated after today are added back and taken out on their days. From the
+// day it runs out a hard-stop instance refuses expenses, so its projected
+// ending stops at zero; with overspending allowed the balance goes on
+// falling.
+func (s *ExpenseService) GetForecast(ctx context.Context, month string, now time.Time) (*model.ForecastResponse, error) {
+	now = now.UTC()
+	current := monthOf(now)
+	if month == "" {
+		month = current
+	}
+	if ValidateMonth(month) != nil {
+		return nil, ErrInvalidMonth
+	}
+	if month > current {
+		return nil, ErrForecastFutureMonth
+	}
+	summary, err := s.repo.GetMonthSummary(ctx, month)
+	if err != nil {
+		return nil, err
+	}
+	if summary == nil {
+		return nil, ErrMonthNotFound
+	}
+	hardStop, err := s.hardStop(ctx)
+	if err != nil {
+		return nil, err
+	}
+	recs, err := s.repo.ListRecurringExpenses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	schedules := make(map[string]bool, len(recs))
+	for _, rec := range recs {
+		schedules[rec.ID] = true
+	}
+
+	start, _ := time.Parse("2006-01", month)
+	daysInMonth := start.AddDate(0, 1, -1).Day()
+	elapsed := daysInMonth
+	if month == current {
+		elapsed = now.Day()
+	}
+	spending, err := s.discretionarySpending(ctx, month, elapsed, schedules)
+	if err != nil {
+		return nil, err
+	}
+	day := func(d int) string { return start.AddDate(0, 0, d-1).Format("2006-01-02") }
+
+	response := &model.ForecastResponse{
+		Month:               month,
+		AsOf:                day(elapsed),
+		DaysElapsed:         elapsed,
+		DaysInMonth:         daysInMonth,
+		CurrentBalance:      summary.EndingBalance,
+		SpentSoFar:          spending.through,
+		UpcomingRecurring:   []model.ForecastItem{},
+		UpcomingInstalments: []model.ForecastItem{},
+		HardStop:            hardStop,
+	}
+	remaining := daysInMonth - elapsed
+	dueOn := make(map[int]model.Money)
+	if remaining > 0 {
+		response.RateProjection = spending.through * model.Money(remaining) / model.Money(elapsed)
+		response.ProjectedSpending = response.RateProjection
+
+		var history model.Money
+		var counted int
+		for m, i := GetPreviousMonth(month), 0; i < forecastHistoryMonths; m, i = GetPreviousMonth(m), i+1 {
+			prev, err := s.repo.GetMonthSummary(ctx, m)
+			if err != nil {
+				return nil, err
+			}
+			if prev == nil {
+				continue
+			}
+			// Cut the month at the same fraction as this one, and scale
+			// what it spent after the cut to the days this one has left:
+			// a 31-day month's last 21 days are not a 30-day month's 20.
+			prevStart, _ := time.Parse("2006-01", m)
+			prevDays := prevStart.AddDate(0, 1, -1).Day()
+			cut := elapsed * prevDays / daysInMonth
+			prevSpending, err := s.discretionarySpending(ctx, m, cut, schedules)
+			if err != nil {
+				return nil, err
+			}
+			history += prevSpending.after * model.Money(remaining) / model.Money(prevDays-cut)
+			counted++
+		}
+		if counted > 0 {
+			historical := history / model.Money(counted)
+			response.HistoricalProjection = &historical
+			response.ProjectedSpending = (response.RateProjection + historical) / 2
+		}
+
+		for _, rec := range recs {
+			if spending.booked[rec.ID] || rec.StartMonth > month || (rec.EndMonth != "" && rec.EndMonth < month) {
+				continue
+			}
+			// An occurrence already due but not booked (the run refused it
+			// for funds) is retried by the next run, tomorrow.
+			due := max(recurringDueTime(month, rec.DayOfMonth).Day(), elapsed+1)
+			dueOn[due] += rec.Amount
+			response.UpcomingRecurring = append(response.UpcomingRecurring, model.ForecastItem{
+				Date: day(due), Description: rec.Description, Amount: rec.Amount,
+			})
+		}
+		sort.SliceStable(response.UpcomingRecurring, func(i, j int) bool {
+			return response.UpcomingRecurring[i].Date < response.UpcomingRecurring[j].Date
+		})
+	}
+
+	balance := summary.EndingBalance
+	for _, e := range spending.instalments {
+		due := e.CreatedAt.UTC().Day()
+		dueOn[due] += e.Amount
+		balance += e.Amount
+		response.UpcomingInstalments = append(response.UpcomingInstalments, model.ForecastItem{
+			Date: day(due), Description: e.Description, Amount: e.Amount,
+		})
+	}
+	sort.SliceStable(response.UpcomingInstalments, func(i, j int) bool {
+		return response.UpcomingInstalments[i].Date < response.UpcomingInstalments[j].Date
+	})
+	if balance < 0 {
+		response.RunsOutOn = response.AsOf
+	}
+	var daily, leftover model.Money
+	if remaining > 0 {
+		daily = response.ProjectedSpending / model.Money(remaining)
+		leftover = response.ProjectedSpending - daily*model.Money(remaining)
+	}
+	for d := elapsed + 1; d <= daysInMonth; d++ {
+		spend := daily + dueOn[d]
+		if d == daysInMonth {
+			spend += leftover
+		}
+		if response.RunsOutOn == "" && balance-spend < 0 {
+			response.RunsOutOn = day(d)
+		}
+		balance -= spend
+	}
+	if hardStop && balance < 0 {
+		balance = 0
+	}
+	response.ProjectedEnding = balance
+	return response, nil
+}
+
+// monthSpending is what a forecast takes from one month's expense rows,
+// split at a day of the month.
+type monthSpending struct {
+	// through and after total the discretionary expenses dated on or
+	// before the day and after it.
+	through, after model.Money
+	// booked records which schedules have a row in the month.
+	booked map[string]bool
+	// instalments are the instalment rows dated after the day.
+	instalments []model.Expense
+}
+
+// discretionarySpending splits month's expense rows at its day-th day.
+// Instalment rows, and rows booked by one of schedules (whose SK ends in
+// the schedule id), are not discretionary.
+func (s *ExpenseService) discretionarySpending(ctx context.Context, month string, day int, schedules map[string]bool) (*monthSpending, error) {
+	out := &monthSpending{booked: make(map[string]bool)}
+	var cursor map[string]types.AttributeValue
+	for {
+		page, lastKey, err := s.repo.GetExpenses(ctx, month, monthsAfterPageSize, cursor)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range page {
+			if id := e.SK[strings.LastIndex(e.SK, "#")+1:]; schedules[id] {
+				out.booked[id] = true
+				continue
+			}
+			after := e.CreatedAt.UTC().Day() > day
+			switch {
+			case e.InstalmentID != "":
+				if after {
+					out.instalments = append(out.instalments, e)
+				}
+			case after:
+				out.after += e.Amount
+			default:
+				out.through += e.Amount
+			}
+		}
+		if lastKey == nil {
+			return out, nil
+		}
+		cursor = lastKey
+	}
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/testutil"
+)
+
+// =====================================================================
+// Forecast — the month's ending balance projected from its spending so
+// far, the previous months' and the recurring expenses still to come.
+// =====================================================================
+
+var forecastNow = time.Date(2026, 4, 10, 9, 0, 0, 0, time.UTC)
+
+// newForecastService leaves April on 125 at forecastNow: 20 discretionary
+// spent and a 5 comic booked by a schedule, with a 15 phone bill due on the
+// 25th. March spent 30 in its last 21 days, after the same third of it.
+func newForecastService(t *testing.T) (*ExpenseService, *testutil.FakeRepo) {
+	t.Helper()
+	svc, repo := newExpenseService(t, false, true, 100)
+	repo.Config = &model.Config{PinHash: "x"}
+	testutil.SeedMonth(repo, "2026-03", 0, 100, 0, 100)
+	testutil.SeedMonth(repo, "2026-04", 100, 100, 0, 200)
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(200)}
+	ctx := context.Background()
+	for _, e := range []struct {
+		date, description string
+		amount            float64
+	}{
+		{"2026-03-05", "Snack", 20},
+		{"2026-03-20", "Toy", 30},
+		{"2026-04-02", "Lunch", 20},
+	} {
+		req := &model.AddExpenseRequest{Amount: model.Dollars(e.amount), Description: e.description, Date: e.date}
+		if _, err := svc.AddExpense(ctx, req); err != nil {
+			t.Fatalf("AddExpense(%s): %v", e.date, err)
+		}
+	}
+	for _, rec := range []model.CreateRecurringRequest{
+		{Amount: model.Dollars(5), Description: "Comic", DayOfMonth: 5, StartMonth: "2026-04"},
+		{Amount: model.Dollars(15), Description: "Phone", DayOfMonth: 25, StartMonth: "2026-04"},
+	} {
+		if _, err := svc.CreateRecurring(ctx, &rec); err != nil {
+			t.Fatalf("CreateRecurring: %v", err)
+		}
+	}
+	if _, err := svc.RunRecurring(ctx, forecastNow); err != nil {
+		t.Fatalf("RunRecurring: %v", err)
+	}
+	return svc, repo
+}
+
+func TestForecast_ProjectsTheMonth(t *testing.T) {
+	svc, _ := newForecastService(t)
+
+	f, err := svc.GetForecast(context.Background(), "", forecastNow)
+	if err != nil {
+		t.Fatalf("GetForecast: %v", err)
+	}
+	if f.Month != "2026-04" || f.DaysElapsed != 10 || f.DaysInMonth != 30 || f.CurrentBalance != model.Dollars(125) {
+		t.Fatalf("forecast = %+v, want April on day 10 at 125", f)
+	}
+	// 20 in 10 days runs to 40 more; March's 30 over 21 days is 28.57
+	// over April's 20.
+	if f.SpentSoFar != model.Dollars(20) || f.RateProjection != model.Dollars(40) ||
+		f.HistoricalProjection == nil || *f.HistoricalProjection != model.Dollars(28.57) || f.ProjectedSpending != model.Dollars(34.28) {
+		t.Errorf("spending = %v so far, %v rate, %v history, %v projected; want 20, 40, 28.57, 34.28",
+			f.SpentSoFar, f.RateProjection, f.HistoricalProjection, f.ProjectedSpending)
+	}
+	if len(f.UpcomingRecurring) != 1 || f.UpcomingRecurring[0].Date != "2026-04-25" || f.UpcomingRecurring[0].Amount != model.Dollars(15) {
+		t.Errorf("upcoming = %+v, want the phone bill on the 25th", f.UpcomingRecurring)
+	}
+	if f.ProjectedEnding != model.Dollars(75.72) || f.RunsOutOn != "" || !f.HardStop {
+		t.Errorf("ending = %v, runs out %q, want 75.72 lasting the month", f.ProjectedEnding, f.RunsOutOn)
+	}
+}
+
+func TestForecast_RunsOutUnderEachOverspendSetting(t *testing.T) {
+	svc, _ := newForecastService(t)
+	ctx := context.Background()
+	if _, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(100), Description: "Bike", Date: "2026-04-10"}); err != nil {
+		t.Fatalf("AddExpense: %v", err)
+	}
+
+	// 25 left, 120 spent in 10 days: (240 + 28.57) / 2 = 134.28 to come,
+	// 6.71 a day, so the 14th is the first day below zero.
+	f, err := svc.GetForecast(ctx, "2026-04", forecastNow)
+	if err != nil {
+		t.Fatalf("GetForecast: %v", err)
+	}
+	if f.RunsOutOn != "2026-04-14" || f.ProjectedEnding != 0 {
+		t.Errorf("hard-stop: runs out %q, ending %v; want 2026-04-14 and 0", f.RunsOutOn, f.ProjectedEnding)
+	}
+
+	if _, err := svc.UpdateSettings(ctx, &model.UpdateSettingsRequest{AllowOverspending: boolPtr(true)}); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+	f, err = svc.GetForecast(ctx, "2026-04", forecastNow)
+	if err != nil {
+		t.Fatalf("GetForecast: %v", err)
+	}
+	if f.RunsOutOn != "2026-04-14" || f.ProjectedEnding != model.Dollars(-124.28) || f.HardStop {
+		t.Errorf("overspending: runs out %q, ending %v; want 2026-04-14 and -124.28", f.RunsOutOn, f.ProjectedEnding)
+	}
+}
+
+// An instalment dated later in the month is booked already, so it is in
+// CurrentBalance; the forecast lists it and takes it out on its day.
+func TestForecast_ListsUpcomingInstalments(t *testing.T) {
+	svc, _ := newForecastService(t)
+	ctx := context.Background()
+	if _, err := svc.CreateInstalmentPlan(ctx, &model.CreateInstalmentRequest{
+		Amount: model.Dollars(20), Description: "Headphones", Count: 2, Date: "2026-04-20",
+	}); err != nil {
+		t.Fatalf("CreateInstalmentPlan: %v", err)
+	}
+
+	f, err := svc.GetForecast(ctx, "2026-04", forecastNow)
+	if err != nil {
+		t.Fatalf("GetForecast: %v", err)
+	}
+	if f.CurrentBalance != model.Dollars(115) || f.SpentSoFar != model.Dollars(20) {
+		t.Errorf("balance %v, spent %v; want the instalment booked but not discretionary", f.CurrentBalance, f.SpentSoFar)
+	}
+	if got := f.UpcomingInstalments; len(got) != 1 || got[0].Date != "2026-04-20" || got[0].Amount != model.Dollars(10) {
+		t.Errorf("upcoming instalments = %+v, want the 10 on the 20th", got)
+	}
+	if f.ProjectedEnding != model.Dollars(65.72) {
+		t.Errorf("ending = %v, want 65.72: the instalment taken out once", f.ProjectedEnding)
+	}
+}
+
+func TestForecast_PastAndFutureMonths(t *testing.T) {
+	svc, _ := newForecastService(t)
+	ctx := context.Background()
+
+	f, err := svc.GetForecast(ctx, "2026-03", forecastNow)
+	if err != nil {
+		t.Fatalf("GetForecast(March): %v", err)
+	}
+	if f.DaysElapsed != 31 || f.ProjectedSpending != 0 || f.ProjectedEnding != f.CurrentBalance {
+		t.Errorf("March = %+v, want its actual ending with nothing projected", f)
+	}
+	if _, err := svc.GetForecast(ctx, "2026-05", forecastNow); !errors.Is(err, ErrForecastFutureMonth) {
+		t.Errorf("May err = %v, want ErrForecastFutureMonth", err)
+	}
+	if _, err := svc.GetForecast(ctx, "2026-02", forecastNow); !errors.Is(err, ErrMonthNotFound) {
+		t.Errorf("February err = %v, want ErrMonthNotFound", err)
+	}
+}
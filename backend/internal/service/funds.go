@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+)
+
+var (
+	// ErrFundEntryNotFound is returned when a funds credit id names no
+	// credit in the month. Handler maps to 404.
+	ErrFundEntryNotFound = errors.New("funds entry not found")
+	// ErrFundEntryModified is returned when a funds credit changed between
+	// the read and the conditional write. Handler maps to 409.
+	ErrFundEntryModified = errors.New("funds entry was modified")
+	// ErrWithdrawalAmountFixed is returned when an edit would change the
+	// amount of a withdrawal's entry. A withdrawal is undone by deleting its
+	// entry and redone through WithdrawFunds, which holds it to the
+	// earnings cap. Handler maps to 400.
+	ErrWithdrawalAmountFixed = errors.New("a withdrawal's amount cannot be changed")
+)
+
+// UpdateFundEntry edits a funds credit's amount and/or description. A new
+// amount moves the month's allowance and ending balance and the global
+// balance by the difference and ripples it through the later months; a
+// lower amount is a withdrawal of the difference, refused by the same rules
+// as WithdrawFunds.
+func (s *ExpenseService) UpdateFundEntry(ctx context.Context, month, id string, req *model.UpdateFundEntryRequest) (*model.AddFundsResponse, error) {
+	if req.Amount == nil && req.Description == nil {
+		return nil, ErrNoChanges
+	}
+	current, err := s.getFundEntry(ctx, month, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *current
+	if req.Amount != nil {
+		if current.Kind == model.FundKindWithdrawal {
+			return nil, ErrWithdrawalAmountFixed
+		}
+		if *req.Amount <= 0 {
+			return nil, ErrFundsNotPositive
+		}
+		if *req.Amount > maxAmount {
+			return nil, ErrInvalidAmount
+		}
+		updated.Amount = *req.Amount
+	}
+	if req.Description != nil {
+		description, err := validateDescription(*req.Description)
+		if err != nil {
+			return nil, err
+		}
+		updated.Description = description
+	}
+
+	delta := updated.Amount - current.Amount
+	if err := s.prepareFundDelta(ctx, month, delta); err != nil {
+		return nil, err
+	}
+	hardStop, err := s.hardStop(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.AtomicUpdateFundEntry(ctx, month, current, &updated, hardStop); err != nil {
+		return nil, s.fundEntryWriteErr(ctx, month, -delta, err)
+	}
+	if err := s.propagateToLaterMonths(ctx, month, delta); err != nil {
+		return nil, err
+	}
+
+	summary, balance, err := s.fetchSummaryAndBalance(ctx, month)
+	if err != nil {
+		return nil, err
+	}
+	return &model.AddFundsResponse{
+		Success:      true,
+		Summary:      summary,
+		TotalBalance: balance.TotalBalance,
+		Entry:        &updated,
+	}, nil
+}
+
+// DeleteFundEntry removes a funds credit, taking its amount back out of the
+// month like a withdrawal and rippling that through the later months.
+// Deleting a withdrawal's entry undoes the withdrawal, crediting it back.
+func (s *ExpenseService) DeleteFundEntry(ctx context.Context, month, id string) error {
+	current, err := s.getFundEntry(ctx, month, id)
+	if err != nil {
+		return err
+	}
+	if err := s.prepareFundDelta(ctx, month, -current.Amount); err != nil {
+		return err
+	}
+	hardStop, err := s.hardStop(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.AtomicDeleteFundEntry(ctx, month, current, hardStop); err != nil {
+		return s.fundEntryWriteErr(ctx, month, current.Amount, err)
+	}
+	return s.propagateToLaterMonths(ctx, month, -current.Amount)
+}
+
+func (s *ExpenseService) getFundEntry(ctx context.Context, month, id string) (*model.FundEntry, error) {
+	if !strings.HasPrefix(id, repository.FundPrefix) {
+		return nil, ErrFundEntryNotFound
+	}
+	entry, err := s.repo.GetFundEntry(ctx, month, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, ErrFundEntryNotFound
+	}
+	return entry, nil
+}
+
+// prepareFundDelta runs the checks a change of delta to a month's funds
+// needs before its transaction: a debit must not drive the carry chain
+// negative, and the month's mirror must exist for the delta update.
+func (s *ExpenseService) prepareFundDelta(ctx context.Context, month string, delta model.Money) error {
+	if delta == 0 {
+		return nil
+	}
+	if delta < 0 {
+		if err := s.ensureCarryChainAffordable(ctx, monthImpulse{month, delta}); err != nil {
+			return err
+		}
+	}
+	return s.repo.EnsureMonthListMirror(ctx, month)
+}
+
+// fundEntryWriteErr maps a refused funds-credit transaction to the service
+// error. A refused debit re-reads the month to say which rule refused it,
+// as WithdrawFunds does.
+func (s *ExpenseService) fundEntryWriteErr(ctx context.Context, month string, debit model.Money, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrExpenseStateMismatch):
+		return ErrFundEntryModified
+	case !errors.Is(err, repository.ErrInsufficientBalance):
+		return err
+	}
+	current, rerr := s.repo.GetMonthSummary(ctx, month)
+	switch {
+	case rerr != nil:
+		return rerr
+	case current == nil:
+		return ErrMonthNotFound
+	case debit > current.AllowanceAdded:
+		return ErrWithdrawalExceedsAllowance
+	default:
+		return &InsufficientFundsError{Available: current.EndingBalance}
+	}
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/testutil"
+)
+
+// =====================================================================
+// Funds credits — every top-up is its own FUND# row, and editing or
+// deleting one moves the month and the carry chain like a top-up or a
+// withdrawal of the difference.
+// =====================================================================
+
+// seedFundsChain seeds January and February, carrying, with 100 each.
+func seedFundsChain(t *testing.T, allow bool) (*ExpenseService, *testutil.FakeRepo) {
+	t.Helper()
+	svc, repo := newExpenseService(t, allow, true, 100)
+	testutil.SeedMonth(repo, "2026-01", 0, 100, 0, 100)
+	testutil.SeedMonth(repo, "2026-02", 100, 100, 0, 200)
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(200)}
+	return svc, repo
+}
+
+func TestFunds_TopUpIsItemized(t *testing.T) {
+	svc, repo := seedFundsChain(t, true)
+	ctx := context.Background()
+
+	resp, err := svc.AddFunds(ctx, "2026-01", model.Dollars(50), "  Birthday money from grandma ")
+	if err != nil {
+		t.Fatalf("AddFunds: %v", err)
+	}
+	if resp.Entry == nil || resp.Entry.Description != "Birthday money from grandma" || resp.Entry.Amount != model.Dollars(50) {
+		t.Fatalf("entry = %+v, want the trimmed 50 credit", resp.Entry)
+	}
+	if _, err := svc.AddFunds(ctx, "2026-01", model.Dollars(10), "Chores"); err != nil {
+		t.Fatalf("AddFunds: %v", err)
+	}
+
+	data, err := svc.GetMonthData(ctx, "2026-01", 50, "")
+	if err != nil {
+		t.Fatalf("GetMonthData: %v", err)
+	}
+	if len(data.Funds) != 2 || data.Funds[0].Description != "Birthday money from grandma" || data.Funds[1].Description != "Chores" {
+		t.Errorf("funds = %+v, want both credits oldest first", data.Funds)
+	}
+	if data.Summary.AllowanceAdded != model.Dollars(160) {
+		t.Errorf("allowance = %v, want 160", data.Summary.AllowanceAdded)
+	}
+	if audit := repo.Audit[0]; audit.Target != resp.Entry.SK || audit.After.Description != "Birthday money from grandma" {
+		t.Errorf("funds.add entry = %+v, want it to name the credit", audit)
+	}
+
+	if _, err := svc.AddFunds(ctx, "2026-01", model.Dollars(1), strings.Repeat("a", maxDescriptionRunes+1)); !errors.Is(err, ErrDescriptionTooLong) {
+		t.Errorf("long description err = %v, want ErrDescriptionTooLong", err)
+	}
+}
+
+func TestFunds_EditAndDeletePropagate(t *testing.T) {
+	svc, repo := seedFundsChain(t, true)
+	ctx := context.Background()
+	added, err := svc.AddFunds(ctx, "2026-01", model.Dollars(50), "Gift")
+	if err != nil {
+		t.Fatalf("AddFunds: %v", err)
+	}
+	id := added.Entry.SK
+
+	amount := model.Dollars(30)
+	resp, err := svc.UpdateFundEntry(ctx, "2026-01", id, &model.UpdateFundEntryRequest{Amount: &amount})
+	if err != nil {
+		t.Fatalf("UpdateFundEntry: %v", err)
+	}
+	if resp.Entry.Amount != amount || resp.Entry.Description != "Gift" {
+		t.Errorf("entry = %+v, want 30 and the description kept", resp.Entry)
+	}
+	if got := repo.Months["2026-02"].EndingBalance; got != model.Dollars(230) {
+		t.Errorf("Feb ending = %v, want 230 after the credit fell to 30", got)
+	}
+	assertLedgerConsistent(t, repo, "2026-01", "2026-02")
+
+	if err := svc.DeleteFundEntry(ctx, "2026-01", id); err != nil {
+		t.Fatalf("DeleteFundEntry: %v", err)
+	}
+	if len(repo.Funds) != 0 || repo.Months["2026-01"].AllowanceAdded != model.Dollars(100) {
+		t.Errorf("after delete: funds %v, allowance %v; want none and 100", repo.Funds, repo.Months["2026-01"].AllowanceAdded)
+	}
+	assertLedgerConsistent(t, repo, "2026-01", "2026-02")
+
+	if err := svc.DeleteFundEntry(ctx, "2026-01", id); !errors.Is(err, ErrFundEntryNotFound) {
+		t.Errorf("second delete err = %v, want ErrFundEntryNotFound", err)
+	}
+	if err := svc.DeleteFundEntry(ctx, "2026-01", "EXP#1#a"); !errors.Is(err, ErrFundEntryNotFound) {
+		t.Errorf("expense id err = %v, want ErrFundEntryNotFound", err)
+	}
+}
+
+func TestFunds_DeleteIsRefusedLikeAWithdrawal(t *testing.T) {
+	svc, repo := seedFundsChain(t, false)
+	ctx := context.Background()
+	added, err := svc.AddFunds(ctx, "2026-01", model.Dollars(50), "Gift")
+	if err != nil {
+		t.Fatalf("AddFunds: %v", err)
+	}
+	// February spends down to 20, so the credit cannot be taken back.
+	repo.Months["2026-02"].TotalExpenses = model.Dollars(230)
+	repo.Months["2026-02"].EndingBalance = model.Dollars(20)
+
+	var insufficient *InsufficientFundsError
+	if err := svc.DeleteFundEntry(ctx, "2026-01", added.Entry.SK); !errors.As(err, &insufficient) {
+		t.Fatalf("DeleteFundEntry err = %v, want InsufficientFundsError", err)
+	}
+	if len(repo.Funds) != 1 {
+		t.Error("refused delete removed the credit")
+	}
+}
+
+func TestFunds_ConcurrentEditIsModified(t *testing.T) {
+	svc, repo := seedFundsChain(t, true)
+	ctx := context.Background()
+	added, err := svc.AddFunds(ctx, "2026-01", model.Dollars(50), "Gift")
+	if err != nil {
+		t.Fatalf("AddFunds: %v", err)
+	}
+	repo.BeforeFundEntryWrite = func() {
+		repo.Funds[testutil.ExpenseKey("2026-01", added.Entry.SK)].Amount -= model.Dollars(1)
+	}
+
+	amount := model.Dollars(45)
+	if _, err := svc.UpdateFundEntry(ctx, "2026-01", added.Entry.SK, &model.UpdateFundEntryRequest{Amount: &amount}); !errors.Is(err, ErrFundEntryModified) {
+		t.Errorf("UpdateFundEntry err = %v, want ErrFundEntryModified", err)
+	}
+	if err := svc.DeleteFundEntry(ctx, "2026-01", added.Entry.SK); !errors.Is(err, ErrFundEntryModified) {
+		t.Errorf("DeleteFundEntry err = %v, want ErrFundEntryModified", err)
+	}
+}
+
+func TestFunds_DeleteMonthTakesItsCredits(t *testing.T) {
+	svc, repo := newExpenseService(t, true, true, 100)
+	ctx := context.Background()
+	testutil.SeedMonth(repo, "2026-01", 0, 100, 0, 100)
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(100)}
+	if _, err := svc.AddFunds(ctx, "2026-01", model.Dollars(25), "Gift"); err != nil {
+		t.Fatalf("AddFunds: %v", err)
+	}
+
+	if err := svc.DeleteMonth(ctx, "2026-01"); err != nil {
+		t.Fatalf("DeleteMonth: %v", err)
+	}
+	if len(repo.Funds) != 0 || repo.Balance.TotalBalance != 0 {
+		t.Errorf("after delete: funds %v, balance %v; want none and 0", repo.Funds, repo.Balance.TotalBalance)
+	}
+}
+
+// A withdrawal is a negative FUND# row, so the month's entries still
+// account for every change to its allowance, and deleting the row undoes
+// the withdrawal.
+func TestFunds_WithdrawalIsItemized(t *testing.T) {
+	svc, repo := seedFundsChain(t, true)
+	ctx := context.Background()
+
+	resp, err := svc.WithdrawFunds(ctx, "2026-01", model.Dollars(30))
+	if err != nil {
+		t.Fatalf("WithdrawFunds: %v", err)
+	}
+	if resp.Entry == nil || resp.Entry.Amount != model.Dollars(-30) || resp.Entry.Kind != model.FundKindWithdrawal {
+		t.Fatalf("entry = %+v, want a -30 withdrawal", resp.Entry)
+	}
+	if len(repo.Funds) != 1 {
+		t.Errorf("funds = %v, want the withdrawal's row", repo.Funds)
+	}
+	assertLedgerConsistent(t, repo, "2026-01", "2026-02")
+
+	amount := model.Dollars(10)
+	if _, err := svc.UpdateFundEntry(ctx, "2026-01", resp.Entry.SK, &model.UpdateFundEntryRequest{Amount: &amount}); !errors.Is(err, ErrWithdrawalAmountFixed) {
+		t.Errorf("UpdateFundEntry err = %v, want ErrWithdrawalAmountFixed", err)
+	}
+
+	if err := svc.DeleteFundEntry(ctx, "2026-01", resp.Entry.SK); err != nil {
+		t.Fatalf("DeleteFundEntry: %v", err)
+	}
+	if got := repo.Months["2026-01"].AllowanceAdded; got != model.Dollars(100) || len(repo.Funds) != 0 {
+		t.Errorf("after undo: allowance %v, funds %v; want 100 and none", got, repo.Funds)
+	}
+	assertLedgerConsistent(t, repo, "2026-01", "2026-02")
+}
+
+// What chores earned stays the child's: a withdrawal can take back the
+// allowance and top-ups, not the earnings on top of them.
+func TestFunds_WithdrawalLeavesEarnings(t *testing.T) {
+	svc, repo := seedFundsChain(t, true)
+	ctx := context.Background()
+	jan := repo.Months["2026-01"]
+	jan.AllowanceAdded += model.Dollars(5)
+	jan.EarningsAdded = model.Dollars(5)
+	jan.EndingBalance += model.Dollars(5)
+	repo.MonthList["2026-01"].AllowanceAdded = jan.AllowanceAdded
+	repo.MonthList["2026-01"].EarningsAdded = jan.EarningsAdded
+	repo.MonthList["2026-01"].EndingBalance = jan.EndingBalance
+	repo.Months["2026-02"].StartingBalance += model.Dollars(5)
+	repo.Months["2026-02"].EndingBalance += model.Dollars(5)
+	repo.Balance.TotalBalance += model.Dollars(5)
+
+	if _, err := svc.WithdrawFunds(ctx, "2026-01", model.Dollars(100.01)); !errors.Is(err, ErrWithdrawalExceedsAllowance) {
+		t.Fatalf("WithdrawFunds err = %v, want ErrWithdrawalExceedsAllowance", err)
+	}
+	if _, err := svc.WithdrawFunds(ctx, "2026-01", model.Dollars(100)); err != nil {
+		t.Fatalf("WithdrawFunds: %v", err)
+	}
+	if got := repo.Months["2026-01"]; got.AllowanceAdded != got.EarningsAdded {
+		t.Errorf("allowance %v, earnings %v; want only the earnings left", got.AllowanceAdded, got.EarningsAdded)
+	}
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+)
+
+var (
+	// ErrGoalNotFound is returned when a goal id does not exist. Handler
+	// maps to 404.
+	ErrGoalNotFound = errors.New("goal not found")
+	// ErrGoalNameRequired is returned when a goal's name is empty after
+	// trimming. Handler maps to 400.
+	ErrGoalNameRequired = errors.New("goal name is required")
+	// ErrInvalidGoalPriority is returned when a priority is outside
+	// 0-maxGoalPriority. Handler maps to 400.
+	ErrInvalidGoalPriority = errors.New("priority must be between 0 and 99")
+)
+
+const (
+	// maxGoalPriority bounds Goal.Priority. 0 means unset.
+	maxGoalPriority = 99
+	// goalProjectionMonths is how many recent completed months the average
+	// monthly saving is taken over. The current month is left out: its
+	// allowance lands on day one, so early in the month it looks like a
+	// month of pure saving.
+	goalProjectionMonths = 6
+	// goalMonthsPage is the one ListMonths page read for the average — a
+	// year, so months created ahead of the current one cannot crowd out
+	// the completed ones.
+	goalMonthsPage = 12
+)
+
+// CreateGoal validates and stores a new goal.
+func (s *ExpenseService) CreateGoal(ctx context.Context, req *model.CreateGoalRequest) (*model.Goal, error) {
+	goal := &model.Goal{
+		ID:       uuid.New().String()[:8],
+		Name:     req.Name,
+		Target:   req.Target,
+		Deadline: req.Deadline,
+		Priority: req.Priority,
+	}
+	if err := validateGoal(goal); err != nil {
+		return nil, err
+	}
+	goal.CreatedAt = time.Now()
+	goal.UpdatedAt = goal.CreatedAt
+	if err := s.repo.CreateGoal(ctx, goal); err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+// UpdateGoal changes a goal's name, target, deadline or priority.
+func (s *ExpenseService) UpdateGoal(ctx context.Context, id string, req *model.UpdateGoalRequest) (*model.Goal, error) {
+	if req.Name == nil && req.Target == nil && req.Deadline == nil && req.Priority == nil {
+		return nil, ErrNoChanges
+	}
+	goal, err := s.repo.GetGoal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if goal == nil {
+		return nil, ErrGoalNotFound
+	}
+	if req.Name != nil {
+		goal.Name = *req.Name
+	}
+	if req.Target != nil {
+		goal.Target = *req.Target
+	}
+	if req.Deadline != nil {
+		goal.Deadline = *req.Deadline
+	}
+	if req.Priority != nil {
+		goal.Priority = *req.Priority
+	}
+	if err := validateGoal(goal); err != nil {
+		return nil, err
+	}
+	goal.UpdatedAt = time.Now()
+	if err := s.repo.UpdateGoal(ctx, goal); err != nil {
+		if errors.Is(err, repository.ErrGoalNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+	return goal, nil
+}
+
+// DeleteGoal removes a goal. No money moves: a goal never held any.
+func (s *ExpenseService) DeleteGoal(ctx context.Context, id string) error {
+	goal, err := s.repo.DeleteGoal(ctx, id)
+	if err != nil {
+		return err
+	}
+	if goal == nil {
+		return ErrGoalNotFound
+	}
+	return nil
+}
+
+// validateGoal checks a goal's fields and normalizes its name in place.
+// The target has the same ceiling as any other money input.
+func validateGoal(goal *model.Goal) error {
+	name, err := validateDescription(goal.Name)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		return ErrGoalNameRequired
+	}
+	goal.Name = name
+	if goal.Target <= 0 || goal.Target > maxAmount {
+		return ErrInvalidAmount
+	}
+	if goal.Deadline != "" {
+		if err := ValidateMonth(goal.Deadline); err != nil {
+			return err
+		}
+	}
+	if goal.Priority < 0 || goal.Priority > maxGoalPriority {
+		return ErrInvalidGoalPriority
+	}
+	return nil
+}
+
+// sortGoalsForFunding puts goals in the order they share the balance:
+// by priority (unset last), then earliest deadline (none last), then
+// oldest first.
+func sortGoalsForFunding(goals []model.Goal) {
+	rank := func(p int) int {
+		if p == 0 {
+			return maxGoalPriority + 1
+		}
+		return p
+	}
+	sort.SliceStable(goals, func(i, j int) bool {
+		a, b := goals[i], goals[j]
+		if rank(a.Priority) != rank(b.Priority) {
+			return rank(a.Priority) < rank(b.Priority)
+		}
+		if a.Deadline != b.Deadline {
+			if a.Deadline == "" || b.Deadline == "" {
+				return b.Deadline == ""
+			}
+			return a.Deadline < b.Deadline
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+}
+
+// ListGoals returns every goal with its progress as of now.
+//
+// The total balance is shared out across the goals in funding order: each
+// takes what it still needs from what the goals before it left, so the
+// saved amounts never add up to more than the balance and a goal only
+// shows progress once everything ranked above it is covered. A goal's
+// projected month follows the same rule — it is reached when the balance
+// covers its target and every target before it — at the average
+// monthly_saved of the recent completed months ListMonths reports.
+func (s *ExpenseService) ListGoals(ctx context.Context, now time.Time) (*model.GoalsResponse, error) {
+	goals, err := s.repo.ListGoals(ctx)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := s.repo.GetBalance(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current := monthOf(now)
+	average, err := s.averageMonthlySaved(ctx, current)
+	if err != nil {
+		return nil, err
+	}
+
+	sortGoalsForFunding(goals)
+	resp := &model.GoalsResponse{
+		Goals:               make([]model.GoalProgress, len(goals)),
+		TotalBalance:        balance.TotalBalance,
+		AverageMonthlySaved: average,
+	}
+	available := max(balance.TotalBalance, 0)
+	var needed model.Money
+	for i, goal := range goals {
+		saved := min(available, goal.Target)
+		available -= saved
+		needed += goal.Target
+
+		p := model.GoalProgress{
+			Goal:      goal,
+			Saved:     saved,
+			Remaining: goal.Target - saved,
+			Percent:   int(saved * 100 / goal.Target),
+			Complete:  saved == goal.Target,
+		}
+		if !p.Complete && average > 0 {
+			shortfall := needed - balance.TotalBalance
+			months := int((shortfall + average - 1) / average)
+			p.ProjectedMonth = addMonths(current, months)
+		}
+		if goal.Deadline != "" {
+			onTrack := p.Complete || (p.ProjectedMonth != "" && p.ProjectedMonth <= goal.Deadline)
+			p.OnTrack = &onTrack
+		}
+		resp.Goals[i] = p
+	}
+	return resp, nil
+}
+
+// averageMonthlySaved is the mean monthly_saved over the most recent
+// goalProjectionMonths months before current, or 0 when there are none.
+func (s *ExpenseService) averageMonthlySaved(ctx context.Context, current string) (model.Money, error) {
+	page, err := s.ListMonths(ctx, goalMonthsPage, "")
+	if err != nil {
+		return 0, err
+	}
+	var total model.Money
+	n := 0
+	for _, m := range page.Months {
+		if m.Month >= current {
+			continue
+		}
+		total += m.MonthlySaved
+		n++
+		if n == goalProjectionMonths {
+			break
+		}
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	return total / model.Money(n), nil
+}
+
+// addMonths returns the month key n months after month.
+func addMonths(month string, n int) string {
+	t, _ := time.Parse("2006-01", month)
+	t = t.AddDate(0, n, 0)
+	return fmt.Sprintf("%04d-%02d", t.Year(), t.Month())
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/testutil"
+)
+
+// =====================================================================
+// Savings goals — progress is never stored: the total balance is shared
+// out across the goals in funding order on every read, and projections
+// come from the average monthly_saved of recent completed months.
+// =====================================================================
+
+var goalsNow = time.Date(2025, time.June, 15, 9, 0, 0, 0, time.UTC)
+
+func createGoal(t *testing.T, svc *ExpenseService, req *model.CreateGoalRequest) *model.Goal {
+	t.Helper()
+	goal, err := svc.CreateGoal(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+	return goal
+}
+
+func goalByName(t *testing.T, resp *model.GoalsResponse, name string) model.GoalProgress {
+	t.Helper()
+	for _, g := range resp.Goals {
+		if g.Name == name {
+			return g
+		}
+	}
+	t.Fatalf("goal %q missing from %+v", name, resp.Goals)
+	return model.GoalProgress{}
+}
+
+func TestCreateGoal_Validation(t *testing.T) {
+	svc, _ := newExpenseService(t, true, false, 0)
+
+	cases := []struct {
+		name string
+		req  model.CreateGoalRequest
+		want error
+	}{
+		{"blank name", model.CreateGoalRequest{Name: "  ", Target: model.Dollars(5)}, ErrGoalNameRequired},
+		{"zero target", model.CreateGoalRequest{Name: "Bike", Target: 0}, ErrInvalidAmount},
+		{"target over ceiling", model.CreateGoalRequest{Name: "Bike", Target: maxAmount + 1}, ErrInvalidAmount},
+		{"bad deadline", model.CreateGoalRequest{Name: "Bike", Target: model.Dollars(5), Deadline: "2025-13"}, ErrInvalidMonth},
+		{"negative priority", model.CreateGoalRequest{Name: "Bike", Target: model.Dollars(5), Priority: -1}, ErrInvalidGoalPriority},
+		{"priority 100", model.CreateGoalRequest{Name: "Bike", Target: model.Dollars(5), Priority: 100}, ErrInvalidGoalPriority},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.CreateGoal(context.Background(), &tc.req)
+			if !errors.Is(err, tc.want) {
+				t.Errorf("err = %v, want %v", err, tc.want)
+			}
+		})
+	}
+
+	goal := createGoal(t, svc, &model.CreateGoalRequest{Name: "  Bike ", Target: model.Dollars(180)})
+	if goal.Name != "Bike" || goal.ID == "" {
+		t.Errorf("created goal = %+v, want trimmed name and an id", goal)
+	}
+}
+
+// The balance funds goals in priority order (unset last), and each goal's
+// projection counts every target ahead of it. The current month is left
+// out of the average: seeded here as a big saver, including it would pull
+// the bike's projection a month earlier.
+func TestListGoals_SharesBalanceInFundingOrder(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	for _, month := range []string{"2025-01", "2025-02", "2025-03", "2025-04", "2025-05"} {
+		testutil.SeedMonth(repo, month, 0, 20, 0, 20)
+	}
+	testutil.SeedMonth(repo, "2025-06", 0, 200, 0, 200)
+	repo.Balance.TotalBalance = model.Dollars(150)
+
+	createGoal(t, svc, &model.CreateGoalRequest{Name: "Game", Target: model.Dollars(40)})
+	createGoal(t, svc, &model.CreateGoalRequest{Name: "Book", Target: model.Dollars(10), Priority: 2})
+	createGoal(t, svc, &model.CreateGoalRequest{Name: "Bike", Target: model.Dollars(180), Priority: 1, Deadline: "2025-08"})
+
+	resp, err := svc.ListGoals(context.Background(), goalsNow)
+	if err != nil {
+		t.Fatalf("ListGoals: %v", err)
+	}
+	if resp.AverageMonthlySaved != model.Dollars(20) || resp.TotalBalance != model.Dollars(150) {
+		t.Errorf("average %v balance %v, want 20 and 150", resp.AverageMonthlySaved, resp.TotalBalance)
+	}
+	order := []string{"Bike", "Book", "Game"}
+	for i, name := range order {
+		if resp.Goals[i].Name != name {
+			t.Fatalf("goals[%d] = %q, want funding order %v", i, resp.Goals[i].Name, order)
+		}
+	}
+
+	bike := goalByName(t, resp, "Bike")
+	if bike.Saved != model.Dollars(150) || bike.Remaining != model.Dollars(30) || bike.Percent != 83 || bike.Complete {
+		t.Errorf("bike = saved %v remaining %v %d%%, want 150, 30, 83%%", bike.Saved, bike.Remaining, bike.Percent)
+	}
+	if bike.ProjectedMonth != "2025-08" || bike.OnTrack == nil || !*bike.OnTrack {
+		t.Errorf("bike projected %q on track %v, want 2025-08 and on track", bike.ProjectedMonth, bike.OnTrack)
+	}
+
+	book := goalByName(t, resp, "Book")
+	if book.Saved != 0 || book.ProjectedMonth != "2025-08" || book.OnTrack != nil {
+		t.Errorf("book = saved %v projected %q on track %v, want 0, 2025-08, unset", book.Saved, book.ProjectedMonth, book.OnTrack)
+	}
+	game := goalByName(t, resp, "Game")
+	if game.ProjectedMonth != "2025-10" {
+		t.Errorf("game projected %q, want 2025-10 (80 short at 20 a month)", game.ProjectedMonth)
+	}
+}
+
+func TestListGoals_CompleteAndUnprojectable(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	// Spending more than the allowance: nothing is saved on average.
+	testutil.SeedMonth(repo, "2025-05", 50, 10, 20, 40)
+	repo.Balance.TotalBalance = model.Dollars(50)
+
+	createGoal(t, svc, &model.CreateGoalRequest{Name: "Ball", Target: model.Dollars(30), Priority: 1, Deadline: "2025-01"})
+	createGoal(t, svc, &model.CreateGoalRequest{Name: "Bike", Target: model.Dollars(180), Priority: 2, Deadline: "2026-01"})
+
+	resp, err := svc.ListGoals(context.Background(), goalsNow)
+	if err != nil {
+		t.Fatalf("ListGoals: %v", err)
+	}
+	ball := goalByName(t, resp, "Ball")
+	if !ball.Complete || ball.Percent != 100 || ball.ProjectedMonth != "" || ball.OnTrack == nil || !*ball.OnTrack {
+		t.Errorf("ball = %+v, want complete, 100%%, no projection, on track past its deadline", ball)
+	}
+	bike := goalByName(t, resp, "Bike")
+	if bike.Saved != model.Dollars(20) || bike.ProjectedMonth != "" || bike.OnTrack == nil || *bike.OnTrack {
+		t.Errorf("bike = %+v, want 20 saved, no projection, off track", bike)
+	}
+}
+
+// A negative balance funds nothing and deepens every shortfall.
+func TestListGoals_NegativeBalance(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	testutil.SeedMonth(repo, "2025-05", -10, 10, 0, 0)
+	repo.Balance.TotalBalance = model.Dollars(-10)
+	createGoal(t, svc, &model.CreateGoalRequest{Name: "Book", Target: model.Dollars(10)})
+
+	resp, err := svc.ListGoals(context.Background(), goalsNow)
+	if err != nil {
+		t.Fatalf("ListGoals: %v", err)
+	}
+	book := resp.Goals[0]
+	if book.Saved != 0 || book.Remaining != model.Dollars(10) || book.ProjectedMonth != "2025-08" {
+		t.Errorf("book = saved %v remaining %v projected %q, want 0, 10, 2025-08", book.Saved, book.Remaining, book.ProjectedMonth)
+	}
+}
+
+func TestUpdateGoal(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	goal := createGoal(t, svc, &model.CreateGoalRequest{Name: "Bike", Target: model.Dollars(180), Deadline: "2025-08"})
+
+	if _, err := svc.UpdateGoal(context.Background(), goal.ID, &model.UpdateGoalRequest{}); !errors.Is(err, ErrNoChanges) {
+		t.Errorf("empty update err = %v, want ErrNoChanges", err)
+	}
+	target := model.Dollars(200)
+	if _, err := svc.UpdateGoal(context.Background(), "nope", &model.UpdateGoalRequest{Target: &target}); !errors.Is(err, ErrGoalNotFound) {
+		t.Errorf("missing goal err = %v, want ErrGoalNotFound", err)
+	}
+	priority := 100
+	if _, err := svc.UpdateGoal(context.Background(), goal.ID, &model.UpdateGoalRequest{Priority: &priority}); !errors.Is(err, ErrInvalidGoalPriority) {
+		t.Errorf("bad priority err = %v, want ErrInvalidGoalPriority", err)
+	}
+
+	noDeadline := ""
+	updated, err := svc.UpdateGoal(context.Background(), goal.ID, &model.UpdateGoalRequest{Target: &target, Deadline: &noDeadline})
+	if err != nil {
+		t.Fatalf("UpdateGoal: %v", err)
+	}
+	stored := repo.Goals[goal.ID]
+	if updated.Target != target || stored.Target != target || stored.Deadline != "" || stored.Name != "Bike" {
+		t.Errorf("stored goal = %+v, want target 200, deadline cleared, name kept", stored)
+	}
+	if !stored.CreatedAt.Equal(goal.CreatedAt) {
+		t.Errorf("created_at changed from %v to %v", goal.CreatedAt, stored.CreatedAt)
+	}
+}
+
+func TestDeleteGoal(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	goal := createGoal(t, svc, &model.CreateGoalRequest{Name: "Bike", Target: model.Dollars(180)})
+
+	if err := svc.DeleteGoal(context.Background(), goal.ID); err != nil {
+		t.Fatalf("DeleteGoal: %v", err)
+	}
+	if len(repo.Goals) != 0 {
+		t.Errorf("goals left = %d, want 0", len(repo.Goals))
+	}
+	if err := svc.DeleteGoal(context.Background(), goal.ID); !errors.Is(err, ErrGoalNotFound) {
+		t.Errorf("second delete err = %v, want ErrGoalNotFound", err)
+	}
+}
@@ -0,0 +1,387 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+)
+
+var (
+	// ErrInstalmentNotFound is returned when an instalment plan id does not
+	// exist. Handler maps to 404.
+	ErrInstalmentNotFound = errors.New("instalment plan not found")
+	// ErrInvalidInstalmentCount is returned when a plan's instalment count
+	// is outside minInstalments-maxInstalments. Handler maps to 400.
+	ErrInvalidInstalmentCount = errors.New("instalment count must be between 2 and 24")
+	// ErrInstalmentSettled is returned when a plan is edited after its last
+	// instalment has been paid — there is nothing left to adjust. Handler
+	// maps to 409.
+	ErrInstalmentSettled = errors.New("instalment plan has no unpaid instalments")
+	// ErrInstalmentTotalTooLow is returned when a new plan total would not
+	// leave at least a cent for each unpaid instalment after what the paid
+	// ones already charged. Handler maps to 400.
+	ErrInstalmentTotalTooLow = errors.New("total does not cover the paid instalments")
+	// ErrInstalmentExpense is returned when the expense API is asked to edit
+	// or delete one instalment of a live plan; the plan owns those rows.
+	// Handler maps to 409.
+	ErrInstalmentExpense = errors.New("expense is an instalment of a plan")
+)
+
+const (
+	minInstalments = 2
+	// maxInstalments bounds a plan. An edit rewrites every unpaid
+	// instalment in one transaction at four items each (row, summary,
+	// mirror, audit entry) plus BALANCE and the plan row, which has to stay
+	// under DynamoDB's 100-item cap.
+	maxInstalments = 24
+)
+
+// splitInstalments divides total over n instalments as evenly as cents
+// allow; the odd cents go to the earliest ones.
+func splitInstalments(total model.Money, n int) []model.Money {
+	share, extra := total/model.Money(n), total%model.Money(n)
+	out := make([]model.Money, n)
+	for i := range out {
+		out[i] = share
+		if model.Money(i) < extra {
+			out[i]++
+		}
+	}
+	return out
+}
+
+// markPaid sets each instalment's computed Paid flag: an instalment is paid
+// once its month has begun.
+func markPaid(plan *model.InstalmentPlan, current string) {
+	for i := range plan.Instalments {
+		plan.Instalments[i].Paid = plan.Instalments[i].Month <= current
+	}
+}
+
+// CreateInstalmentPlan spreads a purchase over consecutive months. Every
+// instalment is booked through addExpense, the same checked path as a
+// manual add, so month creation, the carry chain, category budgets and the
+// overspend rule apply to each one. The whole plan's effect on the carry
+// chain is checked up front; if an instalment is still refused while
+// booking, the ones already booked are removed again and nothing is kept.
+// The plan row is written last, so it never names rows that do not exist.
+func (s *ExpenseService) CreateInstalmentPlan(ctx context.Context, req *model.CreateInstalmentRequest) (*model.InstalmentPlan, error) {
+	if req.Amount <= 0 || req.Amount > maxAmount {
+		return nil, ErrInvalidAmount
+	}
+	if req.Count < minInstalments || req.Count > maxInstalments {
+		return nil, ErrInvalidInstalmentCount
+	}
+	if req.Amount < model.Money(req.Count) {
+		// Some instalment would be zero.
+		return nil, ErrInvalidAmount
+	}
+	description, err := validateDescription(req.Description)
+	if err != nil {
+		return nil, err
+	}
+	if description == "" {
+		description = "Expense"
+	}
+	category, err := validateCategory(req.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	month, firstTime := GetCurrentMonth(), time.Now()
+	if req.Date != "" {
+		month, firstTime, err = s.resolveExpenseTime(req.Date)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	plan := &model.InstalmentPlan{
+		ID:          uuid.New().String()[:8],
+		Total:       req.Amount,
+		Description: description,
+		Category:    category,
+		Version:     1,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	expenses := make([]*model.Expense, req.Count)
+	impulses := make([]monthImpulse, req.Count)
+	for i, amount := range splitInstalments(req.Amount, req.Count) {
+		due := firstTime
+		if i > 0 {
+			due = recurringDueTime(month, firstTime.UTC().Day())
+		}
+		expenses[i] = &model.Expense{
+			SK:           fmt.Sprintf("%s%d#%s", repository.ExpensePrefix, due.UnixNano(), plan.ID),
+			Amount:       amount,
+			Description:  description,
+			Category:     category,
+			CreatedAt:    due,
+			InstalmentID: plan.ID,
+		}
+		plan.Instalments = append(plan.Instalments, model.Instalment{
+			Number:    i + 1,
+			Month:     month,
+			ExpenseID: expenses[i].SK,
+			Amount:    amount,
+		})
+		impulses[i] = monthImpulse{month, -amount}
+		month = GetNextMonth(month)
+	}
+
+	if err := s.ensureCarryChainAffordable(ctx, impulses...); err != nil {
+		return nil, err
+	}
+	for i, e := range expenses {
+		if _, err := s.addExpense(ctx, plan.Instalments[i].Month, e); err != nil {
+			s.unbookInstalments(ctx, plan.Instalments[:i], expenses[:i])
+			return nil, err
+		}
+	}
+	if err := s.repo.CreateInstalmentPlan(ctx, plan); err != nil {
+		s.unbookInstalments(ctx, plan.Instalments, expenses)
+		return nil, err
+	}
+	markPaid(plan, GetCurrentMonth())
+	return plan, nil
+}
+
+// unbookInstalments removes instalments booked by a plan creation that then
+// failed, newest first. It is best effort: the creation's own error is the
+// one reported, and anything left behind is logged. A leftover row carries
+// the id of a plan that was never written, so it is an ordinary expense the
+// user can delete.
+func (s *ExpenseService) unbookInstalments(ctx context.Context, instalments []model.Instalment, expenses []*model.Expense) {
+	for i := len(expenses) - 1; i >= 0; i-- {
+		if err := s.deleteExpense(ctx, instalments[i].Month, expenses[i], nil); err != nil {
+			log.Printf("warn: could not remove instalment %s in %s after a failed plan create: %v", expenses[i].SK, instalments[i].Month, err)
+		}
+	}
+}
+
+// ListInstalmentPlans returns every plan, with its instalments marked paid
+// as of now.
+func (s *ExpenseService) ListInstalmentPlans(ctx context.Context, now time.Time) (*model.InstalmentListResponse, error) {
+	plans, err := s.repo.ListInstalmentPlans(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if plans == nil {
+		plans = []model.InstalmentPlan{}
+	}
+	current := monthOf(now)
+	for i := range plans {
+		markPaid(&plans[i], current)
+	}
+	return &model.InstalmentListResponse{Instalments: plans}, nil
+}
+
+// UpdateInstalmentPlan edits a plan's total, description or category. Paid
+// instalments are history and keep what they were; every unpaid one is
+// rewritten to match in one transaction with the plan row, and a new total
+// is re-split over the unpaid instalments after what the paid ones charged.
+// The months' carry chains are re-propagated after the transaction.
+func (s *ExpenseService) UpdateInstalmentPlan(ctx context.Context, id string, req *model.UpdateInstalmentRequest, now time.Time) (*model.InstalmentPlan, error) {
+	if req.Amount == nil && req.Description == nil && req.Category == nil {
+		return nil, ErrNoChanges
+	}
+	if req.Amount != nil && (*req.Amount <= 0 || *req.Amount > maxAmount) {
+		return nil, ErrInvalidAmount
+	}
+	if req.Description != nil {
+		trimmed, err := validateDescription(*req.Description)
+		if err != nil {
+			return nil, err
+		}
+		req.Description = &trimmed
+	}
+	if req.Category != nil {
+		normalized, err := validateCategory(*req.Category)
+		if err != nil {
+			return nil, err
+		}
+		req.Category = &normalized
+	}
+
+	plan, err := s.repo.GetInstalmentPlan(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil {
+		return nil, ErrInstalmentNotFound
+	}
+	current := monthOf(now)
+	markPaid(plan, current)
+
+	updated := *plan
+	updated.Instalments = append([]model.Instalment(nil), plan.Instalments...)
+	if req.Description != nil {
+		updated.Description = *req.Description
+		if updated.Description == "" {
+			updated.Description = "Expense"
+		}
+	}
+	if req.Category != nil {
+		updated.Category = *req.Category
+	}
+	var unpaid []int
+	var paidTotal model.Money
+	for i, inst := range updated.Instalments {
+		if inst.Paid {
+			paidTotal += inst.Amount
+		} else {
+			unpaid = append(unpaid, i)
+		}
+	}
+	if len(unpaid) == 0 {
+		return nil, ErrInstalmentSettled
+	}
+	if req.Amount != nil {
+		remaining := *req.Amount - paidTotal
+		if remaining < model.Money(len(unpaid)) {
+			return nil, ErrInstalmentTotalTooLow
+		}
+		updated.Total = *req.Amount
+		for k, amount := range splitInstalments(remaining, len(unpaid)) {
+			updated.Instalments[unpaid[k]].Amount = amount
+		}
+	}
+	updated.UpdatedAt = time.Now()
+
+	rewrites := make([]repository.InstalmentRewrite, len(unpaid))
+	impulses := make([]monthImpulse, len(unpaid))
+	for k, i := range unpaid {
+		old, inst := plan.Instalments[i], updated.Instalments[i]
+		rewrites[k] = repository.InstalmentRewrite{
+			Month: inst.Month,
+			Old:   &model.Expense{SK: old.ExpenseID, Amount: old.Amount, Description: plan.Description, Category: plan.Category},
+			New:   &model.Expense{SK: inst.ExpenseID, Amount: inst.Amount, Description: updated.Description, Category: updated.Category, InstalmentID: plan.ID},
+		}
+		impulses[k] = monthImpulse{inst.Month, -(inst.Amount - old.Amount)}
+		if err := s.prepareInstalmentMonth(ctx, inst.Month, plan.Category, updated.Category); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.ensureCarryChainAffordable(ctx, impulses...); err != nil {
+		return nil, err
+	}
+	hardStop, err := s.hardStop(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.AtomicUpdateInstalmentPlan(ctx, plan, &updated, rewrites, hardStop); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrInsufficientBalance):
+			return nil, ErrInsufficientFunds
+		case errors.Is(err, repository.ErrExpenseStateMismatch):
+			return nil, ErrExpenseModified
+		}
+		return nil, err
+	}
+	s.reindexInstalments(ctx, rewrites)
+	if err := s.propagateImpulses(ctx, impulses); err != nil {
+		return nil, err
+	}
+	markPaid(&updated, current)
+	return &updated, nil
+}
+
+// DeleteInstalmentPlan removes a plan and its unpaid instalments in one
+// transaction, refunding their months. Paid instalments stay as ordinary
+// expenses: with the plan gone they are edited like any other.
+func (s *ExpenseService) DeleteInstalmentPlan(ctx context.Context, id string, now time.Time) error {
+	plan, err := s.repo.GetInstalmentPlan(ctx, id)
+	if err != nil {
+		return err
+	}
+	if plan == nil {
+		return ErrInstalmentNotFound
+	}
+	markPaid(plan, monthOf(now))
+
+	var rewrites []repository.InstalmentRewrite
+	var impulses []monthImpulse
+	for _, inst := range plan.Instalments {
+		if inst.Paid {
+			continue
+		}
+		rewrites = append(rewrites, repository.InstalmentRewrite{
+			Month: inst.Month,
+			Old:   &model.Expense{SK: inst.ExpenseID, Amount: inst.Amount, Description: plan.Description, Category: plan.Category},
+		})
+		impulses = append(impulses, monthImpulse{inst.Month, inst.Amount})
+		if err := s.prepareInstalmentMonth(ctx, inst.Month, plan.Category); err != nil {
+			return err
+		}
+	}
+	if err := s.repo.AtomicDeleteInstalmentPlan(ctx, plan, rewrites); err != nil {
+		if errors.Is(err, repository.ErrExpenseStateMismatch) {
+			return ErrExpenseModified
+		}
+		return err
+	}
+	s.reindexInstalments(ctx, rewrites)
+	return s.propagateImpulses(ctx, impulses)
+}
+
+// prepareInstalmentMonth back-fills what the instalment transaction's
+// delta updates need to exist in month: the MONTHLIST mirror and, for a
+// categorized plan, the category_totals map.
+func (s *ExpenseService) prepareInstalmentMonth(ctx context.Context, month string, categories ...string) error {
+	if err := s.repo.EnsureMonthListMirror(ctx, month); err != nil {
+		return err
+	}
+	return s.ensureCategoryTotals(ctx, month, categories...)
+}
+
+// reindexInstalments brings the search index in line with a committed plan
+// transaction, which has no room for the index entries itself. It is best
+// effort: searches re-check every hit against the expense row, so a failure
+// can only leave a rewritten instalment unfindable under its new words,
+// and that is logged.
+func (s *ExpenseService) reindexInstalments(ctx context.Context, rewrites []repository.InstalmentRewrite) {
+	for _, w := range rewrites {
+		if err := s.repo.ReindexExpense(ctx, w.Month, w.Old, w.Month, w.New); err != nil {
+			log.Printf("warn: could not reindex instalment %s in %s: %v", w.Old.SK, w.Month, err)
+		}
+	}
+}
+
+// propagateImpulses ripples each month's ending-balance change through the
+// months after it. Each call composes with the others, so one plan
+// transaction touching several months re-chains exactly like that many
+// single-expense edits.
+func (s *ExpenseService) propagateImpulses(ctx context.Context, impulses []monthImpulse) error {
+	for _, imp := range impulses {
+		if err := s.propagateToLaterMonths(ctx, imp.month, imp.delta); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureNotInstalment refuses an expense-API edit of one instalment of a
+// live plan: the plan records each instalment's amount, and an edit behind
+// its back would make the plan's next rewrite fail its amount lock. Once
+// the plan is deleted its remaining rows are ordinary expenses.
+func (s *ExpenseService) ensureNotInstalment(ctx context.Context, e *model.Expense) error {
+	if e.InstalmentID == "" {
+		return nil
+	}
+	plan, err := s.repo.GetInstalmentPlan(ctx, e.InstalmentID)
+	if err != nil {
+		return err
+	}
+	if plan != nil {
+		return ErrInstalmentExpense
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/testutil"
+)
+
+// =====================================================================
+// Instalment plans — a purchase split over consecutive months, each
+// instalment booked through the checked add path. Edits and deletes move
+// every unpaid instalment in one transaction and leave paid ones alone.
+// =====================================================================
+
+func createInstalments(t *testing.T, svc *ExpenseService, req *model.CreateInstalmentRequest) *model.InstalmentPlan {
+	t.Helper()
+	plan, err := svc.CreateInstalmentPlan(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateInstalmentPlan: %v", err)
+	}
+	return plan
+}
+
+// instalmentExpense returns the EXP# row behind a plan's n-th instalment.
+func instalmentExpense(t *testing.T, repo *testutil.FakeRepo, plan *model.InstalmentPlan, n int) *model.Expense {
+	t.Helper()
+	inst := plan.Instalments[n]
+	e := repo.Expenses[testutil.ExpenseKey(inst.Month, inst.ExpenseID)]
+	if e == nil {
+		t.Fatalf("instalment %d (%s %s) not booked", n+1, inst.Month, inst.ExpenseID)
+	}
+	return e
+}
+
+// midMonth is noon on the 15th of the month monthsAgo months back, as a
+// "now" for paid/unpaid decisions.
+func midMonth(monthsAgo int) time.Time {
+	_, date := pastMonthDay(monthsAgo, 15)
+	t, _ := time.Parse("2006-01-02", date)
+	return t.Add(12 * time.Hour)
+}
+
+func TestSplitInstalments(t *testing.T) {
+	got := splitInstalments(model.Dollars(10), 3)
+	want := []model.Money{334, 333, 333}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("split = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestCreateInstalmentPlan_Validation(t *testing.T) {
+	svc, _ := newExpenseService(t, true, false, 0)
+
+	cases := []struct {
+		name string
+		req  model.CreateInstalmentRequest
+		want error
+	}{
+		{"zero amount", model.CreateInstalmentRequest{Amount: 0, Count: 3}, ErrInvalidAmount},
+		{"one instalment", model.CreateInstalmentRequest{Amount: model.Dollars(10), Count: 1}, ErrInvalidInstalmentCount},
+		{"too many", model.CreateInstalmentRequest{Amount: model.Dollars(10), Count: 25}, ErrInvalidInstalmentCount},
+		{"cent per instalment", model.CreateInstalmentRequest{Amount: 2, Count: 3}, ErrInvalidAmount},
+		{"bad date", model.CreateInstalmentRequest{Amount: model.Dollars(10), Count: 3, Date: "2025-13-01"}, ErrInvalidDate},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.CreateInstalmentPlan(context.Background(), &tc.req)
+			if !errors.Is(err, tc.want) {
+				t.Errorf("err = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestCreateInstalmentPlan_BooksConsecutiveMonths(t *testing.T) {
+	svc, repo := newExpenseService(t, true, true, 0)
+	first, date := pastMonthDay(2, 10)
+	testutil.SeedMonth(repo, first, 0, 100, 0, 100)
+	repo.Balance.TotalBalance = model.Dollars(100)
+
+	plan := createInstalments(t, svc, &model.CreateInstalmentRequest{
+		Amount: model.Dollars(10), Description: "Bike", Category: "Toys", Count: 3, Date: date,
+	})
+
+	months := []string{first, GetNextMonth(first), GetCurrentMonth()}
+	wantAmounts := []float64{3.34, 3.33, 3.33}
+	for i, month := range months {
+		inst := plan.Instalments[i]
+		if inst.Month != month || inst.Number != i+1 || !inst.Paid {
+			t.Errorf("instalment %d = %+v, want paid #%d in %s", i+1, inst, i+1, month)
+		}
+		e := instalmentExpense(t, repo, plan, i)
+		if e.Amount != model.Dollars(wantAmounts[i]) || e.InstalmentID != plan.ID || e.CreatedAt.UTC().Day() != 10 {
+			t.Errorf("%s expense = %+v, want %v on the 10th tagged %s", month, e, wantAmounts[i], plan.ID)
+		}
+		assertCategoryTotal(t, repo, month, "toys", wantAmounts[i])
+	}
+	assertLedgerConsistent(t, repo, months...)
+	if repo.Balance.TotalBalance != model.Dollars(90) {
+		t.Errorf("balance = %v, want 90", repo.Balance.TotalBalance)
+	}
+	if repo.Instalments[plan.ID] == nil {
+		t.Error("plan row not written")
+	}
+}
+
+// Under hard-stop without carry-over, a later month with no money refuses
+// its instalment; the ones already booked are taken back out.
+func TestCreateInstalmentPlan_RefusedInstalmentRollsBack(t *testing.T) {
+	svc, repo := newExpenseService(t, false, false, 0)
+	first, date := pastMonthDay(1, 10)
+	testutil.SeedMonth(repo, first, 0, 50, 0, 50)
+
+	_, err := svc.CreateInstalmentPlan(context.Background(), &model.CreateInstalmentRequest{
+		Amount: model.Dollars(20), Count: 2, Date: date,
+	})
+	if !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("err = %v, want ErrInsufficientFunds", err)
+	}
+	if n := len(expensesIn(repo, first)); n != 0 {
+		t.Errorf("%s kept %d expenses, want 0", first, n)
+	}
+	if repo.Months[first].EndingBalance != model.Dollars(50) {
+		t.Errorf("%s ending = %v, want 50 restored", first, repo.Months[first].EndingBalance)
+	}
+	if len(repo.Instalments) != 0 {
+		t.Errorf("%d plans stored, want 0", len(repo.Instalments))
+	}
+}
+
+func TestUpdateInstalmentPlan_ResplitsUnpaid(t *testing.T) {
+	svc, repo := newExpenseService(t, true, true, 0)
+	first, date := pastMonthDay(3, 10)
+	testutil.SeedMonth(repo, first, 0, 100, 0, 100)
+	repo.Balance.TotalBalance = model.Dollars(100)
+	plan := createInstalments(t, svc, &model.CreateInstalmentRequest{
+		Amount: model.Dollars(40), Description: "Bike", Category: "Toys", Count: 4, Date: date,
+	})
+
+	// As of two months ago, the first two instalments are paid.
+	amount, category := model.Dollars(30), "Games"
+	updated, err := svc.UpdateInstalmentPlan(context.Background(), plan.ID,
+		&model.UpdateInstalmentRequest{Amount: &amount, Category: &category}, midMonth(2))
+	if err != nil {
+		t.Fatalf("UpdateInstalmentPlan: %v", err)
+	}
+	if updated.Total != amount || !updated.Instalments[1].Paid || updated.Instalments[2].Paid {
+		t.Errorf("updated plan = %+v, want total 30 with two paid", updated)
+	}
+
+	for i, want := range []float64{10, 10, 5, 5} {
+		e := instalmentExpense(t, repo, plan, i)
+		wantCategory := "games"
+		if i < 2 {
+			wantCategory = "toys"
+		}
+		if e.Amount != model.Dollars(want) || e.Category != wantCategory {
+			t.Errorf("instalment %d = %v %q, want %v %q", i+1, e.Amount, e.Category, want, wantCategory)
+		}
+	}
+	assertCategoryTotal(t, repo, plan.Instalments[2].Month, "games", 5)
+	assertCategoryTotal(t, repo, plan.Instalments[2].Month, "toys", 0)
+	months := []string{first, plan.Instalments[1].Month, plan.Instalments[2].Month, plan.Instalments[3].Month}
+	assertLedgerConsistent(t, repo, months...)
+	if repo.Balance.TotalBalance != model.Dollars(70) {
+		t.Errorf("balance = %v, want 70", repo.Balance.TotalBalance)
+	}
+	if repo.Instalments[plan.ID].Version != 2 {
+		t.Errorf("version = %d, want 2", repo.Instalments[plan.ID].Version)
+	}
+}
+
+func TestUpdateInstalmentPlan_Refusals(t *testing.T) {
+	svc, repo := newExpenseService(t, true, true, 0)
+	first, date := pastMonthDay(3, 10)
+	testutil.SeedMonth(repo, first, 0, 100, 0, 100)
+	repo.Balance.TotalBalance = model.Dollars(100)
+	plan := createInstalments(t, svc, &model.CreateInstalmentRequest{Amount: model.Dollars(40), Count: 4, Date: date})
+	ctx := context.Background()
+
+	// Two paid instalments charged 20; 20 leaves nothing for the other two.
+	low := model.Dollars(20)
+	if _, err := svc.UpdateInstalmentPlan(ctx, plan.ID, &model.UpdateInstalmentRequest{Amount: &low}, midMonth(2)); !errors.Is(err, ErrInstalmentTotalTooLow) {
+		t.Errorf("low total err = %v, want ErrInstalmentTotalTooLow", err)
+	}
+
+	description := "Scooter"
+	if _, err := svc.UpdateInstalmentPlan(ctx, plan.ID, &model.UpdateInstalmentRequest{Description: &description}, time.Now()); !errors.Is(err, ErrInstalmentSettled) {
+		t.Errorf("settled err = %v, want ErrInstalmentSettled", err)
+	}
+	if _, err := svc.UpdateInstalmentPlan(ctx, "missing", &model.UpdateInstalmentRequest{Description: &description}, time.Now()); !errors.Is(err, ErrInstalmentNotFound) {
+		t.Errorf("missing err = %v, want ErrInstalmentNotFound", err)
+	}
+
+	// An instalment row that no longer matches the plan fails the amount
+	// lock, and nothing is rewritten.
+	instalmentExpense(t, repo, plan, 3).Amount = model.Dollars(11)
+	if _, err := svc.UpdateInstalmentPlan(ctx, plan.ID, &model.UpdateInstalmentRequest{Description: &description}, midMonth(2)); !errors.Is(err, ErrExpenseModified) {
+		t.Errorf("stale err = %v, want ErrExpenseModified", err)
+	}
+	if e := instalmentExpense(t, repo, plan, 2); e.Description == description {
+		t.Error("instalment 3 was rewritten despite the failed lock")
+	}
+}
+
+func TestDeleteInstalmentPlan_RefundsUnpaidKeepsPaid(t *testing.T) {
+	svc, repo := newExpenseService(t, true, true, 0)
+	first, date := pastMonthDay(3, 10)
+	testutil.SeedMonth(repo, first, 0, 100, 0, 100)
+	repo.Balance.TotalBalance = model.Dollars(100)
+	plan := createInstalments(t, svc, &model.CreateInstalmentRequest{
+		Amount: model.Dollars(40), Category: "Toys", Count: 4, Date: date,
+	})
+	ctx := context.Background()
+
+	if err := svc.DeleteInstalmentPlan(ctx, plan.ID, midMonth(2)); err != nil {
+		t.Fatalf("DeleteInstalmentPlan: %v", err)
+	}
+	if repo.Instalments[plan.ID] != nil {
+		t.Error("plan row still stored")
+	}
+	for i, inst := range plan.Instalments {
+		_, kept := repo.Expenses[testutil.ExpenseKey(inst.Month, inst.ExpenseID)]
+		if kept != (i < 2) {
+			t.Errorf("instalment %d kept = %v, want %v", i+1, kept, i < 2)
+		}
+	}
+	assertCategoryTotal(t, repo, plan.Instalments[3].Month, "toys", 0)
+	months := []string{first, plan.Instalments[1].Month, plan.Instalments[2].Month, plan.Instalments[3].Month}
+	assertLedgerConsistent(t, repo, months...)
+	if repo.Balance.TotalBalance != model.Dollars(80) {
+		t.Errorf("balance = %v, want 80", repo.Balance.TotalBalance)
+	}
+
+	// With the plan gone, the paid rows are ordinary expenses.
+	if err := svc.DeleteExpense(ctx, first, plan.Instalments[0].ExpenseID); err != nil {
+		t.Errorf("DeleteExpense on a leftover instalment: %v", err)
+	}
+	if err := svc.DeleteInstalmentPlan(ctx, plan.ID, time.Now()); !errors.Is(err, ErrInstalmentNotFound) {
+		t.Errorf("second delete err = %v, want ErrInstalmentNotFound", err)
+	}
+}
+
+// The plan owns its rows: the expense API cannot change one behind it.
+func TestExpenseAPI_RefusesLiveInstalment(t *testing.T) {
+	svc, repo := newExpenseService(t, true, true, 0)
+	first, date := pastMonthDay(1, 10)
+	testutil.SeedMonth(repo, first, 0, 100, 0, 100)
+	plan := createInstalments(t, svc, &model.CreateInstalmentRequest{Amount: model.Dollars(10), Count: 2, Date: date})
+	ctx := context.Background()
+	inst := plan.Instalments[0]
+
+	amount := model.Dollars(1)
+	if _, err := svc.UpdateExpense(ctx, inst.Month, inst.ExpenseID, &model.UpdateExpenseRequest{Amount: &amount}); !errors.Is(err, ErrInstalmentExpense) {
+		t.Errorf("update err = %v, want ErrInstalmentExpense", err)
+	}
+	if err := svc.DeleteExpense(ctx, inst.Month, inst.ExpenseID); !errors.Is(err, ErrInstalmentExpense) {
+		t.Errorf("delete err = %v, want ErrInstalmentExpense", err)
+	}
+	if _, ok := repo.Expenses[testutil.ExpenseKey(inst.Month, inst.ExpenseID)]; !ok {
+		t.Error("instalment removed through the expense API")
+	}
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+)
+
+// InterestPolicy is the instance's monthly interest on savings: each month
+// is credited RateBasisPoints (hundredths of a percent, so 100 is 1%) of the
+// balance it carried in from the month before, rounded down to the cent.
+// A carried balance below MinBalance earns nothing, and a positive Cap
+// bounds one month's interest. The zero policy pays no interest.
+type InterestPolicy struct {
+	RateBasisPoints int64
+	MinBalance      model.Money
+	Cap             model.Money
+}
+
+// interestOn is the interest a month that carried in balance earns.
+func (p InterestPolicy) interestOn(balance model.Money) model.Money {
+	if p.RateBasisPoints <= 0 || balance <= 0 || balance < p.MinBalance {
+		return 0
+	}
+	interest := balance * model.Money(p.RateBasisPoints) / 10000
+	if p.Cap > 0 && interest > p.Cap {
+		interest = p.Cap
+	}
+	return interest
+}
+
+// describe is the description of the interest credit earned on balance.
+func (p InterestPolicy) describe(balance model.Money) string {
+	// Basis points print as a percentage through Money's decimal form:
+	// 125 → "1.25".
+	return fmt.Sprintf("Interest at %s%% on %s", model.Money(p.RateBasisPoints), balance)
+}
+
+// SetInterestPolicy sets the monthly interest paid on carried balances. A
+// policy with no positive rate turns interest off.
+func (s *ExpenseService) SetInterestPolicy(p InterestPolicy) {
+	s.interest = p
+}
+
+// interestEnabled reports whether months earn interest. Without carry-over
+// no balance is carried in, so there is nothing to pay interest on.
+func (s *ExpenseService) interestEnabled(ctx context.Context) (bool, error) {
+	if s.interest.RateBasisPoints <= 0 {
+		return false, nil
+	}
+	return s.carriesOver(ctx)
+}
+
+// creditInterest credits a newly opened month its interest on the balance
+// it carried in (summary.StartingBalance), as an itemized funds credit under
+// InterestFundSK, and returns the amount credited. The credit is written
+// even when it is zero: its presence records that the month earns interest,
+// so reconcileInterest can re-price it if an earlier month changes.
+//
+// It is idempotent. A month that already has its interest credit, because a
+// concurrent request or an earlier call got there first, is left alone and
+// 0 is returned; the caller has nothing more to propagate.
+func (s *ExpenseService) creditInterest(ctx context.Context, summary *model.MonthSummary) (model.Money, error) {
+	if enabled, err := s.interestEnabled(ctx); err != nil || !enabled {
+		return 0, err
+	}
+	existing, err := s.repo.GetFundEntry(ctx, summary.Month, repository.InterestFundSK)
+	if err != nil {
+		return 0, err
+	}
+	if existing != nil {
+		return 0, nil
+	}
+	if err := s.repo.EnsureMonthListMirror(ctx, summary.Month); err != nil {
+		return 0, err
+	}
+	amount := s.interest.interestOn(summary.StartingBalance)
+	entry := &model.FundEntry{
+		SK:          repository.InterestFundSK,
+		Amount:      amount,
+		Description: s.interest.describe(summary.StartingBalance),
+		Kind:        model.FundKindInterest,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if err := s.repo.AtomicAddFunds(ctx, summary.Month, amount, entry); err != nil {
+		if errors.Is(err, repository.ErrExpenseStateMismatch) {
+			// The credit was written since the read above, or the month
+			// was deleted; either way this call has credited nothing.
+			return 0, nil
+		}
+		return 0, err
+	}
+	return amount, nil
+}
+
+// interestCredited returns the amount of month's interest credit, 0 when it
+// has none.
+func (s *ExpenseService) interestCredited(ctx context.Context, month string) (model.Money, error) {
+	entry, err := s.repo.GetFundEntry(ctx, month, repository.InterestFundSK)
+	if err != nil || entry == nil {
+		return 0, err
+	}
+	return entry.Amount, nil
+}
+
+// interestRepricing is how far reconcileInterest will move month's interest
+// credit once the balance it carries in shifts by carriedIn: 0 when interest
+// is off or the month has no credit.
+func (s *ExpenseService) interestRepricing(ctx context.Context, summary *model.MonthSummary, carriedIn model.Money) (model.Money, error) {
+	if carriedIn == 0 {
+		return 0, nil
+	}
+	if enabled, err := s.interestEnabled(ctx); err != nil || !enabled {
+		return 0, err
+	}
+	entry, err := s.repo.GetFundEntry(ctx, summary.Month, repository.InterestFundSK)
+	if err != nil || entry == nil {
+		return 0, err
+	}
+	return s.interest.interestOn(summary.StartingBalance+carriedIn) - entry.Amount, nil
+}
+
+// reconcileInterest re-prices the interest credit of each of months, whose
+// carried balances have just moved, ascending and all with a canonical row.
+// The balance a month carries in fixes its interest, so when an earlier month
+// is edited the later months' credits are recomputed from their new starting
+// balances; a credit that changes moves its month's ending balance and so is
+// propagated to the months after it before they are priced in turn.
+//
+// Only months that have an interest credit are re-priced. One without a
+// credit was opened while interest was off, or had its credit deleted by a
+// parent, and does not start earning because an earlier month changed.
+//
+// A lowered credit is written without a balance check: the change that
+// lowers it was refused up front if it could not be afforded, as
+// ensureCarryChainAffordable prices every re-priced credit into its walk
+// of the chain (interestRepricing).
+func (s *ExpenseService) reconcileInterest(ctx context.Context, months []string) error {
+	if enabled, err := s.interestEnabled(ctx); err != nil || !enabled {
+		return err
+	}
+	for i, m := range months {
+		entry, err := s.repo.GetFundEntry(ctx, m, repository.InterestFundSK)
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			continue
+		}
+		summary, err := s.repo.GetMonthSummary(ctx, m)
+		if err != nil {
+			return err
+		}
+		if summary == nil {
+			continue
+		}
+		want := s.interest.interestOn(summary.StartingBalance)
+		delta := want - entry.Amount
+		if delta == 0 {
+			continue
+		}
+		updated := *entry
+		updated.Amount = want
+		updated.Description = s.interest.describe(summary.StartingBalance)
+		if err := s.repo.AtomicUpdateFundEntry(ctx, m, entry, &updated, false); err != nil {
+			return err
+		}
+		if rest := months[i+1:]; len(rest) > 0 {
+			if err := s.repo.PropagateLaterMonthDeltas(ctx, rest, delta); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+	"github.com/vppillai/passbook/backend/internal/testutil"
+)
+
+// =====================================================================
+// Interest — each new month is credited interest on the balance it
+// carried in, as an itemized funds credit that follows later edits.
+// =====================================================================
+
+// newInterestService seeds January ending on 100 and pays 10% interest.
+func newInterestService(t *testing.T, p InterestPolicy) (*ExpenseService, *testutil.FakeRepo) {
+	t.Helper()
+	svc, repo := newExpenseService(t, false, true, 100)
+	svc.SetInterestPolicy(p)
+	testutil.SeedMonth(repo, "2026-01", 0, 100, 0, 100)
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(100)}
+	return svc, repo
+}
+
+func interestEntry(t *testing.T, repo *testutil.FakeRepo, month string) *model.FundEntry {
+	t.Helper()
+	entry, err := repo.GetFundEntry(context.Background(), month, repository.InterestFundSK)
+	if err != nil || entry == nil {
+		t.Fatalf("interest entry for %s = %+v, %v, want one", month, entry, err)
+	}
+	return entry
+}
+
+func TestInterest_CreditedOnceWhenAMonthOpens(t *testing.T) {
+	svc, repo := newInterestService(t, InterestPolicy{RateBasisPoints: 1000})
+	ctx := context.Background()
+
+	resp, err := svc.CreateMonth(ctx, "2026-02")
+	if err != nil {
+		t.Fatalf("CreateMonth: %v", err)
+	}
+	if resp.Summary.AllowanceAdded != model.Dollars(110) || resp.Summary.EndingBalance != model.Dollars(210) {
+		t.Errorf("summary = %+v, want 100 allowance + 10 interest on 100", resp.Summary)
+	}
+	if resp.TotalBalance != model.Dollars(210) {
+		t.Errorf("balance = %v, want 210", resp.TotalBalance)
+	}
+	entry := interestEntry(t, repo, "2026-02")
+	if entry.Amount != model.Dollars(10) || entry.Kind != model.FundKindInterest || entry.Description != "Interest at 10% on 100" {
+		t.Errorf("entry = %+v, want a 10 interest credit", entry)
+	}
+
+	if _, err := svc.CreateMonth(ctx, "2026-02"); !errors.Is(err, ErrMonthExists) {
+		t.Fatalf("second CreateMonth err = %v, want ErrMonthExists", err)
+	}
+	if repo.Balance.TotalBalance != model.Dollars(210) {
+		t.Errorf("balance after a repeat = %v, want no second credit", repo.Balance.TotalBalance)
+	}
+}
+
+func TestInterest_MinimumAndCap(t *testing.T) {
+	svc, repo := newInterestService(t, InterestPolicy{RateBasisPoints: 1000, MinBalance: model.Dollars(150), Cap: model.Dollars(15)})
+	ctx := context.Background()
+
+	// 100 carried in is below the minimum: a zero credit.
+	if _, err := svc.CreateMonth(ctx, "2026-02"); err != nil {
+		t.Fatalf("CreateMonth: %v", err)
+	}
+	if entry := interestEntry(t, repo, "2026-02"); entry.Amount != 0 {
+		t.Errorf("February interest = %v, want none below the minimum", entry.Amount)
+	}
+	// 200 carried in earns 20, capped at 15.
+	resp, err := svc.CreateMonth(ctx, "2026-03")
+	if err != nil {
+		t.Fatalf("CreateMonth: %v", err)
+	}
+	if entry := interestEntry(t, repo, "2026-03"); entry.Amount != model.Dollars(15) {
+		t.Errorf("March interest = %v, want the 15 cap", entry.Amount)
+	}
+	if resp.Summary.EndingBalance != model.Dollars(315) {
+		t.Errorf("March ending = %v, want 200 + 100 + 15", resp.Summary.EndingBalance)
+	}
+}
+
+func TestInterest_FollowsEditsToEarlierMonths(t *testing.T) {
+	svc, repo := newInterestService(t, InterestPolicy{RateBasisPoints: 1000})
+	ctx := context.Background()
+	for _, m := range []string{"2026-02", "2026-03"} {
+		if _, err := svc.CreateMonth(ctx, m); err != nil {
+			t.Fatalf("CreateMonth(%s): %v", m, err)
+		}
+	}
+	// February: 100 in, 10 interest, 210 out. March: 210 in, 21, 331 out.
+	if repo.Balance.TotalBalance != model.Dollars(331) {
+		t.Fatalf("balance = %v, want 331", repo.Balance.TotalBalance)
+	}
+
+	// January ends on 200 instead: February earns 20 and ends on 320, so
+	// March carries in 320 and earns 32.
+	if _, err := svc.AddFunds(ctx, "2026-01", model.Dollars(100), "Birthday"); err != nil {
+		t.Fatalf("AddFunds: %v", err)
+	}
+	if got := interestEntry(t, repo, "2026-02").Amount; got != model.Dollars(20) {
+		t.Errorf("February interest = %v, want 20", got)
+	}
+	if got := interestEntry(t, repo, "2026-03").Amount; got != model.Dollars(32) {
+		t.Errorf("March interest = %v, want 32", got)
+	}
+	if got := repo.Months["2026-03"]; got.StartingBalance != model.Dollars(320) || got.EndingBalance != model.Dollars(452) {
+		t.Errorf("March = %+v, want 320 -> 452", got)
+	}
+	if got := repo.MonthList["2026-03"]; got.EndingBalance != model.Dollars(452) {
+		t.Errorf("March mirror ending = %v, want 452", got.EndingBalance)
+	}
+	if repo.Balance.TotalBalance != model.Dollars(452) {
+		t.Errorf("balance = %v, want 452", repo.Balance.TotalBalance)
+	}
+
+	report, err := svc.VerifyLedger(ctx)
+	if err != nil {
+		t.Fatalf("VerifyLedger: %v", err)
+	}
+	if len(report.Issues) != 0 {
+		t.Errorf("ledger issues = %v, want none", ledgerFields(report))
+	}
+}
+
+// Lowering an earlier month lowers the interest of every month after it, and
+// those smaller credits are written without a balance check: the guard has
+// to count them before it lets the change through.
+func TestInterest_LoweredCreditsAreGuarded(t *testing.T) {
+	svc, repo := newInterestService(t, InterestPolicy{RateBasisPoints: 1000})
+	ctx := context.Background()
+	for _, m := range []string{"2026-02", "2026-03"} {
+		if _, err := svc.CreateMonth(ctx, m); err != nil {
+			t.Fatalf("CreateMonth(%s): %v", m, err)
+		}
+	}
+	// March spends down to 95, exactly what a 95 expense in January takes
+	// from it — before February and March each lose interest on it.
+	march := repo.Months["2026-03"]
+	march.TotalExpenses = model.Dollars(236)
+	march.EndingBalance = model.Dollars(95)
+	repo.MonthList["2026-03"].TotalExpenses = march.TotalExpenses
+	repo.MonthList["2026-03"].EndingBalance = march.EndingBalance
+	repo.Balance.TotalBalance = march.EndingBalance
+
+	var insufficient *InsufficientFundsError
+	if _, err := addOn(t, svc, "2026-01-15", 95); !errors.As(err, &insufficient) {
+		t.Fatalf("AddExpense err = %v, want InsufficientFundsError", err)
+	}
+	if got := interestEntry(t, repo, "2026-03").Amount; got != model.Dollars(21) {
+		t.Errorf("March interest = %v, want it untouched at 21", got)
+	}
+
+	// 75 leaves room for the interest it costs: February earns 2.50, March
+	// 12.75 on the 127.50 it carries in, and ends on 4.25.
+	if _, err := addOn(t, svc, "2026-01-15", 75); err != nil {
+		t.Fatalf("AddExpense: %v", err)
+	}
+	if got := repo.Months["2026-03"].EndingBalance; got != model.Dollars(4.25) {
+		t.Errorf("March ending = %v, want 4.25", got)
+	}
+}
+
+func TestInterest_AutoCreatedMonthStillGetsItsAllowance(t *testing.T) {
+	svc, repo := newExpenseService(t, false, true, 100)
+	svc.SetInterestPolicy(InterestPolicy{RateBasisPoints: 1000})
+	ctx := context.Background()
+	month := time.Now().UTC().Format("2006-01")
+	testutil.SeedMonth(repo, GetPreviousMonth(month), 0, 100, 0, 100)
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(100)}
+
+	// The expense opens the month at $0 allowance; it still earns interest.
+	if _, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(5), Description: "Snack"}); err != nil {
+		t.Fatalf("AddExpense: %v", err)
+	}
+	if got := repo.Months[month]; got.AllowanceAdded != model.Dollars(10) || got.EndingBalance != model.Dollars(105) {
+		t.Fatalf("month = %+v, want 10 interest and 100 + 10 - 5", got)
+	}
+
+	resp, err := svc.CreateMonth(ctx, month)
+	if err != nil {
+		t.Fatalf("CreateMonth: %v", err)
+	}
+	if resp.Summary.AllowanceAdded != model.Dollars(110) || resp.Summary.EndingBalance != model.Dollars(205) {
+		t.Errorf("summary = %+v, want the allowance on top of the interest", resp.Summary)
+	}
+	if got := interestEntry(t, repo, month).Amount; got != model.Dollars(10) {
+		t.Errorf("interest = %v, want it credited once", got)
+	}
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+)
+
+// ErrLedgerModified is returned by RepairLedger when the app changed a row
+// between the check and its repair. The rows repaired before it stay
+// repaired and the changed one is untouched; the ledger command asks for
+// another run, which starts from a fresh check.
+var ErrLedgerModified = errors.New("ledger modified during repair")
+
+// ledgerMonth is one month as the check sees it: the canonical row, the
+// values the carry chain derives for it and where the two disagree.
+type ledgerMonth struct {
+	stored   *model.MonthSummary
+	expected model.MonthSummary
+	issues   []model.LedgerIssue
+}
+
+// VerifyLedger checks every month against its expense rows and the carry
+// chain, the same invariants `add-data.sh audit` checks but computed by the
+// code that maintains them:
+//
+//  1. total_expenses is the sum of the month's expense rows
+//  2. starting_balance is the previous month's ending_balance with carry-over
+//     on, 0 with it off
+//  3. ending_balance is starting_balance + allowance_added - total_expenses
+//  4. BALANCE is the sum of allowance_added - total_expenses, which holds in
+//     both carry modes
+//  5. every MONTHLIST mirror matches its canonical row, none missing or
+//     orphaned
+//  6. earnings_added is the sum of the month's chore rewards (funds entries
+//     of kind earning), and no more than allowance_added
+//  7. the month's funds entries sum to allowance_added less its base
+//     allowance, on months that record one
+//
+// Expected values chain from the expected, not the stored, previous month,
+// so one drifted month reports every month its drift has to be carried
+// through. It only reads.
+func (s *ExpenseService) VerifyLedger(ctx context.Context) (*model.LedgerReport, error) {
+	months, orphans, balance, expectedBalance, err := s.checkLedger(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ledgerReport(months, orphans, balance, expectedBalance), nil
+}
+
+// RepairLedger runs VerifyLedger's check and writes the expected values over
+// every discrepancy it found: each drifted month and its mirror in one
+// journaled transaction, then the orphaned mirrors, then BALANCE. Every
+// write is conditioned on the row still holding what the check read, so a
+// concurrent app write is never overwritten; it stops with
+// ErrLedgerModified instead. allowance_added and the funds entries are never
+// rewritten — they are the record of money granted and nothing else derives
+// them — so the issues that would take that are left Manual.
+func (s *ExpenseService) RepairLedger(ctx context.Context) (*model.LedgerReport, error) {
+	months, orphans, balance, expectedBalance, err := s.checkLedger(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := ledgerReport(months, orphans, balance, expectedBalance)
+
+	for _, m := range months {
+		if !slices.ContainsFunc(m.issues, func(issue model.LedgerIssue) bool { return !issue.Manual }) {
+			continue
+		}
+		fixed := *m.stored
+		fixed.TotalExpenses = m.expected.TotalExpenses
+		fixed.StartingBalance = m.expected.StartingBalance
+		fixed.EndingBalance = m.expected.EndingBalance
+		fixed.EarningsAdded = m.expected.EarningsAdded
+		if err := s.repo.RepairMonthSummary(ctx, m.stored, &fixed); err != nil {
+			return nil, ledgerRepairErr(err)
+		}
+	}
+	for _, month := range orphans {
+		if err := s.repo.DeleteOrphanMonthListMirror(ctx, month.Month); err != nil {
+			return nil, ledgerRepairErr(err)
+		}
+	}
+	if balance != expectedBalance {
+		if err := s.repo.RepairBalance(ctx, balance, expectedBalance); err != nil {
+			return nil, ledgerRepairErr(err)
+		}
+	}
+
+	report.Repaired = true
+	return report, nil
+}
+
+func ledgerRepairErr(err error) error {
+	if errors.Is(err, repository.ErrLedgerRowChanged) {
+		return ErrLedgerModified
+	}
+	return err
+}
+
+// checkLedger reads the whole ledger and derives what it should hold: the
+// months ascending with their issues, the orphaned mirrors, and the stored
+// and expected global balance.
+//
+// The month set comes from the canonical rows (ListAllMonthsLegacy), not
+// from ListMonths: the mirrors are one of the things being checked, and a
+// missing mirror would otherwise hide its month from the check altogether.
+func (s *ExpenseService) checkLedger(ctx context.Context) ([]*ledgerMonth, []model.MonthSummary, model.Money, model.Money, error) {
+	carry, err := s.carriesOver(ctx)
+	if err != nil {
+		return nil, nil, 0, 0, err
+	}
+	canonical, err := s.repo.ListAllMonthsLegacy(ctx)
+	if err != nil {
+		return nil, nil, 0, 0, err
+	}
+	sort.Slice(canonical, func(i, j int) bool { return canonical[i].Month < canonical[j].Month })
+
+	mirrors := make(map[string]model.MonthSummary)
+	var cursor map[string]types.AttributeValue
+	for {
+		page, lastKey, err := s.repo.ListMonths(ctx, monthsAfterPageSize, cursor)
+		if err != nil {
+			return nil, nil, 0, 0, err
+		}
+		for _, m := range page {
+			mirrors[m.Month] = m
+		}
+		if lastKey == nil {
+			break
+		}
+		cursor = lastKey
+	}
+
+	months := make([]*ledgerMonth, 0, len(canonical))
+	var carried, expectedBalance model.Money
+	for i := range canonical {
+		stored := &canonical[i]
+		total, err := s.sumExpenses(ctx, stored.Month)
+		if err != nil {
+			return nil, nil, 0, 0, err
+		}
+
+		m := &ledgerMonth{stored: stored, expected: *stored}
+		m.expected.TotalExpenses = total
+		if carry {
+			m.expected.StartingBalance = carried
+		} else {
+			m.expected.StartingBalance = 0
+		}
+		m.expected.EndingBalance = m.expected.StartingBalance + stored.AllowanceAdded - total
+		carried = m.expected.EndingBalance
+		expectedBalance += stored.AllowanceAdded - total
+
+		m.check(model.LedgerTotalExpenses, stored.TotalExpenses, m.expected.TotalExpenses)
+		m.check(model.LedgerStartingBalance, stored.StartingBalance, m.expected.StartingBalance)
+		m.check(model.LedgerEndingBalance, stored.EndingBalance, m.expected.EndingBalance)
+
+		entries, err := s.repo.GetFundEntries(ctx, stored.Month)
+		if err != nil {
+			return nil, nil, 0, 0, err
+		}
+		var itemized, earned model.Money
+		for _, e := range entries {
+			itemized += e.Amount
+			if e.Kind == model.FundKindEarning {
+				earned += e.Amount
+			}
+		}
+		m.expected.EarningsAdded = earned
+		m.check(model.LedgerEarnings, stored.EarningsAdded, earned)
+		if earned > stored.AllowanceAdded {
+			m.manual(model.LedgerEarningsOverAllowance, earned, stored.AllowanceAdded)
+		}
+		if stored.BaseAllowance != nil && itemized != stored.AllowanceAdded-*stored.BaseAllowance {
+			m.manual(model.LedgerFunds, itemized, stored.AllowanceAdded-*stored.BaseAllowance)
+		}
+
+		mirror, ok := mirrors[stored.Month]
+		switch {
+		case !ok:
+			m.issues = append(m.issues, model.LedgerIssue{Month: stored.Month, Field: model.LedgerMirrorMissing})
+		case mirror.StartingBalance != stored.StartingBalance || mirror.AllowanceAdded != stored.AllowanceAdded ||
+			mirror.TotalExpenses != stored.TotalExpenses || mirror.EndingBalance != stored.EndingBalance:
+			m.issues = append(m.issues, model.LedgerIssue{
+				Month: stored.Month, Field: model.LedgerMirror,
+				Stored: mirror.EndingBalance, Expected: stored.EndingBalance,
+			})
+		}
+		delete(mirrors, stored.Month)
+		months = append(months, m)
+	}
+
+	// What is left in mirrors has no canonical row.
+	orphans := make([]model.MonthSummary, 0, len(mirrors))
+	for _, mirror := range mirrors {
+		orphans = append(orphans, mirror)
+	}
+	sort.Slice(orphans, func(i, j int) bool { return orphans[i].Month < orphans[j].Month })
+
+	balance, err := s.repo.GetBalance(ctx)
+	if err != nil {
+		return nil, nil, 0, 0, err
+	}
+	return months, orphans, balance.TotalBalance, expectedBalance, nil
+}
+
+func (m *ledgerMonth) check(field string, stored, expected model.Money) {
+	if stored != expected {
+		m.issues = append(m.issues, model.LedgerIssue{Month: m.stored.Month, Field: field, Stored: stored, Expected: expected})
+	}
+}
+
+// manual records an issue the repair cannot fix; see model.LedgerIssue.
+func (m *ledgerMonth) manual(field string, stored, expected model.Money) {
+	m.issues = append(m.issues, model.LedgerIssue{Month: m.stored.Month, Field: field, Stored: stored, Expected: expected, Manual: true})
+}
+
+// sumExpenses totals a month's expense rows, paging through the partition.
+func (s *ExpenseService) sumExpenses(ctx context.Context, month string) (model.Money, error) {
+	var total model.Money
+	var cursor map[string]types.AttributeValue
+	for {
+		page, lastKey, err := s.repo.GetExpenses(ctx, month, monthsAfterPageSize, cursor)
+		if err != nil {
+			return 0, err
+		}
+		for _, e := range page {
+			total += e.Amount
+		}
+		if lastKey == nil {
+			return total, nil
+		}
+		cursor = lastKey
+	}
+}
+
+func ledgerReport(months []*ledgerMonth, orphans []model.MonthSummary, balance, expectedBalance model.Money) *model.LedgerReport {
+	report := &model.LedgerReport{Months: len(months), Issues: []model.LedgerIssue{}}
+	for _, m := range months {
+		report.Issues = append(report.Issues, m.issues...)
+	}
+	for _, o := range orphans {
+		report.Issues = append(report.Issues, model.LedgerIssue{Month: o.Month, Field: model.LedgerMirrorOrphan, Stored: o.EndingBalance})
+	}
+	if balance != expectedBalance {
+		report.Issues = append(report.Issues, model.LedgerIssue{Field: model.LedgerBalance, Stored: balance, Expected: expectedBalance})
+	}
+	return report
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+	"github.com/vppillai/passbook/backend/internal/testutil"
+)
+
+// =====================================================================
+// Ledger check — VerifyLedger derives every month's totals and balances
+// from the expense rows and the carry chain; RepairLedger writes them back.
+// =====================================================================
+
+// seedLedger seeds three consistent carry-over months, the oldest first:
+// 30 spent of 100, nothing of 100, 20 of 100, ending on 250.
+func seedLedger(t *testing.T, repo *testutil.FakeRepo) []string {
+	t.Helper()
+	var months []string
+	for ago := 3; ago >= 1; ago-- {
+		month, _ := pastMonthDay(ago, 1)
+		months = append(months, month)
+	}
+	testutil.SeedMonth(repo, months[0], 0, 100, 30, 70)
+	testutil.SeedMonth(repo, months[1], 70, 100, 0, 170)
+	testutil.SeedMonth(repo, months[2], 170, 100, 20, 250)
+	seedLedgerExpense(repo, months[0], "EXP#1#a", 30)
+	seedLedgerExpense(repo, months[2], "EXP#1#b", 20)
+	repo.Balance.TotalBalance = model.Dollars(250)
+	return months
+}
+
+func seedLedgerExpense(repo *testutil.FakeRepo, month, sk string, amount float64) {
+	repo.Expenses[testutil.ExpenseKey(month, sk)] = &model.Expense{
+		PK:     repository.MonthPrefix + month,
+		SK:     sk,
+		Amount: model.Dollars(amount),
+	}
+}
+
+func ledgerFields(report *model.LedgerReport) []string {
+	var out []string
+	for _, issue := range report.Issues {
+		out = append(out, issue.Month+" "+issue.Field)
+	}
+	return out
+}
+
+func TestLedger_ConsistentLedgerIsClean(t *testing.T) {
+	svc, repo := newExpenseService(t, false, true, 100)
+	seedLedger(t, repo)
+
+	report, err := svc.VerifyLedger(context.Background())
+	if err != nil {
+		t.Fatalf("VerifyLedger: %v", err)
+	}
+	if report.Months != 3 || len(report.Issues) != 0 {
+		t.Errorf("report = %+v, want 3 months and no issues", report)
+	}
+}
+
+func TestLedger_RepairsDriftAndRechecksClean(t *testing.T) {
+	svc, repo := newExpenseService(t, false, true, 100)
+	ctx := context.Background()
+	months := seedLedger(t, repo)
+	// The oldest month lost track of an expense row, and the mirror of the
+	// middle one went stale: the drift an old app version could leave.
+	seedLedgerExpense(repo, months[0], "EXP#2#c", 5)
+	repo.MonthList[months[1]].EndingBalance = model.Dollars(1)
+
+	report, err := svc.VerifyLedger(ctx)
+	if err != nil {
+		t.Fatalf("VerifyLedger: %v", err)
+	}
+	want := []string{
+		months[0] + " " + model.LedgerTotalExpenses,
+		months[0] + " " + model.LedgerEndingBalance,
+		months[1] + " " + model.LedgerStartingBalance,
+		months[1] + " " + model.LedgerEndingBalance,
+		months[1] + " " + model.LedgerMirror,
+		months[2] + " " + model.LedgerStartingBalance,
+		months[2] + " " + model.LedgerEndingBalance,
+		" " + model.LedgerBalance,
+	}
+	if got := ledgerFields(report); !slices.Equal(got, want) {
+		t.Fatalf("issues = %v, want %v", got, want)
+	}
+	if last := report.Issues[len(report.Issues)-1]; last.Stored != model.Dollars(250) || last.Expected != model.Dollars(245) {
+		t.Errorf("balance issue = %+v, want 250 stored, 245 expected", last)
+	}
+	if len(repo.Audit) != 0 {
+		t.Errorf("journal = %v, want VerifyLedger to write nothing", repo.AuditActions())
+	}
+
+	repaired, err := svc.RepairLedger(ctx)
+	if err != nil {
+		t.Fatalf("RepairLedger: %v", err)
+	}
+	if !repaired.Repaired || len(repaired.Issues) != len(want) {
+		t.Errorf("repair report = %+v, want the same issues, repaired", repaired)
+	}
+	if got := repo.Months[months[2]]; got.StartingBalance != model.Dollars(165) || got.EndingBalance != model.Dollars(245) {
+		t.Errorf("latest month = %+v, want 165 -> 245", got)
+	}
+	if repo.Balance.TotalBalance != model.Dollars(245) {
+		t.Errorf("balance = %v, want 245", repo.Balance.TotalBalance)
+	}
+	if got := repo.AuditActions(); len(got) != 4 || got[0] != model.AuditLedgerRepair {
+		t.Errorf("journal = %v, want a ledger.repair per month and one for the balance", got)
+	}
+
+	again, err := svc.VerifyLedger(ctx)
+	if err != nil {
+		t.Fatalf("VerifyLedger after repair: %v", err)
+	}
+	if len(again.Issues) != 0 {
+		t.Errorf("issues after repair = %v, want none", ledgerFields(again))
+	}
+}
+
+func TestLedger_CarryOffStartsEveryMonthAtZero(t *testing.T) {
+	svc, repo := newExpenseService(t, false, false, 100)
+	months := seedLedger(t, repo)
+
+	report, err := svc.RepairLedger(context.Background())
+	if err != nil {
+		t.Fatalf("RepairLedger: %v", err)
+	}
+	// Every month after the first carried; the balance formula holds in
+	// both modes, so BALANCE is not an issue.
+	want := []string{
+		months[1] + " " + model.LedgerStartingBalance,
+		months[1] + " " + model.LedgerEndingBalance,
+		months[2] + " " + model.LedgerStartingBalance,
+		months[2] + " " + model.LedgerEndingBalance,
+	}
+	if got := ledgerFields(report); !slices.Equal(got, want) {
+		t.Fatalf("issues = %v, want %v", got, want)
+	}
+	if got := repo.Months[months[2]]; got.StartingBalance != 0 || got.EndingBalance != model.Dollars(80) {
+		t.Errorf("latest month = %+v, want 0 -> 80", got)
+	}
+	if got := repo.MonthList[months[2]]; got.EndingBalance != model.Dollars(80) {
+		t.Errorf("latest mirror ending = %v, want 80", got.EndingBalance)
+	}
+}
+
+func TestLedger_MissingAndOrphanMirrors(t *testing.T) {
+	svc, repo := newExpenseService(t, false, true, 100)
+	months := seedLedger(t, repo)
+	delete(repo.MonthList, months[1])
+	orphan, _ := pastMonthDay(6, 1)
+	repo.MonthList[orphan] = &model.MonthSummary{Month: orphan, EndingBalance: model.Dollars(9)}
+
+	report, err := svc.RepairLedger(context.Background())
+	if err != nil {
+		t.Fatalf("RepairLedger: %v", err)
+	}
+	want := []string{months[1] + " " + model.LedgerMirrorMissing, orphan + " " + model.LedgerMirrorOrphan}
+	if got := ledgerFields(report); !slices.Equal(got, want) {
+		t.Fatalf("issues = %v, want %v", got, want)
+	}
+	if _, ok := repo.MonthList[orphan]; ok {
+		t.Error("orphan mirror survived the repair")
+	}
+	if got := repo.MonthList[months[1]]; got == nil || got.EndingBalance != model.Dollars(170) {
+		t.Errorf("restored mirror = %+v, want a copy of the canonical row", got)
+	}
+}
+
+func TestLedger_ConcurrentWriteStopsRepair(t *testing.T) {
+	svc, repo := newExpenseService(t, false, true, 100)
+	months := seedLedger(t, repo)
+	seedLedgerExpense(repo, months[2], "EXP#2#c", 5)
+	repo.BeforeLedgerRepair = func() {
+		// The app adds funds to the month between the check and its repair.
+		repo.Months[months[2]].AllowanceAdded += model.Dollars(10)
+	}
+
+	if _, err := svc.RepairLedger(context.Background()); !errors.Is(err, ErrLedgerModified) {
+		t.Fatalf("RepairLedger err = %v, want ErrLedgerModified", err)
+	}
+	if got := repo.Months[months[2]].TotalExpenses; got != model.Dollars(20) {
+		t.Errorf("total = %v, want the changed row left alone", got)
+	}
+	if len(repo.Audit) != 0 {
+		t.Errorf("journal = %v, want nothing for a refused repair", repo.AuditActions())
+	}
+}
+
+func seedLedgerFund(repo *testutil.FakeRepo, month, sk, kind string, amount float64) {
+	repo.Funds[testutil.ExpenseKey(month, sk)] = &model.FundEntry{
+		PK:     repository.MonthPrefix + month,
+		SK:     sk,
+		Amount: model.Dollars(amount),
+		Kind:   kind,
+	}
+}
+
+func TestLedger_RepairsEarningsFromTheirEntries(t *testing.T) {
+	svc, repo := newExpenseService(t, false, true, 100)
+	ctx := context.Background()
+	months := seedLedger(t, repo)
+	// A chore's reward was credited as part of the allowance, but the
+	// month's earnings never counted it.
+	seedLedgerFund(repo, months[0], "FUND#1#a", model.FundKindEarning, 5)
+
+	report, err := svc.VerifyLedger(ctx)
+	if err != nil {
+		t.Fatalf("VerifyLedger: %v", err)
+	}
+	if got, want := ledgerFields(report), []string{months[0] + " " + model.LedgerEarnings}; !slices.Equal(got, want) {
+		t.Fatalf("issues = %v, want %v", got, want)
+	}
+
+	if _, err := svc.RepairLedger(ctx); err != nil {
+		t.Fatalf("RepairLedger: %v", err)
+	}
+	if got := repo.Months[months[0]]; got.EarningsAdded != model.Dollars(5) || got.AllowanceAdded != model.Dollars(100) {
+		t.Errorf("month = %+v, want 5 earned of the same 100", got)
+	}
+	again, err := svc.VerifyLedger(ctx)
+	if err != nil {
+		t.Fatalf("VerifyLedger after repair: %v", err)
+	}
+	if len(again.Issues) != 0 {
+		t.Errorf("issues after repair = %v, want none", ledgerFields(again))
+	}
+}
+
+// Funds entries and allowance_added are both records of money granted, so
+// when they disagree the check cannot tell which is right: it reports the
+// month for a person to reconcile and the repair leaves it alone.
+func TestLedger_FundsDriftIsLeftManual(t *testing.T) {
+	svc, repo := newExpenseService(t, false, true, 100)
+	ctx := context.Background()
+	months := seedLedger(t, repo)
+	base := model.Dollars(100)
+	repo.Months[months[1]].BaseAllowance = &base
+	seedLedgerFund(repo, months[1], "FUND#1#a", "", 10)
+	seedLedgerFund(repo, months[2], "FUND#1#b", model.FundKindEarning, 150)
+	repo.Months[months[2]].EarningsAdded = model.Dollars(150)
+	repo.MonthList[months[2]].EarningsAdded = model.Dollars(150)
+
+	report, err := svc.VerifyLedger(ctx)
+	if err != nil {
+		t.Fatalf("VerifyLedger: %v", err)
+	}
+	want := []string{
+		months[1] + " " + model.LedgerFunds,
+		months[2] + " " + model.LedgerEarningsOverAllowance,
+	}
+	if got := ledgerFields(report); !slices.Equal(got, want) {
+		t.Fatalf("issues = %v, want %v", got, want)
+	}
+	for _, issue := range report.Issues {
+		if !issue.Manual {
+			t.Errorf("issue %+v, want it left manual", issue)
+		}
+	}
+	if funds := report.Issues[0]; funds.Stored != model.Dollars(10) || funds.Expected != 0 {
+		t.Errorf("funds issue = %+v, want 10 itemized, 0 expected", funds)
+	}
+
+	repaired, err := svc.RepairLedger(ctx)
+	if err != nil {
+		t.Fatalf("RepairLedger: %v", err)
+	}
+	if len(repaired.Issues) != len(want) {
+		t.Errorf("repair report = %v, want the same issues", ledgerFields(repaired))
+	}
+	if len(repo.Audit) != 0 {
+		t.Errorf("journal = %v, want the repair to write nothing", repo.AuditActions())
+	}
+	if got := repo.Months[months[1]].AllowanceAdded; got != model.Dollars(100) {
+		t.Errorf("allowance = %v, want it untouched", got)
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+)
+
+var (
+	// ErrInvalidSpendingLimit is returned when a daily or weekly limit is
+	// negative or above maxAmount. Handler maps to 400.
+	ErrInvalidSpendingLimit = errors.New("spending limit must be between 0 and 99999.99")
+	// ErrSpendingLimitExceeded is returned when an expense would take the
+	// spending of its day or week past the limit. Handler maps to 400.
+	ErrSpendingLimitExceeded = errors.New("spending limit exceeded")
+)
+
+// Spending limit periods, as reported by SpendingLimitError.
+const (
+	LimitPeriodDay  = "day"
+	LimitPeriodWeek = "week"
+)
+
+// SpendingLimitError carries the period whose limit an expense would break
+// and the headroom left in its day and week, so the handler can tell the
+// user how much they can still spend — the spending-limit counterpart of
+// InsufficientFundsError. A headroom is nil when that limit is off. It
+// wraps ErrSpendingLimitExceeded.
+type SpendingLimitError struct {
+	Period          string
+	DailyRemaining  *model.Money
+	WeeklyRemaining *model.Money
+}
+
+func (e *SpendingLimitError) Error() string { return ErrSpendingLimitExceeded.Error() }
+func (e *SpendingLimitError) Unwrap() error { return ErrSpendingLimitExceeded }
+
+// GetSpendingLimits returns the daily and weekly limits; zero when off.
+func (s *ExpenseService) GetSpendingLimits(ctx context.Context) (*model.SpendingLimits, error) {
+	config, err := s.repo.GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return &model.SpendingLimits{}, nil
+	}
+	return &model.SpendingLimits{Daily: config.DailyLimit, Weekly: config.WeeklyLimit}, nil
+}
+
+// SetSpendingLimits replaces both limits on the CONFIG row, writing only
+// their attributes. Expenses already booked are not re-checked.
+func (s *ExpenseService) SetSpendingLimits(ctx context.Context, req *model.SpendingLimits) (*model.SpendingLimits, error) {
+	for _, limit := range []model.Money{req.Daily, req.Weekly} {
+		if limit < 0 || limit > maxAmount {
+			return nil, ErrInvalidSpendingLimit
+		}
+	}
+	config := &model.Config{DailyLimit: req.Daily, WeeklyLimit: req.Weekly}
+	if err := s.updateConfig(ctx, "set spending limits", config, repository.ConfigDailyLimit, repository.ConfigWeeklyLimit); err != nil {
+		return nil, err
+	}
+	return &model.SpendingLimits{Daily: req.Daily, Weekly: req.Weekly}, nil
+}
+
+// ensureWithinSpendingLimits refuses an expense of amount dated at that
+// would take the spending of its UTC day or Monday-to-Sunday UTC week past
+// the limit. Every expense row dated in the period counts, whichever path
+// booked it. old is the expense being edited, nil for an add: it is left
+// out of the period's spending, and an edit is only refused where it
+// raises a period's spending, so an unrelated edit of an expense already
+// over a limit that was lowered since still goes through.
+//
+// Like ensureCategoryBudgetAffordable it is a pre-check on the rows just
+// read, not a transaction condition: a limit is a pace the family set, not
+// a ledger invariant, so the narrow race between two concurrent adds is
+// acceptable. Unlike a budget it applies whatever the overspend setting.
+func (s *ExpenseService) ensureWithinSpendingLimits(ctx context.Context, at time.Time, amount model.Money, old *model.Expense) error {
+	limits, err := s.GetSpendingLimits(ctx)
+	if err != nil || (limits.Daily == 0 && limits.Weekly == 0) {
+		return err
+	}
+	at = at.UTC()
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+	week := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+
+	refused := &SpendingLimitError{}
+	for _, p := range []struct {
+		period    string
+		limit     model.Money
+		from, to  time.Time
+		remaining **model.Money
+	}{
+		{LimitPeriodDay, limits.Daily, day, day.AddDate(0, 0, 1), &refused.DailyRemaining},
+		{LimitPeriodWeek, limits.Weekly, week, week.AddDate(0, 0, 7), &refused.WeeklyRemaining},
+	} {
+		if p.limit == 0 {
+			continue
+		}
+		others, err := s.spentBetween(ctx, p.from, p.to, old)
+		if err != nil {
+			return err
+		}
+		before := others
+		if old != nil && !old.CreatedAt.Before(p.from) && old.CreatedAt.Before(p.to) {
+			before += old.Amount
+		}
+		remaining := max(p.limit-others, 0)
+		*p.remaining = &remaining
+		if after := others + amount; after > p.limit && after > before && refused.Period == "" {
+			refused.Period = p.period
+		}
+	}
+	if refused.Period != "" {
+		return refused
+	}
+	return nil
+}
+
+// spentBetween totals the expenses dated in [from, to), across the months
+// the span touches, leaving out skip when it is not nil.
+func (s *ExpenseService) spentBetween(ctx context.Context, from, to time.Time, skip *model.Expense) (model.Money, error) {
+	var total model.Money
+	for month := monthOf(from); month <= monthOf(to.Add(-time.Nanosecond)); month = GetNextMonth(month) {
+		var cursor map[string]types.AttributeValue
+		for {
+			page, lastKey, err := s.repo.GetExpensesInRange(ctx, month, from, to, monthsAfterPageSize, cursor)
+			if err != nil {
+				return 0, err
+			}
+			for _, e := range page {
+				// The range query's bounds are inclusive; the period is not.
+				if e.CreatedAt.Before(to) && (skip == nil || e.SK != skip.SK) {
+					total += e.Amount
+				}
+			}
+			if lastKey == nil {
+				break
+			}
+			cursor = lastKey
+		}
+	}
+	return total, nil
+}
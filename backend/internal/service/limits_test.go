@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/testutil"
+)
+
+// =====================================================================
+// Daily and weekly spending limits — checked against the UTC day and
+// Monday-to-Sunday week an expense is dated in. The week of 2025-03-31
+// straddles March and April, so it spans two month partitions.
+// =====================================================================
+
+func setLimits(t *testing.T, svc *ExpenseService, repo *testutil.FakeRepo, daily, weekly float64) {
+	t.Helper()
+	if repo.Config == nil {
+		repo.Config = &model.Config{PinHash: "x"}
+	}
+	if _, err := svc.SetSpendingLimits(context.Background(), &model.SpendingLimits{Daily: model.Dollars(daily), Weekly: model.Dollars(weekly)}); err != nil {
+		t.Fatalf("SetSpendingLimits: %v", err)
+	}
+}
+
+func addOn(t *testing.T, svc *ExpenseService, date string, amount float64) (*model.AddExpenseResponse, error) {
+	t.Helper()
+	return svc.AddExpense(context.Background(), &model.AddExpenseRequest{Amount: model.Dollars(amount), Description: "snack", Date: date})
+}
+
+func TestAddExpense_SpendingLimits(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	setLimits(t, svc, repo, 10, 25)
+
+	for _, add := range []struct {
+		date   string
+		amount float64
+	}{{"2025-03-31", 6}, {"2025-03-31", 4}, {"2025-04-02", 9}} {
+		if _, err := addOn(t, svc, add.date, add.amount); err != nil {
+			t.Fatalf("AddExpense %v on %s: %v", add.amount, add.date, err)
+		}
+	}
+
+	_, err := addOn(t, svc, "2025-03-31", 0.01)
+	var exceeded *SpendingLimitError
+	if !errors.As(err, &exceeded) || !errors.Is(err, ErrSpendingLimitExceeded) {
+		t.Fatalf("err = %v, want a SpendingLimitError", err)
+	}
+	if exceeded.Period != LimitPeriodDay || *exceeded.DailyRemaining != 0 || *exceeded.WeeklyRemaining != model.Dollars(6) {
+		t.Errorf("refusal = %s daily %v weekly %v, want day 0 / 6", exceeded.Period, *exceeded.DailyRemaining, *exceeded.WeeklyRemaining)
+	}
+
+	// Sunday is the week's last day; the April rows count with March's.
+	_, err = addOn(t, svc, "2025-04-06", 7)
+	if !errors.As(err, &exceeded) || exceeded.Period != LimitPeriodWeek || *exceeded.DailyRemaining != model.Dollars(10) || *exceeded.WeeklyRemaining != model.Dollars(6) {
+		t.Fatalf("err = %v (%+v), want the weekly limit with 10 / 6 left", err, exceeded)
+	}
+	if _, err := addOn(t, svc, "2025-04-06", 6); err != nil {
+		t.Fatalf("AddExpense up to the weekly limit: %v", err)
+	}
+	if _, err := addOn(t, svc, "2025-04-07", 7); err != nil {
+		t.Fatalf("AddExpense in the next week: %v", err)
+	}
+
+	setLimits(t, svc, repo, 0, 0)
+	if _, err := addOn(t, svc, "2025-03-31", 50); err != nil {
+		t.Fatalf("AddExpense with the limits off: %v", err)
+	}
+}
+
+func TestUpdateExpense_SpendingLimits(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	ctx := context.Background()
+	setLimits(t, svc, repo, 10, 0)
+
+	first, err := addOn(t, svc, "2025-03-10", 8)
+	if err != nil {
+		t.Fatalf("AddExpense: %v", err)
+	}
+	second, err := addOn(t, svc, "2025-03-11", 5)
+	if err != nil {
+		t.Fatalf("AddExpense: %v", err)
+	}
+
+	// Raising an expense counts it once, not on top of its old amount.
+	raised := model.Dollars(10)
+	if _, err := svc.UpdateExpense(ctx, "2025-03", first.Expense.SK, &model.UpdateExpenseRequest{Amount: &raised}); err != nil {
+		t.Fatalf("UpdateExpense up to the limit: %v", err)
+	}
+	over := model.Dollars(10.01)
+	_, err = svc.UpdateExpense(ctx, "2025-03", first.Expense.SK, &model.UpdateExpenseRequest{Amount: &over})
+	var exceeded *SpendingLimitError
+	if !errors.As(err, &exceeded) || *exceeded.DailyRemaining != model.Dollars(10) || exceeded.WeeklyRemaining != nil {
+		t.Fatalf("err = %v, want the daily limit with 10 left and no weekly limit", err)
+	}
+
+	// Re-dating onto a day that is already spent is refused, in another
+	// month too.
+	_, err = svc.UpdateExpense(ctx, "2025-03", second.Expense.SK, &model.UpdateExpenseRequest{Date: "2025-03-10"})
+	if !errors.Is(err, ErrSpendingLimitExceeded) {
+		t.Fatalf("re-date err = %v, want ErrSpendingLimitExceeded", err)
+	}
+	if _, err := addOn(t, svc, "2025-04-01", 9); err != nil {
+		t.Fatalf("AddExpense: %v", err)
+	}
+	_, err = svc.UpdateExpense(ctx, "2025-03", second.Expense.SK, &model.UpdateExpenseRequest{Date: "2025-04-01"})
+	if !errors.Is(err, ErrSpendingLimitExceeded) {
+		t.Fatalf("cross-month re-date err = %v, want ErrSpendingLimitExceeded", err)
+	}
+
+	// Once a limit is lowered, edits that do not add to the day still pass.
+	setLimits(t, svc, repo, 4, 0)
+	note := "lunch"
+	if _, err := svc.UpdateExpense(ctx, "2025-03", first.Expense.SK, &model.UpdateExpenseRequest{Description: &note}); err != nil {
+		t.Fatalf("description edit over a lowered limit: %v", err)
+	}
+	lower := model.Dollars(7)
+	if _, err := svc.UpdateExpense(ctx, "2025-03", first.Expense.SK, &model.UpdateExpenseRequest{Amount: &lower}); err != nil {
+		t.Fatalf("lowering an expense over a lowered limit: %v", err)
+	}
+}
+
+func TestApprovePendingExpense_SpendingLimits(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	ctx := context.Background()
+	setLimits(t, svc, repo, 10, 0)
+
+	// Submitting is not held to the limit; approving is, as of then.
+	pending, err := svc.SubmitExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(6), Description: "toy", Date: "2025-03-10"})
+	if err != nil {
+		t.Fatalf("SubmitExpense: %v", err)
+	}
+	if _, err := addOn(t, svc, "2025-03-10", 5); err != nil {
+		t.Fatalf("AddExpense: %v", err)
+	}
+	if _, err := svc.ApprovePendingExpense(ctx, pending.ID); !errors.Is(err, ErrSpendingLimitExceeded) {
+		t.Fatalf("approve err = %v, want ErrSpendingLimitExceeded", err)
+	}
+	if left, _ := repo.GetPendingExpense(ctx, pending.ID); left == nil {
+		t.Error("a refused approval must leave the expense pending")
+	}
+}
+
+func TestSetSpendingLimits_Validation(t *testing.T) {
+	svc, repo := newExpenseService(t, false, false, 0)
+	repo.Config = &model.Config{PinHash: "x"}
+	for _, limits := range []model.SpendingLimits{{Daily: -1}, {Weekly: model.Dollars(100000)}} {
+		if _, err := svc.SetSpendingLimits(context.Background(), &limits); !errors.Is(err, ErrInvalidSpendingLimit) {
+			t.Errorf("SetSpendingLimits(%+v) err = %v, want ErrInvalidSpendingLimit", limits, err)
+		}
+	}
+	if repo.Config.DailyLimit != 0 || repo.Config.WeeklyLimit != 0 || repo.Config.PinHash != "x" {
+		t.Errorf("config = %+v, a refused write must not change it", repo.Config)
+	}
+}
+
+// Only the two limit attributes are written, so a PIN change or budget
+// edit racing the update is kept, and a limit set to 0 is removed.
+func TestSetSpendingLimits_WritesOnlyTheLimits(t *testing.T) {
+	svc, repo := newExpenseService(t, false, false, 0)
+	ctx := context.Background()
+	repo.Config = &model.Config{PinHash: "x", CategoryBudgets: map[string]model.Money{"food": model.Dollars(50)}}
+	setLimits(t, svc, repo, 10, 25)
+	setLimits(t, svc, repo, 0, 30)
+
+	got, err := svc.GetSpendingLimits(ctx)
+	if err != nil {
+		t.Fatalf("GetSpendingLimits: %v", err)
+	}
+	if got.Daily != 0 || got.Weekly != model.Dollars(30) {
+		t.Errorf("limits = %+v, want daily off and weekly 30", got)
+	}
+	if repo.SaveConfigCalls != 0 || repo.Config.PinHash != "x" || repo.Config.CategoryBudgets["food"] != model.Dollars(50) {
+		t.Errorf("SaveConfig calls %d, config %+v; want only the limits written", repo.SaveConfigCalls, repo.Config)
+	}
+
+	repo.Config = nil
+	if _, err := svc.SetSpendingLimits(ctx, &model.SpendingLimits{Daily: model.Dollars(5)}); !errors.Is(err, ErrPINNotSetup) {
+		t.Errorf("no CONFIG err = %v, want ErrPINNotSetup", err)
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+)
+
+var (
+	// ErrPendingExpenseNotFound is returned when an approval or rejection
+	// names an expense that is not pending, including one another request
+	// has just approved or rejected. Handler maps to 404.
+	ErrPendingExpenseNotFound = errors.New("pending expense not found")
+	// ErrRejectReasonRequired is returned when a rejection has no reason.
+	// Handler maps to 400.
+	ErrRejectReasonRequired = errors.New("a rejection needs a reason")
+)
+
+// SubmitExpense validates an add request like AddExpense and files the
+// expense as pending instead of booking it: no balance changes until an
+// admin approves it. Only the request itself is checked now; the funds,
+// spending-limit, budget and carry-chain checks run at approval, against
+// the balances of that moment.
+func (s *ExpenseService) SubmitExpense(ctx context.Context, req *model.AddExpenseRequest) (*model.PendingExpense, error) {
+	month, expense, err := s.newExpense(req)
+	if err != nil {
+		return nil, err
+	}
+	pending := &model.PendingExpense{
+		ID:          expense.SK,
+		Month:       month,
+		Amount:      expense.Amount,
+		Description: expense.Description,
+		Category:    expense.Category,
+		CreatedAt:   expense.CreatedAt,
+		SubmittedAt: time.Now(),
+	}
+	if err := s.repo.PutPendingExpense(ctx, pending); err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// ListPendingExpenses returns the expenses awaiting approval, oldest
+// submission first.
+func (s *ExpenseService) ListPendingExpenses(ctx context.Context) (*model.PendingResponse, error) {
+	entries, err := s.repo.ListPendingExpenses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []model.PendingExpense{}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].SubmittedAt.Before(entries[j].SubmittedAt) })
+	return &model.PendingResponse{Expenses: entries}, nil
+}
+
+// ApprovePendingExpense books a pending expense under its id, month and
+// date through the same checks as any add — the month is created if
+// missing, and the spending limits, category budget, carry chain and
+// overspend rules all apply as of now. A refused approval leaves it
+// pending. The entry is deleted in the transaction that books the expense,
+// so a rejection racing the approval either lands first and the approval
+// finds it gone (ErrPendingExpenseNotFound), or finds it gone itself. An
+// entry left behind by an expense already booked is cleared on retry
+// (ErrDuplicateExpense), as RestoreExpense does.
+func (s *ExpenseService) ApprovePendingExpense(ctx context.Context, id string) (*model.AddExpenseResponse, error) {
+	pending, err := s.repo.GetPendingExpense(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if pending == nil {
+		return nil, ErrPendingExpenseNotFound
+	}
+
+	if err := s.ensureWithinSpendingLimits(ctx, pending.CreatedAt, pending.Amount, nil); err != nil {
+		return nil, err
+	}
+	response, err := s.bookExpense(ctx, pending.Month, &model.Expense{
+		SK:          pending.ID,
+		Amount:      pending.Amount,
+		Description: pending.Description,
+		Category:    pending.Category,
+		CreatedAt:   pending.CreatedAt,
+	}, s.repo.AtomicApprovePendingExpense)
+	if err != nil {
+		if errors.Is(err, ErrDuplicateExpense) {
+			if derr := s.repo.DeletePendingExpense(ctx, id); derr != nil {
+				log.Printf("warn: could not clear pending entry %s of a booked expense: %v", id, derr)
+			}
+		}
+		return nil, err
+	}
+	return response, nil
+}
+
+// RejectPendingExpense discards a pending expense, journaling reason so
+// the member can see why.
+func (s *ExpenseService) RejectPendingExpense(ctx context.Context, id, reason string) error {
+	reason, err := validateDescription(reason)
+	if err != nil {
+		return err
+	}
+	if reason == "" {
+		return ErrRejectReasonRequired
+	}
+	pending, err := s.repo.GetPendingExpense(ctx, id)
+	if err != nil {
+		return err
+	}
+	if pending == nil {
+		return ErrPendingExpenseNotFound
+	}
+	if err := s.repo.AtomicRejectPendingExpense(ctx, pending, reason); err != nil {
+		if errors.Is(err, repository.ErrPendingExpenseNotFound) {
+			return ErrPendingExpenseNotFound
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/testutil"
+)
+
+// =====================================================================
+// Pending expenses — submitted by members, booked only on approval
+// =====================================================================
+
+func TestPending_ApprovalRunsTheAddChecks(t *testing.T) {
+	svc, repo := newExpenseService(t, false, true, 100)
+	ctx := context.Background()
+	month := time.Now().UTC().Format("2006-01")
+	testutil.SeedMonth(repo, month, 0, 50, 0, 50)
+	repo.Balance.TotalBalance = model.Dollars(50)
+
+	big, err := svc.SubmitExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(80), Description: "Bike"})
+	if err != nil {
+		t.Fatalf("SubmitExpense: %v", err)
+	}
+	if big.Month != month || repo.Balance.TotalBalance != model.Dollars(50) {
+		t.Fatalf("pending = %+v, balance %v, want it filed without touching the balance", big, repo.Balance.TotalBalance)
+	}
+	if _, err := svc.SubmitExpense(ctx, &model.AddExpenseRequest{Amount: 0}); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("invalid submission err = %v, want ErrInvalidAmount", err)
+	}
+
+	// The hard stop applies at approval, and the expense stays pending.
+	if _, err := svc.ApprovePendingExpense(ctx, big.ID); !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("approve over the balance err = %v, want ErrInsufficientFunds", err)
+	}
+	if repo.Pending[big.ID] == nil {
+		t.Fatal("a refused approval discarded the pending expense")
+	}
+
+	// Money arrives; the same approval now goes through under its id.
+	repo.Months[month].AllowanceAdded = model.Dollars(100)
+	repo.Months[month].EndingBalance = model.Dollars(100)
+	repo.MonthList[month].EndingBalance = model.Dollars(100)
+	repo.Balance.TotalBalance = model.Dollars(100)
+	resp, err := svc.ApprovePendingExpense(ctx, big.ID)
+	if err != nil {
+		t.Fatalf("ApprovePendingExpense: %v", err)
+	}
+	if resp.Expense.SK != big.ID || resp.MonthBalance != model.Dollars(20) {
+		t.Errorf("approved = %+v, want %s booked, 100 - 80 = 20", resp, big.ID)
+	}
+	if len(repo.Pending) != 0 {
+		t.Error("pending entry survived its approval")
+	}
+}
+
+func TestPending_RejectNeedsAReasonAndJournalsIt(t *testing.T) {
+	svc, repo := newExpenseService(t, false, true, 100)
+	ctx := context.Background()
+	p, err := svc.SubmitExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(5), Description: "Gum"})
+	if err != nil {
+		t.Fatalf("SubmitExpense: %v", err)
+	}
+
+	if err := svc.RejectPendingExpense(ctx, p.ID, "  "); !errors.Is(err, ErrRejectReasonRequired) {
+		t.Errorf("blank reason err = %v, want ErrRejectReasonRequired", err)
+	}
+	if err := svc.RejectPendingExpense(ctx, p.ID, " Not this week "); err != nil {
+		t.Fatalf("RejectPendingExpense: %v", err)
+	}
+	if got := repo.Audit; len(got) != 1 || got[0].Action != model.AuditExpenseReject || got[0].Reason != "Not this week" {
+		t.Errorf("journal = %+v, want one rejection with its trimmed reason", got)
+	}
+	if err := svc.RejectPendingExpense(ctx, p.ID, "again"); !errors.Is(err, ErrPendingExpenseNotFound) {
+		t.Errorf("second reject err = %v, want ErrPendingExpenseNotFound", err)
+	}
+	if _, err := svc.ApprovePendingExpense(ctx, p.ID); !errors.Is(err, ErrPendingExpenseNotFound) {
+		t.Errorf("approve after reject err = %v, want ErrPendingExpenseNotFound", err)
+	}
+}
+
+// A rejection that lands after the approval read the entry wins: the
+// approval books nothing rather than an expense the journal says was
+// rejected.
+func TestPending_RejectionRacingApprovalBooksNothing(t *testing.T) {
+	svc, repo := newExpenseService(t, true, true, 100)
+	ctx := context.Background()
+	month := time.Now().UTC().Format("2006-01")
+	testutil.SeedMonth(repo, month, 0, 50, 0, 50)
+	repo.Balance.TotalBalance = model.Dollars(50)
+	p, err := svc.SubmitExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(5), Description: "Gum"})
+	if err != nil {
+		t.Fatalf("SubmitExpense: %v", err)
+	}
+	repo.BeforePendingApproval = func() {
+		if err := svc.RejectPendingExpense(ctx, p.ID, "No"); err != nil {
+			t.Fatalf("RejectPendingExpense: %v", err)
+		}
+	}
+
+	if _, err := svc.ApprovePendingExpense(ctx, p.ID); !errors.Is(err, ErrPendingExpenseNotFound) {
+		t.Fatalf("approve err = %v, want ErrPendingExpenseNotFound", err)
+	}
+	if len(repo.Expenses) != 0 || repo.Balance.TotalBalance != model.Dollars(50) {
+		t.Errorf("expenses %v, balance %v; want nothing booked", repo.Expenses, repo.Balance.TotalBalance)
+	}
+}
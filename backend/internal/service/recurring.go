@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+)
+
+var (
+	// ErrRecurringNotFound is returned when a recurring schedule id does not
+	// exist. Handler maps to 404.
+	ErrRecurringNotFound = errors.New("recurring expense not found")
+	// ErrInvalidDayOfMonth is returned when a schedule's day is outside
+	// 1-31. Handler maps to 400.
+	ErrInvalidDayOfMonth = errors.New("day of month must be between 1 and 31")
+	// ErrInvalidRecurringRange is returned when a schedule's end month is
+	// before its start month. Handler maps to 400.
+	ErrInvalidRecurringRange = errors.New("end month is before start month")
+)
+
+// maxRecurringCatchUp bounds how many months of one schedule a single run
+// books. A schedule back-dated years, or an instance nobody opened for a
+// long time, catches up over several runs instead of holding one Lambda
+// invocation for hundreds of transactions.
+const maxRecurringCatchUp = 24
+
+// CreateRecurring validates and stores a new schedule. Nothing is booked
+// here; occurrences are booked by RunRecurring.
+func (s *ExpenseService) CreateRecurring(ctx context.Context, req *model.CreateRecurringRequest) (*model.RecurringExpense, error) {
+	startMonth, err := resolveMonth(req.StartMonth)
+	if err != nil {
+		return nil, err
+	}
+	rec := &model.RecurringExpense{
+		ID:          uuid.New().String()[:8],
+		Amount:      req.Amount,
+		Description: req.Description,
+		Category:    req.Category,
+		DayOfMonth:  req.DayOfMonth,
+		StartMonth:  startMonth,
+		EndMonth:    req.EndMonth,
+	}
+	if err := validateRecurring(rec); err != nil {
+		return nil, err
+	}
+	rec.CreatedAt = time.Now()
+	rec.UpdatedAt = rec.CreatedAt
+	if err := s.repo.CreateRecurringExpense(ctx, rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// ListRecurring returns every schedule.
+func (s *ExpenseService) ListRecurring(ctx context.Context) (*model.RecurringListResponse, error) {
+	recs, err := s.repo.ListRecurringExpenses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if recs == nil {
+		recs = []model.RecurringExpense{}
+	}
+	return &model.RecurringListResponse{Recurring: recs}, nil
+}
+
+// UpdateRecurring changes a schedule's amount, description, category, day
+// or end month. Only occurrences not yet booked are affected.
+func (s *ExpenseService) UpdateRecurring(ctx context.Context, id string, req *model.UpdateRecurringRequest) (*model.RecurringExpense, error) {
+	if req.Amount == nil && req.Description == nil && req.Category == nil && req.DayOfMonth == nil && req.EndMonth == nil {
+		return nil, ErrNoChanges
+	}
+	rec, err := s.repo.GetRecurringExpense(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rec == nil {
+		return nil, ErrRecurringNotFound
+	}
+	if req.Amount != nil {
+		rec.Amount = *req.Amount
+	}
+	if req.Description != nil {
+		rec.Description = *req.Description
+	}
+	if req.Category != nil {
+		rec.Category = *req.Category
+	}
+	if req.DayOfMonth != nil {
+		rec.DayOfMonth = *req.DayOfMonth
+	}
+	if req.EndMonth != nil {
+		rec.EndMonth = *req.EndMonth
+	}
+	if err := validateRecurring(rec); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateRecurringExpense(ctx, rec); err != nil {
+		if errors.Is(err, repository.ErrRecurringNotFound) {
+			return nil, ErrRecurringNotFound
+		}
+		return nil, err
+	}
+	return rec, nil
+}
+
+// DeleteRecurring removes a schedule. Expenses it already booked stay.
+func (s *ExpenseService) DeleteRecurring(ctx context.Context, id string) error {
+	rec, err := s.repo.DeleteRecurringExpense(ctx, id)
+	if err != nil {
+		return err
+	}
+	if rec == nil {
+		return ErrRecurringNotFound
+	}
+	return nil
+}
+
+// validateRecurring applies the expense input rules to a schedule (the
+// same amount ceiling, description and category normalization an add
+// gets), plus the schedule's own day and month-range rules. It normalizes
+// rec in place.
+func validateRecurring(rec *model.RecurringExpense) error {
+	if rec.Amount <= 0 || rec.Amount > maxAmount {
+		return ErrInvalidAmount
+	}
+	description, err := validateDescription(rec.Description)
+	if err != nil {
+		return err
+	}
+	if description == "" {
+		description = "Expense"
+	}
+	rec.Description = description
+	category, err := validateCategory(rec.Category)
+	if err != nil {
+		return err
+	}
+	rec.Category = category
+	if rec.DayOfMonth < 1 || rec.DayOfMonth > 31 {
+		return ErrInvalidDayOfMonth
+	}
+	if rec.EndMonth != "" {
+		if err := ValidateMonth(rec.EndMonth); err != nil {
+			return err
+		}
+		if rec.EndMonth < rec.StartMonth {
+			return ErrInvalidRecurringRange
+		}
+	}
+	return nil
+}
+
+// recurringDueTime is the timestamp of a schedule's occurrence in month:
+// noon UTC (the same stable mid-day stamp a back-dated add gets) on its
+// day, clamped to the month's last day.
+func recurringDueTime(month string, day int) time.Time {
+	t, _ := time.Parse("2006-01", month)
+	last := t.AddDate(0, 1, -1).Day()
+	if day > last {
+		day = last
+	}
+	return time.Date(t.Year(), t.Month(), day, 12, 0, 0, 0, time.UTC)
+}
+
+// RunRecurring books every occurrence that has fallen due by now and is not
+// yet booked, oldest first per schedule.
+//
+// Each occurrence is an ordinary expense written through addExpense, so
+// month creation, the carry chain, category budgets and the overspend rule
+// all apply exactly as for a manual add. Its SK is deterministic —
+// EXP#<due time>#<schedule id> — so the expense put's attribute_not_exists
+// condition makes booking idempotent: a repeated or concurrent run gets
+// ErrDuplicateExpense for an occurrence that is already booked and simply
+// moves on. The last_booked_month cursor only saves re-reading old months.
+//
+// An occurrence refused for funds or budget is reported as skipped and
+// stops that schedule for this run, without advancing its cursor, so the
+// next run retries it before anything later.
+func (s *ExpenseService) RunRecurring(ctx context.Context, now time.Time) (*model.RecurringRunResponse, error) {
+	recs, err := s.repo.ListRecurringExpenses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, time.UTC)
+	current := fmt.Sprintf("%04d-%02d", now.Year(), now.Month())
+
+	resp := &model.RecurringRunResponse{
+		Booked:  []model.RecurringOccurrence{},
+		Skipped: []model.RecurringOccurrence{},
+	}
+	for _, rec := range recs {
+		month := rec.StartMonth
+		if rec.LastBookedMonth != "" {
+			month = GetNextMonth(rec.LastBookedMonth)
+		}
+	schedule:
+		for n := 0; n < maxRecurringCatchUp && month <= current; n++ {
+			if rec.EndMonth != "" && month > rec.EndMonth {
+				break
+			}
+			due := recurringDueTime(month, rec.DayOfMonth)
+			if due.After(today) {
+				break
+			}
+			occurrence := model.RecurringOccurrence{RecurringID: rec.ID, Month: month, Amount: rec.Amount}
+			expense := &model.Expense{
+				SK:          fmt.Sprintf("%s%d#%s", repository.ExpensePrefix, due.UnixNano(), rec.ID),
+				Amount:      rec.Amount,
+				Description: rec.Description,
+				Category:    rec.Category,
+				CreatedAt:   due,
+			}
+			_, err := s.addExpense(ctx, month, expense)
+			switch {
+			case err == nil:
+				occurrence.ExpenseID = expense.SK
+				resp.Booked = append(resp.Booked, occurrence)
+			case errors.Is(err, ErrDuplicateExpense):
+				// Booked by an earlier run that did not get to move the cursor.
+			case errors.Is(err, ErrInsufficientFunds), errors.Is(err, ErrCategoryBudgetExceeded):
+				occurrence.Reason = err.Error()
+				resp.Skipped = append(resp.Skipped, occurrence)
+				break schedule
+			default:
+				return nil, fmt.Errorf("book recurring %s for %s: %w", rec.ID, month, err)
+			}
+			if err := s.repo.MarkRecurringBooked(ctx, rec.ID, month); err != nil {
+				return nil, err
+			}
+			month = GetNextMonth(month)
+		}
+	}
+	return resp, nil
+}
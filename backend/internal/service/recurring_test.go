@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/testutil"
+)
+
+// =====================================================================
+// Recurring expenses — occurrences are booked through the checked add
+// path with a deterministic SK, so a run can be repeated (or raced) without
+// double-booking, and a refused occurrence is retried rather than lost.
+// =====================================================================
+
+func createRecurring(t *testing.T, svc *ExpenseService, req *model.CreateRecurringRequest) *model.RecurringExpense {
+	t.Helper()
+	rec, err := svc.CreateRecurring(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateRecurring: %v", err)
+	}
+	return rec
+}
+
+func expensesIn(repo *testutil.FakeRepo, month string) []*model.Expense {
+	var out []*model.Expense
+	for _, e := range repo.Expenses {
+		if e.PK == "MONTH#"+month {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+var recurringNow = time.Date(2025, time.March, 20, 9, 0, 0, 0, time.UTC)
+
+func TestCreateRecurring_Validation(t *testing.T) {
+	svc, _ := newExpenseService(t, true, false, 0)
+
+	cases := []struct {
+		name string
+		req  model.CreateRecurringRequest
+		want error
+	}{
+		{"zero amount", model.CreateRecurringRequest{Amount: 0, DayOfMonth: 1}, ErrInvalidAmount},
+		{"day zero", model.CreateRecurringRequest{Amount: model.Dollars(5), DayOfMonth: 0}, ErrInvalidDayOfMonth},
+		{"day 32", model.CreateRecurringRequest{Amount: model.Dollars(5), DayOfMonth: 32}, ErrInvalidDayOfMonth},
+		{"bad start", model.CreateRecurringRequest{Amount: model.Dollars(5), DayOfMonth: 1, StartMonth: "2025-13"}, ErrInvalidMonth},
+		{"end before start", model.CreateRecurringRequest{Amount: model.Dollars(5), DayOfMonth: 1, StartMonth: "2025-03", EndMonth: "2025-02"}, ErrInvalidRecurringRange},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.CreateRecurring(context.Background(), &tc.req)
+			if !errors.Is(err, tc.want) {
+				t.Errorf("err = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestRunRecurring_BooksDueOccurrencesOnce(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	rec := createRecurring(t, svc, &model.CreateRecurringRequest{
+		Amount: model.Dollars(9.99), Description: "Music", Category: "Subscriptions", DayOfMonth: 5, StartMonth: "2025-01",
+	})
+
+	resp, err := svc.RunRecurring(context.Background(), recurringNow)
+	if err != nil {
+		t.Fatalf("RunRecurring: %v", err)
+	}
+	if len(resp.Booked) != 3 || len(resp.Skipped) != 0 {
+		t.Fatalf("booked %d skipped %d, want 3 and 0", len(resp.Booked), len(resp.Skipped))
+	}
+	for _, month := range []string{"2025-01", "2025-02", "2025-03"} {
+		got := expensesIn(repo, month)
+		if len(got) != 1 {
+			t.Fatalf("%s has %d expenses, want 1", month, len(got))
+		}
+		if got[0].Category != "subscriptions" || got[0].CreatedAt.Day() != 5 {
+			t.Errorf("%s expense = %+v, want subscriptions on the 5th", month, got[0])
+		}
+		assertCategoryTotal(t, repo, month, "subscriptions", 9.99)
+	}
+	if repo.Recurring[rec.ID].LastBookedMonth != "2025-03" {
+		t.Errorf("cursor = %q, want 2025-03", repo.Recurring[rec.ID].LastBookedMonth)
+	}
+
+	// A second run has nothing left to book.
+	resp, err = svc.RunRecurring(context.Background(), recurringNow)
+	if err != nil {
+		t.Fatalf("second RunRecurring: %v", err)
+	}
+	if len(resp.Booked) != 0 {
+		t.Errorf("second run booked %d, want 0", len(resp.Booked))
+	}
+}
+
+// The cursor is only an optimization: a run that booked an occurrence but
+// died before moving it must not book the occurrence again.
+func TestRunRecurring_LostCursorDoesNotDoubleBook(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	rec := createRecurring(t, svc, &model.CreateRecurringRequest{Amount: model.Dollars(10), DayOfMonth: 1, StartMonth: "2025-02"})
+	if _, err := svc.RunRecurring(context.Background(), recurringNow); err != nil {
+		t.Fatalf("RunRecurring: %v", err)
+	}
+
+	repo.Recurring[rec.ID].LastBookedMonth = ""
+	resp, err := svc.RunRecurring(context.Background(), recurringNow)
+	if err != nil {
+		t.Fatalf("re-run: %v", err)
+	}
+	if len(resp.Booked) != 0 {
+		t.Errorf("re-run booked %d, want 0", len(resp.Booked))
+	}
+	if repo.Months["2025-02"].TotalExpenses != model.Dollars(10) || repo.Months["2025-03"].TotalExpenses != model.Dollars(10) {
+		t.Errorf("totals = %v / %v, want 10 each", repo.Months["2025-02"].TotalExpenses, repo.Months["2025-03"].TotalExpenses)
+	}
+	if repo.Recurring[rec.ID].LastBookedMonth != "2025-03" {
+		t.Errorf("cursor = %q, want it restored to 2025-03", repo.Recurring[rec.ID].LastBookedMonth)
+	}
+}
+
+func TestRunRecurring_NotYetDueAndEndMonth(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	// Due on the 25th: March's occurrence is still ahead of recurringNow.
+	late := createRecurring(t, svc, &model.CreateRecurringRequest{Amount: model.Dollars(1), DayOfMonth: 25, StartMonth: "2025-02"})
+	// Ended in January: nothing after it is booked.
+	ended := createRecurring(t, svc, &model.CreateRecurringRequest{Amount: model.Dollars(2), DayOfMonth: 1, StartMonth: "2025-01", EndMonth: "2025-01"})
+
+	if _, err := svc.RunRecurring(context.Background(), recurringNow); err != nil {
+		t.Fatalf("RunRecurring: %v", err)
+	}
+	if got := repo.Recurring[late.ID].LastBookedMonth; got != "2025-02" {
+		t.Errorf("late cursor = %q, want 2025-02", got)
+	}
+	if got := repo.Recurring[ended.ID].LastBookedMonth; got != "2025-01" {
+		t.Errorf("ended cursor = %q, want 2025-01", got)
+	}
+	if n := len(expensesIn(repo, "2025-03")); n != 0 {
+		t.Errorf("2025-03 has %d expenses, want 0", n)
+	}
+}
+
+// Day 31 lands on the last day of a shorter month.
+func TestRunRecurring_ClampsToMonthEnd(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	createRecurring(t, svc, &model.CreateRecurringRequest{Amount: model.Dollars(3), DayOfMonth: 31, StartMonth: "2025-02", EndMonth: "2025-02"})
+
+	if _, err := svc.RunRecurring(context.Background(), recurringNow); err != nil {
+		t.Fatalf("RunRecurring: %v", err)
+	}
+	got := expensesIn(repo, "2025-02")
+	if len(got) != 1 || got[0].CreatedAt.Day() != 28 {
+		t.Fatalf("2025-02 expenses = %+v, want one on the 28th", got)
+	}
+}
+
+// Under hard-stop an unaffordable occurrence is skipped, the cursor stays
+// put, and the next run books it once funds arrive.
+func TestRunRecurring_InsufficientFundsIsRetried(t *testing.T) {
+	svc, repo := newExpenseService(t, false, false, 0)
+	testutil.SeedMonth(repo, "2025-03", 0, 5, 0, 5)
+	rec := createRecurring(t, svc, &model.CreateRecurringRequest{Amount: model.Dollars(8), DayOfMonth: 1, StartMonth: "2025-03"})
+
+	resp, err := svc.RunRecurring(context.Background(), recurringNow)
+	if err != nil {
+		t.Fatalf("RunRecurring: %v", err)
+	}
+	if len(resp.Skipped) != 1 || resp.Skipped[0].Month != "2025-03" {
+		t.Fatalf("skipped = %+v, want the 2025-03 occurrence", resp.Skipped)
+	}
+	if repo.Recurring[rec.ID].LastBookedMonth != "" {
+		t.Errorf("cursor = %q, a skipped occurrence must not advance it", repo.Recurring[rec.ID].LastBookedMonth)
+	}
+
+	if _, err := svc.AddFunds(context.Background(), "2025-03", model.Dollars(10), ""); err != nil {
+		t.Fatalf("AddFunds: %v", err)
+	}
+	resp, err = svc.RunRecurring(context.Background(), recurringNow)
+	if err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if len(resp.Booked) != 1 {
+		t.Errorf("retry booked %d, want 1", len(resp.Booked))
+	}
+	if repo.Months["2025-03"].EndingBalance != model.Dollars(7) {
+		t.Errorf("ending balance = %v, want 7", repo.Months["2025-03"].EndingBalance)
+	}
+}
+
+// Editing a schedule never moves its booking cursor.
+func TestUpdateRecurring_KeepsCursor(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	rec := createRecurring(t, svc, &model.CreateRecurringRequest{Amount: model.Dollars(4), DayOfMonth: 2, StartMonth: "2025-03"})
+	if _, err := svc.RunRecurring(context.Background(), recurringNow); err != nil {
+		t.Fatalf("RunRecurring: %v", err)
+	}
+
+	amount, day := model.Dollars(6), 10
+	updated, err := svc.UpdateRecurring(context.Background(), rec.ID, &model.UpdateRecurringRequest{Amount: &amount, DayOfMonth: &day})
+	if err != nil {
+		t.Fatalf("UpdateRecurring: %v", err)
+	}
+	if updated.Amount != model.Dollars(6) || updated.DayOfMonth != 10 {
+		t.Errorf("updated = %+v", updated)
+	}
+	stored := repo.Recurring[rec.ID]
+	if stored.Amount != model.Dollars(6) || stored.LastBookedMonth != "2025-03" {
+		t.Errorf("stored = %+v, want amount 6 and cursor 2025-03", stored)
+	}
+
+	if _, err := svc.UpdateRecurring(context.Background(), "missing", &model.UpdateRecurringRequest{Amount: &amount}); !errors.Is(err, ErrRecurringNotFound) {
+		t.Errorf("missing id err = %v, want ErrRecurringNotFound", err)
+	}
+	if _, err := svc.UpdateRecurring(context.Background(), rec.ID, &model.UpdateRecurringRequest{}); !errors.Is(err, ErrNoChanges) {
+		t.Errorf("empty update err = %v, want ErrNoChanges", err)
+	}
+}
+
+func TestDeleteRecurring_KeepsBookedExpenses(t *testing.T) {
+	svc, repo := newExpenseService(t, true, false, 0)
+	rec := createRecurring(t, svc, &model.CreateRecurringRequest{Amount: model.Dollars(4), DayOfMonth: 2, StartMonth: "2025-03"})
+	if _, err := svc.RunRecurring(context.Background(), recurringNow); err != nil {
+		t.Fatalf("RunRecurring: %v", err)
+	}
+
+	if err := svc.DeleteRecurring(context.Background(), rec.ID); err != nil {
+		t.Fatalf("DeleteRecurring: %v", err)
+	}
+	if len(expensesIn(repo, "2025-03")) != 1 {
+		t.Error("the booked expense must survive its schedule")
+	}
+	if err := svc.DeleteRecurring(context.Background(), rec.ID); !errors.Is(err, ErrRecurringNotFound) {
+		t.Errorf("second delete err = %v, want ErrRecurringNotFound", err)
+	}
+}
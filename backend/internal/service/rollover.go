@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// maxRolloverGap bounds how many missing months one rollover fills. An
+// instance left untouched for longer than this gets the most recent two
+// years of allowance, not an unbounded back-fill in a single invocation.
+const maxRolloverGap = 24
+
+// RolloverMonths activates every month from the one after the newest
+// existing month up to and including the month of now, oldest first, so
+// each carries from the one before it. Each goes through CreateMonth, which
+// grants the allowance to a missing month and tops up one that exists only
+// as a $0 auto-created row; an already-activated month is left alone. It is
+// safe to run any number of times. Returns the months it activated.
+func (s *ExpenseService) RolloverMonths(ctx context.Context, now time.Time) ([]string, error) {
+	now = now.UTC()
+	current := fmt.Sprintf("%04d-%02d", now.Year(), now.Month())
+
+	start := current
+	latest, err := s.latestMonthBefore(ctx, GetNextMonth(current))
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && latest.Month < current {
+		start = GetNextMonth(latest.Month)
+	}
+	earliest := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -(maxRolloverGap - 1), 0)
+	if floor := fmt.Sprintf("%04d-%02d", earliest.Year(), earliest.Month()); start < floor {
+		start = floor
+	}
+
+	activated := []string{}
+	for month := start; month <= current; month = GetNextMonth(month) {
+		if _, err := s.CreateMonth(ctx, month); err != nil {
+			if errors.Is(err, ErrMonthExists) {
+				continue
+			}
+			return activated, fmt.Errorf("rollover %s: %w", month, err)
+		}
+		activated = append(activated, month)
+	}
+	return activated, nil
+}
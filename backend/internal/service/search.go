@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+)
+
+// ErrSearchQueryTooShort is returned when a search query holds no word of
+// at least two letters or digits. Handler maps to 400.
+var ErrSearchQueryTooShort = errors.New("search query too short")
+
+const (
+	// maxSearchQueryRunes bounds the query like a description is bounded;
+	// nothing longer could match one.
+	maxSearchQueryRunes = maxDescriptionRunes
+	// maxSearchScanned bounds the index entries one search request reads
+	// while filtering. A multi-word query whose first word is common could
+	// otherwise walk the whole index to fill a page; instead the page comes
+	// back short, with a cursor to carry on from.
+	maxSearchScanned = 500
+)
+
+// ensureSearchIndex runs the repository's one-time backfill of the search
+// index before the first search this process serves. Same shape as
+// ensureMonthListComplete.
+func (s *ExpenseService) ensureSearchIndex(ctx context.Context) error {
+	if s.searchIndexed.Load() {
+		return nil
+	}
+	if err := s.repo.EnsureSearchIndex(ctx); err != nil {
+		return err
+	}
+	s.searchIndexed.Store(true)
+	return nil
+}
+
+// Search finds expenses, across every month, whose description has a word
+// beginning with each word of query — "piz" finds "Pizza night", and
+// "pizza hut" needs both. The longest query word drives the index Query;
+// the others filter. Every hit is re-read from its expense row and checked
+// against the current description, so what is returned is always live.
+// An expense appears once even when several of its words match, on the
+// hit for its greatest matching word, which keeps that true across pages
+// without any state in the cursor.
+func (s *ExpenseService) Search(ctx context.Context, query string, limit int32, cursorStr string) (*model.SearchResponse, error) {
+	query = strings.TrimSpace(query)
+	words := repository.SearchTokens(query)
+	if len(words) == 0 || len([]rune(query)) > maxSearchQueryRunes {
+		return nil, ErrSearchQueryTooShort
+	}
+	primary := words[0]
+	for _, w := range words[1:] {
+		if len(w) > len(primary) {
+			primary = w
+		}
+	}
+
+	var cursor map[string]types.AttributeValue
+	if cursorStr != "" {
+		var err error
+		cursor, err = decodeCursor(cursorStr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+		}
+		if err := validateSearchCursor(ctx, cursor, primary); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.ensureSearchIndex(ctx); err != nil {
+		return nil, err
+	}
+
+	results := []model.ExpenseItem{}
+	var next map[string]types.AttributeValue
+	for scanned := 0; ; {
+		hits, lastKey, err := s.repo.SearchExpenses(ctx, primary, limit, cursor)
+		if err != nil {
+			return nil, err
+		}
+		expenses, err := s.searchHitExpenses(ctx, hits)
+		if err != nil {
+			return nil, err
+		}
+		for i, h := range hits {
+			e, ok := expenses[repository.ExpenseRef{Month: h.Month, ExpenseID: h.ExpenseID}]
+			if !ok || !searchMatches(e.Description, words, primary, h.Token) {
+				continue
+			}
+			results = append(results, model.ExpenseItem{
+				ID:           e.SK,
+				Amount:       e.Amount,
+				Description:  e.Description,
+				Category:     e.Category,
+				CreatedAt:    e.CreatedAt,
+				Month:        h.Month,
+				InstalmentID: e.InstalmentID,
+				Attachments:  e.Attachments,
+			})
+			if int32(len(results)) == limit {
+				// Resume right after this hit unless it was the last entry.
+				if i < len(hits)-1 || lastKey != nil {
+					next = h.Key(ctx)
+				}
+				break
+			}
+		}
+		scanned += len(hits)
+		if int32(len(results)) == limit || lastKey == nil {
+			break
+		}
+		if scanned >= maxSearchScanned {
+			next = lastKey
+			break
+		}
+		cursor = lastKey
+	}
+
+	response := &model.SearchResponse{Query: query, Results: results}
+	if next != nil {
+		nextCursor, err := encodeCursor(next)
+		if err != nil {
+			return nil, err
+		}
+		response.NextCursor = nextCursor
+	}
+	return response, nil
+}
+
+// searchHitExpenses reads the expense rows behind a page of hits, keyed by
+// reference. A page can name one expense more than once (two of its words
+// share the prefix), and BatchGetItem refuses duplicate keys.
+func (s *ExpenseService) searchHitExpenses(ctx context.Context, hits []repository.SearchHit) (map[repository.ExpenseRef]model.Expense, error) {
+	var refs []repository.ExpenseRef
+	seen := make(map[repository.ExpenseRef]bool, len(hits))
+	for _, h := range hits {
+		ref := repository.ExpenseRef{Month: h.Month, ExpenseID: h.ExpenseID}
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	expenses, err := s.repo.BatchGetExpenses(ctx, refs)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[repository.ExpenseRef]model.Expense, len(expenses))
+	for _, e := range expenses {
+		out[repository.ExpenseRef{Month: repository.MonthOfPK(e.PK), ExpenseID: e.SK}] = e
+	}
+	return out, nil
+}
+
+// searchMatches reports whether a hit on token should be returned for an
+// expense now described as description: token must still be one of its
+// words (else the entry is stale), it must be the greatest of the words
+// the primary prefix matches (so the expense is returned once), and every
+// query word must prefix some word of the description.
+func searchMatches(description string, words []string, primary, token string) bool {
+	have := repository.SearchTokens(description)
+	if !slices.Contains(have, token) {
+		return false
+	}
+	for _, h := range have {
+		if strings.HasPrefix(h, primary) && h > token {
+			return false
+		}
+	}
+	for _, w := range words {
+		if !slices.ContainsFunc(have, func(h string) bool { return strings.HasPrefix(h, w) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateSearchCursor checks that a decoded search cursor is an index key
+// inside this query's prefix, the counterpart of validateExpenseCursor.
+func validateSearchCursor(ctx context.Context, cursor map[string]types.AttributeValue, primary string) error {
+	if len(cursor) != 2 {
+		return ErrInvalidCursor
+	}
+	pkAV, pkOK := cursor["PK"].(*types.AttributeValueMemberS)
+	skAV, skOK := cursor["SK"].(*types.AttributeValueMemberS)
+	if !pkOK || !skOK || pkAV.Value != repository.AccountPK(ctx, repository.PKSearch) || !strings.HasPrefix(skAV.Value, primary) {
+		return ErrInvalidCursor
+	}
+	return nil
+}
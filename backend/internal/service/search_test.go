@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+	"github.com/vppillai/passbook/backend/internal/testutil"
+)
+
+// =====================================================================
+// Search — a word index maintained by the expense transactions and
+// queried by prefix, with every hit re-checked against the live row.
+// =====================================================================
+
+// addDescribed books an expense with a description on date.
+func addDescribed(t *testing.T, svc *ExpenseService, description, date string) *model.Expense {
+	t.Helper()
+	resp, err := svc.AddExpense(context.Background(), &model.AddExpenseRequest{
+		Amount: model.Dollars(1), Description: description, Date: date,
+	})
+	if err != nil {
+		t.Fatalf("AddExpense(%q): %v", description, err)
+	}
+	return resp.Expense
+}
+
+// searchAll pages through every result for query, limit at a time.
+func searchAll(t *testing.T, svc *ExpenseService, query string, limit int32) []model.ExpenseItem {
+	t.Helper()
+	var out []model.ExpenseItem
+	cursor := ""
+	for page := 0; page < 20; page++ {
+		resp, err := svc.Search(context.Background(), query, limit, cursor)
+		if err != nil {
+			t.Fatalf("Search(%q): %v", query, err)
+		}
+		out = append(out, resp.Results...)
+		if resp.NextCursor == "" {
+			return out
+		}
+		cursor = resp.NextCursor
+	}
+	t.Fatalf("Search(%q) did not finish paging", query)
+	return nil
+}
+
+func descriptions(items []model.ExpenseItem) []string {
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i] = item.Description
+	}
+	return out
+}
+
+func newSearchService(t *testing.T) (*ExpenseService, *testutil.FakeRepo) {
+	t.Helper()
+	svc, repo := newExpenseService(t, true, true, 0)
+	for n := 1; n <= 3; n++ {
+		month, _ := pastMonthDay(n, 1)
+		testutil.SeedMonth(repo, month, 0, 100, 0, 100)
+	}
+	return svc, repo
+}
+
+func TestSearch_AcrossMonthsNewestFirst(t *testing.T) {
+	svc, _ := newSearchService(t)
+	_, oldest := pastMonthDay(3, 5)
+	_, middle := pastMonthDay(2, 5)
+	_, newest := pastMonthDay(1, 5)
+	addDescribed(t, svc, "Pizza night", oldest)
+	addDescribed(t, svc, "Groceries", middle)
+	addDescribed(t, svc, "pizza & movie", newest)
+
+	got := searchAll(t, svc, "PIZZA", 50)
+	if len(got) != 2 || got[0].Description != "pizza & movie" || got[1].Description != "Pizza night" {
+		t.Fatalf("results = %q, want the two pizzas newest first", descriptions(got))
+	}
+	if month, _ := pastMonthDay(1, 5); got[0].Month != month {
+		t.Errorf("result month = %q, want %q", got[0].Month, month)
+	}
+
+	if got := searchAll(t, svc, "piz mov", 50); len(got) != 1 || got[0].Description != "pizza & movie" {
+		t.Errorf("two-word prefix query = %q, want only the one with both words", descriptions(got))
+	}
+	if got := searchAll(t, svc, "sushi", 50); len(got) != 0 {
+		t.Errorf("no-match query = %q", descriptions(got))
+	}
+}
+
+// Paging one result at a time visits every match once, including an
+// expense with two words under the same prefix.
+func TestSearch_PaginatesWithoutDuplicates(t *testing.T) {
+	svc, _ := newSearchService(t)
+	_, date := pastMonthDay(1, 5)
+	addDescribed(t, svc, "Pizza from the pizzeria", date)
+	addDescribed(t, svc, "Pizza", date)
+	addDescribed(t, svc, "Pizzeria tip", date)
+
+	got := searchAll(t, svc, "pizz", 1)
+	if len(got) != 3 {
+		t.Fatalf("results = %q, want 3 distinct expenses", descriptions(got))
+	}
+	seen := map[string]bool{}
+	for _, item := range got {
+		if seen[item.ID] {
+			t.Errorf("expense %s returned twice", item.ID)
+		}
+		seen[item.ID] = true
+	}
+}
+
+// Edits, re-dates and deletes keep the index in step, so a search always
+// answers from the current descriptions.
+func TestSearch_FollowsExpenseChanges(t *testing.T) {
+	svc, repo := newSearchService(t)
+	ctx := context.Background()
+	month, date := pastMonthDay(1, 5)
+	e := addDescribed(t, svc, "Pizza", date)
+
+	description := "Burrito"
+	resp, err := svc.UpdateExpense(ctx, month, e.SK, &model.UpdateExpenseRequest{Description: &description})
+	if err != nil {
+		t.Fatalf("UpdateExpense: %v", err)
+	}
+	if got := searchAll(t, svc, "pizza", 50); len(got) != 0 {
+		t.Errorf("old word still finds %q", descriptions(got))
+	}
+	if got := searchAll(t, svc, "burr", 50); len(got) != 1 {
+		t.Errorf("new word finds %q, want the edited expense", descriptions(got))
+	}
+
+	target, newDate := pastMonthDay(2, 7)
+	moved, err := svc.UpdateExpense(ctx, month, resp.Expense.ID, &model.UpdateExpenseRequest{Date: newDate})
+	if err != nil {
+		t.Fatalf("UpdateExpense (re-date): %v", err)
+	}
+	if got := searchAll(t, svc, "burrito", 50); len(got) != 1 || got[0].Month != target || got[0].ID != moved.Expense.ID {
+		t.Errorf("after the move results = %+v, want the row in %s", got, target)
+	}
+
+	if err := svc.DeleteExpense(ctx, target, moved.Expense.ID); err != nil {
+		t.Fatalf("DeleteExpense: %v", err)
+	}
+	if got := searchAll(t, svc, "burrito", 50); len(got) != 0 {
+		t.Errorf("deleted expense still found: %q", descriptions(got))
+	}
+	if len(repo.Search) != 0 {
+		t.Errorf("index holds %d entries after the only expense went, want 0", len(repo.Search))
+	}
+}
+
+// Rows written before the index existed are back-filled on the first
+// search; an entry whose expense is gone is skipped rather than served.
+func TestSearch_BackfillsAndSkipsStaleEntries(t *testing.T) {
+	svc, repo := newSearchService(t)
+	month, _ := pastMonthDay(2, 1)
+	repo.Expenses[testutil.ExpenseKey(month, "EXP#1#legacy")] = &model.Expense{
+		PK: "MONTH#" + month, SK: "EXP#1#legacy", Amount: model.Dollars(3), Description: "Old pizza",
+	}
+	stale := repository.SearchHit{Token: "pizza", Month: month, ExpenseID: "EXP#2#gone"}
+	repo.Search["pizza#"+month+"#EXP#2#gone"] = stale
+
+	got := searchAll(t, svc, "pizza", 50)
+	if len(got) != 1 || got[0].ID != "EXP#1#legacy" {
+		t.Errorf("results = %+v, want only the back-filled legacy row", got)
+	}
+	if !repo.SearchIndexed {
+		t.Error("backfill did not run")
+	}
+}
+
+// Renaming an instalment plan re-files its unpaid instalments; the plan
+// transaction itself has no room for the index entries.
+func TestSearch_FollowsInstalmentPlan(t *testing.T) {
+	svc, repo := newSearchService(t)
+	repo.Balance.TotalBalance = model.Dollars(300)
+	_, date := pastMonthDay(2, 10)
+	plan := createInstalments(t, svc, &model.CreateInstalmentRequest{
+		Amount: model.Dollars(20), Description: "Bike", Count: 2, Date: date,
+	})
+
+	description := "Scooter"
+	if _, err := svc.UpdateInstalmentPlan(context.Background(), plan.ID,
+		&model.UpdateInstalmentRequest{Description: &description}, midMonth(3)); err != nil {
+		t.Fatalf("UpdateInstalmentPlan: %v", err)
+	}
+	if got := searchAll(t, svc, "scooter", 50); len(got) != 2 {
+		t.Errorf("new name finds %q, want both instalments", descriptions(got))
+	}
+	if got := searchAll(t, svc, "bike", 50); len(got) != 0 {
+		t.Errorf("old name still finds %q", descriptions(got))
+	}
+
+	if err := svc.DeleteInstalmentPlan(context.Background(), plan.ID, midMonth(3)); err != nil {
+		t.Fatalf("DeleteInstalmentPlan: %v", err)
+	}
+	if len(repo.Search) != 0 {
+		t.Errorf("index holds %d entries after the plan went, want 0", len(repo.Search))
+	}
+}
+
+func TestSearch_Refusals(t *testing.T) {
+	svc, _ := newSearchService(t)
+	ctx := context.Background()
+	for _, q := range []string{"", "  ", "a", "& !"} {
+		if _, err := svc.Search(ctx, q, 10, ""); !errors.Is(err, ErrSearchQueryTooShort) {
+			t.Errorf("Search(%q) err = %v, want ErrSearchQueryTooShort", q, err)
+		}
+	}
+
+	other, err := encodeCursor(repository.SearchHit{Token: "burrito", Month: "2025-01", ExpenseID: "EXP#1#a"}.Key(ctx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cursor := range []string{"not-base64!", other} {
+		if _, err := svc.Search(ctx, "pizza", 10, cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("cursor %q err = %v, want ErrInvalidCursor", cursor, err)
+		}
+	}
+}
@@ -24,7 +24,7 @@ func TestMintSession_SurvivesAFailureToClearTheRateLimit(t *testing.T) {
 	repo := testutil.NewFakeRepo()
 	repo.ClearRateLimitErr = errors.New("transient DynamoDB failure")
 
-	resp, err := mintSession(ctx, repo, "203.0.113.9")
+	resp, err := mintSession(ctx, repo, "203.0.113.9", model.RoleAdmin)
 	if err != nil {
 		t.Fatalf("mintSession failed because the counter could not be cleared: %v — the "+
 			"login had already succeeded", err)
@@ -44,7 +44,7 @@ func TestMintSession_FailsWhenTheSessionCannotBePersisted(t *testing.T) {
 	repo := testutil.NewFakeRepo()
 	repo.CreateSessionErr = errors.New("transient DynamoDB failure")
 
-	if _, err := mintSession(ctx, repo, "203.0.113.9"); err == nil {
+	if _, err := mintSession(ctx, repo, "203.0.113.9", model.RoleAdmin); err == nil {
 		t.Fatal("mintSession succeeded although the session could not be stored")
 	}
 }
@@ -59,7 +59,7 @@ func TestMintSession_ClearsTheCounterOnSuccess(t *testing.T) {
 		t.Fatalf("seeding: %v", err)
 	}
 
-	if _, err := mintSession(ctx, repo, ip); err != nil {
+	if _, err := mintSession(ctx, repo, ip, model.RoleAdmin); err != nil {
 		t.Fatalf("mintSession: %v", err)
 	}
 	if entry, err := repo.GetRateLimitEntry(ctx, ip); err != nil {
@@ -96,15 +96,15 @@ func TestDeleteMonth_RefusesWhenTheAllowanceChangedUnderIt(t *testing.T) {
 	ctx := context.Background()
 	svc, repo := newExpenseService(t, false, true, 100)
 	testutil.SeedMonth(repo, "2026-03", 0, 100, 0, 100)
-	repo.Balance = &model.Balance{TotalBalance: 100}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(100)}
 
 	// Stand in for a top-up that lands between the pre-read and the delete: the
 	// stored allowance no longer matches what DeleteMonth is about to debit.
 	repo.BeforeDeleteMonth = func() {
 		m := repo.Months["2026-03"]
-		m.AllowanceAdded = 150
-		m.EndingBalance = 150
-		repo.Balance.TotalBalance = 150
+		m.AllowanceAdded = model.Dollars(150)
+		m.EndingBalance = model.Dollars(150)
+		repo.Balance.TotalBalance = model.Dollars(150)
 	}
 
 	err := svc.DeleteMonth(ctx, "2026-03")
@@ -114,8 +114,8 @@ func TestDeleteMonth_RefusesWhenTheAllowanceChangedUnderIt(t *testing.T) {
 	if repo.Months["2026-03"] == nil {
 		t.Error("the month was deleted despite its allowance changing under the delete")
 	}
-	if got := repo.Balance.TotalBalance; got != 150 {
-		t.Errorf("global balance = %.2f, want 150 — a stale allowance was debited", got)
+	if got := repo.Balance.TotalBalance; got != model.Dollars(150) {
+		t.Errorf("global balance = %v, want 150 — a stale allowance was debited", got)
 	}
 }
 
@@ -125,7 +125,7 @@ func TestDeleteMonth_StillDeletesAnUnchangedMonth(t *testing.T) {
 	svc, repo := newExpenseService(t, false, true, 100)
 	testutil.SeedMonth(repo, "2026-02", 0, 100, 0, 100)
 	testutil.SeedMonth(repo, "2026-03", 100, 100, 0, 200)
-	repo.Balance = &model.Balance{TotalBalance: 200}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(200)}
 
 	if err := svc.DeleteMonth(ctx, "2026-02"); err != nil {
 		t.Fatalf("DeleteMonth: %v", err)
@@ -133,12 +133,12 @@ func TestDeleteMonth_StillDeletesAnUnchangedMonth(t *testing.T) {
 	if repo.Months["2026-02"] != nil {
 		t.Error("month not deleted")
 	}
-	if got := repo.Balance.TotalBalance; got != 100 {
-		t.Errorf("global balance = %.2f, want 100 (200 - the deleted month's 100)", got)
+	if got := repo.Balance.TotalBalance; got != model.Dollars(100) {
+		t.Errorf("global balance = %v, want 100 (200 - the deleted month's 100)", got)
 	}
 	// March must fall back to carrying nothing, since February is gone.
 	if got := repo.Months["2026-03"].StartingBalance; got != 0 {
-		t.Errorf("March starting_balance = %.2f, want 0", got)
+		t.Errorf("March starting_balance = %v, want 0", got)
 	}
 }
 
@@ -147,7 +147,7 @@ func TestDeleteMonth_MonthWithExpensesIsStillRefusedAsSuch(t *testing.T) {
 	ctx := context.Background()
 	svc, repo := newExpenseService(t, false, true, 100)
 	testutil.SeedMonth(repo, "2026-03", 0, 100, 25, 75)
-	repo.Balance = &model.Balance{TotalBalance: 75}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(75)}
 
 	if err := svc.DeleteMonth(ctx, "2026-03"); !errors.Is(err, ErrMonthHasExpenses) {
 		t.Fatalf("err = %v, want ErrMonthHasExpenses", err)
@@ -159,7 +159,7 @@ func TestDeleteMonth_VanishedMonthReportsNotFound(t *testing.T) {
 	ctx := context.Background()
 	svc, repo := newExpenseService(t, false, true, 100)
 	testutil.SeedMonth(repo, "2026-03", 0, 100, 0, 100)
-	repo.Balance = &model.Balance{TotalBalance: 100}
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(100)}
 
 	repo.BeforeDeleteMonth = func() {
 		delete(repo.Months, "2026-03")
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+)
+
+// settingsTTL is how long a process trusts its copy of the runtime
+// settings. A change made through another Lambda instance reaches this one
+// within it; a change made through this one applies at once.
+const settingsTTL = 30 * time.Second
+
+// Settings returns the instance's effective overspending and carry-over
+// modes: the CONFIG row's overrides, or the deployment's
+// ALLOW_OVERSPENDING and CARRY_OVER_BALANCE where it has none.
+//
+// Every write path asks for them, so they are cached in process for
+// settingsTTL rather than read from CONFIG on each call.
+func (s *ExpenseService) Settings(ctx context.Context) (model.Settings, error) {
+	s.settingsMu.Lock()
+	defer s.settingsMu.Unlock()
+	if time.Now().Before(s.settingsExpiry) {
+		return s.settingsCache, nil
+	}
+	config, err := s.repo.GetConfig(ctx)
+	if err != nil {
+		return model.Settings{}, err
+	}
+	s.cacheSettings(s.settingsFrom(config))
+	return s.settingsCache, nil
+}
+
+// RechainIncompleteError is returned by UpdateSettings when the settings
+// were saved but not every account could be re-chained. Rechained lists the
+// accounts done and Remaining those left, in ListAccounts order; repeating
+// the request finishes them. It unwraps to each remaining account's error,
+// so a concurrent ledger write is errors.Is ErrLedgerModified. Handler maps
+// to 409 when every remaining account is ErrLedgerModified, else to 500.
+type RechainIncompleteError struct {
+	Settings  model.Settings
+	Rechained []string
+	Remaining []string
+	errs      []error
+}
+
+func (e *RechainIncompleteError) Error() string {
+	return fmt.Sprintf("settings saved; re-chain incomplete for %d accounts: %v", len(e.Remaining), errors.Join(e.errs...))
+}
+
+func (e *RechainIncompleteError) Unwrap() []error { return e.errs }
+
+// UpdateSettings changes the overspending and carry-over modes on the
+// CONFIG row. Only the attributes the request names are written
+// (UpdateConfig), as SetCategoryBudgets does.
+//
+// Carry-over decides every month's starting balance, so a request that
+// names it re-chains each account's ledger through RepairLedger: with it
+// on, each month starts from the previous month's ending balance; with it
+// off, from 0. allowance_added, expenses and BALANCE do not move, and
+// interest already credited is not re-priced. The re-chain is a repair, not
+// a spend, so a hard-stop instance does not refuse it even where a month
+// ends negative.
+//
+// An account whose re-chain fails does not stop the others; the settings
+// stay saved and a *RechainIncompleteError names the accounts done and
+// left. A request that names carry-over without changing it re-chains all
+// the same, which is how the rest are finished.
+func (s *ExpenseService) UpdateSettings(ctx context.Context, req *model.UpdateSettingsRequest) (*model.Settings, error) {
+	if req.AllowOverspending == nil && req.CarryOverBalance == nil {
+		return nil, ErrNoChanges
+	}
+	update := &model.Config{AllowOverspending: req.AllowOverspending, CarryOverBalance: req.CarryOverBalance}
+	var fields []string
+	if req.AllowOverspending != nil {
+		fields = append(fields, repository.ConfigAllowOverspending)
+	}
+	if req.CarryOverBalance != nil {
+		fields = append(fields, repository.ConfigCarryOverBalance)
+	}
+	if err := s.updateConfig(ctx, "update settings", update, fields...); err != nil {
+		return nil, err
+	}
+	// Read the row back: the mode this request left alone may have been
+	// changed by another instance since this one cached it.
+	config, err := s.repo.GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	settings := s.settingsFrom(config)
+	s.settingsMu.Lock()
+	s.cacheSettings(settings)
+	s.settingsMu.Unlock()
+
+	if req.CarryOverBalance != nil {
+		accounts, err := s.ListAccounts(ctx)
+		if err != nil {
+			return nil, err
+		}
+		incomplete := &RechainIncompleteError{Settings: settings}
+		for _, account := range accounts.Accounts {
+			if _, err := s.RepairLedger(repository.WithAccount(ctx, account.ID)); err != nil {
+				incomplete.Remaining = append(incomplete.Remaining, account.ID)
+				incomplete.errs = append(incomplete.errs, fmt.Errorf("re-chain account %s: %w", account.ID, err))
+				continue
+			}
+			incomplete.Rechained = append(incomplete.Rechained, account.ID)
+		}
+		if len(incomplete.Remaining) > 0 {
+			return nil, incomplete
+		}
+	}
+	return &settings, nil
+}
+
+// settingsFrom resolves config's overrides against the deployment values.
+func (s *ExpenseService) settingsFrom(config *model.Config) model.Settings {
+	settings := model.Settings{AllowOverspending: s.allowOverspending, CarryOverBalance: s.carryOverBalance}
+	if config == nil {
+		return settings
+	}
+	if config.AllowOverspending != nil {
+		settings.AllowOverspending = *config.AllowOverspending
+	}
+	if config.CarryOverBalance != nil {
+		settings.CarryOverBalance = *config.CarryOverBalance
+	}
+	return settings
+}
+
+// cacheSettings stores settings for settingsTTL. The caller holds
+// settingsMu.
+func (s *ExpenseService) cacheSettings(settings model.Settings) {
+	s.settingsCache = settings
+	s.settingsExpiry = time.Now().Add(settingsTTL)
+}
+
+// hardStop reports whether writes must keep every balance non-negative.
+func (s *ExpenseService) hardStop(ctx context.Context) (bool, error) {
+	settings, err := s.Settings(ctx)
+	return !settings.AllowOverspending, err
+}
+
+// carriesOver reports whether a month starts from the previous month's
+// ending balance.
+func (s *ExpenseService) carriesOver(ctx context.Context) (bool, error) {
+	settings, err := s.Settings(ctx)
+	return settings.CarryOverBalance, err
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/testutil"
+)
+
+// =====================================================================
+// Runtime settings — the CONFIG row overrides the deployment's modes,
+// and toggling carry-over re-chains the existing months.
+// =====================================================================
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestSettings_OverspendingOverride(t *testing.T) {
+	svc, repo := newExpenseService(t, false, true, 100)
+	repo.Config = &model.Config{PinHash: "x"}
+	testutil.SeedMonth(repo, "2026-02", 0, 10, 0, 10)
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(10)}
+	ctx := context.Background()
+
+	overspend := &model.AddExpenseRequest{Amount: model.Dollars(25), Description: "Game", Month: "2026-02"}
+	if _, err := svc.AddExpense(ctx, overspend); !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("hard-stop AddExpense err = %v, want ErrInsufficientFunds", err)
+	}
+	settings, err := svc.UpdateSettings(ctx, &model.UpdateSettingsRequest{AllowOverspending: boolPtr(true)})
+	if err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+	if !settings.AllowOverspending || !settings.CarryOverBalance {
+		t.Errorf("settings = %+v, want overspending on and carry-over kept", settings)
+	}
+	if _, err := svc.AddExpense(ctx, overspend); err != nil {
+		t.Fatalf("AddExpense with overspending on: %v", err)
+	}
+	if got := repo.Months["2026-02"].EndingBalance; got != model.Dollars(-15) {
+		t.Errorf("ending = %v, want -15", got)
+	}
+
+	if _, err := svc.UpdateSettings(ctx, &model.UpdateSettingsRequest{}); !errors.Is(err, ErrNoChanges) {
+		t.Errorf("empty update err = %v, want ErrNoChanges", err)
+	}
+}
+
+func TestSettings_TogglingCarryOverRechains(t *testing.T) {
+	svc, repo := newExpenseService(t, true, true, 100)
+	repo.Config = &model.Config{PinHash: "x"}
+	testutil.SeedMonth(repo, "2026-01", 0, 100, 0, 100)
+	testutil.SeedMonth(repo, "2026-02", 100, 50, 0, 150)
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(150)}
+	ctx := context.Background()
+
+	if _, err := svc.UpdateSettings(ctx, &model.UpdateSettingsRequest{CarryOverBalance: boolPtr(false)}); err != nil {
+		t.Fatalf("UpdateSettings(carry off): %v", err)
+	}
+	if got := repo.Months["2026-02"]; got.StartingBalance != 0 || got.EndingBalance != model.Dollars(50) {
+		t.Errorf("February with carry off = %+v, want 0 -> 50", got)
+	}
+	if got := repo.MonthList["2026-02"]; got.EndingBalance != model.Dollars(50) {
+		t.Errorf("February mirror ending = %v, want 50", got.EndingBalance)
+	}
+	if repo.Balance.TotalBalance != model.Dollars(150) {
+		t.Errorf("balance = %v, want 150 untouched", repo.Balance.TotalBalance)
+	}
+
+	if _, err := svc.UpdateSettings(ctx, &model.UpdateSettingsRequest{CarryOverBalance: boolPtr(true)}); err != nil {
+		t.Fatalf("UpdateSettings(carry on): %v", err)
+	}
+	if got := repo.Months["2026-02"]; got.StartingBalance != model.Dollars(100) || got.EndingBalance != model.Dollars(150) {
+		t.Errorf("February with carry on = %+v, want 100 -> 150", got)
+	}
+	report, err := svc.VerifyLedger(ctx)
+	if err != nil {
+		t.Fatalf("VerifyLedger: %v", err)
+	}
+	if len(report.Issues) != 0 {
+		t.Errorf("ledger issues = %v, want none", ledgerFields(report))
+	}
+}
+
+// A ledger that changes mid-way stops only its own account's re-chain: the
+// others are finished, the settings stay saved, and the error names which
+// account is left for a repeat of the request.
+func TestSettings_RechainReportsTheAccountsLeft(t *testing.T) {
+	svc, repo := newExpenseService(t, true, true, 100)
+	repo.Config = &model.Config{PinHash: "x"}
+	ctx := context.Background()
+	account, err := svc.CreateAccount(ctx, "Sam")
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	testutil.SeedMonth(repo, "2026-01", 0, 100, 0, 100)
+	testutil.SeedMonth(repo, "2026-02", 100, 50, 0, 150)
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(150)}
+	sam := repo.Ledger(account.ID)
+	testutil.SeedMonth(sam, "2026-01", 0, 100, 0, 100)
+	testutil.SeedMonth(sam, "2026-02", 100, 50, 0, 150)
+	sam.Balance = &model.Balance{TotalBalance: model.Dollars(150)}
+	sam.BeforeLedgerRepair = func() {
+		sam.BeforeLedgerRepair = nil
+		sam.Months["2026-02"].TotalExpenses += model.Dollars(1)
+	}
+
+	_, err = svc.UpdateSettings(ctx, &model.UpdateSettingsRequest{CarryOverBalance: boolPtr(false)})
+	var incomplete *RechainIncompleteError
+	if !errors.As(err, &incomplete) || !errors.Is(err, ErrLedgerModified) {
+		t.Fatalf("UpdateSettings err = %v, want a RechainIncompleteError of ErrLedgerModified", err)
+	}
+	if !slices.Equal(incomplete.Rechained, []string{model.DefaultAccountID}) || !slices.Equal(incomplete.Remaining, []string{account.ID}) {
+		t.Errorf("rechained %v, remaining %v; want the default account done and %s left", incomplete.Rechained, incomplete.Remaining, account.ID)
+	}
+	if got := repo.Months["2026-02"].StartingBalance; got != 0 {
+		t.Errorf("default February starts on %v, want 0", got)
+	}
+	if repo.Config.CarryOverBalance == nil || *repo.Config.CarryOverBalance || repo.Config.PinHash != "x" {
+		t.Errorf("config = %+v, want carry-over off saved beside the PIN", repo.Config)
+	}
+
+	// The repeat finishes the account that was left.
+	if _, err := svc.UpdateSettings(ctx, &model.UpdateSettingsRequest{CarryOverBalance: boolPtr(false)}); err != nil {
+		t.Fatalf("repeat UpdateSettings: %v", err)
+	}
+	if got := sam.Months["2026-02"].StartingBalance; got != 0 {
+		t.Errorf("Sam's February starts on %v, want 0", got)
+	}
+}
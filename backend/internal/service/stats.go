@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/repository"
+)
+
+// ErrInvalidStatsWindow is returned when a stats request asks for fewer
+// than one or more than maxStatsMonths months. Handler maps to 400.
+var ErrInvalidStatsWindow = errors.New("invalid stats window")
+
+const (
+	// defaultStatsMonths is the window GET /api/stats covers when the
+	// request does not say, and maxStatsMonths the widest it may ask for.
+	defaultStatsMonths = 6
+	maxStatsMonths     = 24
+	// statsRollingMonths is the span of StatsMonth.RollingAverageSpent.
+	statsRollingMonths = 3
+	// statsTopN bounds the biggest expenses and top descriptions reported.
+	statsTopN = 5
+	// statsCacheSize bounds the closed months statsFor keeps: ten accounts'
+	// widest windows.
+	statsCacheSize = 10 * maxStatsMonths
+)
+
+// monthStats is what GetStats takes from one month's expense rows.
+type monthStats struct {
+	// summary is the month summary the rows were read under; a cached
+	// entry is only reused while the month's summary still matches it.
+	summary      model.MonthSummary
+	count        int
+	biggest      []model.ExpenseItem
+	descriptions map[string]*model.DescriptionStat
+	byWeekday    [7]model.Money
+	byHour       [24]model.Money
+}
+
+// GetStats reports spending analytics over the months months ending with
+// to ("YYYY-MM", the month of now when empty). Income, spending, savings,
+// the rolling averages and the month-over-month changes come from the month
+// summaries; the expense count, biggest expenses, frequent descriptions and
+// the weekday and hour distributions from the window's expense rows.
+//
+// A closed month's rows are read once per process and cached (statsFor);
+// only the current month, still filling up, is read on every request.
+func (s *ExpenseService) GetStats(ctx context.Context, to string, months int, now time.Time) (*model.StatsResponse, error) {
+	current := monthOf(now)
+	if to == "" {
+		to = current
+	}
+	if ValidateMonth(to) != nil {
+		return nil, ErrInvalidMonth
+	}
+	if months == 0 {
+		months = defaultStatsMonths
+	}
+	if months < 1 || months > maxStatsMonths {
+		return nil, ErrInvalidStatsWindow
+	}
+
+	// The window, ascending, and the summaries it needs: its own and, for
+	// its first months' rolling averages and changes, the ones before it.
+	window := make([]string, months)
+	month := to
+	for i := months - 1; i >= 0; i-- {
+		window[i] = month
+		month = GetPreviousMonth(month)
+	}
+	summaries := make(map[string]*model.MonthSummary, months+statsRollingMonths-1)
+	lookback := window[0]
+	for i := 1; i < statsRollingMonths; i++ {
+		lookback = GetPreviousMonth(lookback)
+	}
+	for m := lookback; m <= to; m = GetNextMonth(m) {
+		summary, err := s.repo.GetMonthSummary(ctx, m)
+		if err != nil {
+			return nil, err
+		}
+		if summary != nil {
+			summaries[m] = summary
+		}
+	}
+
+	response := &model.StatsResponse{
+		From:            window[0],
+		To:              to,
+		Months:          []model.StatsMonth{},
+		BiggestExpenses: []model.ExpenseItem{},
+		TopDescriptions: []model.DescriptionStat{},
+		ByWeekday:       make([]model.Money, 7),
+		ByHour:          make([]model.Money, 24),
+	}
+	descriptions := map[string]*model.DescriptionStat{}
+	var income, spent, saved model.Money
+	for _, m := range window {
+		summary := summaries[m]
+		if summary == nil {
+			continue
+		}
+		stats, err := s.statsFor(ctx, summary, m < current)
+		if err != nil {
+			return nil, err
+		}
+
+		row := model.StatsMonth{
+			Month:        m,
+			Income:       summary.AllowanceAdded,
+			Spent:        summary.TotalExpenses,
+			Saved:        summary.AllowanceAdded - summary.TotalExpenses,
+			ExpenseCount: stats.count,
+		}
+		var rolling model.Money
+		var counted int
+		for k, i := m, 0; i < statsRollingMonths; k, i = GetPreviousMonth(k), i+1 {
+			if prev := summaries[k]; prev != nil {
+				rolling += prev.TotalExpenses
+				counted++
+			}
+		}
+		row.RollingAverageSpent = rolling / model.Money(counted)
+		if prev := summaries[GetPreviousMonth(m)]; prev != nil {
+			change := summary.TotalExpenses - prev.TotalExpenses
+			row.SpentChange = &change
+		}
+		response.Months = append(response.Months, row)
+		income += row.Income
+		spent += row.Spent
+		saved += row.Saved
+
+		response.BiggestExpenses = append(response.BiggestExpenses, stats.biggest...)
+		for key, d := range stats.descriptions {
+			if total := descriptions[key]; total != nil {
+				total.Count += d.Count
+				total.Total += d.Total
+			} else {
+				copied := *d
+				descriptions[key] = &copied
+			}
+		}
+		for i, amount := range stats.byWeekday {
+			response.ByWeekday[i] += amount
+		}
+		for i, amount := range stats.byHour {
+			response.ByHour[i] += amount
+		}
+	}
+
+	if n := model.Money(len(response.Months)); n > 0 {
+		response.AverageSpent = spent / n
+		response.AverageSaved = saved / n
+	}
+	if income > 0 {
+		rate := math.Round(float64(saved)*1000/float64(income)) / 10
+		response.SavingsRate = &rate
+	}
+	response.BiggestExpenses = biggestExpenses(response.BiggestExpenses)
+	for _, d := range descriptions {
+		response.TopDescriptions = append(response.TopDescriptions, *d)
+	}
+	sort.Slice(response.TopDescriptions, func(i, j int) bool {
+		a, b := response.TopDescriptions[i], response.TopDescriptions[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		return a.Description < b.Description
+	})
+	if len(response.TopDescriptions) > statsTopN {
+		response.TopDescriptions = response.TopDescriptions[:statsTopN]
+	}
+	return response, nil
+}
+
+// statsFor returns the stats of the month summary describes, reading its
+// expense rows. A closed month's stats are cached per account and month
+// and reused while its summary is unchanged: every write to a month's
+// expense rows also updates its summary, so a back-dated edit invalidates
+// the entry. At most statsCacheSize months are kept; a new one past that
+// evicts an arbitrary other, which costs only a re-read.
+func (s *ExpenseService) statsFor(ctx context.Context, summary *model.MonthSummary, closed bool) (*monthStats, error) {
+	key := repository.AccountPK(ctx, repository.MonthPrefix+summary.Month)
+	if closed {
+		s.statsMu.Lock()
+		cached := s.statsCache[key]
+		s.statsMu.Unlock()
+		if cached != nil && sameStatsSummary(&cached.summary, summary) {
+			return cached, nil
+		}
+	}
+
+	stats := &monthStats{summary: *summary, descriptions: map[string]*model.DescriptionStat{}}
+	var cursor map[string]types.AttributeValue
+	for {
+		page, lastKey, err := s.repo.GetExpenses(ctx, summary.Month, monthsAfterPageSize, cursor)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range page {
+			stats.add(summary.Month, e)
+		}
+		if lastKey == nil {
+			break
+		}
+		cursor = lastKey
+	}
+	stats.biggest = biggestExpenses(stats.biggest)
+
+	if closed {
+		s.statsMu.Lock()
+		if s.statsCache == nil {
+			s.statsCache = make(map[string]*monthStats)
+		}
+		if _, ok := s.statsCache[key]; !ok && len(s.statsCache) >= statsCacheSize {
+			for evict := range s.statsCache {
+				delete(s.statsCache, evict)
+				break
+			}
+		}
+		s.statsCache[key] = stats
+		s.statsMu.Unlock()
+	}
+	return stats, nil
+}
+
+// add counts one expense row of month.
+func (m *monthStats) add(month string, e model.Expense) {
+	m.count++
+	m.biggest = append(m.biggest, model.ExpenseItem{
+		ID:           e.SK,
+		Amount:       e.Amount,
+		Description:  e.Description,
+		Category:     e.Category,
+		CreatedAt:    e.CreatedAt,
+		Month:        month,
+		InstalmentID: e.InstalmentID,
+	})
+	if len(m.biggest) > 4*statsTopN {
+		m.biggest = biggestExpenses(m.biggest)
+	}
+	if key := strings.ToLower(strings.TrimSpace(e.Description)); key != "" {
+		d := m.descriptions[key]
+		if d == nil {
+			d = &model.DescriptionStat{Description: strings.TrimSpace(e.Description)}
+			m.descriptions[key] = d
+		}
+		d.Count++
+		d.Total += e.Amount
+	}
+	at := e.CreatedAt.UTC()
+	m.byWeekday[at.Weekday()] += e.Amount
+	m.byHour[at.Hour()] += e.Amount
+}
+
+// biggestExpenses sorts items by amount, largest first and newest first
+// among equals, and keeps the first statsTopN.
+func biggestExpenses(items []model.ExpenseItem) []model.ExpenseItem {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Amount != items[j].Amount {
+			return items[i].Amount > items[j].Amount
+		}
+		return items[i].CreatedAt.After(items[j].CreatedAt)
+	})
+	if len(items) > statsTopN {
+		items = items[:statsTopN]
+	}
+	return items
+}
+
+// sameStatsSummary reports whether a month's expense rows can have changed
+// between two reads of its summary. updated_at is written to the nanosecond
+// on every write, so an edit that leaves the total where it was, or two
+// edits within one second, still moves it.
+func sameStatsSummary(a, b *model.MonthSummary) bool {
+	return a.TotalExpenses == b.TotalExpenses && a.UpdatedAt.Equal(b.UpdatedAt)
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/testutil"
+)
+
+// =====================================================================
+// Stats — analytics over a window of months, from the summaries and the
+// expense rows, with closed months cached until they change.
+// =====================================================================
+
+var statsNow = time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+func newStatsService(t *testing.T) (*ExpenseService, *testutil.FakeRepo) {
+	t.Helper()
+	svc, repo := newExpenseService(t, true, true, 100)
+	testutil.SeedMonth(repo, "2026-01", 0, 100, 0, 100)
+	testutil.SeedMonth(repo, "2026-02", 100, 100, 0, 200)
+	testutil.SeedMonth(repo, "2026-03", 200, 100, 0, 300)
+	repo.Balance = &model.Balance{TotalBalance: model.Dollars(300)}
+	for _, e := range []struct {
+		date, description string
+		amount            float64
+	}{
+		{"2026-01-05", "Lunch", 10},  // Monday
+		{"2026-02-02", "lunch ", 20}, // Monday
+		{"2026-02-07", "Game", 50},   // Saturday
+		{"2026-03-01", "Lunch", 30},  // Sunday
+	} {
+		req := &model.AddExpenseRequest{Amount: model.Dollars(e.amount), Description: e.description, Date: e.date}
+		if _, err := svc.AddExpense(context.Background(), req); err != nil {
+			t.Fatalf("AddExpense(%s): %v", e.date, err)
+		}
+	}
+	return svc, repo
+}
+
+func TestStats_Window(t *testing.T) {
+	svc, _ := newStatsService(t)
+
+	stats, err := svc.GetStats(context.Background(), "2026-03", 2, statsNow)
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if stats.From != "2026-02" || len(stats.Months) != 2 {
+		t.Fatalf("window = %s with %d months, want 2026-02 with 2", stats.From, len(stats.Months))
+	}
+	feb, mar := stats.Months[0], stats.Months[1]
+	if feb.Spent != model.Dollars(70) || feb.Saved != model.Dollars(30) || feb.ExpenseCount != 2 {
+		t.Errorf("February = %+v, want 70 spent, 30 saved, 2 expenses", feb)
+	}
+	// The rolling average reaches back before the window, to January.
+	if feb.RollingAverageSpent != model.Dollars(40) || feb.SpentChange == nil || *feb.SpentChange != model.Dollars(60) {
+		t.Errorf("February rolling = %v, change = %v, want 40 and +60", feb.RollingAverageSpent, feb.SpentChange)
+	}
+	if mar.RollingAverageSpent != model.Money(3666) || *mar.SpentChange != model.Dollars(-40) {
+		t.Errorf("March rolling = %v, change = %v, want 36.66 and -40", mar.RollingAverageSpent, *mar.SpentChange)
+	}
+	if stats.AverageSpent != model.Dollars(50) || stats.SavingsRate == nil || *stats.SavingsRate != 50 {
+		t.Errorf("average spent = %v, savings rate = %v, want 50 and 50%%", stats.AverageSpent, stats.SavingsRate)
+	}
+	if len(stats.BiggestExpenses) != 3 || stats.BiggestExpenses[0].Description != "Game" {
+		t.Errorf("biggest = %+v, want Game first of 3", stats.BiggestExpenses)
+	}
+	if top := stats.TopDescriptions; len(top) != 2 || top[0].Description != "lunch" || top[0].Count != 2 || top[0].Total != model.Dollars(50) {
+		t.Errorf("top descriptions = %+v, want lunch twice for 50", top)
+	}
+	if stats.ByWeekday[time.Monday] != model.Dollars(20) || stats.ByWeekday[time.Saturday] != model.Dollars(50) ||
+		stats.ByWeekday[time.Sunday] != model.Dollars(30) || stats.ByHour[12] != model.Dollars(100) {
+		t.Errorf("by weekday = %v, by hour 12 = %v", stats.ByWeekday, stats.ByHour[12])
+	}
+}
+
+func TestStats_ClosedMonthCacheFollowsEdits(t *testing.T) {
+	svc, _ := newStatsService(t)
+	ctx := context.Background()
+
+	if _, err := svc.GetStats(ctx, "2026-02", 1, statsNow); err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if _, err := svc.AddExpense(ctx, &model.AddExpenseRequest{Amount: model.Dollars(5), Description: "Gum", Date: "2026-02-10"}); err != nil {
+		t.Fatalf("AddExpense: %v", err)
+	}
+	stats, err := svc.GetStats(ctx, "2026-02", 1, statsNow)
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if got := stats.Months[0].ExpenseCount; got != 3 {
+		t.Errorf("February expenses after a back-dated add = %d, want 3", got)
+	}
+}
+
+// An edit that leaves February's total where it was, within the second of
+// the last, is still seen: only the nanoseconds of updated_at move.
+func TestStats_ClosedMonthCacheSeesSubsecondEdits(t *testing.T) {
+	svc, repo := newStatsService(t)
+	ctx := context.Background()
+	if _, err := svc.GetStats(ctx, "2026-02", 1, statsNow); err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	for _, e := range repo.Expenses {
+		if e.Description == "Game" {
+			e.Description = "Board game"
+		}
+	}
+	at := repo.Months["2026-02"].UpdatedAt.Add(time.Nanosecond)
+	repo.Months["2026-02"].UpdatedAt = at
+	repo.MonthList["2026-02"].UpdatedAt = at
+
+	stats, err := svc.GetStats(ctx, "2026-02", 1, statsNow)
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if got := stats.BiggestExpenses; len(got) == 0 || got[0].Description != "Board game" {
+		t.Errorf("biggest = %+v, want the renamed expense", got)
+	}
+}
+
+func TestStats_CacheIsBounded(t *testing.T) {
+	svc, _ := newStatsService(t)
+	svc.statsCache = make(map[string]*monthStats, statsCacheSize)
+	for i := range statsCacheSize {
+		svc.statsCache[fmt.Sprintf("MONTH#%d", i)] = &monthStats{}
+	}
+	if _, err := svc.GetStats(context.Background(), "2026-02", 2, statsNow); err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if got := len(svc.statsCache); got != statsCacheSize {
+		t.Errorf("cache holds %d months, want %d", got, statsCacheSize)
+	}
+}
+
+func TestStats_InvalidWindow(t *testing.T) {
+	svc, _ := newExpenseService(t, true, true, 100)
+	ctx := context.Background()
+	if _, err := svc.GetStats(ctx, "", maxStatsMonths+1, statsNow); !errors.Is(err, ErrInvalidStatsWindow) {
+		t.Errorf("too wide err = %v, want ErrInvalidStatsWindow", err)
+	}
+	if _, err := svc.GetStats(ctx, "2026-13", 1, statsNow); !errors.Is(err, ErrInvalidMonth) {
+		t.Errorf("bad month err = %v, want ErrInvalidMonth", err)
+	}
+	stats, err := svc.GetStats(ctx, "", 0, statsNow)
+	if err != nil || stats.To != "2026-10" || len(stats.Months) != 0 || stats.SavingsRate != nil {
+		t.Errorf("empty ledger = %+v, %v, want six empty months to 2026-10", stats, err)
+	}
+}
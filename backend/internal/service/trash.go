@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+)
+
+// ErrTrashedExpenseNotFound is returned when a restore names an expense
+// that is not in the trash, or whose retention has run out. Handler maps
+// to 404.
+var ErrTrashedExpenseNotFound = errors.New("trashed expense not found")
+
+const (
+	// defaultTrashRetentionDays is how long a deleted expense stays
+	// restorable when TRASH_RETENTION_DAYS is not set.
+	defaultTrashRetentionDays = 30
+	// trashPurgeGrace delays the TTL of an entry with attachments past its
+	// expiry, so the daily PurgeTrash deletes the blobs before DynamoDB
+	// drops the only row that names them.
+	trashPurgeGrace = 7 * 24 * time.Hour
+)
+
+// SetTrashRetention sets how many days a deleted expense stays restorable.
+// Values below one keep the default.
+func (s *ExpenseService) SetTrashRetention(days int) {
+	if days > 0 {
+		s.trashRetention = time.Duration(days) * 24 * time.Hour
+	}
+}
+
+// trashEntry is the trash row for an expense deleted from month at now.
+func (s *ExpenseService) trashEntry(month string, e *model.Expense, now time.Time) *model.TrashedExpense {
+	expires := now.Add(s.trashRetention)
+	ttl := expires
+	if len(e.Attachments) > 0 {
+		ttl = expires.Add(trashPurgeGrace)
+	}
+	return &model.TrashedExpense{
+		ID:          e.SK,
+		Month:       month,
+		Amount:      e.Amount,
+		Description: e.Description,
+		Category:    e.Category,
+		CreatedAt:   e.CreatedAt,
+		Attachments: e.Attachments,
+		DeletedAt:   now,
+		ExpiresAt:   expires,
+		TTL:         ttl.Unix(),
+	}
+}
+
+// ListTrash returns the restorable deleted expenses as of now, most
+// recently deleted first. Entries past their expiry that the TTL sweep has
+// not reached yet are left out.
+func (s *ExpenseService) ListTrash(ctx context.Context, now time.Time) (*model.TrashResponse, error) {
+	entries, err := s.repo.ListTrash(ctx)
+	if err != nil {
+		return nil, err
+	}
+	live := []model.TrashedExpense{}
+	for _, e := range entries {
+		if now.Before(e.ExpiresAt) {
+			live = append(live, e)
+		}
+	}
+	sort.SliceStable(live, func(i, j int) bool { return live[i].DeletedAt.After(live[j].DeletedAt) })
+	return &model.TrashResponse{
+		Expenses:      live,
+		RetentionDays: int(s.trashRetention / (24 * time.Hour)),
+	}, nil
+}
+
+// RestoreExpense books a trashed expense again under its original id,
+// month and timestamp, through the same checks as any add: the month is
+// created if it has gone, and the category budget, carry chain and
+// overspend rules all apply. The trash entry is deleted in the transaction
+// that books the expense, so of two restores racing each other only one
+// books it; the other finds the entry gone (ErrTrashedExpenseNotFound) or
+// the expense already back (ErrDuplicateExpense). An entry left behind by
+// an expense already booked is cleared on retry.
+func (s *ExpenseService) RestoreExpense(ctx context.Context, id string, now time.Time) (*model.AddExpenseResponse, error) {
+	entry, err := s.repo.GetTrashedExpense(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil || !now.Before(entry.ExpiresAt) {
+		return nil, ErrTrashedExpenseNotFound
+	}
+
+	response, err := s.bookExpense(ctx, entry.Month, &model.Expense{
+		SK:          entry.ID,
+		Amount:      entry.Amount,
+		Description: entry.Description,
+		Category:    entry.Category,
+		CreatedAt:   entry.CreatedAt,
+		Attachments: entry.Attachments,
+	}, s.repo.AtomicRestoreTrashedExpense)
+	if err != nil {
+		if errors.Is(err, ErrDuplicateExpense) {
+			if derr := s.repo.DeleteTrashedExpense(ctx, id); derr != nil {
+				log.Printf("warn: could not clear trash entry %s of a restored expense: %v", id, derr)
+			}
+		}
+		return nil, err
+	}
+	return response, nil
+}
+
+// PurgeTrash removes the expired trash entries that have attachments,
+// deleting their blobs first, and returns how many it removed. Entries
+// without attachments are left to the table's TTL. Run daily by the
+// scheduler, well inside trashPurgeGrace.
+func (s *ExpenseService) PurgeTrash(ctx context.Context, now time.Time) (int, error) {
+	entries, err := s.repo.ListTrash(ctx)
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, e := range entries {
+		if len(e.Attachments) == 0 || now.Before(e.ExpiresAt) {
+			continue
+		}
+		s.deleteAttachmentBlobs(ctx, e.Attachments)
+		if err := s.repo.DeleteTrashedExpense(ctx, e.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
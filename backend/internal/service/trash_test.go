@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vppillai/passbook/backend/internal/model"
+	"github.com/vppillai/passbook/backend/internal/testutil"
+)
+
+// =====================================================================
+// Trash — a delete files the expense in the trash in the same transaction,
+// and a restore books it again through the checked add path.
+// =====================================================================
+
+func TestTrash_DeleteAndRestore(t *testing.T) {
+	svc, repo := newExpenseService(t, true, true, 0)
+	ctx := context.Background()
+	month, date := pastMonthDay(1, 10)
+	testutil.SeedMonth(repo, month, 0, 100, 0, 100)
+	repo.Balance.TotalBalance = model.Dollars(100)
+	e := addCategorized(t, svc, 25, "food", date)
+
+	if err := svc.DeleteExpense(ctx, month, e.SK); err != nil {
+		t.Fatalf("DeleteExpense: %v", err)
+	}
+	if repo.Balance.TotalBalance != model.Dollars(100) || repo.Months[month].EndingBalance != model.Dollars(100) {
+		t.Errorf("after delete balance = %v, month ending = %v, want both refunded to 100",
+			repo.Balance.TotalBalance, repo.Months[month].EndingBalance)
+	}
+	list, err := svc.ListTrash(ctx, time.Now())
+	if err != nil {
+		t.Fatalf("ListTrash: %v", err)
+	}
+	if len(list.Expenses) != 1 || list.RetentionDays != defaultTrashRetentionDays {
+		t.Fatalf("trash = %+v, want the one deleted expense", list)
+	}
+	got := list.Expenses[0]
+	if got.ID != e.SK || got.Month != month || got.Amount != model.Dollars(25) || got.Category != "food" || !got.CreatedAt.Equal(e.CreatedAt) {
+		t.Errorf("trash entry = %+v, want the expense as it was", got)
+	}
+	if want := got.DeletedAt.Add(defaultTrashRetentionDays * 24 * time.Hour); !got.ExpiresAt.Equal(want) || repo.Trash[e.SK].TTL != want.Unix() {
+		t.Errorf("expires %v, ttl %d, want both at %v", got.ExpiresAt, repo.Trash[e.SK].TTL, want)
+	}
+
+	resp, err := svc.RestoreExpense(ctx, e.SK, time.Now())
+	if err != nil {
+		t.Fatalf("RestoreExpense: %v", err)
+	}
+	restored := repo.Expenses[testutil.ExpenseKey(month, e.SK)]
+	if restored == nil || restored.Amount != model.Dollars(25) || !restored.CreatedAt.Equal(e.CreatedAt) || resp.Expense.SK != e.SK {
+		t.Fatalf("restored row = %+v, want the original back under its id", restored)
+	}
+	if repo.Balance.TotalBalance != model.Dollars(75) || repo.Months[month].CategoryTotals["food"] != model.Dollars(25) {
+		t.Errorf("after restore balance = %v, food = %v, want 75 / 25",
+			repo.Balance.TotalBalance, repo.Months[month].CategoryTotals["food"])
+	}
+	if len(repo.Trash) != 0 {
+		t.Errorf("trash still holds %d entries after the restore", len(repo.Trash))
+	}
+}
+
+// A restore spends the money again, so a hard-stop instance refuses one the
+// month can no longer afford, and the entry stays in the trash.
+func TestTrash_RestoreIsOverspendChecked(t *testing.T) {
+	svc, repo := newExpenseService(t, false, true, 0)
+	ctx := context.Background()
+	month, date := pastMonthDay(1, 10)
+	testutil.SeedMonth(repo, month, 0, 100, 0, 100)
+	repo.Balance.TotalBalance = model.Dollars(100)
+	e := addCategorized(t, svc, 80, "", date)
+	if err := svc.DeleteExpense(ctx, month, e.SK); err != nil {
+		t.Fatalf("DeleteExpense: %v", err)
+	}
+	addCategorized(t, svc, 50, "", date)
+
+	if _, err := svc.RestoreExpense(ctx, e.SK, time.Now()); !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("restore err = %v, want ErrInsufficientFunds", err)
+	}
+	if repo.Trash[e.SK] == nil {
+		t.Error("refused restore dropped the trash entry")
+	}
+}
+
+// Two restores of the same entry book it once: the one that lands second
+// finds the entry gone and charges nothing.
+func TestTrash_RacingRestoresBookOnce(t *testing.T) {
+	svc, repo := newExpenseService(t, true, true, 0)
+	ctx := context.Background()
+	month, date := pastMonthDay(1, 10)
+	testutil.SeedMonth(repo, month, 0, 100, 0, 100)
+	repo.Balance.TotalBalance = model.Dollars(100)
+	e := addCategorized(t, svc, 25, "", date)
+	if err := svc.DeleteExpense(ctx, month, e.SK); err != nil {
+		t.Fatalf("DeleteExpense: %v", err)
+	}
+	repo.BeforeTrashRestore = func() {
+		repo.BeforeTrashRestore = nil
+		if _, err := svc.RestoreExpense(ctx, e.SK, time.Now()); err != nil {
+			t.Fatalf("racing RestoreExpense: %v", err)
+		}
+	}
+
+	if _, err := svc.RestoreExpense(ctx, e.SK, time.Now()); !errors.Is(err, ErrTrashedExpenseNotFound) {
+		t.Fatalf("restore err = %v, want ErrTrashedExpenseNotFound", err)
+	}
+	if len(repo.Expenses) != 1 || repo.Balance.TotalBalance != model.Dollars(75) || len(repo.Trash) != 0 {
+		t.Errorf("expenses %d, balance %v, trash %d; want it booked once and the entry gone",
+			len(repo.Expenses), repo.Balance.TotalBalance, len(repo.Trash))
+	}
+}
+
+func TestTrash_Expiry(t *testing.T) {
+	svc, repo := newExpenseService(t, true, true, 0)
+	svc.SetTrashRetention(7)
+	ctx := context.Background()
+	month, date := pastMonthDay(1, 10)
+	testutil.SeedMonth(repo, month, 0, 100, 0, 100)
+	e := addCategorized(t, svc, 5, "", date)
+	if err := svc.DeleteExpense(ctx, month, e.SK); err != nil {
+		t.Fatalf("DeleteExpense: %v", err)
+	}
+
+	later := time.Now().AddDate(0, 0, 8)
+	if list, err := svc.ListTrash(ctx, later); err != nil || len(list.Expenses) != 0 || list.RetentionDays != 7 {
+		t.Errorf("ListTrash after expiry = %+v, %v, want nothing with a 7-day retention", list, err)
+	}
+	if _, err := svc.RestoreExpense(ctx, e.SK, later); !errors.Is(err, ErrTrashedExpenseNotFound) {
+		t.Errorf("restore after expiry err = %v, want ErrTrashedExpenseNotFound", err)
+	}
+	if _, err := svc.RestoreExpense(ctx, "EXP#1#nothere", time.Now()); !errors.Is(err, ErrTrashedExpenseNotFound) {
+		t.Errorf("restore of unknown id err = %v, want ErrTrashedExpenseNotFound", err)
+	}
+}
+
+// An entry left behind by a restore that could not clear it is cleared by
+// the next attempt, which reports the expense as already restored.
+func TestTrash_LeftoverEntryAfterRestore(t *testing.T) {
+	svc, repo := newExpenseService(t, true, true, 0)
+	ctx := context.Background()
+	month, date := pastMonthDay(1, 10)
+	testutil.SeedMonth(repo, month, 0, 100, 0, 100)
+	e := addCategorized(t, svc, 5, "", date)
+	if err := svc.DeleteExpense(ctx, month, e.SK); err != nil {
+		t.Fatalf("DeleteExpense: %v", err)
+	}
+	leftover := *repo.Trash[e.SK]
+	if _, err := svc.RestoreExpense(ctx, e.SK, time.Now()); err != nil {
+		t.Fatalf("RestoreExpense: %v", err)
+	}
+	repo.Trash[e.SK] = &leftover
+
+	if _, err := svc.RestoreExpense(ctx, e.SK, time.Now()); !errors.Is(err, ErrDuplicateExpense) {
+		t.Errorf("second restore err = %v, want ErrDuplicateExpense", err)
+	}
+	if len(repo.Trash) != 0 {
+		t.Error("leftover trash entry was not cleared")
+	}
+}
+
+// Rolling back an instalment plan deletes for good; only a user's delete
+// goes to the trash.
+func TestTrash_InstalmentRollbackBypassesTrash(t *testing.T) {
+	svc, repo := newExpenseService(t, true, true, 0)
+	month, date := pastMonthDay(1, 10)
+	testutil.SeedMonth(repo, month, 0, 100, 0, 100)
+	e := addCategorized(t, svc, 5, "", date)
+	if err := svc.deleteExpense(context.Background(), month, e, nil); err != nil {
+		t.Fatalf("deleteExpense: %v", err)
+	}
+	if len(repo.Trash) != 0 {
+		t.Errorf("trash holds %d entries, want none", len(repo.Trash))
+	}
+}
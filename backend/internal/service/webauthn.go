@@ -207,7 +207,7 @@ func (s *WebAuthnService) FinishRegistration(ctx context.Context, req *model.Web
 		return ErrWebAuthnVerification
 	}
 
-	return s.storeCredential(ctx, credential)
+	return s.storeCredential(ctx, credential, true)
 }
 
 // BeginLogin produces request options for a userless (discoverable-credential)
@@ -275,20 +275,23 @@ func (s *WebAuthnService) FinishLogin(ctx context.Context, req *model.WebAuthnVe
 
 	// Persist the advanced sign count so a replayed/cloned authenticator is
 	// detectable on the next login.
-	if err := s.storeCredential(ctx, credential); err != nil {
+	if err := s.storeCredential(ctx, credential, false); err != nil {
 		return nil, err
 	}
 
 	// Success: the same tail PIN verify uses, shared rather than copied. The copy
 	// here returned an error when the rate-limit counter could not be cleared,
 	// which turned an already-verified biometric unlock into a 500.
-	return mintSession(ctx, s.repo, sourceIP)
+	// Only an admin can enrol a credential, so biometric unlock is an
+	// admin one.
+	return mintSession(ctx, s.repo, sourceIP, model.RoleAdmin)
 }
 
 // DisableWebAuthn removes every stored credential so the user can turn off
-// biometric unlock. Requires a valid session (gated by the handler).
+// biometric unlock, journaling it. Requires a valid session (gated by the
+// handler).
 func (s *WebAuthnService) DisableWebAuthn(ctx context.Context) error {
-	return s.repo.DeleteAllWebAuthnCredentials(ctx)
+	return s.repo.AtomicDisableWebAuthn(ctx)
 }
 
 // failedLogin records a failed biometric login against the per-IP limiter
@@ -362,8 +365,9 @@ func (s *WebAuthnService) loadChallenge(ctx context.Context, challengeID string)
 
 // storeCredential serializes a go-webauthn credential and persists it
 // (canonical row + WACREDLIST mirror), recording the transports and current
-// sign count for later cloned-authenticator detection.
-func (s *WebAuthnService) storeCredential(ctx context.Context, credential *webauthn.Credential) error {
+// sign count for later cloned-authenticator detection. A new enrolment is
+// journaled in the audit log; a login's sign-count bump is not.
+func (s *WebAuthnService) storeCredential(ctx context.Context, credential *webauthn.Credential, enrol bool) error {
 	credJSON, err := json.Marshal(credential)
 	if err != nil {
 		return fmt.Errorf("failed to marshal webauthn credential: %w", err)
@@ -373,11 +377,15 @@ func (s *WebAuthnService) storeCredential(ctx context.Context, credential *webau
 	for _, t := range credential.Transport {
 		transports = append(transports, string(t))
 	}
-	return s.repo.PutWebAuthnCredential(ctx, &model.WebAuthnCredential{
+	cred := &model.WebAuthnCredential{
 		CredentialID: credID,
 		Credential:   string(credJSON),
 		SignCount:    credential.Authenticator.SignCount,
 		Transports:   strings.Join(transports, ","),
 		CreatedAt:    time.Now().Unix(),
-	})
+	}
+	if enrol {
+		return s.repo.AtomicEnrolWebAuthnCredential(ctx, cred)
+	}
+	return s.repo.PutWebAuthnCredential(ctx, cred)
 }
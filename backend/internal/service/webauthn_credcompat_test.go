@@ -139,7 +139,7 @@ func TestWebAuthnCredential_ReserializeKeepsIdentity(t *testing.T) {
 	// Re-persist exactly the way FinishLogin does after a successful assertion.
 	cred := user.credentials[0]
 	cred.Authenticator.SignCount = 43
-	if err := svc.storeCredential(ctx, &cred); err != nil {
+	if err := svc.storeCredential(ctx, &cred, false); err != nil {
 		t.Fatalf("storeCredential: %v", err)
 	}
 
@@ -7,11 +7,12 @@ package testutil
 import (
 	"context"
 	"errors"
-	"math"
+	"fmt"
 	"sort"
-	"strconv"
+	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/vppillai/passbook/backend/internal/model"
 	"github.com/vppillai/passbook/backend/internal/repository"
@@ -49,6 +50,26 @@ type FakeRepo struct {
 	// between a caller's pre-read and the transaction — the window in which a
 	// stale allowance figure could be debited.
 	BeforeDeleteMonth func()
+	// BeforeLedgerRepair, when set, runs inside RepairMonthSummary before its
+	// condition is evaluated: an app write landing between the ledger check
+	// and the repair of a month it read.
+	BeforeLedgerRepair func()
+	// BeforeFundEntryWrite, when set, runs inside AtomicUpdateFundEntry and
+	// AtomicDeleteFundEntry before their conditions are evaluated: a
+	// concurrent edit of the credit after the service read it.
+	BeforeFundEntryWrite func()
+	// BeforePendingApproval, when set, runs inside AtomicApprovePendingExpense
+	// before its conditions are evaluated: a rejection landing between the
+	// service's read of the pending entry and the booking.
+	BeforePendingApproval func()
+	// BeforeTrashRestore, when set, runs inside AtomicRestoreTrashedExpense
+	// before its conditions are evaluated: a second restore landing between
+	// the service's read of the trash entry and the booking.
+	BeforeTrashRestore func()
+	// BeforeSessionRevoke, when set, runs at the start of DeleteAllSessions
+	// and DeleteMemberSessions: a settings write landing while a PIN change
+	// revokes sessions, after it read the CONFIG row.
+	BeforeSessionRevoke func()
 	// SaveConfigCalls counts SaveConfig calls, so a test can assert that an
 	// ordinary login does NOT rewrite the config — the transparent PIN-hash
 	// upgrade must fire once, not on every unlock.
@@ -61,6 +82,48 @@ type FakeRepo struct {
 	// enumeration partition).
 	WAChallenges  map[string]*model.WebAuthnChallenge
 	WACredentials map[string]*model.WebAuthnCredential
+	// Recurring holds the recurring-expense schedules, keyed by id.
+	Recurring map[string]*model.RecurringExpense
+	// Goals holds the savings goals, keyed by id.
+	Goals map[string]*model.Goal
+	// Instalments holds the instalment plans, keyed by id.
+	Instalments map[string]*model.InstalmentPlan
+	// Search models the SEARCH index partition, keyed by the entry's SK
+	// ("<word>#<month>#<expense SK>"). The atomic expense methods keep it
+	// in step like the real transactions do; the instalment-plan ones do
+	// not, matching the real repository, whose callers re-index afterwards.
+	// Expenses seeded directly are not indexed until EnsureSearchIndex.
+	Search map[string]repository.SearchHit
+	// SearchIndexed records that EnsureSearchIndex has back-filled Search.
+	SearchIndexed bool
+	// Funds holds the itemized funds credits, keyed by ExpenseKey(month, SK).
+	Funds map[string]*model.FundEntry
+	// Trash holds the deleted expenses awaiting restore, keyed by expense id.
+	Trash map[string]*model.TrashedExpense
+	// Pending holds the expenses awaiting approval, keyed by expense id.
+	Pending map[string]*model.PendingExpense
+	// Chores holds the chores, keyed by id.
+	Chores map[string]*model.Chore
+	// ChoreCompletions holds the completions awaiting approval, keyed by id.
+	ChoreCompletions map[string]*model.ChoreCompletion
+	// Audit is the journal, in the order the entries were written. Each
+	// atomic method appends its entry only when its transaction succeeds.
+	// The journal is instance-wide: an account's ledger writes to its
+	// root's.
+	Audit []model.AuditEntry
+
+	// Accounts models the ACCOUNTS registry partition, keyed by id.
+	Accounts map[string]*model.Account
+	// ledgers holds the ledger of every account but the default one, whose
+	// ledger is the root fake itself; see Ledger.
+	ledgers map[string]*FakeRepo
+	// root is the fake an account's ledger belongs to, nil on the root.
+	root *FakeRepo
+
+	// MoneyMigrations counts EnsureMoneyMigrated calls. The fake stores
+	// Money natively, so there is never anything to migrate; tests use the
+	// count to check that only the scheduled jobs run the sweep.
+	MoneyMigrations int
 
 	// LegacyScans counts ListAllMonthsLegacy calls — the full-table Scan.
 	// Tests assert this stays at 0 on the hot expense-mutation paths.
@@ -69,15 +132,55 @@ type FakeRepo struct {
 
 func NewFakeRepo() *FakeRepo {
 	return &FakeRepo{
-		Months:        make(map[string]*model.MonthSummary),
-		MonthList:     make(map[string]*model.MonthSummary),
-		Expenses:      make(map[string]*model.Expense),
-		Sessions:      make(map[string]*model.Session),
-		RateLimits:    make(map[string]*model.RateLimitEntry),
-		WAChallenges:  make(map[string]*model.WebAuthnChallenge),
-		WACredentials: make(map[string]*model.WebAuthnCredential),
-		Balance:       &model.Balance{TotalBalance: 0},
+		Months:           make(map[string]*model.MonthSummary),
+		MonthList:        make(map[string]*model.MonthSummary),
+		Expenses:         make(map[string]*model.Expense),
+		Sessions:         make(map[string]*model.Session),
+		RateLimits:       make(map[string]*model.RateLimitEntry),
+		WAChallenges:     make(map[string]*model.WebAuthnChallenge),
+		WACredentials:    make(map[string]*model.WebAuthnCredential),
+		Recurring:        make(map[string]*model.RecurringExpense),
+		Goals:            make(map[string]*model.Goal),
+		Instalments:      make(map[string]*model.InstalmentPlan),
+		Search:           make(map[string]repository.SearchHit),
+		Trash:            make(map[string]*model.TrashedExpense),
+		Pending:          make(map[string]*model.PendingExpense),
+		Chores:           make(map[string]*model.Chore),
+		ChoreCompletions: make(map[string]*model.ChoreCompletion),
+		Funds:            make(map[string]*model.FundEntry),
+		Accounts:         make(map[string]*model.Account),
+		Balance:          &model.Balance{TotalBalance: 0},
+	}
+}
+
+// Ledger returns the fake holding account id's ledger — the months,
+// expenses, balance, schedules, goals, plans, search index and trash the
+// real repository keeps behind the account's ACCT# prefix. The default
+// account's ledger is f itself, so single-ledger tests are unchanged.
+// Tests use it to seed and inspect another account.
+func (f *FakeRepo) Ledger(id string) *FakeRepo {
+	if f.root != nil {
+		return f.root.Ledger(id)
+	}
+	if id == model.DefaultAccountID {
+		return f
+	}
+	if f.ledgers == nil {
+		f.ledgers = make(map[string]*FakeRepo)
+	}
+	l, ok := f.ledgers[id]
+	if !ok {
+		l = NewFakeRepo()
+		l.root = f
+		f.ledgers[id] = l
 	}
+	return l
+}
+
+// scoped is the ledger of the context's account. Every ledger method
+// hands its call to it first, so it runs on the right maps.
+func (f *FakeRepo) scoped(ctx context.Context) *FakeRepo {
+	return f.Ledger(repository.AccountFrom(ctx))
 }
 
 var _ repository.RepositoryInterface = (*FakeRepo)(nil)
@@ -93,14 +196,27 @@ func ExpenseKey(month, sk string) string { return month + "|" + sk }
 func SeedMonth(f *FakeRepo, month string, start, allow, expenses, ending float64) {
 	s := &model.MonthSummary{
 		Month:           month,
-		StartingBalance: start,
-		AllowanceAdded:  allow,
-		TotalExpenses:   expenses,
-		EndingBalance:   ending,
+		StartingBalance: model.Dollars(start),
+		AllowanceAdded:  model.Dollars(allow),
+		TotalExpenses:   model.Dollars(expenses),
+		EndingBalance:   model.Dollars(ending),
 	}
 	f.Months[month] = s
-	listCopy := *s
-	f.MonthList[month] = &listCopy
+	f.MonthList[month] = copySummary(s)
+}
+
+// copySummary returns a deep copy of a month summary — the CategoryTotals
+// map included, so the canonical row and its mirror never alias one map the
+// way two separate DynamoDB items never could.
+func copySummary(s *model.MonthSummary) *model.MonthSummary {
+	out := *s
+	if s.CategoryTotals != nil {
+		out.CategoryTotals = make(map[string]model.Money, len(s.CategoryTotals))
+		for k, v := range s.CategoryTotals {
+			out.CategoryTotals[k] = v
+		}
+	}
+	return &out
 }
 
 // SeedLegacyMonth inserts ONLY the canonical month summary with NO MonthList
@@ -113,25 +229,14 @@ func SeedMonth(f *FakeRepo, month string, start, allow, expenses, ending float64
 func SeedLegacyMonth(f *FakeRepo, month string, start, allow, expenses, ending float64) {
 	f.Months[month] = &model.MonthSummary{
 		Month:           month,
-		StartingBalance: start,
-		AllowanceAdded:  allow,
-		TotalExpenses:   expenses,
-		EndingBalance:   ending,
+		StartingBalance: model.Dollars(start),
+		AllowanceAdded:  model.Dollars(allow),
+		TotalExpenses:   model.Dollars(expenses),
+		EndingBalance:   model.Dollars(ending),
 	}
 	delete(f.MonthList, month)
 }
 
-// FmtFloat renders a float64 exactly as attributevalue marshals it into
-// DynamoDB (shortest round-trip form). Cent-rounding bugs are invisible
-// to tolerance-based comparison — dusty and clean values differ by ~1
-// ULP — but show up plainly in this string.
-func FmtFloat(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
-
-// AlmostEqual compares dollar floats with a tolerance; cent values are
-// not exactly representable in float64, so direct == on arithmetic
-// results is flaky by one ULP.
-func AlmostEqual(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
-
 // =====================================================================
 // Config
 // =====================================================================
@@ -154,6 +259,36 @@ func (f *FakeRepo) SaveConfig(_ context.Context, config *model.Config) error {
 	return nil
 }
 
+// UpdateConfig copies only the named attributes onto the stored row, by
+// way of the same attribute marshalling the real UpdateItem writes.
+func (f *FakeRepo) UpdateConfig(_ context.Context, config *model.Config, fields ...string) error {
+	if f.Config == nil {
+		return repository.ErrConfigNotFound
+	}
+	stored, err := attributevalue.MarshalMap(f.Config)
+	if err != nil {
+		return err
+	}
+	updated, err := attributevalue.MarshalMap(config)
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		if value, ok := updated[field]; ok {
+			stored[field] = value
+		} else {
+			delete(stored, field)
+		}
+	}
+	var c model.Config
+	if err := attributevalue.UnmarshalMap(stored, &c); err != nil {
+		return err
+	}
+	c.UpdatedAt = time.Now()
+	f.Config = &c
+	return nil
+}
+
 func (f *FakeRepo) CreateConfig(_ context.Context, config *model.Config) error {
 	if f.Config != nil {
 		return repository.ErrConfigAlreadyExists
@@ -167,7 +302,10 @@ func (f *FakeRepo) CreateConfig(_ context.Context, config *model.Config) error {
 // Balance
 // =====================================================================
 
-func (f *FakeRepo) GetBalance(_ context.Context) (*model.Balance, error) {
+func (f *FakeRepo) GetBalance(ctx context.Context) (*model.Balance, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.GetBalance(ctx)
+	}
 	if f.Balance == nil {
 		return &model.Balance{TotalBalance: 0}, nil
 	}
@@ -194,8 +332,7 @@ func (f *FakeRepo) putMonthListMirror(month string) {
 		delete(f.MonthList, month)
 		return
 	}
-	copy := *s
-	f.MonthList[month] = &copy
+	f.MonthList[month] = copySummary(s)
 }
 
 // applyMonthListDelta models the real monthListUpdate: a conditional delta
@@ -205,7 +342,7 @@ func (f *FakeRepo) putMonthListMirror(month string) {
 // the mirror exists it is mutated by the supplied deltas (NOT re-synced from
 // the canonical row) so the fake faithfully reproduces independent-row
 // drift if the two ever diverge.
-func (f *FakeRepo) applyMonthListDelta(month string, totalExpensesDelta, endingDelta, allowanceDelta, startingDelta float64) error {
+func (f *FakeRepo) applyMonthListDelta(month string, totalExpensesDelta, endingDelta, allowanceDelta, startingDelta model.Money) error {
 	mirror, ok := f.MonthList[month]
 	if !ok {
 		return errMonthListMirrorMissing
@@ -217,39 +354,49 @@ func (f *FakeRepo) applyMonthListDelta(month string, totalExpensesDelta, endingD
 	return nil
 }
 
-func (f *FakeRepo) GetMonthSummary(_ context.Context, month string) (*model.MonthSummary, error) {
+func (f *FakeRepo) GetMonthSummary(ctx context.Context, month string) (*model.MonthSummary, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.GetMonthSummary(ctx, month)
+	}
 	s, ok := f.Months[month]
 	if !ok {
 		return nil, nil
 	}
-	out := *s
-	return &out, nil
+	return copySummary(s), nil
 }
 
-func (f *FakeRepo) SaveMonthSummary(_ context.Context, summary *model.MonthSummary) error {
-	s := *summary
-	f.Months[summary.Month] = &s
+func (f *FakeRepo) SaveMonthSummary(ctx context.Context, summary *model.MonthSummary) error {
+	if l := f.scoped(ctx); l != f {
+		return l.SaveMonthSummary(ctx, summary)
+	}
+	f.Months[summary.Month] = copySummary(summary)
 	f.putMonthListMirror(summary.Month)
 	return nil
 }
 
-func (f *FakeRepo) CreateMonthSummaryIfAbsent(_ context.Context, summary *model.MonthSummary) error {
+func (f *FakeRepo) CreateMonthSummaryIfAbsent(ctx context.Context, summary *model.MonthSummary) error {
+	if l := f.scoped(ctx); l != f {
+		return l.CreateMonthSummaryIfAbsent(ctx, summary)
+	}
 	if _, exists := f.Months[summary.Month]; exists {
 		return repository.ErrMonthAlreadyExists
 	}
-	s := *summary
-	f.Months[summary.Month] = &s
+	f.Months[summary.Month] = copySummary(summary)
 	f.putMonthListMirror(summary.Month)
+	f.journal(ctx, model.AuditMonthCreate, summary.Month, "", nil, repository.MonthAuditState(summary))
 	return nil
 }
 
 // ListMonths reads the MonthList index, sorts descending by month, and
 // applies native key-based pagination via the lastKey marker. The cursor
 // map carries the SK ("month") of the last item from the previous page.
-func (f *FakeRepo) ListMonths(_ context.Context, limit int32, cursor map[string]types.AttributeValue) ([]model.MonthSummary, map[string]types.AttributeValue, error) {
+func (f *FakeRepo) ListMonths(ctx context.Context, limit int32, cursor map[string]types.AttributeValue) ([]model.MonthSummary, map[string]types.AttributeValue, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.ListMonths(ctx, limit, cursor)
+	}
 	all := make([]model.MonthSummary, 0, len(f.MonthList))
 	for _, s := range f.MonthList {
-		all = append(all, *s)
+		all = append(all, *copySummary(s))
 	}
 	sort.Slice(all, func(i, j int) bool { return all[i].Month > all[j].Month })
 
@@ -274,7 +421,7 @@ func (f *FakeRepo) ListMonths(_ context.Context, limit int32, cursor map[string]
 	var lastKey map[string]types.AttributeValue
 	if end < len(all) && len(page) > 0 {
 		lastKey = map[string]types.AttributeValue{
-			"PK": &types.AttributeValueMemberS{Value: repository.PKMonthList},
+			"PK": &types.AttributeValueMemberS{Value: repository.AccountPK(ctx, repository.PKMonthList)},
 			"SK": &types.AttributeValueMemberS{Value: page[len(page)-1].Month},
 		}
 	}
@@ -285,19 +432,24 @@ func (f *FakeRepo) ListMonths(_ context.Context, limit int32, cursor map[string]
 // tests can assert the hot mutation paths never reach it — in DynamoDB this
 // Scan reads (and bills for) every item in the table, so its cost grows with
 // every expense ever written.
-func (f *FakeRepo) ListAllMonthsLegacy(_ context.Context) ([]model.MonthSummary, error) {
+func (f *FakeRepo) ListAllMonthsLegacy(ctx context.Context) ([]model.MonthSummary, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.ListAllMonthsLegacy(ctx)
+	}
 	f.LegacyScans++
 	out := make([]model.MonthSummary, 0, len(f.Months))
 	for _, s := range f.Months {
-		out = append(out, *s)
+		out = append(out, *copySummary(s))
 	}
 	return out, nil
 }
 
-func (f *FakeRepo) BackfillMonthList(_ context.Context, summaries []model.MonthSummary) error {
+func (f *FakeRepo) BackfillMonthList(ctx context.Context, summaries []model.MonthSummary) error {
+	if l := f.scoped(ctx); l != f {
+		return l.BackfillMonthList(ctx, summaries)
+	}
 	for i := range summaries {
-		s := summaries[i]
-		f.MonthList[s.Month] = &s
+		f.MonthList[summaries[i].Month] = copySummary(&summaries[i])
 	}
 	return nil
 }
@@ -307,7 +459,10 @@ func (f *FakeRepo) BackfillMonthList(_ context.Context, summaries []model.MonthS
 // legacy month. Idempotent: a present mirror is left untouched (no clobber
 // of any independent drift). A missing canonical row is tolerated (no-op),
 // matching the real repository.
-func (f *FakeRepo) EnsureMonthListMirror(_ context.Context, month string) error {
+func (f *FakeRepo) EnsureMonthListMirror(ctx context.Context, month string) error {
+	if l := f.scoped(ctx); l != f {
+		return l.EnsureMonthListMirror(ctx, month)
+	}
 	if _, ok := f.MonthList[month]; ok {
 		return nil
 	}
@@ -315,11 +470,81 @@ func (f *FakeRepo) EnsureMonthListMirror(_ context.Context, month string) error
 	if !ok {
 		return nil
 	}
-	copy := *s
-	f.MonthList[month] = &copy
+	f.MonthList[month] = copySummary(s)
+	return nil
+}
+
+// EnsureCategoryTotals seeds an empty CategoryTotals map on the canonical
+// row and the mirror when absent. Both rows must exist, matching the real
+// transaction's attribute_exists(PK) conditions.
+func (f *FakeRepo) EnsureCategoryTotals(ctx context.Context, month string) error {
+	if l := f.scoped(ctx); l != f {
+		return l.EnsureCategoryTotals(ctx, month)
+	}
+	s, ok := f.Months[month]
+	if !ok {
+		return errors.New("month not found: category totals transaction cancelled")
+	}
+	mirror, ok := f.MonthList[month]
+	if !ok {
+		return errMonthListMirrorMissing
+	}
+	for _, row := range []*model.MonthSummary{s, mirror} {
+		if row.CategoryTotals == nil {
+			row.CategoryTotals = map[string]model.Money{}
+		}
+	}
+	return nil
+}
+
+// errCategoryTotalsMissing models DynamoDB rejecting a nested
+// category_totals.#cat update because the parent map was never seeded —
+// the caller skipped EnsureCategoryTotals.
+var errCategoryTotalsMissing = errors.New("category_totals map missing: transaction cancelled")
+
+// categoryDeltas nets the per-category changes of one transaction on one
+// month, dropping uncategorized spend and zero nets — the same rule the real
+// repository applies when building its update expression.
+func categoryDeltas(changes ...categoryChange) map[string]model.Money {
+	deltas := map[string]model.Money{}
+	for _, c := range changes {
+		if c.category != "" {
+			deltas[c.category] += c.delta
+		}
+	}
+	for cat, d := range deltas {
+		if d == 0 {
+			delete(deltas, cat)
+		}
+	}
+	return deltas
+}
+
+type categoryChange struct {
+	category string
+	delta    model.Money
+}
+
+// checkCategoryTotals is the condition half: a non-empty delta set against a
+// month whose canonical row or mirror has no map cancels the transaction.
+func (f *FakeRepo) checkCategoryTotals(month string, deltas map[string]model.Money) error {
+	if len(deltas) == 0 {
+		return nil
+	}
+	if f.Months[month].CategoryTotals == nil || f.MonthList[month].CategoryTotals == nil {
+		return errCategoryTotalsMissing
+	}
 	return nil
 }
 
+// applyCategoryDeltas is the mutation half, applied to both rows.
+func (f *FakeRepo) applyCategoryDeltas(month string, deltas map[string]model.Money) {
+	for cat, d := range deltas {
+		f.Months[month].CategoryTotals[cat] += d
+		f.MonthList[month].CategoryTotals[cat] += d
+	}
+}
+
 // PropagateLaterMonthDeltas applies the carry-chain delta to both the
 // canonical row and the mirror of every named month, in one logical
 // transaction. It models DynamoDB's all-or-nothing semantics: it first
@@ -327,7 +552,10 @@ func (f *FakeRepo) EnsureMonthListMirror(_ context.Context, month string) error
 // mirror is the legacy-table failure mode), and only then mutates — so a
 // single missing mirror cancels the entire propagation with no partial
 // writes.
-func (f *FakeRepo) PropagateLaterMonthDeltas(_ context.Context, months []string, delta float64) error {
+func (f *FakeRepo) PropagateLaterMonthDeltas(ctx context.Context, months []string, delta model.Money) error {
+	if l := f.scoped(ctx); l != f {
+		return l.PropagateLaterMonthDeltas(ctx, months, delta)
+	}
 	if len(months) == 0 || delta == 0 {
 		return nil
 	}
@@ -350,11 +578,21 @@ func (f *FakeRepo) PropagateLaterMonthDeltas(_ context.Context, months []string,
 	return nil
 }
 
+// EnsureMoneyMigrated is a no-op beyond counting: the fake has no legacy
+// float-dollar rows to rewrite.
+func (f *FakeRepo) EnsureMoneyMigrated(_ context.Context) error {
+	f.MoneyMigrations++
+	return nil
+}
+
 // =====================================================================
 // Expenses
 // =====================================================================
 
-func (f *FakeRepo) GetExpense(_ context.Context, month string, expenseID string) (*model.Expense, error) {
+func (f *FakeRepo) GetExpense(ctx context.Context, month string, expenseID string) (*model.Expense, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.GetExpense(ctx, month, expenseID)
+	}
 	e, ok := f.Expenses[ExpenseKey(month, expenseID)]
 	if !ok {
 		return nil, nil
@@ -363,7 +601,10 @@ func (f *FakeRepo) GetExpense(_ context.Context, month string, expenseID string)
 	return &out, nil
 }
 
-func (f *FakeRepo) GetExpenses(_ context.Context, month string, _ int32, _ map[string]types.AttributeValue) ([]model.Expense, map[string]types.AttributeValue, error) {
+func (f *FakeRepo) GetExpenses(ctx context.Context, month string, limit int32, cursor map[string]types.AttributeValue) ([]model.Expense, map[string]types.AttributeValue, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.GetExpenses(ctx, month, limit, cursor)
+	}
 	out := []model.Expense{}
 	for k, e := range f.Expenses {
 		if len(k) > len(month) && k[:len(month)] == month {
@@ -373,7 +614,41 @@ func (f *FakeRepo) GetExpenses(_ context.Context, month string, _ int32, _ map[s
 	return out, nil, nil
 }
 
-func (f *FakeRepo) UpdateExpense(_ context.Context, month, expenseID string, amount float64, description string) (*model.Expense, error) {
+// GetExpensesInRange pages like the real Query: SK order descending, the
+// [from, to) bounds applied to the SK's timestamp, and the last row's key as
+// the cursor when more rows follow.
+func (f *FakeRepo) GetExpensesInRange(ctx context.Context, month string, from, to time.Time, limit int32, cursor map[string]types.AttributeValue) ([]model.Expense, map[string]types.AttributeValue, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.GetExpensesInRange(ctx, month, from, to, limit, cursor)
+	}
+	lo := fmt.Sprintf("EXP#%d", from.UnixNano())
+	hi := fmt.Sprintf("EXP#%d", to.UnixNano())
+	after := ""
+	if sk, ok := cursor["SK"].(*types.AttributeValueMemberS); ok {
+		after = sk.Value
+	}
+	var out []model.Expense
+	for _, e := range f.Expenses {
+		if e.PK == "MONTH#"+month && e.SK >= lo && e.SK <= hi && (after == "" || e.SK < after) {
+			out = append(out, *e)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].SK > out[j].SK })
+	var lastKey map[string]types.AttributeValue
+	if limit > 0 && len(out) > int(limit) {
+		out = out[:limit]
+		lastKey = map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: "MONTH#" + month},
+			"SK": &types.AttributeValueMemberS{Value: out[len(out)-1].SK},
+		}
+	}
+	return out, lastKey, nil
+}
+
+func (f *FakeRepo) UpdateExpense(ctx context.Context, month, expenseID string, amount model.Money, description string) (*model.Expense, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.UpdateExpense(ctx, month, expenseID, amount, description)
+	}
 	e, ok := f.Expenses[ExpenseKey(month, expenseID)]
 	if !ok {
 		return nil, nil
@@ -384,7 +659,10 @@ func (f *FakeRepo) UpdateExpense(_ context.Context, month, expenseID string, amo
 	return &old, nil
 }
 
-func (f *FakeRepo) DeleteExpense(_ context.Context, month, expenseID string) (*model.Expense, error) {
+func (f *FakeRepo) DeleteExpense(ctx context.Context, month, expenseID string) (*model.Expense, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.DeleteExpense(ctx, month, expenseID)
+	}
 	e, ok := f.Expenses[ExpenseKey(month, expenseID)]
 	if !ok {
 		return nil, nil
@@ -394,15 +672,49 @@ func (f *FakeRepo) DeleteExpense(_ context.Context, month, expenseID string) (*m
 	return &out, nil
 }
 
+func (f *FakeRepo) AddExpenseAttachment(ctx context.Context, month, expenseID string, count int, attachment *model.Attachment) error {
+	if l := f.scoped(ctx); l != f {
+		return l.AddExpenseAttachment(ctx, month, expenseID, count, attachment)
+	}
+	e, ok := f.Expenses[ExpenseKey(month, expenseID)]
+	if !ok || len(e.Attachments) != count {
+		return repository.ErrExpenseStateMismatch
+	}
+	e.Attachments = append(append([]model.Attachment(nil), e.Attachments...), *attachment)
+	return nil
+}
+
+func (f *FakeRepo) RemoveExpenseAttachment(ctx context.Context, month, expenseID string, index int, attachmentID string) error {
+	if l := f.scoped(ctx); l != f {
+		return l.RemoveExpenseAttachment(ctx, month, expenseID, index, attachmentID)
+	}
+	e, ok := f.Expenses[ExpenseKey(month, expenseID)]
+	if !ok || index < 0 || index >= len(e.Attachments) || e.Attachments[index].ID != attachmentID {
+		return repository.ErrExpenseStateMismatch
+	}
+	kept := append([]model.Attachment(nil), e.Attachments[:index]...)
+	e.Attachments = append(kept, e.Attachments[index+1:]...)
+	if len(e.Attachments) == 0 {
+		e.Attachments = nil
+	}
+	return nil
+}
+
 // =====================================================================
 // Atomic operations
 // =====================================================================
 
-func (f *FakeRepo) AtomicAddExpense(_ context.Context, month string, expense *model.Expense, checkBalance bool) error {
+func (f *FakeRepo) AtomicAddExpense(ctx context.Context, month string, expense *model.Expense, checkBalance bool) error {
+	if l := f.scoped(ctx); l != f {
+		return l.AtomicAddExpense(ctx, month, expense, checkBalance)
+	}
 	s, ok := f.Months[month]
 	if !ok {
 		return errors.New("month not found")
 	}
+	if _, exists := f.Expenses[ExpenseKey(month, expense.SK)]; exists {
+		return repository.ErrExpenseAlreadyExists
+	}
 	if checkBalance && s.EndingBalance < expense.Amount {
 		return repository.ErrInsufficientBalance
 	}
@@ -411,28 +723,44 @@ func (f *FakeRepo) AtomicAddExpense(_ context.Context, month string, expense *mo
 	if _, ok := f.MonthList[month]; !ok {
 		return errMonthListMirrorMissing
 	}
+	cats := categoryDeltas(categoryChange{expense.Category, expense.Amount})
+	if err := f.checkCategoryTotals(month, cats); err != nil {
+		return err
+	}
 	e := *expense
-	e.PK = "MONTH#" + month
+	e.PK = repository.AccountPK(ctx, repository.MonthPrefix+month)
 	f.Expenses[ExpenseKey(month, expense.SK)] = &e
+	f.reindex(month, nil, month, &e)
 	s.TotalExpenses += expense.Amount
 	s.EndingBalance -= expense.Amount
 	_ = f.applyMonthListDelta(month, expense.Amount, -expense.Amount, 0, 0)
+	f.applyCategoryDeltas(month, cats)
 	if f.Balance == nil {
 		f.Balance = &model.Balance{}
 	}
 	f.Balance.TotalBalance -= expense.Amount
+	f.journal(ctx, model.AuditExpenseAdd, month, expense.SK, nil, repository.ExpenseAuditState(month, &e))
 	return nil
 }
 
-func (f *FakeRepo) AtomicUpdateExpense(_ context.Context, month, expenseID string, oldAmount, newAmount float64, newDescription string, checkBalance bool) error {
-	e, ok := f.Expenses[ExpenseKey(month, expenseID)]
+// expenseUnchanged is the real repository's expenseLock: the row must still
+// carry the amount and category the caller read.
+func expenseUnchanged(e, old *model.Expense) bool {
+	return e.Amount == old.Amount && e.Category == old.Category
+}
+
+func (f *FakeRepo) AtomicUpdateExpense(ctx context.Context, month string, old, updated *model.Expense, checkBalance bool) error {
+	if l := f.scoped(ctx); l != f {
+		return l.AtomicUpdateExpense(ctx, month, old, updated, checkBalance)
+	}
+	e, ok := f.Expenses[ExpenseKey(month, old.SK)]
 	if !ok {
 		return repository.ErrExpenseStateMismatch
 	}
-	if e.Amount != oldAmount {
+	if !expenseUnchanged(e, old) {
 		return repository.ErrExpenseStateMismatch
 	}
-	delta := newAmount - oldAmount
+	delta := updated.Amount - old.Amount
 	s, ok := f.Months[month]
 	if !ok {
 		return errors.New("month not found")
@@ -444,24 +772,40 @@ func (f *FakeRepo) AtomicUpdateExpense(_ context.Context, month, expenseID strin
 	if _, ok := f.MonthList[month]; !ok {
 		return errMonthListMirrorMissing
 	}
-	e.Amount = newAmount
-	e.Description = newDescription
+	cats := categoryDeltas(
+		categoryChange{old.Category, -old.Amount},
+		categoryChange{updated.Category, updated.Amount},
+	)
+	if err := f.checkCategoryTotals(month, cats); err != nil {
+		return err
+	}
+	f.reindex(month, e, month, updated)
+	before := repository.ExpenseAuditState(month, e)
+	e.Amount = updated.Amount
+	e.Description = updated.Description
+	e.Category = updated.Category
 	s.TotalExpenses += delta
 	s.EndingBalance -= delta
 	_ = f.applyMonthListDelta(month, delta, -delta, 0, 0)
+	f.applyCategoryDeltas(month, cats)
 	if f.Balance == nil {
 		f.Balance = &model.Balance{}
 	}
 	f.Balance.TotalBalance -= delta
+	f.journal(ctx, model.AuditExpenseUpdate, month, old.SK, before, repository.ExpenseAuditState(month, e))
 	return nil
 }
 
-func (f *FakeRepo) AtomicDeleteExpense(_ context.Context, month, expenseID string, oldAmount float64) error {
-	e, ok := f.Expenses[ExpenseKey(month, expenseID)]
+func (f *FakeRepo) AtomicDeleteExpense(ctx context.Context, month string, old *model.Expense, trash *model.TrashedExpense) error {
+	if l := f.scoped(ctx); l != f {
+		return l.AtomicDeleteExpense(ctx, month, old, trash)
+	}
+	e, ok := f.Expenses[ExpenseKey(month, old.SK)]
 	if !ok {
 		return repository.ErrExpenseStateMismatch
 	}
-	if e.Amount != oldAmount {
+	oldAmount := old.Amount
+	if !expenseUnchanged(e, old) {
 		return repository.ErrExpenseStateMismatch
 	}
 	s, ok := f.Months[month]
@@ -473,14 +817,25 @@ func (f *FakeRepo) AtomicDeleteExpense(_ context.Context, month, expenseID strin
 	if _, ok := f.MonthList[month]; !ok {
 		return errMonthListMirrorMissing
 	}
-	delete(f.Expenses, ExpenseKey(month, expenseID))
+	cats := categoryDeltas(categoryChange{old.Category, -oldAmount})
+	if err := f.checkCategoryTotals(month, cats); err != nil {
+		return err
+	}
+	delete(f.Expenses, ExpenseKey(month, old.SK))
+	f.reindex(month, e, month, nil)
+	if trash != nil {
+		cp := *trash
+		f.Trash[trash.ID] = &cp
+	}
 	s.TotalExpenses -= oldAmount
 	s.EndingBalance += oldAmount
 	_ = f.applyMonthListDelta(month, -oldAmount, oldAmount, 0, 0)
+	f.applyCategoryDeltas(month, cats)
 	if f.Balance == nil {
 		f.Balance = &model.Balance{}
 	}
 	f.Balance.TotalBalance += oldAmount
+	f.journal(ctx, model.AuditExpenseDelete, month, old.SK, repository.ExpenseAuditState(month, e), nil)
 	return nil
 }
 
@@ -490,12 +845,16 @@ func (f *FakeRepo) AtomicDeleteExpense(_ context.Context, month, expenseID strin
 // shift the summary + mirror + balance by the amount delta. A missing mirror
 // cancels the whole transaction (legacy-table defect); an overspend with
 // checkBalance && delta>0 returns ErrInsufficientBalance before any write.
-func (f *FakeRepo) AtomicMoveExpenseSameMonth(_ context.Context, month string, oldExpenseID string, newExpense *model.Expense, oldAmount float64, checkBalance bool) error {
-	e, ok := f.Expenses[ExpenseKey(month, oldExpenseID)]
+func (f *FakeRepo) AtomicMoveExpenseSameMonth(ctx context.Context, month string, old, newExpense *model.Expense, checkBalance bool) error {
+	if l := f.scoped(ctx); l != f {
+		return l.AtomicMoveExpenseSameMonth(ctx, month, old, newExpense, checkBalance)
+	}
+	e, ok := f.Expenses[ExpenseKey(month, old.SK)]
 	if !ok {
 		return repository.ErrExpenseStateMismatch
 	}
-	if e.Amount != oldAmount {
+	oldAmount := old.Amount
+	if !expenseUnchanged(e, old) {
 		return repository.ErrExpenseStateMismatch
 	}
 	s, ok := f.Months[month]
@@ -510,17 +869,27 @@ func (f *FakeRepo) AtomicMoveExpenseSameMonth(_ context.Context, month string, o
 	if _, ok := f.MonthList[month]; !ok {
 		return errMonthListMirrorMissing
 	}
-	delete(f.Expenses, ExpenseKey(month, oldExpenseID))
+	cats := categoryDeltas(
+		categoryChange{old.Category, -oldAmount},
+		categoryChange{newExpense.Category, newExpense.Amount},
+	)
+	if err := f.checkCategoryTotals(month, cats); err != nil {
+		return err
+	}
+	delete(f.Expenses, ExpenseKey(month, old.SK))
 	ne := *newExpense
-	ne.PK = "MONTH#" + month
+	ne.PK = repository.AccountPK(ctx, repository.MonthPrefix+month)
 	f.Expenses[ExpenseKey(month, newExpense.SK)] = &ne
+	f.reindex(month, e, month, &ne)
 	s.TotalExpenses += delta
 	s.EndingBalance -= delta
 	_ = f.applyMonthListDelta(month, delta, -delta, 0, 0)
+	f.applyCategoryDeltas(month, cats)
 	if f.Balance == nil {
 		f.Balance = &model.Balance{}
 	}
 	f.Balance.TotalBalance -= delta
+	f.journal(ctx, model.AuditExpenseUpdate, month, ne.SK, repository.ExpenseAuditState(month, e), repository.ExpenseAuditState(month, &ne))
 	return nil
 }
 
@@ -531,12 +900,16 @@ func (f *FakeRepo) AtomicMoveExpenseSameMonth(_ context.Context, month string, o
 // (oldAmount - newAmount). A missing mirror on either month cancels the whole
 // transaction; an overspend on the destination (checkBalance) returns
 // ErrInsufficientBalance before any write lands.
-func (f *FakeRepo) AtomicMoveExpenseAcrossMonths(_ context.Context, srcMonth, dstMonth, oldExpenseID string, newExpense *model.Expense, oldAmount float64, checkBalance bool, srcRefundReachesDst bool) error {
-	e, ok := f.Expenses[ExpenseKey(srcMonth, oldExpenseID)]
+func (f *FakeRepo) AtomicMoveExpenseAcrossMonths(ctx context.Context, srcMonth, dstMonth string, old, newExpense *model.Expense, checkBalance bool, srcRefundReachesDst bool) error {
+	if l := f.scoped(ctx); l != f {
+		return l.AtomicMoveExpenseAcrossMonths(ctx, srcMonth, dstMonth, old, newExpense, checkBalance, srcRefundReachesDst)
+	}
+	e, ok := f.Expenses[ExpenseKey(srcMonth, old.SK)]
 	if !ok {
 		return repository.ErrExpenseStateMismatch
 	}
-	if e.Amount != oldAmount {
+	oldAmount := old.Amount
+	if !expenseUnchanged(e, old) {
 		return repository.ErrExpenseStateMismatch
 	}
 	src, ok := f.Months[srcMonth]
@@ -564,57 +937,142 @@ func (f *FakeRepo) AtomicMoveExpenseAcrossMonths(_ context.Context, srcMonth, ds
 			return repository.ErrInsufficientBalance
 		}
 	}
-	delete(f.Expenses, ExpenseKey(srcMonth, oldExpenseID))
+	srcCats := categoryDeltas(categoryChange{old.Category, -oldAmount})
+	dstCats := categoryDeltas(categoryChange{newExpense.Category, newExpense.Amount})
+	if err := f.checkCategoryTotals(srcMonth, srcCats); err != nil {
+		return err
+	}
+	if err := f.checkCategoryTotals(dstMonth, dstCats); err != nil {
+		return err
+	}
+	delete(f.Expenses, ExpenseKey(srcMonth, old.SK))
 	ne := *newExpense
-	ne.PK = "MONTH#" + dstMonth
+	ne.PK = repository.AccountPK(ctx, repository.MonthPrefix+dstMonth)
 	f.Expenses[ExpenseKey(dstMonth, newExpense.SK)] = &ne
+	f.reindex(srcMonth, e, dstMonth, &ne)
 	src.TotalExpenses -= oldAmount
 	src.EndingBalance += oldAmount
 	_ = f.applyMonthListDelta(srcMonth, -oldAmount, oldAmount, 0, 0)
 	dst.TotalExpenses += newExpense.Amount
 	dst.EndingBalance -= newExpense.Amount
 	_ = f.applyMonthListDelta(dstMonth, newExpense.Amount, -newExpense.Amount, 0, 0)
+	f.applyCategoryDeltas(srcMonth, srcCats)
+	f.applyCategoryDeltas(dstMonth, dstCats)
 	if f.Balance == nil {
 		f.Balance = &model.Balance{}
 	}
 	f.Balance.TotalBalance += oldAmount - newExpense.Amount
+	f.journal(ctx, model.AuditExpenseUpdate, dstMonth, ne.SK, repository.ExpenseAuditState(srcMonth, e), repository.ExpenseAuditState(dstMonth, &ne))
 	return nil
 }
 
-func (f *FakeRepo) AtomicCreateMonth(_ context.Context, summary *model.MonthSummary, allowance float64) error {
+func (f *FakeRepo) AtomicCreateMonth(ctx context.Context, summary *model.MonthSummary, allowance model.Money) error {
+	if l := f.scoped(ctx); l != f {
+		return l.AtomicCreateMonth(ctx, summary, allowance)
+	}
 	if _, exists := f.Months[summary.Month]; exists {
 		return repository.ErrMonthAlreadyExists
 	}
-	s := *summary
-	f.Months[summary.Month] = &s
+	f.Months[summary.Month] = copySummary(summary)
 	f.putMonthListMirror(summary.Month)
 	if f.Balance == nil {
 		f.Balance = &model.Balance{}
 	}
 	f.Balance.TotalBalance += allowance
+	f.journal(ctx, model.AuditMonthCreate, summary.Month, "", nil, repository.MonthAuditState(summary))
 	return nil
 }
 
-func (f *FakeRepo) AtomicAddFunds(_ context.Context, month string, amount float64) error {
-	s, ok := f.Months[month]
-	if !ok {
+func (f *FakeRepo) AtomicAddFunds(ctx context.Context, month string, amount model.Money, entry *model.FundEntry) error {
+	if l := f.scoped(ctx); l != f {
+		return l.AtomicAddFunds(ctx, month, amount, entry)
+	}
+	if _, ok := f.Months[month]; !ok {
 		return repository.ErrExpenseStateMismatch
 	}
 	// Missing mirror cancels the whole transaction (legacy-table defect).
 	if _, ok := f.MonthList[month]; !ok {
 		return errMonthListMirrorMissing
 	}
-	s.AllowanceAdded += amount
-	s.EndingBalance += amount
-	_ = f.applyMonthListDelta(month, 0, amount, amount, 0)
+	target, after := "", &model.AuditState{Amount: &amount}
+	if entry == nil {
+		s := f.Months[month]
+		var base model.Money
+		if s.BaseAllowance != nil {
+			base = *s.BaseAllowance
+		}
+		base += amount
+		s.BaseAllowance = &base
+	} else {
+		key := ExpenseKey(month, entry.SK)
+		if _, exists := f.Funds[key]; exists {
+			return repository.ErrExpenseStateMismatch
+		}
+		entry.PK = repository.AccountPK(ctx, repository.MonthPrefix+month)
+		stored := *entry
+		f.Funds[key] = &stored
+		target, after = entry.SK, repository.FundAuditState(month, entry)
+	}
+	f.applyFundDelta(month, amount, 0)
+	f.journal(ctx, model.AuditFundsAdd, month, target, nil, after)
+	return nil
+}
+
+// applyFundDelta moves a month's allowance, its mirror and the global
+// balance by delta, and its earnings by earned, as the real
+// creditDeltaItems does.
+func (f *FakeRepo) applyFundDelta(month string, delta, earned model.Money) {
+	s := f.Months[month]
+	s.AllowanceAdded += delta
+	s.EndingBalance += delta
+	s.EarningsAdded += earned
+	_ = f.applyMonthListDelta(month, 0, delta, delta, 0)
+	if l, ok := f.MonthList[month]; ok {
+		l.EarningsAdded += earned
+	}
 	if f.Balance == nil {
 		f.Balance = &model.Balance{}
 	}
-	f.Balance.TotalBalance += amount
+	f.Balance.TotalBalance += delta
+}
+
+// fundDebitRefused reports whether the real fundDeltaItems condition would
+// cancel a debit of amount from month.
+func (f *FakeRepo) fundDebitRefused(month string, amount model.Money, checkBalance bool) bool {
+	s, ok := f.Months[month]
+	return !ok || s.AllowanceAdded < amount || (checkBalance && s.EndingBalance < amount)
+}
+
+func (f *FakeRepo) AtomicWithdrawFunds(ctx context.Context, month string, entry *model.FundEntry, earnings model.Money, checkBalance bool) error {
+	if l := f.scoped(ctx); l != f {
+		return l.AtomicWithdrawFunds(ctx, month, entry, earnings, checkBalance)
+	}
+	amount := -entry.Amount
+	if f.fundDebitRefused(month, amount, checkBalance) {
+		return repository.ErrExpenseStateMismatch
+	}
+	if s := f.Months[month]; s.EarningsAdded != earnings || s.AllowanceAdded < amount+earnings {
+		return repository.ErrExpenseStateMismatch
+	}
+	if _, ok := f.MonthList[month]; !ok {
+		return errMonthListMirrorMissing
+	}
+	key := ExpenseKey(month, entry.SK)
+	if _, exists := f.Funds[key]; exists {
+		return repository.ErrExpenseStateMismatch
+	}
+	entry.PK = repository.AccountPK(ctx, repository.MonthPrefix+month)
+	stored := *entry
+	f.Funds[key] = &stored
+	f.applyFundDelta(month, -amount, 0)
+	f.journal(ctx, model.AuditFundsWithdraw, month, entry.SK, nil, &model.AuditState{Amount: &amount})
 	return nil
 }
 
-func (f *FakeRepo) AtomicDeleteMonth(_ context.Context, month string, allowanceAdded float64) error {
+func (f *FakeRepo) AtomicDeleteMonth(ctx context.Context, month string, allowanceAdded model.Money, fundIDs []string) error {
+	if l := f.scoped(ctx); l != f {
+		return l.AtomicDeleteMonth(ctx, month, allowanceAdded, fundIDs)
+	}
 	if f.BeforeDeleteMonth != nil {
 		f.BeforeDeleteMonth()
 	}
@@ -627,15 +1085,102 @@ func (f *FakeRepo) AtomicDeleteMonth(_ context.Context, month string, allowanceA
 	}
 	// The real transaction also pins allowance_added, so the figure being
 	// debited from the global balance cannot have moved since the caller read it.
-	if !AlmostEqual(s.AllowanceAdded, allowanceAdded) {
+	if s.AllowanceAdded != allowanceAdded {
 		return repository.ErrMonthHasExpenses
 	}
 	delete(f.Months, month)
 	delete(f.MonthList, month)
+	for _, id := range fundIDs {
+		delete(f.Funds, ExpenseKey(month, id))
+	}
 	if f.Balance == nil {
 		f.Balance = &model.Balance{}
 	}
 	f.Balance.TotalBalance -= allowanceAdded
+	f.journal(ctx, model.AuditMonthDelete, month, "", &model.AuditState{AllowanceAdded: &allowanceAdded}, nil)
+	return nil
+}
+
+// =====================================================================
+// Funds credits
+// =====================================================================
+
+func (f *FakeRepo) GetFundEntries(ctx context.Context, month string) ([]model.FundEntry, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.GetFundEntries(ctx, month)
+	}
+	var out []model.FundEntry
+	for k, e := range f.Funds {
+		if strings.HasPrefix(k, month+"|") {
+			out = append(out, *e)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].SK < out[j].SK })
+	return out, nil
+}
+
+func (f *FakeRepo) GetFundEntry(ctx context.Context, month, id string) (*model.FundEntry, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.GetFundEntry(ctx, month, id)
+	}
+	e, ok := f.Funds[ExpenseKey(month, id)]
+	if !ok {
+		return nil, nil
+	}
+	cp := *e
+	return &cp, nil
+}
+
+func (f *FakeRepo) AtomicUpdateFundEntry(ctx context.Context, month string, old, updated *model.FundEntry, checkBalance bool) error {
+	if l := f.scoped(ctx); l != f {
+		return l.AtomicUpdateFundEntry(ctx, month, old, updated, checkBalance)
+	}
+	if f.BeforeFundEntryWrite != nil {
+		f.BeforeFundEntryWrite()
+	}
+	key := ExpenseKey(month, old.SK)
+	current, ok := f.Funds[key]
+	if !ok || current.Amount != old.Amount {
+		return repository.ErrExpenseStateMismatch
+	}
+	delta := updated.Amount - old.Amount
+	if delta < 0 && f.fundDebitRefused(month, -delta, checkBalance) {
+		return repository.ErrInsufficientBalance
+	}
+	if _, ok := f.MonthList[month]; delta != 0 && !ok {
+		return errMonthListMirrorMissing
+	}
+	if delta != 0 {
+		f.applyFundDelta(month, delta, earnedDelta(old, delta))
+	}
+	updated.PK = repository.AccountPK(ctx, repository.MonthPrefix+month)
+	stored := *updated
+	f.Funds[key] = &stored
+	f.journal(ctx, model.AuditFundsUpdate, month, updated.SK, repository.FundAuditState(month, old), repository.FundAuditState(month, updated))
+	return nil
+}
+
+func (f *FakeRepo) AtomicDeleteFundEntry(ctx context.Context, month string, old *model.FundEntry, checkBalance bool) error {
+	if l := f.scoped(ctx); l != f {
+		return l.AtomicDeleteFundEntry(ctx, month, old, checkBalance)
+	}
+	if f.BeforeFundEntryWrite != nil {
+		f.BeforeFundEntryWrite()
+	}
+	key := ExpenseKey(month, old.SK)
+	current, ok := f.Funds[key]
+	if !ok || current.Amount != old.Amount {
+		return repository.ErrExpenseStateMismatch
+	}
+	if f.fundDebitRefused(month, old.Amount, checkBalance) {
+		return repository.ErrInsufficientBalance
+	}
+	if _, ok := f.MonthList[month]; !ok {
+		return errMonthListMirrorMissing
+	}
+	f.applyFundDelta(month, -old.Amount, earnedDelta(old, -old.Amount))
+	delete(f.Funds, key)
+	f.journal(ctx, model.AuditFundsDelete, month, old.SK, repository.FundAuditState(month, old), nil)
 	return nil
 }
 
@@ -643,11 +1188,11 @@ func (f *FakeRepo) AtomicDeleteMonth(_ context.Context, month string, allowanceA
 // Sessions
 // =====================================================================
 
-func (f *FakeRepo) CreateSession(_ context.Context, token string, _ int) error {
+func (f *FakeRepo) CreateSession(_ context.Context, token, role string, _ int) error {
 	if f.CreateSessionErr != nil {
 		return f.CreateSessionErr
 	}
-	f.Sessions[token] = &model.Session{Token: token}
+	f.Sessions[token] = &model.Session{Token: token, Role: role}
 	return nil
 }
 
@@ -666,10 +1211,25 @@ func (f *FakeRepo) DeleteSession(_ context.Context, token string) error {
 }
 
 func (f *FakeRepo) DeleteAllSessions(_ context.Context) error {
+	if f.BeforeSessionRevoke != nil {
+		f.BeforeSessionRevoke()
+	}
 	f.Sessions = make(map[string]*model.Session)
 	return nil
 }
 
+func (f *FakeRepo) DeleteMemberSessions(_ context.Context) error {
+	if f.BeforeSessionRevoke != nil {
+		f.BeforeSessionRevoke()
+	}
+	for token, s := range f.Sessions {
+		if s.Role == model.RoleMember {
+			delete(f.Sessions, token)
+		}
+	}
+	return nil
+}
+
 // =====================================================================
 // Rate limiting (per-IP)
 // =====================================================================
@@ -778,3 +1338,884 @@ func (f *FakeRepo) DeleteAllWebAuthnCredentials(_ context.Context) error {
 	f.WACredentials = make(map[string]*model.WebAuthnCredential)
 	return nil
 }
+
+func (f *FakeRepo) AtomicEnrolWebAuthnCredential(ctx context.Context, cred *model.WebAuthnCredential) error {
+	if err := f.PutWebAuthnCredential(ctx, cred); err != nil {
+		return err
+	}
+	f.appendAudit(repository.NewInstanceAuditEntry(ctx, model.AuditWebAuthnEnrol, cred.CredentialID))
+	return nil
+}
+
+func (f *FakeRepo) AtomicDisableWebAuthn(ctx context.Context) error {
+	creds, _ := f.ListWebAuthnCredentials(ctx)
+	ids := make([]string, len(creds))
+	for i, c := range creds {
+		ids[i] = c.CredentialID
+	}
+	f.WACredentials = make(map[string]*model.WebAuthnCredential)
+	f.appendAudit(repository.NewInstanceAuditEntry(ctx, model.AuditWebAuthnDisable, strings.Join(ids, ",")))
+	return nil
+}
+
+// =====================================================================
+// Recurring expenses
+// =====================================================================
+
+func (f *FakeRepo) CreateRecurringExpense(ctx context.Context, rec *model.RecurringExpense) error {
+	if l := f.scoped(ctx); l != f {
+		return l.CreateRecurringExpense(ctx, rec)
+	}
+	if _, exists := f.Recurring[rec.ID]; exists {
+		return errors.New("recurring expense already exists")
+	}
+	rec.PK = repository.AccountPK(ctx, repository.PKRecurring)
+	rec.SK = repository.RecurringPrefix + rec.ID
+	r := *rec
+	f.Recurring[rec.ID] = &r
+	return nil
+}
+
+func (f *FakeRepo) GetRecurringExpense(ctx context.Context, id string) (*model.RecurringExpense, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.GetRecurringExpense(ctx, id)
+	}
+	r, ok := f.Recurring[id]
+	if !ok {
+		return nil, nil
+	}
+	out := *r
+	return &out, nil
+}
+
+func (f *FakeRepo) ListRecurringExpenses(ctx context.Context) ([]model.RecurringExpense, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.ListRecurringExpenses(ctx)
+	}
+	out := make([]model.RecurringExpense, 0, len(f.Recurring))
+	for _, r := range f.Recurring {
+		out = append(out, *r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].SK < out[j].SK })
+	return out, nil
+}
+
+// UpdateRecurringExpense copies the editable fields only, leaving the
+// stored booking cursor alone exactly as the real UpdateItem does.
+func (f *FakeRepo) UpdateRecurringExpense(ctx context.Context, rec *model.RecurringExpense) error {
+	if l := f.scoped(ctx); l != f {
+		return l.UpdateRecurringExpense(ctx, rec)
+	}
+	r, ok := f.Recurring[rec.ID]
+	if !ok {
+		return repository.ErrRecurringNotFound
+	}
+	rec.UpdatedAt = time.Now()
+	r.Amount = rec.Amount
+	r.Description = rec.Description
+	r.Category = rec.Category
+	r.DayOfMonth = rec.DayOfMonth
+	r.EndMonth = rec.EndMonth
+	r.UpdatedAt = rec.UpdatedAt
+	return nil
+}
+
+func (f *FakeRepo) DeleteRecurringExpense(ctx context.Context, id string) (*model.RecurringExpense, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.DeleteRecurringExpense(ctx, id)
+	}
+	r, ok := f.Recurring[id]
+	if !ok {
+		return nil, nil
+	}
+	delete(f.Recurring, id)
+	return r, nil
+}
+
+func (f *FakeRepo) MarkRecurringBooked(ctx context.Context, id, month string) error {
+	if l := f.scoped(ctx); l != f {
+		return l.MarkRecurringBooked(ctx, id, month)
+	}
+	if r, ok := f.Recurring[id]; ok && r.LastBookedMonth < month {
+		r.LastBookedMonth = month
+	}
+	return nil
+}
+
+// =====================================================================
+// Savings goals
+// =====================================================================
+
+func (f *FakeRepo) CreateGoal(ctx context.Context, goal *model.Goal) error {
+	if l := f.scoped(ctx); l != f {
+		return l.CreateGoal(ctx, goal)
+	}
+	if _, exists := f.Goals[goal.ID]; exists {
+		return errors.New("goal already exists")
+	}
+	goal.PK = repository.AccountPK(ctx, repository.PKGoals)
+	goal.SK = repository.GoalPrefix + goal.ID
+	g := *goal
+	f.Goals[goal.ID] = &g
+	return nil
+}
+
+func (f *FakeRepo) GetGoal(ctx context.Context, id string) (*model.Goal, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.GetGoal(ctx, id)
+	}
+	g, ok := f.Goals[id]
+	if !ok {
+		return nil, nil
+	}
+	out := *g
+	return &out, nil
+}
+
+func (f *FakeRepo) ListGoals(ctx context.Context) ([]model.Goal, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.ListGoals(ctx)
+	}
+	out := make([]model.Goal, 0, len(f.Goals))
+	for _, g := range f.Goals {
+		out = append(out, *g)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].SK < out[j].SK })
+	return out, nil
+}
+
+func (f *FakeRepo) UpdateGoal(ctx context.Context, goal *model.Goal) error {
+	if l := f.scoped(ctx); l != f {
+		return l.UpdateGoal(ctx, goal)
+	}
+	if _, ok := f.Goals[goal.ID]; !ok {
+		return repository.ErrGoalNotFound
+	}
+	goal.PK = repository.AccountPK(ctx, repository.PKGoals)
+	goal.SK = repository.GoalPrefix + goal.ID
+	g := *goal
+	f.Goals[goal.ID] = &g
+	return nil
+}
+
+func (f *FakeRepo) DeleteGoal(ctx context.Context, id string) (*model.Goal, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.DeleteGoal(ctx, id)
+	}
+	g, ok := f.Goals[id]
+	if !ok {
+		return nil, nil
+	}
+	delete(f.Goals, id)
+	return g, nil
+}
+
+// =====================================================================
+// Instalment plans
+// =====================================================================
+
+// copyPlan copies a plan with its own Instalments slice, so a stored plan
+// never aliases one the service is still editing.
+func copyPlan(p *model.InstalmentPlan) *model.InstalmentPlan {
+	out := *p
+	out.Instalments = append([]model.Instalment(nil), p.Instalments...)
+	return &out
+}
+
+func (f *FakeRepo) CreateInstalmentPlan(ctx context.Context, plan *model.InstalmentPlan) error {
+	if l := f.scoped(ctx); l != f {
+		return l.CreateInstalmentPlan(ctx, plan)
+	}
+	if _, exists := f.Instalments[plan.ID]; exists {
+		return errors.New("instalment plan already exists")
+	}
+	plan.PK = repository.AccountPK(ctx, repository.PKInstalment)
+	plan.SK = repository.InstalmentPrefix + plan.ID
+	f.Instalments[plan.ID] = copyPlan(plan)
+	return nil
+}
+
+func (f *FakeRepo) GetInstalmentPlan(ctx context.Context, id string) (*model.InstalmentPlan, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.GetInstalmentPlan(ctx, id)
+	}
+	p, ok := f.Instalments[id]
+	if !ok {
+		return nil, nil
+	}
+	return copyPlan(p), nil
+}
+
+func (f *FakeRepo) ListInstalmentPlans(ctx context.Context) ([]model.InstalmentPlan, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.ListInstalmentPlans(ctx)
+	}
+	out := make([]model.InstalmentPlan, 0, len(f.Instalments))
+	for _, p := range f.Instalments {
+		out = append(out, *copyPlan(p))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].SK < out[j].SK })
+	return out, nil
+}
+
+func (f *FakeRepo) AtomicUpdateInstalmentPlan(ctx context.Context, old, updated *model.InstalmentPlan, rewrites []repository.InstalmentRewrite, checkBalance bool) error {
+	if l := f.scoped(ctx); l != f {
+		return l.AtomicUpdateInstalmentPlan(ctx, old, updated, rewrites, checkBalance)
+	}
+	if err := f.checkInstalmentTransaction(old, rewrites, checkBalance); err != nil {
+		return err
+	}
+	f.applyInstalmentRewrites(ctx, rewrites)
+	updated.PK = repository.AccountPK(ctx, repository.PKInstalment)
+	updated.SK = repository.InstalmentPrefix + updated.ID
+	updated.Version = old.Version + 1
+	f.Instalments[updated.ID] = copyPlan(updated)
+	return nil
+}
+
+func (f *FakeRepo) AtomicDeleteInstalmentPlan(ctx context.Context, old *model.InstalmentPlan, rewrites []repository.InstalmentRewrite) error {
+	if l := f.scoped(ctx); l != f {
+		return l.AtomicDeleteInstalmentPlan(ctx, old, rewrites)
+	}
+	if err := f.checkInstalmentTransaction(old, rewrites, false); err != nil {
+		return err
+	}
+	f.applyInstalmentRewrites(ctx, rewrites)
+	delete(f.Instalments, old.ID)
+	return nil
+}
+
+func rewriteDelta(w repository.InstalmentRewrite) model.Money {
+	if w.New == nil {
+		return -w.Old.Amount
+	}
+	return w.New.Amount - w.Old.Amount
+}
+
+func rewriteCategories(w repository.InstalmentRewrite) map[string]model.Money {
+	changes := []categoryChange{{w.Old.Category, -w.Old.Amount}}
+	if w.New != nil {
+		changes = append(changes, categoryChange{w.New.Category, w.New.Amount})
+	}
+	return categoryDeltas(changes...)
+}
+
+// checkInstalmentTransaction is the condition half of the instalment
+// transaction — every condition is checked before anything is written, so a
+// single failure leaves the whole ledger untouched.
+func (f *FakeRepo) checkInstalmentTransaction(old *model.InstalmentPlan, rewrites []repository.InstalmentRewrite, checkBalance bool) error {
+	for _, w := range rewrites {
+		e, ok := f.Expenses[ExpenseKey(w.Month, w.Old.SK)]
+		if !ok || !expenseUnchanged(e, w.Old) {
+			return repository.ErrExpenseStateMismatch
+		}
+		delta := rewriteDelta(w)
+		cats := rewriteCategories(w)
+		if delta == 0 && len(cats) == 0 {
+			continue
+		}
+		s, ok := f.Months[w.Month]
+		if !ok {
+			return repository.ErrExpenseStateMismatch
+		}
+		if checkBalance && delta > 0 && s.EndingBalance < delta {
+			return repository.ErrInsufficientBalance
+		}
+		if _, ok := f.MonthList[w.Month]; !ok {
+			return errMonthListMirrorMissing
+		}
+		if err := f.checkCategoryTotals(w.Month, cats); err != nil {
+			return err
+		}
+	}
+	if p, ok := f.Instalments[old.ID]; !ok || p.Version != old.Version {
+		return repository.ErrExpenseStateMismatch
+	}
+	return nil
+}
+
+// applyInstalmentRewrites is the mutation half.
+func (f *FakeRepo) applyInstalmentRewrites(ctx context.Context, rewrites []repository.InstalmentRewrite) {
+	for _, w := range rewrites {
+		key := ExpenseKey(w.Month, w.Old.SK)
+		if w.New == nil {
+			delete(f.Expenses, key)
+			f.journal(ctx, model.AuditExpenseDelete, w.Month, w.Old.SK, repository.ExpenseAuditState(w.Month, w.Old), nil)
+		} else {
+			f.journal(ctx, model.AuditExpenseUpdate, w.Month, w.New.SK, repository.ExpenseAuditState(w.Month, w.Old), repository.ExpenseAuditState(w.Month, w.New))
+			e := f.Expenses[key]
+			e.Amount = w.New.Amount
+			e.Description = w.New.Description
+			e.Category = w.New.Category
+		}
+		delta := rewriteDelta(w)
+		cats := rewriteCategories(w)
+		if delta == 0 && len(cats) == 0 {
+			continue
+		}
+		s := f.Months[w.Month]
+		s.TotalExpenses += delta
+		s.EndingBalance -= delta
+		_ = f.applyMonthListDelta(w.Month, delta, -delta, 0, 0)
+		f.applyCategoryDeltas(w.Month, cats)
+		if f.Balance == nil {
+			f.Balance = &model.Balance{}
+		}
+		f.Balance.TotalBalance -= delta
+	}
+}
+
+// =====================================================================
+// Search
+// =====================================================================
+
+func searchKey(h repository.SearchHit) string {
+	return h.Token + "#" + h.Month + "#" + h.ExpenseID
+}
+
+// reindex replaces the index entries of old (in oldMonth) with those of
+// updated (in newMonth); either may be nil.
+func (f *FakeRepo) reindex(oldMonth string, old *model.Expense, newMonth string, updated *model.Expense) {
+	if old != nil {
+		for _, t := range repository.SearchTokens(old.Description) {
+			delete(f.Search, searchKey(repository.SearchHit{Token: t, Month: oldMonth, ExpenseID: old.SK}))
+		}
+	}
+	if updated != nil {
+		for _, t := range repository.SearchTokens(updated.Description) {
+			h := repository.SearchHit{Token: t, Month: newMonth, ExpenseID: updated.SK}
+			f.Search[searchKey(h)] = h
+		}
+	}
+}
+
+// SearchExpenses walks Search in descending key order like the real Query,
+// returning the last hit's key as the cursor when more entries follow.
+func (f *FakeRepo) SearchExpenses(ctx context.Context, prefix string, limit int32, cursor map[string]types.AttributeValue) ([]repository.SearchHit, map[string]types.AttributeValue, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.SearchExpenses(ctx, prefix, limit, cursor)
+	}
+	after := ""
+	if sk, ok := cursor["SK"].(*types.AttributeValueMemberS); ok {
+		after = sk.Value
+	}
+	var keys []string
+	for k := range f.Search {
+		if strings.HasPrefix(k, prefix) && (after == "" || k < after) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+	var lastKey map[string]types.AttributeValue
+	if limit > 0 && len(keys) > int(limit) {
+		keys = keys[:limit]
+		lastKey = f.Search[keys[len(keys)-1]].Key(ctx)
+	}
+	hits := make([]repository.SearchHit, len(keys))
+	for i, k := range keys {
+		hits[i] = f.Search[k]
+	}
+	return hits, lastKey, nil
+}
+
+func (f *FakeRepo) BatchGetExpenses(ctx context.Context, refs []repository.ExpenseRef) ([]model.Expense, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.BatchGetExpenses(ctx, refs)
+	}
+	var out []model.Expense
+	for _, ref := range refs {
+		if e, ok := f.Expenses[ExpenseKey(ref.Month, ref.ExpenseID)]; ok {
+			out = append(out, *e)
+		}
+	}
+	return out, nil
+}
+
+func (f *FakeRepo) ReindexExpense(ctx context.Context, oldMonth string, old *model.Expense, newMonth string, updated *model.Expense) error {
+	if l := f.scoped(ctx); l != f {
+		return l.ReindexExpense(ctx, oldMonth, old, newMonth, updated)
+	}
+	f.reindex(oldMonth, old, newMonth, updated)
+	return nil
+}
+
+// EnsureSearchIndex back-fills Search from Expenses the first time it runs,
+// like the real one-time Scan.
+func (f *FakeRepo) EnsureSearchIndex(ctx context.Context) error {
+	if l := f.scoped(ctx); l != f {
+		return l.EnsureSearchIndex(ctx)
+	}
+	if f.SearchIndexed {
+		return nil
+	}
+	for k, e := range f.Expenses {
+		f.reindex("", nil, k[:strings.Index(k, "|")], e)
+	}
+	f.SearchIndexed = true
+	return nil
+}
+
+// =====================================================================
+// Trash
+// =====================================================================
+
+func (f *FakeRepo) GetTrashedExpense(ctx context.Context, id string) (*model.TrashedExpense, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.GetTrashedExpense(ctx, id)
+	}
+	t, ok := f.Trash[id]
+	if !ok {
+		return nil, nil
+	}
+	cp := *t
+	return &cp, nil
+}
+
+func (f *FakeRepo) ListTrash(ctx context.Context) ([]model.TrashedExpense, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.ListTrash(ctx)
+	}
+	var out []model.TrashedExpense
+	for _, t := range f.Trash {
+		out = append(out, *t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (f *FakeRepo) DeleteTrashedExpense(ctx context.Context, id string) error {
+	if l := f.scoped(ctx); l != f {
+		return l.DeleteTrashedExpense(ctx, id)
+	}
+	delete(f.Trash, id)
+	return nil
+}
+
+func (f *FakeRepo) AtomicRestoreTrashedExpense(ctx context.Context, month string, expense *model.Expense, checkBalance bool) error {
+	if l := f.scoped(ctx); l != f {
+		return l.AtomicRestoreTrashedExpense(ctx, month, expense, checkBalance)
+	}
+	if f.BeforeTrashRestore != nil {
+		f.BeforeTrashRestore()
+	}
+	if _, ok := f.Trash[expense.SK]; !ok {
+		return repository.ErrTrashedExpenseNotFound
+	}
+	if err := f.AtomicAddExpense(ctx, month, expense, checkBalance); err != nil {
+		return err
+	}
+	delete(f.Trash, expense.SK)
+	return nil
+}
+
+// =====================================================================
+// Pending
+// =====================================================================
+
+func (f *FakeRepo) PutPendingExpense(ctx context.Context, p *model.PendingExpense) error {
+	if l := f.scoped(ctx); l != f {
+		return l.PutPendingExpense(ctx, p)
+	}
+	p.PK = repository.AccountPK(ctx, repository.PKPending)
+	p.SK = repository.PendingPrefix + p.ID
+	cp := *p
+	f.Pending[p.ID] = &cp
+	return nil
+}
+
+func (f *FakeRepo) GetPendingExpense(ctx context.Context, id string) (*model.PendingExpense, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.GetPendingExpense(ctx, id)
+	}
+	p, ok := f.Pending[id]
+	if !ok {
+		return nil, nil
+	}
+	cp := *p
+	return &cp, nil
+}
+
+func (f *FakeRepo) ListPendingExpenses(ctx context.Context) ([]model.PendingExpense, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.ListPendingExpenses(ctx)
+	}
+	var out []model.PendingExpense
+	for _, p := range f.Pending {
+		out = append(out, *p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (f *FakeRepo) DeletePendingExpense(ctx context.Context, id string) error {
+	if l := f.scoped(ctx); l != f {
+		return l.DeletePendingExpense(ctx, id)
+	}
+	delete(f.Pending, id)
+	return nil
+}
+
+func (f *FakeRepo) AtomicApprovePendingExpense(ctx context.Context, month string, expense *model.Expense, checkBalance bool) error {
+	if l := f.scoped(ctx); l != f {
+		return l.AtomicApprovePendingExpense(ctx, month, expense, checkBalance)
+	}
+	if f.BeforePendingApproval != nil {
+		f.BeforePendingApproval()
+	}
+	if _, ok := f.Pending[expense.SK]; !ok {
+		return repository.ErrPendingExpenseNotFound
+	}
+	if err := f.AtomicAddExpense(ctx, month, expense, checkBalance); err != nil {
+		return err
+	}
+	delete(f.Pending, expense.SK)
+	return nil
+}
+
+func (f *FakeRepo) AtomicRejectPendingExpense(ctx context.Context, p *model.PendingExpense, reason string) error {
+	if l := f.scoped(ctx); l != f {
+		return l.AtomicRejectPendingExpense(ctx, p, reason)
+	}
+	if _, ok := f.Pending[p.ID]; !ok {
+		return repository.ErrPendingExpenseNotFound
+	}
+	delete(f.Pending, p.ID)
+	entry := repository.NewAuditEntry(ctx, model.AuditExpenseReject, p.Month, p.ID, repository.PendingAuditState(p), nil)
+	entry.Reason = reason
+	f.appendAudit(entry)
+	return nil
+}
+
+// =====================================================================
+// Chores
+// =====================================================================
+
+func (f *FakeRepo) CreateChore(ctx context.Context, chore *model.Chore) error {
+	if l := f.scoped(ctx); l != f {
+		return l.CreateChore(ctx, chore)
+	}
+	if _, exists := f.Chores[chore.ID]; exists {
+		return errors.New("chore already exists")
+	}
+	chore.PK = repository.AccountPK(ctx, repository.PKChores)
+	chore.SK = repository.ChorePrefix + chore.ID
+	c := *chore
+	f.Chores[chore.ID] = &c
+	return nil
+}
+
+func (f *FakeRepo) GetChore(ctx context.Context, id string) (*model.Chore, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.GetChore(ctx, id)
+	}
+	c, ok := f.Chores[id]
+	if !ok {
+		return nil, nil
+	}
+	cp := *c
+	return &cp, nil
+}
+
+func (f *FakeRepo) ListChores(ctx context.Context) ([]model.Chore, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.ListChores(ctx)
+	}
+	var out []model.Chore
+	for _, c := range f.Chores {
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (f *FakeRepo) UpdateChore(ctx context.Context, chore *model.Chore) error {
+	if l := f.scoped(ctx); l != f {
+		return l.UpdateChore(ctx, chore)
+	}
+	if _, ok := f.Chores[chore.ID]; !ok {
+		return repository.ErrChoreNotFound
+	}
+	chore.PK = repository.AccountPK(ctx, repository.PKChores)
+	chore.SK = repository.ChorePrefix + chore.ID
+	c := *chore
+	f.Chores[chore.ID] = &c
+	return nil
+}
+
+func (f *FakeRepo) DeleteChore(ctx context.Context, id string) (*model.Chore, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.DeleteChore(ctx, id)
+	}
+	c, ok := f.Chores[id]
+	if !ok {
+		return nil, nil
+	}
+	delete(f.Chores, id)
+	return c, nil
+}
+
+func (f *FakeRepo) PutChoreCompletion(ctx context.Context, c *model.ChoreCompletion) error {
+	if l := f.scoped(ctx); l != f {
+		return l.PutChoreCompletion(ctx, c)
+	}
+	c.PK = repository.AccountPK(ctx, repository.PKChoreDone)
+	c.SK = repository.ChoreDonePrefix + c.ID
+	cp := *c
+	f.ChoreCompletions[c.ID] = &cp
+	return nil
+}
+
+func (f *FakeRepo) GetChoreCompletion(ctx context.Context, id string) (*model.ChoreCompletion, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.GetChoreCompletion(ctx, id)
+	}
+	c, ok := f.ChoreCompletions[id]
+	if !ok {
+		return nil, nil
+	}
+	cp := *c
+	return &cp, nil
+}
+
+func (f *FakeRepo) ListChoreCompletions(ctx context.Context) ([]model.ChoreCompletion, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.ListChoreCompletions(ctx)
+	}
+	var out []model.ChoreCompletion
+	for _, c := range f.ChoreCompletions {
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (f *FakeRepo) DeleteChoreCompletion(ctx context.Context, id string) (*model.ChoreCompletion, error) {
+	if l := f.scoped(ctx); l != f {
+		return l.DeleteChoreCompletion(ctx, id)
+	}
+	c, ok := f.ChoreCompletions[id]
+	if !ok {
+		return nil, nil
+	}
+	delete(f.ChoreCompletions, id)
+	return c, nil
+}
+
+func (f *FakeRepo) AtomicApproveChoreCompletion(ctx context.Context, c *model.ChoreCompletion, entry *model.FundEntry) error {
+	if l := f.scoped(ctx); l != f {
+		return l.AtomicApproveChoreCompletion(ctx, c, entry)
+	}
+	if _, ok := f.ChoreCompletions[c.ID]; !ok {
+		return repository.ErrChoreCompletionNotFound
+	}
+	if _, ok := f.Months[c.Month]; !ok {
+		return repository.ErrExpenseStateMismatch
+	}
+	if _, ok := f.MonthList[c.Month]; !ok {
+		return errMonthListMirrorMissing
+	}
+	key := ExpenseKey(c.Month, entry.SK)
+	if _, exists := f.Funds[key]; exists {
+		return repository.ErrExpenseStateMismatch
+	}
+	delete(f.ChoreCompletions, c.ID)
+	entry.PK = repository.AccountPK(ctx, repository.MonthPrefix+c.Month)
+	stored := *entry
+	f.Funds[key] = &stored
+	f.applyFundDelta(c.Month, entry.Amount, earnedDelta(entry, entry.Amount))
+	f.journal(ctx, model.AuditFundsAdd, c.Month, entry.SK, nil, repository.FundAuditState(c.Month, entry))
+	return nil
+}
+
+// earnedDelta is the part of a change of delta to funds credit e that
+// moves earnings_added, as the real entryDeltaItems decides it.
+func earnedDelta(e *model.FundEntry, delta model.Money) model.Money {
+	if e.Kind == model.FundKindEarning {
+		return delta
+	}
+	return 0
+}
+
+// =====================================================================
+// Audit
+// =====================================================================
+
+// journal appends the entry a successful atomic method writes alongside
+// its change.
+func (f *FakeRepo) journal(ctx context.Context, action, month, target string, before, after *model.AuditState) {
+	f.appendAudit(repository.NewAuditEntry(ctx, action, month, target, before, after))
+}
+
+func (f *FakeRepo) appendAudit(entry *model.AuditEntry) {
+	if f.root != nil {
+		f.root.appendAudit(entry)
+		return
+	}
+	f.Audit = append(f.Audit, *entry)
+}
+
+// AuditActions lists the journal's actions in the order they were written.
+func (f *FakeRepo) AuditActions() []string {
+	out := make([]string, len(f.Audit))
+	for i, e := range f.Audit {
+		out[i] = e.Action
+	}
+	return out
+}
+
+func (f *FakeRepo) ListAudit(_ context.Context, limit int32, cursor map[string]types.AttributeValue) ([]model.AuditEntry, map[string]types.AttributeValue, error) {
+	all := append([]model.AuditEntry(nil), f.Audit...)
+	sort.SliceStable(all, func(i, j int) bool { return all[i].SK > all[j].SK })
+
+	start := 0
+	if cursor != nil {
+		if sk, ok := cursor["SK"].(*types.AttributeValueMemberS); ok {
+			for start < len(all) && all[start].SK >= sk.Value {
+				start++
+			}
+		}
+	}
+	end := len(all)
+	if limit > 0 && start+int(limit) < end {
+		end = start + int(limit)
+	}
+	page := all[start:end]
+
+	var lastKey map[string]types.AttributeValue
+	if end < len(all) && len(page) > 0 {
+		lastKey = map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: repository.PKAudit},
+			"SK": &types.AttributeValueMemberS{Value: page[len(page)-1].SK},
+		}
+	}
+	return page, lastKey, nil
+}
+
+func (f *FakeRepo) AtomicChangePIN(ctx context.Context, config *model.Config) error {
+	if err := f.UpdateConfig(ctx, config, repository.ConfigPinHash); err != nil {
+		return err
+	}
+	f.appendAudit(repository.NewInstanceAuditEntry(ctx, model.AuditPINChange, ""))
+	return nil
+}
+
+func (f *FakeRepo) AtomicSaveMemberPIN(ctx context.Context, config *model.Config) error {
+	if err := f.UpdateConfig(ctx, config, repository.ConfigMemberPinHash); err != nil {
+		return err
+	}
+	action := model.AuditMemberPINSet
+	if config.MemberPinHash == "" {
+		action = model.AuditMemberPINRemove
+	}
+	f.appendAudit(repository.NewInstanceAuditEntry(ctx, action, ""))
+	return nil
+}
+
+// =====================================================================
+// Ledger repair
+// =====================================================================
+
+func (f *FakeRepo) RepairMonthSummary(ctx context.Context, stored, fixed *model.MonthSummary) error {
+	if l := f.scoped(ctx); l != f {
+		return l.RepairMonthSummary(ctx, stored, fixed)
+	}
+	if f.BeforeLedgerRepair != nil {
+		f.BeforeLedgerRepair()
+	}
+	s, ok := f.Months[fixed.Month]
+	if !ok || s.TotalExpenses != stored.TotalExpenses || s.StartingBalance != stored.StartingBalance ||
+		s.EndingBalance != stored.EndingBalance || s.AllowanceAdded != stored.AllowanceAdded ||
+		s.EarningsAdded != stored.EarningsAdded {
+		return repository.ErrLedgerRowChanged
+	}
+	s.TotalExpenses = fixed.TotalExpenses
+	s.StartingBalance = fixed.StartingBalance
+	s.EndingBalance = fixed.EndingBalance
+	s.EarningsAdded = fixed.EarningsAdded
+	f.putMonthListMirror(fixed.Month)
+	f.journal(ctx, model.AuditLedgerRepair, fixed.Month, "", repository.MonthAuditState(stored), repository.MonthAuditState(fixed))
+	return nil
+}
+
+func (f *FakeRepo) RepairBalance(ctx context.Context, stored, expected model.Money) error {
+	if l := f.scoped(ctx); l != f {
+		return l.RepairBalance(ctx, stored, expected)
+	}
+	if f.Balance == nil {
+		f.Balance = &model.Balance{}
+	}
+	if f.Balance.TotalBalance != stored {
+		return repository.ErrLedgerRowChanged
+	}
+	f.Balance.TotalBalance = expected
+	f.journal(ctx, model.AuditLedgerRepair, "", repository.PKBalance,
+		&model.AuditState{TotalBalance: &stored}, &model.AuditState{TotalBalance: &expected})
+	return nil
+}
+
+func (f *FakeRepo) DeleteOrphanMonthListMirror(ctx context.Context, month string) error {
+	if l := f.scoped(ctx); l != f {
+		return l.DeleteOrphanMonthListMirror(ctx, month)
+	}
+	if _, ok := f.Months[month]; ok {
+		return repository.ErrLedgerRowChanged
+	}
+	delete(f.MonthList, month)
+	f.journal(ctx, model.AuditLedgerRepair, month, repository.PKMonthList, nil, nil)
+	return nil
+}
+
+// =====================================================================
+// Accounts
+// =====================================================================
+
+func (f *FakeRepo) GetAccount(_ context.Context, id string) (*model.Account, error) {
+	if a, ok := f.Accounts[id]; ok {
+		cp := *a
+		return &cp, nil
+	}
+	return nil, nil
+}
+
+func (f *FakeRepo) ListAccounts(_ context.Context) ([]model.Account, error) {
+	ids := make([]string, 0, len(f.Accounts))
+	for id := range f.Accounts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	out := make([]model.Account, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, *f.Accounts[id])
+	}
+	return out, nil
+}
+
+func (f *FakeRepo) CreateAccount(_ context.Context, account *model.Account) error {
+	if _, ok := f.Accounts[account.ID]; ok {
+		return fmt.Errorf("account %s already exists", account.ID)
+	}
+	cp := *account
+	f.Accounts[account.ID] = &cp
+	return nil
+}
+
+func (f *FakeRepo) UpdateAccount(_ context.Context, account *model.Account) error {
+	if _, ok := f.Accounts[account.ID]; !ok && account.ID != model.DefaultAccountID {
+		return repository.ErrAccountNotFound
+	}
+	cp := *account
+	f.Accounts[account.ID] = &cp
+	return nil
+}
+
+func (f *FakeRepo) DeleteAccount(_ context.Context, id string) error {
+	if _, ok := f.Accounts[id]; !ok {
+		return repository.ErrAccountNotFound
+	}
+	if l := f.Ledger(id); l.Balance.TotalBalance != 0 {
+		return repository.ErrAccountHasBalance
+	}
+	delete(f.Accounts, id)
+	delete(f.ledgers, id)
+	return nil
+}
@@ -30,6 +30,54 @@ calc() { LC_ALL=C awk "BEGIN { printf \"%.2f\", $* }"; }
 # num_gt A B — true when A > B (decimal-aware, locale-independent).
 num_gt() { LC_ALL=C awk "BEGIN { exit !($1 > $2) }"; }
 
+# ---- Money ------------------------------------------------------------------
+# The backend stores every amount as an integer Number of cents under a
+# *_cents attribute (ending_balance_cents, amount_cents, ...); see
+# backend/internal/repository/money.go. Rows written before that switch hold
+# float dollars under the bare name instead, until the backend's one-time
+# migration rewrites them. This script does its arithmetic in dollars with
+# calc, reads either form, and always writes cents.
+
+# cents AMOUNT — a dollar figure as integer cents, rounded half away from
+# zero like the backend's ParseMoney.
+cents() { LC_ALL=C awk "BEGIN { v = ($*) * 100; printf \"%d\", (v < 0 ? v - 0.5 : v + 0.5) }"; }
+
+# dollars CENTS — integer cents back to a dollar figure, printed like calc.
+dollars() { LC_ALL=C awk "BEGIN { printf \"%.2f\", ($1) / 100 }"; }
+
+# JQ_MONEY — jq definitions shared by every read of a money attribute.
+#   money(attr)    the attribute in integer cents: attr_cents when present,
+#                  else the legacy float-dollar attr converted, else 0.
+#   upgrade_money  an item with every legacy money attribute replaced by its
+#                  cents form (the cents one wins where both exist).
+JQ_MONEY='
+def money($a):
+    if .[$a + "_cents"] then (.[$a + "_cents"].N | tonumber)
+    else ((.[$a].N // "0") | tonumber) * 100 | round end;
+def upgrade_money:
+    reduce ("total_balance", "starting_balance", "allowance_added",
+            "total_expenses", "ending_balance", "amount") as $a (.;
+        if has($a) then
+            (if has($a + "_cents") then . else .[$a + "_cents"] = {N: (money($a) | tostring)} end)
+            | del(.[$a])
+        else . end)
+    | if has("category_totals") then
+          (if has("category_totals_cents") then .
+           else .category_totals_cents = {M: (.category_totals.M
+               | map_values({N: ((.N | tonumber) * 100 | round | tostring)}))} end)
+          | del(.category_totals)
+      else . end;
+'
+
+# item_money JSON ATTR — ATTR of one item, in dollars. JSON may be the item
+# itself or a get-item response; a missing item (or no output at all from a
+# failed read) reads as 0.
+item_money() {
+    local c
+    c=$(jq -r --arg a "$2" "$JQ_MONEY"' (.Item // .) | money($a)' <<<"$1" 2>/dev/null) || true
+    dollars "${c:-0}"
+}
+
 # now_iso — UTC timestamp for created_at/updated_at attributes.
 now_iso() { date -u +%Y-%m-%dT%H:%M:%SZ; }
 
@@ -49,6 +97,13 @@ gen_expense_id() {
     echo "EXP#${ts}#${rand}"
 }
 
+# gen_fund_id — the same shape under FUND#, for the itemized funds rows.
+gen_fund_id() {
+    local id
+    id=$(gen_expense_id)
+    echo "FUND#${id#EXP#}"
+}
+
 # ---- Input validation -------------------------------------------------------
 # Reject bad input before ANY write. The backend validates equivalently;
 # unvalidated input here previously let "2026-6" or "abc" reach DynamoDB and
@@ -310,6 +365,32 @@ get_summary() {
         --output json 2>/dev/null
 }
 
+# upgrade_money_rows MONTH — rewrite the month's canonical row, its MONTHLIST
+# mirror and the BALANCE row in the integer-cents form if any still holds
+# float dollars. The delta updates below do arithmetic on the *_cents
+# attributes, and arithmetic on a missing attribute cancels the whole
+# transaction. The backend does the same for the rows it writes and sweeps
+# the rest in its daily scheduled job, so this only matters before that job
+# has run since an upgrade, or after importing an older backup.
+upgrade_money_rows() {
+    local month="$1" key item upgraded
+    if [[ "$DRY_RUN" == "true" ]]; then
+        return 0
+    fi
+    for key in \
+        "{\"PK\": {\"S\": \"MONTH#$month\"}, \"SK\": {\"S\": \"SUMMARY\"}}" \
+        "{\"PK\": {\"S\": \"MONTHLIST\"}, \"SK\": {\"S\": \"$month\"}}" \
+        '{"PK": {"S": "BALANCE"}, "SK": {"S": "BALANCE"}}'; do
+        item=$(aws dynamodb get-item --table-name "$TABLE_NAME" --region "$REGION" \
+            --key "$key" --output json | jq -c '.Item // empty')
+        [[ -z "$item" ]] && continue
+        upgraded=$(jq -c "$JQ_MONEY"' upgrade_money' <<<"$item")
+        [[ "$upgraded" == "$item" ]] && continue
+        aws dynamodb put-item --table-name "$TABLE_NAME" --region "$REGION" \
+            --item "$upgraded" >/dev/null
+    done
+}
+
 # ensure_monthlist_mirror MONTH — backfill the MONTHLIST/<month> mirror row
 # if it is missing (legacy table written before the MONTHLIST scheme). Mirrors
 # the backend's EnsureMonthListMirror: read-mirror → if absent, read canonical
@@ -397,16 +478,16 @@ recompute_carry_chain() {
         # Skip everything up to and including from_month; its ending is the
         # seed for the first later month.
         if [[ "$m" < "$from_month" || "$m" == "$from_month" ]]; then
-            prev_ending=$(echo "$months_json" | jq -r --arg mm "$m" \
-                '.[] | select(.month.S==$mm) | .ending_balance.N // "0"')
+            prev_ending=$(item_money "$(echo "$months_json" | jq -c --arg mm "$m" \
+                '.[] | select(.month.S==$mm)')" ending_balance)
             continue
         fi
         [[ -z "$prev_ending" ]] && prev_ending="0"
         local allowance expenses new_start new_ending
-        allowance=$(echo "$months_json" | jq -r --arg mm "$m" \
-            '.[] | select(.month.S==$mm) | .allowance_added.N // "0"')
-        expenses=$(echo "$months_json" | jq -r --arg mm "$m" \
-            '.[] | select(.month.S==$mm) | .total_expenses.N // "0"')
+        local row
+        row=$(echo "$months_json" | jq -c --arg mm "$m" '.[] | select(.month.S==$mm)')
+        allowance=$(item_money "$row" allowance_added)
+        expenses=$(item_money "$row" total_expenses)
         # Carry-over off means every month starts from zero rather than from the
         # previous month's ending.
         if [[ "$carry_enabled" == "true" ]]; then
@@ -419,10 +500,10 @@ recompute_carry_chain() {
         local carry_ts
         carry_ts=$(now_iso)
         local carry_expr carry_vals
-        carry_expr="SET starting_balance = :s, ending_balance = :e, updated_at = :u"
+        carry_expr="SET starting_balance_cents = :s, ending_balance_cents = :e, updated_at = :u"
         carry_vals="{
-            \":s\": {\"N\": \"$new_start\"},
-            \":e\": {\"N\": \"$new_ending\"},
+            \":s\": {\"N\": \"$(cents "$new_start")\"},
+            \":e\": {\"N\": \"$(cents "$new_ending")\"},
             \":u\": {\"S\": \"$carry_ts\"}
         }"
         # Build a transact-write-items payload that updates both the canonical
@@ -487,7 +568,8 @@ scan_month_summaries() {
         --output json
 }
 
-# sum_month_expenses MONTH — total of the ACTUAL EXP# rows in a month.
+# sum_month_expenses MONTH — total of the ACTUAL EXP# rows in a month, in
+# dollars.
 #
 # This is the figure nothing else in this script derives. `recalc` and
 # `fixchain` both trust the summary's stored total_expenses; if that field has
@@ -501,9 +583,10 @@ sum_month_expenses() {
     aws dynamodb query --table-name "$TABLE_NAME" --region "$REGION" \
         --key-condition-expression "PK = :pk AND begins_with(SK, :sk)" \
         --expression-attribute-values "{\":pk\": {\"S\": \"MONTH#$month\"}, \":sk\": {\"S\": \"EXP#\"}}" \
-        --projection-expression "amount" \
+        --projection-expression "amount_cents, amount" \
         --output json \
-        | jq -r '[.Items[]? | (.amount.N // "0" | tonumber)] | add // 0'
+        | jq -r "$JQ_MONEY"' [.Items[]? | money("amount")] | add // 0' \
+        | { read -r c; dollars "$c"; }
 }
 
 # recompute_total_expenses — rewrite every month's total_expenses from the
@@ -545,8 +628,8 @@ recompute_total_expenses() {
         actual=$(calc "$(sum_month_expenses "$m")")
         echo "  Expenses: $m total_expenses -> \$$actual"
         ts=$(now_iso)
-        expr="SET total_expenses = :t, updated_at = :u"
-        vals="{\":t\": {\"N\": \"$actual\"}, \":u\": {\"S\": \"$ts\"}}"
+        expr="SET total_expenses_cents = :t, updated_at = :u"
+        vals="{\":t\": {\"N\": \"$(cents "$actual")\"}, \":u\": {\"S\": \"$ts\"}}"
 
         # The mirror must exist before a conditional update lands on it.
         ensure_monthlist_mirror "$m" || true
@@ -712,9 +795,9 @@ audit_ledger() {
         # warns about. Returning 0 here without looking would have audit exit
         # clean on the clearest possible drift.
         local empty_balance
-        empty_balance=$(aws dynamodb get-item --table-name "$TABLE_NAME" --region "$REGION" \
+        empty_balance=$(item_money "$(aws dynamodb get-item --table-name "$TABLE_NAME" --region "$REGION" \
             --key '{"PK": {"S": "BALANCE"}, "SK": {"S": "BALANCE"}}' \
-            --output json | jq -r '.Item.total_balance.N // "0"')
+            --output json)" total_balance)
         echo "  No month summaries found."
         printf '  %-24s %12s\n' "BALANCE (stored)"   "$empty_balance"
         printf '  %-24s %12s\n' "BALANCE (expected)" "0.00"
@@ -745,10 +828,10 @@ audit_ledger() {
         [[ -z "$m" ]] && continue
         local row allowance stored_exp stored_start stored_end actual_exp
         row=$(echo "$items" | jq -c --arg mm "$m" '.[] | select(.month.S==$mm)')
-        allowance=$(echo "$row"    | jq -r '.allowance_added.N  // "0"')
-        stored_exp=$(echo "$row"   | jq -r '.total_expenses.N   // "0"')
-        stored_start=$(echo "$row" | jq -r '.starting_balance.N // "0"')
-        stored_end=$(echo "$row"   | jq -r '.ending_balance.N   // "0"')
+        allowance=$(item_money "$row"    allowance_added)
+        stored_exp=$(item_money "$row"   total_expenses)
+        stored_start=$(item_money "$row" starting_balance)
+        stored_end=$(item_money "$row"   ending_balance)
 
         actual_exp=$(calc "$(sum_month_expenses "$m")")
 
@@ -788,8 +871,8 @@ audit_ledger() {
             local mp
             for mp in starting_balance ending_balance allowance_added total_expenses; do
                 local cv mv
-                cv=$(echo "$row"    | jq -r --arg k "$mp" '.[$k].N // "0"')
-                mv=$(echo "$mirror" | jq -r --arg k "$mp" '.[$k].N // "0"')
+                cv=$(item_money "$row"    "$mp")
+                mv=$(item_money "$mirror" "$mp")
                 if [[ "$(calc "$cv")" != "$(calc "$mv")" ]]; then
                     echo "     DRIFT mirror $mp: canonical \$$cv, MONTHLIST \$$mv"
                     problems=$((problems + 1))
@@ -805,9 +888,9 @@ audit_ledger() {
     # (4) the global balance row.
     echo ""
     local stored_balance
-    stored_balance=$(aws dynamodb get-item --table-name "$TABLE_NAME" --region "$REGION" \
+    stored_balance=$(item_money "$(aws dynamodb get-item --table-name "$TABLE_NAME" --region "$REGION" \
         --key '{"PK": {"S": "BALANCE"}, "SK": {"S": "BALANCE"}}' \
-        --output json | jq -r '.Item.total_balance.N // "0"')
+        --output json)" total_balance)
     printf '  %-24s %12s\n' "BALANCE (stored)"   "$stored_balance"
     printf '  %-24s %12s\n' "BALANCE (expected)" "$expected_balance"
     if [[ "$(calc "$stored_balance")" != "$expected_balance" ]]; then
@@ -892,15 +975,15 @@ recalc_balance() {
 
     # Equals sum(ending-starting) under the ledger invariant ending = starting
     # + allowance - expenses; allowance-expenses is used because it is robust
-    # to a broken carry chain. Rounded to cents (mirrors backend roundCents).
-    total=$(echo "$items" | jq -r '[.[] | ((.allowance_added.N // "0") | tonumber) - ((.total_expenses.N // "0") | tonumber)] | add // 0')
-    total=$(calc "$total")
+    # to a broken carry chain. Summed in integer cents, as the backend does.
+    total=$(echo "$items" | jq -r "$JQ_MONEY"' [.[] | money("allowance_added") - money("total_expenses")] | add // 0')
+    total=$(dollars "$total")
     echo "  Calculated total balance: $total"
 
     aws_write dynamodb put-item --table-name "$TABLE_NAME" --region "$REGION" --item "{
         \"PK\": {\"S\": \"BALANCE\"},
         \"SK\": {\"S\": \"BALANCE\"},
-        \"total_balance\": {\"N\": \"$total\"},
+        \"total_balance_cents\": {\"N\": \"$(cents "$total")\"},
         \"updated_at\": {\"S\": \"$(now_iso)\"}
     }" >/dev/null
 
@@ -925,7 +1008,7 @@ add_month() {
     local existing exp_total exp_count
     existing=$(get_summary "$month") || true
     if [ -n "$existing" ] && [ "$(echo "$existing" | jq -r '.Item // empty')" != "" ]; then
-        exp_total=$(echo "$existing" | jq -r '.Item.total_expenses.N // "0"')
+        exp_total=$(item_money "$existing" total_expenses)
         # Count actual expense rows (not just the summary's total field).
         exp_count=$(aws dynamodb query --table-name "$TABLE_NAME" --region "$REGION" \
             --key-condition-expression "PK = :pk AND begins_with(SK, :sk)" \
@@ -945,10 +1028,8 @@ add_month() {
         local prev pm prev_data
         pm=$(prev_month "$month")
         prev_data=$(get_summary "$pm") || true
-        prev=$(echo "$prev_data" | jq -r '.Item.ending_balance.N // "0"')
-        if [ -n "$prev" ] && [ "$prev" != "null" ]; then
-            starting_balance=$(calc "$prev")
-        fi
+        prev=$(item_money "$prev_data" ending_balance)
+        starting_balance=$(calc "$prev")
     fi
 
     local ending_balance
@@ -970,39 +1051,41 @@ add_month() {
         --arg sk_sum   "SUMMARY" \
         --arg sk_m     "$month" \
         --arg mon      "$month" \
-        --arg start    "$starting_balance" \
-        --arg allow    "$allowance" \
-        --arg exp      "$expenses" \
-        --arg end      "$ending_balance" \
+        --arg start    "$(cents "$starting_balance")" \
+        --arg allow    "$(cents "$allowance")" \
+        --arg exp      "$(cents "$expenses")" \
+        --arg end      "$(cents "$ending_balance")" \
         --arg ts       "$month_ts" \
         '{
             "TransactItems": [
                 {"Put": {
                     "TableName": $table,
                     "Item": {
-                        "PK":               {"S": $pk_canon},
-                        "SK":               {"S": $sk_sum},
-                        "month":            {"S": $mon},
-                        "starting_balance": {"N": $start},
-                        "allowance_added":  {"N": $allow},
-                        "total_expenses":   {"N": $exp},
-                        "ending_balance":   {"N": $end},
-                        "created_at":       {"S": $ts},
-                        "updated_at":       {"S": $ts}
+                        "PK":                     {"S": $pk_canon},
+                        "SK":                     {"S": $sk_sum},
+                        "month":                  {"S": $mon},
+                        "starting_balance_cents": {"N": $start},
+                        "allowance_added_cents":  {"N": $allow},
+                        "base_allowance_cents":   {"N": $allow},
+                        "total_expenses_cents":   {"N": $exp},
+                        "ending_balance_cents":   {"N": $end},
+                        "created_at":             {"S": $ts},
+                        "updated_at":             {"S": $ts}
                     }
                 }},
                 {"Put": {
                     "TableName": $table,
                     "Item": {
-                        "PK":               {"S": $pk_list},
-                        "SK":               {"S": $sk_m},
-                        "month":            {"S": $mon},
-                        "starting_balance": {"N": $start},
-                        "allowance_added":  {"N": $allow},
-                        "total_expenses":   {"N": $exp},
-                        "ending_balance":   {"N": $end},
-                        "created_at":       {"S": $ts},
-                        "updated_at":       {"S": $ts}
+                        "PK":                     {"S": $pk_list},
+                        "SK":                     {"S": $sk_m},
+                        "month":                  {"S": $mon},
+                        "starting_balance_cents": {"N": $start},
+                        "allowance_added_cents":  {"N": $allow},
+                        "base_allowance_cents":   {"N": $allow},
+                        "total_expenses_cents":   {"N": $exp},
+                        "ending_balance_cents":   {"N": $end},
+                        "created_at":             {"S": $ts},
+                        "updated_at":             {"S": $ts}
                     }
                 }}
             ]
@@ -1056,10 +1139,8 @@ add_expense() {
             local pm prev_data prev
             pm=$(prev_month "$month")
             prev_data=$(get_summary "$pm") || true
-            prev=$(echo "$prev_data" | jq -r '.Item.ending_balance.N // "0"')
-            if [ -n "$prev" ] && [ "$prev" != "null" ]; then
-                start=$(calc "$prev")
-            fi
+            prev=$(item_money "$prev_data" ending_balance)
+            start=$(calc "$prev")
         fi
         echo "  Month $month doesn't exist, creating it (starting_balance=\$$start, \$0 allowance)..."
         local new_month_ts
@@ -1074,36 +1155,36 @@ add_expense() {
             --arg sk_sum   "SUMMARY" \
             --arg sk_m     "$month" \
             --arg mon      "$month" \
-            --arg s        "$start" \
+            --arg s        "$(cents "$start")" \
             --arg ts       "$new_month_ts" \
             '{
                 "TransactItems": [
                     {"Put": {
                         "TableName": $table,
                         "Item": {
-                            "PK":               {"S": $pk_canon},
-                            "SK":               {"S": $sk_sum},
-                            "month":            {"S": $mon},
-                            "starting_balance": {"N": $s},
-                            "allowance_added":  {"N": "0"},
-                            "total_expenses":   {"N": "0"},
-                            "ending_balance":   {"N": $s},
-                            "created_at":       {"S": $ts},
-                            "updated_at":       {"S": $ts}
+                            "PK":                     {"S": $pk_canon},
+                            "SK":                     {"S": $sk_sum},
+                            "month":                  {"S": $mon},
+                            "starting_balance_cents": {"N": $s},
+                            "allowance_added_cents":  {"N": "0"},
+                            "total_expenses_cents":   {"N": "0"},
+                            "ending_balance_cents":   {"N": $s},
+                            "created_at":             {"S": $ts},
+                            "updated_at":             {"S": $ts}
                         }
                     }},
                     {"Put": {
                         "TableName": $table,
                         "Item": {
-                            "PK":               {"S": $pk_list},
-                            "SK":               {"S": $sk_m},
-                            "month":            {"S": $mon},
-                            "starting_balance": {"N": $s},
-                            "allowance_added":  {"N": "0"},
-                            "total_expenses":   {"N": "0"},
-                            "ending_balance":   {"N": $s},
-                            "created_at":       {"S": $ts},
-                            "updated_at":       {"S": $ts}
+                            "PK":                     {"S": $pk_list},
+                            "SK":                     {"S": $sk_m},
+                            "month":                  {"S": $mon},
+                            "starting_balance_cents": {"N": $s},
+                            "allowance_added_cents":  {"N": "0"},
+                            "total_expenses_cents":   {"N": "0"},
+                            "ending_balance_cents":   {"N": $s},
+                            "created_at":             {"S": $ts},
+                            "updated_at":             {"S": $ts}
                         }
                     }}
                 ]
@@ -1127,10 +1208,10 @@ add_expense() {
 
     # Determine the ConditionExpression for the month summary update.
     # When ALLOW_OVERSPENDING is false and --force is not set, mirror the
-    # backend's check: ending_balance >= :amt (mirrors dynamodb.go ~838-840).
+    # backend's check: ending_balance_cents >= :amt (AtomicAddExpense).
     local month_condition="attribute_exists(PK)"
     if [[ "$ALLOW_OVERSPENDING" == "false" && "$FORCE" != "true" ]]; then
-        month_condition="attribute_exists(PK) AND ending_balance >= :amt"
+        month_condition="attribute_exists(PK) AND ending_balance_cents >= :amt"
     fi
 
     # Ensure the MONTHLIST mirror exists (legacy-table guard) before the
@@ -1138,11 +1219,16 @@ add_expense() {
     if [[ "$DRY_RUN" != "true" ]]; then
         ensure_monthlist_mirror "$month" || true
     fi
+    upgrade_money_rows "$month"
 
     # Build a single transact-write-items: expense put + canonical summary
     # delta + MONTHLIST mirror delta + global balance delta.  The summary
     # update carries the overspend condition; the mirror update carries
-    # attribute_exists(PK).  Mirrors the backend's AtomicAddExpense.
+    # attribute_exists(PK).  Mirrors the backend's AtomicAddExpense, down to
+    # the SEARCH index entries: one per distinct word of two or more letters
+    # or digits, lower-cased (repository.SearchTokens). The lower-casing is
+    # bash's, so outside a UTF-8 locale a non-ASCII capital stays as it is and
+    # that one word is not found by search.
     local exp_tx
     exp_tx=$(jq -n \
         --arg table    "$TABLE_NAME" \
@@ -1152,26 +1238,31 @@ add_expense() {
         --arg sk_m     "$month" \
         --arg pk_exp   "MONTH#$month" \
         --arg sk_exp   "$expense_id" \
-        --arg amt      "$amount_n" \
+        --arg amt      "$(cents "$amount_n")" \
         --arg desc     "$description" \
+        --arg words    "${description,,}" \
         --arg ts       "$exp_ts" \
         --arg month_cond "$month_condition" \
-        '{
-            "TransactItems": [
+        '($words
+            | [scan("[\\p{L}\\p{N}]+") | select(length >= 2)]
+            | reduce .[] as $w ([]; if any(.[]; . == $w) then . else . + [$w] end)
+            | .[:32]) as $tokens
+        | {
+            "TransactItems": ([
                 {"Put": {
                     "TableName": $table,
                     "Item": {
-                        "PK":          {"S": $pk_exp},
-                        "SK":          {"S": $sk_exp},
-                        "amount":      {"N": $amt},
-                        "description": {"S": $desc},
-                        "created_at":  {"S": $ts}
+                        "PK":           {"S": $pk_exp},
+                        "SK":           {"S": $sk_exp},
+                        "amount_cents": {"N": $amt},
+                        "description":  {"S": $desc},
+                        "created_at":   {"S": $ts}
                     }
                 }},
                 {"Update": {
                     "TableName": $table,
                     "Key": {"PK": {"S": $pk_canon}, "SK": {"S": $sk_sum}},
-                    "UpdateExpression": "SET total_expenses = total_expenses + :amt, ending_balance = ending_balance - :amt, updated_at = :u",
+                    "UpdateExpression": "SET total_expenses_cents = total_expenses_cents + :amt, ending_balance_cents = ending_balance_cents - :amt, updated_at = :u",
                     "ConditionExpression": $month_cond,
                     "ExpressionAttributeValues": {
                         ":amt": {"N": $amt},
@@ -1181,7 +1272,7 @@ add_expense() {
                 {"Update": {
                     "TableName": $table,
                     "Key": {"PK": {"S": $pk_list}, "SK": {"S": $sk_m}},
-                    "UpdateExpression": "SET total_expenses = total_expenses + :amt, ending_balance = ending_balance - :amt, updated_at = :u",
+                    "UpdateExpression": "SET total_expenses_cents = total_expenses_cents + :amt, ending_balance_cents = ending_balance_cents - :amt, updated_at = :u",
                     "ConditionExpression": "attribute_exists(PK)",
                     "ExpressionAttributeValues": {
                         ":amt": {"N": $amt},
@@ -1191,14 +1282,23 @@ add_expense() {
                 {"Update": {
                     "TableName": $table,
                     "Key": {"PK": {"S": "BALANCE"}, "SK": {"S": "BALANCE"}},
-                    "UpdateExpression": "SET total_balance = if_not_exists(total_balance, :z) - :amt, updated_at = :u",
+                    "UpdateExpression": "SET total_balance_cents = if_not_exists(total_balance_cents, :z) - :amt, updated_at = :u",
                     "ExpressionAttributeValues": {
                         ":amt": {"N": $amt},
                         ":z":   {"N": "0"},
                         ":u":   {"S": $ts}
                     }
                 }}
-            ]
+            ] + [$tokens[] | {"Put": {
+                    "TableName": $table,
+                    "Item": {
+                        "PK":         {"S": "SEARCH"},
+                        "SK":         {"S": (. + "#" + $sk_m + "#" + $sk_exp)},
+                        "token":      {"S": .},
+                        "month":      {"S": $sk_m},
+                        "expense_id": {"S": $sk_exp}
+                    }
+                }}])
         }')
 
     if [[ "$DRY_RUN" == "true" ]]; then
@@ -1212,7 +1312,7 @@ add_expense() {
             # Check if it was a ConditionalCheckFailed on the summary item (index 1).
             if grep -q "ConditionalCheckFailed" "$EXP_TX_ERR" 2>/dev/null; then
                 local cur_bal
-                cur_bal=$(get_summary "$month" | jq -r '.Item.ending_balance.N // "0"')
+                cur_bal=$(item_money "$(get_summary "$month")" ending_balance)
                 echo "Error: insufficient funds (month $month balance \$$cur_bal is less than expense \$$amount_n); use --force to override." >&2
                 rm -f "$EXP_TX_ERR"
                 exit 1
@@ -1242,7 +1342,7 @@ set_balance() {
     aws_write dynamodb put-item --table-name "$TABLE_NAME" --region "$REGION" --item "{
         \"PK\": {\"S\": \"BALANCE\"},
         \"SK\": {\"S\": \"BALANCE\"},
-        \"total_balance\": {\"N\": \"$balance_n\"},
+        \"total_balance_cents\": {\"N\": \"$(cents "$balance_n")\"},
         \"updated_at\": {\"S\": \"$(now_iso)\"}
     }" >/dev/null
 }
@@ -1274,11 +1374,13 @@ add_funds() {
     if [[ "$DRY_RUN" != "true" ]]; then
         ensure_monthlist_mirror "$month" || true
     fi
+    upgrade_money_rows "$month"
 
     local funds_ts
     funds_ts=$(now_iso)
     # Delta update: allowance_added and ending_balance both += amount, mirrored
-    # to the MONTHLIST row in the same transaction.
+    # to the MONTHLIST row in the same transaction, with the FUND# row that
+    # itemizes the top-up so the ledger check can account for it.
     local funds_tx
     funds_tx=$(jq -n \
         --arg table    "$TABLE_NAME" \
@@ -1286,34 +1388,45 @@ add_funds() {
         --arg pk_list  "MONTHLIST" \
         --arg sk_sum   "SUMMARY" \
         --arg sk_m     "$month" \
-        --arg amt      "$amount_n" \
+        --arg amt      "$(cents "$amount_n")" \
+        --arg sk_fund  "$(gen_fund_id)" \
         --arg ts       "$funds_ts" \
         '{
             "TransactItems": [
+                {"Put": {
+                    "TableName": $table,
+                    "Item": {
+                        "PK":           {"S": $pk_canon},
+                        "SK":           {"S": $sk_fund},
+                        "amount_cents": {"N": $amt},
+                        "created_at":   {"S": $ts}
+                    },
+                    "ConditionExpression": "attribute_not_exists(PK)"
+                }},
                 {"Update": {
                     "TableName": $table,
                     "Key": {"PK": {"S": $pk_canon}, "SK": {"S": $sk_sum}},
-                    "UpdateExpression": "SET allowance_added = allowance_added + :amt, ending_balance = ending_balance + :amt, updated_at = :u",
+                    "UpdateExpression": "SET allowance_added_cents = allowance_added_cents + :amt, ending_balance_cents = ending_balance_cents + :amt, updated_at = :u",
                     "ConditionExpression": "attribute_exists(PK)",
                     "ExpressionAttributeValues": {":amt": {"N": $amt}, ":u": {"S": $ts}}
                 }},
                 {"Update": {
                     "TableName": $table,
                     "Key": {"PK": {"S": $pk_list}, "SK": {"S": $sk_m}},
-                    "UpdateExpression": "SET allowance_added = allowance_added + :amt, ending_balance = ending_balance + :amt, updated_at = :u",
+                    "UpdateExpression": "SET allowance_added_cents = allowance_added_cents + :amt, ending_balance_cents = ending_balance_cents + :amt, updated_at = :u",
                     "ConditionExpression": "attribute_exists(PK)",
                     "ExpressionAttributeValues": {":amt": {"N": $amt}, ":u": {"S": $ts}}
                 }},
                 {"Update": {
                     "TableName": $table,
                     "Key": {"PK": {"S": "BALANCE"}, "SK": {"S": "BALANCE"}},
-                    "UpdateExpression": "SET total_balance = if_not_exists(total_balance, :z) + :amt, updated_at = :u",
+                    "UpdateExpression": "SET total_balance_cents = if_not_exists(total_balance_cents, :z) + :amt, updated_at = :u",
                     "ExpressionAttributeValues": {":amt": {"N": $amt}, ":z": {"N": "0"}, ":u": {"S": $ts}}
                 }}
             ]
         }')
     if [[ "$DRY_RUN" == "true" ]]; then
-        echo "  [DRY-RUN] transact-write-items (add_funds canonical+MONTHLIST+BALANCE):" >&2
+        echo "  [DRY-RUN] transact-write-items (add_funds FUND#+canonical+MONTHLIST+BALANCE):" >&2
         echo "$funds_tx" | jq -c . >&2
     else
         aws dynamodb transact-write-items --region "$REGION" \
@@ -1345,11 +1458,13 @@ remove_funds() {
         exit 1
     fi
 
-    local current_allowance new_allowance
-    current_allowance=$(echo "$current" | jq -r '.Item.allowance_added.N // "0"')
-    new_allowance=$(calc "$current_allowance - $amount_n")
-    if num_gt 0 "$new_allowance"; then
-        echo "Error: cannot remove more than current allowance (\$$current_allowance)" >&2
+    # Chore earnings stay the child's, as in the backend's WithdrawFunds:
+    # only the allowance and top-ups above them can be taken back.
+    local removable left
+    removable=$(calc "$(item_money "$current" allowance_added) - $(item_money "$current" earnings_added)")
+    left=$(calc "$removable - $amount_n")
+    if num_gt 0 "$left"; then
+        echo "Error: cannot remove more than the allowance less earnings (\$$removable)" >&2
         exit 1
     fi
 
@@ -1357,11 +1472,13 @@ remove_funds() {
     if [[ "$DRY_RUN" != "true" ]]; then
         ensure_monthlist_mirror "$month" || true
     fi
+    upgrade_money_rows "$month"
 
     local rmfunds_ts
     rmfunds_ts=$(now_iso)
     # Delta update: allowance_added and ending_balance both -= amount, mirrored
-    # to the MONTHLIST row in the same transaction.
+    # to the MONTHLIST row in the same transaction, with a negative FUND# row
+    # of kind withdrawal — the same record the backend's WithdrawFunds keeps.
     local rmfunds_tx
     rmfunds_tx=$(jq -n \
         --arg table    "$TABLE_NAME" \
@@ -1369,34 +1486,46 @@ remove_funds() {
         --arg pk_list  "MONTHLIST" \
         --arg sk_sum   "SUMMARY" \
         --arg sk_m     "$month" \
-        --arg amt      "$amount_n" \
+        --arg amt      "$(cents "$amount_n")" \
+        --arg sk_fund  "$(gen_fund_id)" \
         --arg ts       "$rmfunds_ts" \
         '{
             "TransactItems": [
+                {"Put": {
+                    "TableName": $table,
+                    "Item": {
+                        "PK":           {"S": $pk_canon},
+                        "SK":           {"S": $sk_fund},
+                        "amount_cents": {"N": ("-" + $amt)},
+                        "kind":         {"S": "withdrawal"},
+                        "created_at":   {"S": $ts}
+                    },
+                    "ConditionExpression": "attribute_not_exists(PK)"
+                }},
                 {"Update": {
                     "TableName": $table,
                     "Key": {"PK": {"S": $pk_canon}, "SK": {"S": $sk_sum}},
-                    "UpdateExpression": "SET allowance_added = allowance_added - :amt, ending_balance = ending_balance - :amt, updated_at = :u",
+                    "UpdateExpression": "SET allowance_added_cents = allowance_added_cents - :amt, ending_balance_cents = ending_balance_cents - :amt, updated_at = :u",
                     "ConditionExpression": "attribute_exists(PK)",
                     "ExpressionAttributeValues": {":amt": {"N": $amt}, ":u": {"S": $ts}}
                 }},
                 {"Update": {
                     "TableName": $table,
                     "Key": {"PK": {"S": $pk_list}, "SK": {"S": $sk_m}},
-                    "UpdateExpression": "SET allowance_added = allowance_added - :amt, ending_balance = ending_balance - :amt, updated_at = :u",
+                    "UpdateExpression": "SET allowance_added_cents = allowance_added_cents - :amt, ending_balance_cents = ending_balance_cents - :amt, updated_at = :u",
                     "ConditionExpression": "attribute_exists(PK)",
                     "ExpressionAttributeValues": {":amt": {"N": $amt}, ":u": {"S": $ts}}
                 }},
                 {"Update": {
                     "TableName": $table,
                     "Key": {"PK": {"S": "BALANCE"}, "SK": {"S": "BALANCE"}},
-                    "UpdateExpression": "SET total_balance = if_not_exists(total_balance, :z) - :amt, updated_at = :u",
+                    "UpdateExpression": "SET total_balance_cents = if_not_exists(total_balance_cents, :z) - :amt, updated_at = :u",
                     "ExpressionAttributeValues": {":amt": {"N": $amt}, ":z": {"N": "0"}, ":u": {"S": $ts}}
                 }}
             ]
         }')
     if [[ "$DRY_RUN" == "true" ]]; then
-        echo "  [DRY-RUN] transact-write-items (remove_funds canonical+MONTHLIST+BALANCE):" >&2
+        echo "  [DRY-RUN] transact-write-items (remove_funds FUND#+canonical+MONTHLIST+BALANCE):" >&2
         echo "$rmfunds_tx" | jq -c . >&2
     else
         aws dynamodb transact-write-items --region "$REGION" \
@@ -116,6 +116,18 @@ fi
 calc() { LC_ALL=C awk "BEGIN { printf \"%.2f\", $* }"; }
 num_gt() { LC_ALL=C awk "BEGIN { exit !($1 > $2) }"; }
 
+# JQ_MONEY — money(attr) reads an amount in integer cents from attr_cents,
+# falling back to a legacy float-dollar attr on a row the backend has not
+# migrated yet (same as add-data.sh); dollars renders cents as "12.35".
+JQ_MONEY='
+def money($a):
+    if .[$a + "_cents"] then (.[$a + "_cents"].N | tonumber)
+    else ((.[$a].N // "0") | tonumber) * 100 | round end;
+def dollars:
+    (if . < 0 then "-" else "" end) as $sign | (if . < 0 then -. else . end) as $v
+    | $sign + (($v / 100 | floor) | tostring) + "." + (("0" + (($v % 100) | tostring)) | .[-2:]);
+'
+
 # run_add_data CMD ARGS... — delegate a data operation to add-data.sh with
 # the current instance/region. Returns add-data.sh's exit status.
 run_add_data() {
@@ -151,7 +163,7 @@ show_summary() {
     balance_data=$(aws dynamodb get-item --table-name "$TABLE_NAME" --region "$REGION" \
         --key '{"PK": {"S": "BALANCE"}, "SK": {"S": "BALANCE"}}' \
         --output json 2>/dev/null || echo "")
-    total_balance=$(echo "$balance_data" | jq -r '.Item.total_balance.N // "0"')
+    total_balance=$(echo "$balance_data" | jq -r "$JQ_MONEY"' .Item // {} | money("total_balance") | dollars')
 
     months_data=$(aws dynamodb scan --table-name "$TABLE_NAME" --region "$REGION" \
         --filter-expression "begins_with(PK, :pk) AND SK = :sk" \
@@ -173,8 +185,8 @@ show_summary() {
             "Month" "Starting" "Allowance" "Expenses" "Ending" "Saved"
         echo "  ───────────┼────────────┼────────────┼────────────┼────────────┼────────────"
 
-        echo "$months_data" | jq -r '.Items | sort_by(.month.S) | reverse | .[] |
-            "\(.month.S)|\(.starting_balance.N)|\(.allowance_added.N)|\(.total_expenses.N)|\(.ending_balance.N)"' 2>/dev/null | \
+        echo "$months_data" | jq -r "$JQ_MONEY"' .Items | sort_by(.month.S) | reverse | .[] |
+            "\(.month.S)|\(money("starting_balance") | dollars)|\(money("allowance_added") | dollars)|\(money("total_expenses") | dollars)|\(money("ending_balance") | dollars)"' 2>/dev/null | \
         while IFS='|' read -r month start allow exp ending; do
             local saved
             saved=$(calc "$ending - $start")
@@ -333,7 +345,7 @@ action_set_balance() {
     balance_item=$(aws dynamodb get-item --table-name "$TABLE_NAME" --region "$REGION" \
         --key '{"PK": {"S": "BALANCE"}, "SK": {"S": "BALANCE"}}' \
         --output json 2>/dev/null || echo "")
-    current_total=$(echo "$balance_item" | jq -r '.Item.total_balance.N // "0"')
+    current_total=$(echo "$balance_item" | jq -r "$JQ_MONEY"' .Item // {} | money("total_balance") | dollars')
 
     echo -e "Current total balance: ${GREEN}\$$current_total${NC}"
     echo ""
@@ -385,7 +397,7 @@ action_delete_month() {
     fi
 
     local ending_balance confirm
-    ending_balance=$(echo "$month_data" | jq -r '.Item.ending_balance.N // "0"')
+    ending_balance=$(echo "$month_data" | jq -r "$JQ_MONEY"' .Item // {} | money("ending_balance") | dollars')
     echo ""
     echo "Month $month has ending balance: \$$ending_balance"
     prompt "Type 'DELETE' to confirm" confirm ""
@@ -431,10 +443,10 @@ action_view_expenses() {
 
     if [ -n "$summary" ] && [ "$summary" != "{}" ]; then
         echo -e "${BOLD}Month Summary:${NC}"
-        echo "  Starting balance: \$$(echo "$summary" | jq -r '.Item.starting_balance.N // "0"')"
-        echo "  Allowance added:  \$$(echo "$summary" | jq -r '.Item.allowance_added.N // "0"')"
-        echo "  Total expenses:   \$$(echo "$summary" | jq -r '.Item.total_expenses.N // "0"')"
-        echo "  Ending balance:   \$$(echo "$summary" | jq -r '.Item.ending_balance.N // "0"')"
+        echo "  Starting balance: \$$(echo "$summary" | jq -r "$JQ_MONEY"' .Item // {} | money("starting_balance") | dollars')"
+        echo "  Allowance added:  \$$(echo "$summary" | jq -r "$JQ_MONEY"' .Item // {} | money("allowance_added") | dollars')"
+        echo "  Total expenses:   \$$(echo "$summary" | jq -r "$JQ_MONEY"' .Item // {} | money("total_expenses") | dollars')"
+        echo "  Ending balance:   \$$(echo "$summary" | jq -r "$JQ_MONEY"' .Item // {} | money("ending_balance") | dollars')"
         echo ""
     fi
 
@@ -446,8 +458,8 @@ action_view_expenses() {
     if [ "$count" = "0" ] || [ -z "$count" ]; then
         echo "  No expenses found for $month"
     else
-        echo "$expenses" | jq -r '.Items | sort_by(.created_at.S) | .[] |
-            "  \(.created_at.S | split("T")[0])  │  $\(.amount.N)  │  \(.description.S)"'
+        echo "$expenses" | jq -r "$JQ_MONEY"' .Items | sort_by(.created_at.S) | .[] |
+            "  \(.created_at.S | split("T")[0])  │  $\(money("amount") | dollars)  │  \(.description.S)"'
     fi
     echo ""
     pause
@@ -44,7 +44,7 @@ fi
 
 MAIN_STACK="passbook-${INSTANCE}-prod"
 TABLE_NAME="passbook-${INSTANCE}-prod"
-LOG_GROUP="/aws/lambda/passbook-api-${INSTANCE}-prod"
+LOG_GROUPS=("/aws/lambda/passbook-api-${INSTANCE}-prod" "/aws/lambda/passbook-scheduler-${INSTANCE}-prod")
 BACKUP_FILE=""
 
 # Resolve add-data.sh relative to THIS script's directory so the export
@@ -93,7 +93,7 @@ echo
 echo "This will delete for instance '${INSTANCE}':"
 echo "  - CloudFormation stack: ${MAIN_STACK}"
 echo "  - DynamoDB table: ${TABLE_NAME} (retained by CloudFormation)"
-echo "  - CloudWatch logs: ${LOG_GROUP}"
+echo "  - CloudWatch logs: ${LOG_GROUPS[*]}"
 echo
 echo -e "${RED}WARNING: This action is irreversible!${NC}"
 echo
@@ -170,14 +170,16 @@ else
 fi
 
 # Step 3: Delete CloudWatch logs
-echo -e "${YELLOW}[3/3] Deleting CloudWatch log group...${NC}"
-if aws logs describe-log-groups --log-group-name-prefix "$LOG_GROUP" --region "$REGION" \
-    --query "logGroups[?logGroupName=='$LOG_GROUP'].logGroupName" --output text 2>/dev/null | grep -q "$LOG_GROUP"; then
-    run aws logs delete-log-group --log-group-name "$LOG_GROUP" --region "$REGION"
-    [[ "$DRY_RUN" != "true" ]] && echo -e "${GREEN}Log group deleted.${NC}"
-else
-    echo "Log group not found, skipping."
-fi
+echo -e "${YELLOW}[3/3] Deleting CloudWatch log groups...${NC}"
+for LOG_GROUP in "${LOG_GROUPS[@]}"; do
+    if aws logs describe-log-groups --log-group-name-prefix "$LOG_GROUP" --region "$REGION" \
+        --query "logGroups[?logGroupName=='$LOG_GROUP'].logGroupName" --output text 2>/dev/null | grep -q "$LOG_GROUP"; then
+        run aws logs delete-log-group --log-group-name "$LOG_GROUP" --region "$REGION"
+        [[ "$DRY_RUN" != "true" ]] && echo -e "${GREEN}Log group ${LOG_GROUP} deleted.${NC}"
+    else
+        echo "Log group ${LOG_GROUP} not found, skipping."
+    fi
+done
 
 echo
 echo -e "${GREEN}╔════════════════════════════════════════════════════╗${NC}"
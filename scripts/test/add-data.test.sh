@@ -304,6 +304,55 @@ assert_money "$(month_field 2026-01 ending_balance)" "$local_first_end" "second
 assert_money "$(total_balance)" "$local_first_bal" "balance stable across repairs"
 end_test
 
+# =============================================================================
+# float-dollar rows — a table written before the backend stored integer cents
+# =============================================================================
+
+begin_test "audit reads float-dollar rows, dust and all"
+seed_float_month 2026-01 0 100 0 99.99999999999999
+seed_float_balance 99.99999999999999
+run_add_data audit
+assert_status 0 "float rows that round to the cent are consistent"
+end_test
+
+begin_test "recalc writes the balance in cents from float-dollar months"
+seed_float_month 2026-01 0 100 0 100
+seed_float_month 2026-02 100 50.5 0 150.5
+seed_float_balance 1
+run_add_data recalc
+assert_money "$(total_balance)" 150.50 "balance summed from the float rows"
+end_test
+
+begin_test "funds on a float-dollar month upgrades the rows it adds to"
+# The delta update adds to allowance_added_cents; on a row that only has
+# allowance_added that attribute does not exist and the transaction fails.
+seed_float_month 2026-01 0 100 0 100
+seed_float_balance 100
+run_add_data funds 2026-01 50
+assert_status 0 "funds succeeds"
+assert_money "$(month_field 2026-01 allowance_added)" 150 "canonical allowance"
+assert_money "$(month_field 2026-01 ending_balance)" 150 "canonical ending"
+assert_money "$(mirror_field 2026-01 ending_balance)" 150 "mirror ending"
+assert_money "$(total_balance)" 150 "balance"
+assert_eq "$(legacy_attrs)" "" "no float attributes left on the touched rows"
+end_test
+
+begin_test "an expense on a float-dollar month is booked in cents"
+export PASSBOOK_FAKE_OVERSPEND=true
+seed_float_month 2026-01 0 100 0 100
+seed_float_balance 100
+run_add_data expense 2026-01 12.35 "Lunch at the café, lunch"
+assert_status 0 "expense succeeds"
+assert_eq "$(jq -r 'map(select(.PK.S == "SEARCH") | .token.S) | sort | join(",")' "$PASSBOOK_FAKE_TABLE")" \
+    "at,café,lunch,the" "one search entry per distinct word, like the backend"
+assert_money "$(month_field 2026-01 total_expenses)" 12.35 "canonical expenses"
+assert_money "$(month_field 2026-01 ending_balance)" 87.65 "canonical ending"
+assert_money "$(total_balance)" 87.65 "balance"
+assert_eq "$(legacy_attrs)" "" "the expense row is written in cents too"
+run_add_data audit
+assert_status 0 "the ledger is consistent afterwards"
+end_test
+
 # =============================================================================
 # --dry-run must be a true preview
 # =============================================================================
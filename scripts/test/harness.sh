@@ -85,15 +85,19 @@ put_item() {
     mv "$WORK_DIR/tmp.json" "$PASSBOOK_FAKE_TABLE"
 }
 
+# to_cents DOLLARS — the integer-cents Number the backend stores.
+to_cents() { LC_ALL=C awk "BEGIN { v = ($1) * 100; printf \"%d\", (v < 0 ? v - 0.5 : v + 0.5) }"; }
+
 # seed_month MONTH START ALLOWANCE EXPENSES ENDING — canonical row AND its
 # MONTHLIST mirror, which is the state a table written by the current backend is
-# in.
+# in. Amounts are given in dollars and stored as *_cents.
 seed_month() {
     local m="$1" st="$2" al="$3" ex="$4" en="$5"
     local body
-    body=$(jq -n --arg m "$m" --arg st "$st" --arg al "$al" --arg ex "$ex" --arg en "$en" '{
-        month: {S: $m}, starting_balance: {N: $st}, allowance_added: {N: $al},
-        total_expenses: {N: $ex}, ending_balance: {N: $en},
+    body=$(jq -n --arg m "$m" --arg st "$(to_cents "$st")" --arg al "$(to_cents "$al")" \
+        --arg ex "$(to_cents "$ex")" --arg en "$(to_cents "$en")" '{
+        month: {S: $m}, starting_balance_cents: {N: $st}, allowance_added_cents: {N: $al},
+        total_expenses_cents: {N: $ex}, ending_balance_cents: {N: $en},
         updated_at: {S: "2026-01-01T00:00:00Z"}}')
     put_item "$(jq -n --argjson b "$body" --arg m "$m" \
         '$b + {PK: {S: ("MONTH#" + $m)}, SK: {S: "SUMMARY"}}')"
@@ -111,49 +115,95 @@ seed_legacy_month() {
     mv "$WORK_DIR/tmp.json" "$PASSBOOK_FAKE_TABLE"
 }
 
+# seed_float_month MONTH START ALLOWANCE EXPENSES ENDING — canonical row and
+# mirror holding float dollars under the bare attribute names: a table written
+# before the backend stored money as integer cents, not yet migrated.
+seed_float_month() {
+    local m="$1" st="$2" al="$3" ex="$4" en="$5"
+    local body
+    body=$(jq -n --arg m "$m" --arg st "$st" --arg al "$al" --arg ex "$ex" --arg en "$en" '{
+        month: {S: $m}, starting_balance: {N: $st}, allowance_added: {N: $al},
+        total_expenses: {N: $ex}, ending_balance: {N: $en},
+        updated_at: {S: "2026-01-01T00:00:00Z"}}')
+    put_item "$(jq -n --argjson b "$body" --arg m "$m" \
+        '$b + {PK: {S: ("MONTH#" + $m)}, SK: {S: "SUMMARY"}}')"
+    put_item "$(jq -n --argjson b "$body" --arg m "$m" \
+        '$b + {PK: {S: "MONTHLIST"}, SK: {S: $m}}')"
+}
+
 # seed_orphan_mirror MONTH — a MONTHLIST row whose canonical month is gone.
 # Reachable for real: rmmonth deletes the two rows in separate calls.
 seed_orphan_mirror() {
     local m="$1"
     put_item "$(jq -n --arg m "$m" '{
         PK: {S: "MONTHLIST"}, SK: {S: $m}, month: {S: $m},
-        starting_balance: {N: "0"}, allowance_added: {N: "0"},
-        total_expenses: {N: "0"}, ending_balance: {N: "0"}}')"
+        starting_balance_cents: {N: "0"}, allowance_added_cents: {N: "0"},
+        total_expenses_cents: {N: "0"}, ending_balance_cents: {N: "0"}}')"
 }
 
 # seed_expense MONTH AMOUNT [DESCRIPTION] [SUFFIX]
 seed_expense() {
     local m="$1" amt="$2" desc="${3:-item}" sfx="${4:-$RANDOM}"
-    put_item "$(jq -n --arg m "$m" --arg amt "$amt" --arg d "$desc" --arg s "$sfx" '{
+    put_item "$(jq -n --arg m "$m" --arg amt "$(to_cents "$amt")" --arg d "$desc" --arg s "$sfx" '{
         PK: {S: ("MONTH#" + $m)}, SK: {S: ("EXP#1700000000000#" + $s)},
-        amount: {N: $amt}, description: {S: $d},
+        amount_cents: {N: $amt}, description: {S: $d},
         created_at: {S: ($m + "-15T12:00:00Z")}}')"
 }
 
 seed_balance() {
+    put_item "$(jq -n --arg b "$(to_cents "$1")" '{
+        PK: {S: "BALANCE"}, SK: {S: "BALANCE"}, total_balance_cents: {N: $b}}')"
+}
+
+# seed_float_balance DOLLARS — the BALANCE row as written before cents.
+seed_float_balance() {
     put_item "$(jq -n --arg b "$1" '{
         PK: {S: "BALANCE"}, SK: {S: "BALANCE"}, total_balance: {N: $b}}')"
 }
 
 # ---- reading ----------------------------------------------------------------
 
-# month_field MONTH FIELD — a numeric attribute of the canonical month row.
+# Money is read from the *_cents attribute and reported in dollars, printed
+# to the cent as add-data.sh prints them. A row that lacks it reads as MISSING
+# — including one still holding only the legacy float attribute, which the
+# backend's write paths cannot do arithmetic on.
+cents_to_dollars() {
+    local c
+    read -r c
+    if [[ "$c" == "MISSING" ]]; then
+        echo MISSING
+    else
+        LC_ALL=C awk "BEGIN { printf \"%.2f\", ($c) / 100 }"
+    fi
+}
+
+# month_field MONTH FIELD — a money attribute of the canonical month row, in
+# dollars. FIELD is the bare name (total_expenses), read from FIELD_cents.
 month_field() {
-    jq -r --arg m "MONTH#$1" --arg f "$2" '
+    jq -r --arg m "MONTH#$1" --arg f "$2_cents" '
         map(select(.PK.S == $m and .SK.S == "SUMMARY")) | .[0][$f].N // "MISSING"' \
-        "$PASSBOOK_FAKE_TABLE"
+        "$PASSBOOK_FAKE_TABLE" | cents_to_dollars
 }
 
 # mirror_field MONTH FIELD — the same attribute on the MONTHLIST mirror.
 mirror_field() {
-    jq -r --arg m "$1" --arg f "$2" '
+    jq -r --arg m "$1" --arg f "$2_cents" '
         map(select(.PK.S == "MONTHLIST" and .SK.S == $m)) | .[0][$f].N // "MISSING"' \
-        "$PASSBOOK_FAKE_TABLE"
+        "$PASSBOOK_FAKE_TABLE" | cents_to_dollars
 }
 
 total_balance() {
-    jq -r 'map(select(.PK.S == "BALANCE")) | .[0].total_balance.N // "MISSING"' \
-        "$PASSBOOK_FAKE_TABLE"
+    jq -r 'map(select(.PK.S == "BALANCE")) | .[0].total_balance_cents.N // "MISSING"' \
+        "$PASSBOOK_FAKE_TABLE" | cents_to_dollars
+}
+
+# legacy_attrs — every float-dollar attribute still present anywhere in the
+# table, as PK/SK:attr lines. Empty once everything is in cents.
+legacy_attrs() {
+    jq -r '.[] | (.PK.S + "/" + .SK.S) as $k
+        | keys[] | select(IN("total_balance", "starting_balance", "allowance_added",
+                             "total_expenses", "ending_balance", "amount"))
+        | $k + ":" + .' "$PASSBOOK_FAKE_TABLE"
 }
 
 mirror_months() {